@@ -304,10 +304,23 @@ DBStatus MVCCFindSplitKey(DBIterator* iter, DBKey start, DBKey end, DBKey min_sp
 // TODO(peter): We could investigate using
 // https://github.com/petermattis/cppgo to generate C++ code that can
 // read the Go roachpb.Transaction structure.
+typedef struct {
+  int32_t start_seqnum;
+  int32_t end_seqnum;
+} DBIgnoredSeqNumRange;
+
+// DBIgnoredSeqNums contains the sorted and disjoint ranges of sequence
+// numbers whose writes have been rolled back by the transaction.
+typedef struct {
+  DBIgnoredSeqNumRange* ranges;
+  int len;
+} DBIgnoredSeqNums;
+
 typedef struct {
   DBSlice id;
   uint32_t epoch;
   DBTimestamp max_timestamp;
+  DBIgnoredSeqNums ignored_seqnums;
 } DBTxn;
 
 typedef struct {
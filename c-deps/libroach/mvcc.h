@@ -61,6 +61,7 @@ template <bool reverse> class mvccScanner {
         txn_id_(ToSlice(txn.id)),
         txn_epoch_(txn.epoch),
         txn_max_timestamp_(txn.max_timestamp),
+        txn_ignored_seqnums_(txn.ignored_seqnums),
         inconsistent_(inconsistent),
         tombstones_(tombstones),
         check_uncertainty_(timestamp < txn.max_timestamp),
@@ -263,6 +264,14 @@ template <bool reverse> class mvccScanner {
       return advanceKey();
     }
 
+    if (txn_epoch_ == meta_.txn().epoch() && seqNumIsIgnored(meta_.txn().sequence())) {
+      // 8a. We're reading our own txn's intent but its latest write
+      // has been rolled back to a savepoint. Read the latest value of
+      // the intent history that hasn't been rolled back instead, or
+      // the previous value if there is none.
+      return getFromIntentHistory(meta_timestamp);
+    }
+
     if (txn_epoch_ == meta_.txn().epoch()) {
       // 8. We're reading our own txn's intent. Note that we read at
       // the intent timestamp, not at our read timestamp as the intent
@@ -405,11 +414,45 @@ template <bool reverse> class mvccScanner {
     }
   }
 
-  bool addAndAdvance(const rocksdb::Slice& value) {
+  // seqNumIsIgnored returns whether the write of our txn with the
+  // given sequence number has been rolled back.
+  bool seqNumIsIgnored(int32_t sequence) const {
+    for (int i = 0; i < txn_ignored_seqnums_.len; i++) {
+      const DBIgnoredSeqNumRange& range = txn_ignored_seqnums_.ranges[i];
+      if (sequence < range.start_seqnum) {
+        // The ranges are sorted.
+        return false;
+      }
+      if (sequence <= range.end_seqnum) {
+        return true;
+      }
+    }
+    return false;
+  }
+
+  // getFromIntentHistory emits the latest value of the intent history
+  // of the current key whose write hasn't been rolled back, at the
+  // timestamp of the intent. If there is no such value, the previous
+  // version of the key is read instead.
+  bool getFromIntentHistory(DBTimestamp meta_timestamp) {
+    for (int i = meta_.intent_history_size() - 1; i >= 0; i--) {
+      const auto& intent = meta_.intent_history(i);
+      if (seqNumIsIgnored(intent.sequence())) {
+        continue;
+      }
+      intent_key_buf_ = EncodeKey(cur_key_, meta_timestamp.wall_time, meta_timestamp.logical);
+      return addAndAdvance(intent_key_buf_, intent.value());
+    }
+    return seekVersion(PrevTimestamp(meta_timestamp), false);
+  }
+
+  bool addAndAdvance(const rocksdb::Slice& value) { return addAndAdvance(cur_raw_key_, value); }
+
+  bool addAndAdvance(const rocksdb::Slice& key, const rocksdb::Slice& value) {
     // Don't include deleted versions (value.size() == 0), unless we've been
     // instructed to include tombstones in the results.
     if (value.size() > 0 || tombstones_) {
-      kvs_->Put(cur_raw_key_, value);
+      kvs_->Put(key, value);
       if (kvs_->Count() == max_keys_) {
         return false;
       }
@@ -594,6 +637,7 @@ template <bool reverse> class mvccScanner {
   const rocksdb::Slice txn_id_;
   const uint32_t txn_epoch_;
   const DBTimestamp txn_max_timestamp_;
+  const DBIgnoredSeqNums txn_ignored_seqnums_;
   const bool inconsistent_;
   const bool tombstones_;
   const bool check_uncertainty_;
@@ -602,6 +646,7 @@ template <bool reverse> class mvccScanner {
   std::unique_ptr<rocksdb::WriteBatch> intents_;
   std::string key_buf_;
   std::string saved_buf_;
+  std::string intent_key_buf_;
   bool peeked_;
   cockroach::storage::engine::enginepb::MVCCMetadata meta_;
   // cur_raw_key_ holds either iter_rep_->key() or the saved value of
@@ -172,12 +172,12 @@ func getRelevantDescChanges(
 			return nil, err
 		}
 		for _, i := range starting {
-			if table := i.GetTable(); table != nil {
+			if parentID, ok := descParentID(i); ok {
 				// We need to add to interestingIDs so that if we later see a delete for
 				// this ID we still know it is interesting to us, even though we will not
 				// have a parentID at that point (since the delete is a nil desc).
-				if _, ok := interestingParents[table.ParentID]; ok {
-					interestingIDs[table.ID] = struct{}{}
+				if _, ok := interestingParents[parentID]; ok {
+					interestingIDs[i.GetID()] = struct{}{}
 				}
 			}
 			if _, ok := interestingIDs[i.GetID()]; ok {
@@ -203,9 +203,9 @@ func getRelevantDescChanges(
 		if _, ok := interestingIDs[change.ID]; ok {
 			interestingChanges = append(interestingChanges, change)
 		} else if change.Desc != nil {
-			if table := change.Desc.GetTable(); table != nil {
-				if _, ok := interestingParents[table.ParentID]; ok {
-					interestingIDs[table.ID] = struct{}{}
+			if parentID, ok := descParentID(*change.Desc); ok {
+				if _, ok := interestingParents[parentID]; ok {
+					interestingIDs[change.ID] = struct{}{}
 					interestingChanges = append(interestingChanges, change)
 				}
 			}
@@ -219,6 +219,18 @@ func getRelevantDescChanges(
 	return interestingChanges, nil
 }
 
+// descParentID returns the ID of the parent database of a table or
+// user-defined schema descriptor, and false for the other descriptors.
+func descParentID(desc sqlbase.Descriptor) (sqlbase.ID, bool) {
+	if table := desc.GetTable(); table != nil {
+		return table.ParentID, true
+	}
+	if schema := desc.GetSchema(); schema != nil {
+		return schema.ParentID, true
+	}
+	return 0, false
+}
+
 // getAllDescChanges gets every sql descriptor change between start and end time
 // returning its ID, content and the change time (with deletions represented as
 // nil content).
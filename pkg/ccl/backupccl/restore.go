@@ -207,10 +207,13 @@ func allocateTableRewrites(
 	}
 
 	databasesByID := make(map[sqlbase.ID]*sqlbase.DatabaseDescriptor)
+	schemasByID := make(map[sqlbase.ID]*sqlbase.SchemaDescriptor)
 	tablesByID := make(map[sqlbase.ID]*sqlbase.TableDescriptor)
 	for _, desc := range sqlDescs {
 		if dbDesc := desc.GetDatabase(); dbDesc != nil {
 			databasesByID[dbDesc.ID] = dbDesc
+		} else if scDesc := desc.GetSchema(); scDesc != nil {
+			schemasByID[scDesc.ID] = scDesc
 		} else if tableDesc := desc.GetTable(); tableDesc != nil {
 			tablesByID[tableDesc.ID] = tableDesc
 		}
@@ -284,6 +287,15 @@ func allocateTableRewrites(
 				targetDB = database.Name
 			}
 
+			var schema *sqlbase.SchemaDescriptor
+			if table.SchemaID != 0 {
+				var ok bool
+				if schema, ok = schemasByID[table.SchemaID]; !ok {
+					return errors.Errorf("no schema with ID %d in backup for table %q",
+						table.SchemaID, table.Name)
+				}
+			}
+
 			if _, ok := restoreDBNames[targetDB]; ok {
 				needsNewParentIDs[targetDB] = append(needsNewParentIDs[targetDB], table.ID)
 			} else {
@@ -305,15 +317,47 @@ func allocateTableRewrites(
 					parentID = sqlbase.ID(newParentID)
 				}
 
+				// The table of a user-defined schema is restored into the schema
+				// with the same name in the target database.
+				var schemaID sqlbase.ID
+				if schema != nil {
+					existingSchemaID, err := txn.Get(ctx, sqlbase.MakeNameMetadataKey(parentID, schema.Name))
+					if err != nil {
+						return err
+					}
+					if existingSchemaID.Value == nil {
+						return errors.Errorf("a schema named %q needs to exist in database %q to restore table %q",
+							schema.Name, targetDB, table.Name)
+					}
+					newSchemaID, err := existingSchemaID.Value.GetInt()
+					if err != nil {
+						return err
+					}
+					schemaID = sqlbase.ID(newSchemaID)
+				}
+
 				// Check that the table name is _not_ in use.
 				// This would fail the CPut later anyway, but this yields a prettier error.
-				if err := CheckTableExists(ctx, txn, parentID, table.Name); err != nil {
+				namespaceParentID := parentID
+				if schemaID != 0 {
+					namespaceParentID = schemaID
+				}
+				if err := CheckTableExists(ctx, txn, namespaceParentID, table.Name); err != nil {
 					return err
 				}
 
 				// Check privileges. These will be checked again in the transaction
 				// that actually writes the new table descriptors.
-				{
+				if schemaID != 0 {
+					parentSchema, err := sqlbase.GetSchemaDescFromID(ctx, txn, schemaID)
+					if err != nil {
+						return errors.Wrapf(err, "failed to lookup parent schema %d", schemaID)
+					}
+
+					if err := p.CheckPrivilege(ctx, parentSchema, privilege.CREATE); err != nil {
+						return err
+					}
+				} else {
 					parentDB, err := sqlbase.GetDatabaseDescFromID(ctx, txn, parentID)
 					if err != nil {
 						return errors.Wrapf(err, "failed to lookup parent DB %d", parentID)
@@ -325,7 +369,10 @@ func allocateTableRewrites(
 				}
 				// Create the table rewrite with the new parent ID. We've done all the
 				// up-front validation that we can.
-				tableRewrites[table.ID] = &jobspb.RestoreDetails_TableRewrite{ParentID: parentID}
+				tableRewrites[table.ID] = &jobspb.RestoreDetails_TableRewrite{
+					ParentID: parentID,
+					SchemaID: schemaID,
+				}
 			}
 		}
 		return nil
@@ -352,8 +399,30 @@ func allocateTableRewrites(
 			return nil, err
 		}
 		tableRewrites[db.ID] = &jobspb.RestoreDetails_TableRewrite{TableID: newID}
+		// The user-defined schemas of a restored database are restored with it.
+		for _, schema := range schemasByID {
+			if schema.ParentID != db.ID {
+				continue
+			}
+			newSchemaID, err := sql.GenerateUniqueDescID(ctx, p.ExecCfg().DB)
+			if err != nil {
+				return nil, err
+			}
+			tableRewrites[schema.ID] = &jobspb.RestoreDetails_TableRewrite{
+				TableID: newSchemaID, ParentID: newID,
+			}
+		}
 		for _, tableID := range needsNewParentIDs[db.Name] {
-			tableRewrites[tableID] = &jobspb.RestoreDetails_TableRewrite{ParentID: newID}
+			rewrite := &jobspb.RestoreDetails_TableRewrite{ParentID: newID}
+			if schemaID := tablesByID[tableID].SchemaID; schemaID != 0 {
+				schemaRewrite, ok := tableRewrites[schemaID]
+				if !ok {
+					return nil, errors.Errorf("missing rewrite for schema %d of table %q",
+						schemaID, tablesByID[tableID].Name)
+				}
+				rewrite.SchemaID = schemaRewrite.TableID
+			}
+			tableRewrites[tableID] = rewrite
 		}
 	}
 
@@ -414,6 +483,7 @@ func RewriteTableDescs(
 
 		table.ID = tableRewrite.TableID
 		table.ParentID = tableRewrite.ParentID
+		table.SchemaID = tableRewrite.SchemaID
 
 		if err := table.ForeachNonDropIndex(func(index *sqlbase.IndexDescriptor) error {
 			// Verify that for any interleaved index being restored, the interleave
@@ -870,12 +940,15 @@ func splitAndScatter(
 // WriteTableDescs writes all the the new descriptors: First the ID ->
 // TableDescriptor for the new table, then flip (or initialize) the name -> ID
 // entry so any new queries will use the new one. The tables are assigned the
-// permissions of their parent database and the user must have CREATE permission
-// on that database at the time this function is called.
+// permissions of their parent schema if it is user-defined, and of their parent
+// database otherwise, and the user must have CREATE permission on that schema
+// or database at the time this function is called. The restored schemas are
+// assigned the permissions of their restored parent database.
 func WriteTableDescs(
 	ctx context.Context,
 	txn *client.Txn,
 	databases []*sqlbase.DatabaseDescriptor,
+	schemas []*sqlbase.SchemaDescriptor,
 	tables []*sqlbase.TableDescriptor,
 	user string,
 	settings *cluster.Settings,
@@ -893,8 +966,30 @@ func WriteTableDescs(
 			b.CPut(sqlbase.MakeDescMetadataKey(desc.ID), sqlbase.WrapDescriptor(desc), nil)
 			b.CPut(sqlbase.MakeNameMetadataKey(keys.RootNamespaceID, desc.Name), desc.ID, nil)
 		}
+		wroteSchemas := make(map[sqlbase.ID]*sqlbase.SchemaDescriptor)
+		for _, desc := range schemas {
+			wrote, ok := wroteDBs[desc.ParentID]
+			if !ok {
+				return errors.Errorf("missing parent DB %d of schema %q", desc.ParentID, desc.Name)
+			}
+			desc.Privileges = wrote.GetPrivileges()
+			wroteSchemas[desc.ID] = desc
+			b.CPut(sqlbase.MakeDescMetadataKey(desc.ID), sqlbase.WrapDescriptor(desc), nil)
+			b.CPut(sqlbase.MakeNameMetadataKey(desc.ParentID, desc.Name), desc.ID, nil)
+		}
 		for _, table := range tables {
-			if wrote, ok := wroteDBs[table.ParentID]; ok {
+			if wrote, ok := wroteSchemas[table.SchemaID]; ok {
+				table.Privileges = wrote.GetPrivileges()
+			} else if table.SchemaID != 0 {
+				parentSchema, err := sqlbase.GetSchemaDescFromID(ctx, txn, table.SchemaID)
+				if err != nil {
+					return errors.Wrapf(err, "failed to lookup parent schema %d", table.SchemaID)
+				}
+				if err := sql.CheckPrivilegeForUser(ctx, user, parentSchema, privilege.CREATE); err != nil {
+					return err
+				}
+				table.Privileges = parentSchema.GetPrivileges()
+			} else if wrote, ok := wroteDBs[table.ParentID]; ok {
 				table.Privileges = wrote.GetPrivileges()
 			} else {
 				parentDB, err := sqlbase.GetDatabaseDescFromID(ctx, txn, table.ParentID)
@@ -922,6 +1017,11 @@ func WriteTableDescs(
 			return err
 		}
 
+		for _, desc := range schemas {
+			if err := desc.Validate(); err != nil {
+				return errors.Wrapf(err, "validate schema %d", desc.ID)
+			}
+		}
 		for _, table := range tables {
 			if err := table.Validate(ctx, txn, settings); err != nil {
 				return errors.Wrapf(err, "validate table %d", table.ID)
@@ -966,7 +1066,13 @@ func restore(
 	overrideDB string,
 	job *jobs.Job,
 	resultsCh chan<- tree.Datums,
-) (roachpb.BulkOpSummary, []*sqlbase.DatabaseDescriptor, []*sqlbase.TableDescriptor, error) {
+) (
+	roachpb.BulkOpSummary,
+	[]*sqlbase.DatabaseDescriptor,
+	[]*sqlbase.SchemaDescriptor,
+	[]*sqlbase.TableDescriptor,
+	error,
+) {
 	// A note about contexts and spans in this method: the top-level context
 	// `restoreCtx` is used for orchestration logging. All operations that carry
 	// out work get their individual contexts.
@@ -981,6 +1087,7 @@ func restore(
 	}
 
 	var databases []*sqlbase.DatabaseDescriptor
+	var schemas []*sqlbase.SchemaDescriptor
 	var tables []*sqlbase.TableDescriptor
 	var oldTableIDs []sqlbase.ID
 	for _, desc := range sqlDescs {
@@ -994,6 +1101,13 @@ func restore(
 				databases = append(databases, dbDesc)
 			}
 		}
+		if scDesc := desc.GetSchema(); scDesc != nil {
+			if rewrite, ok := tableRewrites[scDesc.ID]; ok {
+				scDesc.ID = rewrite.TableID
+				scDesc.ParentID = rewrite.ParentID
+				schemas = append(schemas, scDesc)
+			}
+		}
 	}
 
 	log.Eventf(restoreCtx, "starting restore for %d tables", len(tables))
@@ -1005,7 +1119,7 @@ func restore(
 	// Assign new IDs and privileges to the tables, and update all references to
 	// use the new IDs.
 	if err := RewriteTableDescs(tables, tableRewrites, overrideDB); err != nil {
-		return mu.res, nil, nil, err
+		return mu.res, nil, nil, nil, err
 	}
 
 	{
@@ -1025,7 +1139,7 @@ func restore(
 	for i := range tables {
 		newDescBytes, err := protoutil.Marshal(sqlbase.WrapDescriptor(tables[i]))
 		if err != nil {
-			return mu.res, nil, nil, errors.Wrap(err, "marshaling descriptor")
+			return mu.res, nil, nil, nil, errors.Wrap(err, "marshaling descriptor")
 		}
 		rekeys = append(rekeys, roachpb.ImportRequest_TableRekey{
 			OldID:   uint32(oldTableIDs[i]),
@@ -1034,7 +1148,7 @@ func restore(
 	}
 	kr, err := storageccl.MakeKeyRewriterFromRekeys(rekeys)
 	if err != nil {
-		return mu.res, nil, nil, err
+		return mu.res, nil, nil, nil, err
 	}
 
 	// Pivot the backups, which are grouped by time, into requests for import,
@@ -1042,7 +1156,7 @@ func restore(
 	highWaterMark := job.Progress().Details.(*jobspb.Progress_Restore).Restore.HighWater
 	importSpans, _, err := makeImportSpans(spans, backupDescs, highWaterMark, errOnMissingRange)
 	if err != nil {
-		return mu.res, nil, nil, errors.Wrapf(err, "making import requests for %d backups", len(backupDescs))
+		return mu.res, nil, nil, nil, errors.Wrapf(err, "making import requests for %d backups", len(backupDescs))
 	}
 
 	for i := range importSpans {
@@ -1178,10 +1292,10 @@ func restore(
 		// This leaves the data that did get imported in case the user wants to
 		// retry.
 		// TODO(dan): Build tooling to allow a user to restart a failed restore.
-		return mu.res, nil, nil, errors.Wrapf(err, "importing %d ranges", len(importSpans))
+		return mu.res, nil, nil, nil, errors.Wrapf(err, "importing %d ranges", len(importSpans))
 	}
 
-	return mu.res, databases, tables, nil
+	return mu.res, databases, schemas, tables, nil
 }
 
 // RestoreHeader is the header for RESTORE stmt results.
@@ -1389,6 +1503,7 @@ type restoreResumer struct {
 	settings       *cluster.Settings
 	res            roachpb.BulkOpSummary
 	databases      []*sqlbase.DatabaseDescriptor
+	schemas        []*sqlbase.SchemaDescriptor
 	tables         []*sqlbase.TableDescriptor
 	statsRefresher *stats.Refresher
 }
@@ -1404,7 +1519,7 @@ func (r *restoreResumer) Resume(
 		return err
 	}
 
-	res, databases, tables, err := restore(
+	res, databases, schemas, tables, err := restore(
 		ctx,
 		p.ExecCfg().DB,
 		p.ExecCfg().Gossip,
@@ -1418,6 +1533,7 @@ func (r *restoreResumer) Resume(
 	)
 	r.res = res
 	r.databases = databases
+	r.schemas = schemas
 	r.tables = tables
 	r.statsRefresher = p.ExecCfg().StatsRefresher
 	return err
@@ -1448,7 +1564,7 @@ func (r *restoreResumer) OnSuccess(ctx context.Context, txn *client.Txn, job *jo
 	// Write the new TableDescriptors and flip the namespace entries over to
 	// them. After this call, any queries on a table will be served by the newly
 	// restored data.
-	if err := WriteTableDescs(ctx, txn, r.databases, r.tables, job.Payload().Username, r.settings, nil); err != nil {
+	if err := WriteTableDescs(ctx, txn, r.databases, r.schemas, r.tables, job.Payload().Username, r.settings, nil); err != nil {
 		return errors.Wrapf(err, "restoring %d TableDescriptors", len(r.tables))
 	}
 
@@ -18,7 +18,7 @@ import (
 )
 
 type descriptorsMatched struct {
-	// all tables that match targets plus their parent databases.
+	// all tables that match targets plus their parent databases and schemas.
 	descs []sqlbase.Descriptor
 
 	// the databases from which all tables were matched (eg a.* or DATABASE a).
@@ -52,19 +52,35 @@ type descriptorResolver struct {
 	descByID map[sqlbase.ID]sqlbase.Descriptor
 	// Map: db name -> dbID
 	dbsByName map[string]sqlbase.ID
-	// Map: dbID -> obj name -> obj ID
+	// Map: dbID -> user-defined schema name -> schema ID
+	schemasByName map[sqlbase.ID]map[string]sqlbase.ID
+	// Map: dbID or user-defined schema ID -> obj name -> obj ID
 	objsByName map[sqlbase.ID]map[string]sqlbase.ID
 }
 
-// LookupSchema implements the tree.TableNameTargetResolver interface.
+// lookupSchemaID returns the ID under which the objects of the given schema of
+// the given database are recorded in objsByName, which is the ID of the
+// database for the public schema.
+func (r *descriptorResolver) lookupSchemaID(dbName, scName string) (sqlbase.ID, bool) {
+	dbID, ok := r.dbsByName[dbName]
+	if !ok {
+		return 0, false
+	}
+	if scName == tree.PublicSchema {
+		return dbID, true
+	}
+	scID, ok := r.schemasByName[dbID][scName]
+	return scID, ok
+}
+
+// LookupSchema implements the tree.TableNameTargetResolver interface. The
+// result is the descriptor of the database for the public schema, and the
+// descriptor of the schema for a user-defined schema.
 func (r *descriptorResolver) LookupSchema(
 	_ context.Context, dbName, scName string,
 ) (bool, tree.SchemaMeta, error) {
-	if scName != tree.PublicSchema {
-		return false, nil, nil
-	}
-	if dbID, ok := r.dbsByName[dbName]; ok {
-		return true, r.descByID[dbID], nil
+	if scID, ok := r.lookupSchemaID(dbName, scName); ok {
+		return true, r.descByID[scID], nil
 	}
 	return false, nil, nil
 }
@@ -76,14 +92,11 @@ func (r *descriptorResolver) LookupObject(
 	if requireMutable {
 		panic("did not expect request for mutable descriptor")
 	}
-	if scName != tree.PublicSchema {
-		return false, nil, nil
-	}
-	dbID, ok := r.dbsByName[dbName]
+	scID, ok := r.lookupSchemaID(dbName, scName)
 	if !ok {
 		return false, nil, nil
 	}
-	if objMap, ok := r.objsByName[dbID]; ok {
+	if objMap, ok := r.objsByName[scID]; ok {
 		if objID, ok := objMap[obName]; ok {
 			return true, r.descByID[objID], nil
 		}
@@ -95,9 +108,10 @@ func (r *descriptorResolver) LookupObject(
 // known set of descriptors.
 func newDescriptorResolver(descs []sqlbase.Descriptor) (*descriptorResolver, error) {
 	r := &descriptorResolver{
-		descByID:   make(map[sqlbase.ID]sqlbase.Descriptor),
-		dbsByName:  make(map[string]sqlbase.ID),
-		objsByName: make(map[sqlbase.ID]map[string]sqlbase.ID),
+		descByID:      make(map[sqlbase.ID]sqlbase.Descriptor),
+		dbsByName:     make(map[string]sqlbase.ID),
+		schemasByName: make(map[sqlbase.ID]map[string]sqlbase.ID),
+		objsByName:    make(map[sqlbase.ID]map[string]sqlbase.ID),
 	}
 
 	// Iterate to find the databases first. We need that because we also
@@ -119,6 +133,25 @@ func newDescriptorResolver(descs []sqlbase.Descriptor) (*descriptorResolver, err
 		}
 		r.descByID[desc.GetID()] = desc
 	}
+	// Then the schemas, which are needed to check the SchemaID of tables.
+	for _, desc := range descs {
+		if scDesc := desc.GetSchema(); scDesc != nil {
+			parentDesc, ok := r.descByID[scDesc.ParentID]
+			if !ok || parentDesc.GetDatabase() == nil {
+				return nil, errors.Errorf("schema %q has unknown ParentID %d", scDesc.Name, scDesc.ParentID)
+			}
+			scMap := r.schemasByName[scDesc.ParentID]
+			if scMap == nil {
+				scMap = make(map[string]sqlbase.ID)
+			}
+			if _, ok := scMap[scDesc.Name]; ok {
+				return nil, errors.Errorf("duplicate schema name: %q.%q used for ID %d and %d",
+					parentDesc.GetName(), scDesc.Name, scDesc.ID, scMap[scDesc.Name])
+			}
+			scMap[scDesc.Name] = scDesc.ID
+			r.schemasByName[scDesc.ParentID] = scMap
+		}
+	}
 	// Now on to the tables.
 	for _, desc := range descs {
 		if tbDesc := desc.GetTable(); tbDesc != nil {
@@ -133,7 +166,17 @@ func newDescriptorResolver(descs []sqlbase.Descriptor) (*descriptorResolver, err
 				return nil, errors.Errorf("table %q's ParentID %d (%q) is not a database",
 					tbDesc.Name, tbDesc.ParentID, parentDesc.GetName())
 			}
-			objMap := r.objsByName[parentDesc.GetID()]
+			// The tables of user-defined schemas are recorded under the ID of
+			// their schema.
+			objParentID := parentDesc.GetID()
+			if tbDesc.SchemaID != 0 {
+				scDesc, ok := r.descByID[tbDesc.SchemaID]
+				if !ok || scDesc.GetSchema() == nil || scDesc.GetSchema().ParentID != tbDesc.ParentID {
+					return nil, errors.Errorf("table %q has unknown SchemaID %d", tbDesc.Name, tbDesc.SchemaID)
+				}
+				objParentID = tbDesc.SchemaID
+			}
+			objMap := r.objsByName[objParentID]
 			if objMap == nil {
 				objMap = make(map[string]sqlbase.ID)
 			}
@@ -142,7 +185,7 @@ func newDescriptorResolver(descs []sqlbase.Descriptor) (*descriptorResolver, err
 					parentDesc.GetName(), tbDesc.Name, tbDesc.ID, objMap[tbDesc.Name])
 			}
 			objMap[tbDesc.Name] = tbDesc.ID
-			r.objsByName[parentDesc.GetID()] = objMap
+			r.objsByName[objParentID] = objMap
 		}
 	}
 
@@ -164,9 +207,6 @@ func descriptorsMatchingTargets(
 	descriptors []sqlbase.Descriptor,
 	targets tree.TargetList,
 ) (descriptorsMatched, error) {
-	// TODO(dan): once CockroachDB supports schemas in addition to
-	// catalogs, then this method will need to support it.
-
 	ret := descriptorsMatched{}
 
 	resolver, err := newDescriptorResolver(descriptors)
@@ -192,6 +232,17 @@ func descriptorsMatchingTargets(
 		}
 	}
 
+	// Pulling in the objects of a user-defined schema needs to pull in the
+	// schema too.
+	alreadyRequestedSchemas := make(map[sqlbase.ID]struct{})
+	alreadyExpandedSchemas := make(map[sqlbase.ID]struct{})
+	requestSchema := func(scID sqlbase.ID) {
+		if _, ok := alreadyRequestedSchemas[scID]; !ok {
+			ret.descs = append(ret.descs, resolver.descByID[scID])
+			alreadyRequestedSchemas[scID] = struct{}{}
+		}
+	}
+
 	// Process all the TABLE requests.
 	// Pulling in a table needs to pull in the underlying database too.
 	alreadyRequestedTables := make(map[sqlbase.ID]struct{})
@@ -220,6 +271,10 @@ func descriptorsMatchingTargets(
 				ret.descs = append(ret.descs, parentDesc)
 				alreadyRequestedDBs[parentID] = struct{}{}
 			}
+			// Likewise for its user-defined schema, if any.
+			if scID := desc.GetTable().SchemaID; scID != sqlbase.InvalidID {
+				requestSchema(scID)
+			}
 			// Then request the table itself.
 			if _, ok := alreadyRequestedTables[desc.GetID()]; !ok {
 				alreadyRequestedTables[desc.GetID()] = struct{}{}
@@ -236,6 +291,18 @@ func descriptorsMatchingTargets(
 			}
 			desc := descI.(sqlbase.Descriptor)
 
+			if scDesc := desc.GetSchema(); scDesc != nil {
+				// The objects of a user-defined schema are requested along with
+				// the schema and its database, but the database is not expanded.
+				if _, ok := alreadyRequestedDBs[scDesc.ParentID]; !ok {
+					ret.descs = append(ret.descs, resolver.descByID[scDesc.ParentID])
+					alreadyRequestedDBs[scDesc.ParentID] = struct{}{}
+				}
+				requestSchema(scDesc.ID)
+				alreadyExpandedSchemas[scDesc.ID] = struct{}{}
+				continue
+			}
+
 			// If the database is not requested already, request it now.
 			dbID := desc.GetID()
 			if _, ok := alreadyRequestedDBs[dbID]; !ok {
@@ -254,10 +321,26 @@ func descriptorsMatchingTargets(
 		}
 	}
 
-	// Then process the database expansions.
+	// Then process the database expansions, which include the user-defined
+	// schemas of the databases.
 	for dbID := range alreadyExpandedDBs {
 		for _, tblID := range resolver.objsByName[dbID] {
 			if _, ok := alreadyRequestedTables[tblID]; !ok {
+				alreadyRequestedTables[tblID] = struct{}{}
+				ret.descs = append(ret.descs, resolver.descByID[tblID])
+			}
+		}
+		for _, scID := range resolver.schemasByName[dbID] {
+			requestSchema(scID)
+			alreadyExpandedSchemas[scID] = struct{}{}
+		}
+	}
+
+	// And finally the schema expansions.
+	for scID := range alreadyExpandedSchemas {
+		for _, tblID := range resolver.objsByName[scID] {
+			if _, ok := alreadyRequestedTables[tblID]; !ok {
+				alreadyRequestedTables[tblID] = struct{}{}
 				ret.descs = append(ret.descs, resolver.descByID[tblID])
 			}
 		}
@@ -33,6 +33,8 @@ func TestDescriptorsMatchingTargets(t *testing.T) {
 		*sqlbase.WrapDescriptor(&sqlbase.TableDescriptor{ID: 4, Name: "baz", ParentID: 3}),
 		*sqlbase.WrapDescriptor(&sqlbase.DatabaseDescriptor{ID: 3, Name: "data"}),
 		*sqlbase.WrapDescriptor(&sqlbase.DatabaseDescriptor{ID: 5, Name: "empty"}),
+		*sqlbase.WrapDescriptor(&sqlbase.SchemaDescriptor{ID: 6, Name: "sc", ParentID: 3}),
+		*sqlbase.WrapDescriptor(&sqlbase.TableDescriptor{ID: 7, Name: "qux", ParentID: 3, SchemaID: 6}),
 	}
 
 	tests := []struct {
@@ -45,13 +47,13 @@ func TestDescriptorsMatchingTargets(t *testing.T) {
 		{"", "DATABASE system", []string{"system", "foo", "bar"}, []string{"system"}, ``},
 		{"", "DATABASE system, noexist", nil, nil, `unknown database "noexist"`},
 		{"", "DATABASE system, system", []string{"system", "foo", "bar"}, []string{"system"}, ``},
-		{"", "DATABASE data", []string{"data", "baz"}, []string{"data"}, ``},
-		{"", "DATABASE system, data", []string{"system", "foo", "bar", "data", "baz"}, []string{"data", "system"}, ``},
+		{"", "DATABASE data", []string{"data", "baz", "sc", "qux"}, []string{"data"}, ``},
+		{"", "DATABASE system, data", []string{"system", "foo", "bar", "data", "baz", "sc", "qux"}, []string{"data", "system"}, ``},
 		{"", "DATABASE system, data, noexist", nil, nil, `unknown database "noexist"`},
 		{"system", "DATABASE system", []string{"system", "foo", "bar"}, []string{"system"}, ``},
 		{"system", "DATABASE system, noexist", nil, nil, `unknown database "noexist"`},
-		{"system", "DATABASE data", []string{"data", "baz"}, []string{"data"}, ``},
-		{"system", "DATABASE system, data", []string{"system", "foo", "bar", "data", "baz"}, []string{"data", "system"}, ``},
+		{"system", "DATABASE data", []string{"data", "baz", "sc", "qux"}, []string{"data"}, ``},
+		{"system", "DATABASE system, data", []string{"system", "foo", "bar", "data", "baz", "sc", "qux"}, []string{"data", "system"}, ``},
 		{"system", "DATABASE system, data, noexist", nil, nil, `unknown database "noexist"`},
 
 		{"", "TABLE foo", nil, nil, `table "foo" does not exist`},
@@ -63,7 +65,7 @@ func TestDescriptorsMatchingTargets(t *testing.T) {
 		{"", "TABLE *, system.public.foo", nil, nil, `"\*" does not match any valid database or schema`},
 		{"noexist", "TABLE *", nil, nil, `"\*" does not match any valid database or schema`},
 		{"system", "TABLE *", []string{"system", "foo", "bar"}, nil, ``},
-		{"data", "TABLE *", []string{"data", "baz"}, nil, ``},
+		{"data", "TABLE *", []string{"data", "baz", "sc", "qux"}, nil, ``},
 		{"empty", "TABLE *", []string{"empty"}, nil, ``},
 
 		{"", "TABLE foo, baz", nil, nil, `table "(foo|baz)" does not exist`},
@@ -89,6 +91,13 @@ func TestDescriptorsMatchingTargets(t *testing.T) {
 		{"data", "TABLE system.public.*, baz", []string{"system", "foo", "bar", "data", "baz"}, nil, ``},
 		{"data", "TABLE system.public.*, foo, baz", nil, nil, `table "(foo|baz)" does not exist`},
 
+		{"", "TABLE data.sc.qux", []string{"data", "sc", "qux"}, nil, ``},
+		{"data", "TABLE sc.qux", []string{"data", "sc", "qux"}, nil, ``},
+		{"data", "TABLE qux", nil, nil, `table "qux" does not exist`},
+		{"", "TABLE data.sc.*", []string{"data", "sc", "qux"}, nil, ``},
+		{"data", "TABLE sc.*, baz", []string{"data", "sc", "qux", "baz"}, nil, ``},
+		{"", "TABLE data.noexist.*", nil, nil, `"data\.noexist\.\*" does not match any valid database or schema`},
+
 		{"", "TABLE SyStEm.FoO", []string{"system", "foo"}, nil, ``},
 		{"", "TABLE SyStEm.pUbLic.FoO", []string{"system", "foo"}, nil, ``},
 
@@ -989,7 +989,8 @@ func parseGossipValues(gossipInfo *gossip.InfoStatus) (string, error) {
 				return "", errors.Wrapf(err, "failed to parse value for key %q", key)
 			}
 			output = append(output, fmt.Sprintf("%q: %+v", key, drainingInfo))
-		} else if strings.HasPrefix(key, gossip.KeyTableStatAddedPrefix) {
+		} else if strings.HasPrefix(key, gossip.KeyTableStatAddedPrefix) ||
+			strings.HasPrefix(key, gossip.KeySQLNotificationPrefix) {
 			gossipedTime := timeutil.Unix(0, info.OrigStamp)
 			output = append(output, fmt.Sprintf("%q: %v", key, gossipedTime))
 		} else if strings.HasPrefix(key, gossip.KeyGossipClientsPrefix) {
@@ -100,6 +100,11 @@ const (
 	// client connections a node has open. This is used by other nodes in the
 	// cluster to build a map of the gossip network.
 	KeyGossipClientsPrefix = "gossip-clients"
+
+	// KeySQLNotificationPrefix is the prefix for keys that carry the
+	// asynchronous notifications recently sent by the SQL sessions of a node
+	// with NOTIFY. The suffix is the ID of the node.
+	KeySQLNotificationPrefix = "sql-notification"
 )
 
 // MakeKey creates a canonical key under which to gossip a piece of
@@ -222,6 +227,12 @@ func MakeTableDisableMergesKey(tableID uint32) string {
 	return MakeKey(KeyTableDisableMergesPrefix, strconv.FormatUint(uint64(tableID), 10 /* base */))
 }
 
+// MakeSQLNotificationKey returns the gossip key used to send the SQL
+// notifications of the given node.
+func MakeSQLNotificationKey(nodeID roachpb.NodeID) string {
+	return MakeKey(KeySQLNotificationPrefix, strconv.Itoa(int(nodeID)))
+}
+
 // removePrefixFromKey removes the key prefix and separator and returns what's
 // left. Returns an error if the key doesn't have this prefix.
 func removePrefixFromKey(key, prefix string) (string, error) {
@@ -215,8 +215,27 @@ type TxnSender interface {
 	// However, this is used by DistSQL for sending the transaction over the wire
 	// when it creates flows.
 	SerializeTxn() *roachpb.Transaction
+
+	// CreateSavepoint establishes a savepoint. The writes performed by the
+	// transaction after the savepoint can later be rolled back with
+	// RollbackToSavepoint.
+	CreateSavepoint(context.Context) (SavepointToken, error)
+
+	// RollbackToSavepoint rolls back the writes performed by the transaction
+	// since the given savepoint was established: they are no longer visible
+	// to the transaction and are discarded when it commits. If the
+	// transaction had encountered a non-retriable error since, it can be
+	// used again afterwards. The savepoint remains valid.
+	//
+	// Savepoints do not survive transaction restarts; rolling back to a
+	// savepoint established in an earlier epoch returns an error.
+	RollbackToSavepoint(context.Context, SavepointToken) error
 }
 
+// SavepointToken represents a savepoint of a transaction. It is created by
+// TxnSender.CreateSavepoint and its contents are specific to the TxnSender.
+type SavepointToken interface{}
+
 // TxnStatusOpt represents options for TxnSender.GetMeta().
 type TxnStatusOpt int
 
@@ -374,6 +393,16 @@ func (m *MockTransactionalSender) UpdateStateOnRemoteRetryableErr(
 // DisablePipelining is part of the client.TxnSender interface.
 func (m *MockTransactionalSender) DisablePipelining() error { return nil }
 
+// CreateSavepoint is part of the client.TxnSender interface.
+func (m *MockTransactionalSender) CreateSavepoint(context.Context) (SavepointToken, error) {
+	panic("unimplemented")
+}
+
+// RollbackToSavepoint is part of the client.TxnSender interface.
+func (m *MockTransactionalSender) RollbackToSavepoint(context.Context, SavepointToken) error {
+	panic("unimplemented")
+}
+
 // EagerRecord is part of the client.TxnSender interface.
 func (m *MockTransactionalSender) EagerRecord() error { return nil }
 
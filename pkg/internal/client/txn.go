@@ -844,6 +844,29 @@ func (txn *Txn) GetTxnCoordMeta(ctx context.Context) roachpb.TxnCoordMeta {
 	return meta
 }
 
+// CreateSavepoint establishes a savepoint. The writes performed by the
+// transaction after it can later be rolled back with RollbackToSavepoint.
+func (txn *Txn) CreateSavepoint(ctx context.Context) (SavepointToken, error) {
+	if txn.typ != RootTxn {
+		return nil, errors.Errorf("CreateSavepoint() called on leaf txn")
+	}
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.CreateSavepoint(ctx)
+}
+
+// RollbackToSavepoint rolls back the writes performed by the transaction
+// since the given savepoint was established. The transaction can be used
+// again afterwards even if it had encountered a non-retriable error.
+func (txn *Txn) RollbackToSavepoint(ctx context.Context, s SavepointToken) error {
+	if txn.typ != RootTxn {
+		return errors.Errorf("RollbackToSavepoint() called on leaf txn")
+	}
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.RollbackToSavepoint(ctx, s)
+}
+
 // GetTxnCoordMetaOrRejectClient is like GetTxnCoordMeta except, if the
 // transaction is already aborted or otherwise in a final state, it returns an
 // error. If the transaction is aborted, the error will be a retryable one, and
@@ -30,6 +30,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
 
@@ -294,6 +295,16 @@ func (r *RSG) GenerateRandomArg(typ types.T) string {
 			panic(err)
 		}
 		v = fmt.Sprintf(`'%s'`, tree.DJSON{JSON: j})
+	case types.TSVector:
+		r.lock.Lock()
+		tv := tsearch.RandomTSVector(r.src)
+		r.lock.Unlock()
+		v = tree.NewDTSVector(tv).String()
+	case types.TSQuery:
+		r.lock.Lock()
+		q := tsearch.RandomTSQuery(r.src)
+		r.lock.Unlock()
+		v = tree.NewDTSQuery(q).String()
 	default:
 		// Check types that can't be compared using equality
 		switch types.UnwrapType(typ).(type) {
@@ -106,6 +106,12 @@ type TxnCoordSender struct {
 
 		txnState txnState
 
+		// recoverableErr is set when the txn is in the txnError state because
+		// of an error which leaves the txn in a consistent state, so that the
+		// txn can be used again after rolling back to a savepoint (see
+		// isSavepointRecoverableError).
+		recoverableErr bool
+
 		// active is set whenever the transaction has sent any requests.
 		active bool
 
@@ -688,6 +694,7 @@ func (tc *TxnCoordSender) Send(
 
 		if !retriable {
 			tc.mu.txnState = txnError
+			tc.mu.recoverableErr = isSavepointRecoverableError(pErr)
 		}
 
 		return nil, pErr
@@ -1070,3 +1077,81 @@ func (tc *TxnCoordSender) SerializeTxn() *roachpb.Transaction {
 	cpy := tc.mu.txn.Clone()
 	return &cpy
 }
+
+// savepoint is the TxnCoordSender's implementation of client.SavepointToken.
+type savepoint struct {
+	txnID uuid.UUID
+	epoch uint32
+	// seqNum is the sequence number of the last request that was sent before
+	// the savepoint was established. Writes with higher sequence numbers are
+	// rolled back by RollbackToSavepoint.
+	seqNum int32
+}
+
+// CreateSavepoint is part of the client.TxnSender interface.
+func (tc *TxnCoordSender) CreateSavepoint(ctx context.Context) (client.SavepointToken, error) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if pErr := tc.maybeRejectClientLocked(ctx, nil /* ba */); pErr != nil {
+		return nil, pErr.GoError()
+	}
+	return &savepoint{
+		txnID:  tc.mu.txn.ID,
+		epoch:  tc.mu.txn.Epoch,
+		seqNum: tc.interceptorAlloc.txnSeqNumAllocator.seqNumCounter,
+	}, nil
+}
+
+// RollbackToSavepoint is part of the client.TxnSender interface.
+func (tc *TxnCoordSender) RollbackToSavepoint(ctx context.Context, s client.SavepointToken) error {
+	sp := s.(*savepoint)
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if tc.mu.txnState == txnFinalized || tc.mu.txn.Status != roachpb.PENDING {
+		return tc.maybeRejectClientLocked(ctx, nil /* ba */).GoError()
+	}
+	if sp.txnID != tc.mu.txn.ID || sp.epoch != tc.mu.txn.Epoch {
+		return errors.Errorf("cannot roll back to a savepoint established before the transaction restarted")
+	}
+	if tc.mu.txnState == txnError && !tc.mu.recoverableErr {
+		// The error may have left the txn (or the state of the interceptors)
+		// in an unknown state, so the txn can't be used any more.
+		return tc.maybeRejectClientLocked(ctx, nil /* ba */).GoError()
+	}
+
+	// Ignore the writes performed since the savepoint. The sequence number
+	// of the savepoint is kept: the sequence numbers of the writes that
+	// follow keep increasing, so they are not ignored.
+	if seqNum := tc.interceptorAlloc.txnSeqNumAllocator.seqNumCounter; seqNum > sp.seqNum {
+		tc.mu.txn.AddIgnoredSeqNumRange(roachpb.IgnoredSeqNumRange{
+			Start: sp.seqNum + 1, End: seqNum,
+		})
+	}
+
+	// The batch that moved the txn to the txnError state, if any, has been
+	// rolled back along with the other writes, so the txn can be used again.
+	tc.mu.txnState = txnPending
+	tc.mu.recoverableErr = false
+	return nil
+}
+
+// isSavepointRecoverableError returns whether a txn that encountered the given
+// non-retriable error can be used again after rolling back to a savepoint.
+// This is only the case for errors which are known to leave the txn and its
+// interceptors in a consistent state: the failed batch definitely did not
+// commit, and any intents it wrote are tracked by the intent collector (and
+// are ignored once the savepoint is rolled back). Other errors, including
+// ambiguous results, keep the txn in the txnError state.
+func isSavepointRecoverableError(pErr *roachpb.Error) bool {
+	switch pErr.GetDetail().(type) {
+	case *roachpb.ConditionFailedError:
+		// Returned by a CPut or InitPut whose condition did not hold, which is
+		// how SQL detects unique constraint violations.
+		return true
+	default:
+		return false
+	}
+}
@@ -2031,6 +2031,84 @@ func TestSequenceNumbers(t *testing.T) {
 	}
 }
 
+// TestTxnCoordSenderSavepoints verifies that rolling back to a savepoint
+// makes the transaction ignore the sequence numbers of the writes performed
+// since the savepoint, and that savepoints do not survive a restart.
+func TestTxnCoordSenderSavepoints(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	clock := hlc.NewClock(hlc.UnixNano, time.Nanosecond)
+	ambient := log.AmbientContext{Tracer: tracing.NewTracer()}
+	sender := &mockSender{}
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	var lastTxn roachpb.Transaction
+	sender.match(func(ba roachpb.BatchRequest) (*roachpb.BatchResponse, *roachpb.Error) {
+		if _, ok := ba.GetArg(roachpb.Put); ok {
+			lastTxn = ba.Txn.Clone()
+		}
+		br := ba.CreateReply()
+		br.Txn = ba.Txn
+		return br, nil
+	})
+	factory := NewTxnCoordSenderFactory(
+		TxnCoordSenderFactoryConfig{
+			AmbientCtx: ambient,
+			Clock:      clock,
+			Stopper:    stopper,
+		},
+		sender,
+	)
+	db := client.NewDB(testutils.MakeAmbientCtx(), factory, clock)
+	txn := client.NewTxn(ctx, db, 0 /* gatewayNodeID */, client.RootTxn)
+
+	// The BeginTransaction and the Put get the sequence numbers 1 and 2.
+	if err := txn.Put(ctx, "a", "val"); err != nil {
+		t.Fatal(err)
+	}
+	sp, err := txn.CreateSavepoint(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// These writes get the sequence numbers 3 and 4.
+	if err := txn.Put(ctx, "b", "val"); err != nil {
+		t.Fatal(err)
+	}
+	if err := txn.Put(ctx, "c", "val"); err != nil {
+		t.Fatal(err)
+	}
+	if err := txn.RollbackToSavepoint(ctx, sp); err != nil {
+		t.Fatal(err)
+	}
+	// The savepoint can be rolled back to again; there is nothing to ignore.
+	if err := txn.RollbackToSavepoint(ctx, sp); err != nil {
+		t.Fatal(err)
+	}
+	if err := txn.Put(ctx, "d", "val"); err != nil {
+		t.Fatal(err)
+	}
+	expected := []roachpb.IgnoredSeqNumRange{{Start: 3, End: 4}}
+	if !reflect.DeepEqual(lastTxn.IgnoredSeqNums, expected) {
+		t.Fatalf("expected ignored sequence numbers %v, got %v", expected, lastTxn.IgnoredSeqNums)
+	}
+
+	// A restart forgets the ignored sequence numbers and invalidates the
+	// savepoint.
+	txn.ManualRestart(ctx, clock.Now())
+	if err := txn.RollbackToSavepoint(ctx, sp); !testutils.IsError(
+		err, "cannot roll back to a savepoint established before the transaction restarted",
+	) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := txn.Put(ctx, "e", "val"); err != nil {
+		t.Fatal(err)
+	}
+	if len(lastTxn.IgnoredSeqNums) != 0 {
+		t.Fatalf("expected no ignored sequence numbers, got %v", lastTxn.IgnoredSeqNums)
+	}
+}
+
 // TestConcurrentTxnRequests verifies that multiple requests can be executed on
 // a transaction at the same time from multiple goroutines. It makes sure that
 // exactly one BeginTxnRequest and one EndTxnRequest are sent.
@@ -2441,3 +2519,96 @@ func TestLeafTxnClientRejectError(t *testing.T) {
 		t.Fatalf("expected UnhandledRetryableError(TransactionAbortedError), got: (%T) %v", err, err)
 	}
 }
+
+// TestTxnCoordSenderRollbackToSavepointAfterError verifies that rolling back
+// to a savepoint makes a txn usable again after a ConditionFailedError, but
+// not after other errors (such as ambiguous results) that might leave the txn
+// in an unknown state.
+func TestTxnCoordSenderRollbackToSavepointAfterError(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		name        string
+		pErr        *roachpb.Error
+		recoverable bool
+	}{
+		{
+			name:        "condition failed",
+			pErr:        roachpb.NewError(&roachpb.ConditionFailedError{}),
+			recoverable: true,
+		},
+		{
+			name:        "ambiguous result",
+			pErr:        roachpb.NewError(roachpb.NewAmbiguousResultError("boom")),
+			recoverable: false,
+		},
+		{
+			name:        "generic error",
+			pErr:        roachpb.NewErrorf("boom"),
+			recoverable: false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			clock := hlc.NewClock(hlc.UnixNano, time.Nanosecond)
+			ambient := log.AmbientContext{Tracer: tracing.NewTracer()}
+			sender := &mockSender{}
+			stopper := stop.NewStopper()
+			defer stopper.Stop(ctx)
+
+			sender.match(func(ba roachpb.BatchRequest) (*roachpb.BatchResponse, *roachpb.Error) {
+				if _, ok := ba.GetArg(roachpb.ConditionalPut); ok {
+					pErr := *tc.pErr
+					return nil, &pErr
+				}
+				br := ba.CreateReply()
+				br.Txn = ba.Txn
+				return br, nil
+			})
+			factory := NewTxnCoordSenderFactory(
+				TxnCoordSenderFactoryConfig{
+					AmbientCtx: ambient,
+					Clock:      clock,
+					Stopper:    stopper,
+				},
+				sender,
+			)
+			db := client.NewDB(testutils.MakeAmbientCtx(), factory, clock)
+			txn := client.NewTxn(ctx, db, 0 /* gatewayNodeID */, client.RootTxn)
+
+			if err := txn.Put(ctx, "a", "val"); err != nil {
+				t.Fatal(err)
+			}
+			sp, err := txn.CreateSavepoint(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := txn.CPut(ctx, "b", "val", nil); err == nil {
+				t.Fatal("expected the CPut to fail")
+			}
+			// The txn can't be used until it is rolled back to the savepoint.
+			const errTxnAlreadyEncountered = "txn already encountered an error"
+			if err := txn.Put(ctx, "c", "val"); !testutils.IsError(err, errTxnAlreadyEncountered) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err = txn.RollbackToSavepoint(ctx, sp)
+			if !tc.recoverable {
+				if !testutils.IsError(err, errTxnAlreadyEncountered) {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if err := txn.Put(ctx, "c", "val"); !testutils.IsError(err, errTxnAlreadyEncountered) {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := txn.Put(ctx, "c", "val"); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -129,6 +129,18 @@ func IsReadOnly(args Request) bool {
 	return (flags&isRead) != 0 && (flags&isWrite) == 0
 }
 
+// IsLockingNoWait returns true if the request is a locking scan which does not
+// wait on the keys locked by other transactions.
+func IsLockingNoWait(args Request) bool {
+	switch t := args.(type) {
+	case *ScanRequest:
+		return t.KeyLocking != LOCK_NONE && t.WaitPolicy == LOCK_WAIT_ERROR
+	case *ReverseScanRequest:
+		return t.KeyLocking != LOCK_NONE && t.WaitPolicy == LOCK_WAIT_ERROR
+	}
+	return false
+}
+
 // IsTransactional returns true if the request may be part of a
 // transaction.
 func IsTransactional(args Request) bool {
@@ -1011,8 +1023,21 @@ func (drr *DeleteRangeRequest) flags() int {
 // Note that ClearRange commands cannot be part of a transaction as
 // they clear all MVCC versions.
 func (*ClearRangeRequest) flags() int { return isWrite | isRange | isAlone }
-func (*ScanRequest) flags() int       { return isRead | isRange | isTxn | updatesReadTSCache | needsRefresh }
-func (*ReverseScanRequest) flags() int {
+
+// Locking scans write lock-only intents on the keys they return, so they are
+// transactional writes which go through Raft and whose intents are resolved
+// like those of any other write. The intents never become new versions of
+// the keys, so locking scans don't need to consult the timestamp cache.
+func (sr *ScanRequest) flags() int {
+	if sr.KeyLocking != LOCK_NONE {
+		return isRead | isWrite | isRange | isTxn | isTxnWrite | updatesReadTSCache | needsRefresh
+	}
+	return isRead | isRange | isTxn | updatesReadTSCache | needsRefresh
+}
+func (rsr *ReverseScanRequest) flags() int {
+	if rsr.KeyLocking != LOCK_NONE {
+		return isRead | isWrite | isRange | isReverse | isTxn | isTxnWrite | updatesReadTSCache | needsRefresh
+	}
 	return isRead | isRange | isReverse | isTxn | updatesReadTSCache | needsRefresh
 }
 func (*BeginTransactionRequest) flags() int { return isWrite | isTxn }
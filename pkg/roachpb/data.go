@@ -823,6 +823,9 @@ func (t Transaction) Clone() Transaction {
 	// Note that we're not cloning the span keys under the assumption that the
 	// keys themselves are not mutable.
 	t.Intents = append([]Span(nil), t.Intents...)
+	if t.IgnoredSeqNums != nil {
+		t.IgnoredSeqNums = append([]IgnoredSeqNumRange(nil), t.IgnoredSeqNums...)
+	}
 	return t
 }
 
@@ -939,6 +942,9 @@ func (t *Transaction) Restart(
 	t.UpgradePriority(upgradePriority)
 	t.WriteTooOld = false
 	t.Sequence = 0
+	// The sequence numbers of the new epoch start over, so the writes that
+	// were rolled back in the previous epoch no longer need to be ignored.
+	t.IgnoredSeqNums = nil
 	// Reset Writing. Since we're using a new epoch, we don't care about the abort
 	// cache.
 	t.Writing = false
@@ -997,8 +1003,16 @@ func (t *Transaction) Update(o *Transaction) {
 		t.OrigTimestampWasObserved = t.OrigTimestampWasObserved || o.OrigTimestampWasObserved
 	}
 
+	// The ignored sequence numbers are only meaningful within an epoch: the
+	// ones of a newer epoch replace ours, the ones of the same epoch are
+	// merged with ours.
 	if t.Epoch < o.Epoch {
 		t.Epoch = o.Epoch
+		t.IgnoredSeqNums = o.IgnoredSeqNums
+	} else if t.Epoch == o.Epoch {
+		for _, r := range o.IgnoredSeqNums {
+			t.AddIgnoredSeqNumRange(r)
+		}
 	}
 
 	t.Timestamp.Forward(o.Timestamp)
@@ -1031,6 +1045,51 @@ func (t *Transaction) Update(o *Transaction) {
 	}
 }
 
+// AddIgnoredSeqNumRange marks the sequence numbers of the given range as
+// ignored. The list of ignored ranges is kept sorted, and overlapping or
+// adjacent ranges are merged. The slice is never modified in place, so that
+// it can be shared between copies of the transaction.
+func (t *Transaction) AddIgnoredSeqNumRange(newRange IgnoredSeqNumRange) {
+	res := make([]IgnoredSeqNumRange, 0, len(t.IgnoredSeqNums)+1)
+	inserted := false
+	for _, r := range t.IgnoredSeqNums {
+		switch {
+		case r.End+1 < newRange.Start:
+			// r is entirely before newRange.
+			res = append(res, r)
+		case newRange.End+1 < r.Start:
+			// r is entirely after newRange.
+			if !inserted {
+				res = append(res, newRange)
+				inserted = true
+			}
+			res = append(res, r)
+		default:
+			// r overlaps or is adjacent to newRange; absorb it.
+			if r.Start < newRange.Start {
+				newRange.Start = r.Start
+			}
+			if r.End > newRange.End {
+				newRange.End = r.End
+			}
+		}
+	}
+	if !inserted {
+		res = append(res, newRange)
+	}
+	t.IgnoredSeqNums = res
+}
+
+// TxnSeqIsIgnored returns whether the given sequence number is part of one
+// of the ignored ranges, which must be sorted as maintained by
+// Transaction.AddIgnoredSeqNumRange.
+func TxnSeqIsIgnored(seq int32, ignored []IgnoredSeqNumRange) bool {
+	i := sort.Search(len(ignored), func(i int) bool {
+		return ignored[i].End >= seq
+	})
+	return i < len(ignored) && ignored[i].Start <= seq
+}
+
 // UpgradePriority sets transaction priority to the maximum of current
 // priority and the specified minPriority. The exception is if the
 // current priority is set to the minimum, in which case the minimum
@@ -1056,6 +1115,9 @@ func (t Transaction) String() string {
 	if ni := len(t.Intents); t.Status != PENDING && ni > 0 {
 		fmt.Fprintf(&buf, " int=%d", ni)
 	}
+	if nr := len(t.IgnoredSeqNums); nr > 0 {
+		fmt.Fprintf(&buf, " isn=%d", nr)
+	}
 	return buf.String()
 }
 
@@ -1102,6 +1164,7 @@ func (t *Transaction) AsRecord() TransactionRecord {
 	tr.LastHeartbeat = t.LastHeartbeat
 	tr.OrigTimestamp = t.OrigTimestamp
 	tr.Intents = t.Intents
+	tr.IgnoredSeqNums = t.IgnoredSeqNums
 	return tr
 }
 
@@ -1115,6 +1178,7 @@ func (tr *TransactionRecord) AsTransaction() Transaction {
 	t.LastHeartbeat = tr.LastHeartbeat
 	t.OrigTimestamp = tr.OrigTimestamp
 	t.Intents = tr.Intents
+	t.IgnoredSeqNums = tr.IgnoredSeqNums
 	return t
 }
 
@@ -1443,9 +1507,10 @@ func AsIntents(spans []Span, txn *Transaction) []Intent {
 	ret := make([]Intent, len(spans))
 	for i := range spans {
 		ret[i] = Intent{
-			Span:   spans[i],
-			Txn:    txn.TxnMeta,
-			Status: txn.Status,
+			Span:           spans[i],
+			Txn:            txn.TxnMeta,
+			Status:         txn.Status,
+			IgnoredSeqNums: txn.IgnoredSeqNums,
 		}
 	}
 	return ret
@@ -494,6 +494,7 @@ var nonZeroTxn = Transaction{
 	Intents:                  []Span{{Key: []byte("a"), EndKey: []byte("b")}},
 	EpochZeroTimestamp:       makeTS(1, 1),
 	OrigTimestampWasObserved: true,
+	IgnoredSeqNums:           []IgnoredSeqNumRange{{Start: 888, End: 999}},
 }
 
 func TestTransactionUpdate(t *testing.T) {
@@ -537,6 +538,77 @@ func TestTransactionUpdateEpochZero(t *testing.T) {
 	}
 }
 
+func TestTransactionUpdateIgnoredSeqNums(t *testing.T) {
+	txn := nonZeroTxn.Clone()
+	txn.IgnoredSeqNums = []IgnoredSeqNumRange{{Start: 1, End: 2}}
+
+	// Ranges of the same epoch are merged.
+	o := txn.Clone()
+	o.IgnoredSeqNums = []IgnoredSeqNumRange{{Start: 5, End: 7}}
+	txn.Update(&o)
+	if e := []IgnoredSeqNumRange{{Start: 1, End: 2}, {Start: 5, End: 7}}; !reflect.DeepEqual(e, txn.IgnoredSeqNums) {
+		t.Fatalf("expected %v, got %v", e, txn.IgnoredSeqNums)
+	}
+
+	// Ranges of an older epoch are ignored.
+	o.Epoch--
+	o.IgnoredSeqNums = []IgnoredSeqNumRange{{Start: 10, End: 10}}
+	txn.Update(&o)
+	if e := []IgnoredSeqNumRange{{Start: 1, End: 2}, {Start: 5, End: 7}}; !reflect.DeepEqual(e, txn.IgnoredSeqNums) {
+		t.Fatalf("expected %v, got %v", e, txn.IgnoredSeqNums)
+	}
+
+	// Ranges of a newer epoch replace ours.
+	o.Epoch += 2
+	txn.Update(&o)
+	if e := []IgnoredSeqNumRange{{Start: 10, End: 10}}; !reflect.DeepEqual(e, txn.IgnoredSeqNums) {
+		t.Fatalf("expected %v, got %v", e, txn.IgnoredSeqNums)
+	}
+
+	// Restarting the transaction clears them.
+	txn.Restart(NormalUserPriority, 0, txn.Timestamp)
+	if txn.IgnoredSeqNums != nil {
+		t.Fatalf("expected no ignored seqnums after restart, got %v", txn.IgnoredSeqNums)
+	}
+}
+
+func TestTransactionAddIgnoredSeqNumRange(t *testing.T) {
+	r := func(start, end int32) IgnoredSeqNumRange {
+		return IgnoredSeqNumRange{Start: start, End: end}
+	}
+	testData := []struct {
+		list     []IgnoredSeqNumRange
+		newRange IgnoredSeqNumRange
+		exp      []IgnoredSeqNumRange
+	}{
+		{nil, r(1, 2), []IgnoredSeqNumRange{r(1, 2)}},
+		{[]IgnoredSeqNumRange{r(1, 2)}, r(4, 5), []IgnoredSeqNumRange{r(1, 2), r(4, 5)}},
+		{[]IgnoredSeqNumRange{r(4, 5)}, r(1, 2), []IgnoredSeqNumRange{r(1, 2), r(4, 5)}},
+		{[]IgnoredSeqNumRange{r(1, 2)}, r(3, 5), []IgnoredSeqNumRange{r(1, 5)}},
+		{[]IgnoredSeqNumRange{r(1, 2), r(4, 5), r(8, 9)}, r(3, 6), []IgnoredSeqNumRange{r(1, 6), r(8, 9)}},
+		{[]IgnoredSeqNumRange{r(1, 2), r(4, 5), r(8, 9)}, r(0, 10), []IgnoredSeqNumRange{r(0, 10)}},
+		{[]IgnoredSeqNumRange{r(1, 10)}, r(3, 4), []IgnoredSeqNumRange{r(1, 10)}},
+	}
+	for _, tc := range testData {
+		txn := Transaction{IgnoredSeqNums: tc.list}
+		orig := append([]IgnoredSeqNumRange(nil), tc.list...)
+		txn.AddIgnoredSeqNumRange(tc.newRange)
+		if !reflect.DeepEqual(tc.exp, txn.IgnoredSeqNums) {
+			t.Errorf("adding %v to %v: expected %v, got %v", tc.newRange, tc.list, tc.exp, txn.IgnoredSeqNums)
+		}
+		if !reflect.DeepEqual(orig, tc.list) {
+			t.Errorf("adding %v modified the original list %v", tc.newRange, orig)
+		}
+	}
+
+	ignored := []IgnoredSeqNumRange{r(2, 3), r(6, 6)}
+	for seq, exp := range map[int32]bool{1: false, 2: true, 3: true, 4: false, 6: true, 7: false} {
+		if act := TxnSeqIsIgnored(seq, ignored); act != exp {
+			t.Errorf("seq %d: expected %t, got %t", seq, exp, act)
+		}
+	}
+}
+
 func TestTransactionClone(t *testing.T) {
 	txn := nonZeroTxn.Clone()
 
@@ -639,6 +639,8 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		),
 
 		QueryCache: querycache.New(s.cfg.SQLQueryCacheSize),
+
+		Notifications: sql.NewNotificationRegistry(s.gossip, &s.nodeIDContainer),
 	}
 
 	if sqlSchemaChangerTestingKnobs := s.cfg.TestingKnobs.SQLSchemaChanger; sqlSchemaChangerTestingKnobs != nil {
@@ -1575,6 +1577,15 @@ func (s *Server) Start(ctx context.Context) error {
 		return err
 	}
 
+	// Start the background thread for periodically dropping the temporary
+	// objects of sessions that disappeared without cleaning up.
+	sql.NewTemporaryObjectCleaner(
+		s.st,
+		s.db,
+		s.distSQLServer.ServerConfig.SessionBoundInternalExecutorFactory,
+		s.status,
+	).Start(ctx, s.stopper)
+
 	// Before serving SQL requests, we have to make sure the database is
 	// in an acceptable form for this version of the software.
 	// We have to do this after actually starting up the server to be able to
@@ -22,6 +22,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/sql/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachange"
@@ -202,6 +203,13 @@ func (n *alterTableNode) startExec(params runParams) error {
 					}
 					idx.Partitioning = partitioning
 				}
+				if d.Predicate != nil {
+					idx.Predicate, err = makeIndexPredicate(params.ctx, n.tableDesc, d.Predicate,
+						n.n.Table, &params.p.semaCtx, params.EvalContext())
+					if err != nil {
+						return err
+					}
+				}
 				_, dropped, err := n.tableDesc.FindIndexByName(string(d.Name))
 				if err == nil {
 					if dropped {
@@ -287,6 +295,9 @@ func (n *alterTableNode) startExec(params runParams) error {
 			if dropped {
 				continue
 			}
+			if err := n.tableDesc.CheckColumnNotBeingSwapped(&col); err != nil {
+				return err
+			}
 
 			// If the dropped column uses a sequence, remove references to it from that sequence.
 			if len(col.UsesSequenceIds) > 0 {
@@ -380,6 +391,17 @@ func (n *alterTableNode) startExec(params runParams) error {
 						containsThisColumn = true
 					}
 				}
+				// A column referenced by the predicate of a partial index is
+				// treated like a stored column.
+				predCols, err := idx.PredicateColumnIDs(n.tableDesc.TableDesc())
+				if err != nil {
+					return err
+				}
+				for _, id := range predCols {
+					if id == col.ID {
+						containsThisColumn = true
+					}
+				}
 
 				// Perform the DROP.
 				if containsThisColumn {
@@ -543,6 +565,9 @@ func (n *alterTableNode) startExec(params runParams) error {
 			if dropped {
 				return fmt.Errorf("column %q in the middle of being dropped", t.GetColumn())
 			}
+			if err := n.tableDesc.CheckColumnNotBeingSwapped(&col); err != nil {
+				return err
+			}
 			if err := applyColumnMutation(n.tableDesc, &col, t, params); err != nil {
 				return err
 			}
@@ -700,34 +725,36 @@ func applyColumnMutation(
 			return err
 		}
 
-		// No-op if the types are Equal.  We don't use Equivalent here
-		// because the user may want to change the visible type of the
-		// column without changing the underlying semantic type.
-		if col.Type.Equal(nextType) {
-			return nil
-		}
+		if t.Using == nil {
+			// No-op if the types are Equal.  We don't use Equivalent here
+			// because the user may want to change the visible type of the
+			// column without changing the underlying semantic type.
+			if col.Type.Equal(nextType) {
+				return nil
+			}
 
-		kind, err := schemachange.ClassifyConversion(&col.Type, &nextType)
-		if err != nil {
-			return err
-		}
+			kind, err := schemachange.ClassifyConversion(&col.Type, &nextType)
+			if err != nil {
+				return err
+			}
 
-		switch kind {
-		case schemachange.ColumnConversionDangerous, schemachange.ColumnConversionImpossible:
-			// We're not going to make it impossible for the user to perform
-			// this conversion, but we do want them to explicit about
-			// what they're going for.
-			return pgerror.NewErrorf(pgerror.CodeCannotCoerceError,
-				"the requested type conversion (%s -> %s) requires an explicit USING expression",
-				col.Type.SQLString(), nextType.SQLString())
-		case schemachange.ColumnConversionTrivial:
-			col.Type = nextType
-		default:
-			return pgerror.UnimplementedWithIssueDetailError(9851,
-				fmt.Sprintf("%s->%s", col.Type.SQLString(), nextType.SQLString()),
-				"type conversion not yet implemented")
+			switch kind {
+			case schemachange.ColumnConversionDangerous, schemachange.ColumnConversionImpossible:
+				// We're not going to make it impossible for the user to perform
+				// this conversion, but we do want them to explicit about
+				// what they're going for.
+				return pgerror.NewErrorf(pgerror.CodeCannotCoerceError,
+					"the requested type conversion (%s -> %s) requires an explicit USING expression",
+					col.Type.SQLString(), nextType.SQLString())
+			case schemachange.ColumnConversionTrivial:
+				col.Type = nextType
+				return nil
+			}
 		}
 
+		// The existing data needs to be validated or rewritten.
+		return alterColumnTypeGeneral(tableDesc, col, nextType, t, params)
+
 	case *tree.AlterTableSetDefault:
 		if len(col.UsesSequenceIds) > 0 {
 			if err := removeSequenceDependencies(tableDesc, col, params); err != nil {
@@ -759,7 +786,44 @@ func applyColumnMutation(
 			}
 		}
 
+	case *tree.AlterTableSetNotNull:
+		if !col.Nullable {
+			return nil
+		}
+		if tableDesc.FindNotNullMutation(col.ID) != nil {
+			return pgerror.NewErrorf(pgerror.CodeObjectNotInPrerequisiteStateError,
+				"column %q in the middle of being made NOT NULL", col.Name)
+		}
+		info, err := tableDesc.GetConstraintInfo(params.ctx, nil)
+		if err != nil {
+			return err
+		}
+		inuseNames := make(map[string]struct{}, len(info))
+		for k := range info {
+			inuseNames[k] = struct{}{}
+		}
+		check := sqlbase.MakeNotNullCheckConstraint(
+			col.Name, col.ID, inuseNames, sqlbase.ConstraintValidity_Validating,
+		)
+		if tableDesc.IsNewTable() {
+			// The table is not visible to other transactions yet: the
+			// existing rows can be validated right away.
+			if err := params.p.validateNotNullCheck(params.ctx, check, tableDesc.TableDesc()); err != nil {
+				return err
+			}
+			col.Nullable = false
+			return nil
+		}
+		// The NOT NULL constraint is enforced on the writes right away through
+		// a hidden check constraint. The schema changer validates the existing
+		// rows before making the column NOT NULL.
+		tableDesc.AddNotNullMutation(check, sqlbase.DescriptorMutation_ADD)
+
 	case *tree.AlterTableDropNotNull:
+		if tableDesc.FindNotNullMutation(col.ID) != nil {
+			return pgerror.NewErrorf(pgerror.CodeObjectNotInPrerequisiteStateError,
+				"column %q in the middle of being made NOT NULL", col.Name)
+		}
 		col.Nullable = true
 
 	case *tree.AlterTableDropStored:
@@ -772,6 +836,300 @@ func applyColumnMutation(
 	return nil
 }
 
+// alterColumnTypeGeneral changes the type of a column whose data needs to be
+// validated or rewritten. A new column of the new type, computed from the old
+// column with the USING expression or a cast, is added along with copies of
+// the indexes on the old column. Writes maintain the new column through its
+// computed expression while it is backfilled. The schema changer then swaps
+// the columns and drops the old column and indexes, computing the old column
+// from the new one until it is dropped.
+func alterColumnTypeGeneral(
+	tableDesc *sqlbase.MutableTableDescriptor,
+	col *sqlbase.ColumnDescriptor,
+	toType sqlbase.ColumnType,
+	t *tree.AlterTableAlterColumnType,
+	params runParams,
+) error {
+	unsupported := func(reason string) error {
+		return pgerror.UnimplementedWithIssueDetailError(9851,
+			fmt.Sprintf("%s->%s", col.Type.SQLString(), toType.SQLString()),
+			"cannot change the type of a column "+reason)
+	}
+	if tableDesc.IsNewTable() {
+		return unsupported("of a table created in the same transaction")
+	}
+	if tableDesc.PrimaryIndex.ContainsColumnID(col.ID) {
+		return unsupported("in the primary key")
+	}
+	if col.IsComputed() {
+		return unsupported("that is computed")
+	}
+	if len(col.UsesSequenceIds) > 0 {
+		return unsupported("whose default uses a sequence")
+	}
+	if tableDesc.FindNotNullMutation(col.ID) != nil {
+		return pgerror.NewErrorf(pgerror.CodeObjectNotInPrerequisiteStateError,
+			"column %q in the middle of being made NOT NULL", col.Name)
+	}
+	for _, ref := range tableDesc.DependedOnBy {
+		for _, colID := range ref.ColumnIDs {
+			if colID == col.ID {
+				return unsupported("used by a view")
+			}
+		}
+	}
+	for _, ck := range tableDesc.Checks {
+		for _, colID := range ck.ColumnIDs {
+			if colID == col.ID {
+				return unsupported("used in a CHECK constraint")
+			}
+		}
+	}
+
+	// usesColumn returns whether the expression refers to the column.
+	usesColumn := func(expr string) (bool, error) {
+		parsed, err := parser.ParseExpr(expr)
+		if err != nil {
+			return false, err
+		}
+		found := false
+		err = iterColDescriptorsInExpr(tableDesc, parsed, func(c sqlbase.ColumnDescriptor) error {
+			found = found || c.ID == col.ID
+			return nil
+		})
+		return found, err
+	}
+	for i := range tableDesc.Columns {
+		if c := &tableDesc.Columns[i]; c.IsComputed() {
+			if found, err := usesColumn(*c.ComputeExpr); err != nil {
+				return err
+			} else if found {
+				return unsupported("used by a computed column")
+			}
+		}
+	}
+
+	// Find the indexes that need to be rebuilt on the new column.
+	var oldIndexes []sqlbase.IndexDescriptor
+	for _, idx := range tableDesc.Indexes {
+		if idx.IsPartial() {
+			if found, err := usesColumn(*idx.Predicate); err != nil {
+				return err
+			} else if found {
+				return unsupported("used in the predicate of a partial index")
+			}
+		}
+		if !idx.ContainsColumnID(col.ID) {
+			continue
+		}
+		if idx.ForeignKey.IsSet() || len(idx.ReferencedBy) > 0 {
+			return unsupported("used in a foreign key")
+		}
+		if idx.IsInterleaved() || len(idx.InterleavedBy) > 0 || idx.Partitioning.NumColumns > 0 {
+			return unsupported("in an interleaved or partitioned index")
+		}
+		oldIndexes = append(oldIndexes, idx)
+	}
+	for _, m := range tableDesc.Mutations {
+		if idx := m.GetIndex(); idx != nil && m.Direction == sqlbase.DescriptorMutation_ADD {
+			for _, names := range [][]string{idx.ColumnNames, idx.StoreColumnNames} {
+				for _, name := range names {
+					if name == col.Name {
+						return pgerror.NewErrorf(pgerror.CodeObjectNotInPrerequisiteStateError,
+							"column %q in the middle of being indexed", col.Name)
+					}
+				}
+			}
+		}
+	}
+
+	// The new column is computed from the old one.
+	var expr tree.Expr
+	if t.Using != nil {
+		expr = t.Using
+		if err := iterColDescriptorsInExpr(tableDesc, expr, func(c sqlbase.ColumnDescriptor) error {
+			if c.IsComputed() {
+				return pgerror.NewError(pgerror.CodeInvalidColumnReferenceError,
+					"USING expression cannot reference computed columns")
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	} else if col.Type.ToDatumType().Equivalent(toType.ToDatumType()) {
+		// Only the width or precision of the type changes. The values are
+		// not cast, so as to be validated rather than truncated.
+		expr = &tree.ColumnItem{ColumnName: tree.Name(col.Name)}
+	} else {
+		expr = &tree.CastExpr{
+			Expr:       &tree.ColumnItem{ColumnName: tree.Name(col.Name)},
+			Type:       t.ToType,
+			SyntaxMode: tree.CastShort,
+		}
+		if t.Collation != "" {
+			expr = &tree.CollateExpr{Expr: expr, Locale: t.Collation}
+		}
+	}
+	replacedExpr, _, err := replaceVars(tableDesc, expr)
+	if err != nil {
+		return err
+	}
+	if _, err := sqlbase.SanitizeVarFreeExpr(
+		replacedExpr, toType.ToDatumType(), "USING expression", &params.p.semaCtx,
+		params.EvalContext(), false, /* allowImpure */
+	); err != nil {
+		return pgerror.Wrap(err, pgerror.CodeDatatypeMismatchError, fmt.Sprintf(
+			"result of USING clause for column %q cannot be cast automatically to type %s",
+			col.Name, toType.SQLString()))
+	}
+	sources := sqlbase.MultiSourceInfo{sqlbase.NewSourceInfoForSingleTable(
+		tree.MakeUnqualifiedTableName(tree.Name(tableDesc.Name)),
+		sqlbase.ResultColumnsFromColDescs(tableDesc.Columns),
+	)}
+	expr, err = dequalifyColumnRefs(params.ctx, sources, expr)
+	if err != nil {
+		return err
+	}
+	computeExpr := tree.Serialize(expr)
+
+	// After the swap, the old column is computed from the new one with a cast
+	// back to its type until it is dropped, as the nodes that have yet to see
+	// the swap still read it. Values that do not fit the old type are
+	// truncated or rejected by the cast.
+	oldComputeExpr, err := parser.ParseExpr(fmt.Sprintf("%s::%s",
+		tree.NameString(col.Name), col.Type.SQLString()))
+	if err != nil {
+		return err
+	}
+	typedOldComputeExpr, err := tree.SimpleVisit(oldComputeExpr,
+		func(expr tree.Expr) (err error, recurse bool, newExpr tree.Expr) {
+			if _, ok := expr.(tree.VarName); ok {
+				return nil, false, &dummyColumnItem{typ: toType.ToDatumType(), name: tree.Name(col.Name)}
+			}
+			return nil, true, expr
+		})
+	if err != nil {
+		return err
+	}
+	if _, err := sqlbase.SanitizeVarFreeExpr(
+		typedOldComputeExpr, col.Type.ToDatumType(), "computed column", &params.p.semaCtx,
+		params.EvalContext(), false, /* allowImpure */
+	); err != nil {
+		return unsupported("whose new type cannot be cast back to its old type")
+	}
+	oldComputeExprStr := tree.Serialize(oldComputeExpr)
+
+	// The default expression of the column is converted to the new type.
+	var defaultExpr *string
+	if col.DefaultExpr != nil {
+		parsed, err := parser.ParseExpr(*col.DefaultExpr)
+		if err != nil {
+			return err
+		}
+		typedExpr, err := sqlbase.SanitizeVarFreeExpr(
+			&tree.CastExpr{Expr: parsed, Type: t.ToType, SyntaxMode: tree.CastShort},
+			toType.ToDatumType(), "DEFAULT", &params.p.semaCtx, params.EvalContext(), true, /* allowImpure */
+		)
+		if err != nil {
+			return pgerror.Wrap(err, pgerror.CodeDatatypeMismatchError, fmt.Sprintf(
+				"default for column %q cannot be cast automatically to type %s",
+				col.Name, toType.SQLString()))
+		}
+		s := tree.Serialize(typedExpr)
+		defaultExpr = &s
+	}
+
+	newCol := sqlbase.ColumnDescriptor{
+		Name:        makeUniqueColumnName(tableDesc, col.Name+"_new"),
+		Type:        toType,
+		Nullable:    col.Nullable,
+		Hidden:      col.Hidden,
+		ComputeExpr: &computeExpr,
+	}
+	tableDesc.AddColumnMutation(newCol, sqlbase.DescriptorMutation_ADD)
+	newColMutation := len(tableDesc.Mutations) - 1
+	for _, family := range tableDesc.Families {
+		for _, colID := range family.ColumnIDs {
+			if colID == col.ID {
+				if err := tableDesc.AddColumnToFamilyMaybeCreate(
+					newCol.Name, family.Name, false /* create */, false, /* ifNotExists */
+				); err != nil {
+					return err
+				}
+				break
+			}
+		}
+	}
+
+	// The indexes are copied with the new column in place of the old one.
+	for i := range oldIndexes {
+		idx := protoutil.Clone(&oldIndexes[i]).(*sqlbase.IndexDescriptor)
+		idx.ID = 0
+		idx.Name = makeUniqueIndexName(tableDesc, idx.Name+"_new")
+		idx.ColumnIDs = nil
+		idx.ExtraColumnIDs = nil
+		idx.StoreColumnIDs = nil
+		idx.CompositeColumnIDs = nil
+		for j := range idx.ColumnNames {
+			if idx.ColumnNames[j] == col.Name {
+				idx.ColumnNames[j] = newCol.Name
+			}
+		}
+		for j := range idx.StoreColumnNames {
+			if idx.StoreColumnNames[j] == col.Name {
+				idx.StoreColumnNames[j] = newCol.Name
+			}
+		}
+		if err := tableDesc.AddIndexMutation(idx, sqlbase.DescriptorMutation_ADD); err != nil {
+			return err
+		}
+	}
+
+	// Allocate the IDs of the new column and indexes, which are referenced by
+	// the swap.
+	if err := tableDesc.AllocateIDs(); err != nil {
+		return err
+	}
+	swap := &sqlbase.ComputedColumnSwap{
+		NewColumnID:    tableDesc.Mutations[newColMutation].GetColumn().ID,
+		OldColumnID:    col.ID,
+		NewDefaultExpr: defaultExpr,
+		OldComputeExpr: &oldComputeExprStr,
+	}
+	for i := range oldIndexes {
+		swap.NewIndexIDs = append(swap.NewIndexIDs, tableDesc.Mutations[newColMutation+1+i].GetIndex().ID)
+		swap.OldIndexIDs = append(swap.OldIndexIDs, oldIndexes[i].ID)
+	}
+	tableDesc.AddComputedColumnSwapMutation(swap)
+	return nil
+}
+
+// makeUniqueColumnName returns name, followed by a number if necessary, so
+// that it is not used by a column of the table, including the columns being
+// added or dropped.
+func makeUniqueColumnName(tableDesc *sqlbase.MutableTableDescriptor, name string) string {
+	candidate := name
+	for i := 1; ; i++ {
+		if _, _, err := tableDesc.FindColumnByName(tree.Name(candidate)); err != nil {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s%d", name, i)
+	}
+}
+
+// makeUniqueIndexName returns name, followed by a number if necessary, so
+// that it is not used by an index of the table, including the indexes being
+// added or dropped.
+func makeUniqueIndexName(tableDesc *sqlbase.MutableTableDescriptor, name string) string {
+	candidate := name
+	for i := 1; ; i++ {
+		if _, _, err := tableDesc.FindIndexByName(candidate); err != nil {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s%d", name, i)
+	}
+}
+
 func labeledRowValues(cols []sqlbase.ColumnDescriptor, values tree.Datums) string {
 	var s bytes.Buffer
 	for i := range cols {
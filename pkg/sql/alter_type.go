@@ -0,0 +1,146 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+type alterTypeAddValueNode struct {
+	n    *tree.AlterTypeAddValue
+	desc *sqlbase.TypeDescriptor
+}
+
+// AlterTypeAddValue adds a value to a user-defined ENUM type.
+// Privileges: CREATE on type.
+//   Notes: postgres requires ownership of the type.
+func (p *planner) AlterTypeAddValue(
+	ctx context.Context, n *tree.AlterTypeAddValue,
+) (planNode, error) {
+	desc, err := p.resolveTypeDesc(ctx, &n.Name, true /* required */)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.CheckPrivilege(ctx, desc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	return &alterTypeAddValueNode{n: n, desc: desc}, nil
+}
+
+func (n *alterTypeAddValueNode) startExec(params runParams) error {
+	p := params.p
+	ctx := params.ctx
+
+	if n.desc.HasEnumMember(n.n.NewVal) {
+		if n.n.IfNotExists {
+			return nil
+		}
+		return pgerror.NewErrorf(pgerror.CodeDuplicateObjectError,
+			"enum label %q already exists", n.n.NewVal)
+	}
+	var neighbor string
+	before := false
+	if n.n.Placement != nil {
+		neighbor = n.n.Placement.ExistingVal
+		before = n.n.Placement.Before
+	}
+	member, err := n.desc.AddEnumMember(n.n.NewVal, neighbor, before)
+	if err != nil {
+		return pgerror.NewError(pgerror.CodeInvalidParameterValueError, err.Error())
+	}
+	if err := n.desc.Validate(); err != nil {
+		return err
+	}
+
+	descKey := sqlbase.MakeDescMetadataKey(n.desc.ID)
+	b := &client.Batch{}
+	if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+		log.VEventf(ctx, 2, "Put %s -> %s", descKey, n.desc)
+	}
+	b.Put(descKey, sqlbase.WrapDescriptor(n.desc))
+	if err := p.txn.Run(ctx, b); err != nil {
+		return err
+	}
+
+	// The members of the type are copied into the columns that use it, so
+	// the descriptors of the tables with such columns are updated as well.
+	// The new member is read-only in these copies: the nodes that have yet to
+	// see the new version of a table could not decode values with it. The
+	// schema changer of the table makes it writable in a later version, once
+	// every node has this one.
+	tableIDs, err := getTablesUsingType(ctx, p.txn, n.desc.ID)
+	if err != nil {
+		return err
+	}
+	for _, id := range tableIDs {
+		tableDesc, err := p.Tables().getMutableTableVersionByID(ctx, id, p.txn)
+		if err != nil {
+			return err
+		}
+		updateColumnsOfType(tableDesc, n.desc.ID, func(col *sqlbase.ColumnDescriptor) {
+			col.Type.AddReadOnlyEnumMember(member)
+		})
+		if err := p.writeSchemaChange(ctx, tableDesc, sqlbase.InvalidMutationID); err != nil {
+			return err
+		}
+	}
+
+	// Log Alter Type event. This is an auditable log event and is
+	// recorded in the same transaction as the type descriptor update.
+	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+		ctx,
+		p.txn,
+		EventLogAlterType,
+		int32(n.desc.ID),
+		int32(params.extendedEvalCtx.NodeID),
+		struct {
+			TypeName  string
+			Statement string
+			User      string
+		}{n.n.Name.FQString(), n.n.String(), params.SessionData().User},
+	)
+}
+
+// updateColumnsOfType calls fn on the columns of the table, including the
+// columns being added or dropped, that have the given type.
+func updateColumnsOfType(
+	tableDesc *sqlbase.MutableTableDescriptor,
+	typeID sqlbase.ID,
+	fn func(col *sqlbase.ColumnDescriptor),
+) {
+	for i := range tableDesc.Columns {
+		if tableDesc.Columns[i].Type.EnumTypeID == typeID {
+			fn(&tableDesc.Columns[i])
+		}
+	}
+	for i := range tableDesc.Mutations {
+		if col := tableDesc.Mutations[i].GetColumn(); col != nil && col.Type.EnumTypeID == typeID {
+			fn(col)
+		}
+	}
+}
+
+func (*alterTypeAddValueNode) Next(runParams) (bool, error) { return false, nil }
+func (*alterTypeAddValueNode) Values() tree.Datums          { return tree.Datums{} }
+func (*alterTypeAddValueNode) Close(context.Context)        {}
@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/sql/backfill"
 	"github.com/cockroachdb/cockroach/pkg/sql/distsqlrun"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
@@ -55,6 +56,10 @@ const (
 	// many ranges.
 	indexBackfillChunkSize = 100
 
+	// materializedViewRefreshChunkSize is the maximum number of rows of a
+	// materialized view written per chunk during a refresh.
+	materializedViewRefreshChunkSize = 100
+
 	// checkpointInterval is the interval after which a checkpoint of the
 	// schema change is posted.
 	checkpointInterval = 2 * time.Minute
@@ -113,6 +118,8 @@ func (sc *SchemaChanger) runBackfill(
 	// mutations. Collect the elements that are part of the mutation.
 	var droppedIndexDescs []sqlbase.IndexDescriptor
 	var addedIndexDescs []sqlbase.IndexDescriptor
+	var constraintsToValidate []sqlbase.ConstraintToUpdate
+	var viewRefresh *sqlbase.MaterializedViewRefresh
 
 	var tableDesc *sqlbase.TableDescriptor
 	if err := sc.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
@@ -145,6 +152,13 @@ func (sc *SchemaChanger) runBackfill(
 				}
 			case *sqlbase.DescriptorMutation_Index:
 				addedIndexDescs = append(addedIndexDescs, *t.Index)
+			case *sqlbase.DescriptorMutation_Constraint:
+				constraintsToValidate = append(constraintsToValidate, *t.Constraint)
+			case *sqlbase.DescriptorMutation_ComputedColumnSwap:
+				// Nothing to do: the columns are swapped when the mutation
+				// completes, after the new column and indexes are backfilled.
+			case *sqlbase.DescriptorMutation_MaterializedViewRefresh:
+				viewRefresh = t.MaterializedViewRefresh
 			default:
 				return errors.Errorf("unsupported mutation: %+v", m)
 			}
@@ -157,6 +171,11 @@ func (sc *SchemaChanger) runBackfill(
 				if !sc.canClearRangeForDrop(t.Index) {
 					droppedIndexDescs = append(droppedIndexDescs, *t.Index)
 				}
+			case *sqlbase.DescriptorMutation_Constraint, *sqlbase.DescriptorMutation_ComputedColumnSwap,
+				*sqlbase.DescriptorMutation_MaterializedViewRefresh:
+				// Nothing to do: the constraint is removed from the table
+				// descriptor when the mutation completes, and a swap or a
+				// refresh is only dropped when it is rolled back.
 			default:
 				return errors.Errorf("unsupported mutation: %+v", m)
 			}
@@ -186,9 +205,59 @@ func (sc *SchemaChanger) runBackfill(
 		}
 	}
 
+	// Fill the new indexes of a materialized view.
+	if viewRefresh != nil {
+		if err := sc.refreshMaterializedView(ctx, lease, tableDesc, viewRefresh); err != nil {
+			return err
+		}
+	}
+
+	// Validate new constraints. They are enforced on the writes of all the
+	// nodes by now, so only the existing rows need to be validated.
+	if len(constraintsToValidate) > 0 {
+		if err := sc.validateConstraints(ctx, constraintsToValidate); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// validateConstraints validates the existing rows of the table against the
+// constraints being added. A validation failure is a permanent error that
+// causes the schema change to be rolled back.
+func (sc *SchemaChanger) validateConstraints(
+	ctx context.Context, constraints []sqlbase.ConstraintToUpdate,
+) error {
+	return sc.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		tableDesc, err := sqlbase.GetTableDescFromID(ctx, txn, sc.tableID)
+		if err != nil {
+			return err
+		}
+		p, cleanup := newInternalPlanner(
+			"validate constraints", txn, security.RootUser, &MemoryMetrics{}, sc.execCfg,
+		)
+		defer cleanup()
+		for _, c := range constraints {
+			switch c.ConstraintType {
+			case sqlbase.ConstraintToUpdate_NOT_NULL:
+				// Use the check constraint of the table descriptor rather than
+				// the copy in the mutation: the column may have been renamed
+				// since. If the column was dropped, the constraint is gone.
+				for _, ck := range tableDesc.Checks {
+					if ck.IsNonNullConstraint && ck.Name == c.Name {
+						if err := p.validateNotNullCheck(ctx, ck, tableDesc); err != nil {
+							return err
+						}
+						break
+					}
+				}
+			}
+		}
+		return nil
+	})
+}
+
 func (sc *SchemaChanger) getTableVersion(
 	ctx context.Context, txn *client.Txn, tc *TableCollection, version sqlbase.DescriptorVersion,
 ) (*sqlbase.ImmutableTableDescriptor, error) {
@@ -234,6 +234,13 @@ func (cb *ColumnBackfiller) RunColumnBackfillChunk(
 			if j < len(cb.added) && !cb.added[j].Nullable && val == tree.DNull {
 				return roachpb.Key{}, sqlbase.NewNonNullViolationError(cb.added[j].Name)
 			}
+			if j < len(cb.added) {
+				// Computed values have to fit in the type of the column, as
+				// they do for INSERT and UPDATE.
+				if val, err = sqlbase.LimitValueWidth(cb.added[j].Type, val, &cb.added[j].Name); err != nil {
+					return roachpb.Key{}, err
+				}
+			}
 
 			// Added computed column values should be usable for the next
 			// added columns being backfilled. They have already been type
@@ -297,6 +304,8 @@ type IndexBackfiller struct {
 	backfiller
 
 	added []sqlbase.IndexDescriptor
+	// partialIndexes evaluates the predicates of the added partial indexes.
+	partialIndexes sqlbase.PartialIndexPredicates
 	// colIdxMap maps ColumnIDs to indices into desc.Columns and desc.Mutations.
 	colIdxMap map[sqlbase.ColumnID]int
 
@@ -329,13 +338,25 @@ func (ib *IndexBackfiller) Init(desc *sqlbase.ImmutableTableDescriptor) error {
 		if IndexMutationFilter(m) {
 			idx := m.GetIndex()
 			ib.added = append(ib.added, *idx)
+			predCols, err := idx.PredicateColumnIDs(desc.TableDesc())
+			if err != nil {
+				return err
+			}
 			for i, col := range cols {
 				if idx.ContainsColumnID(col.ID) {
 					valNeededForCol.Add(i)
 				}
+				for _, colID := range predCols {
+					if colID == col.ID {
+						valNeededForCol.Add(i)
+					}
+				}
 			}
 		}
 	}
+	if err := ib.partialIndexes.Init(desc.TableDesc(), ib.added); err != nil {
+		return err
+	}
 
 	ib.types = make([]sqlbase.ColumnType, len(cols))
 	for i := range cols {
@@ -412,7 +433,23 @@ func (ib *IndexBackfiller) BuildIndexEntriesChunk(
 			ib.rowVals, buffer); err != nil {
 			return nil, nil, err
 		}
-		entries = append(entries, buffer...)
+		if !ib.partialIndexes.HasPartialIndexes() {
+			entries = append(entries, buffer...)
+			continue
+		}
+		// Skip the entries of the partial indexes that don't contain the row.
+		for j := range buffer {
+			if j < len(ib.added) {
+				inIndex, err := ib.partialIndexes.RowInIndex(j, ib.colIdxMap, ib.rowVals)
+				if err != nil {
+					return nil, nil, err
+				}
+				if !inIndex {
+					continue
+				}
+			}
+			entries = append(entries, buffer[j])
+		}
 	}
 	return entries, ib.fetcher.Key(), nil
 }
@@ -74,6 +74,21 @@ func (p *planner) validateCheckExpr(
 	return nil
 }
 
+// validateNotNullCheck validates the existing rows of the table against the
+// hidden check constraint of a column being made NOT NULL.
+func (p *planner) validateNotNullCheck(
+	ctx context.Context,
+	ck *sqlbase.TableDescriptor_CheckConstraint,
+	tableDesc *sqlbase.TableDescriptor,
+) error {
+	tableRef := &tree.TableRef{TableID: int64(tableDesc.ID)}
+	if err := p.validateCheckExpr(ctx, ck.Expr, tableRef, tableDesc); err != nil {
+		return pgerror.Wrap(err, pgerror.CodeNotNullViolationError,
+			"validation of NOT NULL constraint failed")
+	}
+	return nil
+}
+
 func (p *planner) validateForeignKey(
 	ctx context.Context, srcTable *sqlbase.TableDescriptor, srcIdx *sqlbase.IndexDescriptor,
 ) error {
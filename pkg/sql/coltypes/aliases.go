@@ -94,6 +94,11 @@ var (
 	// JSON is an immutable T instance.
 	JSON = &TJSON{}
 
+	// TSVector is an immutable T instance.
+	TSVector = &TTSVector{}
+	// TSQuery is an immutable T instance.
+	TSQuery = &TTSQuery{}
+
 	// Oid is an immutable T instance.
 	Oid = &TOid{Name: "OID"}
 	// RegClass is an immutable T instance.
@@ -141,6 +146,46 @@ func NewFloat(prec int64) (*TFloat, error) {
 	return nil, errFloatPrecMax54
 }
 
+// timePrecision validates the precision specified for a TIME, TIMESTAMP or
+// TIMESTAMPTZ type. Like in PostgreSQL, a precision larger than the maximum is
+// reduced to the maximum.
+func timePrecision(typName string, prec int64) (int, error) {
+	if prec < 0 {
+		return 0, pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+			"%s(%d) precision must not be negative", typName, prec)
+	}
+	if prec > MaxTimePrecision {
+		return MaxTimePrecision, nil
+	}
+	return int(prec), nil
+}
+
+// NewTime creates a TIME type with the given precision.
+func NewTime(prec int64) (*TTime, error) {
+	p, err := timePrecision("TIME", prec)
+	if err != nil {
+		return nil, err
+	}
+	return &TTime{PrecisionSet: true, Precision: p}, nil
+}
+
+// NewTimestamp creates a TIMESTAMP or TIMESTAMPTZ type with the given
+// precision.
+func NewTimestamp(prec int64, withTZ bool) (T, error) {
+	typName := "TIMESTAMP"
+	if withTZ {
+		typName = "TIMESTAMPTZ"
+	}
+	p, err := timePrecision(typName, prec)
+	if err != nil {
+		return nil, err
+	}
+	if withTZ {
+		return &TTimestampTZ{PrecisionSet: true, Precision: p}, nil
+	}
+	return &TTimestamp{PrecisionSet: true, Precision: p}, nil
+}
+
 // ArrayOf creates a type alias for an array of the given element type and fixed bounds.
 func ArrayOf(colType T, bounds []int32) (T, error) {
 	if !canBeInArrayColType(colType) {
@@ -195,8 +240,6 @@ var postgresPredefinedTypeIssues = map[string]int{
 	"pg_lsn":        -1,
 	"point":         21286,
 	"polygon":       21286,
-	"tsquery":       7821,
-	"tsvector":      7821,
 	"txid_snapshot": -1,
 	"xml":           -1,
 }
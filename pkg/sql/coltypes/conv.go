@@ -86,6 +86,10 @@ func DatumTypeToColumnType(t types.T) (T, error) {
 		return Interval, nil
 	case types.JSON:
 		return JSON, nil
+	case types.TSVector:
+		return TSVector, nil
+	case types.TSQuery:
+		return TSQuery, nil
 	case types.UUID:
 		return UUID, nil
 	case types.INet:
@@ -133,6 +137,8 @@ func DatumTypeToColumnType(t types.T) (T, error) {
 		return colTyp, nil
 	case types.TOidWrapper:
 		return DatumTypeToColumnType(typ.T)
+	case types.TEnum:
+		return &TUserDefined{Name: typ.Name, Resolved: typ}, nil
 	}
 
 	return nil, pgerror.NewErrorf(pgerror.CodeInvalidTableDefinitionError,
@@ -180,6 +186,10 @@ func CastTargetToDatumType(t CastTargetType) types.T {
 		return types.Interval
 	case *TJSON:
 		return types.JSON
+	case *TTSVector:
+		return types.TSVector
+	case *TTSQuery:
+		return types.TSQuery
 	case *TUUID:
 		return types.UUID
 	case *TIPAddr:
@@ -205,6 +215,13 @@ func CastTargetToDatumType(t CastTargetType) types.T {
 		return ret
 	case *TOid:
 		return TOidToType(ct)
+	case *TUserDefined:
+		if ct.Resolved == nil {
+			// The name has not been resolved yet; no cast to this type is
+			// valid until then.
+			return types.Any
+		}
+		return ct.Resolved
 	default:
 		panic(fmt.Sprintf("unexpected CastTarget %T", t))
 	}
@@ -73,10 +73,13 @@ func (*TName) columnType()           {}
 func (*TOid) columnType()            {}
 func (*TSerial) columnType()         {}
 func (*TString) columnType()         {}
+func (*TTSQuery) columnType()        {}
+func (*TTSVector) columnType()       {}
 func (*TTime) columnType()           {}
 func (*TTimestamp) columnType()      {}
 func (*TTimestampTZ) columnType()    {}
 func (*TUUID) columnType()           {}
+func (*TUserDefined) columnType()    {}
 func (*TVector) columnType()         {}
 func (TTuple) columnType()           {}
 
@@ -97,10 +100,13 @@ func (*TName) castTargetType()           {}
 func (*TOid) castTargetType()            {}
 func (*TSerial) castTargetType()         {}
 func (*TString) castTargetType()         {}
+func (*TTSQuery) castTargetType()        {}
+func (*TTSVector) castTargetType()       {}
 func (*TTime) castTargetType()           {}
 func (*TTimestamp) castTargetType()      {}
 func (*TTimestampTZ) castTargetType()    {}
 func (*TUUID) castTargetType()           {}
+func (*TUserDefined) castTargetType()    {}
 func (*TVector) castTargetType()         {}
 func (TTuple) castTargetType()           {}
 
@@ -120,9 +126,12 @@ func (node *TName) String() string           { return ColTypeAsString(node) }
 func (node *TOid) String() string            { return ColTypeAsString(node) }
 func (node *TSerial) String() string         { return ColTypeAsString(node) }
 func (node *TString) String() string         { return ColTypeAsString(node) }
+func (node *TTSQuery) String() string        { return ColTypeAsString(node) }
+func (node *TTSVector) String() string       { return ColTypeAsString(node) }
 func (node *TTime) String() string           { return ColTypeAsString(node) }
 func (node *TTimestamp) String() string      { return ColTypeAsString(node) }
 func (node *TTimestampTZ) String() string    { return ColTypeAsString(node) }
 func (node *TUUID) String() string           { return ColTypeAsString(node) }
+func (node *TUserDefined) String() string    { return ColTypeAsString(node) }
 func (node *TVector) String() string         { return ColTypeAsString(node) }
 func (node TTuple) String() string           { return ColTypeAsString(node) }
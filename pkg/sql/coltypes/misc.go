@@ -18,6 +18,7 @@ import (
 	"bytes"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
 )
 
 // This file contains column type definitions that don't fit
@@ -59,6 +60,28 @@ func (node *TJSON) Format(buf *bytes.Buffer, _ lex.EncodeFlags) {
 	buf.WriteString(node.TypeName())
 }
 
+// TTSVector represents the TSVECTOR type.
+type TTSVector struct{}
+
+// TypeName implements the ColTypeFormatter interface.
+func (node *TTSVector) TypeName() string { return "TSVECTOR" }
+
+// Format implements the ColTypeFormatter interface.
+func (node *TTSVector) Format(buf *bytes.Buffer, _ lex.EncodeFlags) {
+	buf.WriteString(node.TypeName())
+}
+
+// TTSQuery represents the TSQUERY type.
+type TTSQuery struct{}
+
+// TypeName implements the ColTypeFormatter interface.
+func (node *TTSQuery) TypeName() string { return "TSQUERY" }
+
+// Format implements the ColTypeFormatter interface.
+func (node *TTSQuery) Format(buf *bytes.Buffer, _ lex.EncodeFlags) {
+	buf.WriteString(node.TypeName())
+}
+
 // TOid represents an OID type, which is the type of system object
 // identifiers. There are several different OID types: the raw OID type, which
 // can be any integer, and the reg* types, each of which corresponds to the
@@ -77,3 +100,21 @@ func (node *TOid) TypeName() string { return node.Name }
 func (node *TOid) Format(buf *bytes.Buffer, f lex.EncodeFlags) {
 	buf.WriteString(node.Name)
 }
+
+// TUserDefined represents a user-defined type, such as an ENUM type, that
+// is referenced by name. The name is resolved to a type during type
+// checking.
+type TUserDefined struct {
+	Name string
+	// Resolved is the type the name refers to. It is nil until the name has
+	// been resolved.
+	Resolved types.T
+}
+
+// TypeName implements the ColTypeFormatter interface.
+func (node *TUserDefined) TypeName() string { return node.Name }
+
+// Format implements the ColTypeFormatter interface.
+func (node *TUserDefined) Format(buf *bytes.Buffer, f lex.EncodeFlags) {
+	lex.EncodeRestrictedSQLIdent(buf, node.Name, f)
+}
@@ -16,6 +16,7 @@ package coltypes
 
 import (
 	"bytes"
+	"fmt"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/lex"
 )
@@ -31,37 +32,73 @@ func (node *TDate) Format(buf *bytes.Buffer, f lex.EncodeFlags) {
 	buf.WriteString(node.TypeName())
 }
 
+// MaxTimePrecision is the maximum number of fractional digits of the seconds
+// field which can be specified for the TIME, TIMESTAMP and TIMESTAMPTZ types.
+// It is also the precision of the values of these types when none is
+// specified.
+const MaxTimePrecision = 6
+
+// formatTimeType formats the name of a TIME, TIMESTAMP or TIMESTAMPTZ type,
+// followed by its precision if it was specified.
+func formatTimeType(buf *bytes.Buffer, name string, precisionSet bool, precision int) {
+	buf.WriteString(name)
+	if precisionSet {
+		fmt.Fprintf(buf, "(%d)", precision)
+	}
+}
+
 // TTime represents a TIME type.
-type TTime struct{}
+type TTime struct {
+	// PrecisionSet is true if the precision was specified explicitly, as in
+	// TIME(3).
+	PrecisionSet bool
+	// Precision is the number of fractional digits of the seconds field
+	// retained by the type.
+	Precision int
+}
 
 // TypeName implements the ColTypeFormatter interface.
 func (node *TTime) TypeName() string { return "TIME" }
 
 // Format implements the ColTypeFormatter interface.
 func (node *TTime) Format(buf *bytes.Buffer, f lex.EncodeFlags) {
-	buf.WriteString(node.TypeName())
+	formatTimeType(buf, node.TypeName(), node.PrecisionSet, node.Precision)
 }
 
 // TTimestamp represents a TIMESTAMP type.
-type TTimestamp struct{}
+type TTimestamp struct {
+	// PrecisionSet is true if the precision was specified explicitly, as in
+	// TIMESTAMP(3).
+	PrecisionSet bool
+	// Precision is the number of fractional digits of the seconds field
+	// retained by the type.
+	Precision int
+}
 
 // TypeName implements the ColTypeFormatter interface.
 func (node *TTimestamp) TypeName() string { return "TIMESTAMP" }
 
 // Format implements the ColTypeFormatter interface.
 func (node *TTimestamp) Format(buf *bytes.Buffer, f lex.EncodeFlags) {
-	buf.WriteString(node.TypeName())
+	formatTimeType(buf, node.TypeName(), node.PrecisionSet, node.Precision)
 }
 
 // TTimestampTZ represents a TIMESTAMP type.
-type TTimestampTZ struct{}
+type TTimestampTZ struct {
+	// PrecisionSet is true if the precision was specified explicitly, as in
+	// TIMESTAMPTZ(3).
+	PrecisionSet bool
+	// Precision is the number of fractional digits of the seconds field
+	// retained by the type.
+	Precision int
+}
 
 // TypeName implements the ColTypeFormatter interface.
 func (node *TTimestampTZ) TypeName() string { return "TIMESTAMPTZ" }
 
 // Format implements the ColTypeFormatter interface.
 func (node *TTimestampTZ) Format(buf *bytes.Buffer, f lex.EncodeFlags) {
-	buf.WriteString(node.TypeName())
+	formatTimeType(buf, node.TypeName(), node.PrecisionSet, node.Precision)
 }
 
 // TInterval represents an INTERVAL type
@@ -74,3 +111,24 @@ func (node *TInterval) TypeName() string { return "INTERVAL" }
 func (node *TInterval) Format(buf *bytes.Buffer, f lex.EncodeFlags) {
 	buf.WriteString(node.TypeName())
 }
+
+// TimePrecision returns the precision of the given TIME, TIMESTAMP or
+// TIMESTAMPTZ type along with the same type without precision, if the
+// precision of the type was specified explicitly. ok is false otherwise.
+func TimePrecision(t CastTargetType) (base T, precision int, ok bool) {
+	switch t := t.(type) {
+	case *TTime:
+		if t.PrecisionSet {
+			return Time, t.Precision, true
+		}
+	case *TTimestamp:
+		if t.PrecisionSet {
+			return Timestamp, t.Precision, true
+		}
+	case *TTimestampTZ:
+		if t.PrecisionSet {
+			return TimestampWithTZ, t.Precision, true
+		}
+	}
+	return nil, 0, false
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/coltypes"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
@@ -413,6 +414,12 @@ func (h ConnectionHandler) GetStatusParam(ctx context.Context, varName string) s
 	return defVal
 }
 
+// Notifications returns the queue of the notifications received by the
+// session, which need to be delivered to the client.
+func (h ConnectionHandler) Notifications() *NotificationQueue {
+	return h.ex.extraTxnState.notifications.queue
+}
+
 // ServeConn serves a client connection by reading commands from
 // the stmtBuf embedded in the ConnHandler.
 func (s *Server) ServeConn(
@@ -568,6 +575,8 @@ func (s *Server) newConnExecutor(
 		prepStmts: make(map[string]*PreparedStatement),
 		portals:   make(map[string]*PreparedPortal),
 	}
+	ex.extraTxnState.cursors = make(cursorNamespace)
+	ex.extraTxnState.notifications = makeSessionNotifications(s.cfg.Notifications)
 	ex.extraTxnState.tables = TableCollection{
 		leaseMgr:          s.cfg.LeaseManager,
 		databaseCache:     s.dbCache.getDatabaseCache(),
@@ -732,6 +741,9 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 	_ = ex.synchronizeParallelStmts(ctx)
 
 	if closeType == normalClose {
+		// A KV txn kept open for savepoints in the Aborted state is not cleaned
+		// up by the event below.
+		ex.state.rollbackKeptKVTxn()
 		// We'll cleanup the SQL txn by creating a non-retriable (commit:true) event.
 		// This event is guaranteed to be accepted in every state.
 		ev := eventNonRetriableErr{IsCommit: fsm.FromBool(true)}
@@ -751,8 +763,17 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 		// Close all statements and prepared portals.
 		ex.extraTxnState.prepStmtsNamespace.resetTo(ctx, prepStmtNamespace{})
 		ex.extraTxnState.prepStmtsNamespaceAtTxnRewindPos.resetTo(ctx, prepStmtNamespace{})
+
+		// Close all cursors.
+		ex.extraTxnState.cursors.release(ctx)
+
+		// Drop the temporary objects of the session.
+		ex.cleanupTemporarySchema(ctx)
 	}
 
+	// Stop listening for notifications.
+	ex.extraTxnState.notifications.close()
+
 	if ex.sessionTracing.Enabled() {
 		if err := ex.sessionTracing.StopTracing(); err != nil {
 			log.Warningf(ctx, "error stopping tracing: %s", err)
@@ -837,6 +858,11 @@ type connExecutor struct {
 		// is done if the statement was executed in an implicit txn).
 		schemaChangers schemaChangerCollection
 
+		// deferredFKChecks accumulates the checks of the deferred foreign key
+		// constraints. The checks are run when the transaction commits, or when
+		// SET CONSTRAINTS ALL IMMEDIATE is executed.
+		deferredFKChecks row.DeferredFKChecks
+
 		// autoRetryCounter keeps track of the which iteration of a transaction
 		// auto-retry we're currently in. It's 0 whenever the transaction state is not
 		// stateOpen.
@@ -870,6 +896,19 @@ type connExecutor struct {
 		// txnRewindPos is advanced. Prepared statements are shared between the two
 		// collections, but these collections are periodically reconciled.
 		prepStmtsNamespaceAtTxnRewindPos prepStmtNamespace
+
+		// cursors contains the SQL cursors of the session. Like portals, cursors
+		// are bound to the transaction that declares them, except for WITH HOLD
+		// cursors which survive until the end of the session. Changes made to
+		// cursors are undone when a transaction aborts or is retried
+		// automatically.
+		cursors cursorNamespace
+
+		// notifications contains the LISTEN/NOTIFY state of the session. The
+		// channels the session listens on outlive transactions, but the effects
+		// of LISTEN, UNLISTEN and NOTIFY are only applied when the transaction
+		// that runs them commits.
+		notifications sessionNotifications
 	}
 
 	// sessionData contains the user-configurable connection variables.
@@ -1015,6 +1054,8 @@ func (ex *connExecutor) resetExtraTxnState(
 ) error {
 	ex.extraTxnState.schemaChangers.reset()
 
+	ex.extraTxnState.deferredFKChecks.Reset()
+
 	ex.extraTxnState.tables.releaseTables(ctx)
 
 	ex.extraTxnState.tables.databaseCache = dbCacheHolder.getDatabaseCache()
@@ -1023,6 +1064,9 @@ func (ex *connExecutor) resetExtraTxnState(
 
 	// Close all portals.
 	for name, p := range ex.extraTxnState.prepStmtsNamespace.portals {
+		// The portal might be restored by a rewind, in which case it needs to
+		// run its query again.
+		p.resetSuspension(ctx)
 		p.decRef(ctx)
 		delete(ex.extraTxnState.prepStmtsNamespace.portals, name)
 	}
@@ -1122,6 +1166,7 @@ func (ex *connExecutor) run(
 	ex.onCancelSession = onCancel
 
 	ex.sessionID = ex.generateID()
+	ex.extraTxnState.notifications.pid = backendPID(ex.sessionID)
 	ex.server.cfg.SessionRegistry.register(ex.sessionID, ex)
 	defer ex.server.cfg.SessionRegistry.deregister(ex.sessionID)
 
@@ -1221,14 +1266,33 @@ func (ex *connExecutor) run(
 				ex.sessionData.DataConversion)
 			stmtRes.SetLimit(tcmd.Limit)
 			res = stmtRes
+			if _, inOpen := ex.machine.CurState().(stateOpen); inOpen && portal.suspended != nil {
+				// The portal was suspended by a previous execution; return the rows
+				// that come next.
+				ev, payload, err = ex.resumePortal(ex.Ctx(), portal.suspended, stmtRes, tcmd.Limit)
+				if err != nil {
+					return err
+				}
+				break
+			}
 			curStmt := Statement{
 				Statement:     portal.Stmt.Statement,
 				Prepared:      portal.Stmt,
 				ExpectedTypes: portal.Stmt.Columns,
 				AnonymizedStr: portal.Stmt.AnonymizedStr,
 			}
+			var execRes RestrictedCommandResult = stmtRes
+			var limitedRes *limitedCommandResult
+			if ex.canSuspendPortal(portal, tcmd.Limit) {
+				limitedRes = &limitedCommandResult{CommandResult: stmtRes, ex: ex, limit: tcmd.Limit}
+				execRes = limitedRes
+			}
 			ctx := withStatement(ex.Ctx(), ex.curStmt)
-			ev, payload, err = ex.execStmt(ctx, curStmt, stmtRes, pinfo)
+			ev, payload, err = ex.execStmt(ctx, curStmt, execRes, pinfo)
+			if limitedRes != nil {
+				_, failed := payload.(payloadWithError)
+				limitedRes.suspend(ctx, portal, err != nil || failed)
+			}
 			if err != nil {
 				return err
 			}
@@ -1818,17 +1882,19 @@ func (ex *connExecutor) evalCtx(
 			ReCache:          ex.server.reCache,
 			InternalExecutor: &ie,
 		},
-		SessionMutator:  &ex.dataMutator,
-		VirtualSchemas:  ex.server.cfg.VirtualSchemas,
-		Tracing:         &ex.sessionTracing,
-		StatusServer:    ex.server.cfg.StatusServer,
-		MemMetrics:      &ex.memMetrics,
-		Tables:          &ex.extraTxnState.tables,
-		ExecCfg:         ex.server.cfg,
-		DistSQLPlanner:  ex.server.cfg.DistSQLPlanner,
-		TxnModesSetter:  ex,
-		SchemaChangers:  &ex.extraTxnState.schemaChangers,
-		schemaAccessors: scInterface,
+		SessionMutator:   &ex.dataMutator,
+		SessionID:        ex.sessionID,
+		VirtualSchemas:   ex.server.cfg.VirtualSchemas,
+		Tracing:          &ex.sessionTracing,
+		StatusServer:     ex.server.cfg.StatusServer,
+		MemMetrics:       &ex.memMetrics,
+		Tables:           &ex.extraTxnState.tables,
+		ExecCfg:          ex.server.cfg,
+		DistSQLPlanner:   ex.server.cfg.DistSQLPlanner,
+		TxnModesSetter:   ex,
+		SchemaChangers:   &ex.extraTxnState.schemaChangers,
+		DeferredFKChecks: &ex.extraTxnState.deferredFKChecks,
+		schemaAccessors:  scInterface,
 	}
 }
 
@@ -1883,6 +1949,8 @@ func (ex *connExecutor) resetPlanner(
 	p.semaCtx = tree.MakeSemaContext(ex.sessionData.User == security.RootUser)
 	p.semaCtx.Location = &ex.sessionData.DataConversion.Location
 	p.semaCtx.SearchPath = ex.sessionData.SearchPath
+	p.semaCtx.TypeResolver = p
+	p.semaCtx.FunctionResolver = p
 	p.semaCtx.AsOfTimestamp = nil
 
 	p.extendedEvalCtx = ex.evalCtx(ctx, p, stmtTS)
@@ -1892,6 +1960,8 @@ func (ex *connExecutor) resetPlanner(
 
 	p.sessionDataMutator = &ex.dataMutator
 	p.preparedStatements = ex.getPrepStmtsAccessor()
+	p.sqlCursors = connExCursorsAccessor{ex: ex}
+	p.notifications = &ex.extraTxnState.notifications
 	p.autoCommit = false
 	p.isPreparing = false
 	p.avoidCachedDescriptors = false
@@ -1973,8 +2043,17 @@ func (ex *connExecutor) txnStateTransitionsApplyWrapper(
 		// Wait for the cache to reflect the dropped databases if any.
 		ex.extraTxnState.tables.waitForCacheToDropDatabases(ex.Ctx())
 
+		ex.extraTxnState.cursors.commitTxn(ex.Ctx())
+		ex.extraTxnState.notifications.commitTxn(ex.Ctx())
+
 		fallthrough
 	case txnRestart, txnAborted:
+		// Savepoints do not survive a restart of the KV txn.
+		ex.state.savepoints = nil
+		if advInfo.txnEvent != txnCommit {
+			ex.extraTxnState.cursors.abortTxn(ex.Ctx())
+			ex.extraTxnState.notifications.abortTxn()
+		}
 		if err := ex.resetExtraTxnState(ex.Ctx(), ex.server.dbCache); err != nil {
 			return advanceInfo{}, err
 		}
@@ -2211,6 +2290,41 @@ func (ps connExPrepStmtsAccessor) DeleteAll(ctx context.Context) {
 	ps.ex.extraTxnState.prepStmtsNamespace.resetTo(ctx, prepStmtNamespace{})
 }
 
+// connExCursorsAccessor is an implementation of sqlCursorsAccessor that gives
+// access to a connExecutor's SQL cursors.
+type connExCursorsAccessor struct {
+	ex *connExecutor
+}
+
+var _ sqlCursorsAccessor = connExCursorsAccessor{}
+
+// Get is part of the sqlCursorsAccessor interface.
+func (cs connExCursorsAccessor) Get(name string) *sqlCursor {
+	return cs.ex.extraTxnState.cursors.get(name)
+}
+
+// Add is part of the sqlCursorsAccessor interface.
+func (cs connExCursorsAccessor) Add(ctx context.Context, c *sqlCursor) {
+	cs.ex.extraTxnState.cursors.add(ctx, c)
+}
+
+// Close is part of the sqlCursorsAccessor interface.
+func (cs connExCursorsAccessor) Close(ctx context.Context, name string) bool {
+	return cs.ex.extraTxnState.cursors.close(ctx, name)
+}
+
+// CloseAll is part of the sqlCursorsAccessor interface.
+func (cs connExCursorsAccessor) CloseAll(ctx context.Context) {
+	cs.ex.extraTxnState.cursors.closeAll(ctx)
+}
+
+// NewRows is part of the sqlCursorsAccessor interface.
+func (cs connExCursorsAccessor) NewRows(
+	ctx context.Context, cols sqlbase.ResultColumns,
+) (*cursorRows, error) {
+	return cs.ex.newCursorRows(ctx, cols)
+}
+
 // contextStatementKey is an empty type for the handle associated with the
 // statement value (see context.Value).
 type contextStatementKey struct{}
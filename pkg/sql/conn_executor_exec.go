@@ -40,7 +40,8 @@ import (
 	"github.com/pkg/errors"
 )
 
-// RestartSavepointName is the only savepoint ident that we accept.
+// RestartSavepointName is the name of the savepoint that marks the beginning
+// of a transaction that the client is prepared to retry. See savepoints.go.
 const RestartSavepointName string = "cockroach_restart"
 
 var errSavepointNotUsed = pgerror.NewErrorf(
@@ -222,6 +223,10 @@ func (ex *connExecutor) execStmtInOpenState(
 		return ev, payload, nil
 
 	case *tree.ReleaseSavepoint:
+		if idx := ex.findSavepoint(s.Savepoint); idx >= 0 {
+			ex.execReleaseSavepointInOpenState(idx)
+			return nil, nil, nil
+		}
 		if err := ex.validateSavepointName(s.Savepoint); err != nil {
 			return makeErrEvent(err)
 		}
@@ -240,6 +245,12 @@ func (ex *connExecutor) execStmtInOpenState(
 		return ev, payload, nil
 
 	case *tree.Savepoint:
+		if !ex.isRestartSavepoint(s.Name) {
+			if err := ex.execSavepointInOpenState(ctx, s.Name); err != nil {
+				return makeErrEvent(err)
+			}
+			return nil, nil, nil
+		}
 		// Ensure that the user isn't trying to run BEGIN; SAVEPOINT; SAVEPOINT;
 		if ex.state.activeSavepointName != "" {
 			err := fmt.Errorf("SAVEPOINT may not be nested")
@@ -256,7 +267,7 @@ func (ex *connExecutor) execStmtInOpenState(
 		// See also:
 		// https://github.com/cockroachdb/cockroach/issues/15012
 		meta := ex.state.mu.txn.GetTxnCoordMeta(ctx)
-		if meta.CommandCount > 0 {
+		if meta.CommandCount > 0 || len(ex.state.savepoints) > 0 {
 			err := fmt.Errorf("SAVEPOINT %s needs to be the first statement in a "+
 				"transaction", RestartSavepointName)
 			return makeErrEvent(err)
@@ -267,6 +278,12 @@ func (ex *connExecutor) execStmtInOpenState(
 		return eventRetryIntentSet{}, nil /* payload */, nil
 
 	case *tree.RollbackToSavepoint:
+		if idx := ex.findSavepoint(s.Savepoint); idx >= 0 {
+			if err := ex.rollbackToSavepoint(ctx, idx); err != nil {
+				return makeErrEvent(err)
+			}
+			return nil, nil, nil
+		}
 		if err := ex.validateSavepointName(s.Savepoint); err != nil {
 			return makeErrEvent(err)
 		}
@@ -353,6 +370,10 @@ func (ex *connExecutor) execStmtInOpenState(
 	// For regular statements (the ones that get to this point), we don't return
 	// any event unless an an error happens.
 
+	if stmt.AST.StatementType() == tree.DDL {
+		ex.state.numDDL++
+	}
+
 	var p *planner
 	stmtTS := ex.server.cfg.Clock.PhysicalTime()
 	// Only run statements asynchronously through the parallelize queue if the
@@ -608,6 +629,12 @@ func (ex *connExecutor) commitSQLTransaction(
 		isRelease = true
 	}
 
+	// Run the checks of the deferred foreign key constraints before the
+	// transaction commits.
+	if err := ex.extraTxnState.deferredFKChecks.Run(ctx, ex.state.mu.txn); err != nil {
+		return ex.makeErrEvent(err, stmt)
+	}
+
 	if err := ex.checkTableTwoVersionInvariant(ctx); err != nil {
 		return ex.makeErrEvent(err, stmt)
 	}
@@ -1160,6 +1187,8 @@ func (ex *connExecutor) execStmtInNoTxnState(
 // - COMMIT / ROLLBACK: aborts the current transaction.
 // - ROLLBACK TO SAVEPOINT / SAVEPOINT: reopens the current transaction,
 //   allowing it to be retried.
+// - ROLLBACK TO a regular savepoint: rolls back the writes performed since
+//   the savepoint and resumes the current transaction.
 func (ex *connExecutor) execStmtInAbortedState(
 	ctx context.Context, stmt Statement, res RestrictedCommandResult,
 ) (fsm.Event, fsm.EventPayload) {
@@ -1179,6 +1208,27 @@ func (ex *connExecutor) execStmtInAbortedState(
 
 		return eventTxnFinish{}, eventTxnFinishPayload{commit: false}
 	case *tree.RollbackToSavepoint, *tree.Savepoint:
+		switch n := s.(type) {
+		case *tree.RollbackToSavepoint:
+			if idx := ex.findSavepoint(n.Savepoint); idx >= 0 && !inRestartWait {
+				if err := ex.rollbackToSavepoint(ctx, idx); err != nil {
+					ev := eventNonRetriableErr{IsCommit: fsm.False}
+					payload := eventNonRetriableErrPayload{
+						err: err,
+					}
+					return ev, payload
+				}
+				return eventSavepointRollback{}, nil
+			}
+		case *tree.Savepoint:
+			if !ex.isRestartSavepoint(n.Name) {
+				ev := eventNonRetriableErr{IsCommit: fsm.False}
+				payload := eventNonRetriableErrPayload{
+					err: sqlbase.NewTransactionAbortedError("" /* customMsg */),
+				}
+				return ev, payload
+			}
+		}
 		// We accept both the "ROLLBACK TO SAVEPOINT cockroach_restart" and the
 		// "SAVEPOINT cockroach_restart" commands to indicate client intent to
 		// retry a transaction in a RestartWait state.
@@ -1469,20 +1519,22 @@ func (ex *connExecutor) incrementStmtCounter(stmt Statement) {
 // matches the active savepoint name, begins with RestartSavepointName,
 // or that force_savepoint_restart==true. We accept everything with the
 // desired prefix because at least the C++ libpqxx appends sequence
-// numbers to the savepoint name specified by the user.
+// numbers to the savepoint name specified by the user. It is only called
+// for names that do not refer to a regular savepoint, so any other name
+// refers to a savepoint that does not exist.
 func (ex *connExecutor) validateSavepointName(savepoint tree.Name) error {
 	if ex.state.activeSavepointName != "" {
 		if savepoint == ex.state.activeSavepointName {
 			return nil
 		}
+		if !ex.isRestartSavepoint(savepoint) {
+			return newSavepointDoesNotExistError(savepoint)
+		}
 		return pgerror.NewErrorf(pgerror.CodeInvalidSavepointSpecificationError,
 			`SAVEPOINT %q is in use`, tree.ErrString(&ex.state.activeSavepointName))
 	}
-	if !ex.sessionData.ForceSavepointRestart && !strings.HasPrefix(string(savepoint), RestartSavepointName) {
-		return pgerror.UnimplementedWithIssueHintError(10735,
-			"SAVEPOINT not supported except for "+RestartSavepointName,
-			"Retryable transactions with arbitrary SAVEPOINT names can be enabled "+
-				"with SET force_savepoint_restart=true")
+	if !ex.isRestartSavepoint(savepoint) {
+		return newSavepointDoesNotExistError(savepoint)
 	}
 	return nil
 }
@@ -343,7 +343,16 @@ func (ex *connExecutor) execBind(
 				// nil indicates a NULL argument value.
 				qargs[k] = tree.DNull
 			} else {
-				d, err := pgwirebase.DecodeOidDatum(ptCtx, t, qArgFormatCodes[i], arg)
+				var d tree.Datum
+				var err error
+				if typ, ok := ps.ValueType(k); ok && typ.FamilyEqual(types.AnyEnum) && t == typ.Oid() {
+					// The values of user-defined ENUM types are sent as their
+					// labels in both formats. The members of the type are only
+					// known to the statement.
+					d, err = tree.NewDEnumFromLogicalRep(types.UnwrapType(typ).(types.TEnum), string(arg))
+				} else {
+					d, err = pgwirebase.DecodeOidDatum(ptCtx, t, qArgFormatCodes[i], arg)
+				}
 				if err != nil {
 					if _, ok := err.(*pgerror.Error); ok {
 						return retErr(err)
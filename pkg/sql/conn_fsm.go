@@ -207,6 +207,10 @@ func (p eventRetriableErrPayload) errorCause() error {
 // eventRetriableErrPayload implements payloadWithError.
 var _ payloadWithError = eventRetriableErrPayload{}
 
+// eventSavepointRollback is generated in the Aborted state after a successful
+// ROLLBACK TO a regular savepoint. It moves the state back to Open.
+type eventSavepointRollback struct{}
+
 // eventTxnReleased is generated after a successful RELEASE SAVEPOINT
 // cockroach_restart. It moves the state to CommitWait.
 type eventTxnReleased struct{}
@@ -216,13 +220,14 @@ type payloadWithError interface {
 	errorCause() error
 }
 
-func (eventRetryIntentSet) Event()  {}
-func (eventTxnStart) Event()        {}
-func (eventTxnFinish) Event()       {}
-func (eventTxnRestart) Event()      {}
-func (eventNonRetriableErr) Event() {}
-func (eventRetriableErr) Event()    {}
-func (eventTxnReleased) Event()     {}
+func (eventRetryIntentSet) Event()    {}
+func (eventTxnStart) Event()          {}
+func (eventTxnFinish) Event()         {}
+func (eventTxnRestart) Event()        {}
+func (eventNonRetriableErr) Event()   {}
+func (eventRetriableErr) Event()      {}
+func (eventTxnReleased) Event()       {}
+func (eventSavepointRollback) Event() {}
 
 // TxnStateTransitions describe the transitions used by a connExecutor's
 // fsm.Machine. Args.Extended is a txnState, which is muted by the Actions.
@@ -328,8 +333,15 @@ var TxnStateTransitions = Compile(Pattern{
 			Next: stateAborted{RetryIntent: Var("retryIntent")},
 			Action: func(args Args) error {
 				ts := args.Extended.(*txnState)
-				ts.mu.txn.CleanupOnError(ts.Ctx, args.Payload.(payloadWithError).errorCause())
-				ts.setAdvanceInfo(skipBatch, noRewind, txnAborted)
+				if len(ts.savepoints) > 0 {
+					// The txn may still be resumed by a ROLLBACK TO SAVEPOINT, so
+					// neither the KV txn nor the txn-scoped state are discarded yet.
+					ts.kvTxnKeptOpen = true
+					ts.setAdvanceInfo(skipBatch, noRewind, noEvent)
+				} else {
+					ts.mu.txn.CleanupOnError(ts.Ctx, args.Payload.(payloadWithError).errorCause())
+					ts.setAdvanceInfo(skipBatch, noRewind, txnAborted)
+				}
 				ts.txnAbortCount.Inc(1)
 				return nil
 			},
@@ -423,6 +435,17 @@ var TxnStateTransitions = Compile(Pattern{
 				return nil
 			},
 		},
+		// ROLLBACK TO a regular savepoint.
+		eventSavepointRollback{}: {
+			Description: "ROLLBACK TO SAVEPOINT",
+			Next:        stateOpen{ImplicitTxn: False, RetryIntent: Var("retryIntent")},
+			Action: func(args Args) error {
+				ts := args.Extended.(*txnState)
+				ts.kvTxnKeptOpen = false
+				ts.setAdvanceInfo(advanceOne, noRewind, noEvent)
+				return nil
+			},
+		},
 	},
 	stateAborted{RetryIntent: True}: {
 		// ROLLBACK TO SAVEPOINT. We accept this in the Aborted state for the
@@ -433,6 +456,12 @@ var TxnStateTransitions = Compile(Pattern{
 			Next:        stateOpen{ImplicitTxn: False, RetryIntent: True},
 			Action: func(args Args) error {
 				ts := args.Extended.(*txnState)
+				// If the txn-scoped state was kept around for savepoints when moving
+				// to Aborted, it is discarded now.
+				txnEv := noEvent
+				if ts.kvTxnKeptOpen {
+					txnEv = txnAborted
+				}
 				ts.finishSQLTxn()
 
 				payload := args.Payload.(eventTxnStartPayload)
@@ -446,7 +475,7 @@ var TxnStateTransitions = Compile(Pattern{
 					nil, /* txn */
 					args.Payload.(eventTxnStartPayload).tranCtx,
 				)
-				ts.setAdvanceInfo(advanceOne, noRewind, noEvent)
+				ts.setAdvanceInfo(advanceOne, noRewind, txnEv)
 				return nil
 			},
 		},
@@ -151,8 +151,9 @@ var _ Command = ExecStmt{}
 // ExecPortal is the Command for executing a portal.
 type ExecPortal struct {
 	Name string
-	// limit is a feature of pgwire that we don't really support. We accept it and
-	// don't complain as long as the statement produces fewer results than this.
+	// Limit is the maximum number of rows to return, or 0 for no limit. Inside
+	// explicit transactions, a portal that reaches its limit is suspended and can
+	// be executed again to get the remaining rows.
 	Limit int
 	// TimeReceived is the time at which the exec message was received
 	// from the client. Used to compute the service latency.
@@ -617,10 +618,17 @@ type CommandResult interface {
 	CommandResultClose
 
 	// SetLimit is used when executing a portal to set a limit on the number of
-	// rows to be returned. We don't currently properly support this feature of
-	// the Postgres protocol; instead, we'll return an error if the number of rows
+	// rows to be returned. Inside explicit transactions, the connExecutor never
+	// produces more rows than this limit and suspends the portal instead (see
+	// SetPortalSuspended). Otherwise, we'll return an error if the number of rows
 	// produced is larger than this limit.
 	SetLimit(n int)
+
+	// SetPortalSuspended marks the result as belonging to the execution of a
+	// portal that reached its row limit before producing all of its rows. The
+	// client is told that the portal was suspended, instead of being told that
+	// the command completed, and can execute the portal again to get more rows.
+	SetPortalSuspended()
 }
 
 // CommandResultErrBase is the subset of CommandResult dealing with setting a
@@ -911,6 +919,11 @@ func (r *bufferedCommandResult) SetLimit(limit int) {
 	}
 }
 
+// SetPortalSuspended is part of the CommandResult interface.
+func (r *bufferedCommandResult) SetPortalSuspended() {
+	panic("unimplemented")
+}
+
 // Close is part of the CommandResult interface.
 func (r *bufferedCommandResult) Close(TransactionStatusIndicator) {
 	if r.closeCallback != nil {
@@ -0,0 +1,213 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// CopyFormat is the data format used by a COPY TO statement.
+type CopyFormat int
+
+// CopyFormat values.
+const (
+	// CopyFormatText is the tab-separated text format, in which special
+	// characters are escaped with backslashes.
+	CopyFormatText CopyFormat = iota
+	// CopyFormatCSV is the comma-separated values format.
+	CopyFormatCSV
+	// CopyFormatBinary is the Postgres binary copy format.
+	CopyFormatBinary
+)
+
+// CopyToOptions are the options of a COPY TO statement, with defaults filled
+// in for the options that were not specified.
+type CopyToOptions struct {
+	Format CopyFormat
+	// Delimiter separates the columns of a row. It is not used by the binary
+	// format.
+	Delimiter byte
+	// Null is the string representing NULL values. It is not used by the
+	// binary format.
+	Null string
+	// Header is set if the first line of the output contains the column names.
+	// It is only allowed with the CSV format.
+	Header bool
+}
+
+// MakeCopyToOptions validates the options of a COPY TO statement.
+//
+// See: https://www.postgresql.org/docs/current/static/sql-copy.html
+func MakeCopyToOptions(opts tree.KVOptions) (CopyToOptions, error) {
+	res := CopyToOptions{Format: CopyFormatText}
+	var delimiter, null *string
+	seen := make(map[string]bool, len(opts))
+	for _, o := range opts {
+		key := string(o.Key)
+		var val string
+		hasVal := o.Value != nil
+		if hasVal {
+			s, ok := o.Value.(*tree.StrVal)
+			if !ok {
+				return res, pgerror.NewErrorf(pgerror.CodeSyntaxError,
+					"invalid value for option %q", key)
+			}
+			val = s.RawString()
+		}
+		// CSV and BINARY are the pre-9.0 spellings of the FORMAT option.
+		if (key == "csv" || key == "binary") && !hasVal {
+			key, val, hasVal = "format", key, true
+		}
+		if seen[key] {
+			return res, pgerror.NewError(pgerror.CodeSyntaxError, "conflicting or redundant options")
+		}
+		seen[key] = true
+
+		switch key {
+		case "format", "delimiter", "null":
+			if !hasVal {
+				return res, pgerror.NewErrorf(pgerror.CodeSyntaxError, "%s requires a parameter", key)
+			}
+		}
+		switch key {
+		case "format":
+			switch val {
+			case "text":
+				res.Format = CopyFormatText
+			case "csv":
+				res.Format = CopyFormatCSV
+			case "binary":
+				res.Format = CopyFormatBinary
+			default:
+				return res, pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+					"COPY format %q not recognized", val)
+			}
+		case "delimiter":
+			delimiter = &val
+		case "null":
+			null = &val
+		case "header":
+			res.Header = true
+			if hasVal {
+				switch strings.ToLower(val) {
+				case "true", "on", "1":
+				case "false", "off", "0":
+					res.Header = false
+				default:
+					return res, pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+						"%s requires a Boolean value", key)
+				}
+			}
+		default:
+			return res, pgerror.NewErrorf(pgerror.CodeSyntaxError, "option %q not recognized", key)
+		}
+	}
+
+	switch res.Format {
+	case CopyFormatBinary:
+		if delimiter != nil {
+			return res, pgerror.NewError(pgerror.CodeSyntaxError,
+				"cannot specify DELIMITER in BINARY mode")
+		}
+		if null != nil {
+			return res, pgerror.NewError(pgerror.CodeSyntaxError,
+				"cannot specify NULL in BINARY mode")
+		}
+	case CopyFormatCSV:
+		res.Delimiter, res.Null = ',', ""
+	default:
+		res.Delimiter, res.Null = '\t', `\N`
+	}
+	if res.Header && res.Format != CopyFormatCSV {
+		return res, pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+			"COPY HEADER available only in CSV mode")
+	}
+	if res.Format == CopyFormatBinary {
+		return res, nil
+	}
+
+	if delimiter != nil {
+		if len(*delimiter) != 1 {
+			return res, pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+				"COPY delimiter must be a single one-byte character")
+		}
+		res.Delimiter = (*delimiter)[0]
+	}
+	if null != nil {
+		res.Null = *null
+	}
+	if res.Delimiter == '\n' || res.Delimiter == '\r' {
+		return res, pgerror.NewError(pgerror.CodeInvalidParameterValueError,
+			"COPY delimiter cannot be newline or carriage return")
+	}
+	if strings.ContainsAny(res.Null, "\r\n") {
+		return res, pgerror.NewError(pgerror.CodeInvalidParameterValueError,
+			"COPY null representation cannot use newline or carriage return")
+	}
+	switch res.Format {
+	case CopyFormatText:
+		// The delimiter can't be a character that could appear in a backslash
+		// escape sequence.
+		if strings.IndexByte(`\.abcdefghijklmnopqrstuvwxyz0123456789`, res.Delimiter) >= 0 {
+			return res, pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+				"COPY delimiter cannot be %q", string(res.Delimiter))
+		}
+	case CopyFormatCSV:
+		if res.Delimiter == '"' {
+			return res, pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+				"COPY delimiter and quote must be different")
+		}
+	}
+	if strings.IndexByte(res.Null, res.Delimiter) >= 0 {
+		return res, pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+			"COPY delimiter must not appear in the NULL specification")
+	}
+	return res, nil
+}
+
+// CopyTo plans a COPY TO statement. The plan produces the rows to be copied
+// out; encoding them in the requested format is the responsibility of the
+// client connection, which streams them in CopyData messages.
+func (p *planner) CopyTo(ctx context.Context, n *tree.CopyTo) (planNode, error) {
+	if _, err := MakeCopyToOptions(n.Options); err != nil {
+		return nil, err
+	}
+	if n.Statement != nil {
+		if n.Statement.StatementType() != tree.Rows {
+			return nil, pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+				"COPY query must have a RETURNING clause")
+		}
+		return p.newPlan(ctx, n.Statement, nil /* desiredTypes */)
+	}
+
+	// COPY t (a, b) TO STDOUT copies the same rows as
+	// COPY (SELECT a, b FROM t) TO STDOUT.
+	exprs := tree.SelectExprs{tree.StarSelectExpr()}
+	if len(n.Columns) > 0 {
+		exprs = make(tree.SelectExprs, len(n.Columns))
+		for i, col := range n.Columns {
+			exprs[i] = tree.SelectExpr{Expr: tree.NewUnresolvedName(string(col))}
+		}
+	}
+	sel := &tree.Select{Select: &tree.SelectClause{
+		Exprs: exprs,
+		From:  &tree.From{Tables: tree.TableExprs{&n.Table}},
+	}}
+	return p.Select(ctx, sel, nil /* desiredTypes */)
+}
@@ -301,6 +301,14 @@ CREATE TABLE crdb_internal.schema_changes (
 					mutType = "INDEX"
 					targetID = tree.NewDInt(tree.DInt(int64(d.Index.ID)))
 					targetName = tree.NewDString(d.Index.Name)
+				case *sqlbase.DescriptorMutation_Constraint:
+					mutType = "CONSTRAINT"
+					targetName = tree.NewDString(d.Constraint.Name)
+				case *sqlbase.DescriptorMutation_ComputedColumnSwap:
+					mutType = "COLUMN SWAP"
+					targetID = tree.NewDInt(tree.DInt(int64(d.ComputedColumnSwap.OldColumnID)))
+				case *sqlbase.DescriptorMutation_MaterializedViewRefresh:
+					mutType = "MATERIALIZED VIEW REFRESH"
 				}
 				if err := addRow(
 					tableID,
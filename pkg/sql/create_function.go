@@ -0,0 +1,441 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+)
+
+type createFunctionNode struct {
+	n      *tree.CreateFunction
+	dbDesc *sqlbase.DatabaseDescriptor
+	// desc is the existing descriptor of the function, if any.
+	desc *sqlbase.FunctionDescriptor
+	// replaceIdx is the index of the overload of desc that is replaced, or -1
+	// if the overload is added.
+	replaceIdx int
+	overload   sqlbase.FunctionDescriptor_Overload
+}
+
+// CreateFunction creates a user-defined function written in SQL, or adds an
+// overload to an existing function.
+// Privileges: CREATE on database, and DROP on function to replace an
+// existing overload.
+//   Notes: postgres requires CREATE on the schema, and ownership of the
+//          function to replace it.
+func (p *planner) CreateFunction(ctx context.Context, n *tree.CreateFunction) (planNode, error) {
+	dbDesc, err := p.resolveTypeDatabase(ctx, &n.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	if _, ok := tree.FunDefs[n.Name.Table()]; ok {
+		return nil, pgerror.NewErrorf(pgerror.CodeDuplicateFunctionError,
+			"function %s conflicts with a built-in function", tree.ErrString(&n.Name))
+	}
+
+	overload := sqlbase.FunctionDescriptor_Overload{
+		ReturnsSet: n.ReturnsSet,
+		Volatility: sqlbase.FunctionDescriptor_Volatility(n.Volatility),
+	}
+	argTypes := make([]types.T, len(n.Args))
+	for i := range n.Args {
+		arg := &n.Args[i]
+		if arg.Name != "" {
+			for j := 0; j < i; j++ {
+				if n.Args[j].Name == arg.Name {
+					return nil, pgerror.NewErrorf(pgerror.CodeInvalidFunctionDefinitionError,
+						"parameter name %q used more than once", arg.Name)
+				}
+			}
+		}
+		var colType sqlbase.ColumnType
+		colType, argTypes[i], err = p.functionColumnType(arg.Type)
+		if err != nil {
+			return nil, err
+		}
+		overload.Arguments = append(overload.Arguments, sqlbase.FunctionDescriptor_Argument{
+			Name: string(arg.Name),
+			Type: colType,
+		})
+	}
+	var retType types.T
+	overload.ReturnType, retType, err = p.functionColumnType(n.ReturnType)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := makeFunctionBody(n.Body, n.Args)
+	if err != nil {
+		return nil, err
+	}
+	overload.Body = tree.AsStringWithFlags(body, tree.FmtParsable)
+	if err := p.checkFunctionBody(ctx, overload.Body, retType); err != nil {
+		return nil, err
+	}
+
+	node := &createFunctionNode{n: n, dbDesc: dbDesc, replaceIdx: -1, overload: overload}
+	node.desc, err = getFunctionDesc(ctx, p.txn, dbDesc.ID, n.Name.Table())
+	if err != nil || node.desc == nil {
+		return node, err
+	}
+	if node.desc.Overloads[0].ReturnsSet != n.ReturnsSet {
+		return nil, pgerror.NewErrorf(pgerror.CodeInvalidFunctionDefinitionError,
+			"overloads of function %s must either all return sets or none",
+			tree.ErrString(&n.Name))
+	}
+	node.replaceIdx = node.desc.FindOverload(argTypes)
+	if node.replaceIdx == -1 {
+		return node, nil
+	}
+	if !n.Replace {
+		return nil, pgerror.NewErrorf(pgerror.CodeDuplicateFunctionError,
+			"function %s already exists with same argument types", tree.ErrString(&n.Name))
+	}
+	if err := p.CheckPrivilege(ctx, node.desc, privilege.DROP); err != nil {
+		return nil, err
+	}
+	if !node.desc.Overloads[node.replaceIdx].ReturnType.ToDatumType().Equivalent(retType) {
+		return nil, pgerror.NewErrorf(pgerror.CodeInvalidFunctionDefinitionError,
+			"cannot change return type of existing function").SetHintf(
+			"Use DROP FUNCTION %s first.", tree.ErrString(&n.Name))
+	}
+	return node, nil
+}
+
+// functionColumnType returns the column type and the datum type of the
+// given type of an argument or of the result of a user-defined function.
+func (p *planner) functionColumnType(t coltypes.T) (sqlbase.ColumnType, types.T, error) {
+	if err := tree.ResolveColumnType(&p.semaCtx, t); err != nil {
+		return sqlbase.ColumnType{}, nil, err
+	}
+	typ := coltypes.CastTargetToDatumType(t)
+	if _, ok := typ.(types.TEnum); ok {
+		return sqlbase.ColumnType{}, nil, pgerror.Unimplemented("udf enum",
+			"user-defined types are not supported in the signature of functions")
+	}
+	colType, err := sqlbase.DatumTypeToColumnType(typ)
+	if err != nil {
+		return sqlbase.ColumnType{}, nil, err
+	}
+	colType, err = sqlbase.PopulateTypeAttrs(colType, t)
+	return colType, typ, err
+}
+
+// makeFunctionBody parses the body of a user-defined function and replaces
+// the references to its arguments, by name or by position, with
+// placeholders cast to the type of the argument. Arguments take precedence
+// over columns with the same name; references to columns can be qualified
+// with the name of their table to avoid the ambiguity.
+func makeFunctionBody(sql string, args tree.FuncArgs) (*tree.Select, error) {
+	stmts, err := parser.Parse(sql)
+	if err != nil {
+		return nil, err
+	}
+	if len(stmts) != 1 {
+		return nil, pgerror.NewErrorf(pgerror.CodeInvalidFunctionDefinitionError,
+			"the body of a SQL function must be a single SELECT statement")
+	}
+	body, ok := stmts[0].AST.(*tree.Select)
+	if !ok {
+		return nil, pgerror.NewErrorf(pgerror.CodeInvalidFunctionDefinitionError,
+			"the body of a SQL function must be a SELECT statement, not %s",
+			stmts[0].AST.StatementTag())
+	}
+
+	argRef := func(i int) tree.Expr {
+		return &tree.CastExpr{
+			Expr:       &tree.Placeholder{Idx: types.PlaceholderIdx(i)},
+			Type:       args[i].Type,
+			SyntaxMode: tree.CastShort,
+		}
+	}
+	r := functionBodyRewriter{rewrite: func(expr tree.Expr) (tree.Expr, error) {
+		switch t := expr.(type) {
+		case *tree.UnresolvedName:
+			if t.NumParts != 1 || t.Star {
+				break
+			}
+			for i := range args {
+				if args[i].Name != "" && string(args[i].Name) == t.Parts[0] {
+					return argRef(i), nil
+				}
+			}
+		case *tree.Placeholder:
+			if int(t.Idx) >= len(args) {
+				return nil, pgerror.NewErrorf(pgerror.CodeUndefinedParameterError,
+					"there is no parameter %s", t)
+			}
+			return argRef(int(t.Idx)), nil
+		}
+		return nil, nil
+	}}
+	r.walkSelectStmt(body)
+	return body, r.err
+}
+
+// checkFunctionBody verifies that the body of an overload of a user-defined
+// function computes a single column of the given type. The body is planned
+// with all the arguments set to NULL.
+func (p *planner) checkFunctionBody(ctx context.Context, sql string, retType types.T) error {
+	stmt, err := parser.ParseOne(sql)
+	if err != nil {
+		return err
+	}
+	body := stmt.AST.(*tree.Select)
+	r := functionBodyRewriter{rewrite: func(expr tree.Expr) (tree.Expr, error) {
+		if _, ok := expr.(*tree.Placeholder); ok {
+			return tree.DNull, nil
+		}
+		return nil, nil
+	}}
+	r.walkSelectStmt(body)
+	if r.err != nil {
+		return r.err
+	}
+
+	// The plans of the subqueries of the body are not needed further.
+	defer func(prev []subquery) {
+		for _, s := range p.curPlan.subqueryPlans[len(prev):] {
+			s.plan.Close(ctx)
+		}
+		p.curPlan.subqueryPlans = prev
+	}(p.curPlan.subqueryPlans)
+
+	plan, err := p.Select(ctx, body, []types.T{retType})
+	if err != nil {
+		return err
+	}
+	defer plan.Close(ctx)
+
+	cols := planColumns(plan)
+	if len(cols) != 1 {
+		return pgerror.NewErrorf(pgerror.CodeInvalidFunctionDefinitionError,
+			"the body of a SQL function must return a single column, not %d", len(cols))
+	}
+	if typ := cols[0].Typ; typ != types.Unknown && !typ.Equivalent(retType) {
+		return pgerror.NewErrorf(pgerror.CodeInvalidFunctionDefinitionError,
+			"return type mismatch in function declared to return %s", retType).SetDetailf(
+			"Actual return type is %s.", typ)
+	}
+	return nil
+}
+
+// functionBodyRewriter replaces expressions in the body of a user-defined
+// function, including in subqueries and in the FROM clause. The body is
+// modified in place.
+type functionBodyRewriter struct {
+	// rewrite returns the replacement of the given expression, or nil to
+	// recurse into the expression.
+	rewrite func(tree.Expr) (tree.Expr, error)
+	err     error
+}
+
+var _ tree.Visitor = &functionBodyRewriter{}
+
+// VisitPre implements the tree.Visitor interface.
+func (r *functionBodyRewriter) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
+	if r.err != nil {
+		return false, expr
+	}
+	if sub, ok := expr.(*tree.Subquery); ok {
+		r.walkSelect(sub.Select)
+		return false, expr
+	}
+	newExpr, r.err = r.rewrite(expr)
+	if newExpr != nil || r.err != nil {
+		return false, newExpr
+	}
+	return true, expr
+}
+
+// VisitPost implements the tree.Visitor interface.
+func (*functionBodyRewriter) VisitPost(expr tree.Expr) tree.Expr { return expr }
+
+func (r *functionBodyRewriter) walkExpr(expr tree.Expr) tree.Expr {
+	if expr == nil {
+		return nil
+	}
+	expr, _ = tree.WalkExpr(r, expr)
+	return expr
+}
+
+func (r *functionBodyRewriter) walkExprs(exprs []tree.Expr) {
+	for i := range exprs {
+		exprs[i] = r.walkExpr(exprs[i])
+	}
+}
+
+func (r *functionBodyRewriter) walkSelectStmt(stmt *tree.Select) {
+	if stmt.With != nil {
+		for _, cte := range stmt.With.CTEList {
+			if sel, ok := cte.Stmt.(*tree.Select); ok {
+				r.walkSelectStmt(sel)
+			}
+		}
+	}
+	r.walkSelect(stmt.Select)
+	for _, o := range stmt.OrderBy {
+		o.Expr = r.walkExpr(o.Expr)
+	}
+	if stmt.Limit != nil {
+		stmt.Limit.Offset = r.walkExpr(stmt.Limit.Offset)
+		stmt.Limit.Count = r.walkExpr(stmt.Limit.Count)
+	}
+}
+
+func (r *functionBodyRewriter) walkSelect(stmt tree.SelectStatement) {
+	switch t := stmt.(type) {
+	case *tree.ParenSelect:
+		r.walkSelectStmt(t.Select)
+	case *tree.UnionClause:
+		r.walkSelectStmt(t.Left)
+		r.walkSelectStmt(t.Right)
+	case *tree.ValuesClause:
+		for _, row := range t.Rows {
+			r.walkExprs(row)
+		}
+	case *tree.SelectClause:
+		r.walkExprs(t.DistinctOn)
+		for i := range t.Exprs {
+			t.Exprs[i].Expr = r.walkExpr(t.Exprs[i].Expr)
+		}
+		if t.From != nil {
+			for _, te := range t.From.Tables {
+				r.walkTableExpr(te)
+			}
+		}
+		if t.Where != nil {
+			t.Where.Expr = r.walkExpr(t.Where.Expr)
+		}
+		r.walkExprs(t.GroupBy)
+		if t.Having != nil {
+			t.Having.Expr = r.walkExpr(t.Having.Expr)
+		}
+	}
+}
+
+func (r *functionBodyRewriter) walkTableExpr(te tree.TableExpr) {
+	switch t := te.(type) {
+	case *tree.AliasedTableExpr:
+		r.walkTableExpr(t.Expr)
+	case *tree.ParenTableExpr:
+		r.walkTableExpr(t.Expr)
+	case *tree.JoinTableExpr:
+		r.walkTableExpr(t.Left)
+		r.walkTableExpr(t.Right)
+		if on, ok := t.Cond.(*tree.OnJoinCond); ok {
+			on.Expr = r.walkExpr(on.Expr)
+		}
+	case *tree.Subquery:
+		r.walkSelect(t.Select)
+	case *tree.RowsFromExpr:
+		r.walkExprs(t.Items)
+	}
+}
+
+func (n *createFunctionNode) startExec(params runParams) error {
+	if n.desc != nil {
+		if n.replaceIdx == -1 {
+			n.desc.Overloads = append(n.desc.Overloads, n.overload)
+		} else {
+			n.desc.Overloads[n.replaceIdx] = n.overload
+		}
+		if err := n.desc.Validate(); err != nil {
+			return err
+		}
+		if err := params.p.writeFunctionDesc(params.ctx, n.desc); err != nil {
+			return err
+		}
+		return n.logEvent(params)
+	}
+
+	tKey := tableKey{parentID: n.dbDesc.ID, name: n.n.Name.Table()}
+	if exists, err := descExists(params.ctx, params.p.txn, tKey.Key()); err == nil && exists {
+		return pgerror.NewErrorf(pgerror.CodeDuplicateObjectError,
+			"function name %q conflicts with the name of a relation, type or schema",
+			tree.ErrString(&n.n.Name))
+	} else if err != nil {
+		return err
+	}
+
+	id, err := GenerateUniqueDescID(params.ctx, params.p.ExecCfg().DB)
+	if err != nil {
+		return err
+	}
+
+	// Inherit permissions from the database descriptor. Like in postgres,
+	// everyone can execute a new function.
+	privs := protoutil.Clone(n.dbDesc.GetPrivileges()).(*sqlbase.PrivilegeDescriptor)
+	privs.Grant(sqlbase.PublicRole, privilege.List{privilege.EXECUTE})
+	n.desc = &sqlbase.FunctionDescriptor{
+		Name:       n.n.Name.Table(),
+		ParentID:   n.dbDesc.ID,
+		Overloads:  []sqlbase.FunctionDescriptor_Overload{n.overload},
+		Privileges: privs,
+	}
+	if err := params.p.createDescriptorWithID(
+		params.ctx, tKey.Key(), id, n.desc, params.EvalContext().Settings); err != nil {
+		return err
+	}
+	if err := n.desc.Validate(); err != nil {
+		return err
+	}
+	return n.logEvent(params)
+}
+
+// logEvent logs a Create Function event. This is an auditable log event and
+// is recorded in the same transaction as the function descriptor update.
+func (n *createFunctionNode) logEvent(params runParams) error {
+	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+		params.ctx,
+		params.p.txn,
+		EventLogCreateFunction,
+		int32(n.desc.ID),
+		int32(params.extendedEvalCtx.NodeID),
+		struct {
+			FunctionName string
+			Statement    string
+			User         string
+		}{n.n.Name.FQString(), n.n.String(), params.SessionData().User},
+	)
+}
+
+// writeFunctionDesc writes an updated function descriptor.
+func (p *planner) writeFunctionDesc(ctx context.Context, desc *sqlbase.FunctionDescriptor) error {
+	descKey := sqlbase.MakeDescMetadataKey(desc.ID)
+	if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+		log.VEventf(ctx, 2, "Put %s -> %s", descKey, desc)
+	}
+	return p.txn.Put(ctx, descKey, sqlbase.WrapDescriptor(desc))
+}
+
+func (*createFunctionNode) Next(runParams) (bool, error) { return false, nil }
+func (*createFunctionNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createFunctionNode) Close(context.Context)        {}
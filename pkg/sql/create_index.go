@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 )
 
@@ -35,7 +36,7 @@ type createIndexNode struct {
 //          mysql requires INDEX on the table.
 func (p *planner) CreateIndex(ctx context.Context, n *tree.CreateIndex) (planNode, error) {
 	tableDesc, err := p.ResolveMutableTableDescriptor(
-		ctx, &n.Table, true /*required*/, requireTableDesc,
+		ctx, &n.Table, true /*required*/, requireTableOrMaterializedViewDesc,
 	)
 	if err != nil {
 		return nil, err
@@ -72,15 +73,58 @@ func MakeIndexDescriptor(n *tree.CreateIndex) (*sqlbase.IndexDescriptor, error)
 		if n.Unique {
 			return nil, pgerror.NewError(pgerror.CodeInvalidSQLStatementNameError, "inverted indexes can't be unique")
 		}
+
+		if n.Predicate != nil {
+			return nil, pgerror.NewError(pgerror.CodeInvalidSQLStatementNameError, "inverted indexes can't be partial")
+		}
 		indexDesc.Type = sqlbase.IndexDescriptor_INVERTED
 	}
 
+	if n.Predicate != nil && n.Interleave != nil {
+		return nil, pgerror.NewError(pgerror.CodeInvalidSQLStatementNameError, "partial indexes don't support interleaved tables")
+	}
+
 	if err := indexDesc.FillColumns(n.Columns); err != nil {
 		return nil, err
 	}
 	return &indexDesc, nil
 }
 
+// makeIndexPredicate checks that the predicate of a partial index is a boolean
+// expression that only references columns of the table, and returns its
+// serialized form for the index descriptor. Like computed columns, the
+// predicate cannot contain impure functions, as it must have the same value
+// every time it is evaluated for a row.
+func makeIndexPredicate(
+	ctx context.Context,
+	desc *sqlbase.MutableTableDescriptor,
+	predicate tree.Expr,
+	tableName tree.TableName,
+	semaCtx *tree.SemaContext,
+	evalCtx *tree.EvalContext,
+) (*string, error) {
+	// Replace column references with typed dummies to allow typechecking.
+	replacedExpr, _, err := replaceVars(desc, predicate)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := sqlbase.SanitizeVarFreeExpr(
+		replacedExpr, types.Bool, "index predicate", semaCtx, evalCtx, false, /* allowImpure */
+	); err != nil {
+		return nil, err
+	}
+
+	sources := sqlbase.MultiSourceInfo{sqlbase.NewSourceInfoForSingleTable(
+		tableName, sqlbase.ResultColumnsFromColDescs(desc.Columns),
+	)}
+	expr, err := dequalifyColumnRefs(ctx, sources, predicate)
+	if err != nil {
+		return nil, err
+	}
+	serialized := tree.Serialize(expr)
+	return &serialized, nil
+}
+
 func (n *createIndexNode) startExec(params runParams) error {
 	_, dropped, err := n.tableDesc.FindIndexByName(string(n.n.Name))
 	if err == nil {
@@ -97,6 +141,14 @@ func (n *createIndexNode) startExec(params runParams) error {
 		return err
 	}
 
+	if n.n.Predicate != nil {
+		indexDesc.Predicate, err = makeIndexPredicate(params.ctx, n.tableDesc, n.n.Predicate,
+			n.n.Table, &params.p.semaCtx, params.EvalContext())
+		if err != nil {
+			return err
+		}
+	}
+
 	if n.n.PartitionBy != nil {
 		partitioning, err := CreatePartitioning(params.ctx, params.p.ExecCfg().Settings,
 			params.EvalContext(), n.tableDesc, indexDesc, n.n.PartitionBy)
@@ -0,0 +1,121 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+type createSchemaNode struct {
+	n      *tree.CreateSchema
+	dbDesc *sqlbase.DatabaseDescriptor
+}
+
+// CreateSchema creates a user-defined schema in the current database.
+// Privileges: CREATE on database.
+//   Notes: postgres requires CREATE on the database.
+func (p *planner) CreateSchema(ctx context.Context, n *tree.CreateSchema) (planNode, error) {
+	if p.CurrentDatabase() == "" {
+		return nil, errNoDatabase
+	}
+	dbDesc, err := p.ResolveUncachedDatabaseByName(ctx, p.CurrentDatabase(), true /* required */)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	scName := string(n.Schema)
+	if strings.HasPrefix(scName, pgSchemaPrefix) {
+		return nil, unacceptableSchemaNameError(scName)
+	}
+	if isSystemSchemaName(scName) {
+		if n.IfNotExists {
+			return newZeroNode(nil /* columns */), nil
+		}
+		return nil, pgerror.NewErrorf(pgerror.CodeDuplicateSchemaError,
+			"schema %q already exists", scName)
+	}
+
+	return &createSchemaNode{n: n, dbDesc: dbDesc}, nil
+}
+
+func (n *createSchemaNode) startExec(params runParams) error {
+	scName := string(n.n.Schema)
+	tKey := tableKey{parentID: n.dbDesc.ID, name: scName}
+	if exists, err := descExists(params.ctx, params.p.txn, tKey.Key()); err == nil && exists {
+		scDesc, err := getSchemaDesc(params.ctx, params.p.txn, n.dbDesc.ID, scName)
+		if err != nil {
+			return err
+		}
+		if scDesc == nil {
+			return pgerror.NewErrorf(pgerror.CodeDuplicateObjectError,
+				"schema name %q conflicts with the name of a relation or type", scName)
+		}
+		if n.n.IfNotExists {
+			return nil
+		}
+		return pgerror.NewErrorf(pgerror.CodeDuplicateSchemaError,
+			"schema %q already exists", scName)
+	} else if err != nil {
+		return err
+	}
+
+	id, err := GenerateUniqueDescID(params.ctx, params.p.ExecCfg().DB)
+	if err != nil {
+		return err
+	}
+
+	// Inherit permissions from the database descriptor.
+	desc := sqlbase.SchemaDescriptor{
+		Name:       scName,
+		ParentID:   n.dbDesc.ID,
+		Privileges: n.dbDesc.GetPrivileges(),
+	}
+	if err := params.p.createDescriptorWithID(
+		params.ctx, tKey.Key(), id, &desc, params.EvalContext().Settings); err != nil {
+		return err
+	}
+	if err := desc.Validate(); err != nil {
+		return err
+	}
+
+	// Log Create Schema event. This is an auditable log event and is
+	// recorded in the same transaction as the schema descriptor update.
+	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+		params.ctx,
+		params.p.txn,
+		EventLogCreateSchema,
+		int32(desc.ID),
+		int32(params.extendedEvalCtx.NodeID),
+		struct {
+			SchemaName string
+			Statement  string
+			User       string
+		}{scName, n.n.String(), params.SessionData().User},
+	)
+}
+
+func (*createSchemaNode) Next(runParams) (bool, error) { return false, nil }
+func (*createSchemaNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createSchemaNode) Close(context.Context)        {}
@@ -20,7 +20,6 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
-	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -32,12 +31,17 @@ type createSequenceNode struct {
 }
 
 func (p *planner) CreateSequence(ctx context.Context, n *tree.CreateSequence) (planNode, error) {
+	var err error
+	n.Temporary, err = sqlbase.QualifyTemporaryTableName(&n.Name, n.Temporary)
+	if err != nil {
+		return nil, err
+	}
 	dbDesc, err := p.ResolveUncachedDatabase(ctx, &n.Name)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+	if err := p.checkCreatePrivilege(ctx, dbDesc, &n.Name); err != nil {
 		return nil, err
 	}
 
@@ -48,7 +52,11 @@ func (p *planner) CreateSequence(ctx context.Context, n *tree.CreateSequence) (p
 }
 
 func (n *createSequenceNode) startExec(params runParams) error {
-	tKey := getSequenceKey(n.dbDesc, n.n.Name.Table())
+	parentID, _, _, err := params.p.getCreateParentID(params.ctx, n.dbDesc.ID, &n.n.Name)
+	if err != nil {
+		return err
+	}
+	tKey := tableKey{parentID: parentID, name: n.n.Name.Table()}
 	if exists, err := descExists(params.ctx, params.p.txn, tKey.Key()); err == nil && exists {
 		if n.n.IfNotExists {
 			// If the sequence exists but the user specified IF NOT EXISTS, return without doing anything.
@@ -62,10 +70,6 @@ func (n *createSequenceNode) startExec(params runParams) error {
 	return doCreateSequence(params, n.n.String(), n.dbDesc, &n.n.Name, n.n.Options)
 }
 
-func getSequenceKey(dbDesc *DatabaseDescriptor, seqName string) tableKey {
-	return tableKey{parentID: dbDesc.ID, name: seqName}
-}
-
 // doCreateSequence performs the creation of a sequence in KV. The
 // context argument is a string to use in the event log.
 func doCreateSequence(
@@ -75,24 +79,31 @@ func doCreateSequence(
 	name *ObjectName,
 	opts tree.SequenceOptions,
 ) error {
+	parentID, temporarySchemaID, scDesc, err := params.p.getCreateParentID(params.ctx, dbDesc.ID, name)
+	if err != nil {
+		return err
+	}
+
 	id, err := GenerateUniqueDescID(params.ctx, params.p.ExecCfg().DB)
 	if err != nil {
 		return err
 	}
 
-	// Inherit permissions from the database descriptor.
-	privs := dbDesc.GetPrivileges()
+	// Inherit permissions from the schema or database descriptor.
+	privs := inheritedPrivileges(dbDesc, scDesc)
 
 	desc, err := MakeSequenceTableDesc(name.Table(), opts,
 		dbDesc.ID, id, params.p.txn.CommitTimestamp(), privs, params.EvalContext().Settings)
 	if err != nil {
 		return err
 	}
+	desc.TemporarySchemaID = temporarySchemaID
+	desc.SchemaID = scDesc.GetID()
 
 	// makeSequenceTableDesc already validates the table. No call to
 	// desc.ValidateTable() needed here.
 
-	key := getSequenceKey(dbDesc, name.Table()).Key()
+	key := tableKey{parentID: parentID, name: name.Table()}.Key()
 	if err = params.p.createDescriptorWithID(params.ctx, key, id, &desc, params.EvalContext().Settings); err != nil {
 		return err
 	}
@@ -27,7 +27,6 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/coltypes"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
-	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
@@ -49,12 +48,17 @@ type createTableNode struct {
 // Privileges: CREATE on database.
 //   Notes: postgres/mysql require CREATE on database.
 func (p *planner) CreateTable(ctx context.Context, n *tree.CreateTable) (planNode, error) {
+	var err error
+	n.Temporary, err = sqlbase.QualifyTemporaryTableName(&n.Table, n.Temporary)
+	if err != nil {
+		return nil, err
+	}
 	dbDesc, err := p.ResolveUncachedDatabase(ctx, &n.Table)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+	if err := p.checkCreatePrivilege(ctx, dbDesc, &n.Table); err != nil {
 		return nil, err
 	}
 
@@ -104,7 +108,11 @@ type createTableRun struct {
 }
 
 func (n *createTableNode) startExec(params runParams) error {
-	tKey := tableKey{parentID: n.dbDesc.ID, name: n.n.Table.Table()}
+	parentID, temporarySchemaID, scDesc, err := params.p.getCreateParentID(params.ctx, n.dbDesc.ID, &n.n.Table)
+	if err != nil {
+		return err
+	}
+	tKey := tableKey{parentID: parentID, name: n.n.Table.Table()}
 	key := tKey.Key()
 	if exists, err := descExists(params.ctx, params.p.txn, key); err == nil && exists {
 		if n.n.IfNotExists {
@@ -122,7 +130,7 @@ func (n *createTableNode) startExec(params runParams) error {
 
 	// If a new system table is being created (which should only be doable by
 	// an internal user account), make sure it gets the correct privileges.
-	privs := n.dbDesc.GetPrivileges()
+	privs := inheritedPrivileges(n.dbDesc, scDesc)
 	if n.dbDesc.ID == keys.SystemDatabaseID {
 		privs = sqlbase.NewDefaultPrivilegeDescriptor()
 	}
@@ -149,6 +157,15 @@ func (n *createTableNode) startExec(params runParams) error {
 	if err != nil {
 		return err
 	}
+	desc.TemporarySchemaID = temporarySchemaID
+	desc.SchemaID = scDesc.GetID()
+	for _, ref := range affected {
+		if ref.IsTable() {
+			if err := checkTemporaryReference(&desc, ref); err != nil {
+				return err
+			}
+		}
+	}
 
 	if desc.Adding() {
 		// if this table and all its references are created in the same
@@ -387,6 +404,21 @@ func qualifyFKColErrorWithDB(
 	return tree.ErrString(tree.NewUnresolvedName(db.Name, tree.PublicSchema, tbl.Name, col))
 }
 
+// checkTemporaryReference checks that a constraint of the table tbl can
+// reference the table target: temporary tables can only reference temporary
+// tables, and permanent tables can only reference permanent tables.
+func checkTemporaryReference(tbl, target *sqlbase.MutableTableDescriptor) error {
+	if tbl.IsTemporary() == target.IsTemporary() {
+		return nil
+	}
+	if tbl.IsTemporary() {
+		return pgerror.NewError(pgerror.CodeInvalidTableDefinitionError,
+			"constraints on temporary tables may reference only temporary tables")
+	}
+	return pgerror.NewError(pgerror.CodeInvalidTableDefinitionError,
+		"constraints on permanent tables may reference only permanent tables")
+}
+
 // FKTableState is the state of the referencing table resolveFK() is called on.
 type FKTableState int
 
@@ -444,6 +476,13 @@ func ResolveFK(
 	if err != nil {
 		return err
 	}
+	if ts != NewTable {
+		// The references of new tables are checked once their descriptor is
+		// complete, see createTableNode.startExec().
+		if err := checkTemporaryReference(tbl, target); err != nil {
+			return err
+		}
+	}
 	if target.ID == tbl.ID {
 		// When adding a self-ref FK to an _existing_ table, we want to make sure
 		// we edit the same copy.
@@ -513,9 +552,11 @@ func ResolveFK(
 		targetIdxID = target.PrimaryIndex.ID
 	} else {
 		found := false
-		// Find the index corresponding to the referenced column.
+		// Find the index corresponding to the referenced column. Partial
+		// indexes only guarantee uniqueness for some of the rows, so they
+		// can't be referenced.
 		for i, idx := range target.Indexes {
-			if idx.Unique && matchesIndex(targetCols, idx, matchExact) {
+			if idx.Unique && !idx.IsPartial() && matchesIndex(targetCols, idx, matchExact) {
 				targetIdxIndex = i
 				targetIdxID = idx.ID
 				found = true
@@ -558,13 +599,15 @@ func ResolveFK(
 	}
 
 	ref := sqlbase.ForeignKeyReference{
-		Table:           target.ID,
-		Index:           targetIdxID,
-		Name:            constraintName,
-		SharedPrefixLen: int32(len(srcCols)),
-		OnDelete:        sqlbase.ForeignKeyReferenceActionValue[d.Actions.Delete],
-		OnUpdate:        sqlbase.ForeignKeyReferenceActionValue[d.Actions.Update],
-		Match:           sqlbase.CompositeKeyMatchMethodValue[d.Match],
+		Table:             target.ID,
+		Index:             targetIdxID,
+		Name:              constraintName,
+		SharedPrefixLen:   int32(len(srcCols)),
+		OnDelete:          sqlbase.ForeignKeyReferenceActionValue[d.Actions.Delete],
+		OnUpdate:          sqlbase.ForeignKeyReferenceActionValue[d.Actions.Update],
+		Match:             sqlbase.CompositeKeyMatchMethodValue[d.Match],
+		Deferrable:        d.Deferrability != tree.NotDeferrable,
+		InitiallyDeferred: d.Deferrability == tree.DeferrableInitiallyDeferred,
 	}
 
 	if ts != NewTable {
@@ -582,7 +625,7 @@ func ResolveFK(
 	} else {
 		found := false
 		for i := range tbl.Indexes {
-			if matchesIndex(srcCols, tbl.Indexes[i], matchPrefix) {
+			if !tbl.Indexes[i].IsPartial() && matchesIndex(srcCols, tbl.Indexes[i], matchPrefix) {
 				if tbl.Indexes[i].ForeignKey.IsSet() {
 					return pgerror.NewErrorf(pgerror.CodeInvalidForeignKeyError,
 						"columns cannot be used by multiple foreign key constraints")
@@ -726,6 +769,10 @@ func addInterleave(
 	if err != nil {
 		return err
 	}
+	if desc.IsTemporary() || parentTable.IsTemporary() {
+		return pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+			"temporary tables cannot be interleaved")
+	}
 	parentIndex := parentTable.PrimaryIndex
 
 	// typeOfIndex is used to give more informative error messages.
@@ -1084,6 +1131,13 @@ func MakeTableDesc(
 				}
 				idx.Partitioning = partitioning
 			}
+			if d.Predicate != nil {
+				pred, err := makeIndexPredicate(ctx, &desc, d.Predicate, n.Table, semaCtx, evalCtx)
+				if err != nil {
+					return desc, err
+				}
+				idx.Predicate = pred
+			}
 			if err := desc.AddIndex(idx, false); err != nil {
 				return desc, err
 			}
@@ -1106,6 +1160,13 @@ func MakeTableDesc(
 				}
 				idx.Partitioning = partitioning
 			}
+			if d.Predicate != nil {
+				pred, err := makeIndexPredicate(ctx, &desc, d.Predicate, n.Table, semaCtx, evalCtx)
+				if err != nil {
+					return desc, err
+				}
+				idx.Predicate = pred
+			}
 			if err := desc.AddIndex(idx, d.PrimaryKey); err != nil {
 				return desc, err
 			}
@@ -1153,6 +1214,10 @@ func MakeTableDesc(
 	}
 
 	if n.Interleave != nil {
+		if n.Temporary {
+			return desc, pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+				"temporary tables cannot be interleaved")
+		}
 		if err := addInterleave(ctx, txn, vt, &desc, &desc.PrimaryIndex, n.Interleave); err != nil {
 			return desc, err
 		}
@@ -0,0 +1,103 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+type createTypeNode struct {
+	n      *tree.CreateType
+	dbDesc *sqlbase.DatabaseDescriptor
+}
+
+// CreateType creates a user-defined ENUM type.
+// Privileges: CREATE on database.
+//   Notes: postgres requires CREATE on the schema.
+func (p *planner) CreateType(ctx context.Context, n *tree.CreateType) (planNode, error) {
+	dbDesc, err := p.resolveTypeDatabase(ctx, &n.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{}, len(n.EnumLabels))
+	for _, label := range n.EnumLabels {
+		if _, ok := seen[label]; ok {
+			return nil, pgerror.NewErrorf(pgerror.CodeDuplicateObjectError,
+				"enum label %q used more than once", label)
+		}
+		seen[label] = struct{}{}
+	}
+
+	return &createTypeNode{n: n, dbDesc: dbDesc}, nil
+}
+
+func (n *createTypeNode) startExec(params runParams) error {
+	tKey := tableKey{parentID: n.dbDesc.ID, name: n.n.Name.Table()}
+	if exists, err := descExists(params.ctx, params.p.txn, tKey.Key()); err == nil && exists {
+		return pgerror.NewErrorf(pgerror.CodeDuplicateObjectError,
+			"type %q already exists", tree.ErrString(&n.n.Name))
+	} else if err != nil {
+		return err
+	}
+
+	id, err := GenerateUniqueDescID(params.ctx, params.p.ExecCfg().DB)
+	if err != nil {
+		return err
+	}
+
+	// Inherit permissions from the database descriptor.
+	desc := sqlbase.TypeDescriptor{
+		Name:        n.n.Name.Table(),
+		ParentID:    n.dbDesc.ID,
+		EnumMembers: sqlbase.MakeEnumMembers(n.n.EnumLabels),
+		Privileges:  n.dbDesc.GetPrivileges(),
+	}
+	if err := params.p.createDescriptorWithID(
+		params.ctx, tKey.Key(), id, &desc, params.EvalContext().Settings); err != nil {
+		return err
+	}
+	if err := desc.Validate(); err != nil {
+		return err
+	}
+
+	// Log Create Type event. This is an auditable log event and is
+	// recorded in the same transaction as the type descriptor update.
+	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+		params.ctx,
+		params.p.txn,
+		EventLogCreateType,
+		int32(desc.ID),
+		int32(params.extendedEvalCtx.NodeID),
+		struct {
+			TypeName  string
+			Statement string
+			User      string
+		}{n.n.Name.FQString(), n.n.String(), params.SessionData().User},
+	)
+}
+
+func (*createTypeNode) Next(runParams) (bool, error) { return false, nil }
+func (*createTypeNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createTypeNode) Close(context.Context)        {}
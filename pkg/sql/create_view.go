@@ -19,8 +19,9 @@ import (
 	"fmt"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/coltypes"
-	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -36,6 +37,8 @@ type createViewNode struct {
 	// depends on. This is collected during the construction of
 	// the view query's logical plan.
 	planDeps planDependencies
+	// sourcePlan computes the contents of a materialized view.
+	sourcePlan planNode
 }
 
 // CreateView creates a view.
@@ -44,12 +47,22 @@ type createViewNode struct {
 //						selected columns.
 //          mysql requires CREATE VIEW plus SELECT on all the selected columns.
 func (p *planner) CreateView(ctx context.Context, n *tree.CreateView) (planNode, error) {
+	var err error
+	explicitSchema := n.Name.ExplicitSchema
+	n.Temporary, err = sqlbase.QualifyTemporaryTableName(&n.Name, n.Temporary)
+	if err != nil {
+		return nil, err
+	}
+	if n.Temporary && n.Materialized {
+		return nil, pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+			"materialized views cannot be temporary")
+	}
 	dbDesc, err := p.ResolveUncachedDatabase(ctx, &n.Name)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+	if err := p.checkCreatePrivilege(ctx, dbDesc, &n.Name); err != nil {
 		return nil, err
 	}
 
@@ -65,6 +78,27 @@ func (p *planner) CreateView(ctx context.Context, n *tree.CreateView) (planNode,
 		return nil, err
 	}
 
+	// Views that depend on temporary objects are temporary, as in Postgres.
+	if !n.Temporary {
+		for _, dep := range planDeps {
+			if !dep.desc.IsTemporary() {
+				continue
+			}
+			if n.Materialized {
+				return nil, pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+					"materialized views must not use temporary tables or views")
+			}
+			if explicitSchema {
+				return nil, pgerror.NewErrorf(pgerror.CodeInvalidTableDefinitionError,
+					"cannot create permanent view %q because it depends on temporary table %q",
+					tree.ErrString(&n.Name), dep.desc.Name)
+			}
+			n.Name.SchemaName = sessiondata.PgTempSchemaName
+			n.Temporary = true
+			break
+		}
+	}
+
 	// Ensure that all the table names pretty-print as fully qualified,
 	// so we store that in the view descriptor.
 	//
@@ -101,17 +135,29 @@ func (p *planner) CreateView(ctx context.Context, n *tree.CreateView) (planNode,
 
 	log.VEventf(ctx, 2, "collected view dependencies:\n%s", planDeps.String())
 
+	var sourcePlan planNode
+	if n.Materialized {
+		if sourcePlan, err = p.planMaterializedViewQuery(ctx, n.AsSource); err != nil {
+			return nil, err
+		}
+	}
+
 	return &createViewNode{
 		n:             n,
 		dbDesc:        dbDesc,
 		sourceColumns: sourceColumns,
 		planDeps:      planDeps,
+		sourcePlan:    sourcePlan,
 	}, nil
 }
 
 func (n *createViewNode) startExec(params runParams) error {
 	viewName := n.n.Name.Table()
-	tKey := tableKey{parentID: n.dbDesc.ID, name: viewName}
+	parentID, temporarySchemaID, scDesc, err := params.p.getCreateParentID(params.ctx, n.dbDesc.ID, &n.n.Name)
+	if err != nil {
+		return err
+	}
+	tKey := tableKey{parentID: parentID, name: viewName}
 	key := tKey.Key()
 	if exists, err := descExists(params.ctx, params.p.txn, key); err == nil && exists {
 		// TODO(a-robinson): Support CREATE OR REPLACE commands.
@@ -125,8 +171,8 @@ func (n *createViewNode) startExec(params runParams) error {
 		return err
 	}
 
-	// Inherit permissions from the database descriptor.
-	privs := n.dbDesc.GetPrivileges()
+	// Inherit permissions from the schema or database descriptor.
+	privs := inheritedPrivileges(n.dbDesc, scDesc)
 
 	desc, err := n.makeViewTableDesc(
 		params,
@@ -140,6 +186,8 @@ func (n *createViewNode) startExec(params runParams) error {
 	if err != nil {
 		return err
 	}
+	desc.TemporarySchemaID = temporarySchemaID
+	desc.SchemaID = scDesc.GetID()
 
 	// Collect all the tables/views this view depends on.
 	for backrefID := range n.planDeps {
@@ -176,6 +224,12 @@ func (n *createViewNode) startExec(params runParams) error {
 		return err
 	}
 
+	if n.n.Materialized {
+		if err := insertMaterializedViewRows(params, &desc, n.sourcePlan); err != nil {
+			return err
+		}
+	}
+
 	// Log Create View event. This is an auditable log event and is
 	// recorded in the same transaction as the table descriptor update.
 	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
@@ -194,7 +248,13 @@ func (n *createViewNode) startExec(params runParams) error {
 
 func (*createViewNode) Next(runParams) (bool, error) { return false, nil }
 func (*createViewNode) Values() tree.Datums          { return tree.Datums{} }
-func (n *createViewNode) Close(ctx context.Context)  {}
+
+func (n *createViewNode) Close(ctx context.Context) {
+	if n.sourcePlan != nil {
+		n.sourcePlan.Close(ctx)
+		n.sourcePlan = nil
+	}
+}
 
 // makeViewTableDesc returns the table descriptor for a new view.
 //
@@ -215,6 +275,9 @@ func (n *createViewNode) makeViewTableDesc(
 	desc := InitTableDescriptor(id, parentID, viewName,
 		params.p.txn.CommitTimestamp(), privileges)
 	desc.ViewQuery = tree.AsStringWithFlags(n.n.AsSource, tree.FmtParsable)
+	// A materialized view is stored like a table, so AllocateIDs gives it a
+	// primary index on a hidden rowid column.
+	desc.IsMaterializedView = n.n.Materialized
 	for i, colRes := range resultColumns {
 		colType, err := coltypes.DatumTypeToColumnType(colRes.Typ)
 		if err != nil {
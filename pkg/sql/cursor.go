@@ -0,0 +1,636 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/distsqlrun"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/fsm"
+)
+
+// cursorRows holds the results of a query for the lifetime of a SQL cursor or
+// of a suspended portal. The rows are kept in memory up to the
+// sql.distsql.temp_storage.workmem budget and spill to temporary storage
+// beyond that, so that cursors over large results can be read in chunks.
+type cursorRows struct {
+	cols  sqlbase.ResultColumns
+	types []sqlbase.ColumnType
+	rows  *distsqlrun.SpillingRowContainer
+
+	// it is the iterator used to read the rows, created on the first read, and
+	// itIdx is the index of the row it points to.
+	it    distsqlrun.RowIterator
+	itIdx int
+
+	encRow sqlbase.EncDatumRow
+	alloc  sqlbase.DatumAlloc
+}
+
+// newCursorRows creates an empty cursorRows for rows with the given columns.
+// Its memory is accounted for by the session monitor.
+func (ex *connExecutor) newCursorRows(
+	ctx context.Context, cols sqlbase.ResultColumns,
+) (*cursorRows, error) {
+	types := make([]sqlbase.ColumnType, len(cols))
+	for i := range cols {
+		typ, err := sqlbase.DatumTypeToColumnType(cols[i].Typ)
+		if err != nil {
+			return nil, err
+		}
+		types[i] = typ
+	}
+	distSQLCfg := &ex.server.cfg.DistSQLSrv.ServerConfig
+	return &cursorRows{
+		cols:  cols,
+		types: types,
+		rows: distsqlrun.NewSpillingRowContainer(
+			ctx, ex.server.cfg.Settings, types,
+			distSQLCfg.TempStorage, ex.sessionMon, distSQLCfg.DiskMonitor,
+		),
+		encRow: make(sqlbase.EncDatumRow, len(cols)),
+	}, nil
+}
+
+// Len returns the number of rows.
+func (r *cursorRows) Len() int {
+	return r.rows.Len()
+}
+
+// addRow appends a row. Rows can't be added once reading has started.
+func (r *cursorRows) addRow(ctx context.Context, row tree.Datums) error {
+	for i, d := range row {
+		r.encRow[i] = sqlbase.DatumToEncDatum(r.types[i], d)
+	}
+	return r.rows.AddRow(ctx, r.encRow)
+}
+
+// row returns the row with the given index, counting from 0. Reading rows in
+// increasing order of index is cheap; reading a row that comes before the
+// previous one read rewinds the underlying iterator.
+func (r *cursorRows) row(ctx context.Context, idx int) (tree.Datums, error) {
+	if r.it == nil {
+		r.it = r.rows.NewIterator(ctx)
+		r.it.Rewind()
+		r.itIdx = 0
+	} else if idx < r.itIdx {
+		r.it.Rewind()
+		r.itIdx = 0
+	}
+	for ; r.itIdx < idx; r.itIdx++ {
+		r.it.Next()
+	}
+	if ok, err := r.it.Valid(); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, pgerror.NewAssertionErrorf("row %d out of range for cursor of %d rows", idx, r.Len())
+	}
+	encRow, err := r.it.Row()
+	if err != nil {
+		return nil, err
+	}
+	row := make(tree.Datums, len(encRow))
+	for i := range encRow {
+		if err := encRow[i].EnsureDecoded(&r.types[i], &r.alloc); err != nil {
+			return nil, err
+		}
+		row[i] = encRow[i].Datum
+	}
+	return row, nil
+}
+
+// close releases the rows.
+func (r *cursorRows) close(ctx context.Context) {
+	if r.it != nil {
+		r.it.Close()
+		r.it = nil
+	}
+	r.rows.Close(ctx)
+}
+
+// sqlCursor is a cursor created by DECLARE. The query of a cursor is run to
+// completion when the cursor is declared, so the cursor does not see changes
+// made afterwards, as for INSENSITIVE cursors in Postgres. Since the rows are
+// materialized, every cursor can scroll backwards unless it was explicitly
+// declared NO SCROLL.
+type sqlCursor struct {
+	*cursorRows
+
+	name     string
+	noScroll bool
+	hold     bool
+
+	// pos is the position of the cursor. As in Postgres, 0 is before the first
+	// row, 1 to Len() are the positions of the rows and Len()+1 is after the
+	// last row.
+	pos int
+
+	// committed is set once the transaction that declared a WITH HOLD cursor
+	// commits. Changes made to a committed cursor by a transaction are undone
+	// if the transaction aborts, or is retried: committedPos is its position as
+	// of the last commit, and closed is set if it was closed by the current
+	// transaction.
+	committed    bool
+	committedPos int
+	closed       bool
+}
+
+// scan determines the rows read by a FETCH statement with the given
+// arguments: count rows starting at position first, in increasing order of
+// position if step is 1 and in decreasing order if it is -1. It also returns
+// the position of the cursor after the FETCH.
+func (c *sqlCursor) scan(s *tree.CursorStmt) (first, step, count, newPos int, _ error) {
+	n := int64(c.Len())
+	pos := int64(c.pos)
+	// clamp bounds a position to the valid range and, if it's the position of a
+	// row, reads that row.
+	clamp := func(target int64) {
+		switch {
+		case target <= 0:
+			newPos = 0
+		case target > n:
+			newPos = int(n) + 1
+		default:
+			first, count, newPos = int(target), 1, int(target)
+		}
+	}
+
+	step = 1
+	switch s.Direction {
+	case tree.FetchForward, tree.FetchBackward:
+		k := s.Count
+		if s.All {
+			k = math.MaxInt64
+		}
+		backward := s.Direction == tree.FetchBackward
+		if k < 0 {
+			backward, k = !backward, -k
+		}
+		if k == 0 {
+			// FORWARD 0 and BACKWARD 0 re-read the current row.
+			clamp(pos)
+			newPos = int(pos)
+			break
+		}
+		var avail int64
+		if backward {
+			step = -1
+			first = int(pos) - 1
+			if avail = pos - 1; avail < 0 {
+				avail = 0
+			}
+		} else {
+			first = int(pos) + 1
+			if avail = n - pos; avail < 0 {
+				avail = 0
+			}
+		}
+		switch {
+		case k <= avail:
+			count = int(k)
+			newPos = int(pos) + step*count
+		case backward:
+			count, newPos = int(avail), 0
+		default:
+			count, newPos = int(avail), int(n)+1
+		}
+	case tree.FetchAbsolute:
+		if s.Count >= 0 {
+			clamp(s.Count)
+		} else {
+			clamp(n + 1 + s.Count)
+		}
+	case tree.FetchRelative:
+		switch {
+		case s.Count == 0:
+			clamp(pos)
+			newPos = int(pos)
+		case s.Count > n+1:
+			// Avoid overflowing below.
+			clamp(n + 1)
+		default:
+			clamp(pos + s.Count)
+		}
+	}
+
+	if c.noScroll && (newPos < c.pos || (count > 0 && first < c.pos)) {
+		return 0, 0, 0, 0, pgerror.NewError(pgerror.CodeObjectNotInPrerequisiteStateError,
+			"cursor can only scan forward").SetHintf(
+			"Declare it with SCROLL option to enable backward scan.")
+	}
+	return first, step, count, newPos, nil
+}
+
+// cursorNamespace contains the SQL cursors of a session, by name.
+type cursorNamespace map[string]*sqlCursor
+
+// get returns the open cursor with the given name, or nil if there is none.
+func (ns cursorNamespace) get(name string) *sqlCursor {
+	if c, ok := ns[name]; ok && !c.closed {
+		return c
+	}
+	return nil
+}
+
+// add registers a new cursor. A cursor with the same name that was closed by
+// the current transaction is released for good.
+func (ns cursorNamespace) add(ctx context.Context, c *sqlCursor) {
+	if old, ok := ns[c.name]; ok {
+		old.close(ctx)
+	}
+	ns[c.name] = c
+}
+
+// close closes the cursor with the given name. It returns false if there is
+// no such cursor.
+func (ns cursorNamespace) close(ctx context.Context, name string) bool {
+	c := ns.get(name)
+	if c == nil {
+		return false
+	}
+	if c.committed {
+		// The cursor is released when the transaction commits.
+		c.closed = true
+	} else {
+		c.close(ctx)
+		delete(ns, name)
+	}
+	return true
+}
+
+// closeAll closes all the open cursors.
+func (ns cursorNamespace) closeAll(ctx context.Context) {
+	for name := range ns {
+		ns.close(ctx, name)
+	}
+}
+
+// commitTxn is called when a transaction commits. Cursors declared without
+// WITH HOLD don't survive their transaction.
+func (ns cursorNamespace) commitTxn(ctx context.Context) {
+	for name, c := range ns {
+		if !c.hold || c.closed {
+			c.close(ctx)
+			delete(ns, name)
+			continue
+		}
+		c.committed = true
+		c.committedPos = c.pos
+	}
+}
+
+// abortTxn is called when a transaction aborts or restarts. The cursors
+// declared by the transaction are closed and the others are restored to
+// their state as of the last commit.
+func (ns cursorNamespace) abortTxn(ctx context.Context) {
+	for name, c := range ns {
+		if !c.committed {
+			c.close(ctx)
+			delete(ns, name)
+			continue
+		}
+		c.pos = c.committedPos
+		c.closed = false
+	}
+}
+
+// release frees all the cursors, including the ones that the current
+// transaction closed. It is called when the session ends.
+func (ns cursorNamespace) release(ctx context.Context) {
+	for name, c := range ns {
+		c.close(ctx)
+		delete(ns, name)
+	}
+}
+
+// sqlCursorsAccessor gives a planner access to the SQL cursors of a session.
+type sqlCursorsAccessor interface {
+	// Get returns the open cursor with the given name, or nil if there is none.
+	Get(name string) *sqlCursor
+	// Add registers a new cursor. There must not be an open cursor with the
+	// same name.
+	Add(ctx context.Context, c *sqlCursor)
+	// Close closes the cursor with the given name. It returns false if there
+	// is no such cursor.
+	Close(ctx context.Context, name string) bool
+	// CloseAll closes all the open cursors.
+	CloseAll(ctx context.Context)
+	// NewRows creates an empty container for the rows of a cursor.
+	NewRows(ctx context.Context, cols sqlbase.ResultColumns) (*cursorRows, error)
+}
+
+type declareCursorNode struct {
+	n *tree.DeclareCursor
+	// sourcePlan computes the rows of the cursor.
+	sourcePlan planNode
+}
+
+// DeclareCursor creates a SQL cursor.
+// See https://www.postgresql.org/docs/current/static/sql-declare.html for details.
+// Privileges: None, besides the ones needed to run the query.
+func (p *planner) DeclareCursor(ctx context.Context, n *tree.DeclareCursor) (planNode, error) {
+	if n.Binary {
+		return nil, pgerror.Unimplemented("declare binary", "DECLARE BINARY CURSOR is not supported")
+	}
+	if !n.Hold && p.EvalContext().TxnImplicit {
+		return nil, pgerror.NewError(pgerror.CodeNoActiveSQLTransactionError,
+			"DECLARE CURSOR can only be used in transaction blocks")
+	}
+	sourcePlan, err := p.Select(ctx, n.Select, nil /* desiredTypes */)
+	if err != nil {
+		return nil, err
+	}
+	sourcePlan, err = p.hideHiddenColumns(ctx, sourcePlan, planColumns(sourcePlan))
+	if err != nil {
+		sourcePlan.Close(ctx)
+		return nil, err
+	}
+	return &declareCursorNode{n: n, sourcePlan: sourcePlan}, nil
+}
+
+func (n *declareCursorNode) startExec(params runParams) error {
+	p := params.p
+	name := string(n.n.Name)
+	if p.sqlCursors.Get(name) != nil {
+		return pgerror.NewErrorf(pgerror.CodeDuplicateCursorError, "cursor %q already exists", name)
+	}
+	rows, err := p.sqlCursors.NewRows(params.ctx, planColumns(n.sourcePlan))
+	if err != nil {
+		return err
+	}
+	for {
+		if err := p.cancelChecker.Check(); err != nil {
+			rows.close(params.ctx)
+			return err
+		}
+		if next, err := n.sourcePlan.Next(params); !next {
+			if err != nil {
+				rows.close(params.ctx)
+				return err
+			}
+			break
+		}
+		if err := rows.addRow(params.ctx, n.sourcePlan.Values()); err != nil {
+			rows.close(params.ctx)
+			return err
+		}
+	}
+	p.sqlCursors.Add(params.ctx, &sqlCursor{
+		cursorRows: rows,
+		name:       name,
+		noScroll:   n.n.Scroll == tree.NoScroll,
+		hold:       n.n.Hold,
+	})
+	return nil
+}
+
+func (*declareCursorNode) Next(runParams) (bool, error) { return false, nil }
+func (*declareCursorNode) Values() tree.Datums          { return tree.Datums{} }
+
+func (n *declareCursorNode) Close(ctx context.Context) {
+	if n.sourcePlan != nil {
+		n.sourcePlan.Close(ctx)
+		n.sourcePlan = nil
+	}
+}
+
+// fetchNode implements FETCH and MOVE.
+type fetchNode struct {
+	n      *tree.CursorStmt
+	cursor *sqlCursor
+	// move is set for MOVE, which repositions the cursor like FETCH but
+	// doesn't return any rows.
+	move    bool
+	columns sqlbase.ResultColumns
+
+	run struct {
+		// next is the position of the next row to return and step is added to
+		// it after each row; remaining is the number of rows left to return.
+		next, step, remaining int
+		count                 int
+		row                   tree.Datums
+	}
+}
+
+// FetchCursor implements the FETCH statement.
+// See https://www.postgresql.org/docs/current/static/sql-fetch.html for details.
+func (p *planner) FetchCursor(ctx context.Context, n *tree.FetchCursor) (planNode, error) {
+	c, err := p.lookupCursor(n.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &fetchNode{n: &n.CursorStmt, cursor: c, columns: c.cols}, nil
+}
+
+// MoveCursor implements the MOVE statement.
+// See https://www.postgresql.org/docs/current/static/sql-move.html for details.
+func (p *planner) MoveCursor(ctx context.Context, n *tree.MoveCursor) (planNode, error) {
+	c, err := p.lookupCursor(n.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &fetchNode{n: &n.CursorStmt, cursor: c, move: true}, nil
+}
+
+func (p *planner) lookupCursor(name tree.Name) (*sqlCursor, error) {
+	c := p.sqlCursors.Get(string(name))
+	if c == nil {
+		return nil, pgerror.NewErrorf(pgerror.CodeInvalidCursorNameError,
+			"cursor %q does not exist", string(name))
+	}
+	return c, nil
+}
+
+func (n *fetchNode) startExec(params runParams) error {
+	first, step, count, newPos, err := n.cursor.scan(n.n)
+	if err != nil {
+		return err
+	}
+	n.cursor.pos = newPos
+	n.run.next, n.run.step, n.run.remaining = first, step, count
+	n.run.count = count
+	return nil
+}
+
+func (n *fetchNode) Next(params runParams) (bool, error) {
+	if n.move || n.run.remaining == 0 {
+		return false, nil
+	}
+	row, err := n.cursor.row(params.ctx, n.run.next-1)
+	if err != nil {
+		return false, err
+	}
+	n.run.row = row
+	n.run.next += n.run.step
+	n.run.remaining--
+	return true, nil
+}
+
+func (n *fetchNode) Values() tree.Datums { return n.run.row }
+
+func (*fetchNode) Close(context.Context) {}
+
+// FastPathResults implements the planNodeFastPath interface.
+func (n *fetchNode) FastPathResults() (int, bool) {
+	return n.run.count, n.move
+}
+
+// CloseCursor implements the CLOSE statement.
+// See https://www.postgresql.org/docs/current/static/sql-close.html for details.
+func (p *planner) CloseCursor(ctx context.Context, n *tree.CloseCursor) (planNode, error) {
+	if n.Name == "" {
+		p.sqlCursors.CloseAll(ctx)
+	} else if !p.sqlCursors.Close(ctx, string(n.Name)) {
+		return nil, pgerror.NewErrorf(pgerror.CodeInvalidCursorNameError,
+			"cursor %q does not exist", string(n.Name))
+	}
+	return newZeroNode(nil /* columns */), nil
+}
+
+// suspendedPortal holds the rows that a portal executed with a row limit has
+// yet to return.
+type suspendedPortal struct {
+	// rows is nil once all the rows have been returned.
+	rows *cursorRows
+	// next is the index of the next row to return.
+	next int
+}
+
+// limitedCommandResult is used to execute a portal with a row limit inside an
+// explicit transaction. The first limit rows are passed on to the client and
+// the rest are buffered, so that the portal can be suspended and return them
+// when it is executed again.
+type limitedCommandResult struct {
+	CommandResult
+
+	ex    *connExecutor
+	limit int
+	cols  sqlbase.ResultColumns
+	// rows buffers the rows past the limit. It is created when the first such
+	// row is produced.
+	rows *cursorRows
+	// err is set if buffering a row failed. Errors from AddRow are taken to be
+	// communication errors that close the connection, so it is reported as an
+	// execution error instead.
+	err error
+}
+
+var _ RestrictedCommandResult = &limitedCommandResult{}
+
+// SetColumns is part of the RestrictedCommandResult interface.
+func (r *limitedCommandResult) SetColumns(ctx context.Context, cols sqlbase.ResultColumns) {
+	r.cols = cols
+	r.CommandResult.SetColumns(ctx, cols)
+}
+
+// AddRow is part of the RestrictedCommandResult interface.
+func (r *limitedCommandResult) AddRow(ctx context.Context, row tree.Datums) error {
+	if r.err != nil {
+		return nil
+	}
+	if r.rows == nil {
+		if r.CommandResult.RowsAffected() < r.limit {
+			return r.CommandResult.AddRow(ctx, row)
+		}
+		if r.rows, r.err = r.ex.newCursorRows(ctx, r.cols); r.err != nil {
+			return nil
+		}
+	}
+	r.err = r.rows.addRow(ctx, row)
+	return nil
+}
+
+// SetError is part of the RestrictedCommandResult interface.
+func (r *limitedCommandResult) SetError(err error) {
+	if r.err == nil {
+		r.CommandResult.SetError(err)
+	}
+}
+
+// OverwriteError is part of the RestrictedCommandResult interface.
+func (r *limitedCommandResult) OverwriteError(err error) {
+	if r.err != nil {
+		r.err = err
+	} else {
+		r.CommandResult.OverwriteError(err)
+	}
+}
+
+// Err is part of the RestrictedCommandResult interface.
+func (r *limitedCommandResult) Err() error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.CommandResult.Err()
+}
+
+// suspend is called once the portal's statement has executed. If rows were
+// buffered and the execution succeeded, the portal is suspended.
+func (r *limitedCommandResult) suspend(ctx context.Context, portal *PreparedPortal, failed bool) {
+	if r.rows == nil {
+		return
+	}
+	if failed || r.Err() != nil {
+		r.rows.close(ctx)
+		r.rows = nil
+		return
+	}
+	portal.suspended = &suspendedPortal{rows: r.rows}
+	r.CommandResult.SetPortalSuspended()
+}
+
+// canSuspendPortal returns whether a portal executed with the given row limit
+// can be suspended when it reaches the limit. This is the case for queries
+// returning rows in explicit transactions; in implicit transactions, the
+// portal would not survive the end of the transaction.
+func (ex *connExecutor) canSuspendPortal(portal *PreparedPortal, limit int) bool {
+	if limit <= 0 || portal.Stmt.AST.StatementType() != tree.Rows {
+		return false
+	}
+	_, inOpen := ex.machine.CurState().(stateOpen)
+	return inOpen && !ex.implicitTxn()
+}
+
+// resumePortal returns the next rows of a suspended portal, up to limit if it
+// is not 0. The portal is suspended again if it has rows left afterwards; if
+// not, executing it again returns no rows, like in Postgres.
+func (ex *connExecutor) resumePortal(
+	ctx context.Context, s *suspendedPortal, res CommandResult, limit int,
+) (fsm.Event, fsm.EventPayload, error) {
+	if s.rows == nil {
+		return nil, nil, nil
+	}
+	res.SetColumns(ctx, s.rows.cols)
+	for ; s.next < s.rows.Len(); s.next++ {
+		if limit != 0 && res.RowsAffected() == limit {
+			res.SetPortalSuspended()
+			return nil, nil, nil
+		}
+		row, err := s.rows.row(ctx, s.next)
+		if err != nil {
+			return eventNonRetriableErr{IsCommit: fsm.False}, eventNonRetriableErrPayload{err: err}, nil
+		}
+		if err := res.AddRow(ctx, row); err != nil {
+			return nil, nil, err
+		}
+	}
+	s.rows.close(ctx)
+	s.rows = nil
+	return nil, nil, nil
+}
@@ -123,7 +123,11 @@ func (p *planner) getDataSource(
 		}
 
 		colCfg := scanColumnsConfig{visibility: scanVisibility}
-		return p.getPlanForDesc(ctx, desc, tn, indexFlags, colCfg)
+		ds, err = p.getPlanForDesc(ctx, desc, tn, indexFlags, colCfg)
+		if err != nil {
+			return ds, err
+		}
+		return ds, p.lockScan(ctx, ds, tn.TableName)
 
 	case *tree.RowsFromExpr:
 		return p.getPlanForRowsFrom(ctx, t.Items...)
@@ -171,6 +175,12 @@ func (p *planner) getDataSource(
 			indexFlags = t.IndexFlags
 		}
 
+		if t.As.Alias != "" && len(p.lockingClause) > 0 {
+			// The locking clause refers to an aliased table by its alias.
+			defer func(prev tree.LockingClause) { p.lockingClause = prev }(p.lockingClause)
+			p.lockingClause = p.lockingClause.ForAlias(t.As.Alias)
+		}
+
 		src, err := p.getDataSource(ctx, t.Expr, indexFlags, scanVisibility)
 		if err != nil {
 			return src, err
@@ -238,6 +248,13 @@ func (p *planner) getTableScanByRef(
 	if err != nil {
 		return src, err
 	}
+	name := tn.TableName
+	if tref.As.Alias != "" {
+		name = tref.As.Alias
+	}
+	if err := p.lockScan(ctx, src, name); err != nil {
+		return src, err
+	}
 
 	return renameSource(src, tref.As, true)
 }
@@ -307,7 +324,7 @@ func (p *planner) getPlanForDesc(
 	indexFlags *tree.IndexFlags,
 	colCfg scanColumnsConfig,
 ) (planDataSource, error) {
-	if desc.IsView() {
+	if desc.IsView() && !desc.MaterializedView() {
 		if colCfg.wantedColumns != nil {
 			return planDataSource{},
 				errors.Errorf("cannot specify an explicit column list when accessing a view by reference")
@@ -317,7 +334,7 @@ func (p *planner) getPlanForDesc(
 	if desc.IsSequence() {
 		return p.getSequenceSource(ctx, *tn, desc)
 	}
-	if !desc.IsTable() {
+	if !desc.IsTable() && !desc.MaterializedView() {
 		return planDataSource{}, errors.Errorf(
 			"unexpected table descriptor of type %s for %q", desc.TypeName(), tree.ErrString(tn))
 	}
@@ -135,6 +135,7 @@ func (p *planner) Delete(
 	if err != nil {
 		return nil, err
 	}
+	rd.SetDeferredFKChecks(p.deferredFKChecks())
 
 	tracing.AnnotateTrace()
 
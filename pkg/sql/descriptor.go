@@ -38,6 +38,8 @@ import (
 var (
 	errEmptyDatabaseName = pgerror.NewError(pgerror.CodeSyntaxError, "empty database name")
 	errNoDatabase        = pgerror.NewError(pgerror.CodeInvalidNameError, "no database specified")
+	errNoSchema          = pgerror.NewError(pgerror.CodeInvalidNameError, "no schema specified")
+	errNoFunction        = pgerror.NewError(pgerror.CodeInvalidNameError, "no function specified")
 	errNoTable           = pgerror.NewError(pgerror.CodeInvalidNameError, "no table specified")
 	errNoMatch           = pgerror.NewError(pgerror.CodeUndefinedObjectError, "no object matched")
 )
@@ -164,6 +166,11 @@ func getDescriptor(
 	}
 
 	if err := getDescriptorByID(ctx, txn, sqlbase.ID(gr.ValueInt()), descriptor); err != nil {
+		if err == sqlbase.ErrDescriptorNotFound {
+			// The name belongs to a descriptor of a different kind, e.g. a
+			// table name lookup that found a type.
+			return false, nil
+		}
 		return false, err
 	}
 	return true, nil
@@ -188,6 +195,10 @@ func getDescriptorByID(
 	case *sqlbase.TableDescriptor:
 		table := desc.GetTable()
 		if table == nil {
+			if desc.GetType() != nil || desc.GetSchema() != nil || desc.GetFunction() != nil {
+				// Tables, types, schemas and functions share a namespace.
+				return sqlbase.ErrDescriptorNotFound
+			}
 			return errors.Errorf("%q is not a table", desc.String())
 		}
 		table.MaybeFillInDescriptor()
@@ -206,6 +217,45 @@ func getDescriptorByID(
 			return err
 		}
 		*t = *database
+	case *sqlbase.TypeDescriptor:
+		typ := desc.GetType()
+		if typ == nil {
+			if desc.GetTable() != nil || desc.GetSchema() != nil || desc.GetFunction() != nil {
+				return sqlbase.ErrDescriptorNotFound
+			}
+			return errors.Errorf("%q is not a type", desc.String())
+		}
+
+		if err := typ.Validate(); err != nil {
+			return err
+		}
+		*t = *typ
+	case *sqlbase.SchemaDescriptor:
+		schema := desc.GetSchema()
+		if schema == nil {
+			if desc.GetTable() != nil || desc.GetType() != nil || desc.GetFunction() != nil {
+				return sqlbase.ErrDescriptorNotFound
+			}
+			return errors.Errorf("%q is not a schema", desc.String())
+		}
+
+		if err := schema.Validate(); err != nil {
+			return err
+		}
+		*t = *schema
+	case *sqlbase.FunctionDescriptor:
+		function := desc.GetFunction()
+		if function == nil {
+			if desc.GetTable() != nil || desc.GetType() != nil || desc.GetSchema() != nil {
+				return sqlbase.ErrDescriptorNotFound
+			}
+			return errors.Errorf("%q is not a function", desc.String())
+		}
+
+		if err := function.Validate(); err != nil {
+			return err
+		}
+		*t = *function
 	}
 	return nil
 }
@@ -230,6 +280,12 @@ func GetAllDescriptors(ctx context.Context, txn *client.Txn) ([]sqlbase.Descript
 			descs[i] = desc.GetTable()
 		case *sqlbase.Descriptor_Database:
 			descs[i] = desc.GetDatabase()
+		case *sqlbase.Descriptor_Type:
+			descs[i] = desc.GetType()
+		case *sqlbase.Descriptor_Schema:
+			descs[i] = desc.GetSchema()
+		case *sqlbase.Descriptor_Function:
+			descs[i] = desc.GetFunction()
 		default:
 			return nil, errors.Errorf("Descriptor.Union has unexpected type %T", t)
 		}
@@ -38,6 +38,21 @@ func (p *planner) Discard(ctx context.Context, s *tree.Discard) (planNode, error
 
 		// DEALLOCATE ALL
 		p.preparedStatements.DeleteAll(ctx)
+
+		// CLOSE ALL
+		p.sqlCursors.CloseAll(ctx)
+
+		// UNLISTEN *
+		p.notifications.unlisten("")
+
+		// DISCARD TEMP
+		if err := p.discardTemporarySchemas(ctx, s.String()); err != nil {
+			return nil, err
+		}
+	case tree.DiscardModeTemp:
+		if err := p.discardTemporarySchemas(ctx, s.String()); err != nil {
+			return nil, err
+		}
 	default:
 		return nil, pgerror.NewAssertionErrorf("unknown mode for DISCARD: %d", s.Mode)
 	}
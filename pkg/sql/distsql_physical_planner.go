@@ -202,9 +202,14 @@ func (v *distSQLExprCheckVisitor) VisitPre(expr tree.Expr) (recurse bool, newExp
 	case *tree.DOid:
 		v.err = newQueryNotSupportedError("OID expressions are not supported by distsql")
 		return false, expr
+	case *tree.DEnum:
+		// The remote nodes cannot resolve the type of the constant when they
+		// parse the expression.
+		v.err = newQueryNotSupportedError("ENUM constants are not supported by distsql")
+		return false, expr
 	case *tree.CastExpr:
 		switch t.Type.(type) {
-		case *coltypes.TOid:
+		case *coltypes.TOid, *coltypes.TUserDefined:
 			v.err = newQueryNotSupportedErrorf("cast to %s is not supported by distsql", t.Type)
 			return false, expr
 		}
@@ -360,6 +365,14 @@ func (dsp *DistSQLPlanner) checkSupportForNode(node planNode) (distRecommendatio
 		return rec, nil
 
 	case *scanNode:
+		if n.lockingStrength != roachpb.LOCK_NONE {
+			// Locking scans write intents, which only the root transaction on
+			// the gateway can do: leaf transactions allocate sequence numbers
+			// independently of each other and of the root, so the intents they
+			// wrote could not be told apart, e.g. when rolling back to a
+			// savepoint.
+			return cannotDistribute, newQueryNotSupportedError("locking scans cannot be distributed")
+		}
 		rec := canDistribute
 		if n.softLimit != 0 {
 			// We don't yet recommend distributing plans where soft limits propagate
@@ -845,6 +858,9 @@ func initTableReaderSpec(
 		IsCheck:    n.run.isCheck,
 		Visibility: n.colCfg.visibility.toDistSQLScanVisibility(),
 
+		LockingStrength:   n.lockingStrength,
+		LockingWaitPolicy: n.lockingWaitPolicy,
+
 		// Retain the capacity of the spans slice.
 		Spans: s.Spans[:0],
 	}
@@ -1273,8 +1289,11 @@ func (dsp *DistSQLPlanner) addAggregators(
 		}
 		aggregations[i].Func = distsqlpb.AggregatorSpec_Func(funcIdx)
 		aggregations[i].Distinct = fholder.isDistinct()
-		if fholder.argRenderIdx != noRenderIdx {
-			aggregations[i].ColIdx = []uint32{uint32(p.PlanToStreamColMap[fholder.argRenderIdx])}
+		if len(fholder.argRenderIdxs) > 0 {
+			aggregations[i].ColIdx = make([]uint32, len(fholder.argRenderIdxs))
+			for j, renderIdx := range fholder.argRenderIdxs {
+				aggregations[i].ColIdx[j] = uint32(p.PlanToStreamColMap[renderIdx])
+			}
 		}
 		if fholder.hasFilter() {
 			col := uint32(p.PlanToStreamColMap[fholder.filterRenderIdx])
@@ -1322,6 +1341,8 @@ func (dsp *DistSQLPlanner) addAggregators(
 	//  - we have a mix of aggregations that use distinct and aggregations that
 	//    don't use distinct. TODO(arjun): This would require doing the same as
 	//    the todo as above.
+	//  - no aggregation function has constant arguments, which are not passed
+	//    to the local stage.
 	multiStage := false
 	allDistinct := true
 	anyDistinct := false
@@ -1352,6 +1373,10 @@ func (dsp *DistSQLPlanner) addAggregators(
 				multiStage = false
 				break
 			}
+			if len(e.Arguments) > 0 {
+				multiStage = false
+				break
+			}
 		}
 	}
 	if !anyDistinct {
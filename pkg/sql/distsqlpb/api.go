@@ -57,6 +57,8 @@ func MakeEvalContext(evalCtx tree.EvalContext) EvalContext {
 		ApplicationName:    evalCtx.SessionData.ApplicationName,
 		BytesEncodeFormat:  be,
 		ExtraFloatDigits:   int32(evalCtx.SessionData.DataConversion.ExtraFloatDigits),
+
+		DefaultTextSearchConfig: evalCtx.SessionData.DefaultTextSearchConfig,
 	}
 
 	// Populate the search path. Make sure not to include the implicit pg_catalog,
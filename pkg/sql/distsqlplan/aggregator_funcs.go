@@ -178,6 +178,48 @@ var DistAggregationTable = map[distsqlpb.AggregatorSpec_Func]DistAggregationInfo
 		},
 	},
 
+	distsqlpb.AggregatorSpec_BIT_AND: {
+		LocalStage: []distsqlpb.AggregatorSpec_Func{distsqlpb.AggregatorSpec_BIT_AND},
+		FinalStage: []FinalStageInfo{
+			{
+				Fn:        distsqlpb.AggregatorSpec_BIT_AND,
+				LocalIdxs: passThroughLocalIdxs,
+			},
+		},
+	},
+
+	distsqlpb.AggregatorSpec_BIT_OR: {
+		LocalStage: []distsqlpb.AggregatorSpec_Func{distsqlpb.AggregatorSpec_BIT_OR},
+		FinalStage: []FinalStageInfo{
+			{
+				Fn:        distsqlpb.AggregatorSpec_BIT_OR,
+				LocalIdxs: passThroughLocalIdxs,
+			},
+		},
+	},
+
+	distsqlpb.AggregatorSpec_EVERY: {
+		LocalStage: []distsqlpb.AggregatorSpec_Func{distsqlpb.AggregatorSpec_BOOL_AND},
+		FinalStage: []FinalStageInfo{
+			{
+				Fn:        distsqlpb.AggregatorSpec_BOOL_AND,
+				LocalIdxs: passThroughLocalIdxs,
+			},
+		},
+	},
+
+	// REGR_COUNT counts the rows in which both arguments are non-NULL; the final
+	// stage adds the counts (SUM_INT).
+	distsqlpb.AggregatorSpec_REGR_COUNT: {
+		LocalStage: []distsqlpb.AggregatorSpec_Func{distsqlpb.AggregatorSpec_REGR_COUNT},
+		FinalStage: []FinalStageInfo{
+			{
+				Fn:        distsqlpb.AggregatorSpec_SUM_INT,
+				LocalIdxs: passThroughLocalIdxs,
+			},
+		},
+	},
+
 	// AVG is more tricky than the ones above; we need two intermediate values in
 	// the local and final stages:
 	//  - the local stage accumulates the SUM and the COUNT;
@@ -272,4 +314,200 @@ var DistAggregationTable = map[distsqlpb.AggregatorSpec_Func]DistAggregationInfo
 			},
 		},
 	},
+
+	// VAR_SAMP and STDDEV_SAMP are aliases of VARIANCE and STDDEV. VAR_POP and
+	// STDDEV_POP use the same local stage, but their final stage divides the sum
+	// of squared differences by COUNT(x) instead of COUNT(x) - 1.
+	distsqlpb.AggregatorSpec_VAR_SAMP: {
+		LocalStage: []distsqlpb.AggregatorSpec_Func{
+			distsqlpb.AggregatorSpec_SQRDIFF,
+			distsqlpb.AggregatorSpec_SUM,
+			distsqlpb.AggregatorSpec_COUNT,
+		},
+		FinalStage: []FinalStageInfo{
+			{
+				Fn:        distsqlpb.AggregatorSpec_FINAL_VARIANCE,
+				LocalIdxs: []uint32{0, 1, 2},
+			},
+		},
+	},
+
+	distsqlpb.AggregatorSpec_STDDEV_SAMP: {
+		LocalStage: []distsqlpb.AggregatorSpec_Func{
+			distsqlpb.AggregatorSpec_SQRDIFF,
+			distsqlpb.AggregatorSpec_SUM,
+			distsqlpb.AggregatorSpec_COUNT,
+		},
+		FinalStage: []FinalStageInfo{
+			{
+				Fn:        distsqlpb.AggregatorSpec_FINAL_STDDEV,
+				LocalIdxs: []uint32{0, 1, 2},
+			},
+		},
+	},
+
+	distsqlpb.AggregatorSpec_VAR_POP: {
+		LocalStage: []distsqlpb.AggregatorSpec_Func{
+			distsqlpb.AggregatorSpec_SQRDIFF,
+			distsqlpb.AggregatorSpec_SUM,
+			distsqlpb.AggregatorSpec_COUNT,
+		},
+		FinalStage: []FinalStageInfo{
+			{
+				Fn:        distsqlpb.AggregatorSpec_FINAL_VAR_POP,
+				LocalIdxs: []uint32{0, 1, 2},
+			},
+		},
+	},
+
+	distsqlpb.AggregatorSpec_STDDEV_POP: {
+		LocalStage: []distsqlpb.AggregatorSpec_Func{
+			distsqlpb.AggregatorSpec_SQRDIFF,
+			distsqlpb.AggregatorSpec_SUM,
+			distsqlpb.AggregatorSpec_COUNT,
+		},
+		FinalStage: []FinalStageInfo{
+			{
+				Fn:        distsqlpb.AggregatorSpec_FINAL_STDDEV_POP,
+				LocalIdxs: []uint32{0, 1, 2},
+			},
+		},
+	},
+
+	// The two-argument statistical and regression aggregates share a local
+	// stage: TRANSITION_REGRESSION_AGGREGATE(y, x) accumulates the number of
+	// rows and the sums (and sums of squares and products) of the arguments
+	// into an array, and a function-specific final stage combines the partial
+	// arrays and computes the result.
+	distsqlpb.AggregatorSpec_CORR: {
+		LocalStage: []distsqlpb.AggregatorSpec_Func{
+			distsqlpb.AggregatorSpec_TRANSITION_REGRESSION_AGGREGATE,
+		},
+		FinalStage: []FinalStageInfo{
+			{
+				Fn:        distsqlpb.AggregatorSpec_FINAL_CORR,
+				LocalIdxs: passThroughLocalIdxs,
+			},
+		},
+	},
+
+	distsqlpb.AggregatorSpec_COVAR_POP: {
+		LocalStage: []distsqlpb.AggregatorSpec_Func{
+			distsqlpb.AggregatorSpec_TRANSITION_REGRESSION_AGGREGATE,
+		},
+		FinalStage: []FinalStageInfo{
+			{
+				Fn:        distsqlpb.AggregatorSpec_FINAL_COVAR_POP,
+				LocalIdxs: passThroughLocalIdxs,
+			},
+		},
+	},
+
+	distsqlpb.AggregatorSpec_COVAR_SAMP: {
+		LocalStage: []distsqlpb.AggregatorSpec_Func{
+			distsqlpb.AggregatorSpec_TRANSITION_REGRESSION_AGGREGATE,
+		},
+		FinalStage: []FinalStageInfo{
+			{
+				Fn:        distsqlpb.AggregatorSpec_FINAL_COVAR_SAMP,
+				LocalIdxs: passThroughLocalIdxs,
+			},
+		},
+	},
+
+	distsqlpb.AggregatorSpec_REGR_AVGX: {
+		LocalStage: []distsqlpb.AggregatorSpec_Func{
+			distsqlpb.AggregatorSpec_TRANSITION_REGRESSION_AGGREGATE,
+		},
+		FinalStage: []FinalStageInfo{
+			{
+				Fn:        distsqlpb.AggregatorSpec_FINAL_REGR_AVGX,
+				LocalIdxs: passThroughLocalIdxs,
+			},
+		},
+	},
+
+	distsqlpb.AggregatorSpec_REGR_AVGY: {
+		LocalStage: []distsqlpb.AggregatorSpec_Func{
+			distsqlpb.AggregatorSpec_TRANSITION_REGRESSION_AGGREGATE,
+		},
+		FinalStage: []FinalStageInfo{
+			{
+				Fn:        distsqlpb.AggregatorSpec_FINAL_REGR_AVGY,
+				LocalIdxs: passThroughLocalIdxs,
+			},
+		},
+	},
+
+	distsqlpb.AggregatorSpec_REGR_INTERCEPT: {
+		LocalStage: []distsqlpb.AggregatorSpec_Func{
+			distsqlpb.AggregatorSpec_TRANSITION_REGRESSION_AGGREGATE,
+		},
+		FinalStage: []FinalStageInfo{
+			{
+				Fn:        distsqlpb.AggregatorSpec_FINAL_REGR_INTERCEPT,
+				LocalIdxs: passThroughLocalIdxs,
+			},
+		},
+	},
+
+	distsqlpb.AggregatorSpec_REGR_R2: {
+		LocalStage: []distsqlpb.AggregatorSpec_Func{
+			distsqlpb.AggregatorSpec_TRANSITION_REGRESSION_AGGREGATE,
+		},
+		FinalStage: []FinalStageInfo{
+			{
+				Fn:        distsqlpb.AggregatorSpec_FINAL_REGR_R2,
+				LocalIdxs: passThroughLocalIdxs,
+			},
+		},
+	},
+
+	distsqlpb.AggregatorSpec_REGR_SLOPE: {
+		LocalStage: []distsqlpb.AggregatorSpec_Func{
+			distsqlpb.AggregatorSpec_TRANSITION_REGRESSION_AGGREGATE,
+		},
+		FinalStage: []FinalStageInfo{
+			{
+				Fn:        distsqlpb.AggregatorSpec_FINAL_REGR_SLOPE,
+				LocalIdxs: passThroughLocalIdxs,
+			},
+		},
+	},
+
+	distsqlpb.AggregatorSpec_REGR_SXX: {
+		LocalStage: []distsqlpb.AggregatorSpec_Func{
+			distsqlpb.AggregatorSpec_TRANSITION_REGRESSION_AGGREGATE,
+		},
+		FinalStage: []FinalStageInfo{
+			{
+				Fn:        distsqlpb.AggregatorSpec_FINAL_REGR_SXX,
+				LocalIdxs: passThroughLocalIdxs,
+			},
+		},
+	},
+
+	distsqlpb.AggregatorSpec_REGR_SXY: {
+		LocalStage: []distsqlpb.AggregatorSpec_Func{
+			distsqlpb.AggregatorSpec_TRANSITION_REGRESSION_AGGREGATE,
+		},
+		FinalStage: []FinalStageInfo{
+			{
+				Fn:        distsqlpb.AggregatorSpec_FINAL_REGR_SXY,
+				LocalIdxs: passThroughLocalIdxs,
+			},
+		},
+	},
+
+	distsqlpb.AggregatorSpec_REGR_SYY: {
+		LocalStage: []distsqlpb.AggregatorSpec_Func{
+			distsqlpb.AggregatorSpec_TRANSITION_REGRESSION_AGGREGATE,
+		},
+		FinalStage: []FinalStageInfo{
+			{
+				Fn:        distsqlpb.AggregatorSpec_FINAL_REGR_SYY,
+				LocalIdxs: passThroughLocalIdxs,
+			},
+		},
+	},
 }
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"strings"
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
@@ -109,18 +110,25 @@ func runTestFlow(
 //
 // Both types of flows are set up and ran against the first numRows of the given
 // table. We assume the table's first column is the primary key, with values
-// from 1 to numRows. A non-PK column that works with the function is chosen.
+// from 1 to numRows. Non-PK columns that work with the function are chosen.
 func checkDistAggregationInfo(
 	ctx context.Context,
 	t *testing.T,
 	srv serverutils.TestServerInterface,
 	tableDesc *sqlbase.TableDescriptor,
-	colIdx int,
+	colIdxs []int,
 	numRows int,
 	fn distsqlpb.AggregatorSpec_Func,
 	info DistAggregationInfo,
 ) {
-	colType := tableDesc.Columns[colIdx].Type
+	colTypes := make([]sqlbase.ColumnType, len(colIdxs))
+	outputColumns := make([]uint32, len(colIdxs))
+	aggColIdxs := make([]uint32, len(colIdxs))
+	for i, colIdx := range colIdxs {
+		colTypes[i] = tableDesc.Columns[colIdx].Type
+		outputColumns[i] = uint32(colIdx)
+		aggColIdxs[i] = uint32(i)
+	}
 
 	makeTableReader := func(startPK, endPK int, streamID int) distsqlpb.ProcessorSpec {
 		tr := distsqlpb.TableReaderSpec{
@@ -142,7 +150,7 @@ func checkDistAggregationInfo(
 			Core: distsqlpb.ProcessorCoreUnion{TableReader: &tr},
 			Post: distsqlpb.PostProcessSpec{
 				Projection:    true,
-				OutputColumns: []uint32{uint32(colIdx)},
+				OutputColumns: outputColumns,
 			},
 			Output: []distsqlpb.OutputRouterSpec{{
 				Type: distsqlpb.OutputRouterSpec_PASS_THROUGH,
@@ -163,13 +171,13 @@ func checkDistAggregationInfo(
 		distsqlpb.ProcessorSpec{
 			Input: []distsqlpb.InputSyncSpec{{
 				Type:        distsqlpb.InputSyncSpec_UNORDERED,
-				ColumnTypes: []sqlbase.ColumnType{colType},
+				ColumnTypes: colTypes,
 				Streams: []distsqlpb.StreamEndpointSpec{
 					{Type: distsqlpb.StreamEndpointSpec_LOCAL, StreamID: 0},
 				},
 			}},
 			Core: distsqlpb.ProcessorCoreUnion{Aggregator: &distsqlpb.AggregatorSpec{
-				Aggregations: []distsqlpb.AggregatorSpec_Aggregation{{Func: fn, ColIdx: []uint32{0}}},
+				Aggregations: []distsqlpb.AggregatorSpec_Aggregation{{Func: fn, ColIdx: aggColIdxs}},
 			}},
 			Output: []distsqlpb.OutputRouterSpec{{
 				Type: distsqlpb.OutputRouterSpec_PASS_THROUGH,
@@ -203,7 +211,7 @@ func checkDistAggregationInfo(
 	intermediaryTypes := make([]sqlbase.ColumnType, numIntermediary)
 	for i, fn := range info.LocalStage {
 		var err error
-		_, intermediaryTypes[i], err = distsqlrun.GetAggregateInfo(fn, colType)
+		_, intermediaryTypes[i], err = distsqlrun.GetAggregateInfo(fn, colTypes...)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -212,7 +220,7 @@ func checkDistAggregationInfo(
 	localAggregations := make([]distsqlpb.AggregatorSpec_Aggregation, numIntermediary)
 	for i, fn := range info.LocalStage {
 		// Local aggregations have the same input.
-		localAggregations[i] = distsqlpb.AggregatorSpec_Aggregation{Func: fn, ColIdx: []uint32{0}}
+		localAggregations[i] = distsqlpb.AggregatorSpec_Aggregation{Func: fn, ColIdx: aggColIdxs}
 	}
 	finalAggregations := make([]distsqlpb.AggregatorSpec_Aggregation, numFinal)
 	for i, finalInfo := range info.FinalStage {
@@ -267,7 +275,7 @@ func checkDistAggregationInfo(
 		agg := distsqlpb.ProcessorSpec{
 			Input: []distsqlpb.InputSyncSpec{{
 				Type:        distsqlpb.InputSyncSpec_UNORDERED,
-				ColumnTypes: []sqlbase.ColumnType{colType},
+				ColumnTypes: colTypes,
 				Streams: []distsqlpb.StreamEndpointSpec{
 					{Type: distsqlpb.StreamEndpointSpec_LOCAL, StreamID: distsqlpb.StreamID(2 * i)},
 				},
@@ -415,20 +423,32 @@ func TestDistAggregationTable(t *testing.T) {
 			continue
 		}
 		// We're going to test each aggregation function on every column that can be
-		// used as input for it.
+		// used as input for it. Functions that take two arguments are tested on
+		// every pair of consecutive columns of the same type.
 		foundCol := false
 		for colIdx := 1; colIdx < len(desc.Columns); colIdx++ {
-			// See if this column works with this function.
+			// See if this column (or this pair of columns) works with this function.
+			colIdxs := []int{colIdx}
 			_, _, err := distsqlrun.GetAggregateInfo(fn, desc.Columns[colIdx].Type)
+			if err != nil && colIdx+1 < len(desc.Columns) {
+				colIdxs = []int{colIdx, colIdx + 1}
+				_, _, err = distsqlrun.GetAggregateInfo(
+					fn, desc.Columns[colIdx].Type, desc.Columns[colIdx+1].Type,
+				)
+			}
 			if err != nil {
 				continue
 			}
 			foundCol = true
+			names := make([]string, len(colIdxs))
+			for i, idx := range colIdxs {
+				names[i] = desc.Columns[idx].Name
+			}
 			for _, numRows := range []int{5, numRows / 10, numRows / 2, numRows} {
-				name := fmt.Sprintf("%s/%s/%d", fn, desc.Columns[colIdx].Name, numRows)
+				name := fmt.Sprintf("%s/%s/%d", fn, strings.Join(names, ","), numRows)
 				t.Run(name, func(t *testing.T) {
 					checkDistAggregationInfo(
-						context.Background(), t, tc.Server(0), desc, colIdx, numRows, fn, info)
+						context.Background(), t, tc.Server(0), desc, colIdxs, numRows, fn, info)
 				})
 			}
 		}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"reflect"
 
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/distsqlpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/exec/types"
@@ -56,6 +57,9 @@ func newColOperator(
 		if err := checkNumIn(inputs, 0); err != nil {
 			return nil, err
 		}
+		if core.TableReader.LockingStrength != roachpb.LOCK_NONE {
+			return nil, errors.New("locking table readers not supported")
+		}
 		op, err = newColBatchScan(flowCtx, core.TableReader, post)
 		returnMutations := core.TableReader.Visibility == distsqlpb.ScanVisibility_PUBLIC_AND_NOT_PUBLIC
 		columnTypes = core.TableReader.Table.ColumnTypesWithMutations(returnMutations)
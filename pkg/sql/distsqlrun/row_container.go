@@ -18,6 +18,7 @@ import (
 	"container/heap"
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
@@ -459,3 +460,74 @@ func (f *diskBackedRowContainer) spillToDisk(ctx context.Context) error {
 	f.spilled = true
 	return nil
 }
+
+// SpillingRowContainer buffers rows in memory and spills them to temporary
+// storage once they outgrow the sql.distsql.temp_storage.workmem budget. Rows
+// are returned by iterators in the order in which they were added. It exposes
+// diskBackedRowContainer to users outside of DistSQL, such as SQL cursors,
+// that need to hold on to query results for longer than a single flow.
+type SpillingRowContainer struct {
+	rc          diskBackedRowContainer
+	memMonitor  mon.BytesMonitor
+	diskMonitor *mon.BytesMonitor
+}
+
+// RowIterator iterates over the rows of a SpillingRowContainer. See
+// rowIterator for usage.
+type RowIterator interface {
+	rowIterator
+}
+
+// NewSpillingRowContainer creates a SpillingRowContainer for rows of the given
+// types. Memory usage is accounted for by a child of memMonitor and disk usage,
+// after spilling, by a child of diskMonitor. The container must be closed once
+// no longer needed.
+func NewSpillingRowContainer(
+	ctx context.Context,
+	st *cluster.Settings,
+	types []sqlbase.ColumnType,
+	engine diskmap.Factory,
+	memMonitor *mon.BytesMonitor,
+	diskMonitor *mon.BytesMonitor,
+) *SpillingRowContainer {
+	c := &SpillingRowContainer{}
+	c.memMonitor = mon.MakeMonitorInheritWithLimit(
+		"spilling-rows-limited", settingWorkMemBytes.Get(&st.SV), memMonitor,
+	)
+	c.memMonitor.Start(ctx, memMonitor, mon.BoundAccount{})
+	c.diskMonitor = NewMonitor(ctx, diskMonitor, "spilling-rows-disk")
+	// The rows are never sorted, so neither an ordering nor an evalCtx is
+	// needed.
+	c.rc.init(
+		nil /* ordering */, types, nil /* evalCtx */, engine, &c.memMonitor, c.diskMonitor,
+	)
+	return c
+}
+
+// Len returns the number of rows in the container.
+func (c *SpillingRowContainer) Len() int {
+	return c.rc.Len()
+}
+
+// AddRow adds a row to the container, spilling to disk if needed.
+func (c *SpillingRowContainer) AddRow(ctx context.Context, row sqlbase.EncDatumRow) error {
+	return c.rc.AddRow(ctx, row)
+}
+
+// NewIterator returns an iterator over the rows of the container. Rows must
+// not be added while the iterator is open.
+func (c *SpillingRowContainer) NewIterator(ctx context.Context) RowIterator {
+	return c.rc.NewIterator(ctx)
+}
+
+// UsingDisk returns whether the rows have spilled to disk.
+func (c *SpillingRowContainer) UsingDisk() bool {
+	return c.rc.UsingDisk()
+}
+
+// Close releases the resources held by the container.
+func (c *SpillingRowContainer) Close(ctx context.Context) {
+	c.rc.Close(ctx)
+	c.diskMonitor.Stop(ctx)
+	c.memMonitor.Stop(ctx)
+}
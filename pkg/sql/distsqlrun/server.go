@@ -382,6 +382,7 @@ func (ds *ServerImpl) setupFlow(
 				BytesEncodeFormat: be,
 				ExtraFloatDigits:  int(req.EvalContext.ExtraFloatDigits),
 			},
+			DefaultTextSearchConfig: req.EvalContext.DefaultTextSearchConfig,
 		}
 		// Enable better compatibility with PostgreSQL date math.
 		if req.Version >= 22 {
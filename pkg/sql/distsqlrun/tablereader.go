@@ -119,6 +119,7 @@ func newTableReader(
 	); err != nil {
 		return nil, err
 	}
+	tr.fetcher.SetLocking(spec.LockingStrength, spec.LockingWaitPolicy)
 
 	nSpans := len(spec.Spans)
 	if cap(tr.spans) >= nSpans {
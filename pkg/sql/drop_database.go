@@ -29,9 +29,13 @@ import (
 )
 
 type dropDatabaseNode struct {
-	n      *tree.DropDatabase
-	dbDesc *sqlbase.DatabaseDescriptor
-	td     []toDelete
+	n           *tree.DropDatabase
+	dbDesc      *sqlbase.DatabaseDescriptor
+	td          []toDelete
+	schemas     []temporarySchema
+	userSchemas []*sqlbase.SchemaDescriptor
+	types       []*sqlbase.TypeDescriptor
+	functions   []*sqlbase.FunctionDescriptor
 }
 
 // DropDatabase drops a database.
@@ -70,7 +74,48 @@ func (p *planner) DropDatabase(ctx context.Context, n *tree.DropDatabase) (planN
 		return nil, err
 	}
 
-	if len(tbNames) > 0 {
+	// The temporary objects of all the sessions are dropped along with the
+	// database.
+	schemas, err := getTemporarySchemasInDatabase(ctx, p.txn, dbDesc.ID, dbDesc.Name, "" /* scName */)
+	if err != nil {
+		return nil, err
+	}
+	var schemaObjects []toDelete
+	for _, sc := range schemas {
+		objects, err := p.getTemporaryObjects(ctx, sc)
+		if err != nil {
+			return nil, err
+		}
+		schemaObjects = append(schemaObjects, objects...)
+	}
+
+	// So are the user-defined schemas and their objects.
+	userSchemas, err := getSchemasInDatabase(ctx, p.txn, dbDesc.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, sc := range userSchemas {
+		objects, err := p.getSchemaObjects(ctx, dbDesc.Name, sc.Name, sc.ID)
+		if err != nil {
+			return nil, err
+		}
+		schemaObjects = append(schemaObjects, objects...)
+	}
+
+	// The user-defined types of the database are dropped along with it.
+	types, err := getTypesInDatabase(ctx, p.txn, dbDesc.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// So are its user-defined functions.
+	functions, err := getFunctionsInDatabase(ctx, p.txn, dbDesc.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tbNames) > 0 || len(schemaObjects) > 0 || len(userSchemas) > 0 || len(types) > 0 ||
+		len(functions) > 0 {
 		switch n.DropBehavior {
 		case tree.DropRestrict:
 			return nil, pgerror.NewErrorf(pgerror.CodeDependentObjectsStillExistError,
@@ -86,7 +131,7 @@ func (p *planner) DropDatabase(ctx context.Context, n *tree.DropDatabase) (planN
 		}
 	}
 
-	td := make([]toDelete, 0, len(tbNames))
+	td := make([]toDelete, 0, len(tbNames)+len(schemaObjects))
 	for i := range tbNames {
 		tbDesc, err := p.prepareDrop(ctx, &tbNames[i], false /*required*/, anyDescType)
 		if err != nil {
@@ -104,64 +149,43 @@ func (p *planner) DropDatabase(ctx context.Context, n *tree.DropDatabase) (planN
 		}
 		td = append(td, toDelete{&tbNames[i], tbDesc})
 	}
+	for _, toDel := range schemaObjects {
+		if err := p.CheckPrivilege(ctx, toDel.desc, privilege.DROP); err != nil {
+			return nil, err
+		}
+		for _, ref := range toDel.desc.DependedOnBy {
+			if err := p.canRemoveDependentView(ctx, toDel.desc, ref, tree.DropCascade); err != nil {
+				return nil, err
+			}
+		}
+		td = append(td, toDel)
+	}
 
 	td, err = p.filterCascadedTables(ctx, td)
 	if err != nil {
 		return nil, err
 	}
 
-	return &dropDatabaseNode{n: n, dbDesc: dbDesc, td: td}, nil
+	return &dropDatabaseNode{
+		n:           n,
+		dbDesc:      dbDesc,
+		td:          td,
+		schemas:     schemas,
+		userSchemas: userSchemas,
+		types:       types,
+		functions:   functions,
+	}, nil
 }
 
 func (n *dropDatabaseNode) startExec(params runParams) error {
 	ctx := params.ctx
 	p := params.p
-	tbNameStrings := make([]string, 0, len(n.td))
-	droppedTableDetails := make([]jobspb.DroppedTableDetails, 0, len(n.td))
-	tableDescs := make([]*sqlbase.MutableTableDescriptor, 0, len(n.td))
-
-	for _, toDel := range n.td {
-		if toDel.desc.IsView() {
-			continue
-		}
-		droppedTableDetails = append(droppedTableDetails, jobspb.DroppedTableDetails{
-			Name: toDel.tn.FQString(),
-			ID:   toDel.desc.ID,
-		})
-		tableDescs = append(tableDescs, toDel.desc)
-	}
-
-	jobID, err := p.createDropTablesJob(
-		ctx,
-		tableDescs,
-		droppedTableDetails,
-		tree.AsStringWithFlags(n.n, tree.FmtAlwaysQualifyTableNames),
-		true, /* drainNames */
-		n.dbDesc.ID)
+	tbNameStrings, jobID, err := p.dropTablesAndViews(
+		params, n.td, tree.AsStringWithFlags(n.n, tree.FmtAlwaysQualifyTableNames), n.dbDesc.ID)
 	if err != nil {
 		return err
 	}
 
-	for _, toDel := range n.td {
-		tbDesc := toDel.desc
-		if tbDesc.IsView() {
-			cascadedViews, err := p.dropViewImpl(ctx, tbDesc, tree.DropCascade)
-			if err != nil {
-				return err
-			}
-			// TODO(knz): dependent dropped views should be qualified here.
-			tbNameStrings = append(tbNameStrings, cascadedViews...)
-		} else {
-			cascadedViews, err := p.dropTableImpl(params, tbDesc)
-			if err != nil {
-				return err
-			}
-			// TODO(knz): dependent dropped table names should be qualified here.
-			tbNameStrings = append(tbNameStrings, cascadedViews...)
-		}
-		tbNameStrings = append(tbNameStrings, toDel.tn.FQString())
-	}
-
 	_ /* zoneKey */, nameKey, descKey := getKeysForDatabaseDescriptor(n.dbDesc)
 
 	b := &client.Batch{}
@@ -171,6 +195,22 @@ func (n *dropDatabaseNode) startExec(params runParams) error {
 	}
 	b.Del(descKey)
 	b.Del(nameKey)
+	for _, sc := range n.schemas {
+		scKey := sc.nameKey()
+		if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+			log.VEventf(ctx, 2, "Del %s", scKey)
+		}
+		b.Del(scKey)
+	}
+	for _, sc := range n.userSchemas {
+		dropSchemaDescToBatch(ctx, p, sc, b)
+	}
+	for _, typ := range n.types {
+		dropTypeDescToBatch(ctx, p, typ, b)
+	}
+	for _, fn := range n.functions {
+		dropFunctionDescToBatch(ctx, p, fn, b)
+	}
 
 	// No job was created because no tables were dropped, so zone config can be
 	// immediately removed.
@@ -214,6 +254,62 @@ func (*dropDatabaseNode) Next(runParams) (bool, error) { return false, nil }
 func (*dropDatabaseNode) Close(context.Context)        {}
 func (*dropDatabaseNode) Values() tree.Datums          { return tree.Datums{} }
 
+// dropTablesAndViews drops the given tables, views and sequences. It returns
+// the names of the dropped objects, including the views dropped by cascade,
+// and the ID of the job created to drop the tables, or 0 if no job was
+// created.
+func (p *planner) dropTablesAndViews(
+	params runParams, td []toDelete, stmt string, droppedDatabaseID sqlbase.ID,
+) ([]string, int64, error) {
+	ctx := params.ctx
+	tbNameStrings := make([]string, 0, len(td))
+	droppedTableDetails := make([]jobspb.DroppedTableDetails, 0, len(td))
+	tableDescs := make([]*sqlbase.MutableTableDescriptor, 0, len(td))
+
+	for _, toDel := range td {
+		if toDel.desc.IsView() {
+			continue
+		}
+		droppedTableDetails = append(droppedTableDetails, jobspb.DroppedTableDetails{
+			Name: toDel.tn.FQString(),
+			ID:   toDel.desc.ID,
+		})
+		tableDescs = append(tableDescs, toDel.desc)
+	}
+
+	jobID, err := p.createDropTablesJob(
+		ctx,
+		tableDescs,
+		droppedTableDetails,
+		stmt,
+		true, /* drainNames */
+		droppedDatabaseID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, toDel := range td {
+		tbDesc := toDel.desc
+		if tbDesc.IsView() {
+			cascadedViews, err := p.dropViewImpl(ctx, tbDesc, tree.DropCascade)
+			if err != nil {
+				return nil, 0, err
+			}
+			// TODO(knz): dependent dropped views should be qualified here.
+			tbNameStrings = append(tbNameStrings, cascadedViews...)
+		} else {
+			cascadedViews, err := p.dropTableImpl(params, tbDesc)
+			if err != nil {
+				return nil, 0, err
+			}
+			// TODO(knz): dependent dropped table names should be qualified here.
+			tbNameStrings = append(tbNameStrings, cascadedViews...)
+		}
+		tbNameStrings = append(tbNameStrings, toDel.tn.FQString())
+	}
+	return tbNameStrings, jobID, nil
+}
+
 // filterCascadedTables takes a list of table descriptors and removes any
 // descriptors from the list that are dependent on other descriptors in the
 // list (e.g. if view v1 depends on table t1, then v1 will be filtered from
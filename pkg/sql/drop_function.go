@@ -0,0 +1,176 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+type dropFunctionNode struct {
+	n *tree.DropFunction
+	// descs are the descriptors of the functions, which are dropped when
+	// they have no overloads left.
+	descs []*sqlbase.FunctionDescriptor
+	// names are the names of the dropped functions, in the order of descs.
+	names []*tree.TableName
+}
+
+// DropFunction drops overloads of user-defined functions.
+// Privileges: DROP on function.
+//   Notes: postgres requires ownership of the function.
+func (p *planner) DropFunction(ctx context.Context, n *tree.DropFunction) (planNode, error) {
+	if n.DropBehavior == tree.DropCascade {
+		return nil, pgerror.Unimplemented("drop function cascade",
+			"DROP FUNCTION ... CASCADE is not supported")
+	}
+
+	node := &dropFunctionNode{n: n}
+	for i := range n.Functions {
+		fn := &n.Functions[i]
+		desc, err := p.resolveFunctionDesc(ctx, &fn.Name, !n.IfExists)
+		if err != nil {
+			return nil, err
+		}
+		if desc == nil {
+			// IfExists specified and the function did not exist.
+			continue
+		}
+		// The same function can be named more than once, with different
+		// argument types.
+		seen := false
+		for _, prev := range node.descs {
+			if prev.ID == desc.ID {
+				desc, seen = prev, true
+				break
+			}
+		}
+		if err := p.CheckPrivilege(ctx, desc, privilege.DROP); err != nil {
+			return nil, err
+		}
+
+		idx := 0
+		if fn.HasArgs {
+			argTypes := make([]types.T, len(fn.Args))
+			for j := range fn.Args {
+				if err := tree.ResolveColumnType(&p.semaCtx, fn.Args[j].Type); err != nil {
+					return nil, err
+				}
+				argTypes[j] = coltypes.CastTargetToDatumType(fn.Args[j].Type)
+			}
+			idx = desc.FindOverload(argTypes)
+			if idx == -1 {
+				if n.IfExists {
+					continue
+				}
+				return nil, pgerror.NewErrorf(pgerror.CodeUndefinedFunctionError,
+					"function %s does not exist", tree.ErrString(fn))
+			}
+		} else if len(desc.Overloads) == 0 {
+			if n.IfExists {
+				continue
+			}
+			return nil, pgerror.NewErrorf(pgerror.CodeUndefinedFunctionError,
+				"function %s does not exist", tree.ErrString(fn))
+		} else if len(desc.Overloads) > 1 {
+			return nil, pgerror.NewErrorf(pgerror.CodeAmbiguousFunctionError,
+				"function name %q is not unique", tree.ErrString(&fn.Name)).SetHintf(
+				"Specify the argument list to select the function unambiguously.")
+		}
+		desc.Overloads = append(desc.Overloads[:idx], desc.Overloads[idx+1:]...)
+
+		if !seen {
+			node.descs = append(node.descs, desc)
+			node.names = append(node.names, &fn.Name)
+		}
+	}
+
+	if len(node.descs) == 0 {
+		return newZeroNode(nil /* columns */), nil
+	}
+	return node, nil
+}
+
+func (n *dropFunctionNode) startExec(params runParams) error {
+	p := params.p
+	ctx := params.ctx
+
+	b := &client.Batch{}
+	for _, desc := range n.descs {
+		if len(desc.Overloads) == 0 {
+			dropFunctionDescToBatch(ctx, p, desc, b)
+			continue
+		}
+		if err := desc.Validate(); err != nil {
+			return err
+		}
+		descKey := sqlbase.MakeDescMetadataKey(desc.ID)
+		if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+			log.VEventf(ctx, 2, "Put %s -> %s", descKey, desc)
+		}
+		b.Put(descKey, sqlbase.WrapDescriptor(desc))
+	}
+	if err := p.txn.Run(ctx, b); err != nil {
+		return err
+	}
+
+	for i, desc := range n.descs {
+		// Log a Drop Function event for this function. This is an auditable
+		// log event and is recorded in the same transaction as the function
+		// descriptor update.
+		if err := MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+			ctx,
+			p.txn,
+			EventLogDropFunction,
+			int32(desc.ID),
+			int32(params.extendedEvalCtx.NodeID),
+			struct {
+				FunctionName string
+				Statement    string
+				User         string
+			}{n.names[i].FQString(), n.n.String(), params.SessionData().User},
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropFunctionDescToBatch adds the deletion of the name and of the
+// descriptor of the function to the batch.
+func dropFunctionDescToBatch(
+	ctx context.Context, p *planner, desc *sqlbase.FunctionDescriptor, b *client.Batch,
+) {
+	nameKey := tableKey{parentID: desc.ParentID, name: desc.Name}.Key()
+	descKey := sqlbase.MakeDescMetadataKey(desc.ID)
+	if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+		log.VEventf(ctx, 2, "Del %s", nameKey)
+		log.VEventf(ctx, 2, "Del %s", descKey)
+	}
+	b.Del(nameKey)
+	b.Del(descKey)
+}
+
+func (*dropFunctionNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropFunctionNode) Values() tree.Datums          { return tree.Datums{} }
+func (*dropFunctionNode) Close(context.Context)        {}
@@ -66,7 +66,7 @@ func (n *dropIndexNode) startExec(params runParams) error {
 		// the mutation list and new version number created by the first
 		// drop need to be visible to the second drop.
 		tableDesc, err := params.p.ResolveMutableTableDescriptor(
-			ctx, index.tn, true /*required*/, requireTableDesc)
+			ctx, index.tn, true /*required*/, requireTableOrMaterializedViewDesc)
 		if err != nil {
 			// Somehow the descriptor we had during newPlan() is not there
 			// any more.
@@ -0,0 +1,135 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+type dropSchemaNode struct {
+	n       *tree.DropSchema
+	schemas []*sqlbase.SchemaDescriptor
+	td      []toDelete
+}
+
+// DropSchema drops user-defined schemas of the current database.
+// Privileges: DROP on schema and DROP on all the objects of the schema.
+//   Notes: postgres requires ownership of the schema.
+func (p *planner) DropSchema(ctx context.Context, n *tree.DropSchema) (planNode, error) {
+	node := &dropSchemaNode{n: n}
+	for _, name := range n.Names {
+		scName := string(name)
+		if isSystemSchemaName(scName) {
+			return nil, pgerror.NewErrorf(pgerror.CodeDependentObjectsStillExistError,
+				"cannot drop schema %q because it is required by the database system", scName)
+		}
+		dbDesc, scDesc, err := p.resolveSchemaDesc(ctx, scName, !n.IfExists)
+		if err != nil {
+			return nil, err
+		}
+		if scDesc == nil {
+			// IfExists specified and the schema did not exist.
+			continue
+		}
+		if err := p.CheckPrivilege(ctx, scDesc, privilege.DROP); err != nil {
+			return nil, err
+		}
+
+		objects, err := p.getSchemaObjects(ctx, dbDesc.Name, scName, scDesc.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(objects) > 0 && n.DropBehavior != tree.DropCascade {
+			return nil, pgerror.NewErrorf(pgerror.CodeDependentObjectsStillExistError,
+				"schema %q is not empty and CASCADE was not specified", scName)
+		}
+		for _, toDel := range objects {
+			if err := p.CheckPrivilege(ctx, toDel.desc, privilege.DROP); err != nil {
+				return nil, err
+			}
+			// Recursively check permissions on all dependent views, since some
+			// may be in different schemas.
+			for _, ref := range toDel.desc.DependedOnBy {
+				if err := p.canRemoveDependentView(ctx, toDel.desc, ref, tree.DropCascade); err != nil {
+					return nil, err
+				}
+			}
+			node.td = append(node.td, toDel)
+		}
+		node.schemas = append(node.schemas, scDesc)
+	}
+
+	if len(node.schemas) == 0 {
+		return newZeroNode(nil /* columns */), nil
+	}
+
+	var err error
+	node.td, err = p.filterCascadedTables(ctx, node.td)
+	if err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+func (n *dropSchemaNode) startExec(params runParams) error {
+	ctx := params.ctx
+	p := params.p
+
+	tbNameStrings, _, err := p.dropTablesAndViews(
+		params, n.td, tree.AsStringWithFlags(n.n, tree.FmtAlwaysQualifyTableNames), sqlbase.InvalidID)
+	if err != nil {
+		return err
+	}
+
+	b := &client.Batch{}
+	for _, sc := range n.schemas {
+		dropSchemaDescToBatch(ctx, p, sc, b)
+	}
+	if err := p.txn.Run(ctx, b); err != nil {
+		return err
+	}
+
+	for _, sc := range n.schemas {
+		// Log a Drop Schema event for this schema. This is an auditable log
+		// event and is recorded in the same transaction as the schema
+		// descriptor update.
+		if err := MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+			ctx,
+			p.txn,
+			EventLogDropSchema,
+			int32(sc.ID),
+			int32(params.extendedEvalCtx.NodeID),
+			struct {
+				SchemaName           string
+				Statement            string
+				User                 string
+				DroppedSchemaObjects []string
+			}{sc.Name, n.n.String(), p.SessionData().User, tbNameStrings},
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (*dropSchemaNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropSchemaNode) Values() tree.Datums          { return tree.Datums{} }
+func (*dropSchemaNode) Close(context.Context)        {}
@@ -0,0 +1,143 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+type dropTypeNode struct {
+	n     *tree.DropType
+	names []*tree.TableName
+	descs []*sqlbase.TypeDescriptor
+}
+
+// DropType drops user-defined types.
+// Privileges: DROP on type.
+//   Notes: postgres requires ownership of the type.
+func (p *planner) DropType(ctx context.Context, n *tree.DropType) (planNode, error) {
+	if n.DropBehavior == tree.DropCascade {
+		return nil, pgerror.Unimplemented("drop type cascade", "DROP TYPE ... CASCADE is not supported")
+	}
+
+	node := &dropTypeNode{n: n}
+	for i := range n.Names {
+		tn := &n.Names[i]
+		desc, err := p.resolveTypeDesc(ctx, tn, !n.IfExists)
+		if err != nil {
+			return nil, err
+		}
+		if desc == nil {
+			// IfExists specified and the type did not exist.
+			continue
+		}
+		if err := p.CheckPrivilege(ctx, desc, privilege.DROP); err != nil {
+			return nil, err
+		}
+		if err := p.checkTypeNotInUse(ctx, desc); err != nil {
+			return nil, err
+		}
+		node.names = append(node.names, tn)
+		node.descs = append(node.descs, desc)
+	}
+
+	if len(node.descs) == 0 {
+		return newZeroNode(nil /* columns */), nil
+	}
+	return node, nil
+}
+
+// checkTypeNotInUse returns an error if a column of a table has the given
+// type.
+func (p *planner) checkTypeNotInUse(ctx context.Context, desc *sqlbase.TypeDescriptor) error {
+	tableIDs, err := getTablesUsingType(ctx, p.txn, desc.ID)
+	if err != nil {
+		return err
+	}
+	if len(tableIDs) == 0 {
+		return nil
+	}
+	tableDesc, err := sqlbase.GetTableDescFromID(ctx, p.txn, tableIDs[0])
+	if err != nil {
+		return err
+	}
+	tableName, err := p.getQualifiedTableName(ctx, tableDesc)
+	if err != nil {
+		return err
+	}
+	return pgerror.NewErrorf(pgerror.CodeDependentObjectsStillExistError,
+		"cannot drop type %q because table %q depends on it", desc.Name, tableName)
+}
+
+func (n *dropTypeNode) startExec(params runParams) error {
+	p := params.p
+	ctx := params.ctx
+
+	b := &client.Batch{}
+	for _, desc := range n.descs {
+		dropTypeDescToBatch(ctx, p, desc, b)
+	}
+	if err := p.txn.Run(ctx, b); err != nil {
+		return err
+	}
+
+	for i, desc := range n.descs {
+		// Log a Drop Type event for this type. This is an auditable log event
+		// and is recorded in the same transaction as the type descriptor
+		// update.
+		if err := MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+			ctx,
+			p.txn,
+			EventLogDropType,
+			int32(desc.ID),
+			int32(params.extendedEvalCtx.NodeID),
+			struct {
+				TypeName  string
+				Statement string
+				User      string
+			}{n.names[i].FQString(), n.n.String(), params.SessionData().User},
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropTypeDescToBatch adds the deletion of the name and of the descriptor of
+// the type to the batch. Types have no data, so unlike tables they are
+// removed immediately.
+func dropTypeDescToBatch(
+	ctx context.Context, p *planner, desc *sqlbase.TypeDescriptor, b *client.Batch,
+) {
+	nameKey := tableKey{parentID: desc.ParentID, name: desc.Name}.Key()
+	descKey := sqlbase.MakeDescMetadataKey(desc.ID)
+	if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+		log.VEventf(ctx, 2, "Del %s", nameKey)
+		log.VEventf(ctx, 2, "Del %s", descKey)
+	}
+	b.Del(nameKey)
+	b.Del(descKey)
+}
+
+func (*dropTypeNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropTypeNode) Values() tree.Datums          { return tree.Datums{} }
+func (*dropTypeNode) Close(context.Context)        {}
@@ -35,6 +35,11 @@ const (
 	// EventLogDropDatabase is recorded when a database is dropped.
 	EventLogDropDatabase EventLogType = "drop_database"
 
+	// EventLogCreateSchema is recorded when a schema is created.
+	EventLogCreateSchema EventLogType = "create_schema"
+	// EventLogDropSchema is recorded when a schema is dropped.
+	EventLogDropSchema EventLogType = "drop_schema"
+
 	// EventLogCreateTable is recorded when a table is created.
 	EventLogCreateTable EventLogType = "create_table"
 	// EventLogDropTable is recorded when a table is dropped.
@@ -61,6 +66,22 @@ const (
 	EventLogCreateView EventLogType = "create_view"
 	// EventLogDropView is recorded when a view is dropped.
 	EventLogDropView EventLogType = "drop_view"
+	// EventLogRefreshMaterializedView is recorded when a materialized view is
+	// refreshed.
+	EventLogRefreshMaterializedView EventLogType = "refresh_materialized_view"
+
+	// EventLogCreateType is recorded when a type is created.
+	EventLogCreateType EventLogType = "create_type"
+	// EventLogDropType is recorded when a type is dropped.
+	EventLogDropType EventLogType = "drop_type"
+	// EventLogAlterType is recorded when a type is altered.
+	EventLogAlterType EventLogType = "alter_type"
+
+	// EventLogCreateFunction is recorded when a function is created or
+	// replaced.
+	EventLogCreateFunction EventLogType = "create_function"
+	// EventLogDropFunction is recorded when a function is dropped.
+	EventLogDropFunction EventLogType = "drop_function"
 
 	// EventLogCreateSequence is recorded when a sequence is created.
 	EventLogCreateSequence EventLogType = "create_sequence"
@@ -361,6 +361,7 @@ type ExecutorConfig struct {
 	AuditLogger      *log.SecondaryLogger
 	InternalExecutor *InternalExecutor
 	QueryCache       *querycache.C
+	Notifications    *NotificationRegistry
 
 	TestingKnobs              *ExecutorTestingKnobs
 	SchemaChangerTestingKnobs *SchemaChangerTestingKnobs
@@ -662,6 +663,8 @@ func checkResultType(typ types.T) error {
 	case types.TimestampTZ:
 	case types.Interval:
 	case types.JSON:
+	case types.TSVector:
+	case types.TSQuery:
 	case types.UUID:
 	case types.INet:
 	case types.NameArray:
@@ -1658,6 +1661,10 @@ func (m *sessionDataMutator) SetDefaultIntSize(size int) {
 	m.data.DefaultIntSize = size
 }
 
+func (m *sessionDataMutator) SetDefaultTextSearchConfig(name string) {
+	m.data.DefaultTextSearchConfig = name
+}
+
 func (m *sessionDataMutator) SetDefaultReadOnly(val bool) {
 	m.data.DefaultReadOnly = val
 }
@@ -1706,6 +1713,10 @@ func (m *sessionDataMutator) SetSearchPath(val sessiondata.SearchPath) {
 	m.data.SearchPath = val
 }
 
+func (m *sessionDataMutator) SetTemporarySchemaName(name string) {
+	m.data.SearchPath = m.data.SearchPath.WithTemporarySchemaName(name)
+}
+
 func (m *sessionDataMutator) SetLocation(loc *time.Location) {
 	m.data.DataConversion.Location = loc
 }
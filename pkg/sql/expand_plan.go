@@ -111,6 +111,19 @@ func doExpandPlan(
 	case *createTableNode:
 		n.sourcePlan, err = doExpandPlan(ctx, p, noParams, n.sourcePlan)
 
+	case *createViewNode:
+		if n.sourcePlan != nil {
+			n.sourcePlan, err = doExpandPlan(ctx, p, noParams, n.sourcePlan)
+		}
+
+	case *refreshViewNode:
+		if n.sourcePlan != nil {
+			n.sourcePlan, err = doExpandPlan(ctx, p, noParams, n.sourcePlan)
+		}
+
+	case *declareCursorNode:
+		n.sourcePlan, err = doExpandPlan(ctx, p, noParams, n.sourcePlan)
+
 	case *updateNode:
 		n.source, err = doExpandPlan(ctx, p, noParams, n.source)
 
@@ -286,6 +299,9 @@ func doExpandPlan(
 	case *max1RowNode:
 		n.plan, err = doExpandPlan(ctx, p, noParams, n.plan)
 
+	case *recursiveCTENode:
+		n.initial, err = doExpandPlan(ctx, p, noParams, n.initial)
+
 	case *sortNode:
 		if !n.ordering.IsPrefixOf(params.desiredOrdering) {
 			params.desiredOrdering = n.ordering
@@ -346,9 +362,11 @@ func doExpandPlan(
 
 	case *valuesNode:
 	case *virtualTableNode:
+	case *workTableScanNode:
 	case *alterIndexNode:
 	case *alterTableNode:
 	case *alterSequenceNode:
+	case *alterTypeAddValueNode:
 	case *alterUserSetPasswordNode:
 	case *commentOnColumnNode:
 	case *commentOnDatabaseNode:
@@ -362,14 +380,19 @@ func doExpandPlan(
 	case *createDatabaseNode:
 	case *createIndexNode:
 	case *CreateUserNode:
-	case *createViewNode:
 	case *createSequenceNode:
+	case *createTypeNode:
+	case *createFunctionNode:
+	case *createSchemaNode:
 	case *createStatsNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *dropSequenceNode:
+	case *dropTypeNode:
+	case *dropFunctionNode:
+	case *dropSchemaNode:
 	case *DropUserNode:
 	case *zeroNode:
 	case *unaryNode:
@@ -383,6 +406,8 @@ func doExpandPlan(
 	case *sequenceSelectNode:
 	case *setVarNode:
 	case *setClusterSettingNode:
+	case *setConstraintsNode:
+	case *fetchNode:
 	case *setZoneConfigNode:
 	case *showZoneConfigNode:
 	case *showFingerprintsNode:
@@ -671,6 +696,19 @@ func (p *planner) simplifyOrderings(plan planNode, usefulOrdering sqlbase.Column
 	case *createTableNode:
 		n.sourcePlan = p.simplifyOrderings(n.sourcePlan, nil)
 
+	case *createViewNode:
+		if n.sourcePlan != nil {
+			n.sourcePlan = p.simplifyOrderings(n.sourcePlan, nil)
+		}
+
+	case *refreshViewNode:
+		if n.sourcePlan != nil {
+			n.sourcePlan = p.simplifyOrderings(n.sourcePlan, nil)
+		}
+
+	case *declareCursorNode:
+		n.sourcePlan = p.simplifyOrderings(n.sourcePlan, nil)
+
 	case *updateNode:
 		n.source = p.simplifyOrderings(n.source, nil)
 
@@ -775,6 +813,9 @@ func (p *planner) simplifyOrderings(plan planNode, usefulOrdering sqlbase.Column
 	case *windowNode:
 		n.plan = p.simplifyOrderings(n.plan, nil)
 
+	case *recursiveCTENode:
+		n.initial = p.simplifyOrderings(n.initial, nil)
+
 	case *sortNode:
 		if n.needSort {
 			// We could pass no ordering below, but a partial ordering can speed up
@@ -858,9 +899,11 @@ func (p *planner) simplifyOrderings(plan planNode, usefulOrdering sqlbase.Column
 
 	case *valuesNode:
 	case *virtualTableNode:
+	case *workTableScanNode:
 	case *alterIndexNode:
 	case *alterTableNode:
 	case *alterSequenceNode:
+	case *alterTypeAddValueNode:
 	case *alterUserSetPasswordNode:
 	case *commentOnColumnNode:
 	case *commentOnDatabaseNode:
@@ -874,14 +917,19 @@ func (p *planner) simplifyOrderings(plan planNode, usefulOrdering sqlbase.Column
 	case *createDatabaseNode:
 	case *createIndexNode:
 	case *CreateUserNode:
-	case *createViewNode:
 	case *createSequenceNode:
+	case *createTypeNode:
+	case *createFunctionNode:
+	case *createSchemaNode:
 	case *createStatsNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *dropSequenceNode:
+	case *dropTypeNode:
+	case *dropFunctionNode:
+	case *dropSchemaNode:
 	case *DropUserNode:
 	case *zeroNode:
 	case *unaryNode:
@@ -889,6 +937,8 @@ func (p *planner) simplifyOrderings(plan planNode, usefulOrdering sqlbase.Column
 	case *sequenceSelectNode:
 	case *setVarNode:
 	case *setClusterSettingNode:
+	case *setConstraintsNode:
+	case *fetchNode:
 	case *setZoneConfigNode:
 	case *showZoneConfigNode:
 	case *showFingerprintsNode:
@@ -361,12 +361,22 @@ func (n *groupNode) accumulateRow(params runParams, values tree.Datums) error {
 			continue
 		}
 
-		var value tree.Datum
-		if f.argRenderIdx != noRenderIdx {
-			value = values[f.argRenderIdx]
+		// Most functions require at most one argument thus we separate
+		// the first argument and allocation of (if applicable) a variadic
+		// collection of arguments thereafter.
+		var firstArg tree.Datum
+		var otherArgs tree.Datums
+		if len(f.argRenderIdxs) > 0 {
+			firstArg = values[f.argRenderIdxs[0]]
+		}
+		if len(f.argRenderIdxs) > 1 {
+			otherArgs = make(tree.Datums, len(f.argRenderIdxs)-1)
+			for j, idx := range f.argRenderIdxs[1:] {
+				otherArgs[j] = values[idx]
+			}
 		}
 
-		if err := f.add(params.ctx, params.EvalContext(), bucket, value); err != nil {
+		if err := f.add(params.ctx, params.EvalContext(), bucket, firstArg, otherArgs); err != nil {
 			return err
 		}
 	}
@@ -540,9 +550,9 @@ func (n *groupNode) desiredAggregateOrdering(evalCtx *tree.EvalContext) sqlbase.
 	impl := f.create(evalCtx, nil /* arguments */)
 	switch impl.(type) {
 	case *builtins.MinAggregate:
-		return sqlbase.ColumnOrdering{{ColIdx: f.argRenderIdx, Direction: encoding.Ascending}}
+		return sqlbase.ColumnOrdering{{ColIdx: f.argRenderIdxs[0], Direction: encoding.Ascending}}
 	case *builtins.MaxAggregate:
-		return sqlbase.ColumnOrdering{{ColIdx: f.argRenderIdx, Direction: encoding.Descending}}
+		return sqlbase.ColumnOrdering{{ColIdx: f.argRenderIdxs[0], Direction: encoding.Descending}}
 	}
 	return nil
 }
@@ -553,7 +563,7 @@ func (n *groupNode) desiredAggregateOrdering(evalCtx *tree.EvalContext) sqlbase.
 func (n *groupNode) aggIsGroupingColumn(aggIdx int) (colIdx int, ok bool) {
 	if holder := n.funcs[aggIdx]; holder.funcName == builtins.AnyNotNull {
 		for _, c := range n.groupCols {
-			if c == holder.argRenderIdx {
+			if c == holder.argRenderIdxs[0] {
 				return c, true
 			}
 		}
@@ -623,7 +633,7 @@ func (v *extractAggregatesVisitor) VisitPre(expr tree.Expr) (recurse bool, newEx
 		f := v.groupNode.newAggregateFuncHolder(
 			builtins.AnyNotNull,
 			v.preRender.render[groupIdx].ResolvedType(),
-			groupIdx,
+			[]int{groupIdx},
 			builtins.NewAnyNotNullAggregate,
 			nil,
 			v.planner.EvalContext().Mon.MakeBoundAccount(),
@@ -636,63 +646,63 @@ func (v *extractAggregatesVisitor) VisitPre(expr tree.Expr) (recurse bool, newEx
 	case *tree.FuncExpr:
 		if agg := t.GetAggregateConstructor(); agg != nil {
 			var f *aggregateFuncHolder
-			if len(t.Exprs) == 0 {
+			args := t.AggregateArgs()
+			if len(args) == 0 {
 				// COUNT_ROWS has no arguments.
 				f = v.groupNode.newAggregateFuncHolder(
 					t.Func.String(),
 					t.ResolvedType(),
-					noRenderIdx,
+					nil, /* argRenderIdxs */
 					agg,
 					nil,
 					v.planner.EvalContext().Mon.MakeBoundAccount(),
 				)
 			} else {
-				// Only the first argument can be an expression, all the following ones
-				// must be consts. So before we proceed, they must be checked.
-				arguments := make(tree.Datums, len(t.Exprs)-1)
-				if len(t.Exprs) > 1 {
-					evalContext := v.planner.EvalContext()
-					for i := 1; i < len(t.Exprs); i++ {
-						if !tree.IsConst(evalContext, t.Exprs[i]) {
-							v.err = pgerror.UnimplementedWithIssueError(28417, "aggregate functions with multiple non-constant expressions are not supported")
-							return false, expr
-						}
-						var err error
-						arguments[i-1], err = t.Exprs[i].(tree.TypedExpr).Eval(evalContext)
+				// The first argument is always rendered. The following ones are
+				// passed to the aggregate function as constant arguments if they
+				// are constants, and are rendered otherwise.
+				var arguments tree.Datums
+				evalContext := v.planner.EvalContext()
+				argRenderIdxs := make([]int, 0, len(args))
+				for i := range args {
+					argExpr := args[i].(tree.TypedExpr)
+					if i > 0 && tree.IsConst(evalContext, argExpr) {
+						d, err := argExpr.Eval(evalContext)
 						if err != nil {
-							v.err = pgerror.NewAssertionErrorf("can't evaluate %s - %v", t.Exprs[i].String(), err)
+							v.err = pgerror.NewAssertionErrorf("can't evaluate %s - %v", argExpr.String(), err)
 							return false, expr
 						}
+						arguments = append(arguments, d)
+						continue
 					}
-				}
 
-				argExpr := t.Exprs[0].(tree.TypedExpr)
-
-				// TODO(knz): it's really a shame that we need to recurse
-				// through the sub-tree to determine whether the arguments
-				// don't contain invalid functions. This really would want to
-				// be checked on the return path of the recursion.
-				// See issue #26425.
-				if v.planner.txCtx.WindowFuncInExpr(argExpr) {
-					v.err = sqlbase.NewWindowInAggError()
-					return false, expr
-				} else if v.planner.txCtx.AggregateInExpr(argExpr, v.planner.SessionData().SearchPath) {
-					v.err = sqlbase.NewAggInAggError()
-					return false, expr
-				}
+					// TODO(knz): it's really a shame that we need to recurse
+					// through the sub-tree to determine whether the arguments
+					// don't contain invalid functions. This really would want to
+					// be checked on the return path of the recursion.
+					// See issue #26425.
+					if v.planner.txCtx.WindowFuncInExpr(argExpr) {
+						v.err = sqlbase.NewWindowInAggError()
+						return false, expr
+					} else if v.planner.txCtx.AggregateInExpr(argExpr, v.planner.SessionData().SearchPath) {
+						v.err = sqlbase.NewAggInAggError()
+						return false, expr
+					}
 
-				// Add a pre-rendering for the argument.
-				col := sqlbase.ResultColumn{
-					Name: argExpr.String(),
-					Typ:  argExpr.ResolvedType(),
+					// Add a pre-rendering for the argument.
+					col := sqlbase.ResultColumn{
+						Name: argExpr.String(),
+						Typ:  argExpr.ResolvedType(),
+					}
+					argRenderIdxs = append(
+						argRenderIdxs, v.preRender.addOrReuseRender(col, argExpr, true /* reuse */),
+					)
 				}
 
-				argRenderIdx := v.preRender.addOrReuseRender(col, argExpr, true /* reuse */)
-
 				f = v.groupNode.newAggregateFuncHolder(
 					t.Func.String(),
 					t.ResolvedType(),
-					argRenderIdx,
+					argRenderIdxs,
 					agg,
 					arguments,
 					v.planner.EvalContext().Mon.MakeBoundAccount(),
@@ -759,10 +769,11 @@ type aggregateFuncHolder struct {
 
 	resultType types.T
 
-	// The argument of the function is a single value produced by the renderNode
-	// underneath. If the function has no argument (COUNT_ROWS), it is set to
-	// noRenderIdx.
-	argRenderIdx int
+	// The arguments of the function are values produced by the renderNode
+	// underneath. If the function has no argument (COUNT_ROWS), it is empty.
+	// Constant arguments other than the first one are not rendered; see
+	// arguments.
+	argRenderIdxs []int
 	// If there is a filter, the result is a single value produced by the
 	// renderNode underneath. If there is no filter, it is set to noRenderIdx.
 	filterRenderIdx int
@@ -794,11 +805,11 @@ const noRenderIdx = -1
 // a group-by column and the "aggregation" returns its value)
 //
 // If the aggregation function takes no arguments (e.g. COUNT_ROWS),
-// argRenderIdx is noRenderIdx.
+// argRenderIdxs is empty.
 func (n *groupNode) newAggregateFuncHolder(
 	funcName string,
 	resultType types.T,
-	argRenderIdx int,
+	argRenderIdxs []int,
 	create func(*tree.EvalContext, tree.Datums) tree.AggregateFunc,
 	arguments tree.Datums,
 	acc mon.BoundAccount,
@@ -806,7 +817,7 @@ func (n *groupNode) newAggregateFuncHolder(
 	res := &aggregateFuncHolder{
 		funcName:        funcName,
 		resultType:      resultType,
-		argRenderIdx:    argRenderIdx,
+		argRenderIdxs:   argRenderIdxs,
 		filterRenderIdx: noRenderIdx,
 		create:          create,
 		arguments:       arguments,
@@ -838,8 +849,16 @@ func (a *aggregateFuncHolder) isDistinct() bool {
 }
 
 func aggregateFuncsEqual(a, b *aggregateFuncHolder) bool {
-	return a.funcName == b.funcName && a.resultType == b.resultType &&
-		a.argRenderIdx == b.argRenderIdx && a.filterRenderIdx == b.filterRenderIdx
+	if a.funcName != b.funcName || a.resultType != b.resultType ||
+		a.filterRenderIdx != b.filterRenderIdx || len(a.argRenderIdxs) != len(b.argRenderIdxs) {
+		return false
+	}
+	for i := range a.argRenderIdxs {
+		if a.argRenderIdxs[i] != b.argRenderIdxs[i] {
+			return false
+		}
+	}
+	return true
 }
 
 func (a *aggregateFuncHolder) close(ctx context.Context) {
@@ -856,16 +875,27 @@ func (a *aggregateFuncHolder) close(ctx context.Context) {
 // add accumulates one more value for a particular bucket into an aggregation
 // function.
 func (a *aggregateFuncHolder) add(
-	ctx context.Context, evalCtx *tree.EvalContext, bucket []byte, d tree.Datum,
+	ctx context.Context,
+	evalCtx *tree.EvalContext,
+	bucket []byte,
+	firstArg tree.Datum,
+	otherArgs tree.Datums,
 ) error {
 	// NB: the compiler *should* optimize `myMap[string(myBytes)]`. See:
 	// https://github.com/golang/go/commit/f5f5a8b6209f84961687d993b93ea0d397f5d5bf
 
 	if a.run.seen != nil {
-		encoded, err := sqlbase.EncodeDatumKeyAscending(bucket, d)
+		encoded, err := sqlbase.EncodeDatumKeyAscending(bucket, firstArg)
 		if err != nil {
 			return err
 		}
+		// Encode additional arguments if necessary.
+		if otherArgs != nil {
+			encoded, err = sqlbase.EncodeDatumsKeyAscending(encoded, otherArgs)
+			if err != nil {
+				return err
+			}
+		}
 		if _, ok := a.run.seen[string(encoded)]; ok {
 			// skip
 			return nil
@@ -882,5 +912,5 @@ func (a *aggregateFuncHolder) add(
 		a.run.buckets[string(bucket)] = impl
 	}
 
-	return impl.Add(ctx, d)
+	return impl.Add(ctx, firstArg, otherArgs...)
 }
@@ -379,8 +379,7 @@ func numericScale(colType sqlbase.ColumnType) tree.Datum {
 }
 
 func datetimePrecision(colType sqlbase.ColumnType) tree.Datum {
-	// We currently do not support a datetime precision.
-	return tree.DNull
+	return dIntFnOrNull(colType.DatetimePrecision)
 }
 
 // Postgres: https://www.postgresql.org/docs/9.6/static/infoschema-constraint-column-usage.html
@@ -1003,6 +1002,10 @@ CREATE TABLE information_schema.table_constraints (
 				tbNameStr := tree.NewDString(table.Name)
 
 				for conName, c := range conInfo {
+					deferrable, initiallyDeferred := false, false
+					if c.Kind == sqlbase.ConstraintTypeFK {
+						deferrable, initiallyDeferred = c.FK.Deferrable, c.FK.InitiallyDeferred
+					}
 					if err := addRow(
 						dbNameStr,                       // constraint_catalog
 						scNameStr,                       // constraint_schema
@@ -1011,8 +1014,8 @@ CREATE TABLE information_schema.table_constraints (
 						scNameStr,                       // table_schema
 						tbNameStr,                       // table_name
 						tree.NewDString(string(c.Kind)), // constraint_type
-						yesOrNoDatum(false),             // is_deferrable
-						yesOrNoDatum(false),             // initially_deferred
+						yesOrNoDatum(deferrable),        // is_deferrable
+						yesOrNoDatum(initiallyDeferred), // initially_deferred
 					); err != nil {
 						return err
 					}
@@ -1100,9 +1103,10 @@ func populateTablePrivileges(
 }
 
 var (
-	tableTypeSystemView = tree.NewDString("SYSTEM VIEW")
-	tableTypeBaseTable  = tree.NewDString("BASE TABLE")
-	tableTypeView       = tree.NewDString("VIEW")
+	tableTypeSystemView       = tree.NewDString("SYSTEM VIEW")
+	tableTypeBaseTable        = tree.NewDString("BASE TABLE")
+	tableTypeView             = tree.NewDString("VIEW")
+	tableTypeMaterializedView = tree.NewDString("MATERIALIZED VIEW")
 )
 
 var informationSchemaTablesTable = virtualSchemaTable{
@@ -1118,6 +1122,9 @@ var informationSchemaTablesTable = virtualSchemaTable{
 				if isVirtualDescriptor(table) {
 					tableType = tableTypeSystemView
 					insertable = noString
+				} else if table.MaterializedView() {
+					tableType = tableTypeMaterializedView
+					insertable = noString
 				} else if table.IsView() {
 					tableType = tableTypeView
 					insertable = noString
@@ -1156,7 +1163,7 @@ CREATE TABLE information_schema.views (
 	populate: func(ctx context.Context, p *planner, dbContext *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
 		return forEachTableDesc(ctx, p, dbContext, hideVirtual, /* virtual schemas have no views */
 			func(db *sqlbase.DatabaseDescriptor, scName string, table *sqlbase.TableDescriptor) error {
-				if !table.IsView() {
+				if !table.IsView() || table.MaterializedView() {
 					return nil
 				}
 				// Note that the view query printed will not include any column aliases
@@ -1192,6 +1199,26 @@ func forEachSchemaName(
 	for _, schema := range p.getVirtualTabler().getEntries() {
 		scNames = append(scNames, schema.desc.Name)
 	}
+	// Handle the temporary schema of the session.
+	if tempSchemaName := p.SessionData().SearchPath.GetTemporarySchemaName(); tempSchemaName != "" {
+		tempSchemaID, err := getTemporarySchemaID(ctx, p.txn, db.ID, tempSchemaName)
+		if err != nil {
+			return err
+		}
+		if tempSchemaID != 0 {
+			scNames = append(scNames, tempSchemaName)
+		}
+	}
+	// Handle the user-defined schemas of the database.
+	descs, err := p.Tables().getAllDescriptors(ctx, p.txn)
+	if err != nil {
+		return err
+	}
+	for _, desc := range descs {
+		if scDesc, ok := desc.(*sqlbase.SchemaDescriptor); ok && scDesc.ParentID == db.ID {
+			scNames = append(scNames, scDesc.Name)
+		}
+	}
 	sort.Strings(scNames)
 	for _, sc := range scNames {
 		if err := fn(sc); err != nil {
@@ -1233,6 +1260,37 @@ func forEachDatabaseDesc(
 	return nil
 }
 
+// forEachTypeDesc retrieves all the user-defined type descriptors of the
+// databases visible to the user and iterates through them in the order of
+// their IDs. For each type, the function will call fn with the descriptor of
+// its database and its own descriptor.
+func forEachTypeDesc(
+	ctx context.Context,
+	p *planner,
+	dbContext *DatabaseDescriptor,
+	fn func(*sqlbase.DatabaseDescriptor, *sqlbase.TypeDescriptor) error,
+) error {
+	descs, err := p.Tables().getAllDescriptors(ctx, p.txn)
+	if err != nil {
+		return err
+	}
+	lCtx := newInternalLookupCtx(descs, dbContext)
+	for _, id := range lCtx.tyIDs {
+		typ := lCtx.tyDescs[id]
+		db, err := lCtx.getDatabaseByID(typ.ParentID)
+		if err != nil {
+			return err
+		}
+		if !userCanSeeDatabase(ctx, p, db) {
+			continue
+		}
+		if err := fn(db, typ); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // forEachTableDesc retrieves all table descriptors from the current
 // database and all system databases and iterates through them. For
 // each table, the function will call fn with its respective database
@@ -1366,13 +1424,40 @@ func forEachTableDescWithTableLookupInternal(
 	}
 
 	// Physical descriptors next.
+	tempSchemaName := p.SessionData().SearchPath.GetTemporarySchemaName()
+	tempSchemaIDs := make(map[sqlbase.ID]sqlbase.ID)
 	for _, tbID := range lCtx.tbIDs {
 		table := lCtx.tbDescs[tbID]
 		dbDesc, parentExists := lCtx.dbDescs[table.GetParentID()]
 		if table.Dropped() || !userCanSeeTable(ctx, p, table, allowAdding) || !parentExists {
 			continue
 		}
-		if err := fn(dbDesc, tree.PublicSchema, table, lCtx); err != nil {
+		scName := tree.PublicSchema
+		if table.IsTemporary() {
+			// Temporary objects are only visible to the session that owns them.
+			if tempSchemaName == "" {
+				continue
+			}
+			tempSchemaID, ok := tempSchemaIDs[dbDesc.ID]
+			if !ok {
+				tempSchemaID, err = getTemporarySchemaID(ctx, p.txn, dbDesc.ID, tempSchemaName)
+				if err != nil {
+					return err
+				}
+				tempSchemaIDs[dbDesc.ID] = tempSchemaID
+			}
+			if table.TemporarySchemaID != tempSchemaID {
+				continue
+			}
+			scName = tempSchemaName
+		} else if table.SchemaID != 0 {
+			scDesc, ok := lCtx.scDescs[table.SchemaID]
+			if !ok {
+				continue
+			}
+			scName = scDesc.Name
+		}
+		if err := fn(dbDesc, scName, table, lCtx); err != nil {
 			return err
 		}
 	}
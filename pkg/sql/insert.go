@@ -270,6 +270,7 @@ func (p *planner) Insert(
 	if err != nil {
 		return nil, err
 	}
+	ri.SetDeferredFKChecks(p.deferredFKChecks())
 
 	// rowsNeeded will help determine whether we need to allocate a
 	// rowsContainer.
@@ -1230,7 +1230,7 @@ func (c *tableNameCache) insert(table *tableVersionState) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	key := makeTableNameCacheKey(table.ParentID, table.Name)
+	key := makeTableNameCacheKey(table.GetNamespaceParentID(), table.Name)
 	existing, ok := c.tables[key]
 	if !ok {
 		c.tables[key] = table
@@ -1250,7 +1250,7 @@ func (c *tableNameCache) remove(table *tableVersionState) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	key := makeTableNameCacheKey(table.ParentID, table.Name)
+	key := makeTableNameCacheKey(table.GetNamespaceParentID(), table.Name)
 	existing, ok := c.tables[key]
 	if !ok {
 		// Table for lease not found in table name cache. This can happen if we had
@@ -1345,7 +1345,7 @@ func (m *LeaseManager) SetExecCfg(execCfg *ExecutorConfig) {
 func nameMatchesTable(
 	table *sqlbase.ImmutableTableDescriptor, dbID sqlbase.ID, tableName string,
 ) bool {
-	return table.ParentID == dbID && table.Name == tableName
+	return table.GetNamespaceParentID() == dbID && table.Name == tableName
 }
 
 // findNewest returns the newest table version state for the tableID.
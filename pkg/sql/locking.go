@@ -0,0 +1,117 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// lockScan makes the given data source lock the rows it reads if it is a
+// table scan to which the locking clause of the SELECT statement being
+// planned applies under the given name. Locking the rows requires the UPDATE
+// privilege on the table.
+func (p *planner) lockScan(ctx context.Context, src planDataSource, name tree.Name) error {
+	scan, ok := src.plan.(*scanNode)
+	if !ok || len(p.lockingClause) == 0 {
+		return nil
+	}
+	strength, waitPolicy := p.lockingClause.Strength(name)
+	if strength != tree.ForNone {
+		if err := p.CheckPrivilege(ctx, scan.desc, privilege.UPDATE); err != nil {
+			return err
+		}
+	}
+	scan.setLocking(strength, waitPolicy)
+	return nil
+}
+
+// checkLockingStrengths verifies that the items of the given locking clause
+// only request exclusive locks.
+func checkLockingStrengths(locking tree.LockingClause) error {
+	for _, item := range locking {
+		if item.Strength == tree.ForKeyShare || item.Strength == tree.ForShare {
+			return sqlbase.NewLockingStrengthUnsupportedError(item.Strength)
+		}
+	}
+	return nil
+}
+
+// checkLockingClause verifies that the locking clause of the SELECT
+// statement being planned can be applied to the given SELECT clause, whose
+// FROM clause has already been planned into r.
+func (p *planner) checkLockingClause(parsed *tree.SelectClause, r *renderNode) error {
+	if len(p.lockingClause) == 0 {
+		return nil
+	}
+	strength := p.lockingClause[0].Strength
+	if with := lockingNotAllowedWith(parsed, r.renderProps); with != "" {
+		return sqlbase.NewLockingNotAllowedError(strength, with)
+	}
+	for _, item := range p.lockingClause {
+		for i := range item.Targets {
+			name := item.Targets[i].TableName
+			if !sourceAliasesContain(r.source.info.SourceAliases, name) {
+				return sqlbase.NewLockingTargetNotFoundError(item.Strength, name)
+			}
+		}
+	}
+	return nil
+}
+
+// sourceAliasesContain returns whether one of the source aliases has the
+// given unqualified name.
+func sourceAliasesContain(aliases sqlbase.SourceAliases, name tree.Name) bool {
+	for i := range aliases {
+		if aliases[i].Name.TableName == name {
+			return true
+		}
+	}
+	return false
+}
+
+// lockingNotAllowedWith returns the clause of the given statement which
+// prevents its rows from being locked, or the empty string if there is none.
+// props are the scalar properties of the statement's target list; they are
+// only used if the statement is a SELECT clause.
+func lockingNotAllowedWith(stmt tree.SelectStatement, props tree.ScalarProperties) string {
+	switch t := stmt.(type) {
+	case *tree.SelectClause:
+		switch {
+		case t.Distinct:
+			return "DISTINCT clause"
+		case len(t.GroupBy) > 0:
+			return "GROUP BY clause"
+		case t.Having != nil:
+			return "HAVING clause"
+		case props.SeenAggregate:
+			return "aggregate functions"
+		case len(t.Window) > 0 || props.SeenWindowApplication:
+			return "window functions"
+		case props.SeenGenerator:
+			return "set-returning functions in the target list"
+		}
+		return ""
+	case *tree.UnionClause:
+		return "UNION/INTERSECT/EXCEPT"
+	case *tree.ValuesClause:
+		return "VALUES"
+	default:
+		return stmt.StatementTag()
+	}
+}
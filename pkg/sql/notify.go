@@ -0,0 +1,545 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"hash/fnv"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/gossip"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// maxNotificationPayloadSize is the maximum length of the payload of a
+// notification. This is the same limit as Postgres.
+const maxNotificationPayloadSize = 8000
+
+// maxQueuedNotifications is the maximum number of notifications that can be
+// waiting to be delivered to the client of a session. Further notifications
+// are dropped until the client consumes the queued ones.
+const maxQueuedNotifications = 10000
+
+// notificationRetention is how long the notifications sent from a node are
+// kept in the batch it gossips. Gossip only propagates the latest value of a
+// key, so the batch must retain the notifications long enough for every node
+// to observe them, even if newer batches are gossiped in quick succession.
+const notificationRetention = 10 * time.Second
+
+// maxNotificationBatchSize is the maximum total size of the payloads of the
+// notifications retained in the batch gossiped by a node. NOTIFY fails when
+// it is reached, like in Postgres when the notification queue is full.
+const maxNotificationBatchSize = 1 << 20
+
+// notificationGossipTTL is how long the batch of notifications of a node
+// remains in gossip after it was last updated.
+const notificationGossipTTL = time.Minute
+
+// Notification is an asynchronous notification sent with NOTIFY or
+// pg_notify() to the sessions listening on a channel.
+type Notification struct {
+	Channel string
+	Payload string
+	// PID identifies the sending session. It stands in for the ID of the
+	// sending server process in Postgres. See backendPID.
+	PID int32
+}
+
+// backendPID derives the identifier reported as the PID of a session in the
+// notifications it sends from the session's cluster-wide ID.
+func backendPID(sessionID ClusterWideID) int32 {
+	h := fnv.New32a()
+	_, _ = h.Write(sessionID.GetBytes())
+	return int32(h.Sum32() &^ (1 << 31))
+}
+
+// NotificationQueue buffers the notifications received by a session until
+// they can be delivered to its client.
+type NotificationQueue struct {
+	// ready is signaled when notifications are added to the queue.
+	ready chan struct{}
+	// dropEvery rate limits the warnings about dropped notifications.
+	dropEvery log.EveryN
+
+	mu struct {
+		syncutil.Mutex
+		notifications []Notification
+		// dropped is the number of notifications dropped because the queue
+		// was full since the last warning.
+		dropped int
+	}
+}
+
+func newNotificationQueue() *NotificationQueue {
+	return &NotificationQueue{
+		ready:     make(chan struct{}, 1),
+		dropEvery: log.Every(10 * time.Second),
+	}
+}
+
+// Ready returns a channel that receives a value when notifications have been
+// added to the queue since the last time it was received from.
+func (q *NotificationQueue) Ready() <-chan struct{} {
+	return q.ready
+}
+
+// Take removes all the notifications from the queue and returns them.
+func (q *NotificationQueue) Take() []Notification {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	n := q.mu.notifications
+	q.mu.notifications = nil
+	return n
+}
+
+func (q *NotificationQueue) push(ctx context.Context, n Notification) {
+	q.mu.Lock()
+	if len(q.mu.notifications) >= maxQueuedNotifications {
+		q.mu.dropped++
+		if q.dropEvery.ShouldLog() {
+			log.Warningf(ctx, "dropped %d notifications because the client of the "+
+				"session does not consume them fast enough", q.mu.dropped)
+			q.mu.dropped = 0
+		}
+		q.mu.Unlock()
+		return
+	}
+	q.mu.notifications = append(q.mu.notifications, n)
+	q.mu.Unlock()
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// sequencedNotification is a notification sent from a node, along with its
+// sequence number on that node.
+type sequencedNotification struct {
+	seq int64
+	// sent is when the notification was published. It is only set on the
+	// sending node.
+	sent time.Time
+	Notification
+}
+
+// notificationSource tracks the notifications received from another node.
+type notificationSource struct {
+	// firstSeq identifies the incarnation of the registry of the node; it is
+	// the first sequence number the registry assigned.
+	firstSeq int64
+	// lastSeq is the sequence number of the last notification delivered.
+	lastSeq int64
+}
+
+// NotificationRegistry delivers the notifications sent on any node of the
+// cluster to the sessions of this node that listen on their channel.
+//
+// Each node gossips the notifications sent from it during the last
+// notificationRetention under a single key, which is updated whenever new
+// notifications are sent. The notifications of a node are numbered, so that
+// the other nodes deliver each of them once and in order, however many of the
+// node's batches they observe.
+type NotificationRegistry struct {
+	gossip *gossip.Gossip
+	nodeID *base.NodeIDContainer
+
+	mu struct {
+		syncutil.Mutex
+		// firstSeq and seq are the first and the last sequence numbers assigned
+		// to the notifications sent from this node.
+		firstSeq, seq int64
+		// batch holds the notifications sent from this node during the last
+		// notificationRetention, in order, and batchSize is the total size of
+		// their payloads.
+		batch     []sequencedNotification
+		batchSize int
+		// sources tracks the notifications received from the other nodes.
+		sources map[roachpb.NodeID]*notificationSource
+		// listeners maps channels to the queues of the sessions that listen on
+		// them.
+		listeners map[string]map[*NotificationQueue]struct{}
+	}
+}
+
+// NewNotificationRegistry creates a NotificationRegistry. The gossip can be
+// nil, in which case notifications are only delivered to the sessions of this
+// node.
+func NewNotificationRegistry(
+	g *gossip.Gossip, nodeID *base.NodeIDContainer,
+) *NotificationRegistry {
+	r := &NotificationRegistry{gossip: g, nodeID: nodeID}
+	// Start the sequence from the current time, so that the other nodes can
+	// tell apart the notifications sent before and after a restart.
+	r.mu.firstSeq = timeutil.Now().UnixNano()
+	r.mu.seq = r.mu.firstSeq - 1
+	r.mu.sources = make(map[roachpb.NodeID]*notificationSource)
+	r.mu.listeners = make(map[string]map[*NotificationQueue]struct{})
+	if g != nil {
+		g.RegisterCallback(
+			gossip.MakePrefixPattern(gossip.KeySQLNotificationPrefix),
+			r.notificationGossipUpdate,
+		)
+	}
+	return r
+}
+
+func (r *NotificationRegistry) listen(channel string, q *NotificationQueue) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	qs, ok := r.mu.listeners[channel]
+	if !ok {
+		qs = make(map[*NotificationQueue]struct{})
+		r.mu.listeners[channel] = qs
+	}
+	qs[q] = struct{}{}
+}
+
+func (r *NotificationRegistry) unlisten(channel string, q *NotificationQueue) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	qs := r.mu.listeners[channel]
+	delete(qs, q)
+	if len(qs) == 0 {
+		delete(r.mu.listeners, channel)
+	}
+}
+
+// checkBatchSize returns an error if the batch of notifications of this node
+// is full.
+func (r *NotificationRegistry) checkBatchSize() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.trimBatchLocked(timeutil.Now())
+	if r.mu.batchSize >= maxNotificationBatchSize {
+		return pgerror.NewError(pgerror.CodeProgramLimitExceededError,
+			"too many notifications in the NOTIFY queue")
+	}
+	return nil
+}
+
+// trimBatchLocked removes from the batch of notifications of this node the
+// ones which were sent longer than notificationRetention ago.
+func (r *NotificationRegistry) trimBatchLocked(now time.Time) {
+	i := 0
+	for ; i < len(r.mu.batch) && now.Sub(r.mu.batch[i].sent) > notificationRetention; i++ {
+		r.mu.batchSize -= len(r.mu.batch[i].Payload)
+	}
+	r.mu.batch = r.mu.batch[i:]
+}
+
+// publish delivers notifications to the sessions of this node, and sends them
+// to the other nodes.
+func (r *NotificationRegistry) publish(ctx context.Context, notifications []Notification) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliverLocked(ctx, notifications)
+	if r.gossip == nil {
+		return
+	}
+	now := timeutil.Now()
+	r.trimBatchLocked(now)
+	for _, n := range notifications {
+		r.mu.seq++
+		r.mu.batch = append(r.mu.batch, sequencedNotification{seq: r.mu.seq, sent: now, Notification: n})
+		r.mu.batchSize += len(n.Payload)
+	}
+	key := gossip.MakeSQLNotificationKey(r.nodeID.Get())
+	b := encodeNotificationBatch(r.mu.firstSeq, r.mu.batch)
+	if err := r.gossip.AddInfo(key, b, notificationGossipTTL); err != nil {
+		log.Warningf(ctx, "failed to gossip notifications: %v", err)
+	}
+}
+
+// notificationGossipUpdate is the gossip callback that fires when the batch of
+// notifications of a node is updated.
+func (r *NotificationRegistry) notificationGossipUpdate(key string, value roachpb.Value) {
+	ctx := context.Background()
+	nodeID, err := gossip.NodeIDFromKey(key, gossip.KeySQLNotificationPrefix)
+	if err != nil {
+		log.Errorf(ctx, "notificationGossipUpdate(%s) error: %v", key, err)
+		return
+	}
+	if nodeID == r.nodeID.Get() {
+		// The notifications of this node were delivered when they were
+		// published.
+		return
+	}
+	b, err := value.GetBytes()
+	if err != nil {
+		log.Errorf(ctx, "notificationGossipUpdate(%s) error: %v", key, err)
+		return
+	}
+	firstSeq, batch, err := decodeNotificationBatch(b)
+	if err != nil {
+		log.Errorf(ctx, "notificationGossipUpdate(%s) error: %v", key, err)
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.receiveLocked(ctx, nodeID, firstSeq, batch)
+}
+
+// receiveLocked delivers the notifications of the batch of the given node
+// which haven't been delivered yet.
+func (r *NotificationRegistry) receiveLocked(
+	ctx context.Context, nodeID roachpb.NodeID, firstSeq int64, batch []sequencedNotification,
+) {
+	if len(batch) == 0 {
+		return
+	}
+	src, ok := r.mu.sources[nodeID]
+	if !ok || src.firstSeq != firstSeq {
+		// This is the first batch of this incarnation of the node observed by
+		// this node. The notifications which are no longer part of it were
+		// sent before this node could observe them.
+		src = &notificationSource{firstSeq: firstSeq, lastSeq: batch[0].seq - 1}
+		r.mu.sources[nodeID] = src
+	}
+	for _, n := range batch {
+		if n.seq <= src.lastSeq {
+			// Already delivered.
+			continue
+		}
+		if lost := n.seq - src.lastSeq - 1; lost > 0 {
+			log.Warningf(ctx, "lost %d notifications from node %d", lost, nodeID)
+		}
+		r.deliverLocked(ctx, []Notification{n.Notification})
+		src.lastSeq = n.seq
+	}
+}
+
+func (r *NotificationRegistry) deliverLocked(ctx context.Context, notifications []Notification) {
+	for _, n := range notifications {
+		for q := range r.mu.listeners[n.Channel] {
+			q.push(ctx, n)
+		}
+	}
+}
+
+// encodeNotificationBatch encodes the batch of notifications gossiped by a
+// node. firstSeq identifies the incarnation of the node's registry.
+func encodeNotificationBatch(firstSeq int64, batch []sequencedNotification) []byte {
+	b := encoding.EncodeVarintAscending(nil, firstSeq)
+	for _, n := range batch {
+		b = encoding.EncodeVarintAscending(b, n.seq)
+		b = encoding.EncodeBytesAscending(b, []byte(n.Channel))
+		b = encoding.EncodeBytesAscending(b, []byte(n.Payload))
+		b = encoding.EncodeVarintAscending(b, int64(n.PID))
+	}
+	return b
+}
+
+func decodeNotificationBatch(b []byte) (int64, []sequencedNotification, error) {
+	b, firstSeq, err := encoding.DecodeVarintAscending(b)
+	if err != nil {
+		return 0, nil, err
+	}
+	var batch []sequencedNotification
+	for len(b) > 0 {
+		var seq, pid int64
+		var channel, payload []byte
+		if b, seq, err = encoding.DecodeVarintAscending(b); err != nil {
+			return 0, nil, err
+		}
+		if b, channel, err = encoding.DecodeBytesAscending(b, nil); err != nil {
+			return 0, nil, err
+		}
+		if b, payload, err = encoding.DecodeBytesAscending(b, nil); err != nil {
+			return 0, nil, err
+		}
+		if b, pid, err = encoding.DecodeVarintAscending(b); err != nil {
+			return 0, nil, err
+		}
+		batch = append(batch, sequencedNotification{
+			seq: seq,
+			Notification: Notification{
+				Channel: string(channel),
+				Payload: string(payload),
+				PID:     int32(pid),
+			},
+		})
+	}
+	return firstSeq, batch, nil
+}
+
+// sessionNotifications is the LISTEN/NOTIFY state of a session. Like in
+// Postgres, the effects of LISTEN, UNLISTEN and NOTIFY are deferred until the
+// transaction that runs them commits.
+type sessionNotifications struct {
+	registry *NotificationRegistry
+	queue    *NotificationQueue
+	// pid is reported as the PID of the session in the notifications it
+	// sends. It is set once the session has an ID.
+	pid int32
+	// channels is the set of channels the session listens on.
+	channels map[string]struct{}
+
+	// unlistenAll is set if the current transaction ran UNLISTEN *. It applies
+	// before the changes in listens.
+	unlistenAll bool
+	// listens records the channels the current transaction started (true) or
+	// stopped (false) listening on.
+	listens map[string]bool
+	// pending are the notifications sent by the current transaction. Duplicate
+	// notifications are folded.
+	pending     []Notification
+	pendingKeys map[Notification]struct{}
+}
+
+func makeSessionNotifications(registry *NotificationRegistry) sessionNotifications {
+	return sessionNotifications{
+		registry: registry,
+		queue:    newNotificationQueue(),
+		channels: make(map[string]struct{}),
+	}
+}
+
+func (s *sessionNotifications) listen(channel string) {
+	if s.listens == nil {
+		s.listens = make(map[string]bool)
+	}
+	s.listens[channel] = true
+}
+
+// unlisten stops listening on the given channel, or on all channels if the
+// channel is empty.
+func (s *sessionNotifications) unlisten(channel string) {
+	if channel == "" {
+		s.unlistenAll = true
+		s.listens = nil
+		return
+	}
+	if s.listens == nil {
+		s.listens = make(map[string]bool)
+	}
+	s.listens[channel] = false
+}
+
+func (s *sessionNotifications) notify(n Notification) {
+	if _, ok := s.pendingKeys[n]; ok {
+		return
+	}
+	if s.pendingKeys == nil {
+		s.pendingKeys = make(map[Notification]struct{})
+	}
+	s.pendingKeys[n] = struct{}{}
+	s.pending = append(s.pending, n)
+}
+
+// commitTxn applies the changes made by the transaction that just committed,
+// and sends its notifications.
+func (s *sessionNotifications) commitTxn(ctx context.Context) {
+	if s.registry == nil {
+		s.abortTxn()
+		return
+	}
+	if s.unlistenAll {
+		for channel := range s.channels {
+			s.registry.unlisten(channel, s.queue)
+			delete(s.channels, channel)
+		}
+	}
+	for channel, listen := range s.listens {
+		_, listening := s.channels[channel]
+		if listen && !listening {
+			s.registry.listen(channel, s.queue)
+			s.channels[channel] = struct{}{}
+		} else if !listen && listening {
+			s.registry.unlisten(channel, s.queue)
+			delete(s.channels, channel)
+		}
+	}
+	if len(s.pending) > 0 {
+		s.registry.publish(ctx, s.pending)
+	}
+	s.abortTxn()
+}
+
+// abortTxn discards the changes made by the current transaction.
+func (s *sessionNotifications) abortTxn() {
+	s.unlistenAll = false
+	s.listens = nil
+	s.pending = nil
+	s.pendingKeys = nil
+}
+
+// close stops listening on all channels.
+func (s *sessionNotifications) close() {
+	s.abortTxn()
+	for channel := range s.channels {
+		s.registry.unlisten(channel, s.queue)
+		delete(s.channels, channel)
+	}
+}
+
+// Listen implements the LISTEN statement.
+// See https://www.postgresql.org/docs/current/static/sql-listen.html for details.
+// Privileges: None.
+func (p *planner) Listen(ctx context.Context, n *tree.Listen) (planNode, error) {
+	p.notifications.listen(string(n.Channel))
+	return newZeroNode(nil /* columns */), nil
+}
+
+// Unlisten implements the UNLISTEN statement.
+// See https://www.postgresql.org/docs/current/static/sql-unlisten.html for details.
+// Privileges: None.
+func (p *planner) Unlisten(ctx context.Context, n *tree.Unlisten) (planNode, error) {
+	p.notifications.unlisten(string(n.Channel))
+	return newZeroNode(nil /* columns */), nil
+}
+
+// Notify implements the NOTIFY statement.
+// See https://www.postgresql.org/docs/current/static/sql-notify.html for details.
+// Privileges: None.
+func (p *planner) Notify(ctx context.Context, n *tree.Notify) (planNode, error) {
+	if err := p.SendNotification(ctx, string(n.Channel), n.Payload); err != nil {
+		return nil, err
+	}
+	return newZeroNode(nil /* columns */), nil
+}
+
+// SendNotification implements the tree.EvalPlanner interface.
+func (p *planner) SendNotification(ctx context.Context, channel, payload string) error {
+	if p.notifications == nil {
+		return pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+			"notifications cannot be sent in this context")
+	}
+	if channel == "" {
+		return pgerror.NewError(pgerror.CodeInvalidParameterValueError,
+			"channel name cannot be empty")
+	}
+	if len(payload) >= maxNotificationPayloadSize {
+		return pgerror.NewError(pgerror.CodeInvalidParameterValueError,
+			"payload string too long")
+	}
+	if r := p.notifications.registry; r != nil {
+		if err := r.checkBatchSize(); err != nil {
+			return err
+		}
+	}
+	p.notifications.notify(Notification{
+		Channel: channel,
+		Payload: payload,
+		PID:     p.notifications.pid,
+	})
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/gossip"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+// TestNotificationRegistryReceive verifies that the notifications gossiped by
+// another node are delivered once and in order, however many of the node's
+// batches are observed.
+func TestNotificationRegistryReceive(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	r := NewNotificationRegistry(nil /* gossip */, &base.NodeIDContainer{})
+	q := newNotificationQueue()
+	r.listen("foo", q)
+
+	key := gossip.MakeSQLNotificationKey(2)
+	receive := func(firstSeq int64, seqs ...int64) {
+		t.Helper()
+		var batch []sequencedNotification
+		for _, seq := range seqs {
+			batch = append(batch, sequencedNotification{
+				seq:          seq,
+				Notification: Notification{Channel: "foo", Payload: string('a' + rune(seq%26)), PID: 7},
+			})
+		}
+		var v roachpb.Value
+		v.SetBytes(encodeNotificationBatch(firstSeq, batch))
+		r.notificationGossipUpdate(key, v)
+	}
+	expect := func(payloads ...string) {
+		t.Helper()
+		var res []string
+		for _, n := range q.Take() {
+			if n.Channel != "foo" || n.PID != 7 {
+				t.Fatalf("unexpected notification %+v", n)
+			}
+			res = append(res, n.Payload)
+		}
+		if !reflect.DeepEqual(res, payloads) {
+			t.Fatalf("expected %v, got %v", payloads, res)
+		}
+	}
+
+	receive(0, 0, 1)
+	expect("a", "b")
+	// The notifications which were already delivered are skipped.
+	receive(0, 0, 1, 2)
+	expect("c")
+	receive(0, 2, 3)
+	expect("d")
+	// An older batch is ignored.
+	receive(0, 0, 1)
+	expect()
+	// The node restarted.
+	receive(26, 26, 27)
+	expect("a", "b")
+}
@@ -117,6 +117,12 @@ type Index interface {
 	// of an outbound foreign key relation. Returns false for the second
 	// return value if there is no foreign key reference on this index.
 	ForeignKey() (ForeignKeyReference, bool)
+
+	// Predicate returns the string representation of the predicate of a
+	// partial index, and true. A partial index only contains entries for the
+	// rows that satisfy its predicate. Returns false for the second return
+	// value if the index is not partial.
+	Predicate() (string, bool)
 }
 
 // IndexColumn describes a single column that is part of an index definition.
@@ -34,6 +34,11 @@ type Table interface {
 	// information_schema tables.
 	IsVirtualTable() bool
 
+	// IsMaterializedView returns true if this table stores the results of a
+	// materialized view. Such tables can be read and indexed, but their rows
+	// can only be changed by refreshing the view.
+	IsMaterializedView() bool
+
 	// ColumnCount returns the number of columns in the table.
 	ColumnCount() int
 
@@ -182,6 +187,10 @@ func formatCatalogIndex(idx Index, isPrimary bool, tp treeprinter.Node) {
 
 		child.Child(buf.String())
 	}
+
+	if pred, isPartial := idx.Predicate(); isPartial {
+		child.Childf("WHERE %s", pred)
+	}
 }
 
 // formatColPrefix returns a string representation of the first prefixLen columns of idx.
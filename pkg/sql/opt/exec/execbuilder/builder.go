@@ -35,6 +35,11 @@ type Builder struct {
 	// expressions we built. Each entry is associated with a tree.Subquery
 	// expression node.
 	subqueries []exec.Subquery
+
+	// workTables maps the ID of a recursive CTE to the node that provides its
+	// working table; it is used to build WorkTableScan operators inside the
+	// recursive side of the CTE.
+	workTables map[int]exec.Node
 }
 
 // New constructs an instance of the execution node builder using the
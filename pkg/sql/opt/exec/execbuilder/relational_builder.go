@@ -205,6 +205,12 @@ func (b *Builder) buildRelational(e memo.RelExpr) (execPlan, error) {
 	case *memo.ProjectSetExpr:
 		ep, err = b.buildProjectSet(t)
 
+	case *memo.RecursiveCTEExpr:
+		ep, err = b.buildRecursiveCTE(t)
+
+	case *memo.WorkTableScanExpr:
+		ep, err = b.buildWorkTableScan(t)
+
 	case *memo.InsertExpr:
 		ep, err = b.buildInsert(t)
 
@@ -345,6 +351,10 @@ func (b *Builder) buildScan(scan *memo.ScanExpr) (execPlan, error) {
 		var err error
 		if idx.IsInverted() {
 			err = fmt.Errorf("index \"%s\" is inverted and cannot be used for this query", idx.Name())
+		} else if _, isPartial := idx.Predicate(); isPartial {
+			err = fmt.Errorf(
+				"index \"%s\" is a partial index whose predicate is not implied by the query filter",
+				idx.Name())
 		} else {
 			// This should never happen.
 			err = fmt.Errorf("index \"%s\" cannot be used for this query", idx.Name())
@@ -365,6 +375,8 @@ func (b *Builder) buildScan(scan *memo.ScanExpr) (execPlan, error) {
 		ordering.ScanIsReverse(scan, &scan.RequiredPhysical().Ordering),
 		b.indexConstraintMaxResults(scan),
 		res.reqOrdering(scan),
+		scan.Locking.Strength,
+		scan.Locking.WaitPolicy,
 	)
 	if err != nil {
 		return execPlan{}, err
@@ -647,6 +659,14 @@ func (b *Builder) buildGroupBy(groupBy memo.RelExpr) (execPlan, error) {
 				return execPlan{}, errors.Errorf("only VariableOp args supported")
 			}
 			argIdx = []exec.ColumnOrdinal{input.getColumnOrdinal(v.Col)}
+
+			// Arguments after the first one that are not constants refer to
+			// input columns as well.
+			for j, n := 1, item.Agg.ChildCount(); j < n; j++ {
+				if v, ok := item.Agg.Child(j).(*memo.VariableExpr); ok {
+					argIdx = append(argIdx, input.getColumnOrdinal(v.Col))
+				}
+			}
 		}
 
 		constArgs := b.extractAggregateConstArgs(item.Agg)
@@ -681,14 +701,15 @@ func (b *Builder) buildGroupBy(groupBy memo.RelExpr) (execPlan, error) {
 }
 
 // extractAggregateConstArgs returns the list of constant arguments associated with a given aggregate
-// expression.
+// expression. The first argument of an aggregate is never a constant argument.
 func (b *Builder) extractAggregateConstArgs(agg opt.ScalarExpr) tree.Datums {
-	switch agg.Op() {
-	case opt.StringAggOp:
-		return tree.Datums{memo.ExtractConstDatum(agg.Child(1))}
-	default:
-		return nil
+	var constArgs tree.Datums
+	for i, n := 1, agg.ChildCount(); i < n; i++ {
+		if child := agg.Child(i).(opt.ScalarExpr); memo.CanExtractConstDatum(child) {
+			constArgs = append(constArgs, memo.ExtractConstDatum(child))
+		}
 	}
+	return constArgs
 }
 
 func (b *Builder) buildDistinct(distinct *memo.DistinctOnExpr) (execPlan, error) {
@@ -1118,6 +1139,82 @@ func (b *Builder) buildProjectSet(projectSet *memo.ProjectSetExpr) (execPlan, er
 	return ep, nil
 }
 
+func (b *Builder) buildRecursiveCTE(rec *memo.RecursiveCTEExpr) (execPlan, error) {
+	initial, err := b.buildRelational(rec.Initial)
+	if err != nil {
+		return execPlan{}, err
+	}
+
+	// The output of the initial side becomes the working table (and the
+	// output) of the CTE, so its columns must be in the same order as OutCols.
+	md := b.mem.Metadata()
+	colNames := make([]string, len(rec.OutCols))
+	for i, col := range rec.OutCols {
+		colNames[i] = md.ColumnMeta(col).Alias
+	}
+	initial, err = b.ensureColumns(
+		initial, rec.InitialCols, colNames, rec.Initial.ProvidedPhysical().Ordering,
+	)
+	if err != nil {
+		return execPlan{}, err
+	}
+
+	// The recursive side is re-planned for every iteration, each time with a
+	// different working table.
+	fn := func(workTable exec.Node) (exec.Plan, error) {
+		innerBld := New(b.factory, b.mem, rec.Recursive, b.evalCtx)
+		innerBld.workTables = make(map[int]exec.Node, len(b.workTables)+1)
+		for id, n := range b.workTables {
+			innerBld.workTables[id] = n
+		}
+		innerBld.workTables[rec.ID] = workTable
+		// The subqueries of the recursive side are numbered after the
+		// subqueries of the enclosing plan, which are in effect while the
+		// iteration runs. The slice is capped so that appending copies it.
+		numOuter := len(b.subqueries)
+		innerBld.subqueries = b.subqueries[:numOuter:numOuter]
+
+		plan, err := innerBld.buildRelational(rec.Recursive)
+		if err != nil {
+			return nil, err
+		}
+		plan, err = innerBld.ensureColumns(
+			plan, rec.RecursiveCols, nil /* colNames */, rec.Recursive.ProvidedPhysical().Ordering,
+		)
+		if err != nil {
+			return nil, err
+		}
+		return innerBld.factory.ConstructPlan(plan.root, innerBld.subqueries[numOuter:])
+	}
+
+	node, err := b.factory.ConstructRecursiveCTE(initial.root, fn, rec.Name, rec.Deduplicate)
+	if err != nil {
+		return execPlan{}, err
+	}
+	ep := execPlan{root: node}
+	for i, col := range rec.OutCols {
+		ep.outputCols.Set(int(col), i)
+	}
+	return ep, nil
+}
+
+func (b *Builder) buildWorkTableScan(scan *memo.WorkTableScanExpr) (execPlan, error) {
+	ref, ok := b.workTables[scan.ID]
+	if !ok {
+		return execPlan{}, pgerror.NewAssertionErrorf(
+			"working table for recursive CTE %q not found", scan.Name)
+	}
+	node, err := b.factory.ConstructWorkTableScan(ref, scan.Name)
+	if err != nil {
+		return execPlan{}, err
+	}
+	ep := execPlan{root: node}
+	for i, col := range scan.Cols {
+		ep.outputCols.Set(int(col), i)
+	}
+	return ep, nil
+}
+
 func (b *Builder) buildInsert(ins *memo.InsertExpr) (execPlan, error) {
 	// Build the input query and ensure that the input columns that correspond to
 	// the table columns are projected.
@@ -55,6 +55,9 @@ type Factory interface {
 	//     the scan.
 	//   - If maxResults > 0, the scan is guaranteed to return at most maxResults
 	//     rows.
+	//   - If lockingStrength is not tree.ForNone, the scan locks the rows it
+	//     reads, and handles the rows locked by other transactions according to
+	//     lockingWaitPolicy.
 	ConstructScan(
 		table cat.Table,
 		index cat.Index,
@@ -64,6 +67,8 @@ type Factory interface {
 		reverse bool,
 		maxResults uint64,
 		reqOrdering OutputOrdering,
+		lockingStrength tree.LockingStrength,
+		lockingWaitPolicy tree.LockingWaitPolicy,
 	) (Node, error)
 
 	// ConstructVirtualScan returns a node that represents the scan of a virtual
@@ -221,6 +226,26 @@ type Factory interface {
 		n Node, exprs tree.TypedExprs, zipCols sqlbase.ResultColumns, numColsPerGen []int,
 	) (Node, error)
 
+	// ConstructRecursiveCTE returns a node that executes a recursive CTE:
+	//   - the initial plan is run first; its results are emitted and also
+	//     saved as the working table.
+	//   - so long as the working table is not empty:
+	//     - the RecursiveCTEIterationFn is used to create a plan for the
+	//       recursive side; a reference to the working table is passed to this
+	//       function, and the returned plan uses it in ConstructWorkTableScan.
+	//     - the plan is run; its results are emitted and also saved as the
+	//       working table for the next iteration.
+	// If deduplicate is set, rows that were already emitted are discarded
+	// (this corresponds to UNION, as opposed to UNION ALL).
+	ConstructRecursiveCTE(
+		initial Node, fn RecursiveCTEIterationFn, label string, deduplicate bool,
+	) (Node, error)
+
+	// ConstructWorkTableScan returns a node that scans the current working
+	// table of a recursive CTE. The ref must be the node that was passed to the
+	// RecursiveCTEIterationFn.
+	ConstructWorkTableScan(ref Node, label string) (Node, error)
+
 	// RenameColumns modifies the column names of a node.
 	RenameColumns(input Node, colNames []string) (Node, error)
 
@@ -358,6 +383,14 @@ const (
 	SubqueryAllRows
 )
 
+// RecursiveCTEIterationFn creates a plan for an iteration of the recursive
+// side of a recursive CTE (see ConstructRecursiveCTE). The workTable node can
+// be used with ConstructWorkTableScan to read the rows produced by the
+// previous iteration. The subqueries of the returned plan are run before each
+// iteration, after the subqueries of the enclosing plan (whose numbering they
+// continue).
+type RecursiveCTEIterationFn func(workTable Node) (Plan, error)
+
 // ColumnOrdinal is the 0-based ordinal index of a column produced by a Node.
 type ColumnOrdinal int32
 
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
@@ -28,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 )
 
 // Convenience aliases to avoid the constraint prefix everywhere.
@@ -107,7 +109,22 @@ func (c *indexConstraintCtx) makeNotNullSpan(offset int, out *constraint.Constra
 func (c *indexConstraintCtx) makeStringPrefixSpan(
 	offset int, prefix string, out *constraint.Constraint,
 ) {
-	startKey, startBoundary := constraint.MakeKey(tree.NewDString(prefix)), includeBoundary
+	c.makePrefixSpan(offset, prefix, func(s string) tree.Datum { return tree.NewDString(s) }, out)
+}
+
+// makeLexemePrefixSpan returns a span that constrains an inverted index over
+// a tsvector column to the lexemes having the given prefix.
+func (c *indexConstraintCtx) makeLexemePrefixSpan(prefix string, out *constraint.Constraint) {
+	c.makePrefixSpan(0 /* offset */, prefix, lexemeDatum, out)
+}
+
+// makePrefixSpan returns a span that constrains column <offset> to the values
+// whose string representation has the given prefix, using makeDatum to build
+// a value from its string representation.
+func (c *indexConstraintCtx) makePrefixSpan(
+	offset int, prefix string, makeDatum func(string) tree.Datum, out *constraint.Constraint,
+) {
+	startKey, startBoundary := constraint.MakeKey(makeDatum(prefix)), includeBoundary
 	endKey, endBoundary := emptyKey, includeBoundary
 
 	i := len(prefix) - 1
@@ -123,8 +140,7 @@ func (c *indexConstraintCtx) makeStringPrefixSpan(
 		//   ABC\xff\xff -> ABD
 		endVal := []byte(prefix[:i+1])
 		endVal[i]++
-		endDatum := tree.NewDString(string(endVal))
-		endKey = constraint.MakeKey(endDatum)
+		endKey = constraint.MakeKey(makeDatum(string(endVal)))
 		endBoundary = excludeBoundary
 	}
 	c.singleSpan(
@@ -783,6 +799,10 @@ func (c *indexConstraintCtx) makeInvertedIndexSpansForExpr(
 			return false, append(constraints, out)
 		}
 
+		if arr, ok := rightDatum.(*tree.DArray); ok {
+			return c.makeArrayContainsSpans(arr, constraints, allPaths)
+		}
+
 		rd := rightDatum.(*tree.DJSON).JSON
 
 		switch rd.Type() {
@@ -853,6 +873,57 @@ func (c *indexConstraintCtx) makeInvertedIndexSpansForExpr(
 			return true, append(constraints, out)
 		}
 
+	case opt.OverlapsOp:
+		lhs, rhs := nd.Child(0), nd.Child(1)
+
+		if !c.isIndexColumn(lhs, 0 /* index */) || !opt.IsConstValueOp(rhs) {
+			c.unconstrained(0 /* offset */, out)
+			return false, append(constraints, out)
+		}
+
+		arr, ok := memo.ExtractConstDatum(rhs).(*tree.DArray)
+		if !ok {
+			// The right side is NULL.
+			c.contradiction(0 /* offset */, out)
+			return false, append(constraints, out)
+		}
+
+		// The rows that overlap the array are those having at least one of its
+		// elements, so the spans of all the elements are unioned. An array
+		// without non-NULL elements doesn't overlap anything.
+		elems := distinctArrayElements(c.evalCtx, arr)
+		if len(elems) == 0 {
+			c.contradiction(0 /* offset */, out)
+			return false, append(constraints, out)
+		}
+		for i := range elems {
+			if i == 0 {
+				c.eqSpan(0 /* offset */, elems[i], out)
+				continue
+			}
+			var other constraint.Constraint
+			c.eqSpan(0 /* offset */, elems[i], &other)
+			out.UnionWith(c.evalCtx, &other)
+		}
+		return true, append(constraints, out)
+
+	case opt.TSMatchesOp:
+		lhs, rhs := nd.Child(0), nd.Child(1)
+
+		if !c.isIndexColumn(lhs, 0 /* index */) || !opt.IsConstValueOp(rhs) {
+			c.unconstrained(0 /* offset */, out)
+			return false, append(constraints, out)
+		}
+
+		q, ok := memo.ExtractConstDatum(rhs).(*tree.DTSQuery)
+		if !ok {
+			// The right side is NULL.
+			c.contradiction(0 /* offset */, out)
+			return false, append(constraints, out)
+		}
+		tight := c.makeTSQuerySpans(q.Root, out)
+		return tight, append(constraints, out)
+
 	case opt.AndOp, opt.FiltersOp:
 		for i, n := 0, nd.ChildCount(); i < n; i++ {
 			tight, constraints = c.makeInvertedIndexSpansForExpr(
@@ -894,6 +965,114 @@ func (c *indexConstraintCtx) makeInvertedIndexSpansForExpr(
 	return false, constraints
 }
 
+// makeArrayContainsSpans generates the constraints on an inverted index over
+// an array column implied by a containment of the given array. Every distinct
+// element of the array generates its own constraint, all of which have to be
+// satisfied. If allPaths is false, only the first constraint is generated.
+func (c *indexConstraintCtx) makeArrayContainsSpans(
+	arr *tree.DArray, constraints []*constraint.Constraint, allPaths bool,
+) (bool, []*constraint.Constraint) {
+	out := &constraint.Constraint{}
+	for _, elem := range arr.Array {
+		if elem == tree.DNull {
+			// NULL elements are never contained in an array.
+			c.contradiction(0 /* offset */, out)
+			return false, append(constraints, out)
+		}
+	}
+
+	elems := distinctArrayElements(c.evalCtx, arr)
+	if len(elems) == 0 {
+		// Every array contains the empty array.
+		c.unconstrained(0 /* offset */, out)
+		return false, append(constraints, out)
+	}
+	for i := range elems {
+		c.eqSpan(0 /* offset */, elems[i], out)
+		constraints = append(constraints, out)
+		if !allPaths {
+			break
+		}
+		out = &constraint.Constraint{}
+	}
+	// The spans are tight if there is only one element.
+	return len(elems) == 1, constraints
+}
+
+// makeTSQuerySpans generates the spans of an inverted index over a tsvector
+// column that contain the rows which can match the given tsquery node, and
+// returns whether the spans are tight.
+func (c *indexConstraintCtx) makeTSQuerySpans(n *tsearch.Node, out *constraint.Constraint) bool {
+	if n == nil {
+		// An empty query matches nothing.
+		c.contradiction(0 /* offset */, out)
+		return false
+	}
+	switch n.Op {
+	case tsearch.OpOperand:
+		if n.Prefix {
+			c.makeLexemePrefixSpan(n.Word, out)
+		} else {
+			c.eqSpan(0 /* offset */, lexemeDatum(n.Word), out)
+		}
+		// An operand restricted to some weights doesn't match all the rows
+		// having its lexeme.
+		return n.Weights == 0
+
+	case tsearch.OpOr:
+		tight := c.makeTSQuerySpans(n.Left, out)
+		var other constraint.Constraint
+		if !c.makeTSQuerySpans(n.Right, &other) {
+			tight = false
+		}
+		out.UnionWith(c.evalCtx, &other)
+		return tight
+
+	case tsearch.OpAnd:
+		// The rows matching both operands are among the rows matching either
+		// one of them. The left operand is used unless it is unconstrained.
+		c.makeTSQuerySpans(n.Left, out)
+		if out.IsUnconstrained() {
+			c.makeTSQuerySpans(n.Right, out)
+		}
+		return false
+	}
+
+	// A negation matches the rows that don't have the negated lexemes, which
+	// are not found in the index.
+	c.unconstrained(0 /* offset */, out)
+	return false
+}
+
+// lexemeDatum returns the single-lexeme tsvector under which the rows having
+// the given lexeme are found in an inverted index.
+func lexemeDatum(word string) tree.Datum {
+	return tree.NewDTSVector(tsearch.TSVector{{Word: word}})
+}
+
+// distinctArrayElements returns a single-element array for each distinct
+// non-NULL element of the given array, in sorted order. These are the values
+// of the inverted index column that an element can be found under.
+func distinctArrayElements(evalCtx *tree.EvalContext, arr *tree.DArray) []tree.Datum {
+	elems := make(tree.Datums, 0, len(arr.Array))
+	for _, elem := range arr.Array {
+		if elem != tree.DNull {
+			elems = append(elems, elem)
+		}
+	}
+	sort.Slice(elems, func(i, j int) bool {
+		return elems[i].Compare(evalCtx, elems[j]) < 0
+	})
+	res := make([]tree.Datum, 0, len(elems))
+	for i := range elems {
+		if i > 0 && elems[i].Compare(evalCtx, elems[i-1]) == 0 {
+			continue
+		}
+		res = append(res, &tree.DArray{ParamTyp: arr.ParamTyp, Array: tree.Datums{elems[i]}})
+	}
+	return res
+}
+
 // getMaxSimplifyPrefix finds the longest prefix (maxSimplifyPrefix) such that
 // every span has the same first maxSimplifyPrefix values for the start and end
 // key. For example, for:
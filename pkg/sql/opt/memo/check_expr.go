@@ -202,8 +202,11 @@ func (m *Memo) checkExpr(e opt.Expr) {
 			}
 		}
 
-		if e.Op() == opt.StringAggOp && !CanExtractConstDatum(e.Child(1)) {
-			panic(fmt.Sprintf("second argument to StringAggOp must always be constant, but got %s", e.Child(1).Op()))
+		switch e.Op() {
+		case opt.StringAggOp, opt.PercentileDiscOp, opt.PercentileContOp:
+			if !CanExtractConstDatum(e.Child(1)) {
+				panic(fmt.Sprintf("second argument to %s must always be constant, but got %s", e.Op(), e.Child(1).Op()))
+			}
 		}
 
 		if opt.IsJoinOp(e) {
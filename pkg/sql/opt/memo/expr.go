@@ -18,6 +18,7 @@ import (
 	"fmt"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/constraint"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props/physical"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -277,6 +278,56 @@ func (sf *ScanFlags) Empty() bool {
 	return !sf.NoIndexJoin && !sf.ForceIndex
 }
 
+// ScanLocking specifies the row-level locking of a scan, as requested by a
+// locking clause like FOR UPDATE. A locking scan always reads the primary
+// index of its table.
+type ScanLocking struct {
+	Strength   tree.LockingStrength
+	WaitPolicy tree.LockingWaitPolicy
+}
+
+// IsLocking returns true if the scan locks the rows it reads.
+func (sl ScanLocking) IsLocking() bool {
+	return sl.Strength != tree.ForNone
+}
+
+// MayReturnDuplicates returns true if the scan can return the same table row
+// more than once. See InvertedIndexMayReturnDuplicates.
+func (s *ScanPrivate) MayReturnDuplicates(md *opt.Metadata) bool {
+	if s.Constraint == nil {
+		return false
+	}
+	index := md.Table(s.Table).Index(s.Index)
+	if !index.IsInverted() {
+		return false
+	}
+	return InvertedIndexMayReturnDuplicates(index.Column(0).Column.DatumType(), s.Constraint)
+}
+
+// InvertedIndexMayReturnDuplicates returns true if a scan over an inverted
+// index on a column of the given type, constrained by c, can return the same
+// table row more than once. A row is found once for each of its array
+// elements or tsvector lexemes that fall in the spans, so this is the case of
+// array columns constrained to several spans, and of tsvector columns
+// constrained to anything but a single lexeme.
+func InvertedIndexMayReturnDuplicates(colType types.T, c *constraint.Constraint) bool {
+	if _, isArray := colType.(types.TArray); isArray {
+		return c.Spans.Count() > 1
+	}
+	if colType == types.TSVector {
+		if c.Spans.Count() != 1 {
+			return true
+		}
+		sp := c.Spans.Get(0)
+		start, end := sp.StartKey(), sp.EndKey()
+		return start.Length() != 1 || end.Length() != 1 ||
+			sp.StartBoundary() == constraint.ExcludeBoundary ||
+			sp.EndBoundary() == constraint.ExcludeBoundary ||
+			start.Value(0).Compare(nil /* ctx */, end.Value(0)) != 0
+	}
+	return false
+}
+
 // MapToInputID maps from the ID of a target table column to the ID of the
 // corresponding input column that provides the value for it:
 //
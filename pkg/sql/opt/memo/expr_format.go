@@ -17,6 +17,7 @@ package memo
 import (
 	"bytes"
 	"fmt"
+	"strings"
 	"unicode"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
@@ -167,7 +168,8 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 		f.Buffer.WriteByte(')')
 
 	case *ScanExpr, *VirtualScanExpr, *IndexJoinExpr, *ShowTraceForSessionExpr,
-		*InsertExpr, *UpdateExpr, *UpsertExpr, *DeleteExpr:
+		*InsertExpr, *UpdateExpr, *UpsertExpr, *DeleteExpr, *RecursiveCTEExpr,
+		*WorkTableScanExpr:
 		fmt.Fprintf(f.Buffer, "%v", e.Op())
 		FormatPrivate(f, e.Private(), required)
 
@@ -202,6 +204,12 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 		*UnionAllExpr, *IntersectAllExpr, *ExceptAllExpr:
 		colList = e.Private().(*SetPrivate).OutCols
 
+	case *RecursiveCTEExpr:
+		colList = t.OutCols
+
+	case *WorkTableScanExpr:
+		colList = t.Cols
+
 	default:
 		// Fall back to writing output columns in column id order.
 		colList = opt.ColSetToList(e.Relational().OutputCols)
@@ -239,6 +247,10 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 		f.formatColList(e, tp, "left columns:", private.LeftCols)
 		f.formatColList(e, tp, "right columns:", private.RightCols)
 
+	case *RecursiveCTEExpr:
+		f.formatColList(e, tp, "initial columns:", t.InitialCols)
+		f.formatColList(e, tp, "recursive columns:", t.RecursiveCols)
+
 	case *ScanExpr:
 		if t.Constraint != nil {
 			tp.Childf("constraint: %s", t.Constraint)
@@ -262,6 +274,14 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 				tp.Childf("flags: force-index=%s%s", idx.Name(), dir)
 			}
 		}
+		if t.Locking.IsLocking() {
+			locking := strings.Replace(strings.ToLower(t.Locking.Strength.String()), " ", "-", -1)
+			if t.Locking.WaitPolicy != tree.LockWaitBlock {
+				waitPolicy := strings.Replace(strings.ToLower(t.Locking.WaitPolicy.String()), " ", "-", -1)
+				locking += "," + waitPolicy
+			}
+			tp.Childf("locking: %s", locking)
+		}
 
 	case *LookupJoinExpr:
 		idxCols := make(opt.ColList, len(t.KeyCols))
@@ -722,6 +742,15 @@ func FormatPrivate(f *ExprFmtCtx, private interface{}, physProps *physical.Requi
 	case *MutationPrivate:
 		fmt.Fprintf(f.Buffer, " %s", tableName(f, t.Table))
 
+	case *RecursiveCTEPrivate:
+		fmt.Fprintf(f.Buffer, " %s", t.Name)
+		if t.Deduplicate {
+			f.Buffer.WriteString(",dedup")
+		}
+
+	case *WorkTableScanPrivate:
+		fmt.Fprintf(f.Buffer, " %s", t.Name)
+
 	case *RowNumberPrivate:
 		if !t.Ordering.Any() {
 			fmt.Fprintf(f.Buffer, " ordering=%s", t.Ordering)
@@ -112,7 +112,8 @@ func ExtractAggSingleInputColumn(e opt.ScalarExpr) opt.ColumnID {
 }
 
 // ExtractAggInputColumns returns the input columns of an aggregate (which can
-// be empty).
+// be empty). The first argument of an aggregate is always an input column; the
+// following arguments are input columns unless they are constants.
 func ExtractAggInputColumns(e opt.ScalarExpr) opt.ColSet {
 	if !opt.IsAggregateOp(e) {
 		panic("not an Aggregate")
@@ -122,6 +123,11 @@ func ExtractAggInputColumns(e opt.ScalarExpr) opt.ColSet {
 	if e.ChildCount() > 0 {
 		res.Add(int(ExtractVarFromAggInput(e.Child(0).(opt.ScalarExpr)).Col))
 	}
+	for i, n := 1, e.ChildCount(); i < n; i++ {
+		if variable, ok := e.Child(i).(*VariableExpr); ok {
+			res.Add(int(variable.Col))
+		}
+	}
 	return res
 }
 
@@ -371,6 +371,9 @@ func (h *hasher) HashDatum(val tree.Datum) {
 		for _, d := range t.Array {
 			h.HashDatum(d)
 		}
+	case *tree.DEnum:
+		h.HashBytes(t.PhysicalRep)
+		h.HashUint64(uint64(t.Typ.ID))
 	default:
 		h.HashBytes(encodeDatum(h.bytes[:0], val))
 	}
@@ -455,6 +458,13 @@ func (h *hasher) HashScanFlags(val ScanFlags) {
 	h.hash *= prime64
 }
 
+func (h *hasher) HashScanLocking(val ScanLocking) {
+	h.hash ^= internHash(val.Strength)
+	h.hash *= prime64
+	h.hash ^= internHash(val.WaitPolicy)
+	h.hash *= prime64
+}
+
 func (h *hasher) HashExplainOptions(val tree.ExplainOptions) {
 	h.HashColSet(val.Flags)
 	h.hash ^= internHash(val.Mode)
@@ -485,7 +495,11 @@ func (h *hasher) HashRelExpr(val RelExpr) {
 }
 
 func (h *hasher) HashScalarExpr(val opt.ScalarExpr) {
-	h.hash ^= internHash(uint64(reflect.ValueOf(val).Pointer()))
+	// Optional scalar fields of privates (like FunctionPrivate.Body) can be
+	// nil.
+	if val != nil {
+		h.hash ^= internHash(uint64(reflect.ValueOf(val).Pointer()))
+	}
 	h.hash *= prime64
 }
 
@@ -639,6 +653,12 @@ func (h *hasher) IsDatumEqual(l, r tree.Datum) bool {
 			}
 			return true
 		}
+	case *tree.DEnum:
+		if rt, ok := r.(*tree.DEnum); ok {
+			// Values of different ENUM types can have the same physical
+			// representation.
+			return lt.Typ.ID == rt.Typ.ID && bytes.Equal(lt.PhysicalRep, rt.PhysicalRep)
+		}
 	default:
 		lb := encodeDatum(h.bytes[:0], l)
 		rb := encodeDatum(h.bytes2[:0], r)
@@ -688,6 +708,10 @@ func (h *hasher) IsScanFlagsEqual(l, r ScanFlags) bool {
 	return l == r
 }
 
+func (h *hasher) IsScanLockingEqual(l, r ScanLocking) bool {
+	return l == r
+}
+
 func (h *hasher) IsExplainOptionsEqual(l, r tree.ExplainOptions) bool {
 	return l.Mode == r.Mode && l.Flags.Equals(r.Flags)
 }
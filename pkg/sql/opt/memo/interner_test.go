@@ -292,6 +292,13 @@ func TestInterner(t *testing.T) {
 			{val1: ScanFlags{NoIndexJoin: true, Index: 1}, val2: ScanFlags{NoIndexJoin: false, Index: 1}, equal: false},
 		}},
 
+		{hashFn: in.hasher.HashScanLocking, eqFn: in.hasher.IsScanLockingEqual, variations: []testVariation{
+			{val1: ScanLocking{}, val2: ScanLocking{}, equal: true},
+			{val1: ScanLocking{Strength: tree.ForUpdate}, val2: ScanLocking{Strength: tree.ForUpdate}, equal: true},
+			{val1: ScanLocking{Strength: tree.ForUpdate}, val2: ScanLocking{Strength: tree.ForShare}, equal: false},
+			{val1: ScanLocking{Strength: tree.ForUpdate}, val2: ScanLocking{Strength: tree.ForUpdate, WaitPolicy: tree.LockWaitError}, equal: false},
+		}},
+
 		{hashFn: in.hasher.HashPointer, eqFn: in.hasher.IsPointerEqual, variations: []testVariation{
 			{val1: unsafe.Pointer((*tree.Subquery)(nil)), val2: unsafe.Pointer((*tree.Subquery)(nil)), equal: true},
 			{val1: unsafe.Pointer(&tree.Subquery{}), val2: unsafe.Pointer(&tree.Subquery{}), equal: false},
@@ -92,10 +92,11 @@ func (b *logicalPropsBuilder) buildScanProps(scan *ScanExpr, rel *props.Relation
 	// that def.HardLimit = 0 indicates there is no known limit.
 	if hardLimit == 1 {
 		rel.FuncDeps.MakeMax1Row(rel.OutputCols)
-	} else {
+	} else if !scan.MayReturnDuplicates(md) {
 		// Initialize key FD's from the table schema, including constant columns from
 		// the constraint, minus any columns that are not projected by the Scan
-		// operator.
+		// operator. The keys of the table are not keys of a scan that may return
+		// the same row more than once.
 		rel.FuncDeps.CopyFrom(makeTableFuncDep(md, scan.Table))
 		if scan.Constraint != nil {
 			rel.FuncDeps.AddConstants(scan.Constraint.ExtractConstCols(b.evalCtx))
@@ -708,6 +709,77 @@ func (b *logicalPropsBuilder) buildShowTraceForSessionProps(
 	// Zero value for Stats is ok for ShowTrace.
 }
 
+func (b *logicalPropsBuilder) buildRecursiveCTEProps(
+	recursiveCTE *RecursiveCTEExpr, rel *props.Relational,
+) {
+	BuildSharedProps(b.mem, recursiveCTE, &rel.Shared)
+
+	// Output Columns
+	// --------------
+	// Output columns are stored in the definition.
+	rel.OutputCols = recursiveCTE.OutCols.ToSet()
+
+	// Not Null Columns
+	// ----------------
+	// All columns are assumed to be nullable.
+
+	// Outer Columns
+	// -------------
+	// Outer columns were already derived by buildSharedProps.
+
+	// Functional Dependencies
+	// -----------------------
+	// Rows produced by the recursive term are not known in advance, so no
+	// dependencies can be derived.
+
+	// Cardinality
+	// -----------
+	// At least as many rows as the initial term are returned.
+	rel.Cardinality = props.AnyCardinality.AtLeast(
+		recursiveCTE.Initial.Relational().Cardinality,
+	)
+
+	// Statistics
+	// ----------
+	if !b.disableStats {
+		b.sb.buildRecursiveCTE(recursiveCTE, rel)
+	}
+}
+
+func (b *logicalPropsBuilder) buildWorkTableScanProps(
+	scan *WorkTableScanExpr, rel *props.Relational,
+) {
+	BuildSharedProps(b.mem, scan, &rel.Shared)
+
+	// Output Columns
+	// --------------
+	// Output columns are stored in the definition.
+	rel.OutputCols = scan.Cols.ToSet()
+
+	// Not Null Columns
+	// ----------------
+	// All columns are assumed to be nullable.
+
+	// Outer Columns
+	// -------------
+	// The working table doesn't have outer columns.
+
+	// Functional Dependencies
+	// -----------------------
+	// WorkTableScan operator has an empty FD set.
+
+	// Cardinality
+	// -----------
+	// The recursive term is only executed when the working table is not empty.
+	rel.Cardinality = props.AnyCardinality.AtLeast(props.OneCardinality)
+
+	// Statistics
+	// ----------
+	if !b.disableStats {
+		b.sb.buildWorkTableScan(scan, rel)
+	}
+}
+
 func (b *logicalPropsBuilder) buildLimitProps(limit *LimitExpr, rel *props.Relational) {
 	BuildSharedProps(b.mem, limit, &rel.Shared)
 
@@ -1195,6 +1267,11 @@ func makeTableFuncDep(md *opt.Metadata, tabID opt.TableID) *props.FuncDepSet {
 			// Skip inverted indexes for now.
 			continue
 		}
+		if _, isPartial := index.Predicate(); isPartial {
+			// A partial index only contains some of the rows of the table, so
+			// its key is not a key of the table.
+			continue
+		}
 
 		// If index has a separate lax key, add a lax key FD. Otherwise, add a
 		// strict key. See the comment for cat.Index.LaxKeyColumnCount.
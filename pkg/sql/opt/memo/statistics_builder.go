@@ -335,7 +335,7 @@ func (sb *statisticsBuilder) colStat(colSet opt.ColSet, e RelExpr) *props.Column
 	case opt.InsertOp, opt.UpdateOp, opt.UpsertOp, opt.DeleteOp:
 		return sb.colStatMutation(colSet, e)
 
-	case opt.ExplainOp, opt.ShowTraceForSessionOp:
+	case opt.ExplainOp, opt.ShowTraceForSessionOp, opt.RecursiveCTEOp, opt.WorkTableScanOp:
 		relProps := e.Relational()
 		return sb.colStatLeaf(colSet, &relProps.Stats, &relProps.FuncDeps, relProps.NotNullCols)
 	}
@@ -510,6 +510,13 @@ func (sb *statisticsBuilder) buildScan(scan *ScanExpr, relProps *props.Relationa
 		s.ApplySelectivity(sb.selectivityFromNullCounts(cols, scan, s, inputRowCount))
 	}
 
+	// A partial index only contains the rows that satisfy its predicate. Treat
+	// each conjunct of the predicate as a filter of unknown selectivity.
+	if pred, ok := sb.md.TableMeta(scan.Table).PartialIndexPredicate(scan.Index); ok {
+		numConjuncts := float64(len(*pred.(*FiltersExpr)))
+		s.ApplySelectivity(sb.selectivityFromUnappliedConjuncts(numConjuncts))
+	}
+
 	sb.finalizeFromCardinality(relProps)
 }
 
@@ -1877,6 +1884,41 @@ func (sb *statisticsBuilder) colStatProjectSet(
 	return colStat
 }
 
+// +--------------------------------+
+// | Recursive CTE, Work Table Scan |
+// +--------------------------------+
+
+func (sb *statisticsBuilder) buildRecursiveCTE(
+	recursiveCTE *RecursiveCTEExpr, relProps *props.Relational,
+) {
+	s := &relProps.Stats
+	if zeroCardinality := s.Init(relProps); zeroCardinality {
+		// Short cut if cardinality is 0.
+		return
+	}
+
+	// The number of iterations is not known until execution time, so assume
+	// that the recursion produces a fixed multiple of the initial rows.
+	initialStats := &recursiveCTE.Initial.Relational().Stats
+	s.RowCount = initialStats.RowCount * unknownRecursiveCTEIterations
+	sb.finalizeFromCardinality(relProps)
+}
+
+func (sb *statisticsBuilder) buildWorkTableScan(
+	scan *WorkTableScanExpr, relProps *props.Relational,
+) {
+	s := &relProps.Stats
+	if zeroCardinality := s.Init(relProps); zeroCardinality {
+		// Short cut if cardinality is 0.
+		return
+	}
+
+	// The working table changes on every iteration, so there is no way to know
+	// how many rows it contains.
+	s.RowCount = unknownWorkTableRowCount
+	sb.finalizeFromCardinality(relProps)
+}
+
 // +--------------------------------+
 // | Insert, Update, Upsert, Delete |
 // +--------------------------------+
@@ -2060,6 +2102,13 @@ const (
 	// Since the generator row count is so small, we need a larger distinct count
 	// ratio for generator functions.
 	unknownGeneratorDistinctCountRatio = 0.7
+
+	// This is the number of times the recursive term of a recursive CTE is
+	// assumed to be executed, used to estimate the row count of the CTE.
+	unknownRecursiveCTEIterations = 10
+
+	// This is the row count used for the working table of a recursive CTE.
+	unknownWorkTableRowCount = 10
 )
 
 // countJSONPaths returns the number of JSON paths in the specified
@@ -109,6 +109,12 @@ func (md *Metadata) AddMetadata(from *Metadata) {
 	md.schemas = append(md.schemas, from.schemas...)
 	md.cols = append(md.cols, from.cols...)
 	md.tables = append(md.tables, from.tables...)
+	for i := range md.tables {
+		// Partial index predicates are expressions in the memo that owns the
+		// source metadata, so they are not shared. The caller is responsible
+		// for copying them into its own memo.
+		md.tables[i].partialIndexPredicates = nil
+	}
 	md.deps = make(map[cat.Object]privilegeBitmap, len(from.deps))
 	for ds, privs := range from.deps {
 		md.deps[ds] = privs
@@ -234,6 +240,12 @@ func (md *Metadata) TableMeta(tabID TableID) *TableMeta {
 	return &md.tables[tabID.index()]
 }
 
+// AllTables returns the metadata for all tables, in the order in which they
+// were added. The caller must not modify the returned slice.
+func (md *Metadata) AllTables() []TableMeta {
+	return md.tables
+}
+
 // Table looks up the catalog table associated with the given metadata id. The
 // same table can be associated with multiple metadata ids.
 func (md *Metadata) Table(tabID TableID) cat.Table {
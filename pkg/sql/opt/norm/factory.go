@@ -186,6 +186,7 @@ func (f *Factory) AssignPlaceholders(from *memo.Memo) (err error) {
 	// Copy all metadata so that referenced tables and columns can keep the same
 	// ids they had in the "from" memo.
 	f.mem.Metadata().AddMetadata(from.Metadata())
+	f.copyPartialIndexPredicates(from.Metadata())
 
 	// Replace all placeholders with their assigned values.
 	dst := f.assignPlaceholders(src)
@@ -194,6 +195,27 @@ func (f *Factory) AssignPlaceholders(from *memo.Memo) (err error) {
 	return nil
 }
 
+// copyPartialIndexPredicates copies the partial index predicates of the tables
+// in the given metadata into the memo, in the tables with the same ids.
+func (f *Factory) copyPartialIndexPredicates(from *opt.Metadata) {
+	tables := from.AllTables()
+	for i := range tables {
+		src := &tables[i]
+		dst := f.mem.Metadata().TableMeta(src.MetaID)
+		for ord, n := 0, src.Table.IndexCount(); ord < n; ord++ {
+			pred, ok := src.PartialIndexPredicate(ord)
+			if !ok {
+				continue
+			}
+			filters := f.assignFiltersExprPlaceholders(*pred.(*memo.FiltersExpr))
+			for j := range filters {
+				filters[j].ScalarProps(f.mem)
+			}
+			dst.AddPartialIndexPredicate(ord, &filters)
+		}
+	}
+}
+
 // onConstructRelational is called as a final step by each factory method that
 // constructs a relational expression, so that any custom manual pattern
 // matching/replacement code can be run.
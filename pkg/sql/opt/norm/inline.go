@@ -289,3 +289,60 @@ func (c *CustomFuncs) inlineProjections(e opt.Expr, projections memo.Projections
 
 	return replace(e)
 }
+
+// CanInlineFunction returns true if the call to a user-defined function can be
+// replaced by the body of the function. This is the case if the optbuilder
+// built the body, and if each argument that is not referenced exactly once by
+// the body is simple enough to be evaluated any number of times (see
+// CanInline).
+func (c *CustomFuncs) CanInlineFunction(
+	args memo.ScalarListExpr, private *memo.FunctionPrivate,
+) bool {
+	if private.Body == nil {
+		return false
+	}
+	refs := make([]int, len(private.Params))
+	var countRefs func(e opt.Expr)
+	countRefs = func(e opt.Expr) {
+		if v, ok := e.(*memo.VariableExpr); ok {
+			for i, col := range private.Params {
+				if v.Col == col {
+					refs[i]++
+				}
+			}
+			return
+		}
+		for i, n := 0, e.ChildCount(); i < n; i++ {
+			countRefs(e.Child(i))
+		}
+	}
+	countRefs(private.Body)
+
+	for i := range args {
+		if refs[i] != 1 && !c.CanInline(args[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// InlineFunction returns the body of a user-defined function, in which the
+// references to the parameters of the function are replaced by the given
+// arguments.
+func (c *CustomFuncs) InlineFunction(
+	args memo.ScalarListExpr, private *memo.FunctionPrivate,
+) opt.ScalarExpr {
+	var replace ReconstructFunc
+	replace = func(e opt.Expr) opt.Expr {
+		if v, ok := e.(*memo.VariableExpr); ok {
+			for i, col := range private.Params {
+				if v.Col == col {
+					return args[i]
+				}
+			}
+			return v
+		}
+		return c.f.Reconstruct(e, replace)
+	}
+	return replace(private.Body).(opt.ScalarExpr)
+}
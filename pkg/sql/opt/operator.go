@@ -132,6 +132,8 @@ var ComparisonOpReverseMap = map[Operator]tree.ComparisonOperator{
 	JsonExistsOp:     tree.JSONExists,
 	JsonSomeExistsOp: tree.JSONSomeExists,
 	JsonAllExistsOp:  tree.JSONAllExists,
+	OverlapsOp:       tree.Overlaps,
+	TSMatchesOp:      tree.TSMatches,
 }
 
 // BinaryOpReverseMap maps from an optimizer operator type to a semantic tree
@@ -170,6 +172,9 @@ var AggregateOpReverseMap = map[Operator]string{
 	AvgOp:             "avg",
 	BoolAndOp:         "bool_and",
 	BoolOrOp:          "bool_or",
+	BitAndOp:          "bit_and",
+	BitOrOp:           "bit_or",
+	EveryOp:           "every",
 	ConcatAggOp:       "concat_agg",
 	CountOp:           "count",
 	CountRowsOp:       "count_rows",
@@ -180,10 +185,29 @@ var AggregateOpReverseMap = map[Operator]string{
 	SqrDiffOp:         "sqrdiff",
 	VarianceOp:        "variance",
 	StdDevOp:          "stddev",
+	VarPopOp:          "var_pop",
+	StdDevPopOp:       "stddev_pop",
+	VarSampOp:         "var_samp",
+	StdDevSampOp:      "stddev_samp",
+	CovarPopOp:        "covar_pop",
+	CovarSampOp:       "covar_samp",
+	CorrOp:            "corr",
+	RegrAvgXOp:        "regr_avgx",
+	RegrAvgYOp:        "regr_avgy",
+	RegrCountOp:       "regr_count",
+	RegrInterceptOp:   "regr_intercept",
+	RegrR2Op:          "regr_r2",
+	RegrSlopeOp:       "regr_slope",
+	RegrSxxOp:         "regr_sxx",
+	RegrSxyOp:         "regr_sxy",
+	RegrSyyOp:         "regr_syy",
 	XorAggOp:          "xor_agg",
 	JsonAggOp:         "json_agg",
 	JsonbAggOp:        "jsonb_agg",
 	StringAggOp:       "string_agg",
+	PercentileDiscOp:  "percentile_disc",
+	PercentileContOp:  "percentile_cont",
+	ModeOp:            "mode",
 	ConstAggOp:        "any_not_null",
 	ConstNotNullAggOp: "any_not_null",
 	AnyNotNullAggOp:   "any_not_null",
@@ -241,12 +265,17 @@ func AggregateIsOrderingSensitive(op Operator) bool {
 
 // AggregateIgnoresNulls returns true if the given aggregate operator has a
 // single input, and if it always evaluates to the same result regardless of
-// how many NULL values are included in that input, in any order.
+// how many NULL values are included in that input, in any order. Aggregates
+// with several inputs, like CovarPop, qualify if rows in which any input is
+// NULL are ignored.
 func AggregateIgnoresNulls(op Operator) bool {
 	switch op {
-	case AvgOp, BoolAndOp, BoolOrOp, CountOp, MaxOp, MinOp, SumIntOp, SumOp,
-		SqrDiffOp, VarianceOp, StdDevOp, XorAggOp, ConstNotNullAggOp,
-		AnyNotNullAggOp, StringAggOp:
+	case AvgOp, BoolAndOp, BoolOrOp, BitAndOp, BitOrOp, EveryOp, CountOp, MaxOp,
+		MinOp, SumIntOp, SumOp, SqrDiffOp, VarianceOp, StdDevOp, VarPopOp,
+		StdDevPopOp, VarSampOp, StdDevSampOp, CovarPopOp, CovarSampOp, CorrOp,
+		RegrAvgXOp, RegrAvgYOp, RegrCountOp, RegrInterceptOp, RegrR2Op, RegrSlopeOp,
+		RegrSxxOp, RegrSxyOp, RegrSyyOp, XorAggOp, ConstNotNullAggOp,
+		AnyNotNullAggOp, StringAggOp, PercentileDiscOp, PercentileContOp, ModeOp:
 		return true
 	}
 	return false
@@ -259,9 +288,13 @@ func AggregateIgnoresNulls(op Operator) bool {
 // NULL when its input is empty.
 func AggregateIsNullOnEmpty(op Operator) bool {
 	switch op {
-	case AvgOp, BoolAndOp, BoolOrOp, MaxOp, MinOp, SumIntOp, SumOp, SqrDiffOp,
-		VarianceOp, StdDevOp, XorAggOp, ConstAggOp, ConstNotNullAggOp, ArrayAggOp,
-		ConcatAggOp, JsonAggOp, JsonbAggOp, AnyNotNullAggOp, StringAggOp:
+	case AvgOp, BoolAndOp, BoolOrOp, BitAndOp, BitOrOp, EveryOp, MaxOp, MinOp,
+		SumIntOp, SumOp, SqrDiffOp, VarianceOp, StdDevOp, VarPopOp, StdDevPopOp,
+		VarSampOp, StdDevSampOp, CovarPopOp, CovarSampOp, CorrOp, RegrAvgXOp,
+		RegrAvgYOp, RegrInterceptOp, RegrR2Op, RegrSlopeOp, RegrSxxOp, RegrSxyOp,
+		RegrSyyOp, XorAggOp, ConstAggOp, ConstNotNullAggOp, ArrayAggOp,
+		ConcatAggOp, JsonAggOp, JsonbAggOp, AnyNotNullAggOp, StringAggOp,
+		PercentileDiscOp, PercentileContOp, ModeOp:
 		return true
 	}
 	return false
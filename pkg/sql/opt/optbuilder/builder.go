@@ -77,6 +77,11 @@ type Builder struct {
 	// values.
 	HadPlaceholders bool
 
+	// HadUserDefinedFunctions is set to true if the statement calls any
+	// user-defined function. The memo of such a statement cannot be reused,
+	// since the definitions of the functions are not tracked by the catalog.
+	HadUserDefinedFunctions bool
+
 	factory *norm.Factory
 	stmt    tree.Statement
 
@@ -97,9 +102,22 @@ type Builder struct {
 	// are referenced multiple times in the same query.
 	views map[cat.View]*tree.Select
 
+	// inliningFunction is set while the body of a user-defined function is
+	// built, in order to prevent the inlining of nested calls.
+	inliningFunction bool
+
 	// subquery contains a pointer to the subquery which is currently being built
 	// (if any).
 	subquery *subquery
+
+	// numRecursiveCTEs is the number of recursive CTEs built so far; it is used
+	// to assign a unique ID to each of them.
+	numRecursiveCTEs int
+
+	// lockingClause is the locking clause (e.g. FOR UPDATE) of the SELECT
+	// statement being built. It applies to the tables of the FROM clause of
+	// that statement, but not to its subqueries.
+	lockingClause tree.LockingClause
 }
 
 // New creates a new Builder structure initialized with the given
@@ -29,6 +29,11 @@ import (
 // buildCreateTable constructs a CreateTable operator based on the CREATE TABLE
 // statement.
 func (b *Builder) buildCreateTable(ct *tree.CreateTable, inScope *scope) (outScope *scope) {
+	var err error
+	ct.Temporary, err = sqlbase.QualifyTemporaryTableName(&ct.Table, ct.Temporary)
+	if err != nil {
+		panic(builderError{err})
+	}
 	sch := b.resolveSchemaForCreate(&ct.Table)
 	schID := b.factory.Metadata().AddSchema(sch)
 
@@ -48,7 +48,7 @@ func (b *Builder) buildDelete(del *tree.Delete, inScope *scope) (outScope *scope
 	}
 
 	if del.With != nil {
-		inScope = b.buildCTE(del.With.CTEList, inScope)
+		inScope = b.buildCTE(del.With, inScope)
 		defer b.checkCTEUsage(inScope)
 	}
 
@@ -73,7 +73,7 @@ func (b *Builder) buildDelete(del *tree.Delete, inScope *scope) (outScope *scope
 	//   ORDER BY <order-by> LIMIT <limit>
 	//
 	// All columns from the delete table will be projected.
-	mb.buildInputForUpdateOrDelete(inScope, del.Where, del.Limit, del.OrderBy)
+	mb.buildInputForUpdateOrDelete(inScope, nil /* from */, del.Where, del.Limit, del.OrderBy)
 
 	// Build the final delete statement, including any returned expressions.
 	if resultsNeeded(del.Returning) {
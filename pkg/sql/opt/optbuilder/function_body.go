@@ -0,0 +1,115 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package optbuilder
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+)
+
+// buildFunctionBody builds the body of a call to a user-defined SQL function
+// as a scalar expression, so that the call can be inlined by the InlineFunction
+// normalization rule. The parameters of the function are represented by new
+// columns, which are returned in params; the rule replaces the references to
+// them with the arguments of the call.
+//
+// A nil body is returned if the function cannot be inlined. Only calls to
+// functions that are not volatile and whose body is a single expression of the
+// form "SELECT expr" are inlined. The expression must not contain subqueries,
+// aggregate, window or generator functions, or calls to other user-defined
+// functions, which rules out recursion.
+func (b *Builder) buildFunctionBody(
+	def *tree.FunctionDefinition, overload *tree.Overload, typ types.T,
+) (body opt.ScalarExpr, params opt.ColList) {
+	fn := overload.SQLFunction
+	if b.inliningFunction || isGenerator(def) || fn.Volatility == tree.FunctionVolatile {
+		return nil, nil
+	}
+	expr := inlinableFunctionExpr(fn.Body)
+	if expr == nil || !b.canInlineFunctionExpr(expr) {
+		return nil, nil
+	}
+
+	argTypes := overload.Types.(tree.ArgTypes)
+	bodyScope := b.allocScope()
+	bodyScope.context = fmt.Sprintf("function %s", def.Name)
+	params = make(opt.ColList, len(argTypes))
+	for i := range argTypes {
+		col := b.synthesizeColumn(bodyScope, argTypes[i].Name, argTypes[i].Typ, nil, nil)
+		params[i] = col.id
+	}
+
+	// The arguments are referenced by placeholders in the body of the function.
+	expr, err := tree.SimpleVisit(expr, func(e tree.Expr) (error, bool, tree.Expr) {
+		if p, ok := e.(*tree.Placeholder); ok {
+			return nil, false, &bodyScope.cols[p.Idx]
+		}
+		return nil, true, e
+	})
+	if err != nil {
+		panic(builderError{err})
+	}
+
+	b.inliningFunction = true
+	defer func() { b.inliningFunction = false }()
+	texpr := bodyScope.resolveType(expr, typ)
+	if !texpr.ResolvedType().Equivalent(typ) {
+		return nil, nil
+	}
+	return b.buildScalar(texpr, bodyScope, nil, nil, nil), params
+}
+
+// inlinableFunctionExpr returns the expression of a function body of the form
+// "SELECT expr", or nil if the body has another form.
+func inlinableFunctionExpr(body *tree.Select) tree.Expr {
+	if body.With != nil || body.OrderBy != nil || body.Limit != nil {
+		return nil
+	}
+	sel, ok := body.Select.(*tree.SelectClause)
+	if !ok || len(sel.Exprs) != 1 || len(sel.From.Tables) != 0 || sel.From.AsOf.Expr != nil ||
+		sel.Distinct || sel.DistinctOn != nil || sel.Where != nil || sel.GroupBy != nil ||
+		sel.Having != nil || sel.Window != nil {
+		return nil
+	}
+	return sel.Exprs[0].Expr
+}
+
+// canInlineFunctionExpr returns true if the expression of a function body can
+// be built as a scalar expression. See buildFunctionBody.
+func (b *Builder) canInlineFunctionExpr(expr tree.Expr) bool {
+	ok := true
+	_, _ = tree.SimpleVisit(expr, func(e tree.Expr) (error, bool, tree.Expr) {
+		switch t := e.(type) {
+		case *tree.Subquery, tree.UnqualifiedStar, *tree.UnresolvedName, *tree.ColumnItem:
+			ok = false
+
+		case *tree.FuncExpr:
+			if t.WindowDef != nil {
+				ok = false
+				break
+			}
+			// Only builtin functions are resolved here.
+			def, err := t.Func.Resolve(b.semaCtx.SearchPath, nil /* resolver */)
+			if err != nil || isAggregate(def) || isGenerator(def) {
+				ok = false
+			}
+		}
+		return nil, ok, e
+	})
+	return ok
+}
@@ -254,8 +254,15 @@ func (b *Builder) buildAggregation(
 				args[0] = b.factory.ConstructAggDistinct(args[0].(opt.ScalarExpr))
 			}
 
-			// Append any constant arguments without further processing.
-			args = append(args, agg.args[1:]...)
+			// Append any constant arguments without further processing. Other
+			// arguments are passed to the aggregate as input columns.
+			for j := 1; j < len(agg.args); j++ {
+				if memo.CanExtractConstDatum(agg.args[j]) {
+					args = append(args, agg.args[j])
+				} else {
+					args = append(args, b.factory.ConstructVariable(argCols[j].id))
+				}
+			}
 		}
 
 		aggCols[i].scalar = b.constructAggregate(agg.def.Name, args).(opt.ScalarExpr)
@@ -426,7 +433,7 @@ func (b *Builder) buildAggregateFunction(
 		FuncExpr: f,
 		def:      *def,
 		distinct: (f.Type == tree.DistinctFuncType),
-		args:     make(memo.ScalarListExpr, len(f.Exprs)),
+		args:     make(memo.ScalarListExpr, len(f.AggregateArgs())),
 	}
 
 	// Temporarily set b.subquery to nil so we don't add outer columns to the
@@ -435,7 +442,7 @@ func (b *Builder) buildAggregateFunction(
 	b.subquery = nil
 	defer func() { b.subquery = subq }()
 
-	for i, pexpr := range f.Exprs {
+	for i, pexpr := range f.AggregateArgs() {
 		// This synthesizes a new tempScope column, unless the argument is a
 		// simple VariableOp.
 		texpr := pexpr.(tree.TypedExpr)
@@ -491,6 +498,12 @@ func (b *Builder) constructAggregate(name string, args []opt.ScalarExpr) opt.Sca
 		return b.factory.ConstructBoolAnd(args[0])
 	case "bool_or":
 		return b.factory.ConstructBoolOr(args[0])
+	case "bit_and":
+		return b.factory.ConstructBitAnd(args[0])
+	case "bit_or":
+		return b.factory.ConstructBitOr(args[0])
+	case "every":
+		return b.factory.ConstructEvery(args[0])
 	case "concat_agg":
 		return b.factory.ConstructConcatAgg(args[0])
 	case "count":
@@ -511,6 +524,38 @@ func (b *Builder) constructAggregate(name string, args []opt.ScalarExpr) opt.Sca
 		return b.factory.ConstructVariance(args[0])
 	case "stddev":
 		return b.factory.ConstructStdDev(args[0])
+	case "var_pop":
+		return b.factory.ConstructVarPop(args[0])
+	case "stddev_pop":
+		return b.factory.ConstructStdDevPop(args[0])
+	case "var_samp":
+		return b.factory.ConstructVarSamp(args[0])
+	case "stddev_samp":
+		return b.factory.ConstructStdDevSamp(args[0])
+	case "covar_pop":
+		return b.factory.ConstructCovarPop(args[0], args[1])
+	case "covar_samp":
+		return b.factory.ConstructCovarSamp(args[0], args[1])
+	case "corr":
+		return b.factory.ConstructCorr(args[0], args[1])
+	case "regr_avgx":
+		return b.factory.ConstructRegrAvgX(args[0], args[1])
+	case "regr_avgy":
+		return b.factory.ConstructRegrAvgY(args[0], args[1])
+	case "regr_count":
+		return b.factory.ConstructRegrCount(args[0], args[1])
+	case "regr_intercept":
+		return b.factory.ConstructRegrIntercept(args[0], args[1])
+	case "regr_r2":
+		return b.factory.ConstructRegrR2(args[0], args[1])
+	case "regr_slope":
+		return b.factory.ConstructRegrSlope(args[0], args[1])
+	case "regr_sxx":
+		return b.factory.ConstructRegrSxx(args[0], args[1])
+	case "regr_sxy":
+		return b.factory.ConstructRegrSxy(args[0], args[1])
+	case "regr_syy":
+		return b.factory.ConstructRegrSyy(args[0], args[1])
 	case "xor_agg":
 		return b.factory.ConstructXorAgg(args[0])
 	case "json_agg":
@@ -518,12 +563,13 @@ func (b *Builder) constructAggregate(name string, args []opt.ScalarExpr) opt.Sca
 	case "jsonb_agg":
 		return b.factory.ConstructJsonbAgg(args[0])
 	case "string_agg":
-		if !memo.CanExtractConstDatum(args[1]) {
-			panic(builderError{
-				fmt.Errorf("unimplemented: aggregate functions with multiple non-constant expressions are not supported"),
-			})
-		}
 		return b.factory.ConstructStringAgg(args[0], args[1])
+	case "percentile_disc":
+		return b.factory.ConstructPercentileDisc(args[0], args[1])
+	case "percentile_cont":
+		return b.factory.ConstructPercentileCont(args[0], args[1])
+	case "mode":
+		return b.factory.ConstructMode(args[0])
 	}
 	panic(fmt.Sprintf("unhandled aggregate: %s", name))
 }
@@ -21,10 +21,12 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/pkg/errors"
 )
 
@@ -145,7 +147,7 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 	}
 
 	if ins.With != nil {
-		inScope = b.buildCTE(ins.With.CTEList, inScope)
+		inScope = b.buildCTE(ins.With, inScope)
 		defer b.checkCTEUsage(inScope)
 	}
 
@@ -253,7 +255,7 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 	case ins.OnConflict.IsUpsertAlias():
 		// Left-join each input row to the target table, using conflict columns
 		// derived from the primary index as the join condition.
-		mb.buildInputForUpsert(inScope, mb.getPrimaryKeyColumnNames(), nil)
+		mb.buildInputForUpsert(inScope, mb.tab.Index(cat.PrimaryIndex), nil)
 
 		// Add columns which will be updated by the Upsert when a conflict occurs.
 		// These are derived from the insert columns.
@@ -270,7 +272,12 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 	default:
 		// Left-join each input row to the target table, using the conflict columns
 		// as the join condition.
-		mb.buildInputForUpsert(inScope, ins.OnConflict.Columns, ins.OnConflict.Where)
+		// Check that the ON CONFLICT columns reference at most one target row.
+		// Using LEFT OUTER JOIN to detect conflicts relies upon this being true
+		// (otherwise result cardinality could increase). This is also a Postgres
+		// requirement.
+		conflictIndex := mb.ensureUniqueConflictIndex(ins.OnConflict)
+		mb.buildInputForUpsert(inScope, conflictIndex, ins.OnConflict.Where)
 
 		// Derive the columns that will be updated from the SET expressions.
 		mb.addTargetColsForUpdate(ins.OnConflict.Exprs)
@@ -549,12 +556,12 @@ func (mb *mutationBuilder) buildInsert(returning tree.ReturningExprs) {
 func (mb *mutationBuilder) buildInputForDoNothing(inScope *scope, onConflict *tree.OnConflict) {
 	// DO NOTHING clause does not require ON CONFLICT columns.
 	var conflictIndex cat.Index
-	if len(onConflict.Columns) != 0 {
+	if len(onConflict.Columns) != 0 || onConflict.Constraint != "" {
 		// Check that the ON CONFLICT columns reference at most one target row by
 		// ensuring they match columns of a UNIQUE index. Using LEFT OUTER JOIN
 		// to detect conflicts relies upon this being true (otherwise result
 		// cardinality could increase). This is also a Postgres requirement.
-		conflictIndex = mb.ensureUniqueConflictCols(onConflict.Columns)
+		conflictIndex = mb.ensureUniqueConflictIndex(onConflict)
 	}
 
 	insertColSet := mb.outScope.expr.Relational().OutputCols
@@ -573,6 +580,11 @@ func (mb *mutationBuilder) buildInputForDoNothing(inScope *scope, onConflict *tr
 			continue
 		}
 
+		if _, isPartial := index.Predicate(); isPartial && conflictIndex == nil {
+			panic(builderError{pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+				"ON CONFLICT DO NOTHING is not supported on tables with partial unique indexes")})
+		}
+
 		// Build the right side of the left outer join.
 		tn := mb.tab.Name().TableName
 		alias := tree.MakeUnqualifiedTableName(tree.Name(fmt.Sprintf("%s_%d", tn, idx+1)))
@@ -581,6 +593,7 @@ func (mb *mutationBuilder) buildInputForDoNothing(inScope *scope, onConflict *tr
 			&alias,
 			nil, /* ordinals */
 			nil, /* indexFlags */
+			noRowLocking,
 			excludeMutations,
 			inScope,
 		)
@@ -612,6 +625,7 @@ func (mb *mutationBuilder) buildInputForDoNothing(inScope *scope, onConflict *tr
 			)
 			on = append(on, memo.FiltersItem{Condition: condition})
 		}
+		on = append(on, mb.buildArbiterPredicateFilters(index, scanScope)...)
 
 		// Construct the left join + filter.
 		// TODO(andyk): Convert this to use anti-join once we have support for
@@ -646,14 +660,8 @@ func (mb *mutationBuilder) buildInputForDoNothing(inScope *scope, onConflict *tr
 // given insert row conflicts with an existing row in the table. If it is null,
 // then there is no conflict.
 func (mb *mutationBuilder) buildInputForUpsert(
-	inScope *scope, conflictCols tree.NameList, whereClause *tree.Where,
+	inScope *scope, conflictIndex cat.Index, whereClause *tree.Where,
 ) {
-	// Check that the ON CONFLICT columns reference at most one target row.
-	// Using LEFT OUTER JOIN to detect conflicts relies upon this being true
-	// (otherwise result cardinality could increase). This is also a Postgres
-	// requirement.
-	mb.ensureUniqueConflictCols(conflictCols)
-
 	// Re-alias all INSERT columns so that they are accessible as if they were
 	// part of a special data source named "crdb_internal.excluded".
 	for i := range mb.outScope.cols {
@@ -667,6 +675,7 @@ func (mb *mutationBuilder) buildInputForUpsert(
 		mb.alias,
 		nil, /* ordinals */
 		nil, /* indexFlags */
+		noRowLocking,
 		includeMutations,
 		inScope,
 	)
@@ -694,19 +703,15 @@ func (mb *mutationBuilder) buildInputForUpsert(
 	//   ON ins.x = scan.a AND ins.y = scan.b
 	//
 	var on memo.FiltersExpr
-	for _, name := range conflictCols {
-		for i := range fetchScope.cols {
-			fetchCol := &fetchScope.cols[i]
-			if fetchCol.name == name {
-				condition := mb.b.factory.ConstructEq(
-					mb.b.factory.ConstructVariable(mb.insertColList[i]),
-					mb.b.factory.ConstructVariable(fetchCol.id),
-				)
-				on = append(on, memo.FiltersItem{Condition: condition})
-				break
-			}
-		}
+	for i, n := 0, conflictIndex.LaxKeyColumnCount(); i < n; i++ {
+		ord := conflictIndex.Column(i).Ordinal
+		condition := mb.b.factory.ConstructEq(
+			mb.b.factory.ConstructVariable(mb.insertColList[ord]),
+			mb.b.factory.ConstructVariable(fetchScope.cols[ord].id),
+		)
+		on = append(on, memo.FiltersItem{Condition: condition})
 	}
+	on = append(on, mb.buildArbiterPredicateFilters(conflictIndex, fetchScope)...)
 
 	// Construct the left join.
 	mb.outScope.expr = mb.b.factory.ConstructLeftJoin(
@@ -894,11 +899,30 @@ func (mb *mutationBuilder) projectUpsertColumns() {
 	mb.outScope = projectionsScope
 }
 
-// ensureUniqueConflictCols tries to prove that the given list of column names
-// correspond to the columns of at least one UNIQUE index on the target table.
-// If true, then ensureUniqueConflictCols returns the matching index. Otherwise,
-// it reports an error.
-func (mb *mutationBuilder) ensureUniqueConflictCols(cols tree.NameList) cat.Index {
+// ensureUniqueConflictIndex tries to prove that the conflict target of the
+// given ON CONFLICT clause corresponds to at least one UNIQUE index on the
+// target table: either the index of the named unique constraint, or an index on
+// the given list of column names. If true, then ensureUniqueConflictIndex
+// returns the matching index. Otherwise, it reports an error.
+func (mb *mutationBuilder) ensureUniqueConflictIndex(onConflict *tree.OnConflict) cat.Index {
+	if onConflict.Constraint != "" {
+		// The unique constraints are the unique indexes that are not partial,
+		// and have the same name.
+		for idx, idxCount := 0, mb.tab.IndexCount(); idx < idxCount; idx++ {
+			index := mb.tab.Index(idx)
+			if index.Name() != onConflict.Constraint || !index.IsUnique() {
+				continue
+			}
+			if _, isPartial := index.Predicate(); !isPartial {
+				return index
+			}
+		}
+		panic(builderError{pgerror.NewErrorf(pgerror.CodeUndefinedObjectError,
+			"constraint %q for table %q does not exist",
+			onConflict.Constraint, mb.tab.Name().TableName)})
+	}
+
+	cols := onConflict.Columns
 	for idx, idxCount := 0, mb.tab.IndexCount(); idx < idxCount; idx++ {
 		index := mb.tab.Index(idx)
 
@@ -918,6 +942,21 @@ func (mb *mutationBuilder) ensureUniqueConflictCols(cols tree.NameList) cat.Inde
 			}
 		}
 
+		// Partial indexes only ensure uniqueness among the rows that satisfy
+		// their predicate. They can only be used if the ON CONFLICT clause
+		// restricts the conflicts to these rows.
+		if pred, isPartial := index.Predicate(); found && isPartial {
+			if onConflict.ArbiterPredicate == nil {
+				found = false
+			} else {
+				implied, err := sqlbase.IndexPredicateImpliedBy(pred, onConflict.ArbiterPredicate)
+				if err != nil {
+					panic(builderError{err})
+				}
+				found = implied
+			}
+		}
+
 		if found {
 			return index
 		}
@@ -926,13 +965,44 @@ func (mb *mutationBuilder) ensureUniqueConflictCols(cols tree.NameList) cat.Inde
 		"there is no unique or exclusion constraint matching the ON CONFLICT specification")})
 }
 
-// getPrimaryKeyColumnNames returns the names of all primary key columns in the
-// target table.
-func (mb *mutationBuilder) getPrimaryKeyColumnNames() tree.NameList {
-	pkIndex := mb.tab.Index(cat.PrimaryIndex)
-	names := make(tree.NameList, pkIndex.KeyColumnCount())
-	for i, n := 0, pkIndex.KeyColumnCount(); i < n; i++ {
-		names[i] = pkIndex.Column(i).Column.ColName()
+// buildArbiterPredicateFilters returns the conditions that restrict the
+// conflicts detected by the join built by buildInputForUpsert or
+// buildInputForDoNothing to the rows that satisfy the predicate of a partial
+// conflict index: both the insert row and the existing row must satisfy it.
+// It returns nil if the index is not partial.
+func (mb *mutationBuilder) buildArbiterPredicateFilters(
+	index cat.Index, fetchScope *scope,
+) memo.FiltersExpr {
+	predStr, isPartial := index.Predicate()
+	if !isPartial {
+		return nil
+	}
+	pred, err := parser.ParseExpr(predStr)
+	if err != nil {
+		panic(builderError{err})
+	}
+
+	// The predicate of the insert row refers to the insert columns by the
+	// names of the table columns.
+	insertScope := mb.b.allocScope()
+	for i, colID := range mb.insertColList {
+		if colID == 0 {
+			continue
+		}
+		col := mb.tab.Column(i)
+		insertScope.cols = append(insertScope.cols, scopeColumn{
+			id:   colID,
+			name: col.ColName(),
+			typ:  col.DatumType(),
+		})
+	}
+
+	var filters memo.FiltersExpr
+	for _, s := range []*scope{insertScope, fetchScope} {
+		texpr := s.resolveAndRequireType(pred, types.Bool)
+		filters = append(filters, memo.FiltersItem{
+			Condition: mb.b.buildScalar(texpr, s, nil, nil, nil),
+		})
 	}
-	return names
+	return filters
 }
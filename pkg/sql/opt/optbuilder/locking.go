@@ -0,0 +1,94 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// scanLocking returns the row-level locking which the locking clause of the
+// SELECT statement being built requests for the table with the given name or
+// alias.
+func (b *Builder) scanLocking(name tree.Name) memo.ScanLocking {
+	strength, waitPolicy := b.lockingClause.Strength(name)
+	if strength == tree.ForNone {
+		return noRowLocking
+	}
+	return memo.ScanLocking{Strength: strength, WaitPolicy: waitPolicy}
+}
+
+// checkLockingStrengths verifies that the items of the given locking clause
+// only request exclusive locks.
+func checkLockingStrengths(locking tree.LockingClause) {
+	for _, item := range locking {
+		if item.Strength == tree.ForKeyShare || item.Strength == tree.ForShare {
+			panic(builderError{sqlbase.NewLockingStrengthUnsupportedError(item.Strength)})
+		}
+	}
+}
+
+// checkLockingTargets verifies that the tables listed in the OF clauses of
+// the locking clause of the SELECT statement being built are all part of its
+// FROM clause, which has been built into fromScope.
+func (b *Builder) checkLockingTargets(fromScope *scope) {
+	for _, item := range b.lockingClause {
+		for i := range item.Targets {
+			name := item.Targets[i].TableName
+			found := false
+			for j := range fromScope.cols {
+				if fromScope.cols[j].table.TableName == name {
+					found = true
+					break
+				}
+			}
+			if !found {
+				panic(builderError{sqlbase.NewLockingTargetNotFoundError(item.Strength, name)})
+			}
+		}
+	}
+}
+
+// lockingNotAllowedWith returns the clause of the given statement which
+// prevents its rows from being locked, or the empty string if there is none.
+// fromScope is the scope of the FROM clause of the statement if it is a
+// SELECT clause, after the analysis of its projection list.
+func lockingNotAllowedWith(stmt tree.SelectStatement, fromScope *scope) string {
+	switch t := stmt.(type) {
+	case *tree.SelectClause:
+		switch {
+		case t.Distinct:
+			return "DISTINCT clause"
+		case len(t.GroupBy) > 0:
+			return "GROUP BY clause"
+		case t.Having != nil:
+			return "HAVING clause"
+		case fromScope.hasAggregates():
+			return "aggregate functions"
+		case len(t.Window) > 0:
+			return "window functions"
+		case len(fromScope.srfs) > 0:
+			return "set-returning functions in the target list"
+		}
+		return ""
+	case *tree.UnionClause:
+		return "UNION/INTERSECT/EXCEPT"
+	case *tree.ValuesClause:
+		return "VALUES"
+	default:
+		return stmt.StatementTag()
+	}
+}
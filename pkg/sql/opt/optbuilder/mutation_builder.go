@@ -19,6 +19,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -99,6 +100,11 @@ type mutationBuilder struct {
 }
 
 func (mb *mutationBuilder) init(b *Builder, op opt.Operator, tab cat.Table, alias *tree.TableName) {
+	if tab.IsMaterializedView() {
+		// The rows of a materialized view can only be changed by a refresh.
+		panic(builderError{sqlbase.NewWrongObjectTypeError(tab.Name(), "table")})
+	}
+
 	mb.b = b
 	mb.md = b.factory.Metadata()
 	mb.op = op
@@ -123,15 +129,27 @@ func (mb *mutationBuilder) init(b *Builder, op opt.Operator, tab cat.Table, alia
 // the Update or Delete operator, similar to this:
 //
 //   SELECT <cols>
-//   FROM <table>
+//   FROM <table>, <from>
 //   WHERE <where>
 //   ORDER BY <order-by>
 //   LIMIT <limit>
 //
 // All columns from the table to update are added to fetchColList.
+//
+// If additional tables are specified in the FROM clause (UPDATE only), they are
+// joined with the target table, and their columns are available to the WHERE
+// clause and to the SET expressions. As in Postgres, a target row that joins
+// with more than one row of the FROM tables is only updated once, using the
+// values of an arbitrary matching row. This is done by de-duplicating the
+// joined rows on the primary key of the target table:
+//
+//   SELECT DISTINCT ON (<pk-cols>) <cols>
+//   FROM <table>, <from>
+//   WHERE <where>
+//
 // TODO(andyk): Do needed column analysis to project fewer columns if possible.
 func (mb *mutationBuilder) buildInputForUpdateOrDelete(
-	inScope *scope, where *tree.Where, limit *tree.Limit, orderBy tree.OrderBy,
+	inScope *scope, from tree.TableExprs, where *tree.Where, limit *tree.Limit, orderBy tree.OrderBy,
 ) {
 	// FROM
 	mb.outScope = mb.b.buildScan(
@@ -139,13 +157,30 @@ func (mb *mutationBuilder) buildInputForUpdateOrDelete(
 		mb.alias,
 		nil, /* ordinals */
 		nil, /* indexFlags */
+		noRowLocking,
 		includeMutations,
 		inScope,
 	)
 
+	if len(from) > 0 {
+		fromScope := mb.b.buildFromTables(from, inScope)
+
+		// Check that the same table name is not used multiple times.
+		mb.b.validateJoinTableNames(mb.outScope, fromScope)
+
+		mb.outScope.appendColumnsFromScope(fromScope)
+		left := mb.outScope.expr.(memo.RelExpr)
+		right := fromScope.expr.(memo.RelExpr)
+		mb.outScope.expr = mb.b.factory.ConstructInnerJoin(left, right, memo.TrueFilter)
+	}
+
 	// WHERE
 	mb.b.buildWhere(where, mb.outScope)
 
+	if len(from) > 0 {
+		mb.buildDistinctOnPrimaryKey()
+	}
+
 	// SELECT + ORDER BY (which may add projected expressions)
 	projectionsScope := mb.outScope.replace()
 	projectionsScope.appendColumnsFromScope(mb.outScope)
@@ -160,13 +195,41 @@ func (mb *mutationBuilder) buildInputForUpdateOrDelete(
 
 	mb.outScope = projectionsScope
 
-	// Set list of columns that will be fetched by the input expression.
+	// Set list of columns that will be fetched by the input expression. The
+	// columns of the target table come first, followed by the columns of any
+	// FROM tables.
 	mb.fetchColList = make(opt.ColList, cap(mb.targetColList))
-	for i := range mb.outScope.cols {
+	for i := range mb.fetchColList {
 		mb.fetchColList[i] = mb.outScope.cols[i].id
 	}
 }
 
+// buildDistinctOnPrimaryKey wraps the input expression in a DistinctOn operator
+// that groups on the primary key columns of the target table, so that at most
+// one input row is produced for each target row. The remaining columns take
+// the values of an arbitrary row in each group.
+func (mb *mutationBuilder) buildDistinctOnPrimaryKey() {
+	var private memo.GroupingPrivate
+	primary := mb.tab.Index(cat.PrimaryIndex)
+	for i, n := 0, primary.KeyColumnCount(); i < n; i++ {
+		// The target table columns are the first columns in scope.
+		private.GroupingCols.Add(int(mb.outScope.cols[primary.Column(i).Ordinal].id))
+	}
+
+	aggs := make(memo.AggregationsExpr, 0, len(mb.outScope.cols))
+	for i := range mb.outScope.cols {
+		if id := mb.outScope.cols[i].id; !private.GroupingCols.Contains(int(id)) {
+			aggs = append(aggs, memo.AggregationsItem{
+				Agg:        mb.b.factory.ConstructFirstAgg(mb.b.factory.ConstructVariable(id)),
+				ColPrivate: memo.ColPrivate{Col: id},
+			})
+		}
+	}
+
+	input := mb.outScope.expr.(memo.RelExpr)
+	mb.outScope.expr = mb.b.factory.ConstructDistinctOn(input, aggs, &private)
+}
+
 // addTargetColsByName adds one target column for each of the names in the given
 // list.
 func (mb *mutationBuilder) addTargetColsByName(names tree.NameList) {
@@ -251,7 +251,9 @@ func (b *Builder) analyzeExtraArgument(
 }
 
 func ensureColumnOrderable(e tree.TypedExpr) {
-	if _, ok := e.ResolvedType().(types.TArray); ok || e.ResolvedType() == types.JSON {
-		panic(unimplementedf("can't order by column type %s", e.ResolvedType()))
+	typ := e.ResolvedType()
+	if _, ok := typ.(types.TArray); ok || typ == types.JSON ||
+		typ == types.TSVector || typ == types.TSQuery {
+		panic(unimplementedf("can't order by column type %s", typ))
 	}
 }
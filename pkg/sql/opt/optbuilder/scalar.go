@@ -433,7 +433,7 @@ func (b *Builder) buildFunction(
 		panic(unimplementedf("window functions are not supported"))
 	}
 
-	def, err := f.Func.Resolve(b.semaCtx.SearchPath)
+	def, err := f.Func.Resolve(b.semaCtx.SearchPath, b.semaCtx.FunctionResolver)
 	if err != nil {
 		panic(builderError{err})
 	}
@@ -448,12 +448,17 @@ func (b *Builder) buildFunction(
 	}
 
 	// Construct a private FuncOpDef that refers to a resolved function overload.
-	out = b.factory.ConstructFunction(args, &memo.FunctionPrivate{
+	private := &memo.FunctionPrivate{
 		Name:       def.Name,
 		Typ:        f.ResolvedType(),
 		Properties: &def.FunctionProperties,
 		Overload:   f.ResolvedOverload(),
-	})
+	}
+	if private.Overload.SQLFunction != nil {
+		b.HadUserDefinedFunctions = true
+		private.Body, private.Params = b.buildFunctionBody(def, private.Overload, private.Typ)
+	}
+	out = b.factory.ConstructFunction(args, private)
 
 	if isGenerator(def) {
 		columns := len(def.ReturnLabels)
@@ -607,6 +612,10 @@ func (b *Builder) constructComparison(
 		return b.factory.ConstructJsonExists(left, right)
 	case tree.JSONAllExists:
 		return b.factory.ConstructJsonAllExists(left, right)
+	case tree.Overlaps:
+		return b.factory.ConstructOverlaps(left, right)
+	case tree.TSMatches:
+		return b.factory.ConstructTSMatches(left, right)
 	case tree.JSONSomeExists:
 		return b.factory.ConstructJsonSomeExists(left, right)
 	}
@@ -90,6 +90,11 @@ type cteSource struct {
 	// to only having a single reference to a given CTE, so if this is set then
 	// this CTE has already been referenced and may not be referenced again.
 	used bool
+
+	// refErr, if set, is the error raised when this CTE is referenced. It is
+	// used to disallow self-references in the parts of a recursive CTE where
+	// they are not permitted.
+	refErr error
 }
 
 // groupByStrSet is a set of stringified GROUP BY expressions that map to the
@@ -809,7 +814,7 @@ func (s *scope) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
 			panic(unimplementedf("window functions are not supported"))
 		}
 
-		def, err := t.Func.Resolve(s.builder.semaCtx.SearchPath)
+		def, err := t.Func.Resolve(s.builder.semaCtx.SearchPath, s.builder.semaCtx.FunctionResolver)
 		if err != nil {
 			panic(builderError{err})
 		}
@@ -999,7 +1004,7 @@ func (s *scope) replaceCount(
 			if _, err := e.TypeCheck(&tree.SemaContext{}, types.Any); err != nil {
 				panic(builderError{err})
 			}
-			newDef, err := e.Func.Resolve(s.builder.semaCtx.SearchPath)
+			newDef, err := e.Func.Resolve(s.builder.semaCtx.SearchPath, s.builder.semaCtx.FunctionResolver)
 			if err != nil {
 				panic(builderError{err})
 			}
@@ -25,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/pkg/errors"
 )
@@ -34,6 +35,10 @@ const (
 	includeMutations = true
 )
 
+// noRowLocking is passed to buildScan for the scans which do not lock the
+// rows they read.
+var noRowLocking = memo.ScanLocking{}
+
 // buildDataSource builds a set of memo groups that represent the given table
 // expression. For example, if the tree.TableExpr consists of a single table,
 // the resulting set of memo groups will consist of a single group with a
@@ -53,6 +58,12 @@ func (b *Builder) buildDataSource(
 			indexFlags = source.IndexFlags
 		}
 
+		if source.As.Alias != "" && len(b.lockingClause) > 0 {
+			// The locking clause refers to an aliased table by its alias.
+			defer func(prev tree.LockingClause) { b.lockingClause = prev }(b.lockingClause)
+			b.lockingClause = b.lockingClause.ForAlias(source.As.Alias)
+		}
+
 		outScope = b.buildDataSource(source.Expr, indexFlags, inScope)
 
 		if source.Ordinality {
@@ -72,6 +83,9 @@ func (b *Builder) buildDataSource(
 
 		// CTEs take precedence over other data sources.
 		if cte := inScope.resolveCTE(tn); cte != nil {
+			if cte.refErr != nil {
+				panic(builderError{cte.refErr})
+			}
 			if cte.used {
 				panic(builderError{fmt.Errorf("unsupported multiple use of CTE clause %q", tn)})
 			}
@@ -89,7 +103,10 @@ func (b *Builder) buildDataSource(
 		ds := b.resolveDataSource(tn, privilege.SELECT)
 		switch t := ds.(type) {
 		case cat.Table:
-			return b.buildScan(t, tn, nil /* ordinals */, indexFlags, excludeMutations, inScope)
+			locking := b.scanLocking(tn.TableName)
+			return b.buildScan(
+				t, tn, nil /* ordinals */, indexFlags, locking, excludeMutations, inScope,
+			)
 		case cat.View:
 			return b.buildView(t, inScope)
 		default:
@@ -114,6 +131,10 @@ func (b *Builder) buildDataSource(
 		return outScope
 
 	case *tree.StatementSource:
+		// The locking clause does not apply to the tables of the statement.
+		defer func(prev tree.LockingClause) { b.lockingClause = prev }(b.lockingClause)
+		b.lockingClause = nil
+
 		outScope = b.buildStmt(source.Statement, inScope)
 		if len(outScope.cols) == 0 {
 			panic(builderError{pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
@@ -125,7 +146,11 @@ func (b *Builder) buildDataSource(
 		ds := b.resolveDataSourceRef(source, privilege.SELECT)
 		switch t := ds.(type) {
 		case cat.Table:
-			outScope = b.buildScanFromTableRef(t, source, indexFlags, inScope)
+			name := t.Name().TableName
+			if source.As.Alias != "" {
+				name = source.As.Alias
+			}
+			outScope = b.buildScanFromTableRef(t, source, indexFlags, b.scanLocking(name), inScope)
 		default:
 			panic(unimplementedf("view and sequence numeric refs are not supported"))
 		}
@@ -252,7 +277,11 @@ func (b *Builder) renameSource(as tree.AliasClause, scope *scope) {
 // Note, the query SELECT * FROM [53() as t] is unsupported. Column lists must
 // be non-empty
 func (b *Builder) buildScanFromTableRef(
-	tab cat.Table, ref *tree.TableRef, indexFlags *tree.IndexFlags, inScope *scope,
+	tab cat.Table,
+	ref *tree.TableRef,
+	indexFlags *tree.IndexFlags,
+	locking memo.ScanLocking,
+	inScope *scope,
 ) (outScope *scope) {
 	if ref.Columns != nil && len(ref.Columns) == 0 {
 		panic(builderError{pgerror.NewErrorf(pgerror.CodeSyntaxError,
@@ -283,7 +312,7 @@ func (b *Builder) buildScanFromTableRef(
 			ordinals[i] = ord
 		}
 	}
-	return b.buildScan(tab, tab.Name(), ordinals, indexFlags, excludeMutations, inScope)
+	return b.buildScan(tab, tab.Name(), ordinals, indexFlags, locking, excludeMutations, inScope)
 }
 
 // buildScan builds a memo group for a ScanOp or VirtualScanOp expression on the
@@ -295,6 +324,9 @@ func (b *Builder) buildScanFromTableRef(
 // list are projected by the scan. Otherwise, all columns from the table are
 // projected.
 //
+// If locking is set, the scan locks the rows it reads, which requires the
+// UPDATE privilege. Locking scans can only use the primary index.
+//
 // See Builder.buildStmt for a description of the remaining input and return
 // values.
 func (b *Builder) buildScan(
@@ -302,6 +334,7 @@ func (b *Builder) buildScan(
 	tn *tree.TableName,
 	ordinals []int,
 	indexFlags *tree.IndexFlags,
+	locking memo.ScanLocking,
 	scanMutationCols bool,
 	inScope *scope,
 ) (outScope *scope) {
@@ -355,6 +388,11 @@ func (b *Builder) buildScan(
 	} else {
 		private := memo.ScanPrivate{Table: tabID, Cols: tabColIDs}
 
+		if locking.IsLocking() {
+			b.checkPrivilege(tab, privilege.UPDATE)
+			private.Locking = locking
+		}
+
 		if indexFlags != nil {
 			private.Flags.NoIndexJoin = indexFlags.NoIndexJoin
 			if indexFlags.Index != "" || indexFlags.IndexID != 0 {
@@ -375,6 +413,9 @@ func (b *Builder) buildScan(
 					}
 					panic(builderError{err})
 				}
+				if locking.IsLocking() && idx != cat.PrimaryIndex {
+					panic(builderError{sqlbase.NewLockingSecondaryIndexError(string(tab.Index(idx).Name()))})
+				}
 				private.Flags.ForceIndex = true
 				private.Flags.Index = idx
 				private.Flags.Direction = indexFlags.Direction
@@ -382,10 +423,60 @@ func (b *Builder) buildScan(
 		}
 
 		outScope.expr = b.factory.ConstructScan(&private)
+		b.addPartialIndexPredicatesForTable(tabID)
 	}
 	return outScope
 }
 
+// addPartialIndexPredicatesForTable builds the predicate of each partial index
+// of the given table as a filters expression over the table's columns, and
+// records it in the table metadata. The optimizer uses these predicates to
+// decide whether a partial index can be used to answer a query.
+func (b *Builder) addPartialIndexPredicatesForTable(tabID opt.TableID) {
+	tabMeta := b.factory.Metadata().TableMeta(tabID)
+	tab := tabMeta.Table
+
+	var predScope *scope
+	for i, n := 0, tab.IndexCount(); i < n; i++ {
+		predStr, ok := tab.Index(i).Predicate()
+		if !ok {
+			continue
+		}
+
+		if predScope == nil {
+			// The predicate can refer to any column of the table.
+			predScope = b.allocScope()
+			predScope.cols = make([]scopeColumn, 0, tab.ColumnCount())
+			for j, cnt := 0, tab.ColumnCount(); j < cnt; j++ {
+				col := tab.Column(j)
+				predScope.cols = append(predScope.cols, scopeColumn{
+					id:   tabID.ColumnID(j),
+					name: col.ColName(),
+					typ:  col.DatumType(),
+				})
+			}
+		}
+
+		expr, err := parser.ParseExpr(predStr)
+		if err != nil {
+			panic(builderError{err})
+		}
+		texpr := predScope.resolveAndRequireType(expr, types.Bool)
+		scalar := b.buildScalar(texpr, predScope, nil, nil, nil)
+
+		// Split the predicate into conjuncts, in the same way that the filters of
+		// a Select operator are normalized, so that they can be matched against
+		// the conjuncts of a query filter. Computing the scalar properties up
+		// front ensures that the predicate is not modified later on, since it
+		// can be shared by copies of the memo.
+		filters := b.factory.CustomFuncs().SimplifyFilters(memo.FiltersExpr{{Condition: scalar}})
+		for j := range filters {
+			filters[j].ScalarProps(b.factory.Memo())
+		}
+		tabMeta.AddPartialIndexPredicate(i, &filters)
+	}
+}
+
 // buildWithOrdinality builds a group which appends an increasing integer column to
 // the output. colName optionally denotes the name this column is given, or can
 // be blank for none.
@@ -407,39 +498,26 @@ func (b *Builder) buildWithOrdinality(colName string, inScope *scope) (outScope
 	return inScope
 }
 
-func (b *Builder) buildCTE(ctes []*tree.CTE, inScope *scope) (outScope *scope) {
+func (b *Builder) buildCTE(with *tree.With, inScope *scope) (outScope *scope) {
 	outScope = inScope.push()
 
 	outScope.ctes = make(map[string]*cteSource)
-	for i := range ctes {
-		cteScope := b.buildStmt(ctes[i].Stmt, outScope)
-		cols := cteScope.cols
-		name := ctes[i].Name.Alias
+	for _, cte := range with.CTEList {
+		name := cte.Name.Alias
 
 		if _, ok := outScope.ctes[name.String()]; ok {
 			panic(builderError{
-				fmt.Errorf("WITH query name %s specified more than once", ctes[i].Name.Alias),
+				fmt.Errorf("WITH query name %s specified more than once", cte.Name.Alias),
 			})
 		}
 
-		// Names for the output columns can optionally be specified.
-		if ctes[i].Name.Cols != nil {
-			if len(cteScope.cols) != len(ctes[i].Name.Cols) {
-				panic(builderError{
-					fmt.Errorf(
-						"source %q has %d columns available but %d columns specified",
-						name, len(cteScope.cols), len(ctes[i].Name.Cols),
-					),
-				})
-			}
-
-			cols = make([]scopeColumn, len(cteScope.cols))
-			tableName := tree.MakeUnqualifiedTableName(ctes[i].Name.Alias)
-			copy(cols, cteScope.cols)
-			for j := range cols {
-				cols[j].name = ctes[i].Name.Cols[j]
-				cols[j].table = tableName
-			}
+		var expr memo.RelExpr
+		var cols []scopeColumn
+		if with.Recursive {
+			expr, cols = b.buildRecursiveCTE(cte, outScope)
+		} else {
+			cteScope := b.buildStmt(cte.Stmt, outScope)
+			expr, cols = cteScope.expr, b.getCTECols(cteScope.cols, cte.Name)
 		}
 
 		if len(cols) == 0 {
@@ -447,16 +525,181 @@ func (b *Builder) buildCTE(ctes []*tree.CTE, inScope *scope) (outScope *scope) {
 				"WITH clause %q does not have a RETURNING clause", tree.ErrString(&name))})
 		}
 
-		outScope.ctes[ctes[i].Name.Alias.String()] = &cteSource{
-			name: ctes[i].Name,
+		outScope.ctes[name.String()] = &cteSource{
+			name: cte.Name,
 			cols: cols,
-			expr: cteScope.expr,
+			expr: expr,
 		}
 	}
 
 	return outScope
 }
 
+// getCTECols returns the output columns of a CTE, renamed according to the
+// column names in the CTE's alias clause (if any were specified).
+func (b *Builder) getCTECols(cteCols []scopeColumn, name tree.AliasClause) []scopeColumn {
+	if name.Cols == nil {
+		return cteCols
+	}
+
+	// Names for the output columns can optionally be specified.
+	if len(cteCols) != len(name.Cols) {
+		panic(builderError{
+			fmt.Errorf(
+				"source %q has %d columns available but %d columns specified",
+				name.Alias, len(cteCols), len(name.Cols),
+			),
+		})
+	}
+
+	cols := make([]scopeColumn, len(cteCols))
+	tableName := tree.MakeUnqualifiedTableName(name.Alias)
+	copy(cols, cteCols)
+	for i := range cols {
+		cols[i].name = name.Cols[i]
+		cols[i].table = tableName
+	}
+	return cols
+}
+
+// buildRecursiveCTE builds a CTE that appears in a WITH RECURSIVE clause. If
+// the CTE refers to itself, it must have the form:
+//
+//   <initial query> UNION [ALL] <recursive query>
+//
+// where only the recursive query contains the (single) self-reference. The
+// result is a RecursiveCTE operator; inside the recursive query, the reference
+// is built as a WorkTableScan operator. A CTE that does not refer to itself is
+// built like a regular CTE.
+func (b *Builder) buildRecursiveCTE(
+	cte *tree.CTE, inScope *scope,
+) (expr memo.RelExpr, cols []scopeColumn) {
+	name := cte.Name.Alias
+
+	// selfRefScope makes the CTE visible to the statement that defines it. Any
+	// reference from a place where it is not allowed raises refErr.
+	selfRefScope := func(refErr error) (*scope, *cteSource) {
+		s := inScope.push()
+		src := &cteSource{name: cte.Name, refErr: refErr}
+		s.ctes = map[string]*cteSource{name.String(): src}
+		return s, src
+	}
+
+	clause, ok := recursiveCTEUnion(cte.Stmt)
+	if !ok {
+		// The CTE isn't in the form that allows recursion, so any reference to
+		// itself is an error.
+		s, _ := selfRefScope(pgerror.NewErrorf(pgerror.CodeInvalidRecursionError,
+			"recursive query %q does not have the form non-recursive-term UNION [ALL] recursive-term",
+			tree.ErrString(&name),
+		))
+		cteScope := b.buildStmt(cte.Stmt, s)
+		return cteScope.expr, b.getCTECols(cteScope.cols, cte.Name)
+	}
+
+	// Build the initial query, which may not refer to the CTE.
+	s, _ := selfRefScope(pgerror.NewErrorf(pgerror.CodeInvalidRecursionError,
+		"recursive reference to query %q must not appear within its non-recursive term",
+		tree.ErrString(&name),
+	))
+	initialScope := b.buildSelect(clause.Left, nil /* desiredTypes */, s)
+	initialScope.removeHiddenCols()
+	initialCols := b.getCTECols(initialScope.cols, cte.Name)
+
+	// Synthesize the output columns of the CTE. They have the types of the
+	// initial query, and they are also the columns of the working table.
+	tableName := tree.MakeUnqualifiedTableName(name)
+	outScope := inScope.push()
+	for i := range initialCols {
+		col := &initialCols[i]
+		b.synthesizeColumn(outScope, string(col.name), col.typ, nil, nil /* scalar */)
+		outScope.cols[i].table = tableName
+	}
+	outCols := colsToColList(outScope.cols)
+
+	b.numRecursiveCTEs++
+	id := b.numRecursiveCTEs
+
+	// Build the recursive query, in which references to the CTE scan the
+	// working table.
+	s, src := selfRefScope(nil /* refErr */)
+	src.cols = outScope.cols
+	src.expr = b.factory.ConstructWorkTableScan(&memo.WorkTableScanPrivate{
+		Name: string(name),
+		ID:   id,
+		Cols: outCols,
+	})
+	recursiveScope := b.buildSelect(clause.Right, nil /* desiredTypes */, s)
+	recursiveScope.removeHiddenCols()
+
+	if !src.used {
+		// The CTE doesn't refer to itself, so build it as a regular UNION.
+		cteScope := b.buildSetOp(clause.Type, clause.All, initialScope, recursiveScope, inScope)
+		return cteScope.expr, b.getCTECols(cteScope.cols, cte.Name)
+	}
+
+	if len(recursiveScope.cols) != len(initialCols) {
+		panic(builderError{pgerror.NewErrorf(
+			pgerror.CodeSyntaxError,
+			"each UNION query must have the same number of columns: %d vs %d",
+			len(initialCols), len(recursiveScope.cols),
+		)})
+	}
+	for i := range recursiveScope.cols {
+		l, r := initialCols[i].typ, recursiveScope.cols[i].typ
+		if l == types.Unknown {
+			panic(builderError{pgerror.NewErrorf(pgerror.CodeDatatypeMismatchError,
+				"could not determine type of column %d of recursive query %q",
+				i+1, tree.ErrString(&name),
+			)})
+		}
+		if !(l.Equivalent(r) || r == types.Unknown) {
+			panic(builderError{pgerror.NewErrorf(pgerror.CodeDatatypeMismatchError,
+				"recursive query %q column %d has type %s in non-recursive term but type %s overall",
+				tree.ErrString(&name), i+1, l, r,
+			)})
+		}
+	}
+
+	expr = b.factory.ConstructRecursiveCTE(
+		initialScope.expr,
+		recursiveScope.expr,
+		&memo.RecursiveCTEPrivate{
+			Name:          string(name),
+			ID:            id,
+			InitialCols:   colsToColList(initialScope.cols),
+			RecursiveCols: colsToColList(recursiveScope.cols),
+			OutCols:       outCols,
+			Deduplicate:   !clause.All,
+		},
+	)
+	return expr, outScope.cols
+}
+
+// recursiveCTEUnion returns the UNION or UNION ALL clause that defines a
+// recursive CTE, or ok=false if the statement doesn't have that form.
+func recursiveCTEUnion(stmt tree.Statement) (clause *tree.UnionClause, ok bool) {
+	sel, ok := stmt.(*tree.Select)
+	if !ok {
+		return nil, false
+	}
+	for {
+		if sel.With != nil || sel.OrderBy != nil || sel.Limit != nil {
+			return nil, false
+		}
+		paren, ok := sel.Select.(*tree.ParenSelect)
+		if !ok {
+			break
+		}
+		sel = paren.Select
+	}
+	clause, ok = sel.Select.(*tree.UnionClause)
+	if !ok || clause.Type != tree.UnionOp {
+		return nil, false
+	}
+	return clause, true
+}
+
 // checkCTEUsage ensures that a CTE that contains a mutation (like INSERT) is
 // used at least once by the query. Otherwise, it might not be executed.
 func (b *Builder) checkCTEUsage(inScope *scope) {
@@ -507,6 +750,7 @@ func (b *Builder) buildSelect(
 	orderBy := stmt.OrderBy
 	limit := stmt.Limit
 	with := stmt.With
+	locking := stmt.Locking
 
 	for s, ok := wrapped.(*tree.ParenSelect); ok; s, ok = wrapped.(*tree.ParenSelect) {
 		stmt = s.Select
@@ -537,16 +781,30 @@ func (b *Builder) buildSelect(
 			}
 			limit = stmt.Limit
 		}
+		locking = append(locking, stmt.Locking...)
 	}
+	checkLockingStrengths(locking)
+
+	// The locking clause only applies to the tables of this statement, so it
+	// must be reset when building its CTEs and subqueries.
+	defer func(prev tree.LockingClause) { b.lockingClause = prev }(b.lockingClause)
+	b.lockingClause = nil
 
 	if with != nil {
-		inScope = b.buildCTE(with.CTEList, inScope)
+		inScope = b.buildCTE(with, inScope)
 		defer b.checkCTEUsage(inScope)
 	}
 
+	if _, ok := stmt.Select.(*tree.SelectClause); !ok && len(locking) > 0 {
+		panic(builderError{sqlbase.NewLockingNotAllowedError(
+			locking[0].Strength, lockingNotAllowedWith(stmt.Select, nil /* fromScope */),
+		)})
+	}
+
 	// NB: The case statements are sorted lexicographically.
 	switch t := stmt.Select.(type) {
 	case *tree.SelectClause:
+		b.lockingClause = locking
 		outScope = b.buildSelectClause(t, orderBy, desiredTypes, inScope)
 
 	case *tree.UnionClause:
@@ -593,6 +851,7 @@ func (b *Builder) buildSelectClause(
 	sel *tree.SelectClause, orderBy tree.OrderBy, desiredTypes []types.T, inScope *scope,
 ) (outScope *scope) {
 	fromScope := b.buildFrom(sel.From, inScope)
+	b.checkLockingTargets(fromScope)
 	b.buildWhere(sel.Where, fromScope)
 
 	projectionsScope := fromScope.replace()
@@ -609,6 +868,12 @@ func (b *Builder) buildSelectClause(
 	orderByScope := b.analyzeOrderBy(orderBy, fromScope, projectionsScope)
 	distinctOnScope := b.analyzeDistinctOnArgs(sel.DistinctOn, fromScope, projectionsScope)
 
+	if len(b.lockingClause) > 0 {
+		if with := lockingNotAllowedWith(sel, fromScope); with != "" {
+			panic(builderError{sqlbase.NewLockingNotAllowedError(b.lockingClause[0].Strength, with)})
+		}
+	}
+
 	if b.needsAggregation(sel, fromScope) {
 		outScope = b.buildAggregation(
 			sel, havingExpr, fromScope, projectionsScope, orderByScope, distinctOnScope,
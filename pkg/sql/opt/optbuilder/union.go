@@ -34,7 +34,14 @@ func (b *Builder) buildUnion(
 ) (outScope *scope) {
 	leftScope := b.buildSelect(clause.Left, desiredTypes, inScope)
 	rightScope := b.buildSelect(clause.Right, desiredTypes, inScope)
+	return b.buildSetOp(clause.Type, clause.All, leftScope, rightScope, inScope)
+}
 
+// buildSetOp builds a set operator (UNION, INTERSECT or EXCEPT, with or
+// without ALL) over the already-built left and right scopes.
+func (b *Builder) buildSetOp(
+	unionType tree.UnionType, all bool, leftScope, rightScope, inScope *scope,
+) (outScope *scope) {
 	// Remove any hidden columns, as they are not included in the Union.
 	leftScope.removeHiddenCols()
 	rightScope.removeHiddenCols()
@@ -44,7 +51,7 @@ func (b *Builder) buildUnion(
 		panic(builderError{pgerror.NewErrorf(
 			pgerror.CodeSyntaxError,
 			"each %v query must have the same number of columns: %d vs %d",
-			clause.Type, len(leftScope.cols), len(rightScope.cols),
+			unionType, len(leftScope.cols), len(rightScope.cols),
 		)})
 	}
 
@@ -65,7 +72,7 @@ func (b *Builder) buildUnion(
 	//   SELECT NULL UNION SELECT 1
 	// The type of NULL is unknown, and the type of 1 is int. We need to
 	// synthesize a new column so the output column will have the correct type.
-	newColsNeeded := clause.Type == tree.UnionOp
+	newColsNeeded := unionType == tree.UnionOp
 	if newColsNeeded {
 		// Create a new scope to hold the new synthesized columns.
 		outScope = outScope.push()
@@ -81,11 +88,11 @@ func (b *Builder) buildUnion(
 		// http://www.postgresql.org/docs/9.5/static/typeconv-union-case.html.
 		if !(l.typ.Equivalent(r.typ) || l.typ == types.Unknown || r.typ == types.Unknown) {
 			panic(builderError{pgerror.NewErrorf(pgerror.CodeDatatypeMismatchError,
-				"%v types %s and %s cannot be matched", clause.Type, l.typ, r.typ)})
+				"%v types %s and %s cannot be matched", unionType, l.typ, r.typ)})
 		}
 		if l.hidden != r.hidden {
 			// This should never happen.
-			panic(fmt.Errorf("%v types cannot be matched", clause.Type))
+			panic(fmt.Errorf("%v types cannot be matched", unionType))
 		}
 
 		if newColsNeeded {
@@ -115,8 +122,8 @@ func (b *Builder) buildUnion(
 	right := rightScope.expr.(memo.RelExpr)
 	private := memo.SetPrivate{LeftCols: leftCols, RightCols: rightCols, OutCols: newCols}
 
-	if clause.All {
-		switch clause.Type {
+	if all {
+		switch unionType {
 		case tree.UnionOp:
 			outScope.expr = b.factory.ConstructUnionAll(left, right, &private)
 		case tree.IntersectOp:
@@ -125,7 +132,7 @@ func (b *Builder) buildUnion(
 			outScope.expr = b.factory.ConstructExceptAll(left, right, &private)
 		}
 	} else {
-		switch clause.Type {
+		switch unionType {
 		case tree.UnionOp:
 			outScope.expr = b.factory.ConstructUnion(left, right, &private)
 		case tree.IntersectOp:
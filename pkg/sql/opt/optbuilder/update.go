@@ -58,6 +58,18 @@ import (
 //   LEFT JOIN LATERAL (SELECT y FROM xyz WHERE x=a)
 //   ON True
 //
+// Tables in the FROM clause are joined with the target table, and their
+// columns can be referenced by the WHERE clause and the SET expressions:
+//
+//   UPDATE abc SET b=y FROM xyz WHERE a=x
+//   =>
+//   SELECT DISTINCT ON (a) a AS oa, b AS ob, c AS oc, y AS nb
+//   FROM abc, xyz
+//   WHERE a=x
+//
+// If a row of the target table matches more than one row of the FROM tables,
+// only one of them (chosen arbitrarily) is used to update it.
+//
 // Computed columns result in an additional wrapper projection that can depend
 // on input columns.
 //
@@ -81,7 +93,7 @@ func (b *Builder) buildUpdate(upd *tree.Update, inScope *scope) (outScope *scope
 	}
 
 	if upd.With != nil {
-		inScope = b.buildCTE(upd.With.CTEList, inScope)
+		inScope = b.buildCTE(upd.With, inScope)
 		defer b.checkCTEUsage(inScope)
 	}
 
@@ -102,11 +114,11 @@ func (b *Builder) buildUpdate(upd *tree.Update, inScope *scope) (outScope *scope
 	// Build the input expression that selects the rows that will be updated:
 	//
 	//   WITH <with>
-	//   SELECT <cols> FROM <table> WHERE <where>
+	//   SELECT <cols> FROM <table>, <from> WHERE <where>
 	//   ORDER BY <order-by> LIMIT <limit>
 	//
 	// All columns from the update table will be projected.
-	mb.buildInputForUpdateOrDelete(inScope, upd.Where, upd.Limit, upd.OrderBy)
+	mb.buildInputForUpdateOrDelete(inScope, upd.From, upd.Where, upd.Limit, upd.OrderBy)
 
 	// Derive the columns that will be updated from the SET expressions.
 	mb.addTargetColsForUpdate(upd.Exprs)
@@ -410,12 +410,6 @@ func (b *Builder) resolveSchemaForCreate(name *tree.TableName) cat.Schema {
 		panic(builderError{err})
 	}
 
-	// Only allow creation of objects in the public schema.
-	if name.Schema() != tree.PublicSchema {
-		panic(builderError{pgerror.NewErrorf(pgerror.CodeInvalidNameError,
-			"schema cannot be modified: %q", tree.ErrString(&name.TableNamePrefix))})
-	}
-
 	b.checkPrivilege(sch, privilege.CREATE)
 	return sch
 }
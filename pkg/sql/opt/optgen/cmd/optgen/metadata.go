@@ -157,6 +157,7 @@ func newMetadata(compiled *lang.CompiledExpr, pkg string) *metadata {
 		"TupleOrdinal":   {fullName: "memo.TupleOrdinal", passByVal: true},
 		"ScanLimit":      {fullName: "memo.ScanLimit", passByVal: true},
 		"ScanFlags":      {fullName: "memo.ScanFlags", passByVal: true},
+		"ScanLocking":    {fullName: "memo.ScanLocking", passByVal: true},
 		"ExplainOptions": {fullName: "tree.ExplainOptions", passByVal: true},
 		"ShowTraceType":  {fullName: "tree.ShowTraceType", passByVal: true},
 		"bool":           {fullName: "bool", passByVal: true},
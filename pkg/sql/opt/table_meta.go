@@ -121,6 +121,11 @@ type TableMeta struct {
 	// debugging, EXPLAIN output, etc. It is set to "" if no alias was specified.
 	Alias string
 
+	// partialIndexPredicates maps the ordinal of each partial index of the
+	// table to the filters expression built from its predicate. It is populated
+	// by the optbuilder when the table is scanned.
+	partialIndexPredicates map[int]ScalarExpr
+
 	// anns annotates the table metadata with arbitrary data.
 	anns [maxTableAnnIDCount]interface{}
 }
@@ -148,6 +153,23 @@ func (tm *TableMeta) IndexColumns(indexOrd int) ColSet {
 	return indexCols
 }
 
+// AddPartialIndexPredicate records the filters expression built from the
+// predicate of the partial index with the given ordinal.
+func (tm *TableMeta) AddPartialIndexPredicate(indexOrd int, pred ScalarExpr) {
+	if tm.partialIndexPredicates == nil {
+		tm.partialIndexPredicates = make(map[int]ScalarExpr)
+	}
+	tm.partialIndexPredicates[indexOrd] = pred
+}
+
+// PartialIndexPredicate returns the filters expression built from the
+// predicate of the partial index with the given ordinal. It returns false if
+// the index is not partial, or if its predicate has not been built.
+func (tm *TableMeta) PartialIndexPredicate(indexOrd int) (pred ScalarExpr, ok bool) {
+	pred, ok = tm.partialIndexPredicates[indexOrd]
+	return pred, ok
+}
+
 // TableAnnotation returns the given annotation that is associated with the
 // given table. If the table has no such annotation, TableAnnotation returns
 // nil.
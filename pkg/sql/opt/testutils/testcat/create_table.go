@@ -271,6 +271,11 @@ func (tt *Table) addIndex(def *tree.IndexTableDef, typ indexType) *Index {
 		table:    tt,
 	}
 
+	if def.Predicate != nil {
+		pred := tree.Serialize(def.Predicate)
+		idx.PredicateExpr = &pred
+	}
+
 	// Add explicit columns and mark primary key columns as not null.
 	notNullIndex := true
 	for _, colDef := range def.Columns {
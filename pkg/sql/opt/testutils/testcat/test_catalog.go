@@ -454,6 +454,11 @@ func (tt *Table) IsVirtualTable() bool {
 	return tt.IsVirtual
 }
 
+// IsMaterializedView is part of the cat.Table interface.
+func (tt *Table) IsMaterializedView() bool {
+	return false
+}
+
 // ColumnCount is part of the cat.Table interface.
 func (tt *Table) ColumnCount() int {
 	return len(tt.Columns) + len(tt.Mutations)
@@ -523,6 +528,10 @@ type Index struct {
 	// Inverted is true when this index is an inverted index.
 	Inverted bool
 
+	// PredicateExpr is the predicate of a partial index, or nil if the index
+	// is not partial.
+	PredicateExpr *string
+
 	Columns []cat.IndexColumn
 
 	// table is a back reference to the table this index is on.
@@ -585,6 +594,14 @@ func (ti *Index) ForeignKey() (cat.ForeignKeyReference, bool) {
 	return ti.foreignKey, ti.fkSet
 }
 
+// Predicate is part of the cat.Index interface.
+func (ti *Index) Predicate() (string, bool) {
+	if ti.PredicateExpr == nil {
+		return "", false
+	}
+	return *ti.PredicateExpr, true
+}
+
 // Column implements the cat.Column interface for testing purposes.
 type Column struct {
 	Ordinal      int
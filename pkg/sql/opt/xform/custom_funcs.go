@@ -183,15 +183,33 @@ func (c *CustomFuncs) GenerateConstrainedScans(
 	var sb indexScanBuilder
 	sb.init(c, scanPrivate.Table)
 
-	// Iterate over all indexes.
+	// Iterate over all indexes, including partial indexes.
 	var iter scanIndexIter
 	iter.init(c.e.mem, scanPrivate)
+	iter.includePartial = true
 	for iter.next() {
+		indexFilters := filters
+		isPartial := iter.isPartial()
+		if isPartial {
+			// A partial index can only be used if the filter implies its
+			// predicate.
+			var ok bool
+			indexFilters, ok = c.partialIndexFilters(filters, scanPrivate.Table, iter.indexOrdinal)
+			if !ok {
+				continue
+			}
+		}
+
 		// Check whether the filter can constrain the index.
 		constraint, remaining, ok := c.tryConstrainIndex(
-			filters, scanPrivate.Table, iter.indexOrdinal, false /* isInverted */)
+			indexFilters, scanPrivate.Table, iter.indexOrdinal, false /* isInverted */)
 		if !ok {
-			continue
+			if !isPartial {
+				continue
+			}
+			// A partial index is worth scanning even if it cannot be constrained,
+			// since it only contains the rows that satisfy its predicate.
+			constraint, remaining = nil, indexFilters
 		}
 
 		// Construct new constrained ScanPrivate.
@@ -273,9 +291,9 @@ func (c *CustomFuncs) GenerateInvertedIndexScans(
 		newScanPrivate.Index = iter.indexOrdinal
 		newScanPrivate.Constraint = constraint
 
-		// Though the index is marked as containing the JSONB column being
-		// indexed, it doesn't actually, and it's only valid to extract the
-		// primary key columns from it.
+		// Though the index is marked as containing the JSONB, array or tsvector
+		// column being indexed, it doesn't actually, and it's only valid to
+		// extract the primary key columns from it.
 		newScanPrivate.Cols = sb.primaryKeyCols()
 
 		// The Scan operator always goes in a new group, since it's always nested
@@ -288,6 +306,13 @@ func (c *CustomFuncs) GenerateInvertedIndexScans(
 		// If remaining filter exists, split it into one part that can be pushed
 		// below the IndexJoin, and one part that needs to stay above.
 		remaining = sb.addSelectAfterSplit(remaining, newScanPrivate.Cols)
+
+		// A scan of an inverted index on an array or tsvector column can find
+		// the same row under several of the constrained elements or lexemes, so
+		// the duplicates have to be removed before looking up the rows.
+		if newScanPrivate.MayReturnDuplicates(c.e.mem.Metadata()) {
+			sb.addDistinct()
+		}
 		sb.addIndexJoin(scanPrivate.Cols)
 		sb.addSelect(remaining)
 
@@ -349,6 +374,103 @@ func (c *CustomFuncs) tryConstrainIndex(
 	return &copy, remaining, true
 }
 
+// partialIndexFilters determines whether the given filter implies the
+// predicate of the given partial index, in which case every row that satisfies
+// the filter has an entry in the index. If so, it returns the part of the
+// filter that still needs to be applied to the rows of the index: conjuncts
+// that exactly match a conjunct of the predicate are removed, since they hold
+// for all of the rows in the index. Otherwise, it returns ok = false.
+//
+// The check is conservative. Each conjunct of the predicate must either match
+// a conjunct of the filter exactly, or have tight constraints that contain the
+// constraints derived from the filter. For example, the filter a > 10 implies
+// the predicate a > 0, but the filter a + 1 > 10 does not.
+func (c *CustomFuncs) partialIndexFilters(
+	filters memo.FiltersExpr, tabID opt.TableID, indexOrd int,
+) (remainingFilters memo.FiltersExpr, ok bool) {
+	pred, ok := c.e.mem.Metadata().TableMeta(tabID).PartialIndexPredicate(indexOrd)
+	if !ok {
+		return nil, false
+	}
+	predFilters := *pred.(*memo.FiltersExpr)
+
+	var exact util.FastIntSet
+	var filterConstraints *constraint.Set
+	for i := range predFilters {
+		// Scalar expressions are interned, so conjuncts that are equal have the
+		// same address.
+		found := false
+		for j := range filters {
+			if filters[j].Condition == predFilters[i].Condition {
+				exact.Add(j)
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+
+		predProps := predFilters[i].ScalarProps(c.e.mem)
+		if predProps.Constraints == nil || !predProps.TightConstraints {
+			return nil, false
+		}
+		if filterConstraints == nil {
+			filterConstraints = constraint.Unconstrained
+			for j := range filters {
+				if cs := filters[j].ScalarProps(c.e.mem).Constraints; cs != nil {
+					filterConstraints = filterConstraints.Intersect(c.e.evalCtx, cs)
+				}
+			}
+		}
+		if !c.constraintsImply(filterConstraints, predProps.Constraints) {
+			return nil, false
+		}
+	}
+
+	if exact.Empty() {
+		return filters, true
+	}
+	remainingFilters = make(memo.FiltersExpr, 0, len(filters)-exact.Len())
+	for j := range filters {
+		if !exact.Contains(j) {
+			remainingFilters = append(remainingFilters, filters[j])
+		}
+	}
+	return remainingFilters, true
+}
+
+// constraintsImply returns true if every row that satisfies the constraint set
+// "from" also satisfies the constraint set "to". For each constraint in "to",
+// it looks for a constraint over the same columns in "from" whose spans are
+// all contained in it.
+func (c *CustomFuncs) constraintsImply(from, to *constraint.Set) bool {
+	if from == constraint.Contradiction {
+		return true
+	}
+	for i := 0; i < to.Length(); i++ {
+		toCon := to.Constraint(i)
+		implied := false
+		for j := 0; j < from.Length() && !implied; j++ {
+			fromCon := from.Constraint(j)
+			if !fromCon.Columns.Equals(&toCon.Columns) {
+				continue
+			}
+			implied = true
+			for k := 0; k < fromCon.Spans.Count(); k++ {
+				if !toCon.ContainsSpan(c.e.evalCtx, fromCon.Spans.Get(k)) {
+					implied = false
+					break
+				}
+			}
+		}
+		if !implied {
+			return false
+		}
+	}
+	return true
+}
+
 // allInvIndexConstraints tries to derive all constraints for the specified inverted
 // index that can be derived. If no constraint is derived, then it returns ok = false,
 // similar to tryConstrainIndex.
@@ -662,6 +784,11 @@ func (c *CustomFuncs) GenerateLookupJoins(
 	scanPrivate *memo.ScanPrivate,
 	on memo.FiltersExpr,
 ) {
+	if scanPrivate.Locking.IsLocking() {
+		// Lookup joins do not lock the rows they read.
+		return
+	}
+
 	inputProps := input.Relational()
 
 	leftEq, rightEq := memo.ExtractJoinEqualityColumns(inputProps.OutputCols, scanPrivate.Cols, on)
@@ -1385,6 +1512,12 @@ type scanIndexIter struct {
 	indexOrdinal int
 	index        cat.Index
 	cols         opt.ColSet
+
+	// includePartial is true if next should return partial indexes. Partial
+	// indexes do not contain all the rows of the table, so they can only be
+	// used when the caller checks that the query filter implies the index
+	// predicate.
+	includePartial bool
 }
 
 func (it *scanIndexIter) init(mem *memo.Memo, scanPrivate *memo.ScanPrivate) {
@@ -1397,8 +1530,10 @@ func (it *scanIndexIter) init(mem *memo.Memo, scanPrivate *memo.ScanPrivate) {
 
 // next advances iteration to the next index of the Scan operator's table. This
 // is the primary index if it's the first time next is called, or a secondary
-// index thereafter. Inverted index are skipped. If the ForceIndex flag is set,
-// then all indexes except the forced index are skipped. When there are no more
+// index thereafter. Inverted index are skipped, and so are partial indexes
+// unless includePartial is set. If the ForceIndex flag is set, then all
+// indexes except the forced index are skipped. Locking scans only use the
+// primary index, so all the other indexes are skipped. When there are no more
 // indexes to enumerate, next returns false. The current index is accessible via
 // the iterator's "index" field.
 func (it *scanIndexIter) next() bool {
@@ -1412,6 +1547,12 @@ func (it *scanIndexIter) next() bool {
 		if it.index.IsInverted() {
 			continue
 		}
+		if !it.includePartial && it.isPartial() {
+			continue
+		}
+		if it.scanPrivate.Locking.IsLocking() && it.indexOrdinal != cat.PrimaryIndex {
+			continue
+		}
 		if it.scanPrivate.Flags.ForceIndex && it.scanPrivate.Flags.Index != it.indexOrdinal {
 			// If we are forcing a specific index, ignore the others.
 			continue
@@ -1434,7 +1575,7 @@ func (it *scanIndexIter) nextInverted() bool {
 		}
 
 		it.index = it.tab.Index(it.indexOrdinal)
-		if !it.index.IsInverted() {
+		if !it.index.IsInverted() || it.scanPrivate.Locking.IsLocking() {
 			continue
 		}
 		if it.scanPrivate.Flags.ForceIndex && it.scanPrivate.Flags.Index != it.indexOrdinal {
@@ -1446,6 +1587,12 @@ func (it *scanIndexIter) nextInverted() bool {
 	}
 }
 
+// isPartial returns true if the current index is a partial index.
+func (it *scanIndexIter) isPartial() bool {
+	_, ok := it.index.Predicate()
+	return ok
+}
+
 // indexCols returns the set of columns contained in the current index.
 func (it *scanIndexIter) indexCols() opt.ColSet {
 	if it.cols.Empty() {
@@ -47,6 +47,7 @@ type indexScanBuilder struct {
 	pkCols           opt.ColSet
 	scanPrivate      memo.ScanPrivate
 	innerFilters     memo.FiltersExpr
+	distinct         bool
 	outerFilters     memo.FiltersExpr
 	indexJoinPrivate memo.IndexJoinPrivate
 }
@@ -76,6 +77,7 @@ func (b *indexScanBuilder) primaryKeyCols() opt.ColSet {
 func (b *indexScanBuilder) setScan(scanPrivate *memo.ScanPrivate) {
 	b.scanPrivate = *scanPrivate
 	b.innerFilters = nil
+	b.distinct = false
 	b.outerFilters = nil
 	b.indexJoinPrivate = memo.IndexJoinPrivate{}
 }
@@ -129,6 +131,16 @@ func (b *indexScanBuilder) addSelectAfterSplit(
 	return b.c.ExtractUnboundConditions(filters, cols)
 }
 
+// addDistinct wraps the input expression with a DistinctOn expression that
+// removes the duplicate rows returned by the scan, grouping on the scanned
+// columns. It must be called before any index join is added.
+func (b *indexScanBuilder) addDistinct() {
+	if b.indexJoinPrivate.Table != 0 {
+		panic("cannot add distinct after index join is added")
+	}
+	b.distinct = true
+}
+
 // addIndexJoin wraps the input expression with an IndexJoin expression that
 // produces the given set of columns by lookup in the primary index.
 func (b *indexScanBuilder) addIndexJoin(cols opt.ColSet) {
@@ -148,7 +160,7 @@ func (b *indexScanBuilder) addIndexJoin(cols opt.ColSet) {
 // expressions that were specified by previous calls to various add methods.
 func (b *indexScanBuilder) build(grp memo.RelExpr) {
 	// 1. Only scan.
-	if len(b.innerFilters) == 0 && b.indexJoinPrivate.Table == 0 {
+	if len(b.innerFilters) == 0 && !b.distinct && b.indexJoinPrivate.Table == 0 {
 		b.mem.AddScanToGroup(&memo.ScanExpr{ScanPrivate: b.scanPrivate}, grp)
 		return
 	}
@@ -156,7 +168,7 @@ func (b *indexScanBuilder) build(grp memo.RelExpr) {
 	// 2. Wrap scan in inner filter if it was added.
 	input := b.f.ConstructScan(&b.scanPrivate)
 	if len(b.innerFilters) != 0 {
-		if b.indexJoinPrivate.Table == 0 {
+		if !b.distinct && b.indexJoinPrivate.Table == 0 {
 			b.mem.AddSelectToGroup(&memo.SelectExpr{Input: input, Filters: b.innerFilters}, grp)
 			return
 		}
@@ -164,7 +176,22 @@ func (b *indexScanBuilder) build(grp memo.RelExpr) {
 		input = b.f.ConstructSelect(input, b.innerFilters)
 	}
 
-	// 3. Wrap input in index join if it was added.
+	// 3. Wrap input in distinct if it was added.
+	if b.distinct {
+		private := memo.GroupingPrivate{GroupingCols: b.scanPrivate.Cols}
+		if b.indexJoinPrivate.Table == 0 {
+			b.mem.AddDistinctOnToGroup(&memo.DistinctOnExpr{
+				Input:           input,
+				Aggregations:    memo.EmptyAggregationsExpr,
+				GroupingPrivate: private,
+			}, grp)
+			return
+		}
+
+		input = b.f.ConstructDistinctOn(input, memo.EmptyAggregationsExpr, &private)
+	}
+
+	// 4. Wrap input in index join if it was added.
 	if b.indexJoinPrivate.Table != 0 {
 		if len(b.outerFilters) == 0 {
 			indexJoin := &memo.IndexJoinExpr{Input: input, IndexJoinPrivate: b.indexJoinPrivate}
@@ -175,10 +202,10 @@ func (b *indexScanBuilder) build(grp memo.RelExpr) {
 		input = b.f.ConstructIndexJoin(input, &b.indexJoinPrivate)
 	}
 
-	// 4. Wrap input in outer filter (which must exist at this point).
+	// 5. Wrap input in outer filter (which must exist at this point).
 	if len(b.outerFilters) == 0 {
-		// indexJoinDef == 0: outerFilters == 0 handled by #1 and #2 above.
-		// indexJoinDef != 0: outerFilters == 0 handled by #3 above.
+		// indexJoinDef == 0: outerFilters == 0 handled by #1, #2 and #3 above.
+		// indexJoinDef != 0: outerFilters == 0 handled by #4 above.
 		panic("outer filter cannot be 0 at this point")
 	}
 	b.mem.AddSelectToGroup(&memo.SelectExpr{Input: input, Filters: b.outerFilters}, grp)
@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/stats"
 	"github.com/cockroachdb/cockroach/pkg/util"
@@ -56,16 +57,24 @@ func (oc *optCatalog) init(statsCache *stats.TableStatisticsCache, resolver Logi
 	oc.dataSources = nil
 }
 
-// optSchema is a wrapper around sqlbase.DatabaseDescriptor that implements the
+// optSchema is a wrapper around sqlbase.DatabaseDescriptor, and around
+// sqlbase.SchemaDescriptor for user-defined schemas, that implements the
 // cat.Object and cat.Schema interfaces.
 type optSchema struct {
 	desc *sqlbase.DatabaseDescriptor
 
+	// scDesc is the descriptor of the schema if it is user-defined, and nil
+	// otherwise.
+	scDesc *sqlbase.SchemaDescriptor
+
 	name cat.SchemaName
 }
 
 // ID is part of the cat.Object interface.
 func (os *optSchema) ID() cat.StableID {
+	if os.scDesc != nil {
+		return cat.StableID(os.scDesc.ID)
+	}
 	return cat.StableID(os.desc.ID)
 }
 
@@ -100,7 +109,23 @@ func (oc *optCatalog) ResolveSchema(ctx context.Context, name *cat.SchemaName) (
 			"target database or schema does not exist")
 	}
 	*name = oc.tn.TableNamePrefix
-	return &optSchema{desc: desc.(*DatabaseDescriptor)}, nil
+	dbDesc := desc.(*DatabaseDescriptor)
+	scName := name.Schema()
+	if isVirtualSchemaName(scName) {
+		return nil, pgerror.NewErrorf(pgerror.CodeInvalidNameError,
+			"schema cannot be modified: %q", tree.ErrString(name))
+	}
+	if scName == tree.PublicSchema || sessiondata.IsTemporarySchemaName(scName) {
+		return &optSchema{desc: dbDesc}, nil
+	}
+	scDesc, err := getSchemaDesc(ctx, p.txn, dbDesc.ID, scName)
+	if err != nil {
+		return nil, err
+	}
+	if scDesc == nil {
+		return nil, sqlbase.NewUndefinedSchemaError(scName)
+	}
+	return &optSchema{desc: dbDesc, scDesc: scDesc}, nil
 }
 
 // ResolveDataSource is part of the cat.Catalog interface.
@@ -141,6 +166,9 @@ func (oc *optCatalog) ResolveDataSourceByID(
 func (oc *optCatalog) CheckPrivilege(ctx context.Context, o cat.Object, priv privilege.Kind) error {
 	switch t := o.(type) {
 	case *optSchema:
+		if t.scDesc != nil {
+			return oc.resolver.CheckPrivilege(ctx, t.scDesc, priv)
+		}
 		return oc.resolver.CheckPrivilege(ctx, t.desc, priv)
 	case *optTable:
 		return oc.resolver.CheckPrivilege(ctx, t.desc, priv)
@@ -170,7 +198,7 @@ func (oc *optCatalog) newDataSource(
 	// Create wrapper for the data source now.
 	var ds cat.DataSource
 	switch {
-	case desc.IsTable():
+	case desc.IsTable() || desc.MaterializedView():
 		stats, err := oc.statsCache.GetTableStats(context.TODO(), desc.ID)
 		if err != nil {
 			// Ignore any error. We still want to be able to run queries even if we lose
@@ -406,6 +434,11 @@ func (ot *optTable) IsVirtualTable() bool {
 	return ot.desc.IsVirtualTable()
 }
 
+// IsMaterializedView is part of the cat.Table interface.
+func (ot *optTable) IsMaterializedView() bool {
+	return ot.desc.MaterializedView()
+}
+
 // ColumnCount is part of the cat.Table interface.
 func (ot *optTable) ColumnCount() int {
 	return len(ot.desc.Columns) + len(ot.mutations)
@@ -641,6 +674,14 @@ func (oi *optIndex) ForeignKey() (cat.ForeignKeyReference, bool) {
 	return oi.foreignKey, oi.desc.ForeignKey.IsSet()
 }
 
+// Predicate is part of the cat.Index interface.
+func (oi *optIndex) Predicate() (string, bool) {
+	if !oi.desc.IsPartial() {
+		return "", false
+	}
+	return *oi.desc.Predicate, true
+}
+
 // Table is part of the cat.Index interface.
 func (oi *optIndex) Table() cat.Table {
 	return oi.tab
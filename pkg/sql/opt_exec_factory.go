@@ -23,7 +23,6 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/constraint"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
-	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -71,6 +70,8 @@ func (ef *execFactory) ConstructScan(
 	reverse bool,
 	maxResults uint64,
 	reqOrdering exec.OutputOrdering,
+	lockingStrength tree.LockingStrength,
+	lockingWaitPolicy tree.LockingWaitPolicy,
 ) (exec.Node, error) {
 	tabDesc := table.(*optTable).desc
 	indexDesc := index.(*optIndex).desc
@@ -98,6 +99,7 @@ func (ef *execFactory) ConstructScan(
 	scan.reverse = reverse
 	scan.maxResults = maxResults
 	scan.parallelScansEnabled = sqlbase.ParallelScans.Get(&ef.planner.extendedEvalCtx.Settings.SV)
+	scan.setLocking(lockingStrength, lockingWaitPolicy)
 	var err error
 	scan.spans, err = spansFromConstraint(
 		tabDesc, indexDesc, indexConstraint, cols, scan.isDeleteSource)
@@ -386,7 +388,7 @@ func (ef *execFactory) ConstructGroupBy(
 		f := n.newAggregateFuncHolder(
 			builtins.AnyNotNull,
 			inputCols[col].Typ,
-			col,
+			[]int{col},
 			builtins.NewAnyNotNullAggregate,
 			nil, /* arguments */
 			ef.planner.EvalContext().Mon.MakeBoundAccount(),
@@ -405,32 +407,20 @@ func (ef *execFactory) addAggregations(n *groupNode, aggregations []exec.AggInfo
 	for i := range aggregations {
 		agg := &aggregations[i]
 		builtin := agg.Builtin
-		var renderIdx int
-		var aggFn func(*tree.EvalContext, tree.Datums) tree.AggregateFunc
-
-		switch len(agg.ArgCols) {
-		case 0:
-			renderIdx = noRenderIdx
-			aggFn = func(evalCtx *tree.EvalContext, arguments tree.Datums) tree.AggregateFunc {
-				return builtin.AggregateFunc([]types.T{}, evalCtx, arguments)
-			}
-
-		case 1:
-			renderIdx = int(agg.ArgCols[0])
-			aggFn = func(evalCtx *tree.EvalContext, arguments tree.Datums) tree.AggregateFunc {
-				return builtin.AggregateFunc([]types.T{inputCols[renderIdx].Typ}, evalCtx, arguments)
-			}
-
-		default:
-			return pgerror.UnimplementedWithIssueError(28417,
-				"aggregate functions with multiple non-constant expressions are not supported",
-			)
+		renderIdxs := make([]int, len(agg.ArgCols))
+		argTypes := make([]types.T, len(agg.ArgCols))
+		for j, col := range agg.ArgCols {
+			renderIdxs[j] = int(col)
+			argTypes[j] = inputCols[col].Typ
+		}
+		aggFn := func(evalCtx *tree.EvalContext, arguments tree.Datums) tree.AggregateFunc {
+			return builtin.AggregateFunc(argTypes, evalCtx, arguments)
 		}
 
 		f := n.newAggregateFuncHolder(
 			agg.FuncName,
 			agg.ResultType,
-			renderIdx,
+			renderIdxs,
 			aggFn,
 			agg.ConstArgs,
 			ef.planner.EvalContext().Mon.MakeBoundAccount(),
@@ -763,6 +753,26 @@ func (ef *execFactory) ConstructProjectSet(
 	return p, nil
 }
 
+// ConstructRecursiveCTE is part of the exec.Factory interface.
+func (ef *execFactory) ConstructRecursiveCTE(
+	initial exec.Node, fn exec.RecursiveCTEIterationFn, label string, deduplicate bool,
+) (exec.Node, error) {
+	return &recursiveCTENode{
+		initial:        initial.(planNode),
+		genIterationFn: fn,
+		label:          label,
+		deduplicate:    deduplicate,
+	}, nil
+}
+
+// ConstructWorkTableScan is part of the exec.Factory interface.
+func (ef *execFactory) ConstructWorkTableScan(ref exec.Node, label string) (exec.Node, error) {
+	return &workTableScanNode{
+		ref:   ref.(*recursiveCTENode),
+		label: label,
+	}, nil
+}
+
 // ConstructPlan is part of the exec.Factory interface.
 func (ef *execFactory) ConstructPlan(
 	root exec.Node, subqueries []exec.Subquery,
@@ -892,6 +902,7 @@ func (ef *execFactory) ConstructInsert(
 	if err != nil {
 		return nil, err
 	}
+	ri.SetDeferredFKChecks(ef.planner.deferredFKChecks())
 
 	// Determine the relational type of the generated insert node.
 	// If rows are not needed, no columns are returned.
@@ -977,6 +988,7 @@ func (ef *execFactory) ConstructUpdate(
 	if err != nil {
 		return nil, err
 	}
+	ru.SetDeferredFKChecks(ef.planner.deferredFKChecks())
 
 	// Determine the relational type of the generated update node.
 	// If rows are not needed, no columns are returned.
@@ -1067,6 +1079,7 @@ func (ef *execFactory) ConstructUpsert(
 	if err != nil {
 		return nil, err
 	}
+	ri.SetDeferredFKChecks(ef.planner.deferredFKChecks())
 
 	// Create the table updater, which does the bulk of the update-related work.
 	// In the HP, the updater derives the columns that need to be fetched. By
@@ -1086,6 +1099,7 @@ func (ef *execFactory) ConstructUpsert(
 	if err != nil {
 		return nil, err
 	}
+	ru.SetDeferredFKChecks(ef.planner.deferredFKChecks())
 
 	// Determine the relational type of the generated upsert node.
 	// If rows are not needed, no columns are returned.
@@ -1117,9 +1131,10 @@ func (ef *execFactory) ConstructUpsert(
 			},
 			tw: &optTableUpserter{
 				tableUpserterBase: tableUpserterBase{
-					ri:          ri,
-					alloc:       &ef.planner.alloc,
-					collectRows: rowsNeeded,
+					ri:               ri,
+					alloc:            &ef.planner.alloc,
+					collectRows:      rowsNeeded,
+					deferredFKChecks: ef.planner.deferredFKChecks(),
 				},
 				canaryOrdinal: int(canaryCol),
 				fkTables:      fkTables,
@@ -1178,6 +1193,7 @@ func (ef *execFactory) ConstructDelete(
 	if err != nil {
 		return nil, err
 	}
+	rd.SetDeferredFKChecks(ef.planner.deferredFKChecks())
 
 	// Determine the relational type of the generated delete node.
 	// If rows are not needed, no columns are returned.
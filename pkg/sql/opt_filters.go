@@ -264,6 +264,11 @@ func (p *planner) propagateFilters(
 			return plan, extraFilter, err
 		}
 
+	case *recursiveCTENode:
+		if n.initial, err = p.triggerFilterPropagation(ctx, n.initial); err != nil {
+			return plan, extraFilter, err
+		}
+
 	case *createTableNode:
 		if n.n.As() {
 			if n.sourcePlan, err = p.triggerFilterPropagation(ctx, n.sourcePlan); err != nil {
@@ -271,6 +276,25 @@ func (p *planner) propagateFilters(
 			}
 		}
 
+	case *createViewNode:
+		if n.sourcePlan != nil {
+			if n.sourcePlan, err = p.triggerFilterPropagation(ctx, n.sourcePlan); err != nil {
+				return plan, extraFilter, err
+			}
+		}
+
+	case *refreshViewNode:
+		if n.sourcePlan != nil {
+			if n.sourcePlan, err = p.triggerFilterPropagation(ctx, n.sourcePlan); err != nil {
+				return plan, extraFilter, err
+			}
+		}
+
+	case *declareCursorNode:
+		if n.sourcePlan, err = p.triggerFilterPropagation(ctx, n.sourcePlan); err != nil {
+			return plan, extraFilter, err
+		}
+
 	case *deleteNode:
 		if n.source, err = p.triggerFilterPropagation(ctx, n.source); err != nil {
 			return plan, extraFilter, err
@@ -364,6 +388,7 @@ func (p *planner) propagateFilters(
 	case *alterIndexNode:
 	case *alterTableNode:
 	case *alterSequenceNode:
+	case *alterTypeAddValueNode:
 	case *alterUserSetPasswordNode:
 	case *renameColumnNode:
 	case *renameDatabaseNode:
@@ -377,21 +402,29 @@ func (p *planner) propagateFilters(
 	case *createDatabaseNode:
 	case *createIndexNode:
 	case *CreateUserNode:
-	case *createViewNode:
 	case *createSequenceNode:
+	case *createTypeNode:
+	case *createFunctionNode:
+	case *createSchemaNode:
 	case *createStatsNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *dropSequenceNode:
+	case *dropTypeNode:
+	case *dropFunctionNode:
+	case *dropSchemaNode:
 	case *DropUserNode:
 	case *hookFnNode:
 	case *valuesNode:
 	case *virtualTableNode:
+	case *workTableScanNode:
 	case *sequenceSelectNode:
 	case *setVarNode:
 	case *setClusterSettingNode:
+	case *setConstraintsNode:
+	case *fetchNode:
 	case *setZoneConfigNode:
 	case *showZoneConfigNode:
 	case *showFingerprintsNode:
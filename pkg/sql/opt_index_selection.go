@@ -96,6 +96,15 @@ func (p *planner) selectIndex(
 		return s, nil
 	}
 
+	if s.lockingStrength != roachpb.LOCK_NONE {
+		// Locking scans only read the primary index, which contains all the keys
+		// of the rows they lock.
+		if s.specifiedIndex != nil && s.specifiedIndex.ID != s.desc.PrimaryIndex.ID {
+			return nil, sqlbase.NewLockingSecondaryIndexError(s.specifiedIndex.Name)
+		}
+		s.specifiedIndex = &s.desc.PrimaryIndex
+	}
+
 	if s.filter == nil && analyzeOrdering == nil && s.specifiedIndex == nil {
 		// No where-clause, no ordering, and no specified index.
 		s.initOrdering(0 /* exactPrefix */, p.EvalContext())
@@ -109,6 +118,11 @@ func (p *planner) selectIndex(
 
 	candidates := make([]*indexInfo, 0, len(s.desc.Indexes)+1)
 	if s.specifiedIndex != nil {
+		if s.specifiedIndex.IsPartial() {
+			return nil, pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+				"index %q is a partial index, which is only supported by the cost-based optimizer",
+				s.specifiedIndex.Name)
+		}
 		// An explicit secondary index was requested. Only add it to the candidate
 		// indexes list.
 		candidates = append(candidates, &indexInfo{
@@ -122,6 +136,10 @@ func (p *planner) selectIndex(
 			index: &s.desc.PrimaryIndex,
 		})
 		for i := range s.desc.Indexes {
+			if s.desc.Indexes[i].IsPartial() {
+				// Partial indexes are only used by the cost-based optimizer.
+				continue
+			}
 			candidates = append(candidates, &indexInfo{
 				desc:  s.desc,
 				index: &s.desc.Indexes[i],
@@ -164,10 +182,13 @@ func (p *planner) selectIndex(
 	}
 
 	// Remove any inverted indexes that don't generate any spans, a full-scan of
-	// an inverted index is always invalid.
+	// an inverted index is always invalid. Inverted indexes that can return the
+	// same row more than once are removed too.
 	for i := 0; i < len(candidates); {
 		c := candidates[i].ic.Constraint()
-		if candidates[i].index.Type == sqlbase.IndexDescriptor_INVERTED && (c == nil || c.IsUnconstrained()) {
+		if candidates[i].index.Type == sqlbase.IndexDescriptor_INVERTED &&
+			(c == nil || c.IsUnconstrained() ||
+				invertedIndexMayReturnDuplicates(s.desc, candidates[i].index, c)) {
 			candidates[i] = candidates[len(candidates)-1]
 			candidates = candidates[:len(candidates)-1]
 		} else {
@@ -642,6 +663,17 @@ func encodeConstraintKey(
 	return key, nil
 }
 
+// invertedIndexMayReturnDuplicates returns whether a scan of the given
+// inverted index constrained by c can return the same row more than once.
+func invertedIndexMayReturnDuplicates(
+	tableDesc *sqlbase.ImmutableTableDescriptor,
+	index *sqlbase.IndexDescriptor,
+	c *constraint.Constraint,
+) bool {
+	col, err := tableDesc.FindColumnByID(index.ColumnIDs[0])
+	return err == nil && memo.InvertedIndexMayReturnDuplicates(col.Type.ToDatumType(), c)
+}
+
 // appendSpansFromConstraintSpan converts a constraint.Span to one or more
 // roachpb.Spans and appends them to the provided spans. It appends multiple
 // spans in the case that multiple, non-adjacent column families should be
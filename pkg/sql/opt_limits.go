@@ -148,6 +148,9 @@ func (p *planner) applyLimit(plan planNode, numRows int64, soft bool) {
 	case *projectSetNode:
 		p.applyLimit(n.source, numRows, true)
 
+	case *recursiveCTENode:
+		p.setUnlimited(n.initial)
+
 	case *rowCountNode:
 		p.setUnlimited(n.source)
 	case *serializeNode:
@@ -176,6 +179,16 @@ func (p *planner) applyLimit(plan planNode, numRows int64, soft bool) {
 		if n.sourcePlan != nil {
 			p.applyLimit(n.sourcePlan, numRows, soft)
 		}
+	case *createViewNode:
+		if n.sourcePlan != nil {
+			p.setUnlimited(n.sourcePlan)
+		}
+	case *refreshViewNode:
+		if n.sourcePlan != nil {
+			p.setUnlimited(n.sourcePlan)
+		}
+	case *declareCursorNode:
+		p.setUnlimited(n.sourcePlan)
 	case *explainDistSQLNode:
 		// EXPLAIN ANALYZE is special: it handles its own limit propagation, since
 		// it fully executes during startExec.
@@ -209,6 +222,7 @@ func (p *planner) applyLimit(plan planNode, numRows int64, soft bool) {
 	case *alterIndexNode:
 	case *alterTableNode:
 	case *alterSequenceNode:
+	case *alterTypeAddValueNode:
 	case *alterUserSetPasswordNode:
 	case *renameColumnNode:
 	case *renameDatabaseNode:
@@ -222,14 +236,19 @@ func (p *planner) applyLimit(plan planNode, numRows int64, soft bool) {
 	case *createDatabaseNode:
 	case *createIndexNode:
 	case *CreateUserNode:
-	case *createViewNode:
 	case *createSequenceNode:
+	case *createTypeNode:
+	case *createFunctionNode:
+	case *createSchemaNode:
 	case *createStatsNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *dropSequenceNode:
+	case *dropTypeNode:
+	case *dropFunctionNode:
+	case *dropSchemaNode:
 	case *DropUserNode:
 	case *zeroNode:
 	case *unaryNode:
@@ -237,6 +256,8 @@ func (p *planner) applyLimit(plan planNode, numRows int64, soft bool) {
 	case *sequenceSelectNode:
 	case *setVarNode:
 	case *setClusterSettingNode:
+	case *setConstraintsNode:
+	case *fetchNode:
 	case *setZoneConfigNode:
 	case *showZoneConfigNode:
 	case *showFingerprintsNode:
@@ -249,6 +270,8 @@ func (p *planner) applyLimit(plan planNode, numRows int64, soft bool) {
 	case *zigzagJoinNode:
 		// The zigzag join node is only planned by the optimizer.
 
+	case *workTableScanNode:
+
 	default:
 		panic(fmt.Sprintf("unhandled node type: %T", plan))
 	}
@@ -31,6 +31,19 @@ func setNeededColumns(plan planNode, needed []bool) {
 			setNeededColumns(n.sourcePlan, allColumns(n.sourcePlan))
 		}
 
+	case *createViewNode:
+		if n.sourcePlan != nil {
+			setNeededColumns(n.sourcePlan, allColumns(n.sourcePlan))
+		}
+
+	case *refreshViewNode:
+		if n.sourcePlan != nil {
+			setNeededColumns(n.sourcePlan, allColumns(n.sourcePlan))
+		}
+
+	case *declareCursorNode:
+		setNeededColumns(n.sourcePlan, allColumns(n.sourcePlan))
+
 	case *explainDistSQLNode:
 		setNeededColumns(n.plan, allColumns(n.plan))
 
@@ -51,6 +64,11 @@ func setNeededColumns(plan planNode, needed []bool) {
 	case *spoolNode:
 		setNeededColumns(n.source, needed)
 
+	case *recursiveCTENode:
+		// The rows of the initial query form the first working table, which
+		// is read by the recursive query; all the columns are needed.
+		setNeededColumns(n.initial, allColumns(n.initial))
+
 	case *indexJoinNode:
 		// Currently all the needed result columns are provided by the
 		// table sub-source; from the index sub-source we only need the PK
@@ -96,6 +114,8 @@ func setNeededColumns(plan planNode, needed []bool) {
 	case *virtualTableNode:
 		markOmitted(n.columns, needed)
 
+	case *workTableScanNode:
+
 	case *projectSetNode:
 		// Optimization: remove the source columns that are not needed.
 		// Be careful not to remove actual SRFs: even if the SRF is not
@@ -257,6 +277,7 @@ func setNeededColumns(plan planNode, needed []bool) {
 	case *alterIndexNode:
 	case *alterTableNode:
 	case *alterSequenceNode:
+	case *alterTypeAddValueNode:
 	case *alterUserSetPasswordNode:
 	case *renameColumnNode:
 	case *renameDatabaseNode:
@@ -270,14 +291,19 @@ func setNeededColumns(plan planNode, needed []bool) {
 	case *createDatabaseNode:
 	case *createIndexNode:
 	case *CreateUserNode:
-	case *createViewNode:
 	case *createSequenceNode:
+	case *createTypeNode:
+	case *createFunctionNode:
+	case *createSchemaNode:
 	case *createStatsNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *dropSequenceNode:
+	case *dropTypeNode:
+	case *dropFunctionNode:
+	case *dropSchemaNode:
 	case *DropUserNode:
 	case *zeroNode:
 	case *unaryNode:
@@ -285,6 +311,8 @@ func setNeededColumns(plan planNode, needed []bool) {
 	case *sequenceSelectNode:
 	case *setVarNode:
 	case *setClusterSettingNode:
+	case *setConstraintsNode:
+	case *fetchNode:
 	case *setZoneConfigNode:
 	case *showZoneConfigNode:
 	case *showFingerprintsNode:
@@ -89,7 +89,7 @@ func helpWith(sqllex sqlLexer, helpText string) int {
 // "in error", with the error set to a contextual help message about
 // the current built-in function.
 func helpWithFunction(sqllex sqlLexer, f tree.ResolvableFunctionReference) int {
-	d, err := f.Resolve(sessiondata.SearchPath{})
+	d, err := f.Resolve(sessiondata.SearchPath{}, nil /* resolver */)
 	if err != nil {
 		return 1
 	}
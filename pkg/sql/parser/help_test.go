@@ -67,6 +67,10 @@ func TestContextualHelp(t *testing.T) {
 		{`ALTER SEQUENCE blah RENAME ??`, `ALTER SEQUENCE`},
 		{`ALTER SEQUENCE blah RENAME TO blih ??`, `ALTER SEQUENCE`},
 
+		{`ALTER TYPE ??`, `ALTER TYPE`},
+		{`ALTER TYPE blah ADD ??`, `ALTER TYPE`},
+		{`ALTER TYPE blah ADD VALUE 'a' BEFORE ??`, `ALTER TYPE`},
+
 		{`ALTER USER IF ??`, `ALTER USER`},
 		{`ALTER USER foo WITH PASSWORD ??`, `ALTER USER`},
 
@@ -101,6 +105,13 @@ func TestContextualHelp(t *testing.T) {
 		{`CREATE DATABASE IF NOT ??`, `CREATE DATABASE`},
 		{`CREATE DATABASE blih ??`, `CREATE DATABASE`},
 
+		{`CREATE SCHEMA ??`, `CREATE SCHEMA`},
+		{`CREATE SCHEMA IF NOT ??`, `CREATE SCHEMA`},
+
+		{`CREATE FUNCTION ??`, `CREATE FUNCTION`},
+		{`CREATE OR REPLACE FUNCTION f(??`, `CREATE FUNCTION`},
+		{`CREATE FUNCTION f() RETURNS ??`, `CREATE FUNCTION`},
+
 		{`CREATE USER blih ??`, `CREATE USER`},
 		{`CREATE USER blih WITH ??`, `CREATE USER`},
 
@@ -110,9 +121,13 @@ func TestContextualHelp(t *testing.T) {
 		{`CREATE VIEW blah AS (SELECT c FROM x) ??`, `CREATE VIEW`},
 		{`CREATE VIEW blah AS SELECT c FROM x ??`, `SELECT`},
 		{`CREATE VIEW blah AS (??`, `<SELECTCLAUSE>`},
+		{`CREATE MATERIALIZED VIEW blah (??`, `CREATE VIEW`},
 
 		{`CREATE SEQUENCE ??`, `CREATE SEQUENCE`},
 
+		{`CREATE TYPE ??`, `CREATE TYPE`},
+		{`CREATE TYPE blah AS ENUM (??`, `CREATE TYPE`},
+
 		{`CREATE STATISTICS ??`, `CREATE STATISTICS`},
 
 		{`CREATE TABLE blah (??`, `CREATE TABLE`},
@@ -136,6 +151,12 @@ func TestContextualHelp(t *testing.T) {
 		{`DROP DATABASE IF ??`, `DROP DATABASE`},
 		{`DROP DATABASE IF EXISTS blah ??`, `DROP DATABASE`},
 
+		{`DROP SCHEMA ??`, `DROP SCHEMA`},
+
+		{`DROP FUNCTION ??`, `DROP FUNCTION`},
+		{`DROP FUNCTION IF EXISTS f(??`, `DROP FUNCTION`},
+		{`DROP SCHEMA IF EXISTS blih, bloh ??`, `DROP SCHEMA`},
+
 		{`DROP INDEX blah, ??`, `DROP INDEX`},
 		{`DROP INDEX blah@blih ??`, `DROP INDEX`},
 
@@ -147,6 +168,10 @@ func TestContextualHelp(t *testing.T) {
 		{`DROP SEQUENCE IF ??`, `DROP SEQUENCE`},
 		{`DROP SEQUENCE IF EXISTS blih, bloh ??`, `DROP SEQUENCE`},
 
+		{`DROP TYPE blah ??`, `DROP TYPE`},
+		{`DROP TYPE IF ??`, `DROP TYPE`},
+		{`DROP TYPE IF EXISTS blih, bloh ??`, `DROP TYPE`},
+
 		{`DROP TABLE blah ??`, `DROP TABLE`},
 		{`DROP TABLE IF ??`, `DROP TABLE`},
 		{`DROP TABLE IF EXISTS blih, bloh ??`, `DROP TABLE`},
@@ -154,6 +179,7 @@ func TestContextualHelp(t *testing.T) {
 		{`DROP VIEW blah ??`, `DROP VIEW`},
 		{`DROP VIEW IF ??`, `DROP VIEW`},
 		{`DROP VIEW IF EXISTS blih, bloh ??`, `DROP VIEW`},
+		{`DROP MATERIALIZED VIEW blah ??`, `DROP VIEW`},
 
 		{`DROP USER ??`, `DROP USER`},
 		{`DROP USER IF ??`, `DROP USER`},
@@ -183,6 +209,17 @@ func TestContextualHelp(t *testing.T) {
 		{`DEALLOCATE ALL ??`, `DEALLOCATE`},
 		{`DEALLOCATE PREPARE ??`, `DEALLOCATE`},
 
+		{`DECLARE ??`, `DECLARE`},
+		{`DECLARE c CURSOR ??`, `DECLARE`},
+		{`FETCH ??`, `FETCH`},
+		{`FETCH NEXT FROM ??`, `FETCH`},
+		{`MOVE ??`, `MOVE`},
+		{`CLOSE ??`, `CLOSE`},
+		{`LISTEN ??`, `LISTEN`},
+		{`UNLISTEN ??`, `UNLISTEN`},
+		{`NOTIFY ??`, `NOTIFY`},
+		{`NOTIFY c, ??`, `NOTIFY`},
+
 		{`INSERT INTO ??`, `INSERT`},
 		{`INSERT INTO blah (??`, `<SELECTCLAUSE>`},
 		{`INSERT INTO blah VALUES (1) RETURNING ??`, `INSERT`},
@@ -209,6 +246,9 @@ func TestContextualHelp(t *testing.T) {
 
 		{`PAUSE ??`, `PAUSE JOBS`},
 
+		{`REFRESH ??`, `REFRESH`},
+		{`REFRESH MATERIALIZED VIEW blah ??`, `REFRESH`},
+
 		{`RESUME ??`, `RESUME JOBS`},
 
 		{`REVOKE ALL ??`, `REVOKE`},
@@ -324,6 +364,8 @@ func TestContextualHelp(t *testing.T) {
 		{`SET SESSION blah TO 42 ??`, `SET SESSION`},
 
 		{`SET TRANSACTION ??`, `SET TRANSACTION`},
+		{`SET CONSTRAINTS ??`, `SET CONSTRAINTS`},
+		{`SET CONSTRAINTS ALL ??`, `SET CONSTRAINTS`},
 		{`SET TRANSACTION ISOLATION LEVEL SNAPSHOT ??`, `SET TRANSACTION`},
 		{`SET TIME ??`, `SET SESSION`},
 		{`SET TIME ZONE 'UTC' ??`, `SET SESSION`},
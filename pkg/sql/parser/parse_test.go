@@ -76,6 +76,16 @@ func TestParse(t *testing.T) {
 		{`CREATE DATABASE IF NOT EXISTS a LC_CTYPE = 'INVALID'`},
 		{`CREATE DATABASE IF NOT EXISTS a TEMPLATE = 'template0' ENCODING = 'UTF8' LC_COLLATE = 'C.UTF-8' LC_CTYPE = 'INVALID'`},
 
+		{`CREATE SCHEMA a`},
+		{`EXPLAIN CREATE SCHEMA a`},
+		{`CREATE SCHEMA IF NOT EXISTS a`},
+
+		{`CREATE FUNCTION f() RETURNS INT8 LANGUAGE SQL VOLATILE AS 'SELECT 1'`},
+		{`EXPLAIN CREATE FUNCTION f() RETURNS INT8 LANGUAGE SQL VOLATILE AS 'SELECT 1'`},
+		{`CREATE FUNCTION f(a INT8, STRING) RETURNS STRING LANGUAGE SQL IMMUTABLE AS 'SELECT $2 || a::STRING'`},
+		{`CREATE OR REPLACE FUNCTION a.b.f(x INT8[]) RETURNS SETOF INT8 LANGUAGE SQL STABLE AS 'SELECT unnest(x)'`},
+		{`CREATE FUNCTION f(DECIMAL(10,2)) RETURNS BOOL LANGUAGE SQL VOLATILE AS e'SELECT \'it\\\'s\' = $1::STRING'`},
+
 		{`CREATE INDEX a ON b (c)`},
 		{`EXPLAIN CREATE INDEX a ON b (c)`},
 		{`CREATE INDEX a ON b.c (d)`},
@@ -89,6 +99,9 @@ func TestParse(t *testing.T) {
 		{`CREATE UNIQUE INDEX a ON b (c) INTERLEAVE IN PARENT d (e, f)`},
 		{`CREATE UNIQUE INDEX a ON b (c) INTERLEAVE IN PARENT d.e (f, g)`},
 		{`CREATE UNIQUE INDEX a ON b.c (d)`},
+		{`CREATE INDEX a ON b (c) WHERE d > 0`},
+		{`CREATE UNIQUE INDEX a ON b (c) STORING (d) WHERE (e IS NULL) AND (f = 'x')`},
+		{`CREATE INDEX IF NOT EXISTS a ON b (c) WHERE d`},
 		{`CREATE INVERTED INDEX a ON b (c)`},
 		{`CREATE INVERTED INDEX a ON b.c (d)`},
 		{`CREATE INVERTED INDEX a ON b (c) STORING (d)`},
@@ -97,6 +110,9 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a ()`},
 		{`EXPLAIN CREATE TABLE a ()`},
 		{`CREATE TABLE a (b INT8)`},
+		{`CREATE TEMPORARY TABLE a (b INT8)`},
+		{`CREATE TEMPORARY TABLE IF NOT EXISTS a (b INT8)`},
+		{`CREATE TEMPORARY TABLE pg_temp.a (b INT8)`},
 		{`CREATE TABLE a (b INT8, c INT8)`},
 		{`CREATE TABLE a (b CHAR)`},
 		{`CREATE TABLE a (b CHAR(3))`},
@@ -110,6 +126,7 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b TIME)`},
 		{`CREATE TABLE a (b UUID)`},
 		{`CREATE TABLE a (b INET)`},
+		{`CREATE TABLE a (b TSVECTOR, c TSQUERY)`},
 		{`CREATE TABLE a (b "char")`},
 		{`CREATE TABLE a (b INT8 NULL)`},
 		{`CREATE TABLE a (b INT8 CONSTRAINT maybe NULL)`},
@@ -161,12 +178,17 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT s FOREIGN KEY (b, c) REFERENCES other (x, y) MATCH FULL ON UPDATE SET NULL)`},
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT s FOREIGN KEY (b, c) REFERENCES other (x, y) MATCH FULL ON DELETE SET DEFAULT)`},
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT s FOREIGN KEY (b, c) REFERENCES other (x, y) MATCH FULL ON DELETE SET DEFAULT ON UPDATE SET NULL)`},
+		{`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other DEFERRABLE)`},
+		{`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED)`},
+		{`CREATE TABLE a (b INT8 REFERENCES other (x) DEFERRABLE INITIALLY DEFERRED, c STRING)`},
 		{`CREATE TABLE a (b INT8, c STRING, INDEX (b, c))`},
 		{`CREATE TABLE a (b INT8, c STRING, INDEX d (b, c))`},
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT d UNIQUE (b, c))`},
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT d UNIQUE (b, c) INTERLEAVE IN PARENT d (e, f))`},
 		{`CREATE TABLE a (b INT8, UNIQUE (b))`},
 		{`CREATE TABLE a (b INT8, UNIQUE (b) STORING (c))`},
+		{`CREATE TABLE a (b INT8, INDEX (b) WHERE b > 0)`},
+		{`CREATE TABLE a (b INT8, c BOOL, UNIQUE (b) WHERE c)`},
 		{`CREATE TABLE a (b INT8, INDEX (b))`},
 		{`CREATE TABLE a (b INT8, INVERTED INDEX (b))`},
 		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo)`},
@@ -239,6 +261,7 @@ func TestParse(t *testing.T) {
 		{`ALTER INDEX a@idx PARTITION BY LIST (b) (PARTITION p1 VALUES IN (1))`},
 
 		{`CREATE TABLE a AS SELECT * FROM b`},
+		{`CREATE TEMPORARY TABLE a AS SELECT * FROM b`},
 		{`CREATE TABLE IF NOT EXISTS a AS SELECT * FROM b`},
 		{`CREATE TABLE a AS SELECT * FROM b ORDER BY c`},
 		{`CREATE TABLE IF NOT EXISTS a AS SELECT * FROM b ORDER BY c`},
@@ -258,6 +281,7 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b STRING(3)[] COLLATE "DE")`},
 
 		{`CREATE VIEW a AS SELECT * FROM b`},
+		{`CREATE TEMPORARY VIEW a AS SELECT * FROM b`},
 		{`EXPLAIN CREATE VIEW a AS SELECT * FROM b`},
 		{`CREATE VIEW a AS SELECT b.* FROM b LIMIT 5`},
 		{`CREATE VIEW a AS (SELECT c, d FROM b WHERE c > 0 ORDER BY c)`},
@@ -265,8 +289,14 @@ func TestParse(t *testing.T) {
 		{`CREATE VIEW a AS VALUES (1, 'one'), (2, 'two')`},
 		{`CREATE VIEW a (x, y) AS VALUES (1, 'one'), (2, 'two')`},
 		{`CREATE VIEW a AS TABLE b`},
+		{`CREATE MATERIALIZED VIEW a AS SELECT * FROM b`},
+		{`CREATE MATERIALIZED VIEW a (x, y) AS SELECT c, d FROM b`},
+		{`EXPLAIN CREATE MATERIALIZED VIEW a AS SELECT * FROM b`},
+		{`REFRESH MATERIALIZED VIEW a`},
+		{`REFRESH MATERIALIZED VIEW a.b`},
 
 		{`CREATE SEQUENCE a`},
+		{`CREATE TEMPORARY SEQUENCE a`},
 		{`EXPLAIN CREATE SEQUENCE a`},
 		{`CREATE SEQUENCE IF NOT EXISTS a`},
 		{`CREATE SEQUENCE a CYCLE`},
@@ -286,6 +316,12 @@ func TestParse(t *testing.T) {
 		{`CREATE SEQUENCE a INCREMENT 5 NO CYCLE NO MAXVALUE MINVALUE 1 START 3 CACHE 1`},
 		{`CREATE SEQUENCE a VIRTUAL`},
 
+		{`CREATE TYPE a AS ENUM ()`},
+		{`CREATE TYPE a AS ENUM ('b')`},
+		{`EXPLAIN CREATE TYPE a AS ENUM ('b')`},
+		{`CREATE TYPE a AS ENUM ('b', 'c', 'd e')`},
+		{`CREATE TYPE a.b AS ENUM ('c')`},
+
 		{`CREATE STATISTICS a ON col1 FROM t`},
 		{`EXPLAIN CREATE STATISTICS a ON col1 FROM t`},
 		{`CREATE STATISTICS a ON col1, col2 FROM t`},
@@ -306,12 +342,25 @@ func TestParse(t *testing.T) {
 		{`DELETE FROM a WHERE a = b ORDER BY c LIMIT d RETURNING e`},
 
 		{`DISCARD ALL`},
+		{`DISCARD TEMPORARY`},
 
 		{`DROP DATABASE a`},
 		{`EXPLAIN DROP DATABASE a`},
 		{`DROP DATABASE IF EXISTS a`},
 		{`DROP DATABASE a CASCADE`},
 		{`DROP DATABASE a RESTRICT`},
+		{`DROP SCHEMA a`},
+		{`EXPLAIN DROP SCHEMA a`},
+		{`DROP SCHEMA IF EXISTS a, b`},
+		{`DROP SCHEMA a CASCADE`},
+		{`DROP SCHEMA a RESTRICT`},
+
+		{`DROP FUNCTION f`},
+		{`EXPLAIN DROP FUNCTION f`},
+		{`DROP FUNCTION f()`},
+		{`DROP FUNCTION IF EXISTS f(INT8, a STRING), b.g`},
+		{`DROP FUNCTION f CASCADE`},
+		{`DROP FUNCTION f(INT8) RESTRICT`},
 		{`DROP TABLE a`},
 		{`EXPLAIN DROP TABLE a`},
 		{`DROP TABLE a.b`},
@@ -341,6 +390,8 @@ func TestParse(t *testing.T) {
 		{`DROP VIEW IF EXISTS a, b RESTRICT`},
 		{`DROP VIEW a.b CASCADE`},
 		{`DROP VIEW a, b CASCADE`},
+		{`DROP MATERIALIZED VIEW a`},
+		{`DROP MATERIALIZED VIEW IF EXISTS a, b CASCADE`},
 		{`DROP SEQUENCE a`},
 		{`EXPLAIN DROP SEQUENCE a`},
 		{`DROP SEQUENCE a.b`},
@@ -351,6 +402,14 @@ func TestParse(t *testing.T) {
 		{`DROP SEQUENCE a.b CASCADE`},
 		{`DROP SEQUENCE a, b CASCADE`},
 
+		{`DROP TYPE a`},
+		{`EXPLAIN DROP TYPE a`},
+		{`DROP TYPE a.b`},
+		{`DROP TYPE a, b`},
+		{`DROP TYPE IF EXISTS a`},
+		{`DROP TYPE a RESTRICT`},
+		{`DROP TYPE IF EXISTS a, b CASCADE`},
+
 		{`CANCEL JOBS SELECT a`},
 		{`EXPLAIN CANCEL JOBS SELECT a`},
 		{`CANCEL QUERIES SELECT a`},
@@ -513,6 +572,33 @@ func TestParse(t *testing.T) {
 		{`DEALLOCATE a`},
 		{`DEALLOCATE ALL`},
 
+		{`DECLARE c CURSOR FOR SELECT 1`},
+		{`DECLARE c BINARY INSENSITIVE SCROLL CURSOR WITH HOLD FOR SELECT * FROM t`},
+		{`DECLARE c NO SCROLL CURSOR FOR SELECT a FROM t ORDER BY a`},
+		{`FETCH NEXT FROM c`},
+		{`FETCH PRIOR FROM c`},
+		{`FETCH FIRST FROM c`},
+		{`FETCH LAST FROM c`},
+		{`FETCH ABSOLUTE 3 FROM c`},
+		{`FETCH ABSOLUTE -3 FROM c`},
+		{`FETCH RELATIVE 0 FROM c`},
+		{`FETCH RELATIVE -2 FROM c`},
+		{`FETCH FORWARD 5 FROM c`},
+		{`FETCH FORWARD ALL FROM c`},
+		{`FETCH BACKWARD 5 FROM c`},
+		{`FETCH BACKWARD ALL FROM c`},
+		{`MOVE NEXT FROM c`},
+		{`MOVE BACKWARD ALL FROM c`},
+		{`CLOSE c`},
+		{`CLOSE ALL`},
+
+		{`LISTEN c`},
+		{`UNLISTEN c`},
+		{`UNLISTEN *`},
+		{`NOTIFY c`},
+		{`NOTIFY c, 'hello world'`},
+		{`NOTIFY "Mixed Case", e'it\'s'`},
+
 		// Tables are the default, but can also be specified with
 		// GRANT x ON TABLE y. However, the stringer does not output TABLE.
 		{`GRANT SELECT ON TABLE foo TO root`},
@@ -522,6 +608,10 @@ func TestParse(t *testing.T) {
 		{`GRANT SELECT, INSERT ON DATABASE bar TO foo, bar, baz`},
 		{`GRANT SELECT, INSERT ON DATABASE db1, db2 TO foo, bar, baz`},
 		{`GRANT SELECT, INSERT ON DATABASE db1, db2 TO "test-user"`},
+		{`GRANT CREATE ON SCHEMA a TO foo`},
+		{`GRANT ALL ON SCHEMA a, b TO foo, bar`},
+		{`GRANT EXECUTE ON FUNCTION f TO foo`},
+		{`GRANT EXECUTE, DROP ON FUNCTION a.f, g TO foo, bar`},
 		{`GRANT rolea, roleb TO usera, userb`},
 		{`GRANT rolea, roleb TO usera, userb WITH ADMIN OPTION`},
 
@@ -533,6 +623,8 @@ func TestParse(t *testing.T) {
 		{`REVOKE ALL ON DATABASE foo FROM root, test`},
 		{`REVOKE SELECT, INSERT ON DATABASE bar FROM foo, bar, baz`},
 		{`REVOKE SELECT, INSERT ON DATABASE db1, db2 FROM foo, bar, baz`},
+		{`REVOKE CREATE ON SCHEMA a, b FROM foo`},
+		{`REVOKE EXECUTE ON FUNCTION a.b.f FROM foo`},
 		{`REVOKE rolea, roleb FROM usera, userb`},
 		{`REVOKE ADMIN OPTION FOR rolea, roleb FROM usera, userb`},
 
@@ -574,6 +666,10 @@ func TestParse(t *testing.T) {
 		{`INSERT INTO a VALUES (1) ON CONFLICT (a) DO UPDATE SET a = 1, b = excluded.a`},
 		{`INSERT INTO a VALUES (1) ON CONFLICT (a) DO UPDATE SET a = 1 WHERE b > 2`},
 		{`INSERT INTO a VALUES (1) ON CONFLICT (a) DO UPDATE SET a = DEFAULT`},
+		{`INSERT INTO a VALUES (1) ON CONFLICT (a) WHERE b > 2 DO NOTHING`},
+		{`INSERT INTO a VALUES (1) ON CONFLICT (a) WHERE b > 2 DO UPDATE SET b = 1 WHERE a > 0`},
+		{`INSERT INTO a VALUES (1) ON CONFLICT ON CONSTRAINT a_pkey DO NOTHING`},
+		{`INSERT INTO a VALUES (1) ON CONFLICT ON CONSTRAINT a_pkey DO UPDATE SET b = excluded.b`},
 		{`INSERT INTO a VALUES (1) ON CONFLICT (a) DO UPDATE SET (a, b) = (SELECT 1, 2)`},
 		{`INSERT INTO a VALUES (1) ON CONFLICT (a) DO UPDATE SET (a, b) = (SELECT 1, 2) RETURNING a, b`},
 		{`INSERT INTO a VALUES (1) ON CONFLICT (a) DO UPDATE SET (a, b) = (SELECT 1, 2) RETURNING 1, 2`},
@@ -614,6 +710,8 @@ func TestParse(t *testing.T) {
 		{`SELECT 'Deutsch' COLLATE "DE"`},
 		{`SELECT a @> b`},
 		{`SELECT a <@ b`},
+		{`SELECT a && b`},
+		{`SELECT a @@ b`},
 		{`SELECT a ? b`},
 		{`SELECT a ?| b`},
 		{`SELECT a ?& b`},
@@ -658,6 +756,7 @@ func TestParse(t *testing.T) {
 		{`SELECT INT2 'foo', 'foo'::INT2`},
 		{`SELECT INT4 'foo', 'foo'::INT4`},
 		{`SELECT INT8 'foo', 'foo'::INT8`},
+		{`SELECT 'foo'::mytype, CAST('foo' AS mytype), ANNOTATE_TYPE('foo', mytype)`},
 		{`SELECT FLOAT4 'foo', 'foo'::FLOAT4`},
 		{`SELECT DECIMAL 'foo', 'foo'::DECIMAL`},
 		{`SELECT CHAR 'foo', 'foo'::CHAR`},
@@ -671,6 +770,11 @@ func TestParse(t *testing.T) {
 		{`SELECT JSONB 'foo', 'foo'::JSONB`},
 		{`SELECT SERIAL8 'foo', 'foo'::SERIAL8`},
 
+		{`SELECT TIME(3) 'foo', 'foo'::TIME(3)`},
+		{`SELECT TIMESTAMP(0) 'foo', 'foo'::TIMESTAMP(0)`},
+		{`SELECT TIMESTAMPTZ(3) 'foo', 'foo'::TIMESTAMPTZ(3)`},
+		{`CREATE TABLE a (b TIMESTAMP(3), c TIMESTAMPTZ(6), d TIME(0))`},
+
 		{`SELECT 'foo'::DECIMAL(1)`},
 		{`SELECT 'foo'::DECIMAL(1,2)`},
 		{`SELECT 'foo'::BIT(3)`},
@@ -722,6 +826,11 @@ func TestParse(t *testing.T) {
 		{`SELECT a FROM (SELECT 1 FROM t) AS bar (bar1, bar2, bar3)`},
 		{`SELECT a FROM (SELECT 1 FROM t) WITH ORDINALITY`},
 		{`SELECT a FROM (SELECT 1 FROM t) WITH ORDINALITY AS bar`},
+
+		{`WITH a AS (SELECT 1) SELECT * FROM a`},
+		{`WITH RECURSIVE a (x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM a WHERE x < 10) SELECT * FROM a`},
+		{`WITH RECURSIVE a AS (VALUES (1) UNION SELECT * FROM a) SELECT * FROM a`},
+
 		{`SELECT a FROM ROWS FROM (a(x), b(y), c(z))`},
 		{`SELECT a FROM t1, t2`},
 		{`SELECT a FROM t AS t1`},
@@ -898,6 +1007,10 @@ func TestParse(t *testing.T) {
 		{`SELECT avg(1) FILTER (WHERE a > b)`},
 		{`SELECT avg(1) FILTER (WHERE a > b) OVER (ORDER BY c)`},
 
+		{`SELECT percentile_disc(0.5) WITHIN GROUP (ORDER BY a) FROM t`},
+		{`SELECT percentile_cont(ARRAY[0.5, 0.99]) WITHIN GROUP (ORDER BY a DESC) FROM t`},
+		{`SELECT mode() WITHIN GROUP (ORDER BY a) FILTER (WHERE a > b) FROM t`},
+
 		{`SELECT a FROM t UNION SELECT 1 FROM t`},
 		{`SELECT a FROM t UNION SELECT 1 FROM t UNION SELECT 1 FROM t`},
 		{`SELECT a FROM t UNION ALL SELECT 1 FROM t`},
@@ -928,6 +1041,17 @@ func TestParse(t *testing.T) {
 		{`SELECT DISTINCT a, b FROM t`},
 		{`SELECT DISTINCT ON (a, b) c FROM t`},
 
+		{`SELECT a FROM t FOR UPDATE`},
+		{`SELECT a FROM t FOR NO KEY UPDATE`},
+		{`SELECT a FROM t FOR SHARE`},
+		{`SELECT a FROM t FOR KEY SHARE`},
+		{`SELECT a FROM t FOR UPDATE NOWAIT`},
+		{`SELECT a FROM t FOR UPDATE SKIP LOCKED`},
+		{`SELECT a FROM t, u FOR UPDATE OF t FOR SHARE OF u, v SKIP LOCKED`},
+		{`SELECT a FROM t ORDER BY a LIMIT 1 FOR UPDATE`},
+		{`WITH a AS (SELECT 1) SELECT * FROM a, t FOR UPDATE OF t NOWAIT`},
+		{`(SELECT a FROM t) FOR UPDATE`},
+
 		{`SET a = 3`},
 		{`EXPLAIN SET a = 3`},
 		{`SET a = 3, 4`},
@@ -935,6 +1059,8 @@ func TestParse(t *testing.T) {
 		{`SET a = 3.0`},
 		{`SET a = $1`},
 		{`SET a = off`},
+		{`SET CONSTRAINTS ALL DEFERRED`},
+		{`SET CONSTRAINTS ALL IMMEDIATE`},
 		{`SET TRANSACTION READ ONLY`},
 		{`SET TRANSACTION READ WRITE`},
 		{`SET TRANSACTION ISOLATION LEVEL SERIALIZABLE`},
@@ -999,6 +1125,9 @@ func TestParse(t *testing.T) {
 		{`UPDATE a SET b = 3 WHERE a = b RETURNING a, a + b`},
 		{`UPDATE a SET b = 3 WHERE a = b RETURNING NOTHING`},
 		{`UPDATE a SET b = 3 WHERE a = b ORDER BY c LIMIT d RETURNING e`},
+		{`UPDATE a SET b = c FROM d`},
+		{`UPDATE a SET b = d.c FROM d, e WHERE (a.x = d.x) AND (d.y = e.y) RETURNING a.b`},
+		{`UPDATE a AS x SET b = y.c FROM (SELECT * FROM d) AS y WHERE x.a = y.a`},
 
 		{`UPDATE t AS "0" SET k = ''`},                 // "0" lost its quotes
 		{`SELECT * FROM "0" JOIN "0" USING (id, "0")`}, // last "0" lost its quotes.
@@ -1051,6 +1180,7 @@ func TestParse(t *testing.T) {
 		{`ALTER TABLE a ALTER COLUMN b SET DEFAULT NULL`},
 		{`ALTER TABLE a ALTER COLUMN b DROP DEFAULT`},
 		{`ALTER TABLE a ALTER COLUMN b DROP NOT NULL`},
+		{`ALTER TABLE a ALTER COLUMN b SET NOT NULL`},
 		{`ALTER TABLE a ALTER COLUMN b DROP STORED`},
 
 		{`ALTER TABLE a ALTER COLUMN b SET DATA TYPE INT8`},
@@ -1059,6 +1189,15 @@ func TestParse(t *testing.T) {
 
 		{`COPY t FROM STDIN`},
 		{`COPY t (a, b, c) FROM STDIN`},
+		{`COPY t TO STDOUT`},
+		{`COPY t (a, b) TO STDOUT`},
+		{`COPY db.t TO STDOUT WITH (format 'csv', header)`},
+		{`COPY t TO STDOUT WITH (format 'text', delimiter '|', "null" 'x')`},
+		{`COPY (SELECT * FROM t) TO STDOUT`},
+		{`COPY ((SELECT 1)) TO STDOUT`},
+		{`COPY (VALUES (1), (2)) TO STDOUT WITH (format 'binary')`},
+		{`COPY (INSERT INTO t VALUES (1) RETURNING a) TO STDOUT`},
+		{`COPY (DELETE FROM t RETURNING a) TO STDOUT`},
 
 		{`ALTER TABLE a SPLIT AT VALUES (1)`},
 		{`EXPLAIN ALTER TABLE a SPLIT AT VALUES (1)`},
@@ -1166,6 +1305,13 @@ func TestParse(t *testing.T) {
 
 		{`ALTER SEQUENCE a INCREMENT BY 5 START WITH 1000`},
 		{`EXPLAIN ALTER SEQUENCE a INCREMENT BY 5 START WITH 1000`},
+
+		{`ALTER TYPE a ADD VALUE 'b'`},
+		{`EXPLAIN ALTER TYPE a ADD VALUE 'b'`},
+		{`ALTER TYPE a.b ADD VALUE 'c'`},
+		{`ALTER TYPE a ADD VALUE IF NOT EXISTS 'b'`},
+		{`ALTER TYPE a ADD VALUE 'b' BEFORE 'c'`},
+		{`ALTER TYPE a ADD VALUE IF NOT EXISTS 'b' AFTER 'c'`},
 		{`ALTER SEQUENCE IF EXISTS a INCREMENT BY 5 START WITH 1000`},
 		{`ALTER SEQUENCE IF EXISTS a NO CYCLE CACHE 1`},
 
@@ -1275,12 +1421,30 @@ func TestParse2(t *testing.T) {
 			`CREATE DATABASE a TEMPLATE = 'template0'`},
 		{`CREATE DATABASE a TEMPLATE = invalid`,
 			`CREATE DATABASE a TEMPLATE = 'invalid'`},
+		{`CREATE TEMP TABLE a (b INT8)`,
+			`CREATE TEMPORARY TABLE a (b INT8)`},
+		{`CREATE LOCAL TEMPORARY TABLE a (b INT8)`,
+			`CREATE TEMPORARY TABLE a (b INT8)`},
+		{`CREATE GLOBAL TEMP TABLE a AS SELECT * FROM b`,
+			`CREATE TEMPORARY TABLE a AS SELECT * FROM b`},
+		{`CREATE TEMP VIEW a AS SELECT * FROM b`,
+			`CREATE TEMPORARY VIEW a AS SELECT * FROM b`},
+		{`CREATE TEMP SEQUENCE a`,
+			`CREATE TEMPORARY SEQUENCE a`},
+		{`CREATE FUNCTION f(int) RETURNS int AS 'SELECT 1' LANGUAGE sql`,
+			`CREATE FUNCTION f(INT8) RETURNS INT8 LANGUAGE SQL VOLATILE AS 'SELECT 1'`},
+		{`CREATE FUNCTION f() RETURNS int IMMUTABLE LANGUAGE 'SQL' AS 'SELECT 1'`,
+			`CREATE FUNCTION f() RETURNS INT8 LANGUAGE SQL IMMUTABLE AS 'SELECT 1'`},
+		{`DISCARD TEMP`,
+			`DISCARD TEMPORARY`},
 		{`CREATE TABLE a (b INT, UNIQUE INDEX foo (b))`,
 			`CREATE TABLE a (b INT8, CONSTRAINT foo UNIQUE (b))`},
 		{`CREATE TABLE a (b INT, UNIQUE INDEX foo (b) INTERLEAVE IN PARENT c (d))`,
 			`CREATE TABLE a (b INT8, CONSTRAINT foo UNIQUE (b) INTERLEAVE IN PARENT c (d))`},
 		{`CREATE TABLE a (UNIQUE INDEX (b) PARTITION BY LIST (c) (PARTITION d VALUES IN (1)))`,
 			`CREATE TABLE a (UNIQUE (b) PARTITION BY LIST (c) (PARTITION d VALUES IN (1)))`},
+		{`CREATE TABLE a (b INT, UNIQUE INDEX foo (b) WHERE b IS NOT NULL)`,
+			`CREATE TABLE a (b INT8, CONSTRAINT foo UNIQUE (b) WHERE b IS NOT NULL)`},
 		{`CREATE INDEX ON a (b) COVERING (c)`, `CREATE INDEX ON a (b) STORING (c)`},
 
 		{`CREATE INDEX a ON b USING GIN (c)`,
@@ -1315,6 +1479,14 @@ func TestParse2(t *testing.T) {
 
 		{`SELECT TIMESTAMP WITHOUT TIME ZONE 'foo'`, `SELECT TIMESTAMP 'foo'`},
 		{`SELECT CAST('foo' AS TIMESTAMP WITHOUT TIME ZONE)`, `SELECT CAST('foo' AS TIMESTAMP)`},
+		{`SELECT 'foo'::TIMESTAMP(3) WITH TIME ZONE`, `SELECT 'foo'::TIMESTAMPTZ(3)`},
+		{`SELECT 'foo'::TIMESTAMP(3) WITHOUT TIME ZONE`, `SELECT 'foo'::TIMESTAMP(3)`},
+		{`SELECT 'foo'::TIME(3) WITHOUT TIME ZONE`, `SELECT 'foo'::TIME(3)`},
+		// The precision is reduced to the maximum precision.
+		{`SELECT 'foo'::TIMESTAMP(123)`, `SELECT 'foo'::TIMESTAMP(6)`},
+		{`SELECT a AT TIME ZONE 'UTC'`, `SELECT timezone('UTC', a)`},
+		{`SELECT a AT TIME ZONE 'UTC' AT TIME ZONE b`, `SELECT timezone(b, timezone('UTC', a))`},
+		{`SELECT a + b AT TIME ZONE c`, `SELECT a + timezone(c, b)`},
 		{`SELECT CAST(1 AS "timestamp")`, `SELECT CAST(1 AS TIMESTAMP)`},
 		{`SELECT CAST(1 AS _int8)`, `SELECT CAST(1 AS INT8[])`},
 		{`SELECT CAST(1 AS "_int8")`, `SELECT CAST(1 AS INT8[])`},
@@ -1354,7 +1526,6 @@ func TestParse2(t *testing.T) {
 
 		{`SELECT b <<= c`, `SELECT inet_contained_by_or_equals(b, c)`},
 		{`SELECT b >>= c`, `SELECT inet_contains_or_equals(b, c)`},
-		{`SELECT b && c`, `SELECT inet_contains_or_contained_by(b, c)`},
 
 		{`SELECT NUMERIC 'foo'`, `SELECT DECIMAL 'foo'`},
 		{`SELECT REAL 'foo'`, `SELECT FLOAT4 'foo'`},
@@ -1445,6 +1616,9 @@ func TestParse2(t *testing.T) {
 			`SELECT a FROM t LIMIT 1`},
 		{`SELECT a FROM t FETCH FIRST (2 * a) ROWS ONLY`,
 			`SELECT a FROM t LIMIT 2 * a`},
+		// FOR READ ONLY is the same as no locking clause.
+		{`SELECT a FROM t FOR READ ONLY`,
+			`SELECT a FROM t`},
 		{`SELECT a FROM t OFFSET b FETCH FIRST (2 * a) ROWS ONLY`,
 			`SELECT a FROM t LIMIT 2 * a OFFSET b`},
 		{`SELECT a FROM t FETCH FIRST (2 * a) ROWS ONLY OFFSET b`,
@@ -1643,6 +1817,23 @@ func TestParse2(t *testing.T) {
 		{`DEALLOCATE PREPARE ALL`,
 			`DEALLOCATE ALL`},
 
+		{`DECLARE c SCROLL INSENSITIVE CURSOR WITHOUT HOLD FOR SELECT 1`,
+			`DECLARE c INSENSITIVE SCROLL CURSOR FOR SELECT 1`},
+		{`FETCH c`, `FETCH NEXT FROM c`},
+		{`FETCH IN c`, `FETCH NEXT FROM c`},
+		{`FETCH NEXT c`, `FETCH NEXT FROM c`},
+		{`FETCH next`, `FETCH NEXT FROM next`},
+		{`FETCH 10 FROM c`, `FETCH FORWARD 10 FROM c`},
+		{`FETCH -1 IN c`, `FETCH FORWARD -1 FROM c`},
+		{`FETCH ALL c`, `FETCH FORWARD ALL FROM c`},
+		{`FETCH FORWARD c`, `FETCH NEXT FROM c`},
+		{`FETCH BACKWARD IN c`, `FETCH PRIOR FROM c`},
+		{`FETCH ABSOLUTE 1 FROM c`, `FETCH FIRST FROM c`},
+		{`FETCH ABSOLUTE -1 FROM c`, `FETCH LAST FROM c`},
+		{`MOVE 3 c`, `MOVE FORWARD 3 FROM c`},
+		{`NOTIFY c, ''`, `NOTIFY c`},
+		{`NOTIFY c, 'it''s'`, `NOTIFY c, e'it\'s'`},
+
 		{`CANCEL JOB a`, `CANCEL JOBS VALUES (a)`},
 		{`RESUME JOB a`, `RESUME JOBS VALUES (a)`},
 		{`PAUSE JOB a`, `PAUSE JOBS VALUES (a)`},
@@ -1718,6 +1909,15 @@ func TestParse2(t *testing.T) {
 			`ALTER INDEX db.t@i CONFIGURE ZONE USING "foo.bar" = yay`},
 		{`ALTER INDEX t@i CONFIGURE ZONE USING foo.bar = yay`,
 			`ALTER INDEX t@i CONFIGURE ZONE USING "foo.bar" = yay`},
+
+		{`COPY t TO STDOUT WITH (FORMAT csv, HEADER true)`,
+			`COPY t TO STDOUT WITH (format 'csv', header 'true')`},
+		{`COPY t TO STDOUT (NULL '', DELIMITER E'\t')`,
+			`COPY t TO STDOUT WITH ("null" '', delimiter e'\t')`},
+		{`COPY t TO STDOUT WITH CSV HEADER DELIMITER AS ';' NULL AS 'n'`,
+			`COPY t TO STDOUT WITH (csv, header, delimiter ';', "null" 'n')`},
+		{`COPY t TO STDOUT BINARY`,
+			`COPY t TO STDOUT WITH (binary)`},
 		{`ALTER INDEX i CONFIGURE ZONE USING foo.bar = yay`,
 			`ALTER INDEX i CONFIGURE ZONE USING "foo.bar" = yay`},
 		{`ALTER INDEX i CONFIGURE ZONE USING foo = COPY FROM PARENT`,
@@ -1875,6 +2075,22 @@ func TestParse2(t *testing.T) {
 			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other ON UPDATE CASCADE ON DELETE SET NULL)`,
 			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other ON DELETE SET NULL ON UPDATE CASCADE)`,
 		},
+		{
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other DEFERRABLE INITIALLY IMMEDIATE)`,
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other DEFERRABLE)`,
+		},
+		{
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other INITIALLY DEFERRED)`,
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other DEFERRABLE INITIALLY DEFERRED)`,
+		},
+		{
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other INITIALLY IMMEDIATE)`,
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other)`,
+		},
+		{
+			`CREATE TABLE a (b INT8 REFERENCES other INITIALLY DEFERRED)`,
+			`CREATE TABLE a (b INT8 REFERENCES other DEFERRABLE INITIALLY DEFERRED)`,
+		},
 		{
 			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other ON UPDATE SET NULL ON DELETE SET DEFAULT)`,
 			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other ON DELETE SET DEFAULT ON UPDATE SET NULL)`,
@@ -1946,6 +2162,7 @@ func TestParse2(t *testing.T) {
 		{`ALTER TABLE a ADD b INT8 FAMILY fam_a`, `ALTER TABLE a ADD COLUMN b INT8 FAMILY fam_a`},
 		{`ALTER TABLE a DROP b`, `ALTER TABLE a DROP COLUMN b`},
 		{`ALTER TABLE a ALTER b DROP NOT NULL`, `ALTER TABLE a ALTER COLUMN b DROP NOT NULL`},
+		{`ALTER TABLE a ALTER b SET NOT NULL`, `ALTER TABLE a ALTER COLUMN b SET NOT NULL`},
 		{`ALTER TABLE a ALTER b TYPE INT8`, `ALTER TABLE a ALTER COLUMN b SET DATA TYPE INT8`},
 		{`EXPLAIN ANALYZE SELECT 1`, `EXPLAIN ANALYZE (DISTSQL) SELECT 1`},
 
@@ -2068,6 +2285,18 @@ SET TIME ZONE INTERVAL 'foobar'
 		{`SELECT INTERVAL 'foo'`, `could not parse "foo" as type interval: interval: missing unit at position 0: "foo" at or near "EOF"
 SELECT INTERVAL 'foo'
                      ^
+`},
+		{`CREATE FUNCTION f() RETURNS INT LANGUAGE SQL STABLE IMMUTABLE AS 'SELECT 1'`, `conflicting or redundant options at or near "EOF"
+CREATE FUNCTION f() RETURNS INT LANGUAGE SQL STABLE IMMUTABLE AS 'SELECT 1'
+                                                                           ^
+`},
+		{`CREATE FUNCTION f() RETURNS INT AS 'SELECT 1'`, `no language specified at or near "EOF"
+CREATE FUNCTION f() RETURNS INT AS 'SELECT 1'
+                                             ^
+`},
+		{`DECLARE c SCROLL NO SCROLL CURSOR FOR SELECT 1`, `cannot specify both SCROLL and NO SCROLL at or near "scroll"
+DECLARE c SCROLL NO SCROLL CURSOR FOR SELECT 1
+                    ^
 `},
 		{`SELECT 1 /* hello`, `unterminated comment
 SELECT 1 /* hello
@@ -2120,6 +2349,10 @@ CREATE TABLE test (
   foo INT8 FAMILY a FAMILY b
 )
 ^
+`},
+		{`CREATE TABLE test (foo INT8, CHECK (foo > 0) DEFERRABLE)`, `CHECK constraints cannot be marked DEFERRABLE at or near ")"
+CREATE TABLE test (foo INT8, CHECK (foo > 0) DEFERRABLE)
+                                                       ^
 `},
 		{`SELECT family FROM test`, `syntax error at or near "from"
 SELECT family FROM test
@@ -2177,11 +2410,6 @@ HINT: try \h SELECT`},
 SELECT 1e-
        ^
 HINT: try \h SELECT`},
-		{"SELECT foo''",
-			`type does not exist at or near ""
-SELECT foo''
-          ^
-`},
 		{
 			`SELECT 0x FROM t`,
 			`invalid hexadecimal numeric literal
@@ -2272,20 +2500,6 @@ HINT: try \h INSERT`,
 ALTER TABLE t RENAME COLUMN x TO family
                                  ^
 HINT: try \h ALTER TABLE`,
-		},
-		{
-			`SELECT CAST(1.2+2.3 AS notatype)`,
-			`type does not exist at or near "notatype"
-SELECT CAST(1.2+2.3 AS notatype)
-                       ^
-`,
-		},
-		{
-			`SELECT ANNOTATE_TYPE(1.2+2.3, notatype)`,
-			`type does not exist at or near "notatype"
-SELECT ANNOTATE_TYPE(1.2+2.3, notatype)
-                              ^
-`,
 		},
 		{
 			`CREATE USER foo WITH PASSWORD`,
@@ -2348,13 +2562,6 @@ SELECT EXISTS(SELECT 1)[1]
 			`+ ANY <array> is invalid because "+" is not a boolean operator at or near "EOF"
 SELECT 1 + ANY ARRAY[1, 2, 3]
                              ^
-`,
-		},
-		{
-			`SELECT 'f'::"blah"`,
-			`type does not exist at or near "blah"
-SELECT 'f'::"blah"
-            ^
 `,
 		},
 		// Ensure that the support for ON ROLE <namelist> doesn't leak
@@ -2722,7 +2929,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 		expected string
 	}{
 		{`ALTER TABLE a ALTER CONSTRAINT foo`, 31632, `alter constraint`},
-		{`ALTER TABLE a ALTER b SET NOT NULL`, 28751, ``},
 		{`ALTER TABLE a RENAME CONSTRAINT b TO c`, 32555, ``},
 
 		{`CREATE AGGREGATE a`, 0, `create aggregate`},
@@ -2733,14 +2939,12 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE EXTENSION a`, 0, `create extension a`},
 		{`CREATE FOREIGN DATA WRAPPER a`, 0, `create fdw`},
 		{`CREATE FOREIGN TABLE a`, 0, `create foreign table`},
-		{`CREATE FUNCTION a`, 17511, `create`},
-		{`CREATE OR REPLACE FUNCTION a`, 17511, `create`},
+		{`CREATE FUNCTION f() RETURNS INT LANGUAGE plpgsql AS 'x'`, 17511, `create function language plpgsql`},
+		{`CREATE FUNCTION f() RETURNS INT STRICT LANGUAGE SQL AS 'x'`, 17511, `strict`},
 		{`CREATE LANGUAGE a`, 17511, `create language a`},
-		{`CREATE MATERIALIZED VIEW a`, 24747, ``},
 		{`CREATE OPERATOR a`, 0, `create operator`},
 		{`CREATE PUBLICATION a`, 0, `create publication`},
 		{`CREATE RULE a`, 0, `create rule`},
-		{`CREATE SCHEMA a`, 26443, `create`},
 		{`CREATE SERVER a`, 0, `create server`},
 		{`CREATE SUBSCRIPTION a`, 0, `create subscription`},
 		{`CREATE TEXT SEARCH a`, 7821, `create text`},
@@ -2754,31 +2958,23 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`DROP EXTENSION a`, 0, `drop extension a`},
 		{`DROP FOREIGN TABLE a`, 0, `drop foreign table`},
 		{`DROP FOREIGN DATA WRAPPER a`, 0, `drop fdw`},
-		{`DROP FUNCTION a`, 17511, `drop `},
 		{`DROP LANGUAGE a`, 17511, `drop language a`},
 		{`DROP OPERATOR a`, 0, `drop operator`},
 		{`DROP PUBLICATION a`, 0, `drop publication`},
 		{`DROP RULE a`, 0, `drop rule`},
-		{`DROP SCHEMA a`, 26443, `drop`},
 		{`DROP SERVER a`, 0, `drop server`},
 		{`DROP SUBSCRIPTION a`, 0, `drop subscription`},
 		{`DROP TEXT SEARCH a`, 7821, `drop text`},
 		{`DROP TRIGGER a`, 28296, `drop`},
-		{`DROP TYPE a`, 27793, `drop type`},
 
 		{`DISCARD PLANS`, 0, `discard plans`},
 		{`DISCARD SEQUENCES`, 0, `discard sequences`},
-		{`DISCARD TEMP`, 0, `discard temp`},
-		{`DISCARD TEMPORARY`, 0, `discard temp`},
 
-		{`SET CONSTRAINTS foo`, 0, `set constraints`},
+		{`SET CONSTRAINTS foo DEFERRED`, 31632, `set constraints name`},
 		{`SET LOCAL foo = bar`, 32562, ``},
 		{`SET foo FROM CURRENT`, 0, `set from current`},
 
-		{`CREATE TEMP TABLE a(b INT8)`, 5807, ``},
 		{`CREATE UNLOGGED TABLE a(b INT8)`, 0, `create unlogged`},
-		{`CREATE TEMP VIEW a AS SELECT b`, 5807, ``},
-		{`CREATE TEMP SEQUENCE a`, 5807, ``},
 
 		{`CREATE TABLE a(LIKE b)`, 30840, ``},
 
@@ -2791,13 +2987,7 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE TABLE a(b INT8 REFERENCES c(x) MATCH PARTIAL`, 20305, `match partial`},
 		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) MATCH PARTIAL)`, 20305, `match partial`},
 
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) DEFERRABLE)`, 31632, `deferrable`},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) INITIALLY DEFERRED)`, 31632, `initially deferred`},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) INITIALLY IMMEDIATE)`, 31632, `initially immediate`},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) DEFERRABLE INITIALLY DEFERRED)`, 31632, `initially deferred`},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) DEFERRABLE INITIALLY IMMEDIATE)`, 31632, `initially immediate`},
-		{`CREATE TABLE a(b INT8, UNIQUE (b) DEFERRABLE)`, 31632, `deferrable`},
-		{`CREATE TABLE a(b INT8, CHECK (b > 0) DEFERRABLE)`, 31632, `deferrable`},
+		{`CREATE TABLE a(b INT8, UNIQUE (b) DEFERRABLE)`, 31632, `deferrable unique`},
 
 		{`CREATE SEQUENCE a AS DOUBLE PRECISION`, 25110, `FLOAT8`},
 		{`CREATE SEQUENCE a OWNED BY b`, 26382, ``},
@@ -2806,13 +2996,11 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE RECURSIVE VIEW a AS SELECT b`, 0, `create recursive view`},
 
 		{`CREATE TYPE a AS (b)`, 27792, ``},
-		{`CREATE TYPE a AS ENUM (b)`, 24873, ``},
 		{`CREATE TYPE a AS RANGE b`, 27791, ``},
 		{`CREATE TYPE a (b)`, 27793, `base`},
 		{`CREATE TYPE a`, 27793, `shell`},
 		{`CREATE DOMAIN a`, 27796, `create`},
 
-		{`CREATE INDEX a ON b(c) WHERE d > 0`, 9683, ``},
 		{`CREATE INDEX a ON b USING HASH (c)`, 0, `index using hash`},
 		{`CREATE INDEX a ON b USING GIST (c)`, 0, `index using gist`},
 		{`CREATE INDEX a ON b USING SPGIST (c)`, 0, `index using spgist`},
@@ -2823,34 +3011,20 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE INDEX a ON b(foo(c))`, 9682, ``},
 
 		{`INSERT INTO foo(a, a.b) VALUES (1,2)`, 27792, ``},
-		{`INSERT INTO foo VALUES (1,2) ON CONFLICT ON CONSTRAINT a DO NOTHING`, 28161, ``},
 
 		{`SELECT * FROM ab, LATERAL (SELECT * FROM kv)`, 24560, `select`},
 		{`SELECT * FROM ab, LATERAL foo(a)`, 24560, `srf`},
 		{`SELECT max(a ORDER BY b) FROM ab`, 23620, ``},
 
-		{`SELECT * FROM a FOR UPDATE`, 6583, ``},
 		{`SELECT * FROM ROWS FROM (a(b) AS (d))`, 0, `ROWS FROM with col_def_list`},
 
-		{`SELECT 123 AT TIME ZONE 'b'`, 32005, ``},
-
 		{`SELECT 'a'::INTERVAL SECOND`, 0, `interval with unit qualifier`},
 		{`SELECT 'a'::INTERVAL(123)`, 32564, ``},
 		{`SELECT 'a'::INTERVAL SECOND(123)`, 32564, `interval second`},
 		{`SELECT INTERVAL(3) 'a'`, 32564, ``},
 
-		{`SELECT 'a'::TIMESTAMP(123)`, 32098, ``},
-		{`SELECT 'a'::TIMESTAMP(123) WITHOUT TIME ZONE`, 32098, ``},
-		{`SELECT 'a'::TIMESTAMPTZ(123)`, 32098, ``},
-		{`SELECT 'a'::TIMESTAMP(123) WITH TIME ZONE`, 32098, ``},
-		{`SELECT TIMESTAMP(3) 'a'`, 32098, ``},
-		{`SELECT TIMESTAMPTZ(3) 'a'`, 32098, ``},
-
-		{`SELECT 'a'::TIME(123)`, 32565, ``},
-		{`SELECT 'a'::TIME(123) WITHOUT TIME ZONE`, 32565, ``},
 		{`SELECT 'a'::TIMETZ(123)`, 26097, `type with precision`},
-		{`SELECT 'a'::TIME(123) WITH TIME ZONE`, 32565, ``},
-		{`SELECT TIME(3) 'a'`, 32565, ``},
+		{`SELECT 'a'::TIME(123) WITH TIME ZONE`, 26097, `type with precision`},
 		{`SELECT TIMETZ(3) 'a'`, 26097, `type with precision`},
 
 		{`SELECT a(b) 'c'`, 0, `a(...) SCONST`},
@@ -2863,7 +3037,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`SELECT CURRENT_TIME`, 26097, `current_time`},
 		{`SELECT CURRENT_TIME()`, 26097, `current_time`},
 		{`SELECT TREAT (a AS INT8)`, 0, `treat`},
-		{`SELECT a(b) WITHIN GROUP (ORDER BY c)`, 0, `within group`},
 
 		{`CREATE TABLE a(b BOX)`, 21286, `box`},
 		{`CREATE TABLE a(b CIDR)`, 18846, `cidr`},
@@ -2877,19 +3050,12 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE TABLE a(b PG_LSN)`, 0, `pg_lsn`},
 		{`CREATE TABLE a(b POINT)`, 21286, `point`},
 		{`CREATE TABLE a(b POLYGON)`, 21286, `polygon`},
-		{`CREATE TABLE a(b TSQUERY)`, 7821, `tsquery`},
-		{`CREATE TABLE a(b TSVECTOR)`, 7821, `tsvector`},
 		{`CREATE TABLE a(b TXID_SNAPSHOT)`, 0, `txid_snapshot`},
 		{`CREATE TABLE a(b XML)`, 0, `xml`},
 		{`CREATE TABLE a(b TIMETZ)`, 26097, `type`},
 
-		{`INSERT INTO a VALUES (1) ON CONFLICT (x) WHERE x > 3 DO NOTHING`, 32557, ``},
-
-		{`WITH RECURSIVE a AS (TABLE b) SELECT c`, 21085, ``},
-
 		{`UPDATE foo SET (a, a.b) = (1, 2)`, 27792, ``},
 		{`UPDATE foo SET a.b = 1`, 27792, ``},
-		{`UPDATE foo SET x = y FROM a, b`, 7841, ``},
 		{`UPDATE Foo SET x.y = z`, 27792, ``},
 
 		{`UPSERT INTO foo(a, a.b) VALUES (1,2)`, 27792, ``},
@@ -307,6 +307,18 @@ func (u *sqlSymUnion) privilegeList() privilege.List {
 func (u *sqlSymUnion) onConflict() *tree.OnConflict {
     return u.val.(*tree.OnConflict)
 }
+func (u *sqlSymUnion) lockingClause() tree.LockingClause {
+    return u.val.(tree.LockingClause)
+}
+func (u *sqlSymUnion) lockingItem() *tree.LockingItem {
+    return u.val.(*tree.LockingItem)
+}
+func (u *sqlSymUnion) lockingStrength() tree.LockingStrength {
+    return u.val.(tree.LockingStrength)
+}
+func (u *sqlSymUnion) lockingWaitPolicy() tree.LockingWaitPolicy {
+    return u.val.(tree.LockingWaitPolicy)
+}
 func (u *sqlSymUnion) orderBy() tree.OrderBy {
     return u.val.(tree.OrderBy)
 }
@@ -364,6 +376,21 @@ func (u *sqlSymUnion) idxElems() tree.IndexElemList {
 func (u *sqlSymUnion) dropBehavior() tree.DropBehavior {
     return u.val.(tree.DropBehavior)
 }
+func (u *sqlSymUnion) funcArg() tree.FuncArg {
+    return u.val.(tree.FuncArg)
+}
+func (u *sqlSymUnion) funcArgs() tree.FuncArgs {
+    return u.val.(tree.FuncArgs)
+}
+func (u *sqlSymUnion) funcObj() tree.FuncObj {
+    return u.val.(tree.FuncObj)
+}
+func (u *sqlSymUnion) funcObjs() tree.FuncObjs {
+    return u.val.(tree.FuncObjs)
+}
+func (u *sqlSymUnion) alterTypeAddValuePlacement() *tree.AlterTypeAddValuePlacement {
+    return u.val.(*tree.AlterTypeAddValuePlacement)
+}
 func (u *sqlSymUnion) validationBehavior() tree.ValidationBehavior {
     return u.val.(tree.ValidationBehavior)
 }
@@ -430,6 +457,9 @@ func (u *sqlSymUnion) referenceAction() tree.ReferenceAction {
 func (u *sqlSymUnion) referenceActions() tree.ReferenceActions {
     return u.val.(tree.ReferenceActions)
 }
+func (u *sqlSymUnion) constraintDeferrability() tree.ConstraintDeferrability {
+    return u.val.(tree.ConstraintDeferrability)
+}
 
 func (u *sqlSymUnion) scrubOptions() tree.ScrubOptions {
     return u.val.(tree.ScrubOptions)
@@ -443,6 +473,12 @@ func (u *sqlSymUnion) resolvableFuncRefFromName() tree.ResolvableFunctionReferen
 func (u *sqlSymUnion) rowsFromExpr() *tree.RowsFromExpr {
     return u.val.(*tree.RowsFromExpr)
 }
+func (u *sqlSymUnion) declareCursor() *tree.DeclareCursor {
+    return u.val.(*tree.DeclareCursor)
+}
+func (u *sqlSymUnion) cursorStmt() tree.CursorStmt {
+    return u.val.(tree.CursorStmt)
+}
 func newNameFromStr(s string) *tree.Name {
     return (*tree.Name)(&s)
 }
@@ -465,24 +501,24 @@ func newNameFromStr(s string) *tree.Name {
 // below; search this file for "Keyword category lists".
 
 // Ordinary key words in alphabetical order.
-%token <str> ABORT ACTION ADD ADMIN AGGREGATE
+%token <str> ABORT ABSOLUTE ACTION ADD ADMIN AFTER AGGREGATE
 %token <str> ALL ALTER ANALYSE ANALYZE AND ANY ANNOTATE_TYPE ARRAY AS ASC
 %token <str> ASYMMETRIC AT
 
-%token <str> BACKUP BEGIN BETWEEN BIGINT BIGSERIAL BIT
+%token <str> BACKUP BACKWARD BEFORE BEGIN BETWEEN BIGINT BIGSERIAL BINARY BIT
 %token <str> BLOB BOOL BOOLEAN BOTH BY BYTEA BYTES
 
 %token <str> CACHE CANCEL CASCADE CASE CAST CHANGEFEED CHAR
 %token <str> CHARACTER CHARACTERISTICS CHECK
-%token <str> CLUSTER COALESCE COLLATE COLLATION COLUMN COLUMNS COMMENT COMMIT
+%token <str> CLOSE CLUSTER COALESCE COLLATE COLLATION COLUMN COLUMNS COMMENT COMMIT
 %token <str> COMMITTED COMPACT CONCAT CONFIGURATION CONFIGURATIONS CONFIGURE
 %token <str> CONFLICT CONSTRAINT CONSTRAINTS CONTAINS CONVERSION COPY COVERING CREATE
 %token <str> CROSS CUBE CURRENT CURRENT_CATALOG CURRENT_DATE CURRENT_SCHEMA
 %token <str> CURRENT_ROLE CURRENT_TIME CURRENT_TIMESTAMP
-%token <str> CURRENT_USER CYCLE
+%token <str> CURRENT_USER CURSOR CYCLE
 
 %token <str> DATA DATABASE DATABASES DATE DAY DEC DECIMAL DEFAULT
-%token <str> DEALLOCATE DEFERRABLE DEFERRED DELETE DESC
+%token <str> DEALLOCATE DECLARE DEFERRABLE DEFERRED DELETE DESC
 %token <str> DISCARD DISTINCT DO DOMAIN DOUBLE DROP
 
 %token <str> ELSE ENCODING END ENUM ESCAPE EXCEPT
@@ -493,52 +529,52 @@ func newNameFromStr(s string) *tree.Name {
 
 %token <str> FALSE FAMILY FETCH FETCHVAL FETCHTEXT FETCHVAL_PATH FETCHTEXT_PATH
 %token <str> FILES FILTER
-%token <str> FIRST FLOAT FLOAT4 FLOAT8 FLOORDIV FOLLOWING FOR FORCE_INDEX FOREIGN FROM FULL FUNCTION
+%token <str> FIRST FLOAT FLOAT4 FLOAT8 FLOORDIV FOLLOWING FOR FORCE_INDEX FOREIGN FORWARD FROM FULL FUNCTION
 
 %token <str> GLOBAL GRANT GRANTS GREATEST GROUP GROUPING GROUPS
 
-%token <str> HAVING HIGH HISTOGRAM HOUR
+%token <str> HAVING HIGH HISTOGRAM HOLD HOUR
 
-%token <str> IMMEDIATE IMPORT INCREMENT INCREMENTAL IF IFERROR IFNULL ILIKE IN ISERROR
+%token <str> IMMEDIATE IMMUTABLE IMPORT INCREMENT INCREMENTAL IF IFERROR IFNULL ILIKE IN ISERROR
 %token <str> INET INET_CONTAINED_BY_OR_EQUALS INET_CONTAINS_OR_CONTAINED_BY
 %token <str> INET_CONTAINS_OR_EQUALS INDEX INDEXES INJECT INTERLEAVE INITIALLY
-%token <str> INNER INSERT INT INT2VECTOR INT2 INT4 INT8 INT64 INTEGER
+%token <str> INNER INSENSITIVE INSERT INT INT2VECTOR INT2 INT4 INT8 INT64 INTEGER
 %token <str> INTERSECT INTERVAL INTO INVERTED IS ISNULL ISOLATION
 
 %token <str> JOB JOBS JOIN JSON JSONB JSON_SOME_EXISTS JSON_ALL_EXISTS
 
 %token <str> KEY KEYS KV
 
-%token <str> LANGUAGE LATERAL LC_CTYPE LC_COLLATE
-%token <str> LEADING LEASE LEAST LEFT LESS LEVEL LIKE LIMIT LIST LOCAL
-%token <str> LOCALTIME LOCALTIMESTAMP LOW LSHIFT
+%token <str> LANGUAGE LAST LATERAL LC_CTYPE LC_COLLATE
+%token <str> LEADING LEASE LEAST LEFT LESS LEVEL LIKE LIMIT LIST LISTEN LOCAL
+%token <str> LOCALTIME LOCALTIMESTAMP LOCKED LOW LSHIFT
 
-%token <str> MATCH MATERIALIZED MINVALUE MAXVALUE MINUTE MONTH
+%token <str> MATCH MATERIALIZED MINVALUE MAXVALUE MINUTE MONTH MOVE
 
 %token <str> NAN NAME NAMES NATURAL NEXT NO NO_INDEX_JOIN NORMAL
-%token <str> NOT NOTHING NOTNULL NULL NULLIF NUMERIC
+%token <str> NOT NOTHING NOTIFY NOTNULL NOWAIT NULL NULLIF NUMERIC
 
 %token <str> OF OFF OFFSET OID OIDS OIDVECTOR ON ONLY OPTION OPTIONS OR
 %token <str> ORDER ORDINALITY OUT OUTER OVER OVERLAPS OVERLAY OWNED OPERATOR
 
 %token <str> PARENT PARTIAL PARTITION PASSWORD PAUSE PHYSICAL PLACING
-%token <str> PLANS POSITION PRECEDING PRECISION PREPARE PRIMARY PRIORITY
+%token <str> PLANS POSITION PRECEDING PRECISION PREPARE PRIMARY PRIOR PRIORITY
 %token <str> PROCEDURAL PUBLICATION
 
 %token <str> QUERIES QUERY
 
-%token <str> RANGE RANGES READ REAL RECURSIVE REF REFERENCES
+%token <str> RANGE RANGES READ REAL RECURSIVE REF REFERENCES REFRESH
 %token <str> REGCLASS REGPROC REGPROCEDURE REGNAMESPACE REGTYPE
 %token <str> REMOVE_PATH RENAME REPEATABLE REPLACE
-%token <str> RELEASE RESET RESTORE RESTRICT RESUME RETURNING REVOKE RIGHT
+%token <str> RELATIVE RELEASE RESET RESTORE RESTRICT RESUME RETURNING RETURNS REVOKE RIGHT
 %token <str> ROLE ROLES ROLLBACK ROLLUP ROW ROWS RSHIFT RULE
 
-%token <str> SAVEPOINT SCATTER SCHEMA SCHEMAS SCRUB SEARCH SECOND SELECT SEQUENCE SEQUENCES
+%token <str> SAVEPOINT SCATTER SCHEMA SCHEMAS SCROLL SCRUB SEARCH SECOND SELECT SEQUENCE SEQUENCES
 %token <str> SERIAL SERIAL2 SERIAL4 SERIAL8
-%token <str> SERIALIZABLE SERVER SESSION SESSIONS SESSION_USER SET SETTING SETTINGS
-%token <str> SHOW SIMILAR SIMPLE SMALLINT SMALLSERIAL SNAPSHOT SOME SPLIT SQL
+%token <str> SERIALIZABLE SERVER SESSION SESSIONS SESSION_USER SET SETOF SETTING SETTINGS
+%token <str> SHARE SHOW SIMILAR SIMPLE SKIP SMALLINT SMALLSERIAL SNAPSHOT SOME SPLIT SQL
 
-%token <str> START STATISTICS STATUS STDIN STRICT STRING STORE STORED STORING SUBSTRING
+%token <str> STABLE START STATISTICS STATUS STDIN STDOUT STRICT STRING STORE STORED STORING SUBSTRING
 %token <str> SYMMETRIC SYNTAX SYSTEM SUBSCRIPTION
 
 %token <str> TABLE TABLES TEMP TEMPLATE TEMPORARY TESTING_RANGES EXPERIMENTAL_RANGES TESTING_RELOCATE EXPERIMENTAL_RELOCATE TEXT THEN
@@ -546,10 +582,10 @@ func newNameFromStr(s string) *tree.Name {
 %token <str> TRUNCATE TRUSTED TYPE
 %token <str> TRACING
 
-%token <str> UNBOUNDED UNCOMMITTED UNION UNIQUE UNKNOWN UNLOGGED
+%token <str> UNBOUNDED UNCOMMITTED UNION UNIQUE UNKNOWN UNLISTEN UNLOGGED
 %token <str> UPDATE UPSERT USE USER USERS USING UUID
 
-%token <str> VALID VALIDATE VALUE VALUES VARBIT VARCHAR VARIADIC VIEW VARYING VIRTUAL
+%token <str> VALID VALIDATE VALUE VALUES VARBIT VARCHAR VARIADIC VIEW VARYING VIRTUAL VOLATILE
 
 %token <str> WHEN WHERE WINDOW WITH WITHIN WITHOUT WORK WRITE
 
@@ -583,6 +619,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Statement> alter_index_stmt
 %type <tree.Statement> alter_view_stmt
 %type <tree.Statement> alter_sequence_stmt
+%type <tree.Statement> alter_type_stmt
 %type <tree.Statement> alter_database_stmt
 %type <tree.Statement> alter_user_stmt
 %type <tree.Statement> alter_range_stmt
@@ -641,6 +678,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Statement> comment_stmt
 %type <tree.Statement> commit_stmt
 %type <tree.Statement> copy_from_stmt
+%type <tree.Statement> copy_to_stmt copy_to_query
 
 %type <tree.Statement> create_stmt
 %type <tree.Statement> create_changefeed_stmt
@@ -655,6 +693,8 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Statement> create_sequence_stmt
 %type <tree.Statement> create_stats_stmt
 %type <tree.Statement> create_type_stmt
+%type <tree.Statement> create_schema_stmt
+%type <tree.Statement> create_function_stmt
 %type <tree.Statement> delete_stmt
 %type <tree.Statement> discard_stmt
 
@@ -667,6 +707,9 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Statement> drop_user_stmt
 %type <tree.Statement> drop_view_stmt
 %type <tree.Statement> drop_sequence_stmt
+%type <tree.Statement> drop_type_stmt
+%type <tree.Statement> drop_schema_stmt
+%type <tree.Statement> drop_function_stmt
 
 %type <tree.Statement> explain_stmt
 %type <tree.Statement> prepare_stmt
@@ -674,10 +717,18 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Statement> export_stmt
 %type <tree.Statement> execute_stmt
 %type <tree.Statement> deallocate_stmt
+%type <tree.Statement> declare_cursor_stmt
+%type <tree.Statement> fetch_cursor_stmt
+%type <tree.Statement> move_cursor_stmt
+%type <tree.Statement> close_cursor_stmt
+%type <tree.Statement> listen_stmt
+%type <tree.Statement> unlisten_stmt
+%type <tree.Statement> notify_stmt
 %type <tree.Statement> grant_stmt
 %type <tree.Statement> insert_stmt
 %type <tree.Statement> import_stmt
 %type <tree.Statement> pause_stmt
+%type <tree.Statement> refresh_stmt
 %type <tree.Statement> release_stmt
 %type <tree.Statement> reset_stmt reset_session_stmt reset_csetting_stmt
 %type <tree.Statement> resume_stmt
@@ -692,6 +743,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Statement> set_session_stmt
 %type <tree.Statement> set_csetting_stmt
 %type <tree.Statement> set_transaction_stmt
+%type <tree.Statement> set_constraints_stmt
 %type <tree.Statement> set_exprs_internal
 %type <tree.Statement> generic_set
 %type <tree.Statement> set_rest_more
@@ -733,8 +785,13 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Statement> use_stmt
 
 %type <[]string> opt_incremental
+%type <[]string> opt_enum_val_list enum_val_list
+%type <*tree.AlterTypeAddValuePlacement> opt_add_val_placement
 %type <tree.KVOption> kv_option
 %type <[]tree.KVOption> kv_option_list opt_with_options var_set_list
+%type <tree.KVOption> copy_to_option copy_to_generic_option
+%type <[]tree.KVOption> opt_copy_to_options copy_to_option_list copy_to_generic_option_list
+%type <str> copy_to_generic_option_arg
 %type <str> import_format
 
 %type <*tree.Select> select_no_parens
@@ -769,9 +826,9 @@ func newNameFromStr(s string) *tree.Name {
 %type <str> opt_collate
 
 %type <str> database_name index_name opt_index_name column_name insert_column_item statistics_name window_name
-%type <str> family_name opt_family_name table_alias_name constraint_name target_name zone_name partition_name collation_name
+%type <str> family_name opt_family_name table_alias_name cursor_name constraint_name target_name zone_name partition_name collation_name
 %type <str> db_object_name_component
-%type <*tree.UnresolvedName> table_name sequence_name type_name view_name db_object_name simple_db_object_name complex_db_object_name
+%type <*tree.UnresolvedName> table_name sequence_name type_name function_name view_name db_object_name simple_db_object_name complex_db_object_name
 %type <*tree.UnresolvedName> table_pattern complex_table_pattern
 %type <*tree.UnresolvedName> column_path prefixed_column_path column_path_with_star
 %type <tree.TableExpr> insert_target create_stats_target
@@ -801,8 +858,8 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.IndexElemList> index_params
 %type <tree.NameList> name_list privilege_list
 %type <[]int32> opt_array_bounds
-%type <*tree.From> from_clause update_from_clause
-%type <tree.TableExprs> from_list rowsfrom_list
+%type <*tree.From> from_clause
+%type <tree.TableExprs> from_list rowsfrom_list update_from_clause
 %type <tree.TablePatterns> table_pattern_list single_table_pattern_list
 %type <tree.TableNames> table_name_list
 %type <tree.Exprs> expr_list opt_expr_list tuple1_ambiguous_values tuple1_unambiguous_values
@@ -814,6 +871,11 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.ArraySubscripts> array_subscripts
 %type <tree.GroupBy> group_clause
 %type <*tree.Limit> select_limit
+%type <tree.LockingClause> opt_for_locking_clause for_locking_clause for_locking_items
+%type <*tree.LockingItem> for_locking_item
+%type <tree.LockingStrength> for_locking_strength
+%type <tree.TableNames> opt_locked_rels
+%type <tree.LockingWaitPolicy> opt_nowait_or_skip
 %type <tree.TableNames> relation_expr_list
 %type <tree.ReturningClause> returning_clause
 
@@ -835,7 +897,14 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.DurationField> opt_interval interval_second interval_qualifier
 %type <tree.Expr> overlay_placing
 
-%type <bool> opt_unique
+%type <bool> opt_unique opt_or_replace opt_setof
+%type <tree.FuncArg> func_arg
+%type <tree.FuncArgs> opt_func_arg_list func_arg_list
+%type <tree.FuncObj> func_obj
+%type <tree.FuncObjs> func_obj_list
+%type <[]tree.KVOption> create_func_opt_list
+%type <tree.KVOption> create_func_opt_item
+%type <bool> opt_temp
 %type <bool> opt_using_gin_btree
 
 %type <*tree.Limit> limit_clause offset_clause opt_limit_clause
@@ -845,8 +914,8 @@ func newNameFromStr(s string) *tree.Name {
 %type <empty> first_or_next
 
 %type <tree.Statement> insert_rest
-%type <tree.NameList> opt_conf_expr opt_col_def_list
-%type <*tree.OnConflict> on_conflict
+%type <tree.NameList> opt_col_def_list
+%type <*tree.OnConflict> on_conflict opt_conf_expr
 
 %type <tree.Statement> begin_transaction
 %type <tree.TransactionModes> transaction_mode_list transaction_mode
@@ -854,7 +923,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.NameList> opt_storing
 %type <*tree.ColumnTableDef> column_def
 %type <tree.TableDef> table_elem
-%type <tree.Expr> where_clause opt_where_clause
+%type <tree.Expr> where_clause opt_where_clause opt_idx_where
 %type <*tree.ArraySubscript> array_subscript
 %type <tree.Expr> opt_slice_bound
 %type <*tree.IndexFlags> opt_index_flags
@@ -907,6 +976,9 @@ func newNameFromStr(s string) *tree.Name {
 
 %type <*tree.NumVal> signed_iconst
 %type <int64> signed_iconst64
+%type <*tree.DeclareCursor> cursor_options
+%type <bool> opt_hold
+%type <tree.CursorStmt> fetch_args
 %type <int64> iconst64
 %type <tree.Expr> var_value
 %type <tree.Exprs> var_list
@@ -928,6 +1000,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.NamedColumnQualification> col_qualification
 %type <tree.ColumnQualification> col_qualification_elem
 %type <tree.CompositeKeyMatchMethod> key_match
+%type <tree.ConstraintDeferrability> opt_deferrable
 %type <tree.ReferenceActions> reference_actions
 %type <tree.ReferenceAction> reference_action reference_on_delete reference_on_update
 
@@ -938,7 +1011,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <[]*tree.CTE> cte_list
 %type <*tree.CTE> common_table_expr
 
-%type <empty> within_group_clause
+%type <tree.OrderBy> within_group_clause
 %type <tree.Expr> filter_clause
 %type <tree.Exprs> opt_partition_clause
 %type <tree.Window> window_clause window_definition_list
@@ -971,7 +1044,7 @@ func newNameFromStr(s string) *tree.Name {
 %left      AND
 %right     NOT
 %nonassoc  IS ISNULL NOTNULL   // IS sets precedence for IS NULL, etc
-%nonassoc  '<' '>' '=' LESS_EQUALS GREATER_EQUALS NOT_EQUALS CONTAINS CONTAINED_BY '?' JSON_SOME_EXISTS JSON_ALL_EXISTS
+%nonassoc  '<' '>' '=' LESS_EQUALS GREATER_EQUALS NOT_EQUALS CONTAINS CONTAINED_BY '?' JSON_SOME_EXISTS JSON_ALL_EXISTS TEXTSEARCH_MATCH
 %nonassoc  '~' BETWEEN IN LIKE ILIKE SIMILAR NOT_REGMATCH REGIMATCH NOT_REGIMATCH NOT_LA
 %nonassoc  ESCAPE              // ESCAPE must be just above LIKE/ILIKE/SIMILAR
 %nonassoc  OVERLAPS
@@ -1038,9 +1111,17 @@ stmt:
   HELPTOKEN { return helpWith(sqllex, "") }
 | preparable_stmt  // help texts in sub-rule
 | copy_from_stmt
+| copy_to_stmt
 | comment_stmt
 | execute_stmt      // EXTEND WITH HELP: EXECUTE
 | deallocate_stmt   // EXTEND WITH HELP: DEALLOCATE
+| declare_cursor_stmt // EXTEND WITH HELP: DECLARE
+| fetch_cursor_stmt // EXTEND WITH HELP: FETCH
+| move_cursor_stmt  // EXTEND WITH HELP: MOVE
+| close_cursor_stmt // EXTEND WITH HELP: CLOSE
+| listen_stmt       // EXTEND WITH HELP: LISTEN
+| unlisten_stmt     // EXTEND WITH HELP: UNLISTEN
+| notify_stmt       // EXTEND WITH HELP: NOTIFY
 | discard_stmt      // EXTEND WITH HELP: DISCARD
 | export_stmt       // EXTEND WITH HELP: EXPORT
 | grant_stmt        // EXTEND WITH HELP: GRANT
@@ -1070,6 +1151,7 @@ alter_ddl_stmt:
 | alter_sequence_stmt // EXTEND WITH HELP: ALTER SEQUENCE
 | alter_database_stmt // EXTEND WITH HELP: ALTER DATABASE
 | alter_range_stmt    // EXTEND WITH HELP: ALTER RANGE
+| alter_type_stmt     // EXTEND WITH HELP: ALTER TYPE
 
 // %Help: ALTER TABLE - change the definition of a table
 // %Category: DDL
@@ -1082,7 +1164,7 @@ alter_ddl_stmt:
 //   ALTER TABLE ... DROP [COLUMN] [IF EXISTS] <colname> [RESTRICT | CASCADE]
 //   ALTER TABLE ... DROP CONSTRAINT [IF EXISTS] <constraintname> [RESTRICT | CASCADE]
 //   ALTER TABLE ... ALTER [COLUMN] <colname> {SET DEFAULT <expr> | DROP DEFAULT}
-//   ALTER TABLE ... ALTER [COLUMN] <colname> DROP NOT NULL
+//   ALTER TABLE ... ALTER [COLUMN] <colname> {SET NOT NULL | DROP NOT NULL}
 //   ALTER TABLE ... ALTER [COLUMN] <colname> DROP STORED
 //   ALTER TABLE ... ALTER [COLUMN] <colname> [SET DATA] TYPE <type> [COLLATE <collation>]
 //   ALTER TABLE ... RENAME TO <newname>
@@ -1169,6 +1251,51 @@ alter_sequence_options_stmt:
     $$.val = &tree.AlterSequence{Name: name, Options: $6.seqOpts(), IfExists: true}
   }
 
+// %Help: ALTER TYPE - change the definition of a type
+// %Category: DDL
+// %Text:
+// ALTER TYPE <typename> ADD VALUE [IF NOT EXISTS] <label> [{BEFORE | AFTER} <label>]
+// %SeeAlso: CREATE TYPE, DROP TYPE
+alter_type_stmt:
+  ALTER TYPE type_name ADD VALUE SCONST opt_add_val_placement
+  {
+    name, err := tree.NormalizeTableName($3.unresolvedName())
+    if err != nil {
+      sqllex.Error(err.Error())
+      return 1
+    }
+    $$.val = &tree.AlterTypeAddValue{Name: name, NewVal: $6, Placement: $7.alterTypeAddValuePlacement()}
+  }
+| ALTER TYPE type_name ADD VALUE IF NOT EXISTS SCONST opt_add_val_placement
+  {
+    name, err := tree.NormalizeTableName($3.unresolvedName())
+    if err != nil {
+      sqllex.Error(err.Error())
+      return 1
+    }
+    $$.val = &tree.AlterTypeAddValue{
+      Name: name,
+      IfNotExists: true,
+      NewVal: $9,
+      Placement: $10.alterTypeAddValuePlacement(),
+    }
+  }
+| ALTER TYPE error // SHOW HELP: ALTER TYPE
+
+opt_add_val_placement:
+  BEFORE SCONST
+  {
+    $$.val = &tree.AlterTypeAddValuePlacement{Before: true, ExistingVal: $2}
+  }
+| AFTER SCONST
+  {
+    $$.val = &tree.AlterTypeAddValuePlacement{Before: false, ExistingVal: $2}
+  }
+| /* EMPTY */
+  {
+    $$.val = (*tree.AlterTypeAddValuePlacement)(nil)
+  }
+
 // %Help: ALTER USER - change user properties
 // %Category: Priv
 // %Text:
@@ -1491,7 +1618,10 @@ alter_table_cmd:
     $$.val = &tree.AlterTableDropStored{Column: tree.Name($3)}
   }
   // ALTER TABLE <name> ALTER [COLUMN] <colname> SET NOT NULL
-| ALTER opt_column column_name SET NOT NULL { return unimplementedWithIssue(sqllex, 28751) }
+| ALTER opt_column column_name SET NOT NULL
+  {
+    $$.val = &tree.AlterTableSetNotNull{Column: tree.Name($3)}
+  }
   // ALTER TABLE <name> DROP [COLUMN] IF EXISTS <colname> [RESTRICT|CASCADE]
 | DROP opt_column IF EXISTS column_name opt_drop_behavior
   {
@@ -1897,6 +2027,109 @@ copy_from_stmt:
     }
   }
 
+copy_to_stmt:
+  COPY table_name opt_column_list TO STDOUT opt_copy_to_options
+  {
+    name, err := tree.NormalizeTableName($2.unresolvedName())
+    if err != nil {
+      sqllex.Error(err.Error())
+      return 1
+    }
+    $$.val = &tree.CopyTo{
+       Table: name,
+       Columns: $3.nameList(),
+       Options: $6.kvOptions(),
+    }
+  }
+| COPY '(' copy_to_query ')' TO STDOUT opt_copy_to_options
+  {
+    $$.val = &tree.CopyTo{Statement: $3.stmt(), Options: $7.kvOptions()}
+  }
+
+copy_to_query:
+  select_stmt
+  {
+    $$.val = $1.slct()
+  }
+| insert_stmt
+| upsert_stmt
+| update_stmt
+| delete_stmt
+
+// The options can be specified either as a parenthesized, comma-separated
+// list, or using the space-separated syntax of Postgres versions before 9.0.
+opt_copy_to_options:
+  opt_with '(' copy_to_generic_option_list ')'
+  {
+    $$.val = $3.kvOptions()
+  }
+| opt_with copy_to_option_list
+  {
+    $$.val = $2.kvOptions()
+  }
+| /* EMPTY */
+  {
+    $$.val = []tree.KVOption(nil)
+  }
+
+copy_to_option_list:
+  copy_to_option
+  {
+    $$.val = []tree.KVOption{$1.kvOption()}
+  }
+| copy_to_option_list copy_to_option
+  {
+    $$.val = append($1.kvOptions(), $2.kvOption())
+  }
+
+copy_to_option:
+  name
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1)}
+  }
+| name SCONST
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1), Value: tree.NewStrVal($2)}
+  }
+| name AS SCONST
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1), Value: tree.NewStrVal($3)}
+  }
+| NULL SCONST
+  {
+    $$.val = tree.KVOption{Key: tree.Name("null"), Value: tree.NewStrVal($2)}
+  }
+| NULL AS SCONST
+  {
+    $$.val = tree.KVOption{Key: tree.Name("null"), Value: tree.NewStrVal($3)}
+  }
+
+copy_to_generic_option_list:
+  copy_to_generic_option
+  {
+    $$.val = []tree.KVOption{$1.kvOption()}
+  }
+| copy_to_generic_option_list ',' copy_to_generic_option
+  {
+    $$.val = append($1.kvOptions(), $3.kvOption())
+  }
+
+copy_to_generic_option:
+  unrestricted_name
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1)}
+  }
+| unrestricted_name copy_to_generic_option_arg
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1), Value: tree.NewStrVal($2)}
+  }
+
+copy_to_generic_option_arg:
+  non_reserved_word_or_sconst
+| TRUE  { $$ = "true" }
+| FALSE { $$ = "false" }
+| ON    { $$ = "on" }
+
 // %Help: CANCEL
 // %Category: Group
 // %Text: CANCEL JOBS, CANCEL QUERIES, CANCEL SESSIONS
@@ -2071,22 +2304,18 @@ create_unsupported:
 | CREATE EXTENSION name error { return unimplemented(sqllex, "create extension " + $3) }
 | CREATE FOREIGN TABLE error { return unimplemented(sqllex, "create foreign table") }
 | CREATE FOREIGN DATA error { return unimplemented(sqllex, "create fdw") }
-| CREATE FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "create function") }
-| CREATE OR REPLACE FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "create function") }
 | CREATE opt_or_replace opt_trusted opt_procedural LANGUAGE name error { return unimplementedWithIssueDetail(sqllex, 17511, "create language " + $6) }
-| CREATE MATERIALIZED VIEW error { return unimplementedWithIssue(sqllex, 24747) }
 | CREATE OPERATOR error { return unimplemented(sqllex, "create operator") }
 | CREATE PUBLICATION error { return unimplemented(sqllex, "create publication") }
 | CREATE opt_or_replace RULE error { return unimplemented(sqllex, "create rule") }
-| CREATE SCHEMA error { return unimplementedWithIssueDetail(sqllex, 26443, "create") }
 | CREATE SERVER error { return unimplemented(sqllex, "create server") }
 | CREATE SUBSCRIPTION error { return unimplemented(sqllex, "create subscription") }
 | CREATE TEXT error { return unimplementedWithIssueDetail(sqllex, 7821, "create text") }
 | CREATE TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "create") }
 
 opt_or_replace:
-  OR REPLACE {}
-| /* EMPTY */ {}
+  OR REPLACE { $$.val = true }
+| /* EMPTY */ { $$.val = false }
 
 opt_trusted:
   TRUSTED {}
@@ -2106,27 +2335,26 @@ drop_unsupported:
 | DROP EXTENSION name error { return unimplemented(sqllex, "drop extension " + $3) }
 | DROP FOREIGN TABLE error { return unimplemented(sqllex, "drop foreign table") }
 | DROP FOREIGN DATA error { return unimplemented(sqllex, "drop fdw") }
-| DROP FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "drop function") }
 | DROP opt_procedural LANGUAGE name error { return unimplementedWithIssueDetail(sqllex, 17511, "drop language " + $4) }
 | DROP OPERATOR error { return unimplemented(sqllex, "drop operator") }
 | DROP PUBLICATION error { return unimplemented(sqllex, "drop publication") }
 | DROP RULE error { return unimplemented(sqllex, "drop rule") }
-| DROP SCHEMA error { return unimplementedWithIssueDetail(sqllex, 26443, "drop") }
 | DROP SERVER error { return unimplemented(sqllex, "drop server") }
 | DROP SUBSCRIPTION error { return unimplemented(sqllex, "drop subscription") }
 | DROP TEXT error { return unimplementedWithIssueDetail(sqllex, 7821, "drop text") }
-| DROP TYPE error { return unimplementedWithIssueDetail(sqllex, 27793, "drop type") }
 | DROP TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "drop") }
 
 create_ddl_stmt:
   create_changefeed_stmt
 | create_database_stmt // EXTEND WITH HELP: CREATE DATABASE
+| create_function_stmt // EXTEND WITH HELP: CREATE FUNCTION
 | create_index_stmt    // EXTEND WITH HELP: CREATE INDEX
+| create_schema_stmt   // EXTEND WITH HELP: CREATE SCHEMA
 | create_table_stmt    // EXTEND WITH HELP: CREATE TABLE
 | create_table_as_stmt // EXTEND WITH HELP: CREATE TABLE
 // Error case for both CREATE TABLE and CREATE TABLE ... AS in one
 | CREATE opt_temp TABLE error   // SHOW HELP: CREATE TABLE
-| create_type_stmt     { /* SKIP DOC */ }
+| create_type_stmt     // EXTEND WITH HELP: CREATE TYPE
 | create_view_stmt     // EXTEND WITH HELP: CREATE VIEW
 | create_sequence_stmt // EXTEND WITH HELP: CREATE SEQUENCE
 
@@ -2241,7 +2469,7 @@ delete_stmt:
 
 // %Help: DISCARD - reset the session to its initial state
 // %Category: Cfg
-// %Text: DISCARD ALL
+// %Text: DISCARD ALL | DISCARD TEMP
 discard_stmt:
   DISCARD ALL
   {
@@ -2249,8 +2477,14 @@ discard_stmt:
   }
 | DISCARD PLANS { return unimplemented(sqllex, "discard plans") }
 | DISCARD SEQUENCES { return unimplemented(sqllex, "discard sequences") }
-| DISCARD TEMP { return unimplemented(sqllex, "discard temp") }
-| DISCARD TEMPORARY { return unimplemented(sqllex, "discard temp") }
+| DISCARD TEMP
+  {
+    $$.val = &tree.Discard{Mode: tree.DiscardModeTemp}
+  }
+| DISCARD TEMPORARY
+  {
+    $$.val = &tree.Discard{Mode: tree.DiscardModeTemp}
+  }
 | DISCARD error // SHOW HELP: DISCARD
 
 // %Help: DROP
@@ -2267,14 +2501,17 @@ drop_stmt:
 
 drop_ddl_stmt:
   drop_database_stmt // EXTEND WITH HELP: DROP DATABASE
+| drop_function_stmt // EXTEND WITH HELP: DROP FUNCTION
 | drop_index_stmt    // EXTEND WITH HELP: DROP INDEX
+| drop_schema_stmt   // EXTEND WITH HELP: DROP SCHEMA
 | drop_table_stmt    // EXTEND WITH HELP: DROP TABLE
 | drop_view_stmt     // EXTEND WITH HELP: DROP VIEW
 | drop_sequence_stmt // EXTEND WITH HELP: DROP SEQUENCE
+| drop_type_stmt     // EXTEND WITH HELP: DROP TYPE
 
 // %Help: DROP VIEW - remove a view
 // %Category: DDL
-// %Text: DROP VIEW [IF EXISTS] <tablename> [, ...] [CASCADE | RESTRICT]
+// %Text: DROP [MATERIALIZED] VIEW [IF EXISTS] <tablename> [, ...] [CASCADE | RESTRICT]
 // %SeeAlso: WEBDOCS/drop-index.html
 drop_view_stmt:
   DROP VIEW table_name_list opt_drop_behavior
@@ -2285,6 +2522,25 @@ drop_view_stmt:
   {
     $$.val = &tree.DropView{Names: $5.tableNames(), IfExists: true, DropBehavior: $6.dropBehavior()}
   }
+| DROP MATERIALIZED VIEW table_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropView{
+      Names: $4.tableNames(),
+      IfExists: false,
+      DropBehavior: $5.dropBehavior(),
+      IsMaterialized: true,
+    }
+  }
+| DROP MATERIALIZED VIEW IF EXISTS table_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropView{
+      Names: $6.tableNames(),
+      IfExists: true,
+      DropBehavior: $7.dropBehavior(),
+      IsMaterialized: true,
+    }
+  }
+| DROP MATERIALIZED VIEW error // SHOW HELP: DROP VIEW
 | DROP VIEW error // SHOW HELP: DROP VIEW
 
 // %Help: DROP SEQUENCE - remove a sequence
@@ -2302,6 +2558,21 @@ drop_sequence_stmt:
   }
 | DROP SEQUENCE error // SHOW HELP: DROP VIEW
 
+// %Help: DROP TYPE - remove a type
+// %Category: DDL
+// %Text: DROP TYPE [IF EXISTS] <typename> [, ...] [CASCADE | RESTRICT]
+// %SeeAlso: CREATE TYPE, ALTER TYPE
+drop_type_stmt:
+  DROP TYPE table_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropType{Names: $3.tableNames(), IfExists: false, DropBehavior: $4.dropBehavior()}
+  }
+| DROP TYPE IF EXISTS table_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropType{Names: $5.tableNames(), IfExists: true, DropBehavior: $6.dropBehavior()}
+  }
+| DROP TYPE error // SHOW HELP: DROP TYPE
+
 // %Help: DROP TABLE - remove a table
 // %Category: DDL
 // %Text: DROP TABLE [IF EXISTS] <tablename> [, ...] [CASCADE | RESTRICT]
@@ -2363,6 +2634,74 @@ drop_database_stmt:
   }
 | DROP DATABASE error // SHOW HELP: DROP DATABASE
 
+// %Help: DROP SCHEMA - remove a schema
+// %Category: DDL
+// %Text: DROP SCHEMA [IF EXISTS] <schemaname> [, ...] [CASCADE | RESTRICT]
+// %SeeAlso: CREATE SCHEMA
+drop_schema_stmt:
+  DROP SCHEMA name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropSchema{
+      Names: $3.nameList(),
+      IfExists: false,
+      DropBehavior: $4.dropBehavior(),
+    }
+  }
+| DROP SCHEMA IF EXISTS name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropSchema{
+      Names: $5.nameList(),
+      IfExists: true,
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP SCHEMA error // SHOW HELP: DROP SCHEMA
+
+// %Help: DROP FUNCTION - remove a user-defined function
+// %Category: DDL
+// %Text: DROP FUNCTION [IF EXISTS] <name> [( [[<argname>] <argtype> [, ...]] )] [, ...] [CASCADE | RESTRICT]
+// %SeeAlso: CREATE FUNCTION
+drop_function_stmt:
+  DROP FUNCTION func_obj_list opt_drop_behavior
+  {
+    $$.val = &tree.DropFunction{Functions: $3.funcObjs(), IfExists: false, DropBehavior: $4.dropBehavior()}
+  }
+| DROP FUNCTION IF EXISTS func_obj_list opt_drop_behavior
+  {
+    $$.val = &tree.DropFunction{Functions: $5.funcObjs(), IfExists: true, DropBehavior: $6.dropBehavior()}
+  }
+| DROP FUNCTION error // SHOW HELP: DROP FUNCTION
+
+func_obj_list:
+  func_obj
+  {
+    $$.val = tree.FuncObjs{$1.funcObj()}
+  }
+| func_obj_list ',' func_obj
+  {
+    $$.val = append($1.funcObjs(), $3.funcObj())
+  }
+
+func_obj:
+  function_name
+  {
+    name, err := tree.NormalizeTableName($1.unresolvedName())
+    if err != nil {
+      sqllex.Error(err.Error())
+      return 1
+    }
+    $$.val = tree.FuncObj{Name: name}
+  }
+| function_name '(' opt_func_arg_list ')'
+  {
+    name, err := tree.NormalizeTableName($1.unresolvedName())
+    if err != nil {
+      sqllex.Error(err.Error())
+      return 1
+    }
+    $$.val = tree.FuncObj{Name: name, Args: $3.funcArgs(), HasArgs: true}
+  }
+
 // %Help: DROP USER - remove a user
 // %Category: Priv
 // %Text: DROP USER [IF EXISTS] <user> [, ...]
@@ -2464,6 +2803,7 @@ preparable_stmt:
 | import_stmt       // EXTEND WITH HELP: IMPORT
 | insert_stmt       // EXTEND WITH HELP: INSERT
 | pause_stmt        // EXTEND WITH HELP: PAUSE JOBS
+| refresh_stmt      // EXTEND WITH HELP: REFRESH
 | reset_stmt        // help texts in sub-rule
 | restore_stmt      // EXTEND WITH HELP: RESTORE
 | resume_stmt       // EXTEND WITH HELP: RESUME JOBS
@@ -2560,6 +2900,237 @@ deallocate_stmt:
   }
 | DEALLOCATE error // SHOW HELP: DEALLOCATE
 
+// %Help: DECLARE - define a cursor
+// %Category: Misc
+// %Text:
+// DECLARE <name> [BINARY] [INSENSITIVE] [[NO] SCROLL]
+//     CURSOR [{WITH | WITHOUT} HOLD] FOR <selectclause>
+// %SeeAlso: FETCH, MOVE, CLOSE
+declare_cursor_stmt:
+  DECLARE cursor_name cursor_options CURSOR opt_hold FOR select_stmt
+  {
+    n := $3.declareCursor()
+    n.Name = tree.Name($2)
+    n.Hold = $5.bool()
+    n.Select = $7.slct()
+    $$.val = n
+  }
+| DECLARE error // SHOW HELP: DECLARE
+
+cursor_options:
+  /* EMPTY */
+  {
+    $$.val = &tree.DeclareCursor{}
+  }
+| cursor_options BINARY
+  {
+    n := $1.declareCursor()
+    n.Binary = true
+    $$.val = n
+  }
+| cursor_options INSENSITIVE
+  {
+    n := $1.declareCursor()
+    n.Insensitive = true
+    $$.val = n
+  }
+| cursor_options SCROLL
+  {
+    n := $1.declareCursor()
+    if n.Scroll == tree.NoScroll {
+      sqllex.Error("cannot specify both SCROLL and NO SCROLL")
+      return 1
+    }
+    n.Scroll = tree.Scroll
+    $$.val = n
+  }
+| cursor_options NO SCROLL
+  {
+    n := $1.declareCursor()
+    if n.Scroll == tree.Scroll {
+      sqllex.Error("cannot specify both SCROLL and NO SCROLL")
+      return 1
+    }
+    n.Scroll = tree.NoScroll
+    $$.val = n
+  }
+
+opt_hold:
+  WITH HOLD
+  {
+    $$.val = true
+  }
+| WITHOUT HOLD
+  {
+    $$.val = false
+  }
+| /* EMPTY */
+  {
+    $$.val = false
+  }
+
+// %Help: FETCH - retrieve rows from a query using a cursor
+// %Category: Misc
+// %Text:
+// FETCH [<direction> [FROM | IN]] <name>
+//
+// Directions:
+//   NEXT, PRIOR, FIRST, LAST, ABSOLUTE <count>, RELATIVE <count>,
+//   <count>, ALL, FORWARD [<count> | ALL], BACKWARD [<count> | ALL]
+// %SeeAlso: DECLARE, MOVE, CLOSE
+fetch_cursor_stmt:
+  FETCH fetch_args
+  {
+    $$.val = &tree.FetchCursor{CursorStmt: $2.cursorStmt()}
+  }
+| FETCH error // SHOW HELP: FETCH
+
+// %Help: MOVE - position a cursor
+// %Category: Misc
+// %Text:
+// MOVE [<direction> [FROM | IN]] <name>
+//
+// Directions:
+//   NEXT, PRIOR, FIRST, LAST, ABSOLUTE <count>, RELATIVE <count>,
+//   <count>, ALL, FORWARD [<count> | ALL], BACKWARD [<count> | ALL]
+// %SeeAlso: DECLARE, FETCH, CLOSE
+move_cursor_stmt:
+  MOVE fetch_args
+  {
+    $$.val = &tree.MoveCursor{CursorStmt: $2.cursorStmt()}
+  }
+| MOVE error // SHOW HELP: MOVE
+
+fetch_args:
+  cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($1), Direction: tree.FetchForward, Count: 1}
+  }
+| from_in cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($2), Direction: tree.FetchForward, Count: 1}
+  }
+| NEXT opt_from_in cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($3), Direction: tree.FetchForward, Count: 1}
+  }
+| PRIOR opt_from_in cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($3), Direction: tree.FetchBackward, Count: 1}
+  }
+| FIRST opt_from_in cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($3), Direction: tree.FetchAbsolute, Count: 1}
+  }
+| LAST opt_from_in cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($3), Direction: tree.FetchAbsolute, Count: -1}
+  }
+| ABSOLUTE signed_iconst64 opt_from_in cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($4), Direction: tree.FetchAbsolute, Count: $2.int64()}
+  }
+| RELATIVE signed_iconst64 opt_from_in cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($4), Direction: tree.FetchRelative, Count: $2.int64()}
+  }
+| signed_iconst64 opt_from_in cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($3), Direction: tree.FetchForward, Count: $1.int64()}
+  }
+| ALL opt_from_in cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($3), Direction: tree.FetchForward, All: true}
+  }
+| FORWARD opt_from_in cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($3), Direction: tree.FetchForward, Count: 1}
+  }
+| FORWARD signed_iconst64 opt_from_in cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($4), Direction: tree.FetchForward, Count: $2.int64()}
+  }
+| FORWARD ALL opt_from_in cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($4), Direction: tree.FetchForward, All: true}
+  }
+| BACKWARD opt_from_in cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($3), Direction: tree.FetchBackward, Count: 1}
+  }
+| BACKWARD signed_iconst64 opt_from_in cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($4), Direction: tree.FetchBackward, Count: $2.int64()}
+  }
+| BACKWARD ALL opt_from_in cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($4), Direction: tree.FetchBackward, All: true}
+  }
+
+from_in:
+  FROM {}
+| IN {}
+
+opt_from_in:
+  from_in {}
+| /* EMPTY */ {}
+
+// %Help: CLOSE - close a cursor
+// %Category: Misc
+// %Text: CLOSE { <name> | ALL }
+// %SeeAlso: DECLARE, FETCH, MOVE
+close_cursor_stmt:
+  CLOSE cursor_name
+  {
+    $$.val = &tree.CloseCursor{Name: tree.Name($2)}
+  }
+| CLOSE ALL
+  {
+    $$.val = &tree.CloseCursor{}
+  }
+| CLOSE error // SHOW HELP: CLOSE
+
+// %Help: LISTEN - listen for notifications on a channel
+// %Category: Misc
+// %Text: LISTEN <channel>
+// %SeeAlso: UNLISTEN, NOTIFY
+listen_stmt:
+  LISTEN name
+  {
+    $$.val = &tree.Listen{Channel: tree.Name($2)}
+  }
+| LISTEN error // SHOW HELP: LISTEN
+
+// %Help: UNLISTEN - stop listening for notifications
+// %Category: Misc
+// %Text: UNLISTEN { <channel> | * }
+// %SeeAlso: LISTEN, NOTIFY
+unlisten_stmt:
+  UNLISTEN name
+  {
+    $$.val = &tree.Unlisten{Channel: tree.Name($2)}
+  }
+| UNLISTEN '*'
+  {
+    $$.val = &tree.Unlisten{}
+  }
+| UNLISTEN error // SHOW HELP: UNLISTEN
+
+// %Help: NOTIFY - generate a notification
+// %Category: Misc
+// %Text: NOTIFY <channel> [, <payload>]
+// %SeeAlso: LISTEN, UNLISTEN
+notify_stmt:
+  NOTIFY name
+  {
+    $$.val = &tree.Notify{Channel: tree.Name($2)}
+  }
+| NOTIFY name ',' SCONST
+  {
+    $$.val = &tree.Notify{Channel: tree.Name($2), Payload: $4}
+  }
+| NOTIFY error // SHOW HELP: NOTIFY
+
 // %Help: GRANT - define access privileges and role memberships
 // %Category: Priv
 // %Text:
@@ -2569,10 +3140,12 @@ deallocate_stmt:
 //   GRANT <roles...> TO <grantees...> [WITH ADMIN OPTION]
 //
 // Privileges:
-//   CREATE, DROP, GRANT, SELECT, INSERT, DELETE, UPDATE
+//   CREATE, DROP, GRANT, SELECT, INSERT, DELETE, UPDATE, EXECUTE
 //
 // Targets:
 //   DATABASE <databasename> [, ...]
+//   SCHEMA <schemaname> [, ...]
+//   FUNCTION <functionname> [, ...]
 //   [TABLE] [<databasename> .] { <tablename> | * } [, ...]
 //
 // %SeeAlso: REVOKE, WEBDOCS/grant.html
@@ -2581,6 +3154,22 @@ grant_stmt:
   {
     $$.val = &tree.Grant{Privileges: $2.privilegeList(), Grantees: $6.nameList(), Targets: $4.targetList()}
   }
+| GRANT privileges ON SCHEMA name_list TO name_list
+  {
+    $$.val = &tree.Grant{
+      Privileges: $2.privilegeList(),
+      Grantees: $7.nameList(),
+      Targets: tree.TargetList{Schemas: $5.nameList()},
+    }
+  }
+| GRANT privileges ON FUNCTION table_name_list TO name_list
+  {
+    $$.val = &tree.Grant{
+      Privileges: $2.privilegeList(),
+      Grantees: $7.nameList(),
+      Targets: tree.TargetList{Functions: $5.tableNames()},
+    }
+  }
 | GRANT privilege_list TO name_list
   {
     $$.val = &tree.GrantRole{Roles: $2.nameList(), Members: $4.nameList(), AdminOption: false}
@@ -2600,10 +3189,12 @@ grant_stmt:
 //   REVOKE [ADMIN OPTION FOR] <roles...> FROM <grantees...>
 //
 // Privileges:
-//   CREATE, DROP, GRANT, SELECT, INSERT, DELETE, UPDATE
+//   CREATE, DROP, GRANT, SELECT, INSERT, DELETE, UPDATE, EXECUTE
 //
 // Targets:
 //   DATABASE <databasename> [, <databasename>]...
+//   SCHEMA <schemaname> [, <schemaname>]...
+//   FUNCTION <functionname> [, <functionname>]...
 //   [TABLE] [<databasename> .] { <tablename> | * } [, ...]
 //
 // %SeeAlso: GRANT, WEBDOCS/revoke.html
@@ -2612,6 +3203,22 @@ revoke_stmt:
   {
     $$.val = &tree.Revoke{Privileges: $2.privilegeList(), Grantees: $6.nameList(), Targets: $4.targetList()}
   }
+| REVOKE privileges ON SCHEMA name_list FROM name_list
+  {
+    $$.val = &tree.Revoke{
+      Privileges: $2.privilegeList(),
+      Grantees: $7.nameList(),
+      Targets: tree.TargetList{Schemas: $5.nameList()},
+    }
+  }
+| REVOKE privileges ON FUNCTION table_name_list FROM name_list
+  {
+    $$.val = &tree.Revoke{
+      Privileges: $2.privilegeList(),
+      Grantees: $7.nameList(),
+      Targets: tree.TargetList{Functions: $5.tableNames()},
+    }
+  }
 | REVOKE privilege_list FROM name_list
   {
     $$.val = &tree.RevokeRole{Roles: $2.nameList(), Members: $4.nameList(), AdminOption: false }
@@ -2705,7 +3312,7 @@ use_stmt:
 nonpreparable_set_stmt:
   set_transaction_stmt // EXTEND WITH HELP: SET TRANSACTION
 | set_exprs_internal   { /* SKIP DOC */ }
-| SET CONSTRAINTS error { return unimplemented(sqllex, "set constraints") }
+| set_constraints_stmt // EXTEND WITH HELP: SET CONSTRAINTS
 | SET LOCAL error { return unimplementedWithIssue(sqllex, 32562) }
 
 // SET SESSION / SET CLUSTER SETTING
@@ -2893,6 +3500,31 @@ set_transaction_stmt:
   }
 | SET SESSION TRANSACTION error // SHOW HELP: SET TRANSACTION
 
+// %Help: SET CONSTRAINTS - configure the checking of deferrable constraints
+// %Category: Txn
+// %Text:
+// SET CONSTRAINTS ALL { DEFERRED | IMMEDIATE }
+//
+// %SeeAlso: SET TRANSACTION, CREATE TABLE
+set_constraints_stmt:
+  SET CONSTRAINTS ALL DEFERRED
+  {
+    $$.val = &tree.SetConstraints{Deferred: true}
+  }
+| SET CONSTRAINTS ALL IMMEDIATE
+  {
+    $$.val = &tree.SetConstraints{Deferred: false}
+  }
+| SET CONSTRAINTS name_list DEFERRED
+  {
+    return unimplementedWithIssueDetail(sqllex, 31632, "set constraints name")
+  }
+| SET CONSTRAINTS name_list IMMEDIATE
+  {
+    return unimplementedWithIssueDetail(sqllex, 31632, "set constraints name")
+  }
+| SET CONSTRAINTS error // SHOW HELP: SET CONSTRAINTS
+
 generic_set:
   var_name to_or_eq var_list
   {
@@ -3865,8 +4497,8 @@ pause_stmt:
 // %Help: CREATE TABLE - create a new table
 // %Category: DDL
 // %Text:
-// CREATE TABLE [IF NOT EXISTS] <tablename> ( <elements...> ) [<interleave>]
-// CREATE TABLE [IF NOT EXISTS] <tablename> [( <colnames...> )] AS <source>
+// CREATE [TEMPORARY] TABLE [IF NOT EXISTS] <tablename> ( <elements...> ) [<interleave>]
+// CREATE [TEMPORARY] TABLE [IF NOT EXISTS] <tablename> [( <colnames...> )] AS <source>
 //
 // Table elements:
 //    <name> <type> [<qualifiers...>]
@@ -3905,6 +4537,7 @@ create_table_stmt:
     $$.val = &tree.CreateTable{
       Table: name,
       IfNotExists: false,
+      Temporary: $2.bool(),
       Interleave: $8.interleave(),
       Defs: $6.tblDefs(),
       AsSource: nil,
@@ -3922,6 +4555,7 @@ create_table_stmt:
     $$.val = &tree.CreateTable{
       Table: name,
       IfNotExists: true,
+      Temporary: $2.bool(),
       Interleave: $11.interleave(),
       Defs: $9.tblDefs(),
       AsSource: nil,
@@ -3946,6 +4580,7 @@ create_table_as_stmt:
     $$.val = &tree.CreateTable{
       Table: name,
       IfNotExists: false,
+      Temporary: $2.bool(),
       Interleave: nil,
       Defs: nil,
       AsSource: $8.slct(),
@@ -3962,6 +4597,7 @@ create_table_as_stmt:
     $$.val = &tree.CreateTable{
       Table: name,
       IfNotExists: true,
+      Temporary: $2.bool(),
       Interleave: nil,
       Defs: nil,
       AsSource: $11.slct(),
@@ -3986,14 +4622,14 @@ opt_create_as_data:
  * so we'll probably continue to treat LOCAL as a noise word.
  */
 opt_temp:
-  TEMPORARY         { return unimplementedWithIssue(sqllex, 5807) }
-| TEMP              { return unimplementedWithIssue(sqllex, 5807) }
-| LOCAL TEMPORARY   { return unimplementedWithIssue(sqllex, 5807) }
-| LOCAL TEMP        { return unimplementedWithIssue(sqllex, 5807) }
-| GLOBAL TEMPORARY  { return unimplementedWithIssue(sqllex, 5807) }
-| GLOBAL TEMP       { return unimplementedWithIssue(sqllex, 5807) }
+  TEMPORARY         { $$.val = true }
+| TEMP              { $$.val = true }
+| LOCAL TEMPORARY   { $$.val = true }
+| LOCAL TEMP        { $$.val = true }
+| GLOBAL TEMPORARY  { $$.val = true }
+| GLOBAL TEMP       { $$.val = true }
 | UNLOGGED          { return unimplemented(sqllex, "create unlogged") }
-| /*EMPTY*/         { /* no error */ }
+| /*EMPTY*/         { $$.val = false }
 
 opt_table_elem_list:
   table_elem_list
@@ -4230,7 +4866,7 @@ col_qualification_elem:
   {
     $$.val = &tree.ColumnDefault{Expr: $2.expr()}
   }
-| REFERENCES table_name opt_name_parens key_match reference_actions
+| REFERENCES table_name opt_name_parens key_match reference_actions opt_deferrable
  {
     name, err := tree.NormalizeTableName($2.unresolvedName())
     if err != nil {
@@ -4242,6 +4878,7 @@ col_qualification_elem:
       Col: tree.Name($3),
       Actions: $5.referenceActions(),
       Match: $4.compositeKeyMatchMethod(),
+      Deferrability: $6.constraintDeferrability(),
     }
  }
 | AS '(' a_expr ')' STORED
@@ -4259,7 +4896,7 @@ col_qualification_elem:
  }
 
 index_def:
-  INDEX opt_index_name '(' index_params ')' opt_storing opt_interleave opt_partition_by
+  INDEX opt_index_name '(' index_params ')' opt_storing opt_interleave opt_partition_by opt_idx_where
   {
     $$.val = &tree.IndexTableDef{
       Name:    tree.Name($2),
@@ -4267,9 +4904,10 @@ index_def:
       Storing: $6.nameList(),
       Interleave: $7.interleave(),
       PartitionBy: $8.partitionBy(),
+      Predicate: $9.expr(),
     }
   }
-| UNIQUE INDEX opt_index_name '(' index_params ')' opt_storing opt_interleave opt_partition_by
+| UNIQUE INDEX opt_index_name '(' index_params ')' opt_storing opt_interleave opt_partition_by opt_idx_where
   {
     $$.val = &tree.UniqueConstraintTableDef{
       IndexTableDef: tree.IndexTableDef {
@@ -4278,6 +4916,7 @@ index_def:
         Storing: $7.nameList(),
         Interleave: $8.interleave(),
         PartitionBy: $9.partitionBy(),
+        Predicate: $10.expr(),
       },
     }
   }
@@ -4316,18 +4955,26 @@ table_constraint:
 constraint_elem:
   CHECK '(' a_expr ')' opt_deferrable
   {
+    if $5.constraintDeferrability() != tree.NotDeferrable {
+      sqllex.Error("CHECK constraints cannot be marked DEFERRABLE")
+      return 1
+    }
     $$.val = &tree.CheckConstraintTableDef{
       Expr: $3.expr(),
     }
   }
-| UNIQUE '(' index_params ')' opt_storing opt_interleave opt_partition_by  opt_deferrable
+| UNIQUE '(' index_params ')' opt_storing opt_interleave opt_partition_by opt_idx_where opt_deferrable
   {
+    if $9.constraintDeferrability() != tree.NotDeferrable {
+      return unimplementedWithIssueDetail(sqllex, 31632, "deferrable unique")
+    }
     $$.val = &tree.UniqueConstraintTableDef{
       IndexTableDef: tree.IndexTableDef{
         Columns: $3.idxElems(),
         Storing: $5.nameList(),
         Interleave: $6.interleave(),
         PartitionBy: $7.partitionBy(),
+        Predicate: $8.expr(),
       },
     }
   }
@@ -4354,16 +5001,36 @@ constraint_elem:
       ToCols: $8.nameList(),
       Match: $9.compositeKeyMatchMethod(),
       Actions: $10.referenceActions(),
+      Deferrability: $11.constraintDeferrability(),
     }
   }
 
+// INITIALLY DEFERRED implies DEFERRABLE, like in PostgreSQL.
 opt_deferrable:
-  /* EMPTY */ { /* no error */ }
-| DEFERRABLE { return unimplementedWithIssueDetail(sqllex, 31632, "deferrable") }
-| DEFERRABLE INITIALLY DEFERRED { return unimplementedWithIssueDetail(sqllex, 31632, "def initially deferred") }
-| DEFERRABLE INITIALLY IMMEDIATE { return unimplementedWithIssueDetail(sqllex, 31632, "def initially immediate") }
-| INITIALLY DEFERRED { return unimplementedWithIssueDetail(sqllex, 31632, "initially deferred") }
-| INITIALLY IMMEDIATE { return unimplementedWithIssueDetail(sqllex, 31632, "initially immediate") }
+  /* EMPTY */
+  {
+    $$.val = tree.NotDeferrable
+  }
+| DEFERRABLE
+  {
+    $$.val = tree.DeferrableInitiallyImmediate
+  }
+| DEFERRABLE INITIALLY DEFERRED
+  {
+    $$.val = tree.DeferrableInitiallyDeferred
+  }
+| DEFERRABLE INITIALLY IMMEDIATE
+  {
+    $$.val = tree.DeferrableInitiallyImmediate
+  }
+| INITIALLY DEFERRED
+  {
+    $$.val = tree.DeferrableInitiallyDeferred
+  }
+| INITIALLY IMMEDIATE
+  {
+    $$.val = tree.NotDeferrable
+  }
 
 storing:
   COVERING
@@ -4510,7 +5177,7 @@ numeric_only:
 // %Help: CREATE SEQUENCE - create a new sequence
 // %Category: DDL
 // %Text:
-// CREATE SEQUENCE <seqname>
+// CREATE [TEMPORARY] SEQUENCE <seqname>
 //   [INCREMENT <increment>]
 //   [MINVALUE <minvalue> | NO MINVALUE]
 //   [MAXVALUE <maxvalue> | NO MAXVALUE]
@@ -4528,7 +5195,7 @@ create_sequence_stmt:
       sqllex.Error(err.Error())
       return 1
     }
-    $$.val = &tree.CreateSequence{Name: name, Options: $5.seqOpts()}
+    $$.val = &tree.CreateSequence{Name: name, Temporary: $2.bool(), Options: $5.seqOpts()}
   }
 | CREATE opt_temp SEQUENCE IF NOT EXISTS sequence_name opt_sequence_option_list
   {
@@ -4537,7 +5204,7 @@ create_sequence_stmt:
       sqllex.Error(err.Error())
       return 1
     }
-    $$.val = &tree.CreateSequence{Name: name, Options: $8.seqOpts(), IfNotExists: true}
+    $$.val = &tree.CreateSequence{Name: name, Temporary: $2.bool(), Options: $8.seqOpts(), IfNotExists: true}
   }
 | CREATE opt_temp SEQUENCE error // SHOW HELP: CREATE SEQUENCE
 
@@ -4585,6 +5252,22 @@ truncate_stmt:
   }
 | TRUNCATE error // SHOW HELP: TRUNCATE
 
+// %Help: REFRESH - recalculate a materialized view
+// %Category: DDL
+// %Text: REFRESH MATERIALIZED VIEW <viewname>
+// %SeeAlso: CREATE VIEW
+refresh_stmt:
+  REFRESH MATERIALIZED VIEW view_name
+  {
+    name, err := tree.NormalizeTableName($4.unresolvedName())
+    if err != nil {
+      sqllex.Error(err.Error())
+      return 1
+    }
+    $$.val = &tree.RefreshMaterializedView{Name: name}
+  }
+| REFRESH error // SHOW HELP: REFRESH
+
 // %Help: CREATE USER - define a new user
 // %Category: Priv
 // %Text: CREATE USER [IF NOT EXISTS] <name> [ [WITH] PASSWORD <passwd> ]
@@ -4633,7 +5316,7 @@ role_or_group:
 
 // %Help: CREATE VIEW - create a new view
 // %Category: DDL
-// %Text: CREATE VIEW <viewname> [( <colnames...> )] AS <source>
+// %Text: CREATE [TEMPORARY | MATERIALIZED] VIEW <viewname> [( <colnames...> )] AS <source>
 // %SeeAlso: CREATE TABLE, SHOW CREATE, WEBDOCS/create-view.html
 create_view_stmt:
   CREATE opt_temp opt_view_recursive VIEW view_name opt_column_list AS select_stmt
@@ -4644,25 +5327,54 @@ create_view_stmt:
       return 1
     }
     $$.val = &tree.CreateView{
+      Temporary: $2.bool(),
       Name: name,
       ColumnNames: $6.nameList(),
       AsSource: $8.slct(),
     }
   }
+| CREATE MATERIALIZED VIEW view_name opt_column_list AS select_stmt
+  {
+    name, err := tree.NormalizeTableName($4.unresolvedName())
+    if err != nil {
+      sqllex.Error(err.Error())
+      return 1
+    }
+    $$.val = &tree.CreateView{
+      Materialized: true,
+      Name: name,
+      ColumnNames: $5.nameList(),
+      AsSource: $7.slct(),
+    }
+  }
 | CREATE OR REPLACE opt_temp opt_view_recursive VIEW error { return unimplementedWithIssue(sqllex, 24897) }
+| CREATE MATERIALIZED VIEW error // SHOW HELP: CREATE VIEW
 | CREATE opt_temp opt_view_recursive VIEW error // SHOW HELP: CREATE VIEW
 
 opt_view_recursive:
   /* EMPTY */ { /* no error */ }
 | RECURSIVE { return unimplemented(sqllex, "create recursive view") }
 
-// CREATE TYPE/DOMAIN is not yet supported by CockroachDB but we
-// want to report it with the right issue number.
+// %Help: CREATE TYPE - create a new user-defined type
+// %Category: DDL
+// %Text: CREATE TYPE <typename> AS ENUM ( [<label> [, ...]] )
+// %SeeAlso: ALTER TYPE, DROP TYPE
 create_type_stmt:
-  // Record/Composite types.
-  CREATE TYPE type_name AS '(' error      { return unimplementedWithIssue(sqllex, 27792) }
   // Enum types.
-| CREATE TYPE type_name AS ENUM '(' error { return unimplementedWithIssue(sqllex, 24873) }
+  CREATE TYPE type_name AS ENUM '(' opt_enum_val_list ')'
+  {
+    name, err := tree.NormalizeTableName($3.unresolvedName())
+    if err != nil {
+      sqllex.Error(err.Error())
+      return 1
+    }
+    $$.val = &tree.CreateType{Name: name, EnumLabels: $7.strs()}
+  }
+  // The other kinds of CREATE TYPE and CREATE DOMAIN are not yet
+  // supported by CockroachDB but we want to report them with the right
+  // issue number.
+  // Record/Composite types.
+| CREATE TYPE type_name AS '(' error      { return unimplementedWithIssue(sqllex, 27792) }
   // Range types.
 | CREATE TYPE type_name AS RANGE error    { return unimplementedWithIssue(sqllex, 27791) }
   // Base (primitive) types.
@@ -4671,6 +5383,24 @@ create_type_stmt:
 | CREATE TYPE type_name                   { return unimplementedWithIssueDetail(sqllex, 27793, "shell") }
   // Domain types.
 | CREATE DOMAIN type_name error           { return unimplementedWithIssueDetail(sqllex, 27796, "create") }
+| CREATE TYPE error // SHOW HELP: CREATE TYPE
+
+opt_enum_val_list:
+  enum_val_list
+| /* EMPTY */
+  {
+    $$.val = []string(nil)
+  }
+
+enum_val_list:
+  SCONST
+  {
+    $$.val = []string{$1}
+  }
+| enum_val_list ',' SCONST
+  {
+    $$.val = append($1.strs(), $3)
+  }
 
 // %Help: CREATE INDEX - create a new index
 // %Category: DDL
@@ -4678,6 +5408,7 @@ create_type_stmt:
 // CREATE [UNIQUE | INVERTED] INDEX [IF NOT EXISTS] [<idxname>]
 //        ON <tablename> ( <colname> [ASC | DESC] [, ...] )
 //        [STORING ( <colnames...> )] [<interleave>]
+//        [WHERE <predicate>]
 //
 // Interleave clause:
 //    INTERLEAVE IN PARENT <tablename> ( <colnames...> ) [CASCADE | RESTRICT]
@@ -4701,6 +5432,7 @@ create_index_stmt:
       Interleave: $12.interleave(),
       PartitionBy: $13.partitionBy(),
       Inverted: $7.bool(),
+      Predicate: $14.expr(),
     }
   }
 | CREATE opt_unique INDEX IF NOT EXISTS index_name ON table_name opt_using_gin_btree '(' index_params ')' opt_storing opt_interleave opt_partition_by opt_idx_where
@@ -4720,6 +5452,7 @@ create_index_stmt:
       Interleave:  $15.interleave(),
       PartitionBy: $16.partitionBy(),
       Inverted:    $10.bool(),
+      Predicate:   $17.expr(),
     }
   }
 | CREATE opt_unique INVERTED INDEX opt_index_name ON table_name '(' index_params ')' opt_storing opt_interleave opt_partition_by opt_idx_where
@@ -4738,6 +5471,7 @@ create_index_stmt:
       Storing:     $11.nameList(),
       Interleave:  $12.interleave(),
       PartitionBy: $13.partitionBy(),
+      Predicate:   $14.expr(),
     }
   }
 | CREATE opt_unique INVERTED INDEX IF NOT EXISTS index_name ON table_name '(' index_params ')' opt_storing opt_interleave opt_partition_by opt_idx_where
@@ -4757,13 +5491,20 @@ create_index_stmt:
       Storing:     $14.nameList(),
       Interleave:  $15.interleave(),
       PartitionBy: $16.partitionBy(),
+      Predicate:   $17.expr(),
     }
   }
 | CREATE opt_unique INDEX error // SHOW HELP: CREATE INDEX
 
 opt_idx_where:
-  /* EMPTY */ { /* no error */ }
-| WHERE error { return unimplementedWithIssue(sqllex, 9683) }
+  WHERE a_expr
+  {
+    $$.val = $2.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.Expr(nil)
+  }
 
 opt_using_gin_btree:
   USING name
@@ -4996,9 +5737,9 @@ opt_set_data:
   SET DATA {}
 | /* EMPTY */ {}
 
-// %Help: RELEASE - complete a retryable block
+// %Help: RELEASE - release a savepoint
 // %Category: Txn
-// %Text: RELEASE [SAVEPOINT] cockroach_restart
+// %Text: RELEASE [SAVEPOINT] <savepoint name>
 // %SeeAlso: SAVEPOINT, WEBDOCS/savepoint.html
 release_stmt:
   RELEASE savepoint_name
@@ -5029,9 +5770,9 @@ resume_stmt:
   }
 | RESUME error // SHOW HELP: RESUME JOBS
 
-// %Help: SAVEPOINT - start a retryable block
+// %Help: SAVEPOINT - define a new savepoint within the current transaction
 // %Category: Txn
-// %Text: SAVEPOINT cockroach_restart
+// %Text: SAVEPOINT <savepoint name>
 // %SeeAlso: RELEASE, WEBDOCS/savepoint.html
 savepoint_stmt:
   SAVEPOINT name
@@ -5101,7 +5842,7 @@ opt_abort_mod:
 
 // %Help: ROLLBACK - abort the current transaction
 // %Category: Txn
-// %Text: ROLLBACK [TRANSACTION] [TO [SAVEPOINT] cockroach_restart]
+// %Text: ROLLBACK [TRANSACTION] [TO [SAVEPOINT] <savepoint name>]
 // %SeeAlso: BEGIN, COMMIT, SAVEPOINT, WEBDOCS/rollback-transaction.html
 rollback_stmt:
   ROLLBACK opt_to_savepoint
@@ -5216,6 +5957,160 @@ transaction_read_mode:
     $$.val = tree.ReadWrite
   }
 
+// %Help: CREATE SCHEMA - create a new schema
+// %Category: DDL
+// %Text: CREATE SCHEMA [IF NOT EXISTS] <schemaname>
+// %SeeAlso: DROP SCHEMA
+create_schema_stmt:
+  CREATE SCHEMA name
+  {
+    $$.val = &tree.CreateSchema{
+      Schema: tree.Name($3),
+    }
+  }
+| CREATE SCHEMA IF NOT EXISTS name
+  {
+    $$.val = &tree.CreateSchema{
+      IfNotExists: true,
+      Schema: tree.Name($6),
+    }
+  }
+| CREATE SCHEMA error // SHOW HELP: CREATE SCHEMA
+
+// %Help: CREATE FUNCTION - create a new user-defined function
+// %Category: DDL
+// %Text:
+// CREATE [OR REPLACE] FUNCTION <name> ( [[<argname>] <argtype> [, ...]] )
+//   RETURNS [SETOF] <rettype>
+//   LANGUAGE SQL [IMMUTABLE | STABLE | VOLATILE]
+//   AS '<definition>'
+//
+// The definition is a single SELECT statement. Arguments are referenced
+// by name or by position ($1, $2, ...).
+// %SeeAlso: DROP FUNCTION
+create_function_stmt:
+  CREATE opt_or_replace FUNCTION function_name '(' opt_func_arg_list ')' RETURNS opt_setof typename create_func_opt_list
+  {
+    name, err := tree.NormalizeTableName($4.unresolvedName())
+    if err != nil {
+      sqllex.Error(err.Error())
+      return 1
+    }
+    n := &tree.CreateFunction{
+      Name: name,
+      Replace: $2.bool(),
+      Args: $6.funcArgs(),
+      ReturnsSet: $9.bool(),
+      ReturnType: $10.colType(),
+    }
+    var haveLanguage, haveVolatility, haveBody bool
+    for _, opt := range $11.kvOptions() {
+      var seen *bool
+      val := opt.Value.(*tree.StrVal).RawString()
+      switch opt.Key {
+      case "language":
+        seen = &haveLanguage
+        if lang := strings.ToLower(val); lang != "sql" {
+          return unimplementedWithIssueDetail(sqllex, 17511, "create function language " + lang)
+        }
+      case "volatility":
+        seen = &haveVolatility
+        switch val {
+        case "IMMUTABLE":
+          n.Volatility = tree.FunctionImmutable
+        case "STABLE":
+          n.Volatility = tree.FunctionStable
+        default:
+          n.Volatility = tree.FunctionVolatile
+        }
+      case "as":
+        seen = &haveBody
+        n.Body = val
+      }
+      if *seen {
+        sqllex.Error("conflicting or redundant options")
+        return 1
+      }
+      *seen = true
+    }
+    if !haveLanguage {
+      sqllex.Error("no language specified")
+      return 1
+    }
+    if !haveBody {
+      sqllex.Error("no function body specified")
+      return 1
+    }
+    $$.val = n
+  }
+| CREATE opt_or_replace FUNCTION error // SHOW HELP: CREATE FUNCTION
+
+opt_func_arg_list:
+  func_arg_list
+| /* EMPTY */
+  {
+    $$.val = tree.FuncArgs(nil)
+  }
+
+func_arg_list:
+  func_arg
+  {
+    $$.val = tree.FuncArgs{$1.funcArg()}
+  }
+| func_arg_list ',' func_arg
+  {
+    $$.val = append($1.funcArgs(), $3.funcArg())
+  }
+
+func_arg:
+  typename
+  {
+    $$.val = tree.FuncArg{Type: $1.colType()}
+  }
+| IDENT typename
+  {
+    // Argument names cannot be keywords, so that the names of types
+    // that are also unreserved keywords are not ambiguous.
+    $$.val = tree.FuncArg{Name: tree.Name($1), Type: $2.colType()}
+  }
+
+opt_setof:
+  SETOF { $$.val = true }
+| /* EMPTY */ { $$.val = false }
+
+create_func_opt_list:
+  create_func_opt_item
+  {
+    $$.val = []tree.KVOption{$1.kvOption()}
+  }
+| create_func_opt_list create_func_opt_item
+  {
+    $$.val = append($1.kvOptions(), $2.kvOption())
+  }
+
+create_func_opt_item:
+  LANGUAGE non_reserved_word_or_sconst
+  {
+    $$.val = tree.KVOption{Key: "language", Value: tree.NewStrVal($2)}
+  }
+| IMMUTABLE
+  {
+    $$.val = tree.KVOption{Key: "volatility", Value: tree.NewStrVal("IMMUTABLE")}
+  }
+| STABLE
+  {
+    $$.val = tree.KVOption{Key: "volatility", Value: tree.NewStrVal("STABLE")}
+  }
+| VOLATILE
+  {
+    $$.val = tree.KVOption{Key: "volatility", Value: tree.NewStrVal("VOLATILE")}
+  }
+| AS SCONST
+  {
+    $$.val = tree.KVOption{Key: "as", Value: tree.NewStrVal($2)}
+  }
+| STRICT { return unimplementedWithIssueDetail(sqllex, 17511, "strict") }
+
 // %Help: CREATE DATABASE - create a new database
 // %Category: DDL
 // %Text: CREATE DATABASE [IF NOT EXISTS] <name>
@@ -5293,7 +6188,8 @@ opt_equal:
 // %Text:
 // INSERT INTO <tablename> [[AS] <name>] [( <colnames...> )]
 //        <selectclause>
-//        [ON CONFLICT [( <colnames...> )] {DO UPDATE SET ... [WHERE <expr>] | DO NOTHING}]
+//        [ON CONFLICT [( <colnames...> ) [WHERE <expr>] | ON CONSTRAINT <name>]
+//         {DO UPDATE SET ... [WHERE <expr>] | DO NOTHING}]
 //        [RETURNING <exprs...>]
 // %SeeAlso: UPSERT, UPDATE, DELETE, WEBDOCS/insert.html
 insert_stmt:
@@ -5401,23 +6297,30 @@ insert_column_item:
 on_conflict:
   ON CONFLICT opt_conf_expr DO UPDATE SET set_clause_list opt_where_clause
   {
-    $$.val = &tree.OnConflict{Columns: $3.nameList(), Exprs: $7.updateExprs(), Where: tree.NewWhere(tree.AstWhere, $8.expr())}
+    onConflict := $3.onConflict()
+    onConflict.Exprs = $7.updateExprs()
+    onConflict.Where = tree.NewWhere(tree.AstWhere, $8.expr())
+    $$.val = onConflict
   }
 | ON CONFLICT opt_conf_expr DO NOTHING
   {
-    $$.val = &tree.OnConflict{Columns: $3.nameList(), DoNothing: true}
+    onConflict := $3.onConflict()
+    onConflict.DoNothing = true
+    $$.val = onConflict
   }
 
 opt_conf_expr:
-  '(' name_list ')'
+  '(' name_list ')' opt_where_clause
   {
-    $$.val = $2.nameList()
+    $$.val = &tree.OnConflict{Columns: $2.nameList(), ArbiterPredicate: $4.expr()}
+  }
+| ON CONSTRAINT constraint_name
+  {
+    $$.val = &tree.OnConflict{Constraint: tree.Name($3)}
   }
-| '(' name_list ')' where_clause { return unimplementedWithIssue(sqllex, 32557) }
-| ON CONSTRAINT constraint_name { return unimplementedWithIssue(sqllex, 28161) }
 | /* EMPTY */
   {
-    $$.val = tree.NameList(nil)
+    $$.val = &tree.OnConflict{}
   }
 
 returning_clause:
@@ -5440,6 +6343,7 @@ returning_clause:
 // %Text:
 // UPDATE <tablename> [[AS] <name>]
 //        SET ...
+//        [FROM <source>]
 //        [WHERE <expr>]
 //        [ORDER BY <exprs...>]
 //        [LIMIT <expr>]
@@ -5453,6 +6357,7 @@ update_stmt:
       With: $1.with(),
       Table: $3.tblExpr(),
       Exprs: $5.updateExprs(),
+      From: $6.tblExprs(),
       Where: tree.NewWhere(tree.AstWhere, $7.expr()),
       OrderBy: $8.orderBy(),
       Limit: $9.limit(),
@@ -5461,10 +6366,15 @@ update_stmt:
   }
 | opt_with_clause UPDATE error // SHOW HELP: UPDATE
 
-// Mark this as unimplemented until the normal from_clause is supported here.
 update_from_clause:
-  FROM from_list { return unimplementedWithIssue(sqllex, 7841) }
-| /* EMPTY */ {}
+  FROM from_list
+  {
+    $$.val = $2.tblExprs()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.TableExprs{}
+  }
 
 set_clause_list:
   set_clause
@@ -5559,34 +6469,110 @@ select_with_parens:
 //      clause.
 //      - 2002-08-28 bjm
 select_no_parens:
-  simple_select opt_for
+  simple_select opt_for_locking_clause
   {
-    $$.val = &tree.Select{Select: $1.selectStmt()}
+    $$.val = &tree.Select{Select: $1.selectStmt(), Locking: $2.lockingClause()}
+  }
+| select_with_parens for_locking_clause
+  {
+    $$.val = &tree.Select{Select: $1.selectStmt(), Locking: $2.lockingClause()}
   }
-| select_clause sort_clause opt_for
+| select_clause sort_clause opt_for_locking_clause
   {
-    $$.val = &tree.Select{Select: $1.selectStmt(), OrderBy: $2.orderBy()}
+    $$.val = &tree.Select{Select: $1.selectStmt(), OrderBy: $2.orderBy(), Locking: $3.lockingClause()}
   }
-| select_clause opt_sort_clause select_limit opt_for
+| select_clause opt_sort_clause select_limit opt_for_locking_clause
   {
-    $$.val = &tree.Select{Select: $1.selectStmt(), OrderBy: $2.orderBy(), Limit: $3.limit()}
+    $$.val = &tree.Select{Select: $1.selectStmt(), OrderBy: $2.orderBy(), Limit: $3.limit(), Locking: $4.lockingClause()}
   }
-| with_clause select_clause opt_for
+| with_clause select_clause opt_for_locking_clause
   {
-    $$.val = &tree.Select{With: $1.with(), Select: $2.selectStmt()}
+    $$.val = &tree.Select{With: $1.with(), Select: $2.selectStmt(), Locking: $3.lockingClause()}
   }
-| with_clause select_clause sort_clause opt_for
+| with_clause select_clause sort_clause opt_for_locking_clause
   {
-    $$.val = &tree.Select{With: $1.with(), Select: $2.selectStmt(), OrderBy: $3.orderBy()}
+    $$.val = &tree.Select{With: $1.with(), Select: $2.selectStmt(), OrderBy: $3.orderBy(), Locking: $4.lockingClause()}
   }
-| with_clause select_clause opt_sort_clause select_limit opt_for
+| with_clause select_clause opt_sort_clause select_limit opt_for_locking_clause
   {
-    $$.val = &tree.Select{With: $1.with(), Select: $2.selectStmt(), OrderBy: $3.orderBy(), Limit: $4.limit()}
+    $$.val = &tree.Select{With: $1.with(), Select: $2.selectStmt(), OrderBy: $3.orderBy(), Limit: $4.limit(), Locking: $5.lockingClause()}
   }
 
-opt_for:
-  /* EMPTY */ { /* no error */ }
-| FOR error { return unimplementedWithIssue(sqllex, 6583) }
+opt_for_locking_clause:
+  for_locking_clause
+| /* EMPTY */
+  {
+    $$.val = tree.LockingClause(nil)
+  }
+
+for_locking_clause:
+  for_locking_items
+| FOR READ ONLY
+  {
+    $$.val = tree.LockingClause(nil)
+  }
+
+for_locking_items:
+  for_locking_item
+  {
+    $$.val = tree.LockingClause{$1.lockingItem()}
+  }
+| for_locking_items for_locking_item
+  {
+    $$.val = append($1.lockingClause(), $2.lockingItem())
+  }
+
+for_locking_item:
+  for_locking_strength opt_locked_rels opt_nowait_or_skip
+  {
+    $$.val = &tree.LockingItem{
+      Strength:   $1.lockingStrength(),
+      Targets:    $2.tableNames(),
+      WaitPolicy: $3.lockingWaitPolicy(),
+    }
+  }
+
+for_locking_strength:
+  FOR UPDATE
+  {
+    $$.val = tree.ForUpdate
+  }
+| FOR NO KEY UPDATE
+  {
+    $$.val = tree.ForNoKeyUpdate
+  }
+| FOR SHARE
+  {
+    $$.val = tree.ForShare
+  }
+| FOR KEY SHARE
+  {
+    $$.val = tree.ForKeyShare
+  }
+
+opt_locked_rels:
+  OF table_name_list
+  {
+    $$.val = $2.tableNames()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.TableNames(nil)
+  }
+
+opt_nowait_or_skip:
+  SKIP LOCKED
+  {
+    $$.val = tree.LockWaitSkip
+  }
+| NOWAIT
+  {
+    $$.val = tree.LockWaitError
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.LockWaitBlock
+  }
 
 select_clause:
 // We only provide help if an open parenthesis is provided, because
@@ -5645,6 +6631,8 @@ simple_select:
 //        [ ORDER BY <expr> [ ASC | DESC ] [, ...] ]
 //        [ LIMIT { <expr> | ALL } ]
 //        [ OFFSET <expr> [ ROW | ROWS ] ]
+//        [ FOR { UPDATE | NO KEY UPDATE | SHARE | KEY SHARE }
+//          [ OF <tablename> [, ...] ] [ NOWAIT | SKIP LOCKED ] [...] ]
 // %SeeAlso: WEBDOCS/select-clause.html
 simple_select_clause:
   SELECT opt_all_clause target_list
@@ -5753,7 +6741,10 @@ with_clause:
     /* SKIP DOC */
     $$.val = &tree.With{CTEList: $2.ctes()}
   }
-| WITH RECURSIVE cte_list { return unimplementedWithIssue(sqllex, 21085) }
+| WITH RECURSIVE cte_list
+  {
+    $$.val = &tree.With{Recursive: true, CTEList: $3.ctes()}
+  }
 
 cte_list:
   common_table_expr
@@ -6618,7 +7609,7 @@ const_typename:
     // See https://www.postgresql.org/docs/9.1/static/datatype-character.html
     // Postgres supports a special character type named "char" (with the quotes)
     // that is a single-character column type. It's used by system tables.
-    // Eventually this clause will be used to parse user-defined types as well,
+    // This clause is also used to parse references to user-defined types,
     // since their names can be quoted.
     if $1 == "char" {
       $$.val = coltypes.QChar
@@ -6629,10 +7620,9 @@ const_typename:
       if !ok {
           switch unimp {
               case 0:
-                // Note: we can only report an unimplemented error for specific
-                // known type names. Anything else may return PII.
-                sqllex.Error("type does not exist")
-                return 1
+                // Any other name may refer to a user-defined type. The name
+                // is resolved during type checking.
+                $$.val = &coltypes.TUserDefined{Name: $1}
               case -1:
                 return unimplemented(sqllex, "type name " + $1)
               default:
@@ -6868,7 +7858,13 @@ const_datetime:
     if $2.bool() { return unimplementedWithIssueDetail(sqllex, 26097, "type") }
     $$.val = coltypes.Time
   }
-| TIME '(' ICONST ')' opt_timezone   { return unimplementedWithIssue(sqllex, 32565) }
+| TIME '(' iconst64 ')' opt_timezone
+  {
+    if $5.bool() { return unimplementedWithIssueDetail(sqllex, 26097, "type with precision") }
+    typ, err := coltypes.NewTime($3.int64())
+    if err != nil { sqllex.Error(err.Error()); return 1 }
+    $$.val = typ
+  }
 | TIMETZ                             { return unimplementedWithIssueDetail(sqllex, 26097, "type") }
 | TIMETZ '(' ICONST ')'              { return unimplementedWithIssueDetail(sqllex, 26097, "type with precision") }
 | TIMESTAMP opt_timezone
@@ -6879,12 +7875,22 @@ const_datetime:
       $$.val = coltypes.Timestamp
     }
   }
-| TIMESTAMP '(' ICONST ')' opt_timezone { return unimplementedWithIssue(sqllex, 32098) }
+| TIMESTAMP '(' iconst64 ')' opt_timezone
+  {
+    typ, err := coltypes.NewTimestamp($3.int64(), $5.bool())
+    if err != nil { sqllex.Error(err.Error()); return 1 }
+    $$.val = typ
+  }
 | TIMESTAMPTZ
   {
     $$.val = coltypes.TimestampWithTZ
   }
-| TIMESTAMPTZ '(' ICONST ')'            { return unimplementedWithIssue(sqllex, 32098) }
+| TIMESTAMPTZ '(' iconst64 ')'
+  {
+    typ, err := coltypes.NewTimestamp($3.int64(), true /* withTZ */)
+    if err != nil { sqllex.Error(err.Error()); return 1 }
+    $$.val = typ
+  }
 
 opt_timezone:
   WITH_LA TIME ZONE { $$.val = true; }
@@ -7000,7 +8006,10 @@ a_expr:
   {
     $$.val = &tree.CollateExpr{Expr: $1.expr(), Locale: $3}
   }
-| a_expr AT TIME ZONE a_expr %prec AT { return unimplementedWithIssue(sqllex, 32005) }
+| a_expr AT TIME ZONE a_expr %prec AT
+  {
+    $$.val = &tree.FuncExpr{Func: tree.WrapFunction("timezone"), Exprs: tree.Exprs{$5.expr(), $1.expr()}}
+  }
   // These operators must be called out explicitly in order to make use of
   // bison's automatic operator-precedence handling. All other operator names
   // are handled by the generic productions using "OP", below; and all those
@@ -7089,6 +8098,10 @@ a_expr:
   {
     $$.val = &tree.ComparisonExpr{Operator: tree.ContainedBy, Left: $1.expr(), Right: $3.expr()}
   }
+| a_expr TEXTSEARCH_MATCH a_expr
+  {
+    $$.val = &tree.ComparisonExpr{Operator: tree.TSMatches, Left: $1.expr(), Right: $3.expr()}
+  }
 | a_expr '=' a_expr
   {
     $$.val = &tree.ComparisonExpr{Operator: tree.EQ, Left: $1.expr(), Right: $3.expr()}
@@ -7131,7 +8144,7 @@ a_expr:
   }
 | a_expr INET_CONTAINS_OR_CONTAINED_BY a_expr
   {
-    $$.val = &tree.FuncExpr{Func: tree.WrapFunction("inet_contains_or_contained_by"), Exprs: tree.Exprs{$1.expr(), $3.expr()}}
+    $$.val = &tree.ComparisonExpr{Operator: tree.Overlaps, Left: $1.expr(), Right: $3.expr()}
   }
 | a_expr INET_CONTAINS_OR_EQUALS a_expr
   {
@@ -7653,6 +8666,7 @@ func_expr:
   func_application within_group_clause filter_clause over_clause
   {
     f := $1.expr().(*tree.FuncExpr)
+    f.WithinGroup = $2.orderBy()
     f.Filter = $3.expr()
     f.WindowDef = $4.windowDef()
     $$.val = f
@@ -7836,8 +8850,14 @@ special_function:
 
 // Aggregate decoration clauses
 within_group_clause:
-WITHIN GROUP '(' sort_clause ')' { return unimplemented(sqllex, "within group") }
-| /* EMPTY */ {}
+  WITHIN GROUP '(' sort_clause ')'
+  {
+    $$.val = $4.orderBy()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.OrderBy(nil)
+  }
 
 filter_clause:
   FILTER '(' WHERE a_expr ')'
@@ -8648,6 +9668,8 @@ opt_family_name:     opt_name
 
 table_alias_name:    name
 
+cursor_name:         name
+
 statistics_name:     name
 
 window_name:         name
@@ -8656,6 +9678,8 @@ view_name:           table_name
 
 type_name:           db_object_name
 
+function_name:       db_object_name
+
 sequence_name:       db_object_name
 
 table_name:          db_object_name
@@ -8833,15 +9857,20 @@ unrestricted_name:
 // "Unreserved" keywords --- available for use as any kind of name.
 unreserved_keyword:
   ABORT
+| ABSOLUTE
 | ACTION
 | ADD
 | ADMIN
+| AFTER
 | AGGREGATE
 | ALTER
 | AT
 | BACKUP
+| BACKWARD
+| BEFORE
 | BEGIN
 | BIGSERIAL
+| BINARY
 | BLOB
 | BOOL
 | BY
@@ -8851,6 +9880,7 @@ unreserved_keyword:
 | CANCEL
 | CASCADE
 | CHANGEFEED
+| CLOSE
 | CLUSTER
 | COLUMNS
 | COMMENT
@@ -8867,6 +9897,7 @@ unreserved_keyword:
 | COVERING
 | CUBE
 | CURRENT
+| CURSOR
 | CYCLE
 | DATA
 | DATABASE
@@ -8874,6 +9905,7 @@ unreserved_keyword:
 | DATE
 | DAY
 | DEALLOCATE
+| DECLARE
 | DELETE
 | DEFERRED
 | DISCARD
@@ -8900,20 +9932,24 @@ unreserved_keyword:
 | FLOAT8
 | FOLLOWING
 | FORCE_INDEX
+| FORWARD
 | FUNCTION
 | GLOBAL
 | GRANTS
 | GROUPS
 | HIGH
 | HISTOGRAM
+| HOLD
 | HOUR
 | IMMEDIATE
+| IMMUTABLE
 | IMPORT
 | INCREMENT
 | INCREMENTAL
 | INDEXES
 | INET
 | INJECT
+| INSENSITIVE
 | INSERT
 | INT2
 | INT2VECTOR
@@ -8931,13 +9967,16 @@ unreserved_keyword:
 | KEYS
 | KV
 | LANGUAGE
+| LAST
 | LC_COLLATE
 | LC_CTYPE
 | LEASE
 | LESS
 | LEVEL
 | LIST
+| LISTEN
 | LOCAL
+| LOCKED
 | LOW
 | MATCH
 | MATERIALIZED
@@ -8945,12 +9984,15 @@ unreserved_keyword:
 | MINUTE
 | MINVALUE
 | MONTH
+| MOVE
 | NAMES
 | NAN
 | NAME
 | NEXT
 | NO
 | NORMAL
+| NOTIFY
+| NOWAIT
 | NO_INDEX_JOIN
 | OF
 | OFF
@@ -8972,6 +10014,7 @@ unreserved_keyword:
 | PLANS
 | PRECEDING
 | PREPARE
+| PRIOR
 | PRIORITY
 | PUBLICATION
 | QUERIES
@@ -8981,11 +10024,13 @@ unreserved_keyword:
 | READ
 | RECURSIVE
 | REF
+| REFRESH
 | REGCLASS
 | REGPROC
 | REGPROCEDURE
 | REGNAMESPACE
 | REGTYPE
+| RELATIVE
 | RELEASE
 | RENAME
 | REPEATABLE
@@ -8994,6 +10039,7 @@ unreserved_keyword:
 | RESTORE
 | RESTRICT
 | RESUME
+| RETURNS
 | REVOKE
 | ROLE
 | ROLES
@@ -9008,6 +10054,7 @@ unreserved_keyword:
 | SCATTER
 | SCHEMA
 | SCHEMAS
+| SCROLL
 | SCRUB
 | SEARCH
 | SECOND
@@ -9022,14 +10069,18 @@ unreserved_keyword:
 | SESSION
 | SESSIONS
 | SET
+| SHARE
 | SHOW
 | SIMPLE
+| SKIP
 | SMALLSERIAL
 | SNAPSHOT
 | SQL
+| STABLE
 | START
 | STATISTICS
 | STDIN
+| STDOUT
 | STORE
 | STORED
 | STORING
@@ -9055,6 +10106,7 @@ unreserved_keyword:
 | UNBOUNDED
 | UNCOMMITTED
 | UNKNOWN
+| UNLISTEN
 | UNLOGGED
 | UPDATE
 | UPSERT
@@ -9066,6 +10118,7 @@ unreserved_keyword:
 | VALUE
 | VARYING
 | VIEW
+| VOLATILE
 | WITHIN
 | WITHOUT
 | WRITE
@@ -9115,6 +10168,7 @@ col_name_keyword:
 | PRECISION
 | REAL
 | ROW
+| SETOF
 | SMALLINT
 | SUBSTRING
 | TIME
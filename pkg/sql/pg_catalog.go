@@ -26,6 +26,7 @@ import (
 	"unicode"
 
 	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -445,12 +446,14 @@ CREATE TABLE pg_catalog.pg_auth_members (
 }
 
 var (
-	relKindTable    = tree.NewDString("r")
-	relKindIndex    = tree.NewDString("i")
-	relKindView     = tree.NewDString("v")
-	relKindSequence = tree.NewDString("S")
+	relKindTable            = tree.NewDString("r")
+	relKindIndex            = tree.NewDString("i")
+	relKindView             = tree.NewDString("v")
+	relKindMaterializedView = tree.NewDString("m")
+	relKindSequence         = tree.NewDString("S")
 
 	relPersistencePermanent = tree.NewDString("p")
+	relPersistenceTemporary = tree.NewDString("t")
 )
 
 // See: https://www.postgresql.org/docs/9.6/static/catalog-pg-class.html.
@@ -491,11 +494,17 @@ CREATE TABLE pg_catalog.pg_class (
 			func(db *sqlbase.DatabaseDescriptor, scName string, table *sqlbase.TableDescriptor) error {
 				// The only difference between tables, views and sequences is the relkind column.
 				relKind := relKindTable
-				if table.IsView() {
+				if table.MaterializedView() {
+					relKind = relKindMaterializedView
+				} else if table.IsView() {
 					relKind = relKindView
 				} else if table.IsSequence() {
 					relKind = relKindSequence
 				}
+				relPersistence := relPersistencePermanent
+				if table.IsTemporary() {
+					relPersistence = relPersistenceTemporary
+				}
 				namespaceOid := h.NamespaceOid(db, scName)
 				if err := addRow(
 					h.TableOid(db, scName, table), // oid
@@ -511,10 +520,10 @@ CREATE TABLE pg_catalog.pg_class (
 					zeroVal,                       // relallvisible
 					oidZero,                       // reltoastrelid
 					tree.MakeDBool(tree.DBool(table.IsPhysicalTable())), // relhasindex
-					tree.DBoolFalse,         // relisshared
-					relPersistencePermanent, // relPersistence
-					tree.DBoolFalse,         // relistemp
-					relKind,                 // relkind
+					tree.DBoolFalse, // relisshared
+					relPersistence,  // relPersistence
+					tree.MakeDBool(tree.DBool(table.IsTemporary())), // relistemp
+					relKind, // relkind
 					tree.NewDInt(tree.DInt(len(table.Columns))), // relnatts
 					tree.NewDInt(tree.DInt(len(table.Checks))),  // relchecks
 					tree.DBoolFalse, // relhasoids
@@ -553,9 +562,9 @@ CREATE TABLE pg_catalog.pg_class (
 						oidZero,                              // reltoastrelid
 						tree.DBoolFalse,                      // relhasindex
 						tree.DBoolFalse,                      // relisshared
-						relPersistencePermanent,              // relPersistence
-						tree.DBoolFalse,                      // relistemp
-						relKindIndex,                         // relkind
+						relPersistence,                       // relPersistence
+						tree.MakeDBool(tree.DBool(table.IsTemporary())), // relistemp
+						relKindIndex, // relkind
 						tree.NewDInt(tree.DInt(len(index.ColumnNames))), // relnatts
 						zeroVal,         // relchecks
 						tree.DBoolFalse, // relhasoids
@@ -702,6 +711,8 @@ CREATE TABLE pg_catalog.pg_constraint (
 				consrc := tree.DNull
 				conbin := tree.DNull
 				condef := tree.DNull
+				condeferrable := tree.DBoolFalse
+				condeferred := tree.DBoolFalse
 
 				// Determine constraint kind-specific fields.
 				var err error
@@ -754,6 +765,8 @@ CREATE TABLE pg_catalog.pg_constraint (
 						return err
 					}
 					condef = tree.NewDString(buf.String())
+					condeferrable = tree.MakeDBool(tree.DBool(con.FK.Deferrable))
+					condeferred = tree.MakeDBool(tree.DBool(con.FK.InitiallyDeferred))
 
 				case sqlbase.ConstraintTypeUnique:
 					oid = h.UniqueConstraintOid(db, scName, table, con.Index)
@@ -785,8 +798,8 @@ CREATE TABLE pg_catalog.pg_constraint (
 					dNameOrNull(conName), // conname
 					namespaceOid,         // connamespace
 					contype,              // contype
-					tree.DBoolFalse,      // condeferrable
-					tree.DBoolFalse,      // condeferred
+					condeferrable,        // condeferrable
+					condeferred,          // condeferred
 					tree.MakeDBool(tree.DBool(!con.Unvalidated)), // convalidated
 					tblOid,         // conrelid
 					oidZero,        // contypid
@@ -1069,9 +1082,23 @@ CREATE TABLE pg_catalog.pg_enum (
   enumsortorder FLOAT,
   enumlabel STRING
 )`,
-	populate: func(_ context.Context, p *planner, _ *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		// Enum types are not currently supported.
-		return nil
+	populate: func(ctx context.Context, p *planner, dbContext *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		h := makeOidHasher()
+		return forEachTypeDesc(ctx, p, dbContext, func(db *DatabaseDescriptor, typ *sqlbase.TypeDescriptor) error {
+			typOid := tree.NewDOid(tree.DInt(typ.DatumType().Oid()))
+			for i := range typ.EnumMembers {
+				label := typ.EnumMembers[i].LogicalRepresentation
+				if err := addRow(
+					h.EnumMemberOid(typ, label),      // oid
+					typOid,                           // enumtypid
+					tree.NewDFloat(tree.DFloat(i+1)), // enumsortorder
+					tree.NewDString(label),           // enumlabel
+				); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
 	},
 }
 
@@ -1224,6 +1251,10 @@ CREATE TABLE pg_catalog.pg_index (
 					if err != nil {
 						return err
 					}
+					indpred := tree.DNull
+					if index.IsPartial() {
+						indpred = tree.NewDString(*index.Predicate)
+					}
 					return addRow(
 						h.IndexOid(db, scName, table, index), // indexrelid
 						tableOid,                             // indrelid
@@ -1243,7 +1274,7 @@ CREATE TABLE pg_catalog.pg_index (
 						indclass,                                 // indclass
 						indoption,                                // indoption
 						tree.DNull,                               // indexprs
-						tree.DNull,                               // indpred
+						indpred,                                  // indpred
 					)
 				})
 			})
@@ -1343,6 +1374,13 @@ func indexDefFromDescriptor(
 		}
 		indexDef.Interleave = intlDef
 	}
+	if index.IsPartial() {
+		pred, err := parser.ParseExpr(*index.Predicate)
+		if err != nil {
+			return "", err
+		}
+		indexDef.Predicate = pred
+	}
 	return indexDef.String(), nil
 }
 
@@ -1982,7 +2020,6 @@ var (
 	// Avoid unused warning for constants.
 	_ = typTypeComposite
 	_ = typTypeDomain
-	_ = typTypeEnum
 	_ = typTypePseudo
 	_ = typTypeRange
 
@@ -2005,7 +2042,6 @@ var (
 
 	// Avoid unused warning for constants.
 	_ = typCategoryComposite
-	_ = typCategoryEnum
 	_ = typCategoryGeometric
 	_ = typCategoryRange
 	_ = typCategoryBitString
@@ -2052,7 +2088,7 @@ CREATE TABLE pg_catalog.pg_type (
 )`,
 	populate: func(ctx context.Context, p *planner, dbContext *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
 		h := makeOidHasher()
-		return forEachDatabaseDesc(ctx, p, dbContext, func(db *DatabaseDescriptor) error {
+		if err := forEachDatabaseDesc(ctx, p, dbContext, func(db *DatabaseDescriptor) error {
 			nspOid := h.NamespaceOid(db, pgCatalogName)
 
 			for o, typ := range types.OidToType {
@@ -2126,6 +2162,46 @@ CREATE TABLE pg_catalog.pg_type (
 				}
 			}
 			return nil
+		}); err != nil {
+			return err
+		}
+
+		// Add the user-defined types.
+		return forEachTypeDesc(ctx, p, dbContext, func(db *DatabaseDescriptor, desc *sqlbase.TypeDescriptor) error {
+			typ := desc.DatumType()
+			return addRow(
+				tree.NewDOid(tree.DInt(typ.Oid())),    // oid
+				tree.NewDName(desc.Name),              // typname
+				h.NamespaceOid(db, tree.PublicSchema), // typnamespace
+				tree.DNull,                            // typowner
+				typLen(typ),                           // typlen
+				typByVal(typ),                         // typbyval
+				typTypeEnum,                           // typtype
+				typCategory(typ),                      // typcategory
+				tree.DBoolFalse,                       // typispreferred
+				tree.DBoolTrue,                        // typisdefined
+				typDelim,                              // typdelim
+				oidZero,                               // typrelid
+				oidZero,                               // typelem
+				oidZero,                               // typarray
+				h.RegProc("enum_in"),                  // typinput
+				h.RegProc("enum_out"),                 // typoutput
+				h.RegProc("enum_recv"),                // typreceive
+				h.RegProc("enum_send"),                // typsend
+				oidZero,                               // typmodin
+				oidZero,                               // typmodout
+				oidZero,                               // typanalyze
+				tree.DNull,                            // typalign
+				tree.DNull,                            // typstorage
+				tree.DBoolFalse,                       // typnotnull
+				oidZero,                               // typbasetype
+				negOneVal,                             // typtypmod
+				zeroVal,                               // typndims
+				oidZero,                               // typcollation
+				tree.DNull,                            // typdefaultbin
+				tree.DNull,                            // typdefault
+				tree.DNull,                            // typacl
+			)
 		})
 	},
 }
@@ -2294,10 +2370,13 @@ var datumToTypeCategory = map[reflect.Type]*tree.DString{
 	reflect.TypeOf(types.String):      typCategoryString,
 	reflect.TypeOf(types.Timestamp):   typCategoryDateTime,
 	reflect.TypeOf(types.TimestampTZ): typCategoryDateTime,
+	reflect.TypeOf(types.TSQuery):     typCategoryUserDefined,
+	reflect.TypeOf(types.TSVector):    typCategoryUserDefined,
 	reflect.TypeOf(types.FamTuple):    typCategoryPseudo,
 	reflect.TypeOf(types.Oid):         typCategoryNumeric,
 	reflect.TypeOf(types.UUID):        typCategoryUserDefined,
 	reflect.TypeOf(types.INet):        typCategoryNetworkAddr,
+	reflect.TypeOf(types.AnyEnum):     typCategoryEnum,
 }
 
 func typCategory(typ types.T) tree.Datum {
@@ -2324,7 +2403,7 @@ CREATE TABLE pg_catalog.pg_views (
 		// because it does not distinguish views in separate databases.
 		return forEachTableDesc(ctx, p, dbContext, hideVirtual, /*virtual schemas do not have views*/
 			func(db *sqlbase.DatabaseDescriptor, scName string, desc *sqlbase.TableDescriptor) error {
-				if !desc.IsView() {
+				if !desc.IsView() || desc.MaterializedView() {
 					return nil
 				}
 				// Note that the view query printed will not include any column aliases
@@ -2419,6 +2498,7 @@ const (
 	userTypeTag
 	collationTypeTag
 	operatorTypeTag
+	enumMemberTypeTag
 )
 
 func (h oidHasher) writeTypeTag(tag oidTypeTag) {
@@ -2594,6 +2674,13 @@ func (h oidHasher) UserOid(username string) *tree.DOid {
 	return h.getOid()
 }
 
+func (h oidHasher) EnumMemberOid(typ *sqlbase.TypeDescriptor, label string) *tree.DOid {
+	h.writeTypeTag(enumMemberTypeTag)
+	h.writeUInt32(uint32(typ.ID))
+	h.writeStr(label)
+	return h.getOid()
+}
+
 func (h oidHasher) CollationOid(collation string) *tree.DOid {
 	h.writeTypeTag(collationTypeTag)
 	h.writeStr(collation)
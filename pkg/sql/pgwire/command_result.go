@@ -36,6 +36,7 @@ const (
 	bindComplete
 	closeComplete
 	parseComplete
+	portalSuspended
 	emptyQueryResponse
 	readyForQuery
 	flush
@@ -63,7 +64,8 @@ type commandResult struct {
 	// CommandComplete message.
 	cmdCompleteTag string
 	// If set, an error will be sent to the client if more rows are produced than
-	// this limit.
+	// this limit. The connExecutor enforces the limit itself when it can suspend
+	// the portal being executed, in which case this is never exceeded.
 	limit int
 
 	stmtType     tree.StatementType
@@ -77,6 +79,10 @@ type commandResult struct {
 	// case for queries executed through the simple protocol). Otherwise, it needs
 	// to have an entry for every column.
 	formatCodes []pgwirebase.FormatCode
+
+	// copyOut is set for COPY TO statements, whose rows are sent using the
+	// Copy-out subprotocol.
+	copyOut *copyOut
 }
 
 func (c *conn) makeCommandResult(
@@ -86,7 +92,7 @@ func (c *conn) makeCommandResult(
 	formatCodes []pgwirebase.FormatCode,
 	conv sessiondata.DataConversionConfig,
 ) commandResult {
-	return commandResult{
+	r := commandResult{
 		conn:           c,
 		pos:            pos,
 		descOpt:        descOpt,
@@ -96,6 +102,13 @@ func (c *conn) makeCommandResult(
 		cmdCompleteTag: stmt.StatementTag(),
 		conv:           conv,
 	}
+	if n, ok := stmt.(*tree.CopyTo); ok {
+		// Invalid options are reported when the statement is planned.
+		if opts, err := sql.MakeCopyToOptions(n.Options); err == nil {
+			r.copyOut = newCopyOut(opts)
+		}
+	}
+	return r
 }
 
 func (c *conn) makeMiscResult(pos sql.CmdPos, typ completionMsgType) commandResult {
@@ -113,6 +126,9 @@ func (r *commandResult) Close(t sql.TransactionStatusIndicator) {
 	}
 
 	r.conn.writerState.fi.registerCmd(r.pos)
+	if r.err == nil && r.copyOut != nil {
+		r.err = r.copyOut.err
+	}
 	if r.err != nil {
 		// TODO(andrei): I'm not sure this is the best place to do error conversion.
 		r.conn.bufferErr(convertToErrWithPGCode(r.err))
@@ -126,7 +142,7 @@ func (r *commandResult) Close(t sql.TransactionStatusIndicator) {
 		r.stmtType == tree.Rows {
 
 		r.err = pgerror.UnimplementedWithIssueErrorf(4035,
-			"execute row count limits not supported: %d of %d",
+			"execute row count limits only supported in explicit transactions: %d of %d",
 			r.limit, r.rowsAffected)
 		telemetry.RecordError(r.err)
 		r.conn.bufferErr(r.err)
@@ -135,6 +151,9 @@ func (r *commandResult) Close(t sql.TransactionStatusIndicator) {
 	// Send a completion message, specific to the type of result.
 	switch r.typ {
 	case commandComplete:
+		if r.copyOut != nil && r.copyOut.started {
+			r.copyOut.bufferDone(r.conn)
+		}
 		tag := cookTag(
 			r.cmdCompleteTag, r.conn.writerState.tagBuf[:0], r.stmtType, r.rowsAffected,
 		)
@@ -145,10 +164,13 @@ func (r *commandResult) Close(t sql.TransactionStatusIndicator) {
 		r.conn.bufferBindComplete()
 	case closeComplete:
 		r.conn.bufferCloseComplete()
+	case portalSuspended:
+		r.conn.bufferPortalSuspended()
 	case readyForQuery:
 		r.conn.bufferReadyForQuery(byte(t))
 		// The error is saved on conn.err.
 		_ /* err */ = r.conn.Flush(r.pos)
+		r.conn.setIdle(t == sql.IdleTxnBlock)
 	case emptyQueryResponse:
 		r.conn.bufferEmptyQueryResponse()
 	case flush:
@@ -213,7 +235,11 @@ func (r *commandResult) AddRow(ctx context.Context, row tree.Datums) error {
 	}
 	r.rowsAffected++
 
-	r.conn.bufferRow(ctx, row, r.formatCodes, r.conv)
+	if r.copyOut != nil {
+		r.copyOut.bufferRow(ctx, r.conn, row, r.conv)
+	} else {
+		r.conn.bufferRow(ctx, row, r.formatCodes, r.conv)
+	}
 	_ /* flushed */, err := r.conn.maybeFlush(r.pos)
 	return err
 }
@@ -221,6 +247,10 @@ func (r *commandResult) AddRow(ctx context.Context, row tree.Datums) error {
 // SetColumns is part of the CommandResult interface.
 func (r *commandResult) SetColumns(ctx context.Context, cols sqlbase.ResultColumns) {
 	r.conn.writerState.fi.registerCmd(r.pos)
+	if r.copyOut != nil {
+		r.copyOut.bufferStart(r.conn, cols)
+		return
+	}
 	if r.descOpt == sql.NeedRowDesc {
 		_ /* err */ = r.conn.writeRowDescription(ctx, cols, r.formatCodes, &r.conn.writerState.buf)
 	}
@@ -267,6 +297,11 @@ func (r *commandResult) SetLimit(n int) {
 	r.limit = n
 }
 
+// SetPortalSuspended is part of the CommandResult interface.
+func (r *commandResult) SetPortalSuspended() {
+	r.typ = portalSuspended
+}
+
 // ResetStmtType is part of the CommandResult interface.
 func (r *commandResult) ResetStmtType(stmt tree.Statement) {
 	r.stmtType = stmt.StatementType()
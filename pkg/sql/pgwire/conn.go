@@ -43,6 +43,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log/logtags"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/lib/pq/oid"
@@ -78,6 +79,12 @@ type conn struct {
 	// stmtBuf is populated with commands queued for execution by this conn.
 	stmtBuf sql.StmtBuf
 
+	// notifications holds the asynchronous notifications received by the
+	// session (see LISTEN). They are sent to the client before a ReadyForQuery
+	// message that ends a transaction, or as soon as they're received if the
+	// connection is idle. It is nil if the conn is not served by a sql.Server.
+	notifications *sql.NotificationQueue
+
 	// err is an error, accessed atomically. It represents any error encountered
 	// while accessing the underlying network connection. This can read via
 	// GetErr() by anybody. If it is found to be != nil, the conn is no longer to
@@ -92,6 +99,17 @@ type conn struct {
 		// network connection.
 		buf    bytes.Buffer
 		tagBuf [64]byte
+
+		// mu serializes the writes to the network connection between the
+		// command processor and the goroutine that delivers notifications while
+		// the connection is idle.
+		mu syncutil.Mutex
+		// idle is set when the last message sent to the client is a
+		// ReadyForQuery message outside of a transaction. Protected by mu.
+		idle bool
+		// notifyBuilder is used to build the notifications sent while the
+		// connection is idle. Protected by mu.
+		notifyBuilder writeBuffer
 	}
 
 	readBuf    pgwirebase.ReadBuffer
@@ -184,6 +202,7 @@ func newConn(
 	c.writerState.fi.lastFlushed = -1
 	c.writerState.fi.cmdStarts = make(map[sql.CmdPos]int)
 	c.msgBuilder.init(metrics.BytesOutCount)
+	c.writerState.notifyBuilder.init(metrics.BytesOutCount)
 
 	return c
 }
@@ -322,6 +341,14 @@ func (c *conn) serveImpl(
 			wg.Done()
 			cancelConn()
 		}()
+
+		c.notifications = connHandler.Notifications()
+		c.writerState.idle = true
+		wg.Add(1)
+		go func() {
+			c.deliverNotifications(ctx)
+			wg.Done()
+		}()
 	}
 
 	var err error
@@ -593,6 +620,11 @@ func (c *conn) handleParse(
 		// https://www.postgresql.org/message-id/flat/CAMsr%2BYGvp2wRx9pPSxaKFdaObxX8DzWse%2BOkWk2xpXSvT0rq-g%40mail.gmail.com#CAMsr+YGvp2wRx9pPSxaKFdaObxX8DzWse+OkWk2xpXSvT0rq-g@mail.gmail.com
 		return c.stmtBuf.Push(ctx, sql.SendError{Err: fmt.Errorf("CopyFrom not supported in extended protocol mode")})
 	}
+	if _, ok := stmt.AST.(*tree.CopyTo); ok {
+		// The rows of a portal can be fetched in several batches, which the
+		// Copy-out subprotocol can't express.
+		return c.stmtBuf.Push(ctx, sql.SendError{Err: fmt.Errorf("CopyTo not supported in extended protocol mode")})
+	}
 
 	return c.stmtBuf.Push(
 		ctx,
@@ -928,6 +960,13 @@ func (c *conn) bufferRow(
 }
 
 func (c *conn) bufferReadyForQuery(txnStatus byte) {
+	// Notifications received during a transaction are delivered once it
+	// finishes.
+	if c.notifications != nil && txnStatus == byte(sql.IdleTxnBlock) {
+		for _, n := range c.notifications.Take() {
+			c.bufferNotification(n)
+		}
+	}
 	c.msgBuilder.initMsg(pgwirebase.ServerMsgReady)
 	c.msgBuilder.writeByte(txnStatus)
 	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
@@ -935,6 +974,16 @@ func (c *conn) bufferReadyForQuery(txnStatus byte) {
 	}
 }
 
+func (c *conn) bufferNotification(n sql.Notification) {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgNotificationResponse)
+	c.msgBuilder.putInt32(n.PID)
+	c.msgBuilder.writeTerminatedString(n.Channel)
+	c.msgBuilder.writeTerminatedString(n.Payload)
+	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+		panic(fmt.Sprintf("unexpected err from buffer: %s", err))
+	}
+}
+
 func (c *conn) bufferParseComplete() {
 	c.msgBuilder.initMsg(pgwirebase.ServerMsgParseComplete)
 	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
@@ -956,6 +1005,13 @@ func (c *conn) bufferCloseComplete() {
 	}
 }
 
+func (c *conn) bufferPortalSuspended() {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgPortalSuspended)
+	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+		panic(fmt.Sprintf("unexpected err from buffer: %s", err))
+	}
+}
+
 func (c *conn) bufferCommandComplete(tag []byte) {
 	c.msgBuilder.initMsg(pgwirebase.ServerMsgCommandComplete)
 	c.msgBuilder.write(tag)
@@ -1110,6 +1166,9 @@ func (c *conn) Flush(pos sql.CmdPos) error {
 	c.writerState.fi.lastFlushed = pos
 	c.writerState.fi.cmdStarts = make(map[sql.CmdPos]int)
 
+	c.writerState.mu.Lock()
+	defer c.writerState.mu.Unlock()
+	c.writerState.idle = false
 	_ /* n */, err := c.writerState.buf.WriteTo(c.conn)
 	if err != nil {
 		c.setErr(err)
@@ -1118,6 +1177,60 @@ func (c *conn) Flush(pos sql.CmdPos) error {
 	return nil
 }
 
+// setIdle is called after a ReadyForQuery message has been flushed. It records
+// whether the connection is now idle, in which case notifications can be sent
+// to the client as soon as they are received.
+func (c *conn) setIdle(idle bool) {
+	c.writerState.mu.Lock()
+	defer c.writerState.mu.Unlock()
+	c.writerState.idle = idle
+	if idle && c.notifications != nil {
+		// Send the notifications that were received since the ReadyForQuery
+		// message was buffered.
+		_ /* err */ = c.sendNotificationsLocked()
+	}
+}
+
+// deliverNotifications sends the notifications received by the session to the
+// client whenever the connection is idle, until ctx is canceled. Notifications
+// received while the connection is busy are sent by the command processor
+// once the connection becomes idle again.
+func (c *conn) deliverNotifications(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.notifications.Ready():
+		}
+		c.writerState.mu.Lock()
+		if c.writerState.idle {
+			// The error is saved on conn.err.
+			_ /* err */ = c.sendNotificationsLocked()
+		}
+		c.writerState.mu.Unlock()
+	}
+}
+
+// sendNotificationsLocked writes the queued notifications directly to the
+// network connection. writerState.mu needs to be held.
+func (c *conn) sendNotificationsLocked() error {
+	if err := c.GetErr(); err != nil {
+		return err
+	}
+	b := &c.writerState.notifyBuilder
+	for _, n := range c.notifications.Take() {
+		b.initMsg(pgwirebase.ServerMsgNotificationResponse)
+		b.putInt32(n.PID)
+		b.writeTerminatedString(n.Channel)
+		b.writeTerminatedString(n.Payload)
+		if err := b.finishMsg(c.conn); err != nil {
+			c.setErr(err)
+			return err
+		}
+	}
+	return nil
+}
+
 // maybeFlush flushes the buffer to the network connection if it exceeded
 // connResultsBufferSizeBytes.
 func (c *conn) maybeFlush(pos sql.CmdPos) (bool, error) {
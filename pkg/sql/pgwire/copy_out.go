@@ -0,0 +1,226 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package pgwire
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// copyBinaryHeader is the signature that starts the output of a COPY in
+// binary format.
+var copyBinaryHeader = []byte("PGCOPY\n\377\r\n\000")
+
+// copyOut implements the Copy-out pgwire subprotocol (COPY ... TO STDOUT) on
+// top of a commandResult. The rows produced by the statement are not sent in
+// DataRow messages, but encoded in the requested format and sent in CopyData
+// messages, one per row. The messages are buffered and flushed like regular
+// results, so the result set is streamed to the client.
+//
+// See: https://www.postgresql.org/docs/current/static/sql-copy.html
+// and: https://www.postgresql.org/docs/current/static/protocol-flow.html#PROTOCOL-COPY
+type copyOut struct {
+	opts sql.CopyToOptions
+	// started is set once the CopyOutResponse message has been buffered.
+	started bool
+	// err is the first error encountered while encoding a row. Once set, the
+	// remaining rows are discarded and the error is sent to the client instead
+	// of the CommandComplete message.
+	err error
+	// scratch is used to format individual values in the text and CSV
+	// formats, before they are escaped.
+	scratch writeBuffer
+}
+
+func newCopyOut(opts sql.CopyToOptions) *copyOut {
+	c := &copyOut{opts: opts}
+	c.scratch.init(nil /* bytecount */)
+	return c
+}
+
+// bufferStart buffers the CopyOutResponse message, followed by the file
+// header of the binary format or the header line of the CSV format.
+func (c *copyOut) bufferStart(conn *conn, cols sqlbase.ResultColumns) {
+	c.started = true
+	format := pgwirebase.FormatText
+	if c.opts.Format == sql.CopyFormatBinary {
+		format = pgwirebase.FormatBinary
+	}
+	b := &conn.msgBuilder
+	b.initMsg(pgwirebase.ServerMsgCopyOutResponse)
+	b.writeByte(byte(format))
+	b.putInt16(int16(len(cols)))
+	for range cols {
+		b.putInt16(int16(format))
+	}
+	c.finishMsg(conn)
+
+	switch {
+	case c.opts.Format == sql.CopyFormatBinary:
+		b.initMsg(pgwirebase.ServerMsgCopyData)
+		b.write(copyBinaryHeader)
+		// Flags field, followed by the length of the header extension area.
+		b.putInt32(0)
+		b.putInt32(0)
+		c.finishMsg(conn)
+	case c.opts.Header:
+		b.initMsg(pgwirebase.ServerMsgCopyData)
+		for i := range cols {
+			if i > 0 {
+				b.writeByte(c.opts.Delimiter)
+			}
+			c.writeCSVField(b, []byte(cols[i].Name), false /* alone */)
+		}
+		b.writeByte('\n')
+		c.finishMsg(conn)
+	}
+}
+
+// bufferRow buffers a CopyData message containing one row.
+func (c *copyOut) bufferRow(
+	ctx context.Context, conn *conn, row tree.Datums, conv sessiondata.DataConversionConfig,
+) {
+	if c.err != nil {
+		return
+	}
+	b := &conn.msgBuilder
+	b.initMsg(pgwirebase.ServerMsgCopyData)
+	if c.opts.Format == sql.CopyFormatBinary {
+		b.putInt16(int16(len(row)))
+		for _, d := range row {
+			b.writeBinaryDatum(ctx, d, conv.Location)
+		}
+		c.finishMsg(conn)
+		return
+	}
+	for i, d := range row {
+		if i > 0 {
+			b.writeByte(c.opts.Delimiter)
+		}
+		if d == tree.DNull {
+			b.writeString(c.opts.Null)
+			continue
+		}
+		c.scratch.reset()
+		c.scratch.writeTextDatum(ctx, d, conv)
+		if c.scratch.err != nil {
+			b.setError(c.scratch.err)
+			break
+		}
+		// Skip the length prefix.
+		field := c.scratch.wrapped.Bytes()[4:]
+		if c.opts.Format == sql.CopyFormatCSV {
+			c.writeCSVField(b, field, len(row) == 1)
+		} else {
+			c.writeTextField(b, field)
+		}
+	}
+	b.writeByte('\n')
+	c.finishMsg(conn)
+}
+
+// bufferDone buffers the binary format trailer, if needed, and the CopyDone
+// message.
+func (c *copyOut) bufferDone(conn *conn) {
+	b := &conn.msgBuilder
+	if c.opts.Format == sql.CopyFormatBinary {
+		b.initMsg(pgwirebase.ServerMsgCopyData)
+		b.putInt16(-1)
+		c.finishMsg(conn)
+	}
+	b.initMsg(pgwirebase.ServerMsgCopyDone)
+	c.finishMsg(conn)
+}
+
+func (c *copyOut) finishMsg(conn *conn) {
+	if err := conn.msgBuilder.finishMsg(&conn.writerState.buf); err != nil && c.err == nil {
+		c.err = err
+	}
+}
+
+// writeTextField writes a value escaped as per the text format: backslashes,
+// the delimiter and control characters that have a backslash escape sequence
+// are escaped.
+func (c *copyOut) writeTextField(b *writeBuffer, field []byte) {
+	start := 0
+	for i, ch := range field {
+		var esc byte
+		switch ch {
+		case '\b':
+			esc = 'b'
+		case '\f':
+			esc = 'f'
+		case '\n':
+			esc = 'n'
+		case '\r':
+			esc = 'r'
+		case '\t':
+			esc = 't'
+		case '\v':
+			esc = 'v'
+		case '\\':
+			esc = '\\'
+		default:
+			if ch != c.opts.Delimiter {
+				continue
+			}
+			esc = ch
+		}
+		b.write(field[start:i])
+		b.writeByte('\\')
+		b.writeByte(esc)
+		start = i + 1
+	}
+	b.write(field[start:])
+}
+
+// writeCSVField writes a value as per the CSV format. The value is quoted if
+// it contains the delimiter, quotes or line breaks, or if it could be
+// mistaken for a NULL. alone is set if the value is the only one of its row,
+// in which case it also needs to be quoted if it could be mistaken for the
+// end-of-data marker.
+func (c *copyOut) writeCSVField(b *writeBuffer, field []byte, alone bool) {
+	quote := string(field) == c.opts.Null || (alone && string(field) == `\.`)
+	if !quote {
+		for _, ch := range field {
+			if ch == c.opts.Delimiter || ch == '"' || ch == '\n' || ch == '\r' {
+				quote = true
+				break
+			}
+		}
+	}
+	if !quote {
+		b.write(field)
+		return
+	}
+	b.writeByte('"')
+	for {
+		i := bytes.IndexByte(field, '"')
+		if i < 0 {
+			break
+		}
+		b.write(field[:i+1])
+		b.writeByte('"')
+		field = field[i+1:]
+	}
+	b.write(field)
+	b.writeByte('"')
+}
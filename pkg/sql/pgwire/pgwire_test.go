@@ -2307,3 +2307,282 @@ func TestCancelRequest(t *testing.T) {
 		t.Fatalf("expected 1 cancel request, got %d", count)
 	}
 }
+
+func TestPGWireExecuteLimit(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	params := base.TestServerArgs{Insecure: true}
+	s, _, _ := serverutils.StartServer(t, params)
+
+	ctx := context.TODO()
+	defer s.Stopper().Stop(ctx)
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", s.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	fe, err := pgproto3.NewFrontend(conn, conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	send := func(msgs ...pgproto3.FrontendMessage) {
+		t.Helper()
+		for _, msg := range msgs {
+			if err := fe.Send(msg); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	// receive reads messages until one that isn't a DataRow, and returns the
+	// number of DataRows and the type of that last message.
+	receive := func() (int, pgproto3.BackendMessage) {
+		t.Helper()
+		rows := 0
+		for {
+			msg, err := fe.Receive()
+			if err != nil {
+				t.Fatal(err)
+			}
+			switch msg := msg.(type) {
+			case *pgproto3.DataRow:
+				rows++
+			case *pgproto3.ParseComplete, *pgproto3.BindComplete,
+				*pgproto3.ParameterStatus, *pgproto3.BackendKeyData:
+			case *pgproto3.ErrorResponse:
+				t.Fatalf("unexpected error: %s", msg.Message)
+			default:
+				return rows, msg
+			}
+		}
+	}
+	expect := func(expRows int, expMsg pgproto3.BackendMessage) {
+		t.Helper()
+		rows, msg := receive()
+		if rows != expRows {
+			t.Fatalf("expected %d rows, got %d", expRows, rows)
+		}
+		if reflect.TypeOf(msg) != reflect.TypeOf(expMsg) {
+			t.Fatalf("expected %T, got %T", expMsg, msg)
+		}
+	}
+
+	send(&pgproto3.StartupMessage{
+		ProtocolVersion: pgproto3.ProtocolVersionNumber,
+		Parameters:      map[string]string{"user": security.RootUser},
+	})
+	expect(0, &pgproto3.Authentication{})
+	expect(0, &pgproto3.ReadyForQuery{})
+
+	send(&pgproto3.Query{String: "BEGIN"})
+	expect(0, &pgproto3.CommandComplete{})
+	expect(0, &pgproto3.ReadyForQuery{})
+
+	// Executing a portal with a row limit suspends it, and executing it again
+	// resumes it where it left off.
+	send(
+		&pgproto3.Parse{Query: "SELECT generate_series(1, 5)"},
+		&pgproto3.Bind{},
+		&pgproto3.Execute{MaxRows: 2},
+		&pgproto3.Sync{},
+	)
+	expect(2, &pgproto3.PortalSuspended{})
+	expect(0, &pgproto3.ReadyForQuery{})
+
+	send(&pgproto3.Execute{MaxRows: 2}, &pgproto3.Sync{})
+	expect(2, &pgproto3.PortalSuspended{})
+	expect(0, &pgproto3.ReadyForQuery{})
+
+	send(&pgproto3.Execute{MaxRows: 2}, &pgproto3.Sync{})
+	expect(1, &pgproto3.CommandComplete{})
+	expect(0, &pgproto3.ReadyForQuery{})
+
+	// Once exhausted, the portal returns no more rows.
+	send(&pgproto3.Execute{}, &pgproto3.Sync{})
+	expect(0, &pgproto3.CommandComplete{})
+	expect(0, &pgproto3.ReadyForQuery{})
+
+	send(&pgproto3.Query{String: "COMMIT"})
+	expect(0, &pgproto3.CommandComplete{})
+	expect(0, &pgproto3.ReadyForQuery{})
+}
+
+func TestPGWireCopyTo(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	params := base.TestServerArgs{Insecure: true}
+	s, db, _ := serverutils.StartServer(t, params)
+
+	ctx := context.TODO()
+	defer s.Stopper().Stop(ctx)
+
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `CREATE DATABASE d`)
+	sqlDB.Exec(t, `CREATE TABLE d.t (a INT PRIMARY KEY, b STRING)`)
+	sqlDB.Exec(t, `INSERT INTO d.t VALUES (1, 'x'), (2, NULL), (3, e'a\tb\\c'), (4, 'say "hi", bye'), (5, '')`)
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", s.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	fe, err := pgproto3.NewFrontend(conn, conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fe.Send(&pgproto3.StartupMessage{
+		ProtocolVersion: pgproto3.ProtocolVersionNumber,
+		Parameters:      map[string]string{"user": security.RootUser},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for {
+		msg, err := fe.Receive()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := msg.(*pgproto3.ReadyForQuery); ok {
+			break
+		}
+	}
+
+	testCases := []struct {
+		query    string
+		expected string
+		tag      string
+	}{
+		{
+			query:    `COPY d.t TO STDOUT`,
+			expected: "1\tx\n2\t\\N\n3\ta\\tb\\\\c\n4\tsay \"hi\", bye\n5\t\n",
+			tag:      "COPY 5",
+		},
+		{
+			query:    `COPY d.t (b, a) TO STDOUT WITH (FORMAT csv, HEADER)`,
+			expected: "b,a\nx,1\n,2\na\tb\\c,3\n\"say \"\"hi\"\", bye\",4\n\"\",5\n",
+			tag:      "COPY 5",
+		},
+		{
+			query:    `COPY (SELECT b FROM d.t WHERE a < 3 ORDER BY a) TO STDOUT DELIMITER '|' NULL 'n'`,
+			expected: "x\nn\n",
+			tag:      "COPY 2",
+		},
+		{
+			query:    `COPY (VALUES ('a|b', NULL)) TO STDOUT WITH (DELIMITER '|', NULL '')`,
+			expected: "a\\|b|\n",
+			tag:      "COPY 1",
+		},
+		{
+			query: `COPY (SELECT 1::INT8, NULL::INT8) TO STDOUT BINARY`,
+			expected: "PGCOPY\n\377\r\n\000" + "\000\000\000\000" + "\000\000\000\000" +
+				"\000\002" + "\000\000\000\010" + "\000\000\000\000\000\000\000\001" + "\377\377\377\377" +
+				"\377\377",
+			tag: "COPY 1",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			if err := fe.Send(&pgproto3.Query{String: tc.query}); err != nil {
+				t.Fatal(err)
+			}
+			var data []byte
+			var tag string
+			var started, done bool
+		Loop:
+			for {
+				msg, err := fe.Receive()
+				if err != nil {
+					t.Fatal(err)
+				}
+				switch msg := msg.(type) {
+				case *pgproto3.CopyOutResponse:
+					started = true
+				case *pgproto3.CopyData:
+					data = append(data, msg.Data...)
+				case *pgproto3.CopyDone:
+					done = true
+				case *pgproto3.CommandComplete:
+					tag = msg.CommandTag
+				case *pgproto3.ErrorResponse:
+					t.Fatalf("unexpected error: %s", msg.Message)
+				case *pgproto3.ReadyForQuery:
+					break Loop
+				default:
+					t.Fatalf("unexpected message %T", msg)
+				}
+			}
+			if !started || !done {
+				t.Fatalf("expected CopyOutResponse and CopyDone messages")
+			}
+			if string(data) != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, data)
+			}
+			if tag != tc.tag {
+				t.Errorf("expected tag %q, got %q", tc.tag, tag)
+			}
+		})
+	}
+}
+
+func TestPGWireNotifications(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	params := base.TestServerArgs{Insecure: true}
+	s, _, _ := serverutils.StartServer(t, params)
+
+	ctx := context.TODO()
+	defer s.Stopper().Stop(ctx)
+
+	host, ports, _ := net.SplitHostPort(s.ServingAddr())
+	port, _ := strconv.Atoi(ports)
+
+	connCfg := pgx.ConnConfig{
+		Host:      host,
+		Port:      uint16(port),
+		User:      security.RootUser,
+		TLSConfig: nil, // insecure
+		Logger:    pgxTestLogger{},
+	}
+
+	listener, err := pgx.Connect(connCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = listener.Close() }()
+	notifier, err := pgx.Connect(connCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = notifier.Close() }()
+
+	if err := listener.Listen("foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the notifications of committed transactions on the channel the
+	// listener listens on are delivered.
+	for _, stmt := range []string{
+		`BEGIN`,
+		`NOTIFY foo, 'rolled back'`,
+		`ROLLBACK`,
+		`NOTIFY bar, 'other channel'`,
+		`SELECT pg_notify('foo', 'hello')`,
+	} {
+		if _, err := notifier.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, testutils.DefaultSucceedsSoonDuration)
+	defer cancel()
+	n, err := listener.WaitForNotification(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.Channel != "foo" || n.Payload != "hello" {
+		t.Fatalf("unexpected notification: %+v", n)
+	}
+}
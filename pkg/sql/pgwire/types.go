@@ -36,6 +36,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/lib/pq/oid"
 	"github.com/pkg/errors"
 )
@@ -121,6 +122,9 @@ func (b *writeBuffer) writeTextDatum(
 	case *tree.DCollatedString:
 		b.writeLengthPrefixedString(v.Contents)
 
+	case *tree.DEnum:
+		b.writeLengthPrefixedString(v.LogicalRep)
+
 	case *tree.DDate:
 		t := timeutil.Unix(int64(*v)*secondsInDay, 0)
 		// Start at offset 4 because `putInt32` clobbers the first 4 bytes.
@@ -152,6 +156,12 @@ func (b *writeBuffer) writeTextDatum(
 	case *tree.DJSON:
 		b.writeLengthPrefixedString(v.JSON.String())
 
+	case *tree.DTSVector:
+		b.writeLengthPrefixedString(v.TSVector.String())
+
+	case *tree.DTSQuery:
+		b.writeLengthPrefixedString(v.TSQuery.String())
+
 	case *tree.DTuple:
 		b.textFormatter.FormatNode(v)
 		b.writeLengthPrefixedVariablePutbuf()
@@ -372,6 +382,9 @@ func (b *writeBuffer) writeBinaryDatum(
 	case *tree.DCollatedString:
 		b.writeLengthPrefixedString(v.Contents)
 
+	case *tree.DEnum:
+		b.writeLengthPrefixedString(v.LogicalRep)
+
 	case *tree.DTimestamp:
 		b.putInt32(8)
 		b.putInt64(timeToPgBinary(v.Time, nil))
@@ -433,6 +446,14 @@ func (b *writeBuffer) writeBinaryDatum(
 		// Postgres version number, as of writing, `1` is the only valid value.
 		b.writeByte(1)
 		b.writeString(s)
+	case *tree.DTSVector:
+		buf := tsvectorToPgBinary(v.TSVector)
+		b.putInt32(int32(len(buf)))
+		b.write(buf)
+	case *tree.DTSQuery:
+		buf := tsqueryToPgBinary(v.TSQuery)
+		b.putInt32(int32(len(buf)))
+		b.write(buf)
 	case *tree.DOid:
 		b.putInt32(4)
 		b.putInt32(int32(v.DInt))
@@ -513,3 +534,72 @@ func timeToPgBinary(t time.Time, offset *time.Location) int64 {
 func dateToPgBinary(d *tree.DDate) int32 {
 	return int32(*d) - pgwirebase.PGEpochJDateFromUnix
 }
+
+// tsvectorToPgBinary encodes a TSVector in the Postgres binary format: the
+// number of lexemes, followed by each lexeme as a NUL-terminated string with
+// its number of positions and its positions. A position is a uint16 with the
+// weight in its two high bits.
+func tsvectorToPgBinary(v tsearch.TSVector) []byte {
+	buf := make([]byte, 4, 4+v.Size())
+	binary.BigEndian.PutUint32(buf, uint32(len(v)))
+	var tmp [2]byte
+	for _, l := range v {
+		buf = append(buf, l.Word...)
+		buf = append(buf, 0)
+		binary.BigEndian.PutUint16(tmp[:], uint16(len(l.Positions)))
+		buf = append(buf, tmp[:]...)
+		for _, p := range l.Positions {
+			binary.BigEndian.PutUint16(tmp[:], uint16(p.Weight)<<14|p.Pos)
+			buf = append(buf, tmp[:]...)
+		}
+	}
+	return buf
+}
+
+// Node types and operators of the Postgres binary format of tsquery values.
+const (
+	pgTSQueryVal = 1
+	pgTSQueryOpr = 2
+
+	pgTSQueryNot = 1
+	pgTSQueryAnd = 2
+	pgTSQueryOr  = 3
+)
+
+// tsqueryToPgBinary encodes a TSQuery in the Postgres binary format: the
+// number of nodes, followed by the nodes in prefix order, with the right
+// operand of binary operators before the left one.
+func tsqueryToPgBinary(q tsearch.TSQuery) []byte {
+	buf := make([]byte, 4, 4+q.Size())
+	n := 0
+	var encode func(node *tsearch.Node)
+	encode = func(node *tsearch.Node) {
+		n++
+		switch node.Op {
+		case tsearch.OpOperand:
+			var prefix byte
+			if node.Prefix {
+				prefix = 1
+			}
+			buf = append(buf, pgTSQueryVal, node.Weights, prefix)
+			buf = append(buf, node.Word...)
+			buf = append(buf, 0)
+		case tsearch.OpNot:
+			buf = append(buf, pgTSQueryOpr, pgTSQueryNot)
+			encode(node.Left)
+		case tsearch.OpAnd, tsearch.OpOr:
+			op := byte(pgTSQueryAnd)
+			if node.Op == tsearch.OpOr {
+				op = pgTSQueryOr
+			}
+			buf = append(buf, pgTSQueryOpr, op)
+			encode(node.Right)
+			encode(node.Left)
+		}
+	}
+	if q.Root != nil {
+		encode(q.Root)
+	}
+	binary.BigEndian.PutUint32(buf, uint32(n))
+	return buf
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -69,9 +70,14 @@ func (a UncachedPhysicalAccessor) GetDatabaseDesc(
 }
 
 // IsValidSchema implements the SchemaAccessor interface.
-func (a UncachedPhysicalAccessor) IsValidSchema(dbDesc *DatabaseDescriptor, scName string) bool {
-	// At this point, only the public schema is recognized.
-	return scName == tree.PublicSchema
+func (a UncachedPhysicalAccessor) IsValidSchema(
+	ctx context.Context, txn *client.Txn, dbDesc *DatabaseDescriptor, scName string,
+) (bool, error) {
+	if scName == tree.PublicSchema || sessiondata.IsTemporarySchemaName(scName) {
+		return true, nil
+	}
+	scDesc, err := getSchemaDesc(ctx, txn, dbDesc.ID, scName)
+	return scDesc != nil, err
 }
 
 // GetObjectNames implements the SchemaAccessor interface.
@@ -82,21 +88,35 @@ func (a UncachedPhysicalAccessor) GetObjectNames(
 	scName string,
 	flags DatabaseListFlags,
 ) (TableNames, error) {
-	if ok := a.IsValidSchema(dbDesc, scName); !ok {
-		if flags.required {
-			tn := tree.MakeTableNameWithSchema(tree.Name(dbDesc.Name), tree.Name(scName), "")
-			return nil, sqlbase.NewUnsupportedSchemaUsageError(tree.ErrString(&tn.TableNamePrefix))
+	if ok, err := a.IsValidSchema(ctx, txn, dbDesc, scName); err != nil || !ok {
+		if err == nil && flags.required {
+			return nil, sqlbase.NewUndefinedSchemaError(scName)
 		}
-		return nil, nil
+		return nil, err
+	}
+
+	parentID, err := getNamespaceParentID(ctx, txn, dbDesc.ID, scName)
+	if err != nil || parentID == 0 {
+		return nil, err
 	}
 
 	log.Eventf(ctx, "fetching list of objects for %q", dbDesc.Name)
-	prefix := sqlbase.MakeNameMetadataKey(dbDesc.ID, "")
+	prefix := sqlbase.MakeNameMetadataKey(parentID, "")
 	sr, err := txn.Scan(ctx, prefix, prefix.PrefixEnd(), 0)
 	if err != nil {
 		return nil, err
 	}
 
+	// The user-defined types and schemas of the database share its namespace
+	// with the tables, so the descriptors are needed to tell them apart.
+	var nonTableIDs map[sqlbase.ID]struct{}
+	if parentID == dbDesc.ID {
+		nonTableIDs, err = getNonTableIDs(ctx, txn, sr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var tableNames tree.TableNames
 	for _, row := range sr {
 		_, tableName, err := encoding.DecodeUnsafeStringAscending(
@@ -104,7 +124,15 @@ func (a UncachedPhysicalAccessor) GetObjectNames(
 		if err != nil {
 			return nil, err
 		}
-		tn := tree.MakeTableName(tree.Name(dbDesc.Name), tree.Name(tableName))
+		if parentID == dbDesc.ID && sessiondata.IsTemporarySchemaName(tableName) {
+			// The temporary schemas of the database are not objects.
+			continue
+		}
+		if _, ok := nonTableIDs[sqlbase.ID(row.ValueInt())]; ok {
+			continue
+		}
+		tn := tree.MakeTableNameWithSchema(
+			tree.Name(dbDesc.Name), tree.Name(scName), tree.Name(tableName))
 		tn.ExplicitCatalog = flags.explicitPrefix
 		tn.ExplicitSchema = flags.explicitPrefix
 		tableNames = append(tableNames, tn)
@@ -112,18 +140,46 @@ func (a UncachedPhysicalAccessor) GetObjectNames(
 	return tableNames, nil
 }
 
+// getNonTableIDs returns the IDs of the type, schema and function descriptors
+// among the descriptors referenced by the given namespace entries.
+func getNonTableIDs(
+	ctx context.Context, txn *client.Txn, nameEntries []client.KeyValue,
+) (map[sqlbase.ID]struct{}, error) {
+	if len(nameEntries) == 0 {
+		return nil, nil
+	}
+	b := txn.NewBatch()
+	for _, row := range nameEntries {
+		b.Get(sqlbase.MakeDescMetadataKey(sqlbase.ID(row.ValueInt())))
+	}
+	if err := txn.Run(ctx, b); err != nil {
+		return nil, err
+	}
+	var ids map[sqlbase.ID]struct{}
+	for _, res := range b.Results {
+		for _, kv := range res.Rows {
+			if !kv.Exists() {
+				continue
+			}
+			var desc sqlbase.Descriptor
+			if err := kv.ValueProto(&desc); err != nil {
+				return nil, err
+			}
+			if desc.GetType() != nil || desc.GetSchema() != nil || desc.GetFunction() != nil {
+				if ids == nil {
+					ids = make(map[sqlbase.ID]struct{})
+				}
+				ids[desc.GetID()] = struct{}{}
+			}
+		}
+	}
+	return ids, nil
+}
+
 // GetObjectDesc implements the SchemaAccessor interface.
 func (a UncachedPhysicalAccessor) GetObjectDesc(
 	ctx context.Context, txn *client.Txn, name *ObjectName, flags ObjectLookupFlags,
 ) (ObjectDescriptor, *DatabaseDescriptor, error) {
-	// At this point, only the public schema is recognized.
-	if name.Schema() != tree.PublicSchema {
-		if flags.required {
-			return nil, nil, sqlbase.NewUnsupportedSchemaUsageError(tree.ErrString(name))
-		}
-		return nil, nil, nil
-	}
-
 	// Look up the database.
 	dbDesc, err := a.GetDatabaseDesc(ctx, txn, name.Catalog(), flags.CommonLookupFlags)
 	if dbDesc == nil || err != nil {
@@ -133,12 +189,18 @@ func (a UncachedPhysicalAccessor) GetObjectDesc(
 	}
 
 	// Look up the table using the discovered database descriptor.
-	desc := &sqlbase.TableDescriptor{}
-	found, err := getDescriptor(ctx, txn,
-		tableKey{parentID: dbDesc.ID, name: name.Table()}, desc)
+	parentID, err := getNamespaceParentID(ctx, txn, dbDesc.ID, name.Schema())
 	if err != nil {
 		return nil, nil, err
 	}
+	desc := &sqlbase.TableDescriptor{}
+	found := false
+	if parentID != 0 && (parentID != dbDesc.ID || !sessiondata.IsTemporarySchemaName(name.Table())) {
+		found, err = getDescriptor(ctx, txn, tableKey{parentID: parentID, name: name.Table()}, desc)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
 
 	if found {
 		// We have a descriptor. Is it in the right state? We'll keep it if
@@ -162,26 +162,35 @@ type planNodeFastPath interface {
 var _ planNode = &alterIndexNode{}
 var _ planNode = &alterSequenceNode{}
 var _ planNode = &alterTableNode{}
+var _ planNode = &alterTypeAddValueNode{}
 var _ planNode = &cancelQueriesNode{}
 var _ planNode = &cancelSessionsNode{}
 var _ planNode = &createDatabaseNode{}
 var _ planNode = &createIndexNode{}
+var _ planNode = &createSchemaNode{}
 var _ planNode = &createSequenceNode{}
 var _ planNode = &createStatsNode{}
 var _ planNode = &createTableNode{}
+var _ planNode = &createTypeNode{}
+var _ planNode = &createFunctionNode{}
 var _ planNode = &CreateUserNode{}
 var _ planNode = &createViewNode{}
+var _ planNode = &declareCursorNode{}
 var _ planNode = &delayedNode{}
 var _ planNode = &deleteNode{}
 var _ planNode = &distinctNode{}
 var _ planNode = &dropDatabaseNode{}
 var _ planNode = &dropIndexNode{}
+var _ planNode = &dropSchemaNode{}
 var _ planNode = &dropSequenceNode{}
 var _ planNode = &dropTableNode{}
+var _ planNode = &dropTypeNode{}
+var _ planNode = &dropFunctionNode{}
 var _ planNode = &DropUserNode{}
 var _ planNode = &dropViewNode{}
 var _ planNode = &explainDistSQLNode{}
 var _ planNode = &explainPlanNode{}
+var _ planNode = &fetchNode{}
 var _ planNode = &filterNode{}
 var _ planNode = &groupNode{}
 var _ planNode = &hookFnNode{}
@@ -192,6 +201,8 @@ var _ planNode = &limitNode{}
 var _ planNode = &max1RowNode{}
 var _ planNode = &ordinalityNode{}
 var _ planNode = &projectSetNode{}
+var _ planNode = &recursiveCTENode{}
+var _ planNode = &refreshViewNode{}
 var _ planNode = &relocateNode{}
 var _ planNode = &renameColumnNode{}
 var _ planNode = &renameDatabaseNode{}
@@ -203,6 +214,7 @@ var _ planNode = &scanNode{}
 var _ planNode = &scatterNode{}
 var _ planNode = &serializeNode{}
 var _ planNode = &sequenceSelectNode{}
+var _ planNode = &setConstraintsNode{}
 var _ planNode = &showFingerprintsNode{}
 var _ planNode = &showTraceNode{}
 var _ planNode = &sortNode{}
@@ -215,6 +227,7 @@ var _ planNode = &upsertNode{}
 var _ planNode = &valuesNode{}
 var _ planNode = &virtualTableNode{}
 var _ planNode = &windowNode{}
+var _ planNode = &workTableScanNode{}
 var _ planNode = &zeroNode{}
 
 var _ planNodeFastPath = &CreateUserNode{}
@@ -222,6 +235,7 @@ var _ planNodeFastPath = &DropUserNode{}
 var _ planNodeFastPath = &alterUserSetPasswordNode{}
 var _ planNodeFastPath = &createTableNode{}
 var _ planNodeFastPath = &deleteNode{}
+var _ planNodeFastPath = &fetchNode{}
 var _ planNodeFastPath = &rowCountNode{}
 var _ planNodeFastPath = &serializeNode{}
 var _ planNodeFastPath = &setZoneConfigNode{}
@@ -614,6 +628,8 @@ func (p *planner) newPlan(
 		return p.AlterTable(ctx, n)
 	case *tree.AlterSequence:
 		return p.AlterSequence(ctx, n)
+	case *tree.AlterTypeAddValue:
+		return p.AlterTypeAddValue(ctx, n)
 	case *tree.AlterUserSetPassword:
 		return p.AlterUserSetPassword(ctx, n)
 	case *tree.CancelQueries:
@@ -644,8 +660,20 @@ func (p *planner) newPlan(
 		return p.CreateSequence(ctx, n)
 	case *tree.CreateStats:
 		return p.CreateStatistics(ctx, n)
+	case *tree.CreateType:
+		return p.CreateType(ctx, n)
+	case *tree.CreateSchema:
+		return p.CreateSchema(ctx, n)
+	case *tree.CreateFunction:
+		return p.CreateFunction(ctx, n)
+	case *tree.CloseCursor:
+		return p.CloseCursor(ctx, n)
+	case *tree.CopyTo:
+		return p.CopyTo(ctx, n)
 	case *tree.Deallocate:
 		return p.Deallocate(ctx, n)
+	case *tree.DeclareCursor:
+		return p.DeclareCursor(ctx, n)
 	case *tree.Delete:
 		return p.Delete(ctx, n, desiredTypes)
 	case *tree.Discard:
@@ -660,16 +688,32 @@ func (p *planner) newPlan(
 		return p.DropView(ctx, n)
 	case *tree.DropSequence:
 		return p.DropSequence(ctx, n)
+	case *tree.DropType:
+		return p.DropType(ctx, n)
+	case *tree.DropSchema:
+		return p.DropSchema(ctx, n)
+	case *tree.DropFunction:
+		return p.DropFunction(ctx, n)
 	case *tree.DropUser:
 		return p.DropUser(ctx, n)
 	case *tree.Explain:
 		return p.Explain(ctx, n)
+	case *tree.FetchCursor:
+		return p.FetchCursor(ctx, n)
 	case *tree.Grant:
 		return p.Grant(ctx, n)
 	case *tree.Insert:
 		return p.Insert(ctx, n, desiredTypes)
+	case *tree.Listen:
+		return p.Listen(ctx, n)
+	case *tree.MoveCursor:
+		return p.MoveCursor(ctx, n)
+	case *tree.Notify:
+		return p.Notify(ctx, n)
 	case *tree.ParenSelect:
 		return p.newPlan(ctx, n.Select, desiredTypes)
+	case *tree.RefreshMaterializedView:
+		return p.RefreshMaterializedView(ctx, n)
 	case *tree.Relocate:
 		return p.Relocate(ctx, n)
 	case *tree.RenameColumn:
@@ -695,6 +739,8 @@ func (p *planner) newPlan(
 		return p.SetZoneConfig(ctx, n)
 	case *tree.SetVar:
 		return p.SetVar(ctx, n)
+	case *tree.SetConstraints:
+		return p.SetConstraints(n)
 	case *tree.SetTransaction:
 		return p.SetTransaction(n)
 	case *tree.SetSessionCharacteristics:
@@ -753,6 +799,8 @@ func (p *planner) newPlan(
 		return p.Truncate(ctx, n)
 	case *tree.UnionClause:
 		return p.Union(ctx, n, desiredTypes)
+	case *tree.Unlisten:
+		return p.Unlisten(ctx, n)
 	case *tree.Update:
 		return p.Update(ctx, n, desiredTypes)
 	case *tree.ValuesClause:
@@ -810,6 +858,8 @@ func (p *planner) doPrepare(ctx context.Context, stmt tree.Statement) (planNode,
 		return p.DropUser(ctx, n)
 	case *tree.Explain:
 		return p.Explain(ctx, n)
+	case *tree.FetchCursor:
+		return p.FetchCursor(ctx, n)
 	case *tree.Insert:
 		return p.Insert(ctx, n, nil)
 	case *tree.Scrub:
@@ -95,6 +95,8 @@ func getPlanColumns(plan planNode, mut bool) sqlbase.ResultColumns {
 		return n.columns
 	case *zigzagJoinNode:
 		return n.columns
+	case *fetchNode:
+		return n.columns
 
 	// Nodes with a fixed schema.
 	case *scrubNode:
@@ -128,6 +130,10 @@ func getPlanColumns(plan planNode, mut bool) sqlbase.ResultColumns {
 		return getPlanColumns(n.plan, mut)
 	case *spoolNode:
 		return getPlanColumns(n.source, mut)
+	case *recursiveCTENode:
+		return getPlanColumns(n.initial, mut)
+	case *workTableScanNode:
+		return getPlanColumns(n.ref.initial, mut)
 	case *serializeNode:
 		return getPlanColumns(n.source, mut)
 
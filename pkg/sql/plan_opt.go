@@ -234,6 +234,12 @@ func (opc *optPlanningCtx) buildReusableMemo(
 	if err := bld.Build(); err != nil {
 		return nil, bld.IsCorrelated, err
 	}
+	if bld.HadUserDefinedFunctions {
+		// The memo depends on the definitions of user-defined functions, which
+		// are not versioned like data sources; it cannot be reused.
+		opc.allowMemoReuse = false
+		opc.useCache = false
+	}
 	// If the memo doesn't have placeholders, then fully optimize it, since
 	// it can be reused without further changes to build the execution tree.
 	if !f.Memo().HasPlaceholders() {
@@ -341,8 +347,9 @@ func (opc *optPlanningCtx) buildExecMemo(
 
 	// If this statement doesn't have placeholders, add it to the cache. Note
 	// that non-prepared statements from pgwire clients cannot have
-	// placeholders.
-	if opc.useCache && !bld.HadPlaceholders {
+	// placeholders. Statements that call user-defined functions are never
+	// cached (see buildReusableMemo).
+	if opc.useCache && !bld.HadPlaceholders && !bld.HadUserDefinedFunctions {
 		memo := p.optimizer.DetachMemo()
 		cachedData := querycache.CachedData{
 			SQL:  opc.stmt.SQL,
@@ -96,6 +96,7 @@ func planPhysicalProps(plan planNode) physicalProps {
 	case *DropUserNode:
 	case *alterIndexNode:
 	case *alterSequenceNode:
+	case *alterTypeAddValueNode:
 	case *alterTableNode:
 	case *alterUserSetPasswordNode:
 	case *cancelQueriesNode:
@@ -104,17 +105,27 @@ func planPhysicalProps(plan planNode) physicalProps {
 	case *createDatabaseNode:
 	case *createIndexNode:
 	case *createSequenceNode:
+	case *createTypeNode:
+	case *createFunctionNode:
+	case *createSchemaNode:
 	case *createStatsNode:
 	case *createTableNode:
 	case *createViewNode:
+	case *declareCursorNode:
 	case *delayedNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
 	case *dropSequenceNode:
+	case *dropTypeNode:
+	case *dropFunctionNode:
+	case *dropSchemaNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *explainDistSQLNode:
+	case *fetchNode:
 	case *hookFnNode:
+	case *recursiveCTENode:
+	case *refreshViewNode:
 	case *relocateNode:
 	case *renameColumnNode:
 	case *renameDatabaseNode:
@@ -126,6 +137,7 @@ func planPhysicalProps(plan planNode) physicalProps {
 	case *scrubNode:
 	case *sequenceSelectNode:
 	case *setClusterSettingNode:
+	case *setConstraintsNode:
 	case *setVarNode:
 	case *setZoneConfigNode:
 	case *showFingerprintsNode:
@@ -137,6 +149,7 @@ func planPhysicalProps(plan planNode) physicalProps {
 	case *unaryNode:
 	case *valuesNode:
 	case *virtualTableNode:
+	case *workTableScanNode:
 	case *zeroNode:
 	default:
 		panic(fmt.Sprintf("unhandled node type: %T", plan))
@@ -47,6 +47,9 @@ type extendedEvalContext struct {
 
 	SessionMutator *sessionDataMutator
 
+	// SessionID is the ID of the session, used to name its temporary schema.
+	SessionID ClusterWideID
+
 	// VirtualSchemas can be used to access virtual tables.
 	VirtualSchemas VirtualTabler
 
@@ -72,6 +75,10 @@ type extendedEvalContext struct {
 
 	SchemaChangers *schemaChangerCollection
 
+	// DeferredFKChecks points to the Session's queue of the checks of the
+	// deferred foreign key constraints.
+	DeferredFKChecks *row.DeferredFKChecks
+
 	schemaAccessors *schemaInterface
 }
 
@@ -109,6 +116,12 @@ type planner struct {
 
 	preparedStatements preparedStatementsAccessor
 
+	sqlCursors sqlCursorsAccessor
+
+	// notifications is the LISTEN/NOTIFY state of the session. It is nil for
+	// internal planners.
+	notifications *sessionNotifications
+
 	// statsCollector is used to collect statistics about SQL statement execution.
 	statsCollector sqlStatsCollector
 
@@ -146,6 +159,11 @@ type planner struct {
 	// be reused for an old prepared statement after a new statement has been prepared.
 	curPlan planTop
 
+	// lockingClause is the locking clause (e.g. FOR UPDATE) of the SELECT
+	// statement being planned. It applies to the tables of the FROM clause of
+	// that statement, but not to its subqueries.
+	lockingClause tree.LockingClause
+
 	// Avoid allocations by embedding commonly used objects and visitors.
 	txCtx                 transform.ExprTransformContext
 	subqueryVisitor       subqueryVisitor
@@ -238,6 +256,8 @@ func newInternalPlanner(
 	p.semaCtx = tree.MakeSemaContext(sd.User == security.RootUser /* privileged */)
 	p.semaCtx.Location = &sd.DataConversion.Location
 	p.semaCtx.SearchPath = sd.SearchPath
+	p.semaCtx.TypeResolver = p
+	p.semaCtx.FunctionResolver = p
 
 	plannerMon := mon.MakeUnlimitedMonitor(ctx,
 		fmt.Sprintf("internal-planner.%s.%s", user, opName),
@@ -105,7 +105,7 @@ type preparedStatementsAccessor interface {
 // (but they allow one to move back and forth through the results). Our portals
 // can be used to execute a query multiple times, which is a bug (executing an
 // exhausted portal in Postres returns 0 results; in CRDB executing a portal a
-// second time always restarts the query).
+// second time restarts the query, unless the portal was suspended).
 type PreparedPortal struct {
 	Stmt  *PreparedStatement
 	Qargs tree.QueryArguments
@@ -113,6 +113,11 @@ type PreparedPortal struct {
 	// OutFormats contains the requested formats for the output columns.
 	OutFormats []pgwirebase.FormatCode
 
+	// suspended is set if the portal was executed with a row limit and had more
+	// rows to return than the limit. Executing the portal again returns the
+	// rows that come next, instead of running the query again.
+	suspended *suspendedPortal
+
 	// refCount keeps track of the number of references to this PreparedStatement.
 	// New references are registered through incRef().
 	// Once refCount hits 0 (through calls to decRef()), the following memAcc is
@@ -165,7 +170,20 @@ func (p *PreparedPortal) decRef(ctx context.Context) {
 	p.refCount--
 
 	if p.refCount == 0 {
+		p.resetSuspension(ctx)
 		p.memAcc.Close(ctx)
 		p.Stmt.decRef(ctx)
 	}
 }
+
+// resetSuspension releases the rows held by a suspended portal, if any.
+// Executing the portal again runs its query from scratch.
+func (p *PreparedPortal) resetSuspension(ctx context.Context) {
+	if p.suspended == nil {
+		return
+	}
+	if p.suspended.rows != nil {
+		p.suspended.rows.close(ctx)
+	}
+	p.suspended = nil
+}
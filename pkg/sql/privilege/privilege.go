@@ -40,6 +40,7 @@ const (
 	INSERT
 	DELETE
 	UPDATE
+	EXECUTE
 )
 
 // Predefined sets of privileges.
@@ -55,19 +56,20 @@ func (k Kind) Mask() uint32 {
 
 // ByValue is just an array of privilege kinds sorted by value.
 var ByValue = [...]Kind{
-	ALL, CREATE, DROP, GRANT, SELECT, INSERT, DELETE, UPDATE,
+	ALL, CREATE, DROP, GRANT, SELECT, INSERT, DELETE, UPDATE, EXECUTE,
 }
 
 // ByName is a map of string -> kind value.
 var ByName = map[string]Kind{
-	"ALL":    ALL,
-	"CREATE": CREATE,
-	"DROP":   DROP,
-	"GRANT":  GRANT,
-	"SELECT": SELECT,
-	"INSERT": INSERT,
-	"DELETE": DELETE,
-	"UPDATE": UPDATE,
+	"ALL":     ALL,
+	"CREATE":  CREATE,
+	"DROP":    DROP,
+	"GRANT":   GRANT,
+	"SELECT":  SELECT,
+	"INSERT":  INSERT,
+	"DELETE":  DELETE,
+	"UPDATE":  UPDATE,
+	"EXECUTE": EXECUTE,
 }
 
 // List is a list of privileges.
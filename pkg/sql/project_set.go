@@ -134,7 +134,7 @@ func (p *planner) ProjectSet(
 
 		if tFunc, ok := normalized.(*tree.FuncExpr); ok && tFunc.IsGeneratorApplication() {
 			// Set-generating functions: generate_series() etc.
-			fd, err := tFunc.Func.Resolve(p.semaCtx.SearchPath)
+			fd, err := tFunc.Func.Resolve(p.semaCtx.SearchPath, p.semaCtx.FunctionResolver)
 			if err != nil {
 				return planDataSource{}, err
 			}
@@ -0,0 +1,488 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+)
+
+// recursiveCTENode implements the logic for a recursive CTE:
+//  1. Evaluate the initial query; emit the results and also save them in
+//     a "working" table.
+//  2. So long as the working table is not empty:
+//     - evaluate the recursive query, reading the current contents of the
+//       working table (through a workTableScanNode);
+//     - emit the resulting rows, and save them as the new contents of the
+//       working table.
+//
+// For UNION (as opposed to UNION ALL), rows that were already emitted are
+// discarded before they reach the working table; this also guarantees that
+// the iteration terminates for cyclic data.
+//
+// All the rows buffered by the node are accounted for against the memory
+// monitor of the session, which bounds the size of each working table.
+//
+// There is no DistSQL processor for this node. In a DistSQL flow it is
+// wrapped like any other planNode and runs on the gateway, reading the rows
+// of the initial query from the flow; the iterations of the recursive query
+// are executed locally.
+type recursiveCTENode struct {
+	// initial is the plan for the non-recursive term.
+	initial planNode
+
+	// genIterationFn builds the plan for one iteration of the recursive
+	// term. The plan reads the working table through a workTableScanNode
+	// which references this node. It is only set when the CTE was planned by
+	// the optimizer.
+	genIterationFn exec.RecursiveCTEIterationFn
+
+	// recursiveTerm is the SQL text of the recursive term of the CTE, which
+	// is parsed and planned anew for every iteration when the CTE was planned
+	// by the heuristic planner. A fresh syntax tree is needed because planning
+	// annotates it (for example with the indexes of subqueries).
+	recursiveTerm string
+	// alias is the name of the CTE and the renaming of its columns; it is
+	// used to resolve the self-reference in recursiveTerm.
+	alias tree.AliasClause
+
+	// label is the name of the CTE, used for EXPLAIN.
+	label string
+
+	// deduplicate is set for UNION (as opposed to UNION ALL).
+	deduplicate bool
+
+	run recursiveCTERun
+}
+
+// recursiveCTERun contains the run-time state of recursiveCTENode during
+// local execution.
+type recursiveCTERun struct {
+	// workingRows contains the rows produced by the current iteration (or the
+	// initial query). These rows are emitted and then become the input of the
+	// next iteration.
+	workingRows *sqlbase.RowContainer
+	// prevRows contains the rows produced by the previous iteration; these
+	// are the rows returned by a workTableScanNode.
+	prevRows *sqlbase.RowContainer
+	// nextRowIdx is the index of the next row in workingRows to emit.
+	nextRowIdx int
+	// initialDone is set once all the rows of the initial query have been
+	// consumed.
+	initialDone bool
+	// curRow is the row most recently returned by Next.
+	curRow tree.Datums
+
+	// seen contains the encoding of all the rows emitted so far; it is only
+	// used when deduplicating.
+	seen map[string]struct{}
+	// seenAcc accounts for the memory used by seen.
+	seenAcc mon.BoundAccount
+	// scratch is a preallocated buffer used to encode rows.
+	scratch []byte
+}
+
+func (n *recursiveCTENode) startExec(params runParams) error {
+	n.run.workingRows = n.newRowContainer(params)
+	if n.deduplicate {
+		n.run.seen = make(map[string]struct{})
+		n.run.seenAcc = params.EvalContext().Mon.MakeBoundAccount()
+	}
+	return nil
+}
+
+func (n *recursiveCTENode) newRowContainer(params runParams) *sqlbase.RowContainer {
+	return sqlbase.NewRowContainer(
+		params.EvalContext().Mon.MakeBoundAccount(),
+		sqlbase.ColTypeInfoFromResCols(planColumns(n.initial)),
+		0, /* rowCapacity */
+	)
+}
+
+func (n *recursiveCTENode) Next(params runParams) (bool, error) {
+	if err := params.p.cancelChecker.Check(); err != nil {
+		return false, err
+	}
+
+	// Emit the rows of the initial query as they come, saving them in the
+	// working table along the way.
+	for !n.run.initialDone {
+		ok, err := n.initial.Next(params)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			n.run.initialDone = true
+			n.run.nextRowIdx = n.run.workingRows.Len()
+			break
+		}
+		row := n.initial.Values()
+		added, err := n.addRow(params, row)
+		if err != nil {
+			return false, err
+		}
+		if added {
+			n.run.curRow = row
+			return true, nil
+		}
+	}
+
+	for n.run.nextRowIdx >= n.run.workingRows.Len() {
+		if n.run.workingRows.Len() == 0 {
+			// The last iteration produced no rows; we are done.
+			return false, nil
+		}
+		// The rows of the working table have all been emitted; they become
+		// the input of the next iteration.
+		if n.run.prevRows != nil {
+			n.run.prevRows.Close(params.ctx)
+		}
+		n.run.prevRows = n.run.workingRows
+		n.run.workingRows = n.newRowContainer(params)
+		n.run.nextRowIdx = 0
+		if err := n.runIteration(params); err != nil {
+			return false, err
+		}
+	}
+
+	n.run.curRow = n.run.workingRows.At(n.run.nextRowIdx)
+	n.run.nextRowIdx++
+	return true, nil
+}
+
+// runIteration evaluates the recursive query once, against the current
+// contents of prevRows, and saves the new rows in workingRows.
+func (n *recursiveCTENode) runIteration(params runParams) error {
+	var p *planTop
+	if n.genIterationFn != nil {
+		plan, err := n.genIterationFn(n)
+		if err != nil {
+			return err
+		}
+		p = plan.(*planTop)
+	} else {
+		var err error
+		if p, err = params.p.newRecursiveCTEIteration(params.ctx, n); err != nil {
+			return err
+		}
+	}
+
+	// The subqueries of the iteration are numbered after those of the
+	// statement, so they are appended to the statement's subqueries (where
+	// EvalSubquery looks them up) for the duration of the iteration.
+	curPlan := &params.p.curPlan
+	prevSubqueries := curPlan.subqueryPlans
+	p.subqueryPlans = append(prevSubqueries[:len(prevSubqueries):len(prevSubqueries)], p.subqueryPlans...)
+	curPlan.subqueryPlans = p.subqueryPlans
+	defer func() {
+		p.subqueryPlans = p.subqueryPlans[len(prevSubqueries):]
+		p.close(params.ctx)
+		curPlan.subqueryPlans = prevSubqueries
+	}()
+
+	if err := p.start(params); err != nil {
+		return err
+	}
+	for {
+		if err := params.p.cancelChecker.Check(); err != nil {
+			return err
+		}
+		ok, err := p.plan.Next(params)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		if _, err := n.addRow(params, p.plan.Values()); err != nil {
+			return err
+		}
+	}
+}
+
+// addRow saves the given row in the working table, unless we are
+// deduplicating and the row was seen before. Returns true if the row was
+// added.
+func (n *recursiveCTENode) addRow(params runParams, row tree.Datums) (bool, error) {
+	if n.deduplicate {
+		var err error
+		n.run.scratch, err = sqlbase.EncodeDatumsKeyAscending(n.run.scratch[:0], row)
+		if err != nil {
+			return false, err
+		}
+		if _, ok := n.run.seen[string(n.run.scratch)]; ok {
+			return false, nil
+		}
+		if err := n.run.seenAcc.Grow(params.ctx, int64(len(n.run.scratch))); err != nil {
+			return false, err
+		}
+		n.run.seen[string(n.run.scratch)] = struct{}{}
+	}
+	if _, err := n.run.workingRows.AddRow(params.ctx, row); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (n *recursiveCTENode) Values() tree.Datums {
+	return n.run.curRow
+}
+
+func (n *recursiveCTENode) Close(ctx context.Context) {
+	n.initial.Close(ctx)
+	if n.run.workingRows != nil {
+		n.run.workingRows.Close(ctx)
+		n.run.workingRows = nil
+	}
+	if n.run.prevRows != nil {
+		n.run.prevRows.Close(ctx)
+		n.run.prevRows = nil
+	}
+	if n.deduplicate {
+		n.run.seenAcc.Close(ctx)
+		n.run.seen = nil
+	}
+}
+
+// newRecursiveCTEPlan plans a CTE that appears in a WITH RECURSIVE clause.
+// If the CTE refers to itself, it must have the form:
+//
+//   <initial query> UNION [ALL] <recursive query>
+//
+// where only the recursive query contains the (single) self-reference; the
+// result is then a recursiveCTENode. A CTE that does not refer to itself is
+// planned like a regular CTE. The rules and errors are the same as in
+// buildRecursiveCTE in opt/optbuilder.
+func (p *planner) newRecursiveCTEPlan(ctx context.Context, cte *tree.CTE) (planNode, error) {
+	name := cte.Name.Alias
+
+	clause, ok := recursiveCTEUnion(cte.Stmt)
+	if !ok {
+		// The CTE isn't in the form that allows recursion, so any reference to
+		// itself is an error.
+		plan, _, err := p.newPlanWithCTERef(ctx, cte.Stmt, name, cteSource{
+			refErr: pgerror.NewErrorf(pgerror.CodeInvalidRecursionError,
+				"recursive query %q does not have the form non-recursive-term UNION [ALL] recursive-term",
+				tree.ErrString(&name),
+			),
+		})
+		return plan, err
+	}
+
+	// Plan the initial query, which may not refer to the CTE.
+	initial, _, err := p.newPlanWithCTERef(ctx, clause.Left, name, cteSource{
+		refErr: pgerror.NewErrorf(pgerror.CodeInvalidRecursionError,
+			"recursive reference to query %q must not appear within its non-recursive term",
+			tree.ErrString(&name),
+		),
+	})
+	if err != nil {
+		return nil, err
+	}
+	node := &recursiveCTENode{
+		initial:       initial,
+		recursiveTerm: tree.AsStringWithFlags(clause.Right, tree.FmtParsable),
+		alias:         cte.Name,
+		label:         string(name),
+		deduplicate:   !clause.All,
+	}
+
+	// Plan the recursive query once to check whether it refers to the CTE,
+	// and to check its columns.
+	prevSubqueries := p.curPlan.subqueryPlans
+	numUsed := p.curPlan.cteNameEnvironment.numUsed()
+	recursive, selfRef, err := p.newPlanWithCTERef(
+		ctx, clause.Right, name, cteSource{alias: cte.Name, recursive: node},
+	)
+	if err != nil {
+		initial.Close(ctx)
+		return nil, err
+	}
+	if !selfRef {
+		// The CTE doesn't refer to itself, so plan it as a regular UNION.
+		return p.newUnionNode(clause.Type, clause.All, initial, recursive)
+	}
+
+	// The recursive query is re-planned for every iteration, so this plan
+	// and its subqueries are never run.
+	defer func() {
+		recursive.Close(ctx)
+		for _, s := range p.curPlan.subqueryPlans[len(prevSubqueries):] {
+			s.plan.Close(ctx)
+		}
+		p.curPlan.subqueryPlans = prevSubqueries
+	}()
+
+	if p.curPlan.cteNameEnvironment.numUsed() != numUsed {
+		// The recursive query is re-planned for every iteration, which doesn't
+		// work with the single-use plans of the other CTEs.
+		initial.Close(ctx)
+		return nil, pgerror.UnimplementedWithIssueErrorf(21085,
+			"references to other CTEs in the recursive term of recursive query %q "+
+				"are only supported by the cost-based optimizer",
+			tree.ErrString(&name))
+	}
+
+	initialCols := planColumns(initial)
+	recursiveCols := planColumns(recursive)
+	if len(recursiveCols) != len(initialCols) {
+		initial.Close(ctx)
+		return nil, pgerror.NewErrorf(
+			pgerror.CodeSyntaxError,
+			"each UNION query must have the same number of columns: %d vs %d",
+			len(initialCols), len(recursiveCols),
+		)
+	}
+	for i := range recursiveCols {
+		l, r := initialCols[i].Typ, recursiveCols[i].Typ
+		if l == types.Unknown {
+			initial.Close(ctx)
+			return nil, pgerror.NewErrorf(pgerror.CodeDatatypeMismatchError,
+				"could not determine type of column %d of recursive query %q",
+				i+1, tree.ErrString(&name),
+			)
+		}
+		if !(l.Equivalent(r) || r == types.Unknown) {
+			initial.Close(ctx)
+			return nil, pgerror.NewErrorf(pgerror.CodeDatatypeMismatchError,
+				"recursive query %q column %d has type %s in non-recursive term but type %s overall",
+				tree.ErrString(&name), i+1, l, r,
+			)
+		}
+	}
+	return node, nil
+}
+
+// newRecursiveCTEIteration plans one iteration of the recursive term of a
+// recursive CTE planned by newRecursiveCTEPlan. The subqueries of the
+// iteration are returned in the planTop instead of being added to the
+// statement's subqueries.
+func (p *planner) newRecursiveCTEIteration(
+	ctx context.Context, n *recursiveCTENode,
+) (_ *planTop, err error) {
+	prevSubqueries := p.curPlan.subqueryPlans
+	res := &planTop{}
+	defer func() {
+		res.subqueryPlans = append([]subquery(nil), p.curPlan.subqueryPlans[len(prevSubqueries):]...)
+		p.curPlan.subqueryPlans = prevSubqueries
+		if err != nil {
+			res.close(ctx)
+		}
+	}()
+
+	// The recursive term doesn't refer to any CTE other than the one being
+	// defined (see newRecursiveCTEPlan), so that is the only one it needs to
+	// see.
+	defer func(env cteNameEnvironment) { p.curPlan.cteNameEnvironment = env }(
+		p.curPlan.cteNameEnvironment,
+	)
+	p.curPlan.cteNameEnvironment = nil
+	stmt, err := parser.ParseOne(n.recursiveTerm)
+	if err != nil {
+		return nil, err
+	}
+	res.plan, _, err = p.newPlanWithCTERef(
+		ctx, stmt.AST, n.alias.Alias, cteSource{alias: n.alias, recursive: n},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if res.plan, err = p.optimizePlan(ctx, res.plan, allColumns(res.plan)); err != nil {
+		return nil, err
+	}
+	for i := len(prevSubqueries); i < len(p.curPlan.subqueryPlans); i++ {
+		if err := p.optimizeSubquery(ctx, &p.curPlan.subqueryPlans[i]); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// newPlanWithCTERef plans the given statement in an environment where the
+// CTE with the given name resolves to src. It also returns whether the
+// statement referred to the CTE.
+func (p *planner) newPlanWithCTERef(
+	ctx context.Context, stmt tree.Statement, name tree.Name, src cteSource,
+) (_ planNode, used bool, _ error) {
+	frame := cteNameEnvironmentFrame{name: src}
+	p.curPlan.cteNameEnvironment = p.curPlan.cteNameEnvironment.push(frame)
+	defer func() { p.curPlan.cteNameEnvironment = p.curPlan.cteNameEnvironment.pop() }()
+
+	plan, err := p.newPlan(ctx, stmt, nil /* desiredTypes */)
+	return plan, frame[name].used, err
+}
+
+// recursiveCTEUnion returns the UNION or UNION ALL clause that defines a
+// recursive CTE, or ok=false if the statement doesn't have that form.
+func recursiveCTEUnion(stmt tree.Statement) (clause *tree.UnionClause, ok bool) {
+	sel, ok := stmt.(*tree.Select)
+	if !ok {
+		return nil, false
+	}
+	for {
+		if sel.With != nil || sel.OrderBy != nil || sel.Limit != nil {
+			return nil, false
+		}
+		paren, ok := sel.Select.(*tree.ParenSelect)
+		if !ok {
+			break
+		}
+		sel = paren.Select
+	}
+	clause, ok = sel.Select.(*tree.UnionClause)
+	if !ok || clause.Type != tree.UnionOp {
+		return nil, false
+	}
+	return clause, true
+}
+
+// workTableScanNode returns the contents of the working table of a
+// recursiveCTENode, i.e. the rows produced by its previous iteration.
+type workTableScanNode struct {
+	// ref is the recursive CTE whose working table we are scanning.
+	ref *recursiveCTENode
+
+	// label is the name of the CTE, used for EXPLAIN.
+	label string
+
+	// nextRowIdx is the index of the next row to return.
+	nextRowIdx int
+}
+
+func (n *workTableScanNode) startExec(params runParams) error {
+	n.nextRowIdx = 0
+	return nil
+}
+
+func (n *workTableScanNode) Next(params runParams) (bool, error) {
+	if n.nextRowIdx >= n.ref.run.prevRows.Len() {
+		return false, nil
+	}
+	n.nextRowIdx++
+	return true, nil
+}
+
+func (n *workTableScanNode) Values() tree.Datums {
+	return n.ref.run.prevRows.At(n.nextRowIdx - 1)
+}
+
+func (n *workTableScanNode) Close(ctx context.Context) {}
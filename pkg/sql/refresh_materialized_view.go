@@ -0,0 +1,396 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/transform"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/pkg/errors"
+)
+
+type refreshViewNode struct {
+	n    *tree.RefreshMaterializedView
+	desc *sqlbase.MutableTableDescriptor
+	// sourcePlan computes the new contents of a view created in the same
+	// transaction. It is nil otherwise.
+	sourcePlan planNode
+}
+
+// RefreshMaterializedView recomputes the contents of a materialized view.
+// Privileges: DROP on view.
+//   Notes: postgres requires ownership of the view.
+func (p *planner) RefreshMaterializedView(
+	ctx context.Context, n *tree.RefreshMaterializedView,
+) (planNode, error) {
+	desc, err := p.ResolveMutableTableDescriptor(ctx, &n.Name, true /* required */, requireViewDesc)
+	if err != nil {
+		return nil, err
+	}
+	if !desc.MaterializedView() {
+		return nil, sqlbase.NewWrongObjectTypeError(&n.Name, "materialized view")
+	}
+	if err := p.CheckPrivilege(ctx, desc, privilege.DROP); err != nil {
+		return nil, err
+	}
+	// The refresh copies the indexes of the view, which must not change
+	// until it is complete.
+	if len(desc.Mutations) > 0 {
+		return nil, pgerror.NewErrorf(pgerror.CodeObjectNotInPrerequisiteStateError,
+			"materialized view %q is being changed; try again later", tree.ErrString(&n.Name))
+	}
+	if !desc.IsNewTable() {
+		return &refreshViewNode{n: n, desc: desc}, nil
+	}
+	sel, err := parseMaterializedViewQuery(desc.TableDesc())
+	if err != nil {
+		return nil, err
+	}
+	sourcePlan, err := p.planMaterializedViewQuery(ctx, sel)
+	if err != nil {
+		return nil, err
+	}
+	return &refreshViewNode{n: n, desc: desc, sourcePlan: sourcePlan}, nil
+}
+
+func (n *refreshViewNode) startExec(params runParams) error {
+	p := params.p
+	ctx := params.ctx
+
+	if n.sourcePlan != nil {
+		// The view was created in this transaction, so its contents are not
+		// visible to anyone else yet and can be rewritten in place.
+		span := n.desc.TableSpan()
+		traceKV := p.extendedEvalCtx.Tracing.KVTracingEnabled()
+		if traceKV {
+			log.VEventf(ctx, 2, "DelRange %s - %s", span.Key, span.EndKey)
+		}
+		b := p.txn.NewBatch()
+		b.DelRange(span.Key, span.EndKey, false /* returnKeys */)
+		if err := p.txn.Run(ctx, b); err != nil {
+			return err
+		}
+		if err := insertMaterializedViewRows(params, n.desc, n.sourcePlan); err != nil {
+			return err
+		}
+	} else {
+		// Otherwise, the schema changer runs the query into a new set of
+		// indexes and then swaps them with the indexes of the view, which
+		// keeps its ID. The old contents remain visible until then, and are
+		// GC-ed like the data of dropped indexes.
+		n.desc.AddMaterializedViewRefreshMutation()
+		mutationID, err := p.createOrUpdateSchemaChangeJob(ctx, n.desc,
+			tree.AsStringWithFlags(n.n, tree.FmtAlwaysQualifyTableNames))
+		if err != nil {
+			return err
+		}
+		if err := p.writeSchemaChange(ctx, n.desc, mutationID); err != nil {
+			return err
+		}
+	}
+
+	// Log a Refresh Materialized View event. This is an auditable log event
+	// and is recorded in the same transaction as the table descriptor update.
+	return MakeEventLogger(p.extendedEvalCtx.ExecCfg).InsertEventRecord(
+		ctx,
+		p.txn,
+		EventLogRefreshMaterializedView,
+		int32(n.desc.ID),
+		int32(p.extendedEvalCtx.NodeID),
+		struct {
+			ViewName  string
+			Statement string
+			User      string
+		}{n.n.Name.FQString(), n.n.String(), p.SessionData().User},
+	)
+}
+
+func (*refreshViewNode) Next(runParams) (bool, error) { return false, nil }
+func (*refreshViewNode) Values() tree.Datums          { return tree.Datums{} }
+
+func (n *refreshViewNode) Close(ctx context.Context) {
+	if n.sourcePlan != nil {
+		n.sourcePlan.Close(ctx)
+		n.sourcePlan = nil
+	}
+}
+
+// parseMaterializedViewQuery parses the query of a materialized view.
+func parseMaterializedViewQuery(desc *sqlbase.TableDescriptor) (*tree.Select, error) {
+	stmt, err := parser.ParseOne(desc.ViewQuery)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse underlying query from view %q", desc.Name)
+	}
+	sel, ok := stmt.AST.(*tree.Select)
+	if !ok {
+		return nil, errors.Errorf("failed to parse underlying query from view %q as a select", desc.Name)
+	}
+	return sel, nil
+}
+
+// planMaterializedViewQuery plans the query of a materialized view. The
+// resulting plan is run by the CREATE or REFRESH statement to populate the
+// view, in the same way that CREATE TABLE AS populates its table.
+func (p *planner) planMaterializedViewQuery(
+	ctx context.Context, viewSelect *tree.Select,
+) (sourcePlan planNode, err error) {
+	// As for CREATE VIEW, use the most recent versions of the descriptors
+	// rather than the copies in the lease cache.
+	p.runWithOptions(resolveFlags{skipCache: true}, func() {
+		sourcePlan, err = p.Select(ctx, viewSelect, []types.T{})
+	})
+	return sourcePlan, err
+}
+
+// materializedViewRowBuilder builds the rows of a materialized view from the
+// rows of its query. The columns of the view are filled from the columns of
+// the query, in order, except for the hidden rowid column that forms the
+// primary key of the view, which is synthesized from its default expression.
+type materializedViewRowBuilder struct {
+	pkColIdx  int
+	pkDefExpr tree.TypedExpr
+}
+
+func makeMaterializedViewRowBuilder(
+	desc *sqlbase.TableDescriptor, txCtx *transform.ExprTransformContext, evalCtx *tree.EvalContext,
+) (materializedViewRowBuilder, error) {
+	pkColIdx := desc.ColumnIdxMap()[desc.PrimaryIndex.ColumnIDs[0]]
+	defExprs, err := sqlbase.MakeDefaultExprs(desc.Columns[pkColIdx:pkColIdx+1], txCtx, evalCtx)
+	if err != nil {
+		return materializedViewRowBuilder{}, err
+	}
+	return materializedViewRowBuilder{pkColIdx: pkColIdx, pkDefExpr: defExprs[0]}, nil
+}
+
+// buildRow fills rowBuffer with the row of the view for the row values of
+// the query.
+func (b *materializedViewRowBuilder) buildRow(
+	rowBuffer, values tree.Datums, evalCtx *tree.EvalContext,
+) error {
+	copy(rowBuffer, values[:b.pkColIdx])
+	copy(rowBuffer[b.pkColIdx+1:], values[b.pkColIdx:])
+	var err error
+	rowBuffer[b.pkColIdx], err = b.pkDefExpr.Eval(evalCtx)
+	return err
+}
+
+// insertMaterializedViewRows writes the rows produced by sourcePlan into the
+// indexes of an empty materialized view. Like CREATE TABLE AS, this is a
+// simplified version of the INSERT logic.
+func insertMaterializedViewRows(
+	params runParams, desc *sqlbase.MutableTableDescriptor, sourcePlan planNode,
+) error {
+	p := params.p
+	traceKV := params.extendedEvalCtx.Tracing.KVTracingEnabled()
+	ri, err := row.MakeInserter(
+		p.txn,
+		sqlbase.NewImmutableTableDescriptor(*desc.TableDesc()),
+		nil,
+		desc.Columns,
+		row.SkipFKs,
+		&p.alloc)
+	if err != nil {
+		return err
+	}
+	ti := tableInserterPool.Get().(*tableInserter)
+	*ti = tableInserter{ri: ri}
+	tw := tableWriter(ti)
+	defer func() {
+		tw.close(params.ctx)
+		*ti = tableInserter{}
+		tableInserterPool.Put(ti)
+	}()
+	if err := tw.init(p.txn, p.EvalContext()); err != nil {
+		return err
+	}
+
+	rb, err := makeMaterializedViewRowBuilder(desc.TableDesc(), &p.txCtx, p.EvalContext())
+	if err != nil {
+		return err
+	}
+	rowBuffer := make(tree.Datums, len(desc.Columns))
+	rowsAffected := 0
+	for {
+		if err := p.cancelChecker.Check(); err != nil {
+			return err
+		}
+		if next, err := sourcePlan.Next(params); !next {
+			if err != nil {
+				return err
+			}
+			break
+		}
+		if err := rb.buildRow(rowBuffer, sourcePlan.Values(), p.EvalContext()); err != nil {
+			return err
+		}
+		if err := tw.row(params.ctx, rowBuffer, traceKV); err != nil {
+			return err
+		}
+		rowsAffected++
+	}
+	if _, err := tw.finalize(params.ctx, noAutoCommit, traceKV); err != nil {
+		return err
+	}
+
+	// Initiate a run of CREATE STATISTICS.
+	params.ExecCfg().StatsRefresher.NotifyMutation(
+		&params.EvalContext().Settings.SV, desc.ID, rowsAffected)
+	return nil
+}
+
+// refreshMaterializedView runs the query of a materialized view into the new
+// indexes of a refresh, which replace the indexes of the view when the
+// mutation completes. The query is run at a fixed timestamp, and its rows
+// are written in chunks by separate transactions. The new indexes are only
+// used by the refresh, so they are cleared at the start of every attempt.
+func (sc *SchemaChanger) refreshMaterializedView(
+	ctx context.Context,
+	lease *sqlbase.TableDescriptor_SchemaChangeLease,
+	tableDesc *sqlbase.TableDescriptor,
+	refresh *sqlbase.MaterializedViewRefresh,
+) error {
+	// The rows are written through a copy of the view that only has the new
+	// indexes.
+	newDesc := protoutil.Clone(tableDesc).(*sqlbase.TableDescriptor)
+	newDesc.PrimaryIndex = refresh.NewPrimaryIndex
+	newDesc.Indexes = refresh.NewIndexes
+	newDesc.Mutations = nil
+	immutDesc := sqlbase.NewImmutableTableDescriptor(*newDesc)
+
+	sel, err := parseMaterializedViewQuery(tableDesc)
+	if err != nil {
+		return err
+	}
+	chunkSize := sc.getChunkSize(materializedViewRefreshChunkSize)
+	return sc.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		// ClearRange cannot be run in a transaction, so create a
+		// non-transactional batch to send the requests.
+		b := &client.Batch{}
+		for _, idx := range newDesc.AllNonDropIndexes() {
+			sp := newDesc.IndexSpan(idx.ID)
+			b.AddRawRequest(&roachpb.ClearRangeRequest{
+				RequestHeader: roachpb.RequestHeader{
+					Key:    sp.Key,
+					EndKey: sp.EndKey,
+				},
+			})
+		}
+		if err := sc.db.Run(ctx, b); err != nil {
+			return err
+		}
+		txn.SetFixedTimestamp(ctx, sc.clock.Now())
+
+		p, cleanup := newInternalPlanner(
+			"refresh materialized view", txn, security.RootUser, &MemoryMetrics{}, sc.execCfg,
+		)
+		defer cleanup()
+		rows, err := p.planMaterializedViewQuery(ctx, sel)
+		if err != nil {
+			return err
+		}
+		rows, err = p.optimizePlan(ctx, rows, allColumns(rows))
+		if err != nil {
+			return err
+		}
+		defer rows.Close(ctx)
+		params := runParams{
+			ctx:             ctx,
+			extendedEvalCtx: &p.extendedEvalCtx,
+			p:               p,
+		}
+		if err := startPlan(params, rows); err != nil {
+			return err
+		}
+		rb, err := makeMaterializedViewRowBuilder(newDesc, &p.txCtx, p.EvalContext())
+		if err != nil {
+			return err
+		}
+
+		var alloc sqlbase.DatumAlloc
+		chunk := make([]tree.Datums, 0, chunkSize)
+		rowsAffected := 0
+		writeChunk := func() error {
+			// First extend the schema change lease.
+			if err := sc.ExtendLease(ctx, lease); err != nil {
+				return err
+			}
+			if err := sc.db.Txn(ctx, func(ctx context.Context, writeTxn *client.Txn) error {
+				ri, err := row.MakeInserter(
+					writeTxn, immutDesc, nil, newDesc.Columns, row.SkipFKs, &alloc,
+				)
+				if err != nil {
+					return err
+				}
+				ti := tableInserter{ri: ri}
+				defer ti.close(ctx)
+				if err := ti.init(writeTxn, p.EvalContext()); err != nil {
+					return err
+				}
+				for _, r := range chunk {
+					if err := ti.row(ctx, r, false /* traceKV */); err != nil {
+						return err
+					}
+				}
+				_, err = ti.finalize(ctx, noAutoCommit, false /* traceKV */)
+				return err
+			}); err != nil {
+				return err
+			}
+			rowsAffected += len(chunk)
+			chunk = chunk[:0]
+			return nil
+		}
+		for {
+			next, err := rows.Next(params)
+			if err != nil {
+				return err
+			}
+			if !next {
+				break
+			}
+			rowBuffer := make(tree.Datums, len(newDesc.Columns))
+			if err := rb.buildRow(rowBuffer, rows.Values(), p.EvalContext()); err != nil {
+				return err
+			}
+			chunk = append(chunk, rowBuffer)
+			if int64(len(chunk)) == chunkSize {
+				if err := writeChunk(); err != nil {
+					return err
+				}
+			}
+		}
+		if len(chunk) > 0 {
+			if err := writeChunk(); err != nil {
+				return err
+			}
+		}
+
+		// Initiate a run of CREATE STATISTICS.
+		sc.execCfg.StatsRefresher.NotifyMutation(&sc.settings.SV, sc.tableID, rowsAffected)
+		return nil
+	})
+}
@@ -67,6 +67,9 @@ func (n *renameColumnNode) startExec(params runParams) error {
 	if err != nil {
 		return err
 	}
+	if err := tableDesc.CheckColumnNotBeingSwapped(&col); err != nil {
+		return err
+	}
 
 	for _, tableRef := range tableDesc.DependedOnBy {
 		found := false
@@ -140,6 +143,17 @@ func (n *renameColumnNode) startExec(params runParams) error {
 		}
 	}
 
+	// Rename the column in the predicates of partial indexes.
+	for _, idx := range tableDesc.AllNonDropIndexes() {
+		if idx.IsPartial() {
+			newExpr, err := renameIn(*idx.Predicate)
+			if err != nil {
+				return err
+			}
+			idx.Predicate = &newExpr
+		}
+	}
+
 	// Rename the column in the indexes.
 	tableDesc.RenameColumnDescriptor(col, string(n.n.NewName))
 
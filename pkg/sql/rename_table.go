@@ -20,8 +20,10 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 )
@@ -88,6 +90,20 @@ func (n *renameTableNode) startExec(params runParams) error {
 		return err
 	}
 
+	if tableDesc.IsTemporary() && !newTn.ExplicitSchema {
+		// Temporary objects are renamed within their temporary schema.
+		newTn.CatalogName = oldTn.CatalogName
+		newTn.ExplicitCatalog = true
+		newTn.SchemaName = sessiondata.PgTempSchemaName
+		newTn.ExplicitSchema = true
+	} else if tableDesc.SchemaID != 0 && !newTn.ExplicitSchema {
+		// The objects of user-defined schemas are renamed within their schema.
+		newTn.CatalogName = oldTn.CatalogName
+		newTn.ExplicitCatalog = true
+		newTn.SchemaName = oldTn.SchemaName
+		newTn.ExplicitSchema = true
+	}
+
 	// Check if target database exists.
 	// We also look at uncached descriptors here.
 	targetDbDesc, err := p.ResolveUncachedDatabase(ctx, newTn)
@@ -95,23 +111,40 @@ func (n *renameTableNode) startExec(params runParams) error {
 		return err
 	}
 
-	if err := p.CheckPrivilege(ctx, targetDbDesc, privilege.CREATE); err != nil {
+	if tableDesc.IsTemporary() != sessiondata.IsTemporarySchemaName(newTn.Schema()) {
+		return pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+			"cannot move objects into or out of temporary schemas")
+	}
+	if tableDesc.IsTemporary() && targetDbDesc.ID != prevDbDesc.ID {
+		return pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+			"cannot move temporary objects to another database")
+	}
+
+	if err := p.checkCreatePrivilege(ctx, targetDbDesc, newTn); err != nil {
 		return err
 	}
 
 	// oldTn and newTn are already normalized, so we can compare directly here.
+	// The temporary schema of the session may be named pg_temp.
 	if oldTn.Catalog() == newTn.Catalog() &&
-		oldTn.Schema() == newTn.Schema() &&
+		(oldTn.Schema() == newTn.Schema() || tableDesc.IsTemporary()) &&
 		oldTn.Table() == newTn.Table() {
 		// Noop.
 		return nil
 	}
 
+	prevParentID := tableDesc.GetNamespaceParentID()
+	newParentID, _, scDesc, err := p.getCreateParentID(ctx, targetDbDesc.ID, newTn)
+	if err != nil {
+		return err
+	}
+
 	tableDesc.SetName(newTn.Table())
 	tableDesc.ParentID = targetDbDesc.ID
+	tableDesc.SchemaID = scDesc.GetID()
 
 	descKey := sqlbase.MakeDescMetadataKey(tableDesc.GetID())
-	newTbKey := tableKey{targetDbDesc.ID, newTn.Table()}.Key()
+	newTbKey := tableKey{newParentID, newTn.Table()}.Key()
 
 	if err := tableDesc.Validate(ctx, p.txn, p.EvalContext().Settings); err != nil {
 		return err
@@ -121,7 +154,7 @@ func (n *renameTableNode) startExec(params runParams) error {
 	descDesc := sqlbase.WrapDescriptor(tableDesc)
 
 	renameDetails := sqlbase.TableDescriptor_NameInfo{
-		ParentID: prevDbDesc.ID,
+		ParentID: prevParentID,
 		Name:     oldTn.Table()}
 	tableDesc.DrainingNames = append(tableDesc.DrainingNames, renameDetails)
 	if err := p.writeSchemaChange(ctx, tableDesc, sqlbase.InvalidMutationID); err != nil {
@@ -98,6 +98,7 @@ func (p *planner) Select(
 	limit := n.Limit
 	orderBy := n.OrderBy
 	with := n.With
+	locking := n.Locking
 
 	for s, ok := wrapped.(*tree.ParenSelect); ok; s, ok = wrapped.(*tree.ParenSelect) {
 		wrapped = s.Select.Select
@@ -124,10 +125,20 @@ func (p *planner) Select(
 			}
 			limit = s.Select.Limit
 		}
+		locking = append(locking, s.Select.Locking...)
+	}
+	if err := checkLockingStrengths(locking); err != nil {
+		return nil, err
 	}
 
+	// The locking clause only applies to the tables of this statement, so it
+	// must be reset when planning its subqueries.
+	defer func(prev tree.LockingClause) { p.lockingClause = prev }(p.lockingClause)
+	p.lockingClause = nil
+
 	switch s := wrapped.(type) {
 	case *tree.SelectClause:
+		p.lockingClause = locking
 		// Select can potentially optimize index selection if it's being ordered,
 		// so we allow it to do its own sorting.
 		return p.SelectClause(ctx, s, orderBy, limit, with, desiredTypes, publicColumns)
@@ -140,6 +151,10 @@ func (p *planner) Select(
 	// TODO(jordan): this limitation also applies to CTEs, which do not yet
 	// propagate into VALUES and UNION clauses
 	default:
+		if len(locking) > 0 {
+			return nil, sqlbase.NewLockingNotAllowedError(
+				locking[0].Strength, lockingNotAllowedWith(s, tree.ScalarProperties{}))
+		}
 		plan, err := p.newPlan(ctx, s, desiredTypes)
 		if err != nil {
 			return nil, err
@@ -268,6 +283,10 @@ func (p *planner) SelectClause(
 
 	r.renderProps = p.semaCtx.Properties.Derived
 
+	if err := p.checkLockingClause(parsed, r); err != nil {
+		return nil, err
+	}
+
 	// For DISTINCT ON expressions either one of the following must be
 	// satisfied:
 	//    - DISTINCT ON expressions is a subset of a prefix of the ORDER BY
@@ -138,6 +138,10 @@ func resolveExistingObjectImpl(
 		return nil, nil
 	}
 	obj := descI.(ObjectDescriptor)
+	if tn.Schema() == sessiondata.PgTempSchemaName {
+		// Use the actual name of the temporary schema of the session.
+		tn.SchemaName = tree.Name(sc.CurrentSearchPath().GetTemporarySchemaName())
+	}
 
 	goodType := true
 	switch requiredType {
@@ -147,6 +151,8 @@ func resolveExistingObjectImpl(
 		goodType = obj.TableDesc().IsView()
 	case requireTableOrViewDesc:
 		goodType = obj.TableDesc().IsTable() || obj.TableDesc().IsView()
+	case requireTableOrMaterializedViewDesc:
+		goodType = obj.TableDesc().IsTable() || obj.TableDesc().MaterializedView()
 	case requireSequenceDesc:
 		goodType = obj.TableDesc().IsSequence()
 	}
@@ -218,7 +224,7 @@ func ResolveTargetObject(
 			"cannot create %q because the target database or schema does not exist",
 			tree.ErrString(tn)).SetHintf("verify that the current database and search_path are valid and/or the target database exists")
 	}
-	if tn.Schema() != tree.PublicSchema {
+	if isVirtualSchemaName(tn.Schema()) {
 		return nil, pgerror.NewErrorf(pgerror.CodeInvalidNameError,
 			"schema cannot be modified: %q", tree.ErrString(&tn.TableNamePrefix))
 	}
@@ -244,31 +250,48 @@ const (
 	requireViewDesc
 	requireTableOrViewDesc
 	requireSequenceDesc
+	// requireTableOrMaterializedViewDesc is used by the statements that
+	// operate on indexes, which materialized views can have.
+	requireTableOrMaterializedViewDesc
 )
 
 var requiredTypeNames = [...]string{
-	requireTableDesc:       "table",
-	requireViewDesc:        "view",
-	requireTableOrViewDesc: "table or view",
-	requireSequenceDesc:    "sequence",
+	requireTableDesc:                   "table",
+	requireViewDesc:                    "view",
+	requireTableOrViewDesc:             "table or view",
+	requireSequenceDesc:                "sequence",
+	requireTableOrMaterializedViewDesc: "table or materialized view",
 }
 
 // LookupSchema implements the tree.TableNameTargetResolver interface.
 func (p *planner) LookupSchema(
 	ctx context.Context, dbName, scName string,
 ) (found bool, scMeta tree.SchemaMeta, err error) {
+	if p.isOtherSessionTemporarySchema(scName) {
+		return false, nil, nil
+	}
 	sc := p.LogicalSchemaAccessor()
 	dbDesc, err := sc.GetDatabaseDesc(ctx, p.txn, dbName, p.CommonLookupFlags(false /*required*/))
 	if err != nil || dbDesc == nil {
 		return false, nil, err
 	}
-	return sc.IsValidSchema(dbDesc, scName), dbDesc, nil
+	found, err = sc.IsValidSchema(ctx, p.txn, dbDesc, scName)
+	return found, dbDesc, err
 }
 
 // LookupObject implements the tree.TableNameExistingResolver interface.
 func (p *planner) LookupObject(
 	ctx context.Context, requireMutable bool, dbName, scName, tbName string,
 ) (found bool, objMeta tree.NameResolutionResult, err error) {
+	if p.isOtherSessionTemporarySchema(scName) {
+		return false, nil, nil
+	}
+	if scName == sessiondata.PgTempSchemaName {
+		// The objects of the temporary schema are stored under its actual name.
+		if tempName := p.SessionData().SearchPath.GetTemporarySchemaName(); tempName != "" {
+			scName = tempName
+		}
+	}
 	sc := p.LogicalSchemaAccessor()
 	p.tableName = tree.MakeTableNameWithSchema(tree.Name(dbName), tree.Name(scName), tree.Name(tbName))
 	objDesc, _, err := sc.GetObjectDesc(ctx, p.txn, &p.tableName, p.ObjectLookupFlags(false /*required*/, requireMutable))
@@ -311,6 +334,36 @@ func getDescriptorsFromTargetList(
 		return descs, nil
 	}
 
+	if targets.Schemas != nil {
+		if len(targets.Schemas) == 0 {
+			return nil, errNoSchema
+		}
+		descs := make([]sqlbase.DescriptorProto, 0, len(targets.Schemas))
+		for _, schema := range targets.Schemas {
+			_, descriptor, err := p.resolveSchemaDesc(ctx, string(schema), true /* required */)
+			if err != nil {
+				return nil, err
+			}
+			descs = append(descs, descriptor)
+		}
+		return descs, nil
+	}
+
+	if targets.Functions != nil {
+		if len(targets.Functions) == 0 {
+			return nil, errNoFunction
+		}
+		descs := make([]sqlbase.DescriptorProto, 0, len(targets.Functions))
+		for i := range targets.Functions {
+			descriptor, err := p.resolveFunctionDesc(ctx, &targets.Functions[i], true /* required */)
+			if err != nil {
+				return nil, err
+			}
+			descs = append(descs, descriptor)
+		}
+		return descs, nil
+	}
+
 	if len(targets.Tables) == 0 {
 		return nil, errNoTable
 	}
@@ -349,6 +402,13 @@ func (p *planner) getQualifiedTableName(
 		return "", err
 	}
 	tbName := tree.MakeTableName(tree.Name(dbDesc.Name), tree.Name(desc.Name))
+	if desc.SchemaID != 0 {
+		scDesc := &sqlbase.SchemaDescriptor{}
+		if err := getDescriptorByID(ctx, p.txn, desc.SchemaID, scDesc); err != nil {
+			return "", err
+		}
+		tbName.SchemaName = tree.Name(scDesc.Name)
+	}
 	return tbName.String(), nil
 }
 
@@ -387,7 +447,7 @@ func findTableContainingIndex(
 		if err != nil {
 			return nil, nil, err
 		}
-		if tableDesc == nil || !tableDesc.IsTable() {
+		if tableDesc == nil || !(tableDesc.IsTable() || tableDesc.MaterializedView()) {
 			continue
 		}
 
@@ -440,7 +500,8 @@ func expandIndexName(
 	tn = &index.Table
 	if !index.SearchTable {
 		// The index and its table prefix must exist already. Resolve the table.
-		desc, err = ResolveMutableExistingObject(ctx, sc, tn, requireTable, requireTableDesc)
+		desc, err = ResolveMutableExistingObject(
+			ctx, sc, tn, requireTable, requireTableOrMaterializedViewDesc)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -604,6 +665,9 @@ type internalLookupCtx struct {
 	dbDescs map[sqlbase.ID]*DatabaseDescriptor
 	tbDescs map[sqlbase.ID]*TableDescriptor
 	tbIDs   []sqlbase.ID
+	tyDescs map[sqlbase.ID]*sqlbase.TypeDescriptor
+	tyIDs   []sqlbase.ID
+	scDescs map[sqlbase.ID]*sqlbase.SchemaDescriptor
 }
 
 // tableLookupFn can be used to retrieve a table descriptor and its corresponding
@@ -616,7 +680,9 @@ func newInternalLookupCtx(
 	dbNames := make(map[sqlbase.ID]string)
 	dbDescs := make(map[sqlbase.ID]*DatabaseDescriptor)
 	tbDescs := make(map[sqlbase.ID]*TableDescriptor)
-	var tbIDs, dbIDs []sqlbase.ID
+	tyDescs := make(map[sqlbase.ID]*sqlbase.TypeDescriptor)
+	scDescs := make(map[sqlbase.ID]*sqlbase.SchemaDescriptor)
+	var tbIDs, dbIDs, tyIDs []sqlbase.ID
 	// Record database descriptors for name lookups.
 	for _, desc := range descs {
 		switch d := desc.(type) {
@@ -632,6 +698,13 @@ func newInternalLookupCtx(
 				// Only make the table visible for iteration if the prefix was included.
 				tbIDs = append(tbIDs, d.ID)
 			}
+		case *sqlbase.TypeDescriptor:
+			tyDescs[d.ID] = d
+			if prefix == nil || prefix.ID == d.ParentID {
+				tyIDs = append(tyIDs, d.ID)
+			}
+		case *sqlbase.SchemaDescriptor:
+			scDescs[d.ID] = d
 		}
 	}
 	return &internalLookupCtx{
@@ -640,6 +713,9 @@ func newInternalLookupCtx(
 		tbDescs: tbDescs,
 		tbIDs:   tbIDs,
 		dbIDs:   dbIDs,
+		tyDescs: tyDescs,
+		tyIDs:   tyIDs,
+		scDescs: scDescs,
 	}
 }
 
@@ -422,7 +422,10 @@ func (rf *CFetcher) StartScan(
 		firstBatchLimit++
 	}
 
-	f, err := makeKVBatchFetcher(txn, spans, rf.reverse, limitBatches, firstBatchLimit, rf.returnRangeInfo)
+	f, err := makeKVBatchFetcher(
+		txn, spans, rf.reverse, limitBatches, firstBatchLimit, rf.returnRangeInfo,
+		roachpb.LOCK_NONE, roachpb.LOCK_WAIT_BLOCK,
+	)
 	if err != nil {
 		return err
 	}
@@ -138,3 +138,10 @@ func NewUniquenessConstraintViolationError(
 		strings.Join(valStrs, ","),
 		index.Name)
 }
+
+// NewLockNotAvailableError creates an error that represents a row which could
+// not be locked without waiting, as with SELECT ... FOR UPDATE NOWAIT.
+func NewLockNotAvailableError(tableName string) error {
+	return pgerror.NewErrorf(pgerror.CodeLockNotAvailableError,
+		"could not obtain lock on row in relation %q", tableName)
+}
@@ -203,6 +203,11 @@ type Fetcher struct {
 	// when beginning a new scan.
 	traceKV bool
 
+	// lockStrength and lockWaitPolicy, if set, cause the fetched rows to be
+	// locked by the transaction. See SetLocking.
+	lockStrength   roachpb.KeyLockingStrength
+	lockWaitPolicy roachpb.KeyLockingWaitPolicy
+
 	// -- Fields updated during a scan --
 
 	kvFetcher      kvFetcher
@@ -433,6 +438,17 @@ func (rf *Fetcher) Init(
 	return nil
 }
 
+// SetLocking causes the rows fetched by the next scans to be locked by the
+// transaction, as with SELECT ... FOR UPDATE. The rows already locked by other
+// transactions are waited for, skipped or cause an error depending on
+// waitPolicy. It can only be used with a single table.
+func (rf *Fetcher) SetLocking(
+	strength roachpb.KeyLockingStrength, waitPolicy roachpb.KeyLockingWaitPolicy,
+) {
+	rf.lockStrength = strength
+	rf.lockWaitPolicy = waitPolicy
+}
+
 // StartScan initializes and starts the key-value scan. Can be used multiple
 // times.
 func (rf *Fetcher) StartScan(
@@ -463,7 +479,10 @@ func (rf *Fetcher) StartScan(
 		firstBatchLimit++
 	}
 
-	f, err := makeKVBatchFetcher(txn, spans, rf.reverse, limitBatches, firstBatchLimit, rf.returnRangeInfo)
+	f, err := makeKVBatchFetcher(
+		txn, spans, rf.reverse, limitBatches, firstBatchLimit, rf.returnRangeInfo,
+		rf.lockStrength, rf.lockWaitPolicy,
+	)
 	if err != nil {
 		return err
 	}
@@ -488,6 +507,11 @@ func (rf *Fetcher) NextKey(ctx context.Context) (rowDone bool, err error) {
 	for {
 		ok, rf.kv, _, err = rf.kvFetcher.nextKV(ctx)
 		if err != nil {
+			if _, ok := err.(*roachpb.WriteIntentError); ok && rf.lockWaitPolicy == roachpb.LOCK_WAIT_ERROR {
+				// The locking scan found rows locked by other transactions which
+				// were still running.
+				return false, NewLockNotAvailableError(rf.tables[0].desc.Name)
+			}
 			return false, err
 		}
 		rf.kvEnd = !ok
@@ -79,9 +79,21 @@ type fkExistenceCheckBaseHelper struct {
 	// searchTable is the descriptor of the searched table. Stored only
 	// for error messages; lookups use the pre-computed searchPrefix.
 	searchTable *sqlbase.ImmutableTableDescriptor
+	// mutatedTable is the descriptor of the table being mutated. Stored
+	// only for deferred checks, which also look up the mutated table.
+	mutatedTable *sqlbase.ImmutableTableDescriptor
 	// mutatedIdx is the descriptor for the target index being mutated.
 	// Stored only for error messages.
 	mutatedIdx *sqlbase.IndexDescriptor
+
+	// constraintName is the name of the FK constraint. Stored only for
+	// error messages.
+	constraintName string
+	// deferrable and initiallyDeferred are copied from the FK constraint
+	// placed on the referencing table. They determine whether the check
+	// can be queued until the end of the transaction.
+	deferrable        bool
+	initiallyDeferred bool
 }
 
 // makeFkExistenceCheckBaseHelper instanciates a FK helper.
@@ -99,6 +111,8 @@ type fkExistenceCheckBaseHelper struct {
 //   This is used to derive the searched table/index,
 //   and determine the MATCH style.
 //
+// - mutatedTable is the table being mutated.
+//
 // - writeIdx is the target index being mutated. This is used
 //   to determine prefixLen in combination with searchIdx.
 //
@@ -114,6 +128,7 @@ type fkExistenceCheckBaseHelper struct {
 func makeFkExistenceCheckBaseHelper(
 	txn *client.Txn,
 	otherTables FkTableMetadata,
+	mutatedTable *sqlbase.ImmutableTableDescriptor,
 	mutatedIdx *sqlbase.IndexDescriptor,
 	ref sqlbase.ForeignKeyReference,
 	colMap map[sqlbase.ColumnID]int,
@@ -147,31 +162,56 @@ func makeFkExistenceCheckBaseHelper(
 	searchPrefix := sqlbase.MakeIndexKeyPrefix(searchTable.TableDesc(), ref.Index)
 
 	// Initialize the row fetcher.
+	rf, err := makeFkCheckFetcher(searchTable, searchIdx, alloc)
+	if err != nil {
+		return ret, err
+	}
+
+	// The deferrability of the constraint is recorded on the referencing
+	// table, which is the searched table for backward checks.
+	fwdRef := ref
+	if dir == CheckDeletes {
+		fwdRef = searchIdx.ForeignKey
+	}
+
+	return fkExistenceCheckBaseHelper{
+		txn:               txn,
+		dir:               dir,
+		rf:                rf,
+		ref:               ref,
+		searchTable:       searchTable,
+		searchIdx:         searchIdx,
+		ids:               ids,
+		prefixLen:         prefixLen,
+		searchPrefix:      searchPrefix,
+		mutatedTable:      mutatedTable,
+		mutatedIdx:        mutatedIdx,
+		constraintName:    fwdRef.Name,
+		deferrable:        fwdRef.Deferrable,
+		initiallyDeferred: fwdRef.InitiallyDeferred,
+	}, nil
+}
+
+// makeFkCheckFetcher instantiates a row fetcher for the lookups of FK
+// existence checks over the given index.
+func makeFkCheckFetcher(
+	table *sqlbase.ImmutableTableDescriptor,
+	idx *sqlbase.IndexDescriptor,
+	alloc *sqlbase.DatumAlloc,
+) (*Fetcher, error) {
 	tableArgs := FetcherTableArgs{
-		Desc:             searchTable,
-		Index:            searchIdx,
-		ColIdxMap:        searchTable.ColumnIdxMap(),
-		IsSecondaryIndex: searchIdx.ID != searchTable.PrimaryIndex.ID,
-		Cols:             searchTable.Columns,
+		Desc:             table,
+		Index:            idx,
+		ColIdxMap:        table.ColumnIdxMap(),
+		IsSecondaryIndex: idx.ID != table.PrimaryIndex.ID,
+		Cols:             table.Columns,
 	}
 	rf := &Fetcher{}
 	if err := rf.Init(
 		false /* reverse */, false /* returnRangeInfo */, false /* isCheck */, alloc, tableArgs); err != nil {
-		return ret, err
+		return nil, err
 	}
-
-	return fkExistenceCheckBaseHelper{
-		txn:          txn,
-		dir:          dir,
-		rf:           rf,
-		ref:          ref,
-		searchTable:  searchTable,
-		searchIdx:    searchIdx,
-		ids:          ids,
-		prefixLen:    prefixLen,
-		searchPrefix: searchPrefix,
-		mutatedIdx:   mutatedIdx,
-	}, nil
+	return rf, nil
 }
 
 // computeFkCheckColumnIDs determines the set of column IDs to use for
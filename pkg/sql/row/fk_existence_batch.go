@@ -41,6 +41,11 @@ type fkExistenceBatchChecker struct {
 	// batchIdxToFk maps the index of the check request/response in the kv batch
 	// to the fkExistenceCheckBaseHelper that created it.
 	batchIdxToFk []*fkExistenceCheckBaseHelper
+
+	// deferred, if set, is the queue of the checks of the deferred
+	// constraints of the transaction. The checks of these constraints are
+	// added to the queue instead of the batch.
+	deferred *DeferredFKChecks
 }
 
 // reset starts a new batch.
@@ -53,6 +58,9 @@ func (f *fkExistenceBatchChecker) reset() {
 func (f *fkExistenceBatchChecker) addCheck(
 	ctx context.Context, row tree.Datums, source *fkExistenceCheckBaseHelper, traceKV bool,
 ) error {
+	if f.deferred != nil && f.deferred.shouldDefer(source) {
+		return f.deferred.addCheck(ctx, row, source, traceKV)
+	}
 	span, err := source.spanForValues(row)
 	if err != nil {
 		return err
@@ -0,0 +1,313 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package row
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// ConstraintsMode is the checking mode of the deferrable constraints of a
+// transaction, as set by SET CONSTRAINTS ALL.
+type ConstraintsMode int
+
+const (
+	// ConstraintsDefault checks every deferrable constraint at the time
+	// specified by its definition (INITIALLY DEFERRED or INITIALLY
+	// IMMEDIATE).
+	ConstraintsDefault ConstraintsMode = iota
+	// ConstraintsAllDeferred checks every deferrable constraint at the end
+	// of the transaction.
+	ConstraintsAllDeferred
+	// ConstraintsAllImmediate checks every constraint after every row.
+	ConstraintsAllImmediate
+)
+
+// deferredFKCheckBatchSize is the maximum number of lookups sent in a
+// single kv batch when the deferred checks are run.
+const deferredFKCheckBatchSize = 1000
+
+// DeferredFKChecks accumulates the FK existence checks of the deferred
+// constraints of a transaction. The checks are run when the transaction
+// commits, or when SET CONSTRAINTS ALL IMMEDIATE makes the constraints
+// immediate.
+//
+// A deferred check verifies the constraint for the values of the row that
+// queued it at the time the check is run, not at the time the row was
+// mutated: the constraint is violated if rows with these values still exist
+// in the referencing table and no row with these values exists in the
+// referenced table. This way, a row that is inserted and then deleted, or a
+// referenced row that is deleted and then inserted again, in the same
+// transaction does not cause a spurious violation.
+//
+// The zero value is ready to use. A DeferredFKChecks is not safe for
+// concurrent use.
+type DeferredFKChecks struct {
+	// mode is the checking mode set by SET CONSTRAINTS ALL.
+	mode ConstraintsMode
+
+	// checks is the queue of checks, in order of addition.
+	checks []deferredFKCheck
+
+	// seen records the referencing key prefixes of the queued checks, so
+	// that the same values are checked only once for every constraint.
+	seen map[string]struct{}
+
+	// fetchers caches the row fetchers used to decode the results of the
+	// lookups, per table and index.
+	fetchers map[fkCheckIndexKey]*Fetcher
+	alloc    sqlbase.DatumAlloc
+}
+
+// deferredFKCheck is a queued FK existence check.
+type deferredFKCheck struct {
+	// referencing and referenced are the lookups of the rows with the
+	// checked values in the referencing and referenced tables.
+	referencing fkCheckLookup
+	referenced  fkCheckLookup
+
+	// values are the checked values, in the order of the columns of the
+	// constraint. Stored only for error messages.
+	values tree.Datums
+	// constraintName is the name of the constraint. Stored only for error
+	// messages.
+	constraintName string
+}
+
+// fkCheckLookup is the lookup of the rows with the checked values in one of
+// the tables of a FK constraint.
+type fkCheckLookup struct {
+	table *sqlbase.ImmutableTableDescriptor
+	index *sqlbase.IndexDescriptor
+	span  roachpb.Span
+}
+
+type fkCheckIndexKey struct {
+	tableID sqlbase.ID
+	indexID sqlbase.IndexID
+}
+
+// Mode returns the checking mode of the deferrable constraints.
+func (d *DeferredFKChecks) Mode() ConstraintsMode {
+	return d.mode
+}
+
+// SetMode sets the checking mode of the deferrable constraints for the rest
+// of the transaction. The checks that are already queued are not run: the
+// caller is responsible for calling Run when the constraints become
+// immediate.
+func (d *DeferredFKChecks) SetMode(mode ConstraintsMode) {
+	d.mode = mode
+}
+
+// Reset empties the queue and restores the default checking mode. It is
+// called when the transaction finishes or restarts.
+func (d *DeferredFKChecks) Reset() {
+	*d = DeferredFKChecks{}
+}
+
+// shouldDefer determines whether the check of the given FK constraint is to
+// be queued instead of being run with the row.
+func (d *DeferredFKChecks) shouldDefer(fk *fkExistenceCheckBaseHelper) bool {
+	switch d.mode {
+	case ConstraintsAllDeferred:
+		return fk.deferrable
+	case ConstraintsAllImmediate:
+		return false
+	default:
+		return fk.initiallyDeferred
+	}
+}
+
+// addCheck queues the check of the given FK constraint for the given
+// mutated row.
+func (d *DeferredFKChecks) addCheck(
+	ctx context.Context, row tree.Datums, fk *fkExistenceCheckBaseHelper, traceKV bool,
+) error {
+	values := make(tree.Datums, fk.prefixLen)
+	for i, colID := range fk.searchIdx.ColumnIDs[:fk.prefixLen] {
+		values[i] = row[fk.ids[colID]]
+	}
+	searched, err := makeFkCheckLookup(fk.searchTable, fk.searchIdx, values)
+	if err != nil {
+		return err
+	}
+	mutated, err := makeFkCheckLookup(fk.mutatedTable, fk.mutatedIdx, values)
+	if err != nil {
+		return err
+	}
+
+	check := deferredFKCheck{values: values, constraintName: fk.constraintName}
+	switch fk.dir {
+	case CheckInserts:
+		check.referencing, check.referenced = mutated, searched
+	case CheckDeletes:
+		check.referencing, check.referenced = searched, mutated
+	default:
+		return pgerror.NewAssertionErrorf("impossible case: fkExistenceCheckBaseHelper has dir=%v", fk.dir)
+	}
+
+	// The referencing index carries a single FK constraint, so its key
+	// prefix identifies both the constraint and the values.
+	key := string(check.referencing.span.Key)
+	if _, ok := d.seen[key]; ok {
+		return nil
+	}
+	if d.seen == nil {
+		d.seen = make(map[string]struct{})
+	}
+	d.seen[key] = struct{}{}
+
+	if traceKV {
+		log.VEventf(ctx, 2, "FKScan deferred %s", check.referenced.span)
+	}
+	d.checks = append(d.checks, check)
+	return nil
+}
+
+// makeFkCheckLookup computes the lookup of the rows with the given values in
+// the first columns of the given index.
+func makeFkCheckLookup(
+	table *sqlbase.ImmutableTableDescriptor, idx *sqlbase.IndexDescriptor, values tree.Datums,
+) (fkCheckLookup, error) {
+	colMap := make(map[sqlbase.ColumnID]int, len(values))
+	for i, colID := range idx.ColumnIDs[:len(values)] {
+		colMap[colID] = i
+	}
+	span, _, err := sqlbase.EncodePartialIndexSpan(
+		table.TableDesc(), idx, len(values), colMap, values,
+		sqlbase.MakeIndexKeyPrefix(table.TableDesc(), idx.ID))
+	if err != nil {
+		return fkCheckLookup{}, err
+	}
+	return fkCheckLookup{table: table, index: idx, span: span}, nil
+}
+
+// Run runs the queued checks and empties the queue. A
+// pgerror.CodeForeignKeyViolationError is returned if a constraint is
+// violated, corresponding to the first violated check in order of addition.
+func (d *DeferredFKChecks) Run(ctx context.Context, txn *client.Txn) error {
+	if len(d.checks) == 0 {
+		return nil
+	}
+	checks := d.checks
+	d.checks = nil
+	d.seen = nil
+
+	// Look up the referenced rows first. The referencing rows are only
+	// looked up for the checks whose referenced rows are missing.
+	var missing []deferredFKCheck
+	if err := d.lookup(ctx, txn, checks, func(c *deferredFKCheck) *fkCheckLookup {
+		return &c.referenced
+	}, func(c *deferredFKCheck, found bool) {
+		if !found {
+			missing = append(missing, *c)
+		}
+	}); err != nil {
+		return err
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	var violated *deferredFKCheck
+	if err := d.lookup(ctx, txn, missing, func(c *deferredFKCheck) *fkCheckLookup {
+		return &c.referencing
+	}, func(c *deferredFKCheck, found bool) {
+		if found && violated == nil {
+			violated = c
+		}
+	}); err != nil {
+		return err
+	}
+	if violated != nil {
+		return pgerror.NewErrorf(pgerror.CodeForeignKeyViolationError,
+			"foreign key violation: value %s not found in %s@%s %s",
+			violated.values, violated.referenced.table.Name, violated.referenced.index.Name,
+			violated.referenced.index.ColumnNames[:len(violated.values)],
+		).SetDetailf("constraint %q was checked at the end of the transaction", violated.constraintName)
+	}
+	return nil
+}
+
+// lookup sends the lookups of one side of the given checks to kv in
+// batches, and reports for every check in order whether rows were found.
+func (d *DeferredFKChecks) lookup(
+	ctx context.Context,
+	txn *client.Txn,
+	checks []deferredFKCheck,
+	side func(*deferredFKCheck) *fkCheckLookup,
+	report func(c *deferredFKCheck, found bool),
+) error {
+	for len(checks) > 0 {
+		n := len(checks)
+		if n > deferredFKCheckBatchSize {
+			n = deferredFKCheckBatchSize
+		}
+		chunk := checks[:n]
+		checks = checks[n:]
+
+		var ba roachpb.BatchRequest
+		for i := range chunk {
+			var r roachpb.RequestUnion
+			r.MustSetInner(&roachpb.ScanRequest{
+				RequestHeader: roachpb.RequestHeaderFromSpan(side(&chunk[i]).span),
+			})
+			ba.Requests = append(ba.Requests, r)
+		}
+		br, pErr := txn.Send(ctx, ba)
+		if pErr != nil {
+			return pErr.GoError()
+		}
+
+		fetcher := SpanKVFetcher{}
+		for i, resp := range br.Responses {
+			l := side(&chunk[i])
+			rf, err := d.getFetcher(l)
+			if err != nil {
+				return err
+			}
+			fetcher.KVs = resp.GetInner().(*roachpb.ScanResponse).Rows
+			if err := rf.StartScanFrom(ctx, &fetcher); err != nil {
+				return err
+			}
+			report(&chunk[i], !rf.kvEnd)
+		}
+	}
+	return nil
+}
+
+// getFetcher returns the row fetcher for the index of the given lookup.
+func (d *DeferredFKChecks) getFetcher(l *fkCheckLookup) (*Fetcher, error) {
+	key := fkCheckIndexKey{tableID: l.table.ID, indexID: l.index.ID}
+	if rf, ok := d.fetchers[key]; ok {
+		return rf, nil
+	}
+	rf, err := makeFkCheckFetcher(l.table, l.index, &d.alloc)
+	if err != nil {
+		return nil, err
+	}
+	if d.fetchers == nil {
+		d.fetchers = make(map[fkCheckIndexKey]*Fetcher)
+	}
+	d.fetchers[key] = rf
+	return rf, nil
+}
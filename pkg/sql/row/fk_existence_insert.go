@@ -66,7 +66,7 @@ func makeFkExistenceCheckHelperForInsert(
 	// of index definitions.
 	for _, idx := range table.AllNonDropIndexes() {
 		if idx.ForeignKey.IsSet() {
-			fk, err := makeFkExistenceCheckBaseHelper(txn, otherTables, idx, idx.ForeignKey, colMap, alloc, CheckInserts)
+			fk, err := makeFkExistenceCheckBaseHelper(txn, otherTables, table, idx, idx.ForeignKey, colMap, alloc, CheckInserts)
 			if err == errSkipUnusedFK {
 				continue
 			}
@@ -30,6 +30,10 @@ type rowHelper struct {
 	Indexes      []sqlbase.IndexDescriptor
 	indexEntries []sqlbase.IndexEntry
 
+	// partialIndexes evaluates the predicates of the partial indexes among
+	// Indexes.
+	partialIndexes sqlbase.PartialIndexPredicates
+
 	// Computed during initialization for pretty-printing.
 	primIndexValDirs []encoding.Direction
 	secIndexValDirs  [][]encoding.Direction
@@ -42,8 +46,11 @@ type rowHelper struct {
 
 func newRowHelper(
 	desc *sqlbase.ImmutableTableDescriptor, indexes []sqlbase.IndexDescriptor,
-) rowHelper {
+) (rowHelper, error) {
 	rh := rowHelper{TableDesc: desc, Indexes: indexes}
+	if err := rh.partialIndexes.Init(desc.TableDesc(), indexes); err != nil {
+		return rowHelper{}, err
+	}
 
 	// Pre-compute the encoding directions of the index key values for
 	// pretty-printing in traces.
@@ -54,12 +61,13 @@ func newRowHelper(
 		rh.secIndexValDirs[i] = sqlbase.IndexKeyValDirs(&index)
 	}
 
-	return rh
+	return rh, nil
 }
 
 // encodeIndexes encodes the primary and secondary index keys. The
 // secondaryIndexEntries are only valid until the next call to encodeIndexes or
-// encodeSecondaryIndexes.
+// encodeSecondaryIndexes. See encodeSecondaryIndexes for the entries of partial
+// indexes.
 func (rh *rowHelper) encodeIndexes(
 	colIDtoRowIndex map[sqlbase.ColumnID]int, values []tree.Datum,
 ) (primaryIndexKey []byte, secondaryIndexEntries []sqlbase.IndexEntry, err error) {
@@ -82,6 +90,9 @@ func (rh *rowHelper) encodeIndexes(
 // encodeSecondaryIndexes encodes the secondary index keys. The
 // secondaryIndexEntries are only valid until the next call to encodeIndexes or
 // encodeSecondaryIndexes.
+//
+// The entry of a partial index whose predicate is not satisfied by the row has
+// a nil key: the row does not belong in that index.
 func (rh *rowHelper) encodeSecondaryIndexes(
 	colIDtoRowIndex map[sqlbase.ColumnID]int, values []tree.Datum,
 ) (secondaryIndexEntries []sqlbase.IndexEntry, err error) {
@@ -93,6 +104,17 @@ func (rh *rowHelper) encodeSecondaryIndexes(
 	if err != nil {
 		return nil, err
 	}
+	if rh.partialIndexes.HasPartialIndexes() {
+		for i := range rh.Indexes {
+			inIndex, err := rh.partialIndexes.RowInIndex(i, colIDtoRowIndex, values)
+			if err != nil {
+				return nil, err
+			}
+			if !inIndex {
+				rh.indexEntries[i] = sqlbase.IndexEntry{}
+			}
+		}
+	}
 	return rh.indexEntries, nil
 }
 
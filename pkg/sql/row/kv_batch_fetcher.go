@@ -53,6 +53,10 @@ type txnKVFetcher struct {
 	// returnRangeInfo, if set, causes the kvBatchFetcher to populate rangeInfos.
 	// See also rowFetcher.returnRangeInfo.
 	returnRangeInfo bool
+	// lockStrength and lockWaitPolicy are set on the scan requests to lock the
+	// fetched keys. See Fetcher.SetLocking.
+	lockStrength   roachpb.KeyLockingStrength
+	lockWaitPolicy roachpb.KeyLockingWaitPolicy
 
 	fetchEnd bool
 	batchIdx int
@@ -136,6 +140,10 @@ func (f *txnKVFetcher) getBatchSizeForIdx(batchIdx int) int64 {
 // Subsequent batches are larger, up to kvBatchSize.
 //
 // Batch limits can only be used if the spans are ordered.
+//
+// If lockStrength is not LOCK_NONE, the fetched keys are locked by txn, and
+// the keys already locked by other transactions are handled according to
+// lockWaitPolicy.
 func makeKVBatchFetcher(
 	txn *client.Txn,
 	spans roachpb.Spans,
@@ -143,6 +151,8 @@ func makeKVBatchFetcher(
 	useBatchLimit bool,
 	firstBatchLimit int64,
 	returnRangeInfo bool,
+	lockStrength roachpb.KeyLockingStrength,
+	lockWaitPolicy roachpb.KeyLockingWaitPolicy,
 ) (txnKVFetcher, error) {
 	if firstBatchLimit < 0 || (!useBatchLimit && firstBatchLimit != 0) {
 		return txnKVFetcher{}, errors.Errorf("invalid batch limit %d (useBatchLimit: %t)",
@@ -194,6 +204,8 @@ func makeKVBatchFetcher(
 		useBatchLimit:   useBatchLimit,
 		firstBatchLimit: firstBatchLimit,
 		returnRangeInfo: returnRangeInfo,
+		lockStrength:    lockStrength,
+		lockWaitPolicy:  lockWaitPolicy,
 	}, nil
 }
 
@@ -207,6 +219,8 @@ func (f *txnKVFetcher) fetch(ctx context.Context) error {
 		scans := make([]roachpb.ReverseScanRequest, len(f.spans))
 		for i := range f.spans {
 			scans[i].ScanFormat = roachpb.BATCH_RESPONSE
+			scans[i].KeyLocking = f.lockStrength
+			scans[i].WaitPolicy = f.lockWaitPolicy
 			scans[i].SetSpan(f.spans[i])
 			ba.Requests[i].MustSetInner(&scans[i])
 		}
@@ -214,6 +228,8 @@ func (f *txnKVFetcher) fetch(ctx context.Context) error {
 		scans := make([]roachpb.ScanRequest, len(f.spans))
 		for i := range f.spans {
 			scans[i].ScanFormat = roachpb.BATCH_RESPONSE
+			scans[i].KeyLocking = f.lockStrength
+			scans[i].WaitPolicy = f.lockWaitPolicy
 			scans[i].SetSpan(f.spans[i])
 			ba.Requests[i].MustSetInner(&scans[i])
 		}
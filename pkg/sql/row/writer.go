@@ -63,8 +63,12 @@ func MakeInserter(
 	checkFKs checkFKConstraints,
 	alloc *sqlbase.DatumAlloc,
 ) (Inserter, error) {
+	helper, err := newRowHelper(tableDesc, tableDesc.WritableIndexes())
+	if err != nil {
+		return Inserter{}, err
+	}
 	ri := Inserter{
-		Helper:                newRowHelper(tableDesc, tableDesc.WritableIndexes()),
+		Helper:                helper,
 		InsertCols:            insertCols,
 		InsertColIDtoRowIndex: ColIDtoRowIndexFromCols(insertCols),
 		marshaled:             make([]roachpb.Value, len(insertCols)),
@@ -86,6 +90,15 @@ func MakeInserter(
 	return ri, nil
 }
 
+// SetDeferredFKChecks sets the queue of the FK existence checks of the
+// deferred constraints of the transaction. The checks of these constraints
+// are queued instead of being run with the rows.
+func (ri *Inserter) SetDeferredFKChecks(d *DeferredFKChecks) {
+	if ri.Fks.checker != nil {
+		ri.Fks.checker.deferred = d
+	}
+}
+
 // insertCPutFn is used by insertRow when conflicts (i.e. the key already exists)
 // should generate errors.
 func insertCPutFn(
@@ -191,6 +204,10 @@ func (ri *Inserter) InsertRow(
 	putFn = insertInvertedPutFn
 	for i := range secondaryIndexEntries {
 		e := &secondaryIndexEntries[i]
+		if e.Key == nil {
+			// The row does not belong in this partial index.
+			continue
+		}
 		putFn(ctx, b, &e.Key, &e.Value, traceKV)
 	}
 
@@ -446,28 +463,36 @@ func makeUpdaterWithoutCascader(
 		}
 	}
 
-	// Secondary indexes needing updating.
-	needsUpdate := func(index sqlbase.IndexDescriptor) bool {
+	// Secondary indexes needing updating. A partial index also needs updating
+	// if a column of its predicate is updated, as the row may enter or leave
+	// the index.
+	needsUpdate := func(index sqlbase.IndexDescriptor) (bool, error) {
 		if updateType == UpdaterOnlyColumns {
 			// Only update columns.
-			return false
+			return false, nil
 		}
 		// If the primary key changed, we need to update all of them.
 		if primaryKeyColChange {
-			return true
+			return true, nil
 		}
-		return index.RunOverAllColumns(func(id sqlbase.ColumnID) error {
+		err := index.RunOverAllColumnsAndPredicate(tableDesc.TableDesc(), func(id sqlbase.ColumnID) error {
 			if _, ok := updateColIDtoRowIndex[id]; ok {
 				return returnTruePseudoError
 			}
 			return nil
-		}) != nil
+		})
+		if err == returnTruePseudoError {
+			return true, nil
+		}
+		return false, err
 	}
 
 	writableIndexes := tableDesc.WritableIndexes()
 	includeIndexes := make([]sqlbase.IndexDescriptor, 0, len(writableIndexes))
 	for _, index := range writableIndexes {
-		if needsUpdate(index) {
+		if update, err := needsUpdate(index); err != nil {
+			return Updater{}, err
+		} else if update {
 			includeIndexes = append(includeIndexes, index)
 		}
 	}
@@ -477,7 +502,9 @@ func makeUpdaterWithoutCascader(
 
 	var deleteOnlyIndexes []sqlbase.IndexDescriptor
 	for _, idx := range tableDesc.DeleteOnlyIndexes() {
-		if needsUpdate(idx) {
+		if update, err := needsUpdate(idx); err != nil {
+			return Updater{}, err
+		} else if update {
 			if deleteOnlyIndexes == nil {
 				// Allocate at most once.
 				deleteOnlyIndexes = make([]sqlbase.IndexDescriptor, 0, len(tableDesc.DeleteOnlyIndexes()))
@@ -488,12 +515,19 @@ func makeUpdaterWithoutCascader(
 
 	var deleteOnlyHelper *rowHelper
 	if len(deleteOnlyIndexes) > 0 {
-		rh := newRowHelper(tableDesc, deleteOnlyIndexes)
+		rh, err := newRowHelper(tableDesc, deleteOnlyIndexes)
+		if err != nil {
+			return Updater{}, err
+		}
 		deleteOnlyHelper = &rh
 	}
 
+	helper, err := newRowHelper(tableDesc, includeIndexes)
+	if err != nil {
+		return Updater{}, err
+	}
 	ru := Updater{
-		Helper:                newRowHelper(tableDesc, includeIndexes),
+		Helper:                helper,
 		DeleteHelper:          deleteOnlyHelper,
 		UpdateCols:            updateCols,
 		UpdateColIDtoRowIndex: updateColIDtoRowIndex,
@@ -567,18 +601,17 @@ func makeUpdaterWithoutCascader(
 		// Fetch all columns from indices that are being update so that they can
 		// be used to create the new kv pairs for those indices.
 		for _, index := range includeIndexes {
-			if err := index.RunOverAllColumns(maybeAddCol); err != nil {
+			if err := index.RunOverAllColumnsAndPredicate(tableDesc.TableDesc(), maybeAddCol); err != nil {
 				return Updater{}, err
 			}
 		}
 		for _, index := range deleteOnlyIndexes {
-			if err := index.RunOverAllColumns(maybeAddCol); err != nil {
+			if err := index.RunOverAllColumnsAndPredicate(tableDesc.TableDesc(), maybeAddCol); err != nil {
 				return Updater{}, err
 			}
 		}
 	}
 
-	var err error
 	if ru.Fks, err = makeFkExistenceCheckHelperForUpdate(txn, tableDesc, fkTables,
 		ru.FetchColIDtoRowIndex, alloc); err != nil {
 		return Updater{}, err
@@ -738,13 +771,22 @@ func (ru *Updater) UpdateRow(
 			continue
 		}
 
+		// A nil key indicates that the old or new row does not belong in a
+		// partial index.
 		var expValue interface{}
 		if !bytes.Equal(newSecondaryIndexEntry.Key, oldSecondaryIndexEntry.Key) {
 			ru.Fks.addCheckForIndex(ru.Helper.Indexes[i].ID, ru.Helper.Indexes[i].Type)
-			if traceKV {
-				log.VEventf(ctx, 2, "Del %s", keys.PrettyPrint(ru.Helper.secIndexValDirs[i], oldSecondaryIndexEntry.Key))
+			if oldSecondaryIndexEntry.Key != nil {
+				if traceKV {
+					log.VEventf(ctx, 2, "Del %s", keys.PrettyPrint(ru.Helper.secIndexValDirs[i], oldSecondaryIndexEntry.Key))
+				}
+				batch.Del(oldSecondaryIndexEntry.Key)
+			}
+			if newSecondaryIndexEntry.Key == nil {
+				continue
 			}
-			batch.Del(oldSecondaryIndexEntry.Key)
+		} else if newSecondaryIndexEntry.Key == nil {
+			continue
 		} else if !newSecondaryIndexEntry.Value.EqualData(oldSecondaryIndexEntry.Value) {
 			expValue = &oldSecondaryIndexEntry.Value
 		} else {
@@ -761,6 +803,9 @@ func (ru *Updater) UpdateRow(
 	// indexed will be handled separately.
 	if ru.DeleteHelper != nil {
 		for _, deletedSecondaryIndexEntry := range deleteOldSecondaryIndexEntries {
+			if deletedSecondaryIndexEntry.Key == nil {
+				continue
+			}
 			if traceKV {
 				log.VEventf(ctx, 2, "Del %s", deletedSecondaryIndexEntry.Key)
 			}
@@ -836,6 +881,16 @@ type Deleter struct {
 	key roachpb.Key
 }
 
+// SetDeferredFKChecks sets the queue of the FK existence checks of the
+// deferred constraints of the transaction. The checks of these constraints
+// are queued instead of being run with the rows. The checks of the rows
+// modified by cascading actions are not deferred.
+func (ru *Updater) SetDeferredFKChecks(d *DeferredFKChecks) {
+	if ru.Fks.checker != nil {
+		ru.Fks.checker.deferred = d
+	}
+}
+
 // MakeDeleter creates a Deleter for the given table.
 //
 // The returned Deleter contains a FetchCols field that defines the
@@ -909,15 +964,29 @@ func makeRowDeleterWithoutCascader(
 				return Deleter{}, err
 			}
 		}
+		// The predicate columns are needed to determine whether the row has an
+		// entry in a partial index.
+		predCols, err := index.PredicateColumnIDs(tableDesc.TableDesc())
+		if err != nil {
+			return Deleter{}, err
+		}
+		for _, colID := range predCols {
+			if err := maybeAddCol(colID); err != nil {
+				return Deleter{}, err
+			}
+		}
 	}
 
+	helper, err := newRowHelper(tableDesc, indexes)
+	if err != nil {
+		return Deleter{}, err
+	}
 	rd := Deleter{
-		Helper:               newRowHelper(tableDesc, indexes),
+		Helper:               helper,
 		FetchCols:            fetchCols,
 		FetchColIDtoRowIndex: fetchColIDtoRowIndex,
 	}
 	if checkFKs == CheckFKs {
-		var err error
 		if rd.Fks, err = makeFkExistenceCheckHelperForDelete(txn, tableDesc, fkTables,
 			fetchColIDtoRowIndex, alloc); err != nil {
 			return Deleter{}, err
@@ -927,6 +996,16 @@ func makeRowDeleterWithoutCascader(
 	return rd, nil
 }
 
+// SetDeferredFKChecks sets the queue of the FK existence checks of the
+// deferred constraints of the transaction. The checks of these constraints
+// are queued instead of being run with the rows. The checks of the rows
+// modified by cascading actions are not deferred.
+func (rd *Deleter) SetDeferredFKChecks(d *DeferredFKChecks) {
+	if rd.Fks.checker != nil {
+		rd.Fks.checker.deferred = d
+	}
+}
+
 // DeleteRow adds to the batch the kv operations necessary to delete a table row
 // with the given values. It also will cascade as required and check for
 // orphaned rows. The bytesMonitor is only used if cascading/fk checking and can
@@ -945,6 +1024,10 @@ func (rd *Deleter) DeleteRow(
 
 	// Delete the row from any secondary indices.
 	for i, secondaryIndexEntry := range secondaryIndexEntries {
+		if secondaryIndexEntry.Key == nil {
+			// The row does not belong in this partial index.
+			continue
+		}
 		if traceKV {
 			log.VEventf(ctx, 2, "Del %s", keys.PrettyPrint(rd.Helper.secIndexValDirs[i], secondaryIndexEntry.Key))
 		}
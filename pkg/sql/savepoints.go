@@ -0,0 +1,114 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// Savepoints come in two flavors:
+//
+// - the restart savepoint (cockroach_restart, or any savepoint when
+//   force_savepoint_restart is set) marks the beginning of a transaction
+//   that the client is prepared to retry. ROLLBACK TO this savepoint
+//   restarts the KV transaction and RELEASE commits it. This savepoint is
+//   tracked in txnState.activeSavepointName.
+// - any other savepoint is a regular savepoint. Regular savepoints can be
+//   nested and are kept in the txnState.savepoints stack. ROLLBACK TO a
+//   regular savepoint rolls back the KV writes performed since the savepoint
+//   was established: the KV transaction ignores the sequence numbers
+//   allocated since then. RELEASE simply forgets the savepoint.
+//
+// Schema changes are not transactional at the level of a savepoint: the
+// descriptors they modify are cached by the connExecutor for the duration of
+// the transaction. Rolling back over a DDL statement is thus refused.
+
+// savepoint is a regular savepoint established with SAVEPOINT.
+type savepoint struct {
+	name tree.Name
+	// token identifies the savepoint in the KV transaction.
+	token client.SavepointToken
+	// numDDL is the number of DDL statements that the transaction had
+	// executed when the savepoint was established.
+	numDDL int
+}
+
+// isRestartSavepoint returns whether the named savepoint is the restart
+// savepoint.
+func (ex *connExecutor) isRestartSavepoint(name tree.Name) bool {
+	return ex.sessionData.ForceSavepointRestart ||
+		strings.HasPrefix(string(name), RestartSavepointName)
+}
+
+// findSavepoint returns the index in the stack of regular savepoints of the
+// most recent savepoint with the given name, or -1 if there is none.
+func (ex *connExecutor) findSavepoint(name tree.Name) int {
+	for i := len(ex.state.savepoints) - 1; i >= 0; i-- {
+		if ex.state.savepoints[i].name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// execSavepointInOpenState establishes a regular savepoint.
+func (ex *connExecutor) execSavepointInOpenState(ctx context.Context, name tree.Name) error {
+	token, err := ex.state.mu.txn.CreateSavepoint(ctx)
+	if err != nil {
+		return err
+	}
+	ex.state.savepoints = append(ex.state.savepoints, savepoint{
+		name:   name,
+		token:  token,
+		numDDL: ex.state.numDDL,
+	})
+	return nil
+}
+
+// execReleaseSavepointInOpenState releases the regular savepoint at the given
+// index of the stack, along with all the savepoints established after it. The
+// writes performed since the savepoint are kept.
+func (ex *connExecutor) execReleaseSavepointInOpenState(idx int) {
+	ex.state.savepoints = ex.state.savepoints[:idx]
+}
+
+// rollbackToSavepoint rolls the KV transaction back to the regular savepoint
+// at the given index of the stack. The savepoints established after it are
+// destroyed; the savepoint itself remains established.
+func (ex *connExecutor) rollbackToSavepoint(ctx context.Context, idx int) error {
+	sp := &ex.state.savepoints[idx]
+	if ex.state.numDDL != sp.numDDL {
+		return pgerror.UnimplementedWithIssueError(10735,
+			"ROLLBACK TO SAVEPOINT is not supported after DDL statements")
+	}
+	if err := ex.state.mu.txn.RollbackToSavepoint(ctx, sp.token); err != nil {
+		return pgerror.Wrap(err, pgerror.CodeSerializationFailureError,
+			"cannot roll back to savepoint "+tree.ErrString(&sp.name))
+	}
+	ex.state.savepoints = ex.state.savepoints[:idx+1]
+	return nil
+}
+
+// newSavepointDoesNotExistError returns the error reported when a savepoint
+// is released or rolled back to without having been established.
+func newSavepointDoesNotExistError(name tree.Name) error {
+	return pgerror.NewErrorf(pgerror.CodeInvalidSavepointSpecificationError,
+		"savepoint %s does not exist", tree.ErrString(&name))
+}
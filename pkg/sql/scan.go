@@ -121,6 +121,12 @@ type scanNode struct {
 
 	// Indicates if this scan is the source for a delete node.
 	isDeleteSource bool
+
+	// lockingStrength and lockingWaitPolicy are set if the scan locks the rows
+	// it reads, as requested by a SELECT ... FOR UPDATE statement. Locking
+	// scans always read the primary index.
+	lockingStrength   roachpb.KeyLockingStrength
+	lockingWaitPolicy roachpb.KeyLockingWaitPolicy
 }
 
 // scanVisibility represents which table columns should be included in a scan.
@@ -219,8 +225,12 @@ func (n *scanNode) startExec(params runParams) error {
 		Cols:             n.cols,
 		ValNeededForCol:  n.valNeededForCol.Copy(),
 	}
-	return n.run.fetcher.Init(n.reverse, false, /* returnRangeInfo */
-		false /* isCheck */, &params.p.alloc, tableArgs)
+	if err := n.run.fetcher.Init(n.reverse, false, /* returnRangeInfo */
+		false /* isCheck */, &params.p.alloc, tableArgs); err != nil {
+		return err
+	}
+	n.run.fetcher.SetLocking(n.lockingStrength, n.lockingWaitPolicy)
+	return nil
 }
 
 func (n *scanNode) Close(context.Context) {
@@ -386,6 +396,29 @@ func (n *scanNode) lookupSpecifiedIndex(indexFlags *tree.IndexFlags) error {
 	return nil
 }
 
+// setLocking makes the scan lock the rows it reads with the given strength.
+// The rows locked by other transactions are handled according to waitPolicy.
+// The shared strengths are rejected when the statement is planned, so all the
+// remaining strengths are implemented with exclusive locks.
+func (n *scanNode) setLocking(strength tree.LockingStrength, waitPolicy tree.LockingWaitPolicy) {
+	if strength == tree.ForNone {
+		n.lockingStrength = roachpb.LOCK_NONE
+		n.lockingWaitPolicy = roachpb.LOCK_WAIT_BLOCK
+		return
+	}
+	n.lockingStrength = roachpb.LOCK_EXCLUSIVE
+	switch waitPolicy {
+	case tree.LockWaitBlock:
+		n.lockingWaitPolicy = roachpb.LOCK_WAIT_BLOCK
+	case tree.LockWaitSkip:
+		n.lockingWaitPolicy = roachpb.LOCK_WAIT_SKIP
+	case tree.LockWaitError:
+		n.lockingWaitPolicy = roachpb.LOCK_WAIT_ERROR
+	default:
+		panic(fmt.Sprintf("unknown locking wait policy %s", waitPolicy))
+	}
+}
+
 // initCols initializes n.cols and n.numBackfillColumns according to n.desc and n.colCfg.
 func (n *scanNode) initCols() error {
 	n.numBackfillColumns = 0
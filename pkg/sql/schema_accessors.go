@@ -81,7 +81,7 @@ type SchemaAccessor interface {
 	GetDatabaseDesc(ctx context.Context, txn *client.Txn, dbName string, flags DatabaseLookupFlags) (*DatabaseDescriptor, error)
 
 	// IsValidSchema returns true if the given schema name is valid for the given database.
-	IsValidSchema(db *DatabaseDescriptor, scName string) bool
+	IsValidSchema(ctx context.Context, txn *client.Txn, db *DatabaseDescriptor, scName string) (bool, error)
 
 	// GetObjectNames returns the list of all objects in the given
 	// database and schema.
@@ -105,9 +105,11 @@ type SchemaChanger struct {
 	jobRegistry    *jobs.Registry
 	// Keep a reference to the job related to this schema change
 	// so that we don't need to read the job again while updating
-	// the status of the job. This job can be one of two jobs: the
-	// original schema change job for the sql command, or the
-	// rollback job for the rollback of the schema change.
+	// the status of the job. This job can be one of three jobs: the
+	// original schema change job for the sql command, the rollback
+	// job for the rollback of the schema change, or the cleanup job
+	// for the mutations queued up by the completion of the schema
+	// change.
 	job *jobs.Job
 	// Caches updated by DistSQL.
 	rangeDescriptorCache *kv.RangeDescriptorCache
@@ -564,6 +566,31 @@ func (sc *SchemaChanger) maybeMakeAddTablePublic(
 	return nil
 }
 
+// maybeMakeEnumMembersWritable allows the ENUM members that are being added
+// to the types of the columns of the table to be written. The new version of
+// the descriptor is published once all the nodes have the version that lets
+// them decode values with the new members.
+func (sc *SchemaChanger) maybeMakeEnumMembersWritable(
+	ctx context.Context, table *sqlbase.TableDescriptor,
+) error {
+	if !table.HasReadOnlyEnumMembers() {
+		return nil
+	}
+	_, err := sc.leaseMgr.Publish(
+		ctx,
+		table.ID,
+		func(tbl *sqlbase.MutableTableDescriptor) error {
+			if !tbl.HasReadOnlyEnumMembers() {
+				return errDidntUpdateDescriptor
+			}
+			tbl.MakeEnumMembersWritable()
+			return nil
+		},
+		func(txn *client.Txn) error { return nil },
+	)
+	return err
+}
+
 func (sc *SchemaChanger) maybeGCMutations(
 	ctx context.Context, inSession bool, table *sqlbase.TableDescriptor,
 ) error {
@@ -783,6 +810,10 @@ func (sc *SchemaChanger) exec(
 		return err
 	}
 
+	if err := sc.maybeMakeEnumMembersWritable(ctx, tableDesc); err != nil {
+		return err
+	}
+
 	if err := sc.maybeGCMutations(ctx, inSession, tableDesc); err != nil {
 		return err
 	}
@@ -961,14 +992,21 @@ func (sc *SchemaChanger) waitToUpdateLeases(ctx context.Context, tableID sqlbase
 // It ensures that all nodes are on the current (pre-update) version of the
 // schema.
 // Returns the updated descriptor.
+//
+// The completion of a column type change queues up the removal of the old
+// column and indexes as a new set of mutations. The schema changer is then
+// updated to run them.
 func (sc *SchemaChanger) done(ctx context.Context) (*sqlbase.ImmutableTableDescriptor, error) {
 	isRollback := false
 	jobSucceeded := true
+	cleanupMutationID := sqlbase.InvalidMutationID
+	var cleanupJob *jobs.Job
 	now := timeutil.Now().UnixNano()
-	return sc.leaseMgr.Publish(ctx, sc.tableID, func(desc *sqlbase.MutableTableDescriptor) error {
+	desc, err := sc.leaseMgr.Publish(ctx, sc.tableID, func(desc *sqlbase.MutableTableDescriptor) error {
 		// Reset vars here because update function can be called multiple times in a retry.
 		isRollback = false
 		jobSucceeded = true
+		cleanupMutationID = sqlbase.InvalidMutationID
 
 		i := 0
 		for _, mutation := range desc.Mutations {
@@ -991,6 +1029,25 @@ func (sc *SchemaChanger) done(ctx context.Context) (*sqlbase.ImmutableTableDescr
 						})
 				}
 			}
+			if mutation.GetComputedColumnSwap() != nil && mutation.Direction == sqlbase.DescriptorMutation_ADD {
+				// The old column and indexes are dropped by a new mutation.
+				cleanupMutationID = desc.ClusterVersion.NextMutationID
+			}
+			if mutation.GetMaterializedViewRefresh() != nil {
+				// The data of the indexes replaced by the refresh, or of the
+				// new indexes if it was rolled back, is GC-ed like that of
+				// dropped indexes.
+				for _, id := range desc.IndexesDroppedByRefresh(&mutation) {
+					jobSucceeded = false
+					desc.GCMutations = append(
+						desc.GCMutations,
+						sqlbase.TableDescriptor_GCDescriptorMutation{
+							IndexID:  id,
+							DropTime: now,
+							JobID:    *sc.job.ID(),
+						})
+				}
+			}
 			if err := desc.MakeMutationComplete(mutation); err != nil {
 				return err
 			}
@@ -1025,6 +1082,14 @@ func (sc *SchemaChanger) done(ctx context.Context) (*sqlbase.ImmutableTableDescr
 			}
 		}
 
+		if cleanupMutationID != sqlbase.InvalidMutationID {
+			var err error
+			cleanupJob, err = sc.createCleanupJob(ctx, txn, cleanupMutationID)
+			if err != nil {
+				return err
+			}
+		}
+
 		schemaChangeEventType := EventLogFinishSchemaChange
 		if isRollback {
 			schemaChangeEventType = EventLogFinishSchemaRollback
@@ -1045,6 +1110,15 @@ func (sc *SchemaChanger) done(ctx context.Context) (*sqlbase.ImmutableTableDescr
 			}{uint32(sc.mutationID)},
 		)
 	})
+	if err != nil {
+		return nil, err
+	}
+	// Only update the schema changer if the transaction has succeeded.
+	if cleanupJob != nil {
+		sc.mutationID = cleanupMutationID
+		sc.job = cleanupJob
+	}
+	return desc, nil
 }
 
 // notFirstInLine returns true whenever the schema change has been queued
@@ -1093,8 +1167,26 @@ func (sc *SchemaChanger) runStateMachineAndBackfill(
 	}
 
 	// Mark the mutations as completed.
-	_, err := sc.done(ctx)
-	return err
+	mutationID := sc.mutationID
+	if _, err := sc.done(ctx); err != nil {
+		return err
+	}
+	if sc.mutationID == mutationID {
+		return nil
+	}
+
+	// The completion of the mutations queued up new mutations. Run them right
+	// away unless other schema changes were queued up before them, in which
+	// case they are left to the asynchronous schema changer.
+	if _, notFirst, err := sc.notFirstInLine(ctx); err != nil || notFirst {
+		return err
+	}
+	if err := sc.job.Started(ctx); err != nil {
+		if log.V(2) {
+			log.Infof(ctx, "Failed to mark job %d as started: %v", *sc.job.ID(), err)
+		}
+	}
+	return sc.runStateMachineAndBackfill(ctx, lease, evalCtx)
 }
 
 // reverseMutations reverses the direction of all the mutations with the
@@ -1282,6 +1374,61 @@ func (sc *SchemaChanger) createRollbackJob(
 	return nil, fmt.Errorf("no job found for table %d mutation %d", sc.tableID, sc.mutationID)
 }
 
+// createCleanupJob creates the job for the mutations with the given ID that
+// were queued up by the completion of the current schema change, and records
+// it in the table descriptor.
+func (sc *SchemaChanger) createCleanupJob(
+	ctx context.Context, txn *client.Txn, mutationID sqlbase.MutationID,
+) (*jobs.Job, error) {
+	// Read the table descriptor from the store. The Version of the
+	// descriptor has already been incremented in the transaction and
+	// this descriptor can be modified without incrementing the version.
+	tableDesc, err := sqlbase.GetTableDescFromID(ctx, txn, sc.tableID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize refresh spans to scan the entire table.
+	span := tableDesc.PrimaryIndexSpan()
+	var spanList []jobspb.ResumeSpanList
+	for _, m := range tableDesc.Mutations {
+		if m.MutationID == mutationID {
+			spanList = append(spanList,
+				jobspb.ResumeSpanList{
+					ResumeSpans: []roachpb.Span{span},
+				},
+			)
+		}
+	}
+	payload := sc.job.Payload()
+	cleanupJob := sc.jobRegistry.NewJob(jobs.Record{
+		Description:   fmt.Sprintf("CLEANUP JOB %d: %s", *sc.job.ID(), payload.Description),
+		Username:      payload.Username,
+		DescriptorIDs: payload.DescriptorIDs,
+		Details:       jobspb.SchemaChangeDetails{ResumeSpanList: spanList},
+		Progress:      jobspb.SchemaChangeProgress{},
+	})
+	if err := cleanupJob.WithTxn(txn).Created(ctx); err != nil {
+		return nil, err
+	}
+	// Set the transaction back to nil so that this job can
+	// be used in other transactions.
+	cleanupJob.WithTxn(nil)
+
+	tableDesc.MutationJobs = append(tableDesc.MutationJobs, sqlbase.TableDescriptor_MutationJob{
+		MutationID: mutationID, JobID: *cleanupJob.ID()})
+
+	// write descriptor, the version has already been incremented.
+	descKey := sqlbase.MakeDescMetadataKey(tableDesc.GetID())
+	descVal := sqlbase.WrapDescriptor(tableDesc)
+	b := txn.NewBatch()
+	b.Put(descKey, descVal)
+	if err := txn.Run(ctx, b); err != nil {
+		return nil, err
+	}
+	return cleanupJob, nil
+}
+
 // deleteIndexMutationsWithReversedColumns deletes mutations with a
 // different mutationID than the schema changer and with an index that
 // references one of the reversed columns. Execute this as a breadth
@@ -1697,8 +1844,8 @@ func (s *SchemaChangeManager) Start(stopper *stop.Stopper) {
 						}
 
 						// Keep track of outstanding schema changes.
-						pendingChanges := table.Adding() ||
-							table.HasDrainingNames() || len(table.Mutations) > 0
+						pendingChanges := table.Adding() || table.HasDrainingNames() ||
+							table.HasReadOnlyEnumMembers() || len(table.Mutations) > 0
 						if pendingChanges {
 							if log.V(2) {
 								log.Infof(ctx, "%s: queue up pending schema change; table: %d, version: %d",
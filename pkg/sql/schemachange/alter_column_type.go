@@ -114,11 +114,16 @@ var classifiers = map[sqlbase.ColumnType_SemanticType]map[sqlbase.ColumnType_Sem
 		},
 		sqlbase.ColumnType_STRING: classifierWidth,
 	},
+	sqlbase.ColumnType_TIME: {
+		sqlbase.ColumnType_TIME: classifierTimePrecision,
+	},
 	sqlbase.ColumnType_TIMESTAMP: {
-		sqlbase.ColumnType_TIMESTAMPTZ: ColumnConversionTrivial.classifier(),
+		sqlbase.ColumnType_TIMESTAMP:   classifierTimePrecision,
+		sqlbase.ColumnType_TIMESTAMPTZ: classifierTimePrecision,
 	},
 	sqlbase.ColumnType_TIMESTAMPTZ: {
-		sqlbase.ColumnType_TIMESTAMP: ColumnConversionTrivial.classifier(),
+		sqlbase.ColumnType_TIMESTAMP:   classifierTimePrecision,
+		sqlbase.ColumnType_TIMESTAMPTZ: classifierTimePrecision,
 	},
 }
 
@@ -179,6 +184,25 @@ func classifierWidth(
 	}
 }
 
+// classifierTimePrecision returns trivial only if the new type retains at
+// least as many fractional digits of the seconds as the existing type.
+// Otherwise, it returns general, since the existing values must be rounded.
+func classifierTimePrecision(
+	oldType *sqlbase.ColumnType, newType *sqlbase.ColumnType,
+) ColumnConversionKind {
+	oldPrecision, newPrecision := int32(coltypes.MaxTimePrecision), int32(coltypes.MaxTimePrecision)
+	if oldType.TimePrecisionIsSet {
+		oldPrecision = oldType.Precision
+	}
+	if newType.TimePrecisionIsSet {
+		newPrecision = newType.Precision
+	}
+	if newPrecision >= oldPrecision {
+		return ColumnConversionTrivial
+	}
+	return ColumnConversionGeneral
+}
+
 // ClassifyConversion takes two ColumnTypes and determines "how hard"
 // the conversion is.  Note that this function will return
 // ColumnConversionTrivial if the two types are equal.
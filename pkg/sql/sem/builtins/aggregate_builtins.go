@@ -19,6 +19,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"sort"
 	"unsafe"
 
 	"github.com/cockroachdb/apd"
@@ -26,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
 	"github.com/cockroachdb/cockroach/pkg/util/arith"
+	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
@@ -73,6 +75,12 @@ func aggPropsNullableArgs() tree.FunctionProperties {
 	return f
 }
 
+func aggPropsOrderedSet() tree.FunctionProperties {
+	f := aggProps()
+	f.OrderedSet = true
+	return f
+}
+
 // aggregates are a special class of builtin functions that are wrapped
 // at execution in a bucketing layer to combine (aggregate) the result
 // of the function being run over many rows.
@@ -125,6 +133,20 @@ var aggregates = map[string]builtinDefinition{
 			"Calculates the average of the selected values."),
 	),
 
+	"bit_and": makeBuiltin(aggProps(),
+		makeAggOverload([]types.T{types.Int}, types.Int, newIntBitAndAggregate,
+			"Calculates the bitwise AND of all non-null input values, or null if none."),
+		makeAggOverload([]types.T{types.BitArray}, types.BitArray, newBitBitAndAggregate,
+			"Calculates the bitwise AND of all non-null input values, or null if none."),
+	),
+
+	"bit_or": makeBuiltin(aggProps(),
+		makeAggOverload([]types.T{types.Int}, types.Int, newIntBitOrAggregate,
+			"Calculates the bitwise OR of all non-null input values, or null if none."),
+		makeAggOverload([]types.T{types.BitArray}, types.BitArray, newBitBitOrAggregate,
+			"Calculates the bitwise OR of all non-null input values, or null if none."),
+	),
+
 	"bool_and": makeBuiltin(aggProps(),
 		makeAggOverload([]types.T{types.Bool}, types.Bool, newBoolAndAggregate,
 			"Calculates the boolean value of `AND`ing all selected values."),
@@ -166,6 +188,26 @@ var aggregates = map[string]builtinDefinition{
 		},
 	),
 
+	"corr": makeBuiltin(aggProps(),
+		makeRegressionAggregateOverloads(types.Float, corrResult,
+			"Calculates the correlation coefficient of the selected values.")...,
+	),
+
+	"covar_pop": makeBuiltin(aggProps(),
+		makeRegressionAggregateOverloads(types.Float, covarPopResult,
+			"Calculates the population covariance of the selected values.")...,
+	),
+
+	"covar_samp": makeBuiltin(aggProps(),
+		makeRegressionAggregateOverloads(types.Float, covarSampResult,
+			"Calculates the sample covariance of the selected values.")...,
+	),
+
+	"every": makeBuiltin(aggProps(),
+		makeAggOverload([]types.T{types.Bool}, types.Bool, newBoolAndAggregate,
+			"Calculates the boolean value of `AND`ing all selected values."),
+	),
+
 	"max": collectOverloads(aggProps(), types.AnyNonArray,
 		func(t types.T) tree.Overload {
 			return makeAggOverload([]types.T{t}, t, newMaxAggregate,
@@ -178,6 +220,69 @@ var aggregates = map[string]builtinDefinition{
 				"Identifies the minimum selected value.")
 		}),
 
+	// The ordered-set aggregates below are called with a WITHIN GROUP clause,
+	// e.g. percentile_disc(0.5) WITHIN GROUP (ORDER BY k). Their first
+	// argument is the expression of the ordering; the following ones are the
+	// direct arguments of the call.
+	"mode": collectOverloads(aggPropsOrderedSet(), types.AnyNonArray,
+		func(t types.T) tree.Overload {
+			return makeAggOverload([]types.T{t}, t, newModeAggregate,
+				"Returns the most frequent input value, choosing the first one in the "+
+					"ordering if there are multiple equally-frequent values.")
+		}),
+
+	"percentile_cont": makeBuiltin(aggPropsOrderedSet(), makePercentileContOverloads()...),
+
+	"percentile_disc": makeBuiltin(aggPropsOrderedSet(), makePercentileDiscOverloads()...),
+
+	// The regression aggregates below take the dependent variable Y as their
+	// first argument and the independent variable X as their second one. The
+	// pairs in which either value is NULL are ignored.
+	"regr_avgx": makeBuiltin(aggProps(),
+		makeRegressionAggregateOverloads(types.Float, regrAvgXResult,
+			"Calculates the average of the independent variable (sum(X)/N).")...,
+	),
+
+	"regr_avgy": makeBuiltin(aggProps(),
+		makeRegressionAggregateOverloads(types.Float, regrAvgYResult,
+			"Calculates the average of the dependent variable (sum(Y)/N).")...,
+	),
+
+	"regr_count": makeBuiltin(aggProps(),
+		makeRegressionAggregateOverloads(types.Int, regrCountResult,
+			"Calculates the number of input rows in which both expressions are non-null.")...,
+	),
+
+	"regr_intercept": makeBuiltin(aggProps(),
+		makeRegressionAggregateOverloads(types.Float, regrInterceptResult,
+			"Calculates the y-intercept of the least-squares-fit linear equation determined by the (X, Y) pairs.")...,
+	),
+
+	"regr_r2": makeBuiltin(aggProps(),
+		makeRegressionAggregateOverloads(types.Float, regrR2Result,
+			"Calculates the square of the correlation coefficient.")...,
+	),
+
+	"regr_slope": makeBuiltin(aggProps(),
+		makeRegressionAggregateOverloads(types.Float, regrSlopeResult,
+			"Calculates the slope of the least-squares-fit linear equation determined by the (X, Y) pairs.")...,
+	),
+
+	"regr_sxx": makeBuiltin(aggProps(),
+		makeRegressionAggregateOverloads(types.Float, regrSXXResult,
+			"Calculates sum of squares of the independent variable.")...,
+	),
+
+	"regr_sxy": makeBuiltin(aggProps(),
+		makeRegressionAggregateOverloads(types.Float, regrSXYResult,
+			"Calculates sum of products of independent times dependent variable.")...,
+	),
+
+	"regr_syy": makeBuiltin(aggProps(),
+		makeRegressionAggregateOverloads(types.Float, regrSYYResult,
+			"Calculates sum of squares of the dependent variable.")...,
+	),
+
 	"string_agg": makeBuiltin(aggPropsNullableArgs(),
 		makeAggOverload([]types.T{types.String, types.String}, types.String, newStringConcatAggregate,
 			"Concatenates all selected values using the provided delimiter."),
@@ -252,6 +357,103 @@ var aggregates = map[string]builtinDefinition{
 		),
 	)),
 
+	// final_(var_pop|stddev_pop) are the counterparts of
+	// final_(variance|stddev) for the population variance and standard
+	// deviation. The input signature is: SQDIFF, SUM, COUNT
+	"final_var_pop": makePrivate(makeBuiltin(aggProps(),
+		makeAggOverload(
+			[]types.T{types.Decimal, types.Decimal, types.Int},
+			types.Decimal,
+			newDecimalFinalVarPopAggregate,
+			"Calculates the population variance from the selected locally-computed squared difference values.",
+		),
+		makeAggOverload(
+			[]types.T{types.Float, types.Float, types.Int},
+			types.Float,
+			newFloatFinalVarPopAggregate,
+			"Calculates the population variance from the selected locally-computed squared difference values.",
+		),
+	)),
+
+	"final_stddev_pop": makePrivate(makeBuiltin(aggProps(),
+		makeAggOverload(
+			[]types.T{types.Decimal, types.Decimal, types.Int},
+			types.Decimal,
+			newDecimalFinalStdDevPopAggregate,
+			"Calculates the population standard deviation from the selected locally-computed squared difference values.",
+		),
+		makeAggOverload(
+			[]types.T{types.Float, types.Float, types.Int},
+			types.Float,
+			newFloatFinalStdDevPopAggregate,
+			"Calculates the population standard deviation from the selected locally-computed squared difference values.",
+		),
+	)),
+
+	// transition_regression_aggregate computes the state of the regression
+	// aggregates (corr, covar_pop, regr_slope, etc) over the local input of
+	// distributed aggregations. The final_* counterparts of these aggregates
+	// combine the local states and compute the global result from them.
+	"transition_regression_aggregate": makePrivate(makeBuiltin(aggProps(),
+		makeRegressionAggregateOverloads(types.TArray{Typ: types.Float}, regressionStateResult,
+			"Calculates the transition values of the regression aggregates.")...,
+	)),
+
+	"final_corr": makePrivate(makeBuiltin(aggProps(),
+		makeFinalRegressionAggregateOverload(types.Float, corrResult,
+			"Calculates the correlation coefficient from the selected locally-computed regression states."),
+	)),
+
+	"final_covar_pop": makePrivate(makeBuiltin(aggProps(),
+		makeFinalRegressionAggregateOverload(types.Float, covarPopResult,
+			"Calculates the population covariance from the selected locally-computed regression states."),
+	)),
+
+	"final_covar_samp": makePrivate(makeBuiltin(aggProps(),
+		makeFinalRegressionAggregateOverload(types.Float, covarSampResult,
+			"Calculates the sample covariance from the selected locally-computed regression states."),
+	)),
+
+	"final_regr_avgx": makePrivate(makeBuiltin(aggProps(),
+		makeFinalRegressionAggregateOverload(types.Float, regrAvgXResult,
+			"Calculates the average of the independent variable from the selected locally-computed regression states."),
+	)),
+
+	"final_regr_avgy": makePrivate(makeBuiltin(aggProps(),
+		makeFinalRegressionAggregateOverload(types.Float, regrAvgYResult,
+			"Calculates the average of the dependent variable from the selected locally-computed regression states."),
+	)),
+
+	"final_regr_intercept": makePrivate(makeBuiltin(aggProps(),
+		makeFinalRegressionAggregateOverload(types.Float, regrInterceptResult,
+			"Calculates the y-intercept of the least-squares-fit linear equation from the selected locally-computed regression states."),
+	)),
+
+	"final_regr_r2": makePrivate(makeBuiltin(aggProps(),
+		makeFinalRegressionAggregateOverload(types.Float, regrR2Result,
+			"Calculates the square of the correlation coefficient from the selected locally-computed regression states."),
+	)),
+
+	"final_regr_slope": makePrivate(makeBuiltin(aggProps(),
+		makeFinalRegressionAggregateOverload(types.Float, regrSlopeResult,
+			"Calculates the slope of the least-squares-fit linear equation from the selected locally-computed regression states."),
+	)),
+
+	"final_regr_sxx": makePrivate(makeBuiltin(aggProps(),
+		makeFinalRegressionAggregateOverload(types.Float, regrSXXResult,
+			"Calculates sum of squares of the independent variable from the selected locally-computed regression states."),
+	)),
+
+	"final_regr_sxy": makePrivate(makeBuiltin(aggProps(),
+		makeFinalRegressionAggregateOverload(types.Float, regrSXYResult,
+			"Calculates sum of products of independent times dependent variable from the selected locally-computed regression states."),
+	)),
+
+	"final_regr_syy": makePrivate(makeBuiltin(aggProps(),
+		makeFinalRegressionAggregateOverload(types.Float, regrSYYResult,
+			"Calculates sum of squares of the dependent variable from the selected locally-computed regression states."),
+	)),
+
 	"variance": makeBuiltin(aggProps(),
 		makeAggOverload([]types.T{types.Int}, types.Decimal, newIntVarianceAggregate,
 			"Calculates the variance of the selected values."),
@@ -261,6 +463,24 @@ var aggregates = map[string]builtinDefinition{
 			"Calculates the variance of the selected values."),
 	),
 
+	"var_samp": makeBuiltin(aggProps(),
+		makeAggOverload([]types.T{types.Int}, types.Decimal, newIntVarianceAggregate,
+			"Calculates the variance of the selected values."),
+		makeAggOverload([]types.T{types.Decimal}, types.Decimal, newDecimalVarianceAggregate,
+			"Calculates the variance of the selected values."),
+		makeAggOverload([]types.T{types.Float}, types.Float, newFloatVarianceAggregate,
+			"Calculates the variance of the selected values."),
+	),
+
+	"var_pop": makeBuiltin(aggProps(),
+		makeAggOverload([]types.T{types.Int}, types.Decimal, newIntVarPopAggregate,
+			"Calculates the population variance of the selected values."),
+		makeAggOverload([]types.T{types.Decimal}, types.Decimal, newDecimalVarPopAggregate,
+			"Calculates the population variance of the selected values."),
+		makeAggOverload([]types.T{types.Float}, types.Float, newFloatVarPopAggregate,
+			"Calculates the population variance of the selected values."),
+	),
+
 	"stddev": makeBuiltin(aggProps(),
 		makeAggOverload([]types.T{types.Int}, types.Decimal, newIntStdDevAggregate,
 			"Calculates the standard deviation of the selected values."),
@@ -270,6 +490,24 @@ var aggregates = map[string]builtinDefinition{
 			"Calculates the standard deviation of the selected values."),
 	),
 
+	"stddev_samp": makeBuiltin(aggProps(),
+		makeAggOverload([]types.T{types.Int}, types.Decimal, newIntStdDevAggregate,
+			"Calculates the standard deviation of the selected values."),
+		makeAggOverload([]types.T{types.Decimal}, types.Decimal, newDecimalStdDevAggregate,
+			"Calculates the standard deviation of the selected values."),
+		makeAggOverload([]types.T{types.Float}, types.Float, newFloatStdDevAggregate,
+			"Calculates the standard deviation of the selected values."),
+	),
+
+	"stddev_pop": makeBuiltin(aggProps(),
+		makeAggOverload([]types.T{types.Int}, types.Decimal, newIntStdDevPopAggregate,
+			"Calculates the population standard deviation of the selected values."),
+		makeAggOverload([]types.T{types.Decimal}, types.Decimal, newDecimalStdDevPopAggregate,
+			"Calculates the population standard deviation of the selected values."),
+		makeAggOverload([]types.T{types.Float}, types.Float, newFloatStdDevPopAggregate,
+			"Calculates the population standard deviation of the selected values."),
+	),
+
 	"xor_agg": makeBuiltin(aggProps(),
 		makeAggOverload([]types.T{types.Bytes}, types.Bytes, newBytesXorAggregate,
 			"Calculates the bitwise XOR of the selected values."),
@@ -299,6 +537,62 @@ var aggregates = map[string]builtinDefinition{
 		))),
 }
 
+// percentileFractionArrayTypes are the types of the arrays of fractions
+// accepted by percentile_disc and percentile_cont. Array literals of numeric
+// constants are typed as DECIMAL[], so those are accepted as well as FLOAT[].
+var percentileFractionArrayTypes = []types.T{
+	types.TArray{Typ: types.Float},
+	types.TArray{Typ: types.Decimal},
+}
+
+// makePercentileDiscOverloads returns the overloads of percentile_disc for
+// all the types of input values.
+func makePercentileDiscOverloads() []tree.Overload {
+	const info = "Discrete percentile: returns the first input value whose " +
+		"position in the ordering equals or exceeds the specified fraction."
+	const infoMulti = "Multiple discrete percentiles: returns an array of results " +
+		"matching the shape of the fractions parameter, with each non-null element " +
+		"replaced by the input value corresponding to that percentile."
+	var overloads []tree.Overload
+	for _, t := range types.AnyNonArray {
+		overloads = append(overloads,
+			makeAggOverload([]types.T{t, types.Float}, t, newPercentileDiscAggregate, info))
+		if !types.IsValidArrayElementType(t) {
+			continue
+		}
+		for _, fractions := range percentileFractionArrayTypes {
+			overloads = append(overloads, makeAggOverload(
+				[]types.T{t, fractions}, types.TArray{Typ: t}, newPercentileDiscAggregate, infoMulti))
+		}
+	}
+	return overloads
+}
+
+// makePercentileContOverloads returns the overloads of percentile_cont for
+// the types of input values that can be interpolated.
+func makePercentileContOverloads() []tree.Overload {
+	const info = "Continuous percentile: returns a value corresponding to the " +
+		"specified fraction in the ordering, interpolating between adjacent input " +
+		"items if needed."
+	const infoMulti = "Multiple continuous percentiles: returns an array of results " +
+		"matching the shape of the fractions parameter, with each non-null element " +
+		"replaced by the value corresponding to that percentile."
+	var overloads []tree.Overload
+	for _, t := range []types.T{types.Int, types.Float, types.Decimal, types.Interval} {
+		ret := t
+		if t == types.Int {
+			ret = types.Float
+		}
+		overloads = append(overloads,
+			makeAggOverload([]types.T{t, types.Float}, ret, newPercentileContAggregate, info))
+		for _, fractions := range percentileFractionArrayTypes {
+			overloads = append(overloads, makeAggOverload(
+				[]types.T{t, fractions}, types.TArray{Typ: ret}, newPercentileContAggregate, infoMulti))
+		}
+	}
+	return overloads
+}
+
 // AnyNotNull is the name of the aggregate returned by NewAnyNotNullAggregate.
 const AnyNotNull = "any_not_null"
 
@@ -398,13 +692,24 @@ var _ tree.AggregateFunc = &floatVarianceAggregate{}
 var _ tree.AggregateFunc = &decimalVarianceAggregate{}
 var _ tree.AggregateFunc = &floatStdDevAggregate{}
 var _ tree.AggregateFunc = &decimalStdDevAggregate{}
+var _ tree.AggregateFunc = &floatVarPopAggregate{}
+var _ tree.AggregateFunc = &decimalVarPopAggregate{}
 var _ tree.AggregateFunc = &anyNotNullAggregate{}
 var _ tree.AggregateFunc = &concatAggregate{}
 var _ tree.AggregateFunc = &boolAndAggregate{}
 var _ tree.AggregateFunc = &boolOrAggregate{}
 var _ tree.AggregateFunc = &bytesXorAggregate{}
 var _ tree.AggregateFunc = &intXorAggregate{}
+var _ tree.AggregateFunc = &intBitAndAggregate{}
+var _ tree.AggregateFunc = &intBitOrAggregate{}
+var _ tree.AggregateFunc = &bitBitAndAggregate{}
+var _ tree.AggregateFunc = &bitBitOrAggregate{}
 var _ tree.AggregateFunc = &jsonAggregate{}
+var _ tree.AggregateFunc = &modeAggregate{}
+var _ tree.AggregateFunc = &percentileDiscAggregate{}
+var _ tree.AggregateFunc = &percentileContAggregate{}
+var _ tree.AggregateFunc = &regressionAggregate{}
+var _ tree.AggregateFunc = &finalRegressionAggregate{}
 
 const sizeOfArrayAggregate = int64(unsafe.Sizeof(arrayAggregate{}))
 const sizeOfAvgAggregate = int64(unsafe.Sizeof(avgAggregate{}))
@@ -426,13 +731,24 @@ const sizeOfFloatVarianceAggregate = int64(unsafe.Sizeof(floatVarianceAggregate{
 const sizeOfDecimalVarianceAggregate = int64(unsafe.Sizeof(decimalVarianceAggregate{}))
 const sizeOfFloatStdDevAggregate = int64(unsafe.Sizeof(floatStdDevAggregate{}))
 const sizeOfDecimalStdDevAggregate = int64(unsafe.Sizeof(decimalStdDevAggregate{}))
+const sizeOfFloatVarPopAggregate = int64(unsafe.Sizeof(floatVarPopAggregate{}))
+const sizeOfDecimalVarPopAggregate = int64(unsafe.Sizeof(decimalVarPopAggregate{}))
 const sizeOfAnyNotNullAggregate = int64(unsafe.Sizeof(anyNotNullAggregate{}))
 const sizeOfConcatAggregate = int64(unsafe.Sizeof(concatAggregate{}))
 const sizeOfBoolAndAggregate = int64(unsafe.Sizeof(boolAndAggregate{}))
 const sizeOfBoolOrAggregate = int64(unsafe.Sizeof(boolOrAggregate{}))
 const sizeOfBytesXorAggregate = int64(unsafe.Sizeof(bytesXorAggregate{}))
 const sizeOfIntXorAggregate = int64(unsafe.Sizeof(intXorAggregate{}))
+const sizeOfIntBitAndAggregate = int64(unsafe.Sizeof(intBitAndAggregate{}))
+const sizeOfIntBitOrAggregate = int64(unsafe.Sizeof(intBitOrAggregate{}))
+const sizeOfBitBitAndAggregate = int64(unsafe.Sizeof(bitBitAndAggregate{}))
+const sizeOfBitBitOrAggregate = int64(unsafe.Sizeof(bitBitOrAggregate{}))
 const sizeOfJSONAggregate = int64(unsafe.Sizeof(jsonAggregate{}))
+const sizeOfModeAggregate = int64(unsafe.Sizeof(modeAggregate{}))
+const sizeOfPercentileDiscAggregate = int64(unsafe.Sizeof(percentileDiscAggregate{}))
+const sizeOfPercentileContAggregate = int64(unsafe.Sizeof(percentileContAggregate{}))
+const sizeOfRegressionAggregate = int64(unsafe.Sizeof(regressionAggregate{}))
+const sizeOfFinalRegressionAggregate = int64(unsafe.Sizeof(finalRegressionAggregate{}))
 
 // See NewAnyNotNullAggregate.
 type anyNotNullAggregate struct {
@@ -1695,6 +2011,114 @@ func (a *decimalVarianceAggregate) Size() int64 {
 	return sizeOfDecimalVarianceAggregate
 }
 
+type floatVarPopAggregate struct {
+	agg floatSqrDiff
+}
+
+type decimalVarPopAggregate struct {
+	agg decimalSqrDiff
+}
+
+// Like Variance and FinalVariance above, VarPop and FinalVarPop aggregators
+// only differ by the square difference aggregator they employ.
+func newIntVarPopAggregate(
+	_ []types.T, evalCtx *tree.EvalContext, _ tree.Datums,
+) tree.AggregateFunc {
+	return &decimalVarPopAggregate{agg: newIntSqrDiff(evalCtx)}
+}
+
+func newFloatVarPopAggregate(_ []types.T, _ *tree.EvalContext, _ tree.Datums) tree.AggregateFunc {
+	return &floatVarPopAggregate{agg: newFloatSqrDiff()}
+}
+
+func newDecimalVarPopAggregate(
+	_ []types.T, evalCtx *tree.EvalContext, _ tree.Datums,
+) tree.AggregateFunc {
+	return &decimalVarPopAggregate{agg: newDecimalSqrDiff(evalCtx)}
+}
+
+func newFloatFinalVarPopAggregate(
+	_ []types.T, _ *tree.EvalContext, _ tree.Datums,
+) tree.AggregateFunc {
+	return &floatVarPopAggregate{agg: newFloatSumSqrDiffs()}
+}
+
+func newDecimalFinalVarPopAggregate(
+	_ []types.T, evalCtx *tree.EvalContext, _ tree.Datums,
+) tree.AggregateFunc {
+	return &decimalVarPopAggregate{agg: newDecimalSumSqrDiffs(evalCtx)}
+}
+
+// Add is part of the tree.AggregateFunc interface.
+//  VarPop: VALUE(float)
+//  FinalVarPop: SQRDIFF(float), SUM(float), COUNT(int)
+func (a *floatVarPopAggregate) Add(
+	ctx context.Context, firstArg tree.Datum, otherArgs ...tree.Datum,
+) error {
+	return a.agg.Add(ctx, firstArg, otherArgs...)
+}
+
+// Add is part of the tree.AggregateFunc interface.
+//  VarPop: VALUE(int|decimal)
+//  FinalVarPop: SQRDIFF(decimal), SUM(decimal), COUNT(int)
+func (a *decimalVarPopAggregate) Add(
+	ctx context.Context, firstArg tree.Datum, otherArgs ...tree.Datum,
+) error {
+	return a.agg.Add(ctx, firstArg, otherArgs...)
+}
+
+// Result calculates the population variance from the member square difference
+// aggregator.
+func (a *floatVarPopAggregate) Result() (tree.Datum, error) {
+	if a.agg.Count() < 1 {
+		return tree.DNull, nil
+	}
+	sqrDiff, err := a.agg.Result()
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewDFloat(tree.DFloat(float64(*sqrDiff.(*tree.DFloat)) / float64(a.agg.Count()))), nil
+}
+
+// Result calculates the population variance from the member square difference
+// aggregator.
+func (a *decimalVarPopAggregate) Result() (tree.Datum, error) {
+	if a.agg.Count().Cmp(decimalOne) < 0 {
+		return tree.DNull, nil
+	}
+	sqrDiff, err := a.agg.Result()
+	if err != nil {
+		return nil, err
+	}
+	dd := &tree.DDecimal{}
+	if _, err = tree.DecimalCtx.Quo(&dd.Decimal, &sqrDiff.(*tree.DDecimal).Decimal, a.agg.Count()); err != nil {
+		return nil, err
+	}
+	// Remove trailing zeros, as in decimalVarianceAggregate.
+	dd.Decimal.Reduce(&dd.Decimal)
+	return dd, nil
+}
+
+// Close is part of the tree.AggregateFunc interface.
+func (a *floatVarPopAggregate) Close(ctx context.Context) {
+	a.agg.Close(ctx)
+}
+
+// Size is part of the tree.AggregateFunc interface.
+func (a *floatVarPopAggregate) Size() int64 {
+	return sizeOfFloatVarPopAggregate
+}
+
+// Close is part of the tree.AggregateFunc interface.
+func (a *decimalVarPopAggregate) Close(ctx context.Context) {
+	a.agg.Close(ctx)
+}
+
+// Size is part of the tree.AggregateFunc interface.
+func (a *decimalVarPopAggregate) Size() int64 {
+	return sizeOfDecimalVarPopAggregate
+}
+
 type floatStdDevAggregate struct {
 	agg tree.AggregateFunc
 }
@@ -1739,6 +2163,38 @@ func newDecimalFinalStdDevAggregate(
 	return &decimalStdDevAggregate{agg: newDecimalFinalVarianceAggregate(params, evalCtx, arguments)}
 }
 
+// StdDevPop and FinalStdDevPop take the square root of the population variance
+// instead of the sample variance.
+func newIntStdDevPopAggregate(
+	params []types.T, evalCtx *tree.EvalContext, arguments tree.Datums,
+) tree.AggregateFunc {
+	return &decimalStdDevAggregate{agg: newIntVarPopAggregate(params, evalCtx, arguments)}
+}
+
+func newFloatStdDevPopAggregate(
+	params []types.T, evalCtx *tree.EvalContext, arguments tree.Datums,
+) tree.AggregateFunc {
+	return &floatStdDevAggregate{agg: newFloatVarPopAggregate(params, evalCtx, arguments)}
+}
+
+func newDecimalStdDevPopAggregate(
+	params []types.T, evalCtx *tree.EvalContext, arguments tree.Datums,
+) tree.AggregateFunc {
+	return &decimalStdDevAggregate{agg: newDecimalVarPopAggregate(params, evalCtx, arguments)}
+}
+
+func newFloatFinalStdDevPopAggregate(
+	params []types.T, evalCtx *tree.EvalContext, arguments tree.Datums,
+) tree.AggregateFunc {
+	return &floatStdDevAggregate{agg: newFloatFinalVarPopAggregate(params, evalCtx, arguments)}
+}
+
+func newDecimalFinalStdDevPopAggregate(
+	params []types.T, evalCtx *tree.EvalContext, arguments tree.Datums,
+) tree.AggregateFunc {
+	return &decimalStdDevAggregate{agg: newDecimalFinalVarPopAggregate(params, evalCtx, arguments)}
+}
+
 // Add implements the tree.AggregateFunc interface.
 // The signature of the datums is:
 //  StdDev: VALUE(float)
@@ -1893,6 +2349,172 @@ func (a *intXorAggregate) Size() int64 {
 	return sizeOfIntXorAggregate
 }
 
+type intBitAndAggregate struct {
+	result     int64
+	sawNonNull bool
+}
+
+func newIntBitAndAggregate(_ []types.T, _ *tree.EvalContext, _ tree.Datums) tree.AggregateFunc {
+	return &intBitAndAggregate{}
+}
+
+// Add inserts one value into the running bitwise AND.
+func (a *intBitAndAggregate) Add(_ context.Context, datum tree.Datum, _ ...tree.Datum) error {
+	if datum == tree.DNull {
+		return nil
+	}
+	x := int64(*datum.(*tree.DInt))
+	if !a.sawNonNull {
+		a.result = x
+		a.sawNonNull = true
+	} else {
+		a.result &= x
+	}
+	return nil
+}
+
+// Result returns the bitwise AND.
+func (a *intBitAndAggregate) Result() (tree.Datum, error) {
+	if !a.sawNonNull {
+		return tree.DNull, nil
+	}
+	return tree.NewDInt(tree.DInt(a.result)), nil
+}
+
+// Close is part of the tree.AggregateFunc interface.
+func (a *intBitAndAggregate) Close(context.Context) {}
+
+// Size is part of the tree.AggregateFunc interface.
+func (a *intBitAndAggregate) Size() int64 {
+	return sizeOfIntBitAndAggregate
+}
+
+type intBitOrAggregate struct {
+	result     int64
+	sawNonNull bool
+}
+
+func newIntBitOrAggregate(_ []types.T, _ *tree.EvalContext, _ tree.Datums) tree.AggregateFunc {
+	return &intBitOrAggregate{}
+}
+
+// Add inserts one value into the running bitwise OR.
+func (a *intBitOrAggregate) Add(_ context.Context, datum tree.Datum, _ ...tree.Datum) error {
+	if datum == tree.DNull {
+		return nil
+	}
+	a.result |= int64(*datum.(*tree.DInt))
+	a.sawNonNull = true
+	return nil
+}
+
+// Result returns the bitwise OR.
+func (a *intBitOrAggregate) Result() (tree.Datum, error) {
+	if !a.sawNonNull {
+		return tree.DNull, nil
+	}
+	return tree.NewDInt(tree.DInt(a.result)), nil
+}
+
+// Close is part of the tree.AggregateFunc interface.
+func (a *intBitOrAggregate) Close(context.Context) {}
+
+// Size is part of the tree.AggregateFunc interface.
+func (a *intBitOrAggregate) Size() int64 {
+	return sizeOfIntBitOrAggregate
+}
+
+type bitBitAndAggregate struct {
+	result     bitarray.BitArray
+	sawNonNull bool
+}
+
+func newBitBitAndAggregate(_ []types.T, _ *tree.EvalContext, _ tree.Datums) tree.AggregateFunc {
+	return &bitBitAndAggregate{}
+}
+
+// Add inserts one value into the running bitwise AND.
+func (a *bitBitAndAggregate) Add(_ context.Context, datum tree.Datum, _ ...tree.Datum) error {
+	if datum == tree.DNull {
+		return nil
+	}
+	x := tree.MustBeDBitArray(datum).BitArray
+	if !a.sawNonNull {
+		a.result = x
+		a.sawNonNull = true
+		return nil
+	}
+	if a.result.BitLen() != x.BitLen() {
+		return newCannotMixBitArraySizesError("AND")
+	}
+	a.result = bitarray.And(a.result, x)
+	return nil
+}
+
+// Result returns the bitwise AND.
+func (a *bitBitAndAggregate) Result() (tree.Datum, error) {
+	if !a.sawNonNull {
+		return tree.DNull, nil
+	}
+	return &tree.DBitArray{BitArray: a.result}, nil
+}
+
+// Close is part of the tree.AggregateFunc interface.
+func (a *bitBitAndAggregate) Close(context.Context) {}
+
+// Size is part of the tree.AggregateFunc interface.
+func (a *bitBitAndAggregate) Size() int64 {
+	return sizeOfBitBitAndAggregate
+}
+
+type bitBitOrAggregate struct {
+	result     bitarray.BitArray
+	sawNonNull bool
+}
+
+func newBitBitOrAggregate(_ []types.T, _ *tree.EvalContext, _ tree.Datums) tree.AggregateFunc {
+	return &bitBitOrAggregate{}
+}
+
+// Add inserts one value into the running bitwise OR.
+func (a *bitBitOrAggregate) Add(_ context.Context, datum tree.Datum, _ ...tree.Datum) error {
+	if datum == tree.DNull {
+		return nil
+	}
+	x := tree.MustBeDBitArray(datum).BitArray
+	if !a.sawNonNull {
+		a.result = x
+		a.sawNonNull = true
+		return nil
+	}
+	if a.result.BitLen() != x.BitLen() {
+		return newCannotMixBitArraySizesError("OR")
+	}
+	a.result = bitarray.Or(a.result, x)
+	return nil
+}
+
+// Result returns the bitwise OR.
+func (a *bitBitOrAggregate) Result() (tree.Datum, error) {
+	if !a.sawNonNull {
+		return tree.DNull, nil
+	}
+	return &tree.DBitArray{BitArray: a.result}, nil
+}
+
+// Close is part of the tree.AggregateFunc interface.
+func (a *bitBitOrAggregate) Close(context.Context) {}
+
+// Size is part of the tree.AggregateFunc interface.
+func (a *bitBitOrAggregate) Size() int64 {
+	return sizeOfBitBitOrAggregate
+}
+
+func newCannotMixBitArraySizesError(op string) error {
+	return pgerror.NewErrorf(pgerror.CodeStringDataLengthMismatchError,
+		"cannot %s bit strings of different sizes", op)
+}
+
 type jsonAggregate struct {
 	builder    *json.ArrayBuilderWithCounter
 	acc        mon.BoundAccount
@@ -1940,3 +2562,562 @@ func (a *jsonAggregate) Close(ctx context.Context) {
 func (a *jsonAggregate) Size() int64 {
 	return sizeOfJSONAggregate
 }
+
+// orderedSetAggregate accumulates the non-NULL input values of an ordered-set
+// aggregate, which are sorted when the result is computed.
+type orderedSetAggregate struct {
+	evalCtx *tree.EvalContext
+	values  tree.Datums
+	sorted  bool
+	acc     mon.BoundAccount
+}
+
+func makeOrderedSetAggregate(evalCtx *tree.EvalContext) orderedSetAggregate {
+	return orderedSetAggregate{
+		evalCtx: evalCtx,
+		acc:     evalCtx.Mon.MakeBoundAccount(),
+	}
+}
+
+// add accumulates the passed datum.
+func (a *orderedSetAggregate) add(ctx context.Context, datum tree.Datum) error {
+	if datum == tree.DNull {
+		return nil
+	}
+	if err := a.acc.Grow(ctx, int64(datum.Size())); err != nil {
+		return err
+	}
+	a.values = append(a.values, datum)
+	a.sorted = false
+	return nil
+}
+
+// sortedValues returns the accumulated values in ascending order.
+func (a *orderedSetAggregate) sortedValues() tree.Datums {
+	if !a.sorted {
+		sort.Slice(a.values, func(i, j int) bool {
+			return a.values[i].Compare(a.evalCtx, a.values[j]) < 0
+		})
+		a.sorted = true
+	}
+	return a.values
+}
+
+// Close allows the aggregate to release the memory it requested during
+// operation.
+func (a *orderedSetAggregate) Close(ctx context.Context) {
+	a.acc.Close(ctx)
+}
+
+type modeAggregate struct {
+	orderedSetAggregate
+}
+
+func newModeAggregate(_ []types.T, evalCtx *tree.EvalContext, _ tree.Datums) tree.AggregateFunc {
+	return &modeAggregate{orderedSetAggregate: makeOrderedSetAggregate(evalCtx)}
+}
+
+// Add accumulates the passed datum.
+func (a *modeAggregate) Add(ctx context.Context, datum tree.Datum, _ ...tree.Datum) error {
+	return a.add(ctx, datum)
+}
+
+// Result returns the most frequent value, or the smallest one in case of a
+// tie.
+func (a *modeAggregate) Result() (tree.Datum, error) {
+	values := a.sortedValues()
+	if len(values) == 0 {
+		return tree.DNull, nil
+	}
+	mode, modeCount := values[0], 0
+	for i := 0; i < len(values); {
+		j := i + 1
+		for j < len(values) && values[j].Compare(a.evalCtx, values[i]) == 0 {
+			j++
+		}
+		if j-i > modeCount {
+			mode, modeCount = values[i], j-i
+		}
+		i = j
+	}
+	return mode, nil
+}
+
+// Size is part of the tree.AggregateFunc interface.
+func (a *modeAggregate) Size() int64 {
+	return sizeOfModeAggregate
+}
+
+// percentileAggregate is the common part of percentile_disc and
+// percentile_cont. The fractions are either a single DFloat or an array of
+// DFloats or DDecimals.
+type percentileAggregate struct {
+	orderedSetAggregate
+	fractions tree.Datum
+}
+
+func makePercentileAggregate(
+	evalCtx *tree.EvalContext, arguments tree.Datums,
+) percentileAggregate {
+	a := percentileAggregate{
+		orderedSetAggregate: makeOrderedSetAggregate(evalCtx),
+		fractions:           tree.DNull,
+	}
+	if len(arguments) > 0 {
+		a.fractions = arguments[0]
+	}
+	return a
+}
+
+// Add accumulates the passed datum. The fractions are passed along with each
+// datum when they are not constant.
+func (a *percentileAggregate) Add(
+	ctx context.Context, datum tree.Datum, others ...tree.Datum,
+) error {
+	if len(others) > 0 {
+		a.fractions = others[0]
+	}
+	return a.add(ctx, datum)
+}
+
+// result computes the percentile of each fraction using the given function,
+// which is passed the sorted input values. The results of multiple fractions
+// are returned in an array of elements of type typ.
+func (a *percentileAggregate) result(
+	typ types.T, percentile func(values tree.Datums, fraction float64) (tree.Datum, error),
+) (tree.Datum, error) {
+	values := a.sortedValues()
+	if len(values) == 0 {
+		return tree.DNull, nil
+	}
+	eval := func(fraction tree.Datum) (tree.Datum, error) {
+		if fraction == tree.DNull {
+			return tree.DNull, nil
+		}
+		var f float64
+		switch t := fraction.(type) {
+		case *tree.DFloat:
+			f = float64(*t)
+		case *tree.DDecimal:
+			var err error
+			if f, err = t.Float64(); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, pgerror.NewAssertionErrorf("unexpected percentile fraction %s", fraction)
+		}
+		if !(f >= 0 && f <= 1) {
+			return nil, pgerror.NewErrorf(pgerror.CodeNumericValueOutOfRangeError,
+				"percentile value %g is not between 0 and 1", f)
+		}
+		return percentile(values, f)
+	}
+	arr, ok := a.fractions.(*tree.DArray)
+	if !ok {
+		return eval(a.fractions)
+	}
+	res := tree.NewDArray(typ)
+	for _, fraction := range arr.Array {
+		d, err := eval(fraction)
+		if err != nil {
+			return nil, err
+		}
+		if err := res.Append(d); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+type percentileDiscAggregate struct {
+	percentileAggregate
+	typ types.T
+}
+
+func newPercentileDiscAggregate(
+	params []types.T, evalCtx *tree.EvalContext, arguments tree.Datums,
+) tree.AggregateFunc {
+	return &percentileDiscAggregate{
+		percentileAggregate: makePercentileAggregate(evalCtx, arguments),
+		typ:                 params[0],
+	}
+}
+
+// Result returns the first value whose position in the ordering equals or
+// exceeds each fraction.
+func (a *percentileDiscAggregate) Result() (tree.Datum, error) {
+	return a.result(a.typ, func(values tree.Datums, fraction float64) (tree.Datum, error) {
+		idx := int(math.Ceil(fraction*float64(len(values)))) - 1
+		if idx < 0 {
+			idx = 0
+		}
+		return values[idx], nil
+	})
+}
+
+// Size is part of the tree.AggregateFunc interface.
+func (a *percentileDiscAggregate) Size() int64 {
+	return sizeOfPercentileDiscAggregate
+}
+
+type percentileContAggregate struct {
+	percentileAggregate
+	typ types.T
+}
+
+func newPercentileContAggregate(
+	params []types.T, evalCtx *tree.EvalContext, arguments tree.Datums,
+) tree.AggregateFunc {
+	typ := params[0]
+	if typ == types.Int {
+		typ = types.Float
+	}
+	return &percentileContAggregate{
+		percentileAggregate: makePercentileAggregate(evalCtx, arguments),
+		typ:                 typ,
+	}
+}
+
+// Result returns the value corresponding to each fraction in the ordering,
+// interpolating linearly between the adjacent values if needed.
+func (a *percentileContAggregate) Result() (tree.Datum, error) {
+	return a.result(a.typ, func(values tree.Datums, fraction float64) (tree.Datum, error) {
+		pos := fraction * float64(len(values)-1)
+		lo, hi := values[int(math.Floor(pos))], values[int(math.Ceil(pos))]
+		weight := pos - math.Floor(pos)
+		switch t := lo.(type) {
+		case *tree.DInt:
+			l, h := float64(*t), float64(*hi.(*tree.DInt))
+			return tree.NewDFloat(tree.DFloat(l + (h-l)*weight)), nil
+		case *tree.DFloat:
+			l, h := float64(*t), float64(*hi.(*tree.DFloat))
+			return tree.NewDFloat(tree.DFloat(l + (h-l)*weight)), nil
+		case *tree.DDecimal:
+			var w apd.Decimal
+			if _, err := w.SetFloat64(weight); err != nil {
+				return nil, err
+			}
+			res := &tree.DDecimal{}
+			if _, err := tree.ExactCtx.Sub(&res.Decimal, &hi.(*tree.DDecimal).Decimal, &t.Decimal); err != nil {
+				return nil, err
+			}
+			if _, err := tree.IntermediateCtx.Mul(&res.Decimal, &res.Decimal, &w); err != nil {
+				return nil, err
+			}
+			if _, err := tree.DecimalCtx.Add(&res.Decimal, &res.Decimal, &t.Decimal); err != nil {
+				return nil, err
+			}
+			return res, nil
+		case *tree.DInterval:
+			l, h := t.Duration, hi.(*tree.DInterval).Duration
+			return &tree.DInterval{Duration: l.Add(h.Sub(l).MulFloat(weight))}, nil
+		default:
+			return nil, pgerror.NewAssertionErrorf("unexpected type %s in percentile_cont", lo.ResolvedType())
+		}
+	})
+}
+
+// Size is part of the tree.AggregateFunc interface.
+func (a *percentileContAggregate) Size() int64 {
+	return sizeOfPercentileContAggregate
+}
+
+// regressionState is the state accumulated by the regression aggregates over
+// pairs of values (Y, X): the number of pairs N, the sums Sx and Sy of the
+// values, and the sums Sxx, Syy and Sxy of the squares and products of their
+// differences from the means. Like in PostgreSQL, the sums of squares are
+// maintained with the Youngs-Cramer algorithm, which is less prone to
+// precision loss than summing the squares of the values.
+type regressionState struct {
+	n, sx, sxx, sy, syy, sxy float64
+}
+
+// numRegressionStateValues is the number of values in the array representation
+// of a regressionState.
+const numRegressionStateValues = 6
+
+// add accumulates one more pair of values.
+func (s *regressionState) add(y, x float64) {
+	n := s.n + 1
+	sx := s.sx + x
+	sy := s.sy + y
+	if s.n > 0 {
+		tmpX := x*n - sx
+		tmpY := y*n - sy
+		scale := 1 / (n * s.n)
+		s.sxx += tmpX * tmpX * scale
+		s.syy += tmpY * tmpY * scale
+		s.sxy += tmpX * tmpY * scale
+	}
+	s.n, s.sx, s.sy = n, sx, sy
+}
+
+// combine merges the state accumulated over another set of pairs into s.
+func (s *regressionState) combine(o regressionState) {
+	if o.n == 0 {
+		return
+	}
+	if s.n == 0 {
+		*s = o
+		return
+	}
+	n := s.n + o.n
+	tmpX := s.sx/s.n - o.sx/o.n
+	tmpY := s.sy/s.n - o.sy/o.n
+	scale := s.n * o.n / n
+	s.sxx += o.sxx + tmpX*tmpX*scale
+	s.syy += o.syy + tmpY*tmpY*scale
+	s.sxy += o.sxy + tmpX*tmpY*scale
+	s.n, s.sx, s.sy = n, s.sx+o.sx, s.sy+o.sy
+}
+
+// regressionStateResult returns the state as an array of floats, which is
+// the representation used between the stages of distributed aggregations.
+func regressionStateResult(s *regressionState) (tree.Datum, error) {
+	arr := tree.NewDArray(types.Float)
+	for _, v := range [numRegressionStateValues]float64{s.n, s.sx, s.sxx, s.sy, s.syy, s.sxy} {
+		if err := arr.Append(tree.NewDFloat(tree.DFloat(v))); err != nil {
+			return nil, err
+		}
+	}
+	return arr, nil
+}
+
+// regressionStateFromDatum decodes a state returned by regressionStateResult.
+func regressionStateFromDatum(d tree.Datum) (regressionState, error) {
+	arr := tree.MustBeDArray(d)
+	if arr.Len() != numRegressionStateValues {
+		return regressionState{}, pgerror.NewAssertionErrorf(
+			"expected %d values in regression state, found %d", numRegressionStateValues, arr.Len())
+	}
+	var v [numRegressionStateValues]float64
+	for i, e := range arr.Array {
+		v[i] = float64(*e.(*tree.DFloat))
+	}
+	return regressionState{n: v[0], sx: v[1], sxx: v[2], sy: v[3], syy: v[4], sxy: v[5]}, nil
+}
+
+func covarPopResult(s *regressionState) (tree.Datum, error) {
+	if s.n < 1 {
+		return tree.DNull, nil
+	}
+	return tree.NewDFloat(tree.DFloat(s.sxy / s.n)), nil
+}
+
+func covarSampResult(s *regressionState) (tree.Datum, error) {
+	if s.n < 2 {
+		return tree.DNull, nil
+	}
+	return tree.NewDFloat(tree.DFloat(s.sxy / (s.n - 1))), nil
+}
+
+func corrResult(s *regressionState) (tree.Datum, error) {
+	if s.n < 1 || s.sxx == 0 || s.syy == 0 {
+		return tree.DNull, nil
+	}
+	return tree.NewDFloat(tree.DFloat(s.sxy / math.Sqrt(s.sxx*s.syy))), nil
+}
+
+func regrAvgXResult(s *regressionState) (tree.Datum, error) {
+	if s.n < 1 {
+		return tree.DNull, nil
+	}
+	return tree.NewDFloat(tree.DFloat(s.sx / s.n)), nil
+}
+
+func regrAvgYResult(s *regressionState) (tree.Datum, error) {
+	if s.n < 1 {
+		return tree.DNull, nil
+	}
+	return tree.NewDFloat(tree.DFloat(s.sy / s.n)), nil
+}
+
+func regrCountResult(s *regressionState) (tree.Datum, error) {
+	return tree.NewDInt(tree.DInt(s.n)), nil
+}
+
+func regrInterceptResult(s *regressionState) (tree.Datum, error) {
+	if s.n < 1 || s.sxx == 0 {
+		return tree.DNull, nil
+	}
+	return tree.NewDFloat(tree.DFloat((s.sy - s.sx*s.sxy/s.sxx) / s.n)), nil
+}
+
+func regrR2Result(s *regressionState) (tree.Datum, error) {
+	if s.n < 1 || s.sxx == 0 {
+		return tree.DNull, nil
+	}
+	if s.syy == 0 {
+		// All the values of Y are equal: the fit is perfect.
+		return tree.NewDFloat(1), nil
+	}
+	return tree.NewDFloat(tree.DFloat(s.sxy * s.sxy / (s.sxx * s.syy))), nil
+}
+
+func regrSlopeResult(s *regressionState) (tree.Datum, error) {
+	if s.n < 1 || s.sxx == 0 {
+		return tree.DNull, nil
+	}
+	return tree.NewDFloat(tree.DFloat(s.sxy / s.sxx)), nil
+}
+
+func regrSXXResult(s *regressionState) (tree.Datum, error) {
+	if s.n < 1 {
+		return tree.DNull, nil
+	}
+	return tree.NewDFloat(tree.DFloat(s.sxx)), nil
+}
+
+func regrSXYResult(s *regressionState) (tree.Datum, error) {
+	if s.n < 1 {
+		return tree.DNull, nil
+	}
+	return tree.NewDFloat(tree.DFloat(s.sxy)), nil
+}
+
+func regrSYYResult(s *regressionState) (tree.Datum, error) {
+	if s.n < 1 {
+		return tree.DNull, nil
+	}
+	return tree.NewDFloat(tree.DFloat(s.syy)), nil
+}
+
+// regressionNumericTypes are the types of the values accepted by the
+// regression aggregates.
+var regressionNumericTypes = []types.T{types.Int, types.Float, types.Decimal}
+
+// makeRegressionAggregateOverloads returns the overloads of a regression
+// aggregate, for all the combinations of numeric types of Y and X. The result
+// of the aggregate is computed from the accumulated state by result.
+func makeRegressionAggregateOverloads(
+	ret types.T, result func(*regressionState) (tree.Datum, error), info string,
+) []tree.Overload {
+	overloads := make([]tree.Overload, 0, len(regressionNumericTypes)*len(regressionNumericTypes))
+	for _, y := range regressionNumericTypes {
+		for _, x := range regressionNumericTypes {
+			overloads = append(overloads, makeAggOverload([]types.T{y, x}, ret,
+				func(_ []types.T, _ *tree.EvalContext, arguments tree.Datums) tree.AggregateFunc {
+					return newRegressionAggregate(result, arguments)
+				},
+				info,
+			))
+		}
+	}
+	return overloads
+}
+
+// makeFinalRegressionAggregateOverload returns the overload of the final stage
+// of a distributed regression aggregate, which combines the states computed by
+// transition_regression_aggregate.
+func makeFinalRegressionAggregateOverload(
+	ret types.T, result func(*regressionState) (tree.Datum, error), info string,
+) tree.Overload {
+	return makeAggOverload([]types.T{types.TArray{Typ: types.Float}}, ret,
+		func(_ []types.T, _ *tree.EvalContext, _ tree.Datums) tree.AggregateFunc {
+			return &finalRegressionAggregate{result: result}
+		},
+		info,
+	)
+}
+
+// regressionAggregate accumulates pairs of values (Y, X) into a
+// regressionState.
+type regressionAggregate struct {
+	state  regressionState
+	result func(*regressionState) (tree.Datum, error)
+	// x is the value of X when it is passed to the aggregate as a constant
+	// argument instead of along with each value of Y.
+	x tree.Datum
+}
+
+func newRegressionAggregate(
+	result func(*regressionState) (tree.Datum, error), arguments tree.Datums,
+) tree.AggregateFunc {
+	a := &regressionAggregate{result: result}
+	if len(arguments) > 0 {
+		a.x = arguments[0]
+	}
+	return a
+}
+
+// Add accumulates the pair of values Y and X, unless one of them is NULL.
+func (a *regressionAggregate) Add(_ context.Context, y tree.Datum, others ...tree.Datum) error {
+	x := a.x
+	if len(others) > 0 {
+		x = others[0]
+	}
+	if y == tree.DNull || x == tree.DNull {
+		return nil
+	}
+	yf, err := regressionValue(y)
+	if err != nil {
+		return err
+	}
+	xf, err := regressionValue(x)
+	if err != nil {
+		return err
+	}
+	a.state.add(yf, xf)
+	return nil
+}
+
+// regressionValue converts a value passed to a regression aggregate to a
+// float.
+func regressionValue(d tree.Datum) (float64, error) {
+	switch t := d.(type) {
+	case *tree.DInt:
+		return float64(*t), nil
+	case *tree.DFloat:
+		return float64(*t), nil
+	case *tree.DDecimal:
+		return t.Float64()
+	default:
+		return 0, pgerror.NewAssertionErrorf("unexpected type %s in regression aggregate", d.ResolvedType())
+	}
+}
+
+// Result is part of the tree.AggregateFunc interface.
+func (a *regressionAggregate) Result() (tree.Datum, error) {
+	return a.result(&a.state)
+}
+
+// Close is part of the tree.AggregateFunc interface.
+func (a *regressionAggregate) Close(context.Context) {}
+
+// Size is part of the tree.AggregateFunc interface.
+func (a *regressionAggregate) Size() int64 {
+	return sizeOfRegressionAggregate
+}
+
+// finalRegressionAggregate combines the regression states computed by the
+// local stages of a distributed aggregation.
+type finalRegressionAggregate struct {
+	state  regressionState
+	result func(*regressionState) (tree.Datum, error)
+}
+
+// Add combines one more local state.
+func (a *finalRegressionAggregate) Add(_ context.Context, datum tree.Datum, _ ...tree.Datum) error {
+	if datum == tree.DNull {
+		return nil
+	}
+	s, err := regressionStateFromDatum(datum)
+	if err != nil {
+		return err
+	}
+	a.state.combine(s)
+	return nil
+}
+
+// Result is part of the tree.AggregateFunc interface.
+func (a *finalRegressionAggregate) Result() (tree.Datum, error) {
+	return a.result(&a.state)
+}
+
+// Close is part of the tree.AggregateFunc interface.
+func (a *finalRegressionAggregate) Close(context.Context) {}
+
+// Size is part of the tree.AggregateFunc interface.
+func (a *finalRegressionAggregate) Size() int64 {
+	return sizeOfFinalRegressionAggregate
+}
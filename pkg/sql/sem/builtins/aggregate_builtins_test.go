@@ -166,6 +166,10 @@ func TestStdDevDecimalResultDeepCopy(t *testing.T) {
 	testAggregateResultDeepCopy(t, newDecimalStdDevAggregate, makeDecimalTestDatum(10))
 }
 
+func TestModeResultDeepCopy(t *testing.T) {
+	testAggregateResultDeepCopy(t, newModeAggregate, makeIntTestDatum(10))
+}
+
 func makeIntTestDatum(count int) []tree.Datum {
 	rng, _ := randutil.NewPseudoRand()
 
@@ -52,6 +52,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tochar"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/knz/strtime"
 	"github.com/pkg/errors"
@@ -83,6 +85,9 @@ const (
 	categorySystemInfo    = "System info"
 	categoryGenerator     = "Set-returning"
 	categoryJSON          = "JSONB"
+	categoryTextSearch    = "Full text search"
+	categoryFormatting    = "Data type formatting"
+	categoryCryptographic = "Cryptographic"
 )
 
 func categorizeType(t types.T) string {
@@ -1154,26 +1159,36 @@ var builtins = map[string]builtinDefinition{
 				return result, nil
 			},
 			Info: "Replaces matches for the regular expression `regex` in `input` with the regular " +
-				"expression `replace` using `flags`." + `
-
-CockroachDB supports the following flags:
-
-| Flag           | Description                                                       |
-|----------------|-------------------------------------------------------------------|
-| **c**          | Case-sensitive matching                                           |
-| **g**          | Global matching (match each substring instead of only the first)  |
-| **i**          | Case-insensitive matching                                         |
-| **m** or **n** | Newline-sensitive (see below)                                     |
-| **p**          | Partial newline-sensitive matching (see below)                    |
-| **s**          | Newline-insensitive (default)                                     |
-| **w**          | Inverse partial newline-sensitive matching (see below)            |
+				"expression `replace` using `flags`." + regexpFlagsInfo,
+		},
+	),
 
-| Mode | ` + "`.` and `[^...]` match newlines | `^` and `$` match line boundaries" + `|
-|------|----------------------------------|--------------------------------------|
-| s    | yes                              | no                                   |
-| w    | yes                              | yes                                  |
-| p    | no                               | no                                   |
-| m/n  | no                               | yes                                  |`,
+	"regexp_split_to_array": makeBuiltin(tree.FunctionProperties{Category: categoryString},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"input", types.String}, {"regex", types.String}},
+			ReturnType: tree.FixedReturnType(types.TArray{Typ: types.String}),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				s := string(tree.MustBeDString(args[0]))
+				pattern := string(tree.MustBeDString(args[1]))
+				return regexpSplitToArray(ctx, s, pattern, "")
+			},
+			Info: "Splits `input` using the regular expression `regex` as the delimiter.",
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"input", types.String},
+				{"regex", types.String},
+				{"flags", types.String},
+			},
+			ReturnType: tree.FixedReturnType(types.TArray{Typ: types.String}),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				s := string(tree.MustBeDString(args[0]))
+				pattern := string(tree.MustBeDString(args[1]))
+				sqlFlags := string(tree.MustBeDString(args[2]))
+				return regexpSplitToArray(ctx, s, pattern, sqlFlags)
+			},
+			Info: "Splits `input` using the regular expression `regex` as the delimiter, " +
+				"using `flags`. The **g** flag is not supported." + regexpFlagsInfo,
 		},
 	),
 
@@ -1678,6 +1693,148 @@ CockroachDB supports the following flags:
 		},
 	),
 
+	// https://www.postgresql.org/docs/10/static/functions-formatting.html
+	"to_char": makeBuiltin(
+		tree.FunctionProperties{
+			Category: categoryFormatting,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"timestamp", types.Timestamp}, {"format", types.String}},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return timeToChar(args[0].(*tree.DTimestamp).Time, false /* withZone */, args[1])
+			},
+			Info: "Formats `timestamp` as a string according to the template patterns of `format`.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"timestamptz", types.TimestampTZ}, {"format", types.String}},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				t := args[0].(*tree.DTimestampTZ).Time.In(ctx.GetLocation())
+				return timeToChar(t, true /* withZone */, args[1])
+			},
+			Info: "Formats `timestamptz` as a string according to the template patterns of " +
+				"`format`, in the session time zone.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"date", types.Date}, {"format", types.String}},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				t := timeutil.Unix(int64(*args[0].(*tree.DDate))*tree.SecondsInDay, 0)
+				return timeToChar(t, false /* withZone */, args[1])
+			},
+			Info: "Formats `date` as a string according to the template patterns of `format`.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"interval", types.Interval}, {"format", types.String}},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				format := string(tree.MustBeDString(args[1]))
+				if format == "" {
+					return tree.DNull, nil
+				}
+				s, err := tochar.IntervalToChar(args[0].(*tree.DInterval).Duration, format)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDString(s), nil
+			},
+			Info: "Formats `interval` as a string according to the template patterns of `format`.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"int", types.Int}, {"format", types.String}},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				d := apd.New(int64(tree.MustBeDInt(args[0])), 0)
+				return decimalToChar(d, args[1])
+			},
+			Info: "Formats `int` as a string according to the template patterns of `format`.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"float", types.Float}, {"format", types.String}},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				f := float64(*args[0].(*tree.DFloat))
+				s, err := tochar.FormatFloat(f, string(tree.MustBeDString(args[1])))
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDString(s), nil
+			},
+			Info: "Formats `float` as a string according to the template patterns of `format`.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"decimal", types.Decimal}, {"format", types.String}},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return decimalToChar(&args[0].(*tree.DDecimal).Decimal, args[1])
+			},
+			Info: "Formats `decimal` as a string according to the template patterns of `format`.",
+		},
+	),
+
+	"to_timestamp": makeBuiltin(
+		tree.FunctionProperties{
+			Category: categoryFormatting,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"input", types.String}, {"format", types.String}},
+			ReturnType: tree.FixedReturnType(types.TimestampTZ),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				t, err := tochar.ParseTimestamp(
+					string(tree.MustBeDString(args[0])), string(tree.MustBeDString(args[1])), ctx.GetLocation())
+				if err != nil {
+					return nil, err
+				}
+				return tree.MakeDTimestampTZ(t, time.Microsecond), nil
+			},
+			Info: "Converts `input` to a timestamptz according to the template patterns of " +
+				"`format`. The time is in the session time zone, unless `format` has the TZH " +
+				"or TZM patterns.",
+		},
+	),
+
+	"to_date": makeBuiltin(
+		tree.FunctionProperties{
+			Category: categoryFormatting,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"input", types.String}, {"format", types.String}},
+			ReturnType: tree.FixedReturnType(types.Date),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				t, err := tochar.ParseDate(string(tree.MustBeDString(args[0])), string(tree.MustBeDString(args[1])))
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDDateFromTime(t, time.UTC), nil
+			},
+			Info: "Converts `input` to a date according to the template patterns of `format`.",
+		},
+	),
+
+	"to_number": makeBuiltin(
+		tree.FunctionProperties{
+			Category: categoryFormatting,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"input", types.String}, {"format", types.String}},
+			ReturnType: tree.FixedReturnType(types.Decimal),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				format := string(tree.MustBeDString(args[1]))
+				if format == "" {
+					return tree.DNull, nil
+				}
+				d, err := tochar.ParseNumber(string(tree.MustBeDString(args[0])), format)
+				if err != nil {
+					return nil, err
+				}
+				return &tree.DDecimal{Decimal: *d}, nil
+			},
+			Info: "Converts `input` to a decimal according to the template patterns of " +
+				"`format`. The characters of `input` which do not match the patterns are skipped.",
+		},
+	),
+
 	// https://www.postgresql.org/docs/10/static/functions-datetime.html
 	"age": makeBuiltin(defProps(),
 		tree.Overload{
@@ -1966,6 +2123,63 @@ may increase either contention or retry errors, or both.`,
 		},
 	),
 
+	"timezone": makeBuiltin(
+		tree.FunctionProperties{Category: categoryDateAndTime},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"timezone", types.String}, {"timestamp", types.Timestamp}},
+			ReturnType: tree.FixedReturnType(types.TimestampTZ),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				loc, err := timeZoneNameToLocation(string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				return tree.TimestampAtTimeZone(args[1].(*tree.DTimestamp), loc), nil
+			},
+			Info: "Treats `timestamp` as a local time in the time zone `timezone` and " +
+				"converts it to a timestamp with time zone. `timestamp AT TIME ZONE " +
+				"timezone` is equivalent.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"timezone", types.String}, {"timestamptz", types.TimestampTZ}},
+			ReturnType: tree.FixedReturnType(types.Timestamp),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				loc, err := timeZoneNameToLocation(string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				return tree.TimestampTZAtTimeZone(args[1].(*tree.DTimestampTZ), loc), nil
+			},
+			Info: "Converts `timestamptz` to the local time in the time zone `timezone`. " +
+				"`timestamptz AT TIME ZONE timezone` is equivalent.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"timezone", types.Interval}, {"timestamp", types.Timestamp}},
+			ReturnType: tree.FixedReturnType(types.TimestampTZ),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				loc, err := tree.TimeZoneIntervalToLocation(args[0].(*tree.DInterval))
+				if err != nil {
+					return nil, err
+				}
+				return tree.TimestampAtTimeZone(args[1].(*tree.DTimestamp), loc), nil
+			},
+			Info: "Treats `timestamp` as a local time in the time zone with the UTC " +
+				"offset `timezone` and converts it to a timestamp with time zone.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"timezone", types.Interval}, {"timestamptz", types.TimestampTZ}},
+			ReturnType: tree.FixedReturnType(types.Timestamp),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				loc, err := tree.TimeZoneIntervalToLocation(args[0].(*tree.DInterval))
+				if err != nil {
+					return nil, err
+				}
+				return tree.TimestampTZAtTimeZone(args[1].(*tree.DTimestampTZ), loc), nil
+			},
+			Info: "Converts `timestamptz` to the local time in the time zone with the " +
+				"UTC offset `timezone`.",
+		},
+	),
+
 	// Math functions
 	"abs": makeBuiltin(defProps(),
 		floatOverload1(func(x float64) (tree.Datum, error) {
@@ -2621,6 +2835,93 @@ may increase either contention or retry errors, or both.`,
 
 	"jsonb_array_length": makeBuiltin(jsonProps(), jsonArrayLengthImpl),
 
+	// Full text search functions.
+
+	"to_tsvector": tsConfigBuiltin(types.TSVector, "document",
+		func(c *tsearch.Config, document string) (tree.Datum, error) {
+			return tree.NewDTSVector(c.ToTSVector(document)), nil
+		},
+		"Normalizes `document` into a tsvector of lexemes and their positions",
+	),
+
+	"to_tsquery": tsConfigBuiltin(types.TSQuery, "query",
+		func(c *tsearch.Config, query string) (tree.Datum, error) {
+			q, err := c.ToTSQuery(query)
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDTSQuery(q), nil
+		},
+		"Parses `query` as a tsquery and normalizes its words into lexemes",
+	),
+
+	"plainto_tsquery": tsConfigBuiltin(types.TSQuery, "text",
+		func(c *tsearch.Config, text string) (tree.Datum, error) {
+			return tree.NewDTSQuery(c.PlainToTSQuery(text)), nil
+		},
+		"Builds a tsquery matching the documents containing all the words of `text`",
+	),
+
+	"ts_rank": makeBuiltin(tree.FunctionProperties{Category: categoryTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector", types.TSVector}, {"query", types.TSQuery}},
+			ReturnType: tree.FixedReturnType(types.Float),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tsRank(tsearch.DefaultWeights, args[0], args[1], nil /* normalization */)
+			},
+			Info: "Ranks how relevant `vector` is to `query`, based on the frequency of the " +
+				"matching lexemes.",
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"vector", types.TSVector}, {"query", types.TSQuery}, {"normalization", types.Int},
+			},
+			ReturnType: tree.FixedReturnType(types.Float),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tsRank(tsearch.DefaultWeights, args[0], args[1], args[2])
+			},
+			Info: "Ranks how relevant `vector` is to `query`, based on the frequency of the " +
+				"matching lexemes. The rank is normalized according to the bit mask " +
+				"`normalization`: 1 divides it by 1 + the logarithm of the document length, " +
+				"2 by the document length, 8 by the number of unique words, 16 by 1 + the " +
+				"logarithm of the number of unique words and 32 by itself + 1.",
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"weights", types.TArray{Typ: types.Float}}, {"vector", types.TSVector}, {"query", types.TSQuery},
+			},
+			ReturnType: tree.FixedReturnType(types.Float),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				weights, err := tsWeights(tree.MustBeDArray(args[0]))
+				if err != nil {
+					return nil, err
+				}
+				return tsRank(weights, args[1], args[2], nil /* normalization */)
+			},
+			Info: "Ranks how relevant `vector` is to `query`, based on the frequency of the " +
+				"matching lexemes. The occurrences of weight D, C, B and A count according to " +
+				"the respective elements of `weights`.",
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"weights", types.TArray{Typ: types.Float}}, {"vector", types.TSVector},
+				{"query", types.TSQuery}, {"normalization", types.Int},
+			},
+			ReturnType: tree.FixedReturnType(types.Float),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				weights, err := tsWeights(tree.MustBeDArray(args[0]))
+				if err != nil {
+					return nil, err
+				}
+				return tsRank(weights, args[1], args[2], args[3])
+			},
+			Info: "Ranks how relevant `vector` is to `query`, based on the frequency of the " +
+				"matching lexemes. The occurrences of weight D, C, B and A count according to " +
+				"the respective elements of `weights`, and the rank is normalized according " +
+				"to the bit mask `normalization`.",
+		},
+	),
+
 	// Metadata functions.
 
 	// https://www.postgresql.org/docs/10/static/functions-info.html
@@ -3521,6 +3822,114 @@ var jsonArrayLengthImpl = tree.Overload{
 	Info: "Returns the number of elements in the outermost JSON or JSONB array.",
 }
 
+// timeToChar implements to_char for the date and time types. As in
+// PostgreSQL, an empty template yields NULL.
+func timeToChar(t time.Time, withZone bool, format tree.Datum) (tree.Datum, error) {
+	f := string(tree.MustBeDString(format))
+	if f == "" {
+		return tree.DNull, nil
+	}
+	s, err := tochar.TimeToChar(t, withZone, f)
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewDString(s), nil
+}
+
+// decimalToChar implements to_char for the exact numeric types.
+func decimalToChar(d *apd.Decimal, format tree.Datum) (tree.Datum, error) {
+	s, err := tochar.FormatDecimal(d, string(tree.MustBeDString(format)))
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewDString(s), nil
+}
+
+// tsConfigBuiltin builds a text search function taking a string and an
+// optional text search configuration, which defaults to the one of the
+// default_text_search_config session variable.
+func tsConfigBuiltin(
+	returnType types.T,
+	argName string,
+	fn func(*tsearch.Config, string) (tree.Datum, error),
+	info string,
+) builtinDefinition {
+	return makeBuiltin(tree.FunctionProperties{Category: categoryTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{argName, types.String}},
+			ReturnType: tree.FixedReturnType(returnType),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				name := ctx.SessionData.DefaultTextSearchConfig
+				if name == "" {
+					name = tsearch.DefaultConfigName
+				}
+				c, err := tsearch.GetConfig(name)
+				if err != nil {
+					return nil, err
+				}
+				return fn(c, string(tree.MustBeDString(args[0])))
+			},
+			Info: info + ", using the default text search configuration.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"config", types.String}, {argName, types.String}},
+			ReturnType: tree.FixedReturnType(returnType),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				c, err := tsearch.GetConfig(string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				return fn(c, string(tree.MustBeDString(args[1])))
+			},
+			Info: info + ", using the text search configuration `config`.",
+		},
+	)
+}
+
+// tsWeights converts the weights argument of ts_rank, which lists the weights
+// D, C, B and A. Negative elements stand for the default weights.
+func tsWeights(arr *tree.DArray) ([4]float32, error) {
+	weights := tsearch.DefaultWeights
+	if arr.Len() < len(weights) {
+		return weights, pgerror.NewError(pgerror.CodeArraySubscriptError, "array of weight is too short")
+	}
+	for i := range weights {
+		if arr.Array[i] == tree.DNull {
+			return weights, pgerror.NewError(pgerror.CodeNullValueNotAllowedError,
+				"array of weight must not contain nulls")
+		}
+		w := float64(*arr.Array[i].(*tree.DFloat))
+		if w > 1 {
+			return weights, pgerror.NewError(pgerror.CodeInvalidParameterValueError, "weight out of range")
+		}
+		if w >= 0 {
+			weights[i] = float32(w)
+		}
+	}
+	return weights, nil
+}
+
+// tsRank implements ts_rank. The rank is computed with single precision
+// floats like in PostgreSQL, and converted to the float with the shortest
+// decimal representation that rounds to it.
+func tsRank(weights [4]float32, vector, query, normalization tree.Datum) (tree.Datum, error) {
+	norm := 0
+	if normalization != nil {
+		norm = int(tree.MustBeDInt(normalization))
+		if norm < 0 {
+			return nil, pgerror.NewError(pgerror.CodeInvalidParameterValueError,
+				"unrecognized normalization method")
+		}
+	}
+	rank := tsearch.Rank(weights,
+		vector.(*tree.DTSVector).TSVector, query.(*tree.DTSQuery).TSQuery, norm)
+	f, err := strconv.ParseFloat(strconv.FormatFloat(float64(rank), 'g', -1, 32), 64)
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewDFloat(tree.DFloat(f)), nil
+}
+
 func arrayBuiltin(impl func(types.T) tree.Overload) builtinDefinition {
 	overloads := make([]tree.Overload, 0, len(types.AnyNonArray))
 	for _, typ := range types.AnyNonArray {
@@ -3898,6 +4307,71 @@ func regexpReplace(ctx *tree.EvalContext, s, pattern, to, sqlFlags string) (tree
 	return tree.NewDString(newString.String()), nil
 }
 
+var errRegexpSplitGlobal = pgerror.NewError(pgerror.CodeInvalidParameterValueError,
+	`the "global" flag is not supported`)
+
+// regexpSplit splits s around the matches of pattern. As in Postgres, an
+// empty match at the start or at the end of s, or right after the previous
+// match, does not split s.
+func regexpSplit(ctx *tree.EvalContext, s, pattern, sqlFlags string) ([]string, error) {
+	if strings.ContainsRune(sqlFlags, 'g') {
+		return nil, errRegexpSplitGlobal
+	}
+	patternRe, err := ctx.ReCache.GetRegexp(regexpFlagKey{pattern, sqlFlags})
+	if err != nil {
+		return nil, err
+	}
+
+	var parts []string
+	start, prevEnd := 0, 0
+	for _, matchIndex := range patternRe.FindAllStringIndex(s, -1) {
+		if matchIndex[0] < len(s) && matchIndex[1] > prevEnd {
+			parts = append(parts, s[start:matchIndex[0]])
+			start = matchIndex[1]
+		}
+		prevEnd = matchIndex[1]
+	}
+	return append(parts, s[start:]), nil
+}
+
+func regexpSplitToArray(ctx *tree.EvalContext, s, pattern, sqlFlags string) (tree.Datum, error) {
+	parts, err := regexpSplit(ctx, s, pattern, sqlFlags)
+	if err != nil {
+		return nil, err
+	}
+	result := tree.NewDArray(types.String)
+	for _, part := range parts {
+		if err := result.Append(tree.NewDString(part)); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// regexpFlagsInfo documents the flags accepted by the regular expression
+// functions.
+const regexpFlagsInfo = `
+
+CockroachDB supports the following flags:
+
+| Flag           | Description                                                       |
+|----------------|-------------------------------------------------------------------|
+| **c**          | Case-sensitive matching                                           |
+| **g**          | Global matching (match each substring instead of only the first)  |
+| **i**          | Case-insensitive matching                                         |
+| **m** or **n** | Newline-sensitive (see below)                                     |
+| **p**          | Partial newline-sensitive matching (see below)                    |
+| **s**          | Newline-insensitive (default)                                     |
+| **w**          | Inverse partial newline-sensitive matching (see below)            |
+| **x**          | Expanded syntax (white space and # comments are ignored)          |
+
+| Mode | ` + "`.` and `[^...]` match newlines | `^` and `$` match line boundaries" + `|
+|------|----------------------------------|--------------------------------------|
+| s    | yes                              | no                                   |
+| w    | yes                              | yes                                  |
+| p    | no                               | no                                   |
+| m/n  | no                               | yes                                  |`
+
 var flagToByte = map[syntax.Flags]byte{
 	syntax.FoldCase: 'i',
 	syntax.DotNL:    's',
@@ -3913,11 +4387,12 @@ var flagToNotByte = map[syntax.Flags]byte{
 // It then returns an adjusted regexp pattern.
 func regexpEvalFlags(pattern, sqlFlags string) (string, error) {
 	flags := syntax.DotNL | syntax.OneLine
+	expanded := false
 
 	for _, sqlFlag := range sqlFlags {
 		switch sqlFlag {
 		case 'g':
-			// Handled in `regexpReplace`.
+			// Handled by the callers, e.g. `regexpReplace`.
 		case 'i':
 			flags |= syntax.FoldCase
 		case 'c':
@@ -3933,11 +4408,16 @@ func regexpEvalFlags(pattern, sqlFlags string) (string, error) {
 		case 'w':
 			flags |= syntax.DotNL
 			flags &^= syntax.OneLine
+		case 'x':
+			expanded = true
 		default:
 			return "", pgerror.NewErrorf(
 				pgerror.CodeInvalidRegularExpressionError, "invalid regexp flag: %q", sqlFlag)
 		}
 	}
+	if expanded {
+		pattern = regexpStripExpanded(pattern)
+	}
 
 	var goFlags bytes.Buffer
 	for flag, b := range flagToByte {
@@ -3958,6 +4438,65 @@ func regexpEvalFlags(pattern, sqlFlags string) (string, error) {
 	return fmt.Sprintf("(?%s:%s)", bs, pattern), nil
 }
 
+// regexpStripExpanded removes the white space and the comments from a
+// pattern written in the expanded syntax of the x flag. As in Postgres,
+// white space and # are kept when they are escaped with a backslash or
+// when they appear in a bracket expression.
+func regexpStripExpanded(pattern string) string {
+	var buf bytes.Buffer
+	inBracket := false
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			if next := pattern[i]; unicode.IsSpace(rune(next)) || next == '#' {
+				// Go's regexp does not accept escaped white space.
+				buf.WriteByte(next)
+			} else {
+				buf.WriteByte(c)
+				buf.WriteByte(next)
+			}
+			continue
+		case inBracket:
+			if c == '[' && i+1 < len(pattern) && pattern[i+1] == ':' {
+				// Copy a character class such as [:alpha:] as a whole.
+				if end := strings.Index(pattern[i+2:], ":]"); end >= 0 {
+					buf.WriteString(pattern[i : i+end+4])
+					i += end + 3
+					continue
+				}
+			}
+			if c == ']' {
+				inBracket = false
+			}
+		case c == '[':
+			inBracket = true
+			buf.WriteByte(c)
+			// A ] right after the opening bracket, or after its negation, is a
+			// literal character.
+			if i+1 < len(pattern) && pattern[i+1] == '^' {
+				i++
+				buf.WriteByte('^')
+			}
+			if i+1 < len(pattern) && pattern[i+1] == ']' {
+				i++
+				buf.WriteByte(']')
+			}
+			continue
+		case c == '#':
+			for i < len(pattern) && pattern[i] != '\n' {
+				i++
+			}
+			continue
+		case unicode.IsSpace(rune(c)):
+			continue
+		}
+		buf.WriteByte(c)
+	}
+	return buf.String()
+}
+
 func overlay(s, to string, pos, size int) (tree.Datum, error) {
 	if pos < 1 {
 		return nil, pgerror.NewErrorf(
@@ -4292,6 +4831,17 @@ func decodeEscape(input string) ([]byte, error) {
 	return result, nil
 }
 
+// timeZoneNameToLocation returns the location of the time zone with the given
+// name, or an error if the name is not a known IANA time zone name.
+func timeZoneNameToLocation(name string) (*time.Location, error) {
+	loc, err := tree.TimeZoneNameToLocation(name)
+	if err != nil {
+		return nil, pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+			"time zone %q not recognized", name)
+	}
+	return loc, nil
+}
+
 func truncateTimestamp(
 	_ *tree.EvalContext, fromTime time.Time, timeSpan string,
 ) (*tree.DTimestampTZ, error) {
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"fmt"
 	"math/rand"
+	"reflect"
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
@@ -239,3 +240,57 @@ func TestLPadRPad(t *testing.T) {
 		}
 	}
 }
+
+func TestRegexpSplit(t *testing.T) {
+	testCases := []struct {
+		s        string
+		pattern  string
+		sqlFlags string
+		expected []string
+	}{
+		{"the quick brown fox", `\s+`, "", []string{"the", "quick", "brown", "fox"}},
+		{"the quick", `\s*`, "", []string{"t", "h", "e", "q", "u", "i", "c", "k"}},
+		{"abc", ``, "", []string{"a", "b", "c"}},
+		{"a1b22c", `\d+`, "", []string{"a", "b", "c"}},
+		{"1a2", `\d`, "", []string{"", "a", ""}},
+		{"aXbxc", `x`, "i", []string{"a", "b", "c"}},
+		{"aXbxc", `x`, "", []string{"aXb", "c"}},
+		{"", `x`, "", []string{""}},
+		{"a b,c", `[ ,]  # separators`, "x", []string{"a", "b", "c"}},
+	}
+	evalCtx := tree.NewTestingEvalContext(cluster.MakeTestingClusterSettings())
+	for _, tc := range testCases {
+		out, err := regexpSplit(evalCtx, tc.s, tc.pattern, tc.sqlFlags)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(out, tc.expected) {
+			t.Errorf("%q split by %q: expected %q, found %q", tc.s, tc.pattern, tc.expected, out)
+		}
+	}
+
+	if _, err := regexpSplit(evalCtx, "abc", "b", "g"); err != errRegexpSplitGlobal {
+		t.Errorf("expected %v, found %v", errRegexpSplitGlobal, err)
+	}
+}
+
+func TestRegexpStripExpanded(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		expected string
+	}{
+		{`a b c`, `abc`},
+		{"a # comment\nb", `ab`},
+		{`a\ b\#c`, `a b#c`},
+		{`[ #] x`, `[ #]x`},
+		{`[] ] x`, `[] ]x`},
+		{`[^] ] x`, `[^] ]x`},
+		{`[[:space:] ] x`, `[[:space:] ]x`},
+		{`\d + \.`, `\d+\.`},
+	}
+	for _, tc := range testCases {
+		if out := regexpStripExpanded(tc.pattern); out != tc.expected {
+			t.Errorf("%q: expected %q, found %q", tc.pattern, tc.expected, out)
+		}
+	}
+}
@@ -16,6 +16,7 @@ package builtins
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/lex"
@@ -161,6 +162,42 @@ var generators = map[string]builtinDefinition{
 		),
 	),
 
+	"regexp_matches": makeBuiltin(genProps(regexpMatchesGeneratorLabels),
+		// See https://www.postgresql.org/docs/10/static/functions-matching.html#FUNCTIONS-POSIX-REGEXP
+		makeGeneratorOverload(
+			tree.ArgTypes{{"input", types.String}, {"regex", types.String}},
+			regexpMatchesGeneratorType,
+			makeRegexpMatchesGenerator,
+			"Returns the substrings captured by the first match of the regular expression "+
+				"`regex` in `input`, or the whole match if `regex` has no capture groups.",
+		),
+		makeGeneratorOverload(
+			tree.ArgTypes{{"input", types.String}, {"regex", types.String}, {"flags", types.String}},
+			regexpMatchesGeneratorType,
+			makeRegexpMatchesGenerator,
+			"Returns the substrings captured by the first match of the regular expression "+
+				"`regex` in `input`, or the whole match if `regex` has no capture groups, "+
+				"using `flags`. With the **g** flag, returns a row for each match."+regexpFlagsInfo,
+		),
+	),
+
+	"regexp_split_to_table": makeBuiltin(genProps(regexpSplitGeneratorLabels),
+		// See https://www.postgresql.org/docs/10/static/functions-matching.html#FUNCTIONS-POSIX-REGEXP
+		makeGeneratorOverload(
+			tree.ArgTypes{{"input", types.String}, {"regex", types.String}},
+			regexpSplitGeneratorType,
+			makeRegexpSplitGenerator,
+			"Splits `input` using the regular expression `regex` as the delimiter.",
+		),
+		makeGeneratorOverload(
+			tree.ArgTypes{{"input", types.String}, {"regex", types.String}, {"flags", types.String}},
+			regexpSplitGeneratorType,
+			makeRegexpSplitGenerator,
+			"Splits `input` using the regular expression `regex` as the delimiter, using "+
+				"`flags`. The **g** flag is not supported."+regexpFlagsInfo,
+		),
+	),
+
 	"json_array_elements":       makeBuiltin(genProps(jsonArrayGeneratorLabels), jsonArrayElementsImpl),
 	"jsonb_array_elements":      makeBuiltin(genProps(jsonArrayGeneratorLabels), jsonArrayElementsImpl),
 	"json_array_elements_text":  makeBuiltin(genProps(jsonArrayGeneratorLabels), jsonArrayElementsTextImpl),
@@ -585,6 +622,133 @@ var noDatums tree.Datums
 // Values implements the tree.ValueGenerator interface.
 func (s *unaryValueGenerator) Values() tree.Datums { return noDatums }
 
+func regexpGeneratorFlags(args tree.Datums) string {
+	if len(args) > 2 {
+		return string(tree.MustBeDString(args[2]))
+	}
+	return ""
+}
+
+func makeRegexpMatchesGenerator(
+	ctx *tree.EvalContext, args tree.Datums,
+) (tree.ValueGenerator, error) {
+	s := string(tree.MustBeDString(args[0]))
+	pattern := string(tree.MustBeDString(args[1]))
+	sqlFlags := regexpGeneratorFlags(args)
+	patternRe, err := ctx.ReCache.GetRegexp(regexpFlagKey{pattern, sqlFlags})
+	if err != nil {
+		return nil, err
+	}
+	matchCount := 1
+	if strings.ContainsRune(sqlFlags, 'g') {
+		matchCount = -1
+	}
+	return &regexpMatchesGenerator{
+		s:       s,
+		matches: patternRe.FindAllStringSubmatchIndex(s, matchCount),
+	}, nil
+}
+
+// regexpMatchesGenerator is a value generator that returns the substrings
+// captured by each match of a regular expression.
+type regexpMatchesGenerator struct {
+	s         string
+	matches   [][]int
+	nextIndex int
+}
+
+var regexpMatchesGeneratorLabels = []string{"regexp_matches"}
+
+var regexpMatchesGeneratorType = types.TArray{Typ: types.String}
+
+// ResolvedType implements the tree.ValueGenerator interface.
+func (g *regexpMatchesGenerator) ResolvedType() types.T {
+	return regexpMatchesGeneratorType
+}
+
+// Start implements the tree.ValueGenerator interface.
+func (g *regexpMatchesGenerator) Start() error {
+	g.nextIndex = -1
+	return nil
+}
+
+// Close implements the tree.ValueGenerator interface.
+func (g *regexpMatchesGenerator) Close() {}
+
+// Next implements the tree.ValueGenerator interface.
+func (g *regexpMatchesGenerator) Next() (bool, error) {
+	g.nextIndex++
+	return g.nextIndex < len(g.matches), nil
+}
+
+// Values implements the tree.ValueGenerator interface.
+func (g *regexpMatchesGenerator) Values() tree.Datums {
+	matchIndex := g.matches[g.nextIndex]
+	result := tree.NewDArray(types.String)
+	if len(matchIndex) == 2 {
+		// Without capture groups, the whole match is returned.
+		_ = result.Append(tree.NewDString(g.s[matchIndex[0]:matchIndex[1]]))
+		return tree.Datums{result}
+	}
+	for i := 2; i < len(matchIndex); i += 2 {
+		if matchIndex[i] < 0 {
+			// The group did not participate in the match.
+			_ = result.Append(tree.DNull)
+		} else {
+			_ = result.Append(tree.NewDString(g.s[matchIndex[i]:matchIndex[i+1]]))
+		}
+	}
+	return tree.Datums{result}
+}
+
+func makeRegexpSplitGenerator(
+	ctx *tree.EvalContext, args tree.Datums,
+) (tree.ValueGenerator, error) {
+	s := string(tree.MustBeDString(args[0]))
+	pattern := string(tree.MustBeDString(args[1]))
+	parts, err := regexpSplit(ctx, s, pattern, regexpGeneratorFlags(args))
+	if err != nil {
+		return nil, err
+	}
+	return &regexpSplitGenerator{parts: parts}, nil
+}
+
+// regexpSplitGenerator is a value generator that returns the substrings
+// delimited by the matches of a regular expression.
+type regexpSplitGenerator struct {
+	parts     []string
+	nextIndex int
+}
+
+var regexpSplitGeneratorLabels = []string{"regexp_split_to_table"}
+
+var regexpSplitGeneratorType = types.String
+
+// ResolvedType implements the tree.ValueGenerator interface.
+func (g *regexpSplitGenerator) ResolvedType() types.T {
+	return regexpSplitGeneratorType
+}
+
+// Start implements the tree.ValueGenerator interface.
+func (g *regexpSplitGenerator) Start() error {
+	g.nextIndex = -1
+	return nil
+}
+
+// Close implements the tree.ValueGenerator interface.
+func (g *regexpSplitGenerator) Close() {}
+
+// Next implements the tree.ValueGenerator interface.
+func (g *regexpSplitGenerator) Next() (bool, error) {
+	g.nextIndex++
+	return g.nextIndex < len(g.parts), nil
+}
+
+// Values implements the tree.ValueGenerator interface.
+func (g *regexpSplitGenerator) Values() tree.Datums {
+	return tree.Datums{tree.NewDString(g.parts[g.nextIndex])}
+}
+
 func jsonAsText(j json.JSON) (tree.Datum, error) {
 	text, err := j.AsText()
 	if err != nil {
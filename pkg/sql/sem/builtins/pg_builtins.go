@@ -849,6 +849,34 @@ var pgBuiltins = map[string]builtinDefinition{
 		},
 	),
 
+	// See https://www.postgresql.org/docs/current/static/sql-notify.html.
+	"pg_notify": makeBuiltin(
+		tree.FunctionProperties{
+			NullableArgs:     true,
+			DistsqlBlacklist: true,
+			Impure:           true,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"channel", types.String}, {"payload", types.String}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				var channel, payload string
+				if args[0] != tree.DNull {
+					channel = string(tree.MustBeDString(args[0]))
+				}
+				if args[1] != tree.DNull {
+					payload = string(tree.MustBeDString(args[1]))
+				}
+				if err := ctx.Planner.SendNotification(ctx.Ctx(), channel, payload); err != nil {
+					return nil, err
+				}
+				return tree.DBoolTrue, nil
+			},
+			Info: "Sends a notification with the given payload to the sessions " +
+				"listening on channel when the current transaction commits.",
+		},
+	),
+
 	"pg_sleep": makeBuiltin(
 		tree.FunctionProperties{
 			// pg_sleep is marked as impure so it doesn't get executed during
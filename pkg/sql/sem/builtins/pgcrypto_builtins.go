@@ -0,0 +1,360 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package builtins
+
+import (
+	"crypto/rand"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/util/pgcrypto"
+)
+
+// This file contains the builtin functions of the pgcrypto extension of
+// PostgreSQL, see pkg/util/pgcrypto.
+
+func initPGCryptoBuiltins() {
+	for k, v := range pgCryptoBuiltins {
+		v.props.Category = categoryCryptographic
+		builtins[k] = v
+	}
+}
+
+// maxRandomBytes is the maximum length accepted by gen_random_bytes.
+const maxRandomBytes = 1024
+
+const cryptAlgorithmsInfo = "The supported algorithms are `bf` (Blowfish, " +
+	"as in bcrypt) and `md5`."
+
+const cipherTypeInfo = "The encryption type has the form " +
+	"`algorithm[-mode][/pad:padding]`, where the algorithm is one of `aes`, `bf`, " +
+	"`des` and `3des`, the mode is `cbc` (the default) or `ecb`, and the padding " +
+	"is `pkcs` (the default) or `none`."
+
+// bytesOrString returns the bytes of a BYTES or STRING datum.
+func bytesOrString(d tree.Datum) []byte {
+	if b, ok := d.(*tree.DBytes); ok {
+		return []byte(*b)
+	}
+	return []byte(tree.MustBeDString(d))
+}
+
+var pgCryptoBuiltins = map[string]builtinDefinition{
+	"digest": makeBuiltin(defProps(),
+		digestOverload(types.String),
+		digestOverload(types.Bytes),
+	),
+
+	"hmac": makeBuiltin(defProps(),
+		hmacOverload(types.String),
+		hmacOverload(types.Bytes),
+	),
+
+	"crypt": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"password", types.String}, {"salt", types.String}},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(evalCtx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				password := string(tree.MustBeDString(args[0]))
+				salt := string(tree.MustBeDString(args[1]))
+				hash, err := pgcrypto.Crypt(evalCtx.Ctx(), password, salt)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDString(hash), nil
+			},
+			Info: "Hashes `password` with `salt`, in the format of crypt(3). The salt " +
+				"is either generated by `gen_salt()`, or a previously computed hash, so that " +
+				"a password is checked with `crypt(password, hash) = hash`. " + cryptAlgorithmsInfo,
+		},
+	),
+
+	"gen_salt": makeBuiltin(
+		tree.FunctionProperties{Impure: true},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"type", types.String}},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return genSalt(string(tree.MustBeDString(args[0])), 0)
+			},
+			Info: "Generates a random salt for `crypt()` with the algorithm `type`. " +
+				cryptAlgorithmsInfo,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"type", types.String}, {"iter_count", types.Int}},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return genSalt(string(tree.MustBeDString(args[0])), int(tree.MustBeDInt(args[1])))
+			},
+			Info: "Generates a random salt for `crypt()` with the algorithm `type`, and " +
+				"`iter_count` rounds. Only the `bf` algorithm has a number of rounds, between 4 " +
+				"and 31 (6 by default), which is the base 2 logarithm of its number of " +
+				"iterations.",
+		},
+	),
+
+	"gen_random_bytes": makeBuiltin(
+		tree.FunctionProperties{Impure: true},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"count", types.Int}},
+			ReturnType: tree.FixedReturnType(types.Bytes),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				n := int(tree.MustBeDInt(args[0]))
+				if n < 1 || n > maxRandomBytes {
+					return nil, pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+						"length not in range")
+				}
+				b := make([]byte, n)
+				if _, err := rand.Read(b); err != nil {
+					return nil, err
+				}
+				return tree.NewDBytes(tree.DBytes(b)), nil
+			},
+			Info: "Returns `count` cryptographically strong random bytes, at most 1024.",
+		},
+	),
+
+	"encrypt": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"data", types.Bytes}, {"key", types.Bytes}, {"type", types.String}},
+			ReturnType: tree.FixedReturnType(types.Bytes),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return cipherDatum(pgcrypto.Encrypt, args[0], args[1], nil, args[2])
+			},
+			Info: "Encrypts `data` with `key`. " + cipherTypeInfo,
+		},
+	),
+
+	"encrypt_iv": makeBuiltin(defProps(),
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"data", types.Bytes}, {"key", types.Bytes}, {"iv", types.Bytes}, {"type", types.String},
+			},
+			ReturnType: tree.FixedReturnType(types.Bytes),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return cipherDatum(pgcrypto.Encrypt, args[0], args[1], args[2], args[3])
+			},
+			Info: "Encrypts `data` with `key` and the initialization vector `iv` of the " +
+				"`cbc` mode. " + cipherTypeInfo,
+		},
+	),
+
+	"decrypt": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"data", types.Bytes}, {"key", types.Bytes}, {"type", types.String}},
+			ReturnType: tree.FixedReturnType(types.Bytes),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return cipherDatum(pgcrypto.Decrypt, args[0], args[1], nil, args[2])
+			},
+			Info: "Decrypts `data` encrypted by `encrypt()` with `key`. " + cipherTypeInfo,
+		},
+	),
+
+	"decrypt_iv": makeBuiltin(defProps(),
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"data", types.Bytes}, {"key", types.Bytes}, {"iv", types.Bytes}, {"type", types.String},
+			},
+			ReturnType: tree.FixedReturnType(types.Bytes),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return cipherDatum(pgcrypto.Decrypt, args[0], args[1], args[2], args[3])
+			},
+			Info: "Decrypts `data` encrypted by `encrypt_iv()` with `key` and the " +
+				"initialization vector `iv`. " + cipherTypeInfo,
+		},
+	),
+
+	"pgp_sym_encrypt": pgpSymEncryptBuiltin(types.String),
+
+	"pgp_sym_encrypt_bytea": pgpSymEncryptBuiltin(types.Bytes),
+
+	"pgp_sym_decrypt": pgpSymDecryptBuiltin(types.String),
+
+	"pgp_sym_decrypt_bytea": pgpSymDecryptBuiltin(types.Bytes),
+
+	"armor": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"data", types.Bytes}},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tree.NewDString(pgcrypto.Armor([]byte(*args[0].(*tree.DBytes)))), nil
+			},
+			Info: "Encodes `data` in the ASCII armor of OpenPGP.",
+		},
+	),
+
+	"dearmor": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"data", types.String}},
+			ReturnType: tree.FixedReturnType(types.Bytes),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				data, err := pgcrypto.Dearmor(string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDBytes(tree.DBytes(data)), nil
+			},
+			Info: "Decodes `data` encoded in the ASCII armor of OpenPGP.",
+		},
+	),
+}
+
+func digestOverload(typ types.T) tree.Overload {
+	return tree.Overload{
+		Types:      tree.ArgTypes{{"data", typ}, {"type", types.String}},
+		ReturnType: tree.FixedReturnType(types.Bytes),
+		Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+			res, err := pgcrypto.Digest(bytesOrString(args[0]), string(tree.MustBeDString(args[1])))
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDBytes(tree.DBytes(res)), nil
+		},
+		Info: "Computes the binary hash of `data` with the algorithm `type`, one of " +
+			"`md5`, `sha1`, `sha224`, `sha256`, `sha384` and `sha512`.",
+	}
+}
+
+func hmacOverload(typ types.T) tree.Overload {
+	return tree.Overload{
+		Types:      tree.ArgTypes{{"data", typ}, {"key", typ}, {"type", types.String}},
+		ReturnType: tree.FixedReturnType(types.Bytes),
+		Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+			res, err := pgcrypto.HMAC(
+				bytesOrString(args[0]), bytesOrString(args[1]), string(tree.MustBeDString(args[2])),
+			)
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDBytes(tree.DBytes(res)), nil
+		},
+		Info: "Computes the HMAC of `data` with `key` and the hash algorithm `type`, " +
+			"one of `md5`, `sha1`, `sha224`, `sha256`, `sha384` and `sha512`.",
+	}
+}
+
+func genSalt(algorithm string, rounds int) (tree.Datum, error) {
+	salt, err := pgcrypto.GenSalt(algorithm, rounds)
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewDString(salt), nil
+}
+
+// cipherDatum applies fn, which is pgcrypto.Encrypt or pgcrypto.Decrypt, to
+// the arguments of the encrypt and decrypt builtins. iv is nil when the
+// builtin has no initialization vector.
+func cipherDatum(
+	fn func(data, key, iv []byte, typ string) ([]byte, error), data, key, iv, typ tree.Datum,
+) (tree.Datum, error) {
+	var ivBytes []byte
+	if iv != nil {
+		ivBytes = []byte(*iv.(*tree.DBytes))
+	}
+	res, err := fn(
+		[]byte(*data.(*tree.DBytes)), []byte(*key.(*tree.DBytes)), ivBytes, string(tree.MustBeDString(typ)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewDBytes(tree.DBytes(res)), nil
+}
+
+const pgpOptionsInfo = " The `options` are a comma-separated list of `name=value` " +
+	"pairs, as in pgcrypto: `cipher-algo` (`aes128` by default, `aes192`, `aes256`, " +
+	"`bf` or `3des`), `compress-algo` (0 for none by default, 1 for ZIP or 2 for " +
+	"ZLIB), `compress-level`, `convert-crlf`, `disable-mdc` (only 0), `sess-key`, " +
+	"`s2k-mode`, `s2k-count`, `s2k-digest-algo`, `s2k-cipher-algo` and `unicode-mode`."
+
+// pgpSymEncryptBuiltin returns the definition of pgp_sym_encrypt for STRING
+// data, and pgp_sym_encrypt_bytea for BYTES data.
+func pgpSymEncryptBuiltin(typ types.T) builtinDefinition {
+	text := typ == types.String
+	fn := func(args tree.Datums) (tree.Datum, error) {
+		options := ""
+		if len(args) > 2 {
+			options = string(tree.MustBeDString(args[2]))
+		}
+		res, err := pgcrypto.PGPSymEncrypt(
+			bytesOrString(args[0]), []byte(tree.MustBeDString(args[1])), text, options,
+		)
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewDBytes(tree.DBytes(res)), nil
+	}
+	info := "Encrypts `data` as an OpenPGP message, with a key derived from `password`."
+	return makeBuiltin(
+		tree.FunctionProperties{Impure: true},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"data", typ}, {"password", types.String}},
+			ReturnType: tree.FixedReturnType(types.Bytes),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return fn(args)
+			},
+			Info: info,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"data", typ}, {"password", types.String}, {"options", types.String}},
+			ReturnType: tree.FixedReturnType(types.Bytes),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return fn(args)
+			},
+			Info: info + pgpOptionsInfo,
+		},
+	)
+}
+
+// pgpSymDecryptBuiltin returns the definition of pgp_sym_decrypt for STRING
+// results, and pgp_sym_decrypt_bytea for BYTES results.
+func pgpSymDecryptBuiltin(typ types.T) builtinDefinition {
+	text := typ == types.String
+	fn := func(evalCtx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+		options := ""
+		if len(args) > 2 {
+			options = string(tree.MustBeDString(args[2]))
+		}
+		res, err := pgcrypto.PGPSymDecrypt(
+			evalCtx.Ctx(), evalCtx.ActiveMemAcc,
+			[]byte(*args[0].(*tree.DBytes)), []byte(tree.MustBeDString(args[1])), text, options,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if text {
+			return tree.NewDString(string(res)), nil
+		}
+		return tree.NewDBytes(tree.DBytes(res)), nil
+	}
+	info := "Decrypts the OpenPGP message `data` encrypted with `password`."
+	if text {
+		info += " The message must contain text data."
+	}
+	return makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"data", types.Bytes}, {"password", types.String}},
+			ReturnType: tree.FixedReturnType(typ),
+			Fn:         fn,
+			Info:       info,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"data", types.Bytes}, {"password", types.String}, {"options", types.String}},
+			ReturnType: tree.FixedReturnType(typ),
+			Fn:         fn,
+			Info:       info + pgpOptionsInfo,
+		},
+	)
+}
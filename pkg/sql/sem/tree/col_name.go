@@ -70,8 +70,13 @@ func ComputeColNameInternal(sp sessiondata.SearchPath, target Expr) (int, string
 		return ComputeColNameInternal(sp, e.Expr)
 
 	case *FuncExpr:
-		fd, err := e.Func.Resolve(sp)
+		fd, err := e.Func.Resolve(sp, nil /* resolver */)
 		if err != nil {
+			// The name of a user-defined function can only be resolved with
+			// a resolver: use the name as written.
+			if n, ok := e.Func.FunctionReference.(*UnresolvedName); ok && !n.Star {
+				return 2, n.Parts[0], nil
+			}
 			return 0, "", err
 		}
 		return 2, fd.Name, nil
@@ -421,6 +421,9 @@ var (
 		types.INet,
 		types.JSON,
 		types.BitArray,
+		types.TSVector,
+		types.TSQuery,
+		types.AnyEnum,
 	}
 	// StrValAvailBytes is the set of types convertible to byte array.
 	StrValAvailBytes = []types.T{types.Bytes, types.UUID, types.String}
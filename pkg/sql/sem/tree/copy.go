@@ -35,3 +35,47 @@ func (node *CopyFrom) Format(ctx *FmtCtx) {
 		ctx.WriteString("STDIN")
 	}
 }
+
+// CopyTo represents a COPY TO statement.
+type CopyTo struct {
+	// Table and Columns identify the data to copy out when copying a table.
+	Table   TableName
+	Columns NameList
+	// Statement is the query whose results are copied out. It is nil when
+	// copying a table.
+	Statement Statement
+	Options   KVOptions
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CopyTo) Format(ctx *FmtCtx) {
+	ctx.WriteString("COPY ")
+	if node.Statement != nil {
+		ctx.WriteByte('(')
+		ctx.FormatNode(node.Statement)
+		ctx.WriteByte(')')
+	} else {
+		ctx.FormatNode(&node.Table)
+		if len(node.Columns) > 0 {
+			ctx.WriteString(" (")
+			ctx.FormatNode(&node.Columns)
+			ctx.WriteString(")")
+		}
+	}
+	ctx.WriteString(" TO STDOUT")
+	if len(node.Options) > 0 {
+		ctx.WriteString(" WITH (")
+		for i := range node.Options {
+			o := &node.Options[i]
+			if i > 0 {
+				ctx.WriteString(", ")
+			}
+			ctx.FormatNode(&o.Key)
+			if o.Value != nil {
+				ctx.WriteByte(' ')
+				ctx.FormatNode(o.Value)
+			}
+		}
+		ctx.WriteByte(')')
+	}
+}
@@ -68,6 +68,21 @@ func (node *CreateDatabase) Format(ctx *FmtCtx) {
 	}
 }
 
+// CreateSchema represents a CREATE SCHEMA statement.
+type CreateSchema struct {
+	IfNotExists bool
+	Schema      Name
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateSchema) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE SCHEMA ")
+	if node.IfNotExists {
+		ctx.WriteString("IF NOT EXISTS ")
+	}
+	ctx.FormatNode(&node.Schema)
+}
+
 // IndexElem represents a column with a direction in a CREATE INDEX statement.
 type IndexElem struct {
 	Column    Name
@@ -110,6 +125,9 @@ type CreateIndex struct {
 	Storing     NameList
 	Interleave  *InterleaveDef
 	PartitionBy *PartitionBy
+	// Predicate, if set, restricts the index to the rows that satisfy it
+	// (a partial index).
+	Predicate Expr
 }
 
 // Format implements the NodeFormatter interface.
@@ -145,6 +163,10 @@ func (node *CreateIndex) Format(ctx *FmtCtx) {
 	if node.PartitionBy != nil {
 		ctx.FormatNode(node.PartitionBy)
 	}
+	if node.Predicate != nil {
+		ctx.WriteString(" WHERE ")
+		ctx.FormatNode(node.Predicate)
+	}
 }
 
 // TableDef represents a column, index or constraint definition within a CREATE
@@ -210,6 +232,7 @@ type ColumnTableDef struct {
 		ConstraintName Name
 		Actions        ReferenceActions
 		Match          CompositeKeyMatchMethod
+		Deferrability  ConstraintDeferrability
 	}
 	Computed struct {
 		Computed bool
@@ -316,6 +339,7 @@ func NewColumnTableDef(
 			d.References.ConstraintName = c.Name
 			d.References.Actions = t.Actions
 			d.References.Match = t.Match
+			d.References.Deferrability = t.Deferrability
 		case *ColumnComputedDef:
 			d.Computed.Computed = true
 			d.Computed.Expr = t.Expr
@@ -419,6 +443,7 @@ func (node *ColumnTableDef) Format(ctx *FmtCtx) {
 			ctx.WriteString(node.References.Match.String())
 		}
 		ctx.FormatNode(&node.References.Actions)
+		ctx.FormatNode(node.References.Deferrability)
 	}
 	if node.IsComputed() {
 		ctx.WriteString(" AS (")
@@ -492,10 +517,11 @@ type ColumnCheckConstraint struct {
 
 // ColumnFKConstraint represents a FK-constaint on a column.
 type ColumnFKConstraint struct {
-	Table   TableName
-	Col     Name // empty-string means use PK
-	Actions ReferenceActions
-	Match   CompositeKeyMatchMethod
+	Table         TableName
+	Col           Name // empty-string means use PK
+	Actions       ReferenceActions
+	Match         CompositeKeyMatchMethod
+	Deferrability ConstraintDeferrability
 }
 
 // ColumnComputedDef represents the description of a computed column.
@@ -519,6 +545,9 @@ type IndexTableDef struct {
 	Interleave  *InterleaveDef
 	Inverted    bool
 	PartitionBy *PartitionBy
+	// Predicate, if set, restricts the index to the rows that satisfy it
+	// (a partial index).
+	Predicate Expr
 }
 
 // SetName implements the TableDef interface.
@@ -550,6 +579,10 @@ func (node *IndexTableDef) Format(ctx *FmtCtx) {
 	if node.PartitionBy != nil {
 		ctx.FormatNode(node.PartitionBy)
 	}
+	if node.Predicate != nil {
+		ctx.WriteString(" WHERE ")
+		ctx.FormatNode(node.Predicate)
+	}
 }
 
 // ConstraintTableDef represents a constraint definition within a CREATE TABLE
@@ -596,6 +629,10 @@ func (node *UniqueConstraintTableDef) Format(ctx *FmtCtx) {
 	if node.PartitionBy != nil {
 		ctx.FormatNode(node.PartitionBy)
 	}
+	if node.Predicate != nil {
+		ctx.WriteString(" WHERE ")
+		ctx.FormatNode(node.Predicate)
+	}
 }
 
 // ReferenceAction is the method used to maintain referential integrity through
@@ -663,14 +700,45 @@ func (c CompositeKeyMatchMethod) String() string {
 	return compositeKeyMatchMethodName[c]
 }
 
+// ConstraintDeferrability specifies whether the checks of a constraint can
+// be deferred until the end of the transaction, and whether they are
+// deferred by default.
+type ConstraintDeferrability int
+
+// The values for ConstraintDeferrability.
+const (
+	NotDeferrable ConstraintDeferrability = iota
+	DeferrableInitiallyImmediate
+	DeferrableInitiallyDeferred
+)
+
+var constraintDeferrabilityName = [...]string{
+	NotDeferrable:                "",
+	DeferrableInitiallyImmediate: "DEFERRABLE",
+	DeferrableInitiallyDeferred:  "DEFERRABLE INITIALLY DEFERRED",
+}
+
+func (c ConstraintDeferrability) String() string {
+	return constraintDeferrabilityName[c]
+}
+
+// Format implements the NodeFormatter interface.
+func (c ConstraintDeferrability) Format(ctx *FmtCtx) {
+	if c != NotDeferrable {
+		ctx.WriteByte(' ')
+		ctx.WriteString(c.String())
+	}
+}
+
 // ForeignKeyConstraintTableDef represents a FOREIGN KEY constraint in the AST.
 type ForeignKeyConstraintTableDef struct {
-	Name     Name
-	Table    TableName
-	FromCols NameList
-	ToCols   NameList
-	Actions  ReferenceActions
-	Match    CompositeKeyMatchMethod
+	Name          Name
+	Table         TableName
+	FromCols      NameList
+	ToCols        NameList
+	Actions       ReferenceActions
+	Match         CompositeKeyMatchMethod
+	Deferrability ConstraintDeferrability
 }
 
 // Format implements the NodeFormatter interface.
@@ -698,6 +766,7 @@ func (node *ForeignKeyConstraintTableDef) Format(ctx *FmtCtx) {
 	}
 
 	ctx.FormatNode(&node.Actions)
+	ctx.FormatNode(node.Deferrability)
 }
 
 // SetName implements the TableDef interface.
@@ -876,6 +945,7 @@ func (node *RangePartition) Format(ctx *FmtCtx) {
 // CreateTable represents a CREATE TABLE statement.
 type CreateTable struct {
 	IfNotExists   bool
+	Temporary     bool
 	Table         TableName
 	Interleave    *InterleaveDef
 	PartitionBy   *PartitionBy
@@ -892,7 +962,11 @@ func (node *CreateTable) As() bool {
 
 // Format implements the NodeFormatter interface.
 func (node *CreateTable) Format(ctx *FmtCtx) {
-	ctx.WriteString("CREATE TABLE ")
+	ctx.WriteString("CREATE ")
+	if node.Temporary {
+		ctx.WriteString("TEMPORARY ")
+	}
+	ctx.WriteString("TABLE ")
 	if node.IfNotExists {
 		ctx.WriteString("IF NOT EXISTS ")
 	}
@@ -968,12 +1042,13 @@ func (node *CreateTable) HoistConstraints() {
 					targetCol = append(targetCol, col.References.Col)
 				}
 				node.Defs = append(node.Defs, &ForeignKeyConstraintTableDef{
-					Table:    *col.References.Table,
-					FromCols: NameList{col.Name},
-					ToCols:   targetCol,
-					Name:     col.References.ConstraintName,
-					Actions:  col.References.Actions,
-					Match:    col.References.Match,
+					Table:         *col.References.Table,
+					FromCols:      NameList{col.Name},
+					ToCols:        targetCol,
+					Name:          col.References.ConstraintName,
+					Actions:       col.References.Actions,
+					Match:         col.References.Match,
+					Deferrability: col.References.Deferrability,
 				})
 				col.References.Table = nil
 			}
@@ -984,13 +1059,18 @@ func (node *CreateTable) HoistConstraints() {
 // CreateSequence represents a CREATE SEQUENCE statement.
 type CreateSequence struct {
 	IfNotExists bool
+	Temporary   bool
 	Name        TableName
 	Options     SequenceOptions
 }
 
 // Format implements the NodeFormatter interface.
 func (node *CreateSequence) Format(ctx *FmtCtx) {
-	ctx.WriteString("CREATE SEQUENCE ")
+	ctx.WriteString("CREATE ")
+	if node.Temporary {
+		ctx.WriteString("TEMPORARY ")
+	}
+	ctx.WriteString("SEQUENCE ")
 	if node.IfNotExists {
 		ctx.WriteString("IF NOT EXISTS ")
 	}
@@ -998,6 +1078,26 @@ func (node *CreateSequence) Format(ctx *FmtCtx) {
 	ctx.FormatNode(&node.Options)
 }
 
+// CreateType represents a CREATE TYPE ... AS ENUM statement.
+type CreateType struct {
+	Name       TableName
+	EnumLabels []string
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateType) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE TYPE ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" AS ENUM (")
+	for i, label := range node.EnumLabels {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		lex.EncodeSQLStringWithFlags(ctx.Buffer, label, ctx.flags.EncodeFlags())
+	}
+	ctx.WriteByte(')')
+}
+
 // SequenceOptions represents a list of sequence options.
 type SequenceOptions []SequenceOption
 
@@ -1139,14 +1239,23 @@ func (node *CreateRole) Format(ctx *FmtCtx) {
 
 // CreateView represents a CREATE VIEW statement.
 type CreateView struct {
-	Name        TableName
-	ColumnNames NameList
-	AsSource    *Select
+	Temporary    bool
+	Materialized bool
+	Name         TableName
+	ColumnNames  NameList
+	AsSource     *Select
 }
 
 // Format implements the NodeFormatter interface.
 func (node *CreateView) Format(ctx *FmtCtx) {
-	ctx.WriteString("CREATE VIEW ")
+	ctx.WriteString("CREATE ")
+	if node.Temporary {
+		ctx.WriteString("TEMPORARY ")
+	}
+	if node.Materialized {
+		ctx.WriteString("MATERIALIZED ")
+	}
+	ctx.WriteString("VIEW ")
 	ctx.FormatNode(&node.Name)
 
 	if len(node.ColumnNames) > 0 {
@@ -1186,3 +1295,96 @@ func (node *CreateStats) Format(ctx *FmtCtx) {
 		ctx.FormatNode(&node.AsOf)
 	}
 }
+
+// FunctionVolatility describes whether a user-defined function can return
+// different results when it is called with the same arguments.
+type FunctionVolatility int
+
+// FunctionVolatility values.
+const (
+	// FunctionVolatile functions can return different results at every call,
+	// and can have side effects. This is the default.
+	FunctionVolatile FunctionVolatility = iota
+	// FunctionStable functions return the same results for the same
+	// arguments within a statement.
+	FunctionStable
+	// FunctionImmutable functions always return the same results for the
+	// same arguments.
+	FunctionImmutable
+)
+
+var functionVolatilityName = [...]string{
+	FunctionVolatile:  "VOLATILE",
+	FunctionStable:    "STABLE",
+	FunctionImmutable: "IMMUTABLE",
+}
+
+func (v FunctionVolatility) String() string {
+	if v < 0 || int(v) >= len(functionVolatilityName) {
+		return fmt.Sprintf("FunctionVolatility(%d)", v)
+	}
+	return functionVolatilityName[v]
+}
+
+// FuncArg is an argument in a CREATE FUNCTION or DROP FUNCTION statement.
+// The name of an unnamed argument is empty.
+type FuncArg struct {
+	Name Name
+	Type coltypes.T
+}
+
+// Format implements the NodeFormatter interface.
+func (node *FuncArg) Format(ctx *FmtCtx) {
+	if node.Name != "" {
+		ctx.FormatNode(&node.Name)
+		ctx.WriteByte(' ')
+	}
+	node.Type.Format(ctx.Buffer, ctx.flags.EncodeFlags())
+}
+
+// FuncArgs is a list of function arguments.
+type FuncArgs []FuncArg
+
+// Format implements the NodeFormatter interface.
+func (node *FuncArgs) Format(ctx *FmtCtx) {
+	for i := range *node {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*node)[i])
+	}
+}
+
+// CreateFunction represents a CREATE FUNCTION statement. Only functions
+// written in SQL are supported.
+type CreateFunction struct {
+	Name       TableName
+	Replace    bool
+	Args       FuncArgs
+	ReturnType coltypes.T
+	ReturnsSet bool
+	Volatility FunctionVolatility
+	// Body is the definition of the function, as written in the AS clause.
+	Body string
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateFunction) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE ")
+	if node.Replace {
+		ctx.WriteString("OR REPLACE ")
+	}
+	ctx.WriteString("FUNCTION ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteByte('(')
+	ctx.FormatNode(&node.Args)
+	ctx.WriteString(") RETURNS ")
+	if node.ReturnsSet {
+		ctx.WriteString("SETOF ")
+	}
+	node.ReturnType.Format(ctx.Buffer, ctx.flags.EncodeFlags())
+	ctx.WriteString(" LANGUAGE SQL ")
+	ctx.WriteString(node.Volatility.String())
+	ctx.WriteString(" AS ")
+	lex.EncodeSQLStringWithFlags(ctx.Buffer, node.Body, ctx.flags.EncodeFlags())
+}
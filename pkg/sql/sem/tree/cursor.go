@@ -0,0 +1,169 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tree
+
+import "strconv"
+
+// CursorScrollOption represents the scroll option of a DECLARE statement.
+type CursorScrollOption int8
+
+// CursorScrollOption values.
+const (
+	UnspecifiedScroll CursorScrollOption = iota
+	Scroll
+	NoScroll
+)
+
+// DeclareCursor represents a DECLARE statement.
+type DeclareCursor struct {
+	Name        Name
+	Select      *Select
+	Binary      bool
+	Insensitive bool
+	Scroll      CursorScrollOption
+	Hold        bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DeclareCursor) Format(ctx *FmtCtx) {
+	ctx.WriteString("DECLARE ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteByte(' ')
+	if node.Binary {
+		ctx.WriteString("BINARY ")
+	}
+	if node.Insensitive {
+		ctx.WriteString("INSENSITIVE ")
+	}
+	switch node.Scroll {
+	case Scroll:
+		ctx.WriteString("SCROLL ")
+	case NoScroll:
+		ctx.WriteString("NO SCROLL ")
+	}
+	ctx.WriteString("CURSOR ")
+	if node.Hold {
+		ctx.WriteString("WITH HOLD ")
+	}
+	ctx.WriteString("FOR ")
+	ctx.FormatNode(node.Select)
+}
+
+// FetchDirection is the direction in which a FETCH or MOVE statement
+// repositions a cursor.
+type FetchDirection int8
+
+// FetchDirection values.
+const (
+	// FetchForward reads the next Count rows.
+	FetchForward FetchDirection = iota
+	// FetchBackward reads the previous Count rows, in reverse order.
+	FetchBackward
+	// FetchAbsolute reads the row at position Count, counting from the end if
+	// Count is negative.
+	FetchAbsolute
+	// FetchRelative reads the Count'th row after the current position, or the
+	// Count'th row before it if Count is negative.
+	FetchRelative
+)
+
+// CursorStmt is the part common to FETCH and MOVE statements.
+type CursorStmt struct {
+	Name      Name
+	Direction FetchDirection
+	// Count is the number of rows to move by or the position to move to,
+	// depending on Direction. It is ignored if All is set.
+	Count int64
+	// All is set for FORWARD ALL and BACKWARD ALL.
+	All bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CursorStmt) Format(ctx *FmtCtx) {
+	switch node.Direction {
+	case FetchForward:
+		switch {
+		case node.All:
+			ctx.WriteString("FORWARD ALL")
+		case node.Count == 1:
+			ctx.WriteString("NEXT")
+		default:
+			ctx.WriteString("FORWARD ")
+			ctx.WriteString(strconv.FormatInt(node.Count, 10))
+		}
+	case FetchBackward:
+		switch {
+		case node.All:
+			ctx.WriteString("BACKWARD ALL")
+		case node.Count == 1:
+			ctx.WriteString("PRIOR")
+		default:
+			ctx.WriteString("BACKWARD ")
+			ctx.WriteString(strconv.FormatInt(node.Count, 10))
+		}
+	case FetchAbsolute:
+		switch node.Count {
+		case 1:
+			ctx.WriteString("FIRST")
+		case -1:
+			ctx.WriteString("LAST")
+		default:
+			ctx.WriteString("ABSOLUTE ")
+			ctx.WriteString(strconv.FormatInt(node.Count, 10))
+		}
+	case FetchRelative:
+		ctx.WriteString("RELATIVE ")
+		ctx.WriteString(strconv.FormatInt(node.Count, 10))
+	}
+	ctx.WriteString(" FROM ")
+	ctx.FormatNode(&node.Name)
+}
+
+// FetchCursor represents a FETCH statement.
+type FetchCursor struct {
+	CursorStmt
+}
+
+// Format implements the NodeFormatter interface.
+func (node *FetchCursor) Format(ctx *FmtCtx) {
+	ctx.WriteString("FETCH ")
+	ctx.FormatNode(&node.CursorStmt)
+}
+
+// MoveCursor represents a MOVE statement.
+type MoveCursor struct {
+	CursorStmt
+}
+
+// Format implements the NodeFormatter interface.
+func (node *MoveCursor) Format(ctx *FmtCtx) {
+	ctx.WriteString("MOVE ")
+	ctx.FormatNode(&node.CursorStmt)
+}
+
+// CloseCursor represents a CLOSE statement.
+type CloseCursor struct {
+	Name Name // empty for ALL
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CloseCursor) Format(ctx *FmtCtx) {
+	ctx.WriteString("CLOSE ")
+	if node.Name == "" {
+		ctx.WriteString("ALL")
+	} else {
+		ctx.FormatNode(&node.Name)
+	}
+}
@@ -41,6 +41,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uint128"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/lib/pq/oid"
@@ -1254,6 +1255,137 @@ func (d *DCollatedString) IsComposite() bool {
 	return true
 }
 
+// DEnum is the Datum for a value of a user-defined ENUM type. The struct
+// members are intended to be immutable.
+type DEnum struct {
+	// Typ is the type of the value.
+	Typ types.TEnum
+	// PhysicalRep is the encoding of the value in keys and values. It sorts in
+	// the declaration order of the members of the type.
+	PhysicalRep []byte
+	// LogicalRep is the label of the value.
+	LogicalRep string
+}
+
+// NewDEnumFromLogicalRep creates a *DEnum of the given type from a label.
+func NewDEnumFromLogicalRep(typ types.TEnum, logicalRep string) (*DEnum, error) {
+	physicalRep, ok := typ.PhysicalRep(logicalRep)
+	if !ok {
+		return nil, pgerror.NewErrorf(pgerror.CodeInvalidTextRepresentationError,
+			"invalid input value for enum %s: %q", typ.Name, logicalRep)
+	}
+	return &DEnum{Typ: typ, PhysicalRep: physicalRep, LogicalRep: logicalRep}, nil
+}
+
+// NewDEnumFromPhysicalRep creates a *DEnum of the given type from the
+// encoding of a value.
+func NewDEnumFromPhysicalRep(typ types.TEnum, physicalRep []byte) (*DEnum, error) {
+	logicalRep, ok := typ.LogicalRep(physicalRep)
+	if !ok {
+		return nil, pgerror.NewAssertionErrorf(
+			"could not find %v in the members of enum %s", physicalRep, typ.Name)
+	}
+	return &DEnum{Typ: typ, PhysicalRep: physicalRep, LogicalRep: logicalRep}, nil
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DEnum) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DEnum) Format(ctx *FmtCtx) {
+	if ctx.flags.HasFlags(fmtRawStrings) {
+		ctx.WriteString(d.LogicalRep)
+	} else {
+		lex.EncodeSQLStringWithFlags(ctx.Buffer, d.LogicalRep, ctx.flags.EncodeFlags())
+	}
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (d *DEnum) ResolvedType() types.T {
+	return d.Typ
+}
+
+// Compare implements the Datum interface.
+func (d *DEnum) Compare(ctx *EvalContext, other Datum) int {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1
+	}
+	v, ok := UnwrapDatum(ctx, other).(*DEnum)
+	if !ok || d.Typ.ID != v.Typ.ID {
+		panic(makeUnsupportedComparisonMessage(d, other))
+	}
+	return bytes.Compare(d.PhysicalRep, v.PhysicalRep)
+}
+
+// memberIdx returns the position of the value among the members of its type.
+func (d *DEnum) memberIdx() int {
+	for i, rep := range d.Typ.Members.PhysicalReps {
+		if bytes.Equal(rep, d.PhysicalRep) {
+			return i
+		}
+	}
+	panic(pgerror.NewAssertionErrorf("could not find %q in enum %s", d.LogicalRep, d.Typ.Name))
+}
+
+func (d *DEnum) member(idx int) *DEnum {
+	return &DEnum{
+		Typ:         d.Typ,
+		PhysicalRep: d.Typ.Members.PhysicalReps[idx],
+		LogicalRep:  d.Typ.Members.LogicalReps[idx],
+	}
+}
+
+// Prev implements the Datum interface.
+func (d *DEnum) Prev(_ *EvalContext) (Datum, bool) {
+	idx := d.memberIdx()
+	if idx == 0 {
+		return nil, false
+	}
+	return d.member(idx - 1), true
+}
+
+// Next implements the Datum interface.
+func (d *DEnum) Next(_ *EvalContext) (Datum, bool) {
+	idx := d.memberIdx()
+	if idx == len(d.Typ.Members.PhysicalReps)-1 {
+		return nil, false
+	}
+	return d.member(idx + 1), true
+}
+
+// IsMax implements the Datum interface.
+func (d *DEnum) IsMax(_ *EvalContext) bool {
+	return d.memberIdx() == len(d.Typ.Members.PhysicalReps)-1
+}
+
+// IsMin implements the Datum interface.
+func (d *DEnum) IsMin(_ *EvalContext) bool {
+	return d.memberIdx() == 0
+}
+
+// Min implements the Datum interface.
+func (d *DEnum) Min(_ *EvalContext) (Datum, bool) {
+	if len(d.Typ.Members.PhysicalReps) == 0 {
+		return nil, false
+	}
+	return d.member(0), true
+}
+
+// Max implements the Datum interface.
+func (d *DEnum) Max(_ *EvalContext) (Datum, bool) {
+	n := len(d.Typ.Members.PhysicalReps)
+	if n == 0 {
+		return nil, false
+	}
+	return d.member(n - 1), true
+}
+
+// Size implements the Datum interface.
+func (d *DEnum) Size() uintptr {
+	return unsafe.Sizeof(*d) + uintptr(len(d.PhysicalRep)) + uintptr(len(d.LogicalRep))
+}
+
 // DBytes is the bytes Datum. The underlying type is a string because we want
 // the immutability, but this may contain arbitrary bytes.
 type DBytes string
@@ -2433,6 +2565,8 @@ func AsJSON(d Datum) (json.JSON, error) {
 		return json.FromString(string(*t)), nil
 	case *DCollatedString:
 		return json.FromString(t.Contents), nil
+	case *DEnum:
+		return json.FromString(t.LogicalRep), nil
 	case *DJSON:
 		return t.JSON, nil
 	case *DArray:
@@ -2455,7 +2589,8 @@ func AsJSON(d Datum) (json.JSON, error) {
 			builder.Add(fmt.Sprintf("f%d", i+1), j)
 		}
 		return builder.Build(), nil
-	case *DTimestamp, *DTimestampTZ, *DDate, *DUuid, *DOid, *DInterval, *DBytes, *DIPAddr, *DTime, *DBitArray:
+	case *DTimestamp, *DTimestampTZ, *DDate, *DUuid, *DOid, *DInterval, *DBytes, *DIPAddr, *DTime, *DBitArray,
+		*DTSVector, *DTSQuery:
 		return json.FromString(AsStringWithFlags(t, FmtBareStrings)), nil
 	default:
 		if d == DNull {
@@ -2542,6 +2677,224 @@ func (d *DJSON) Size() uintptr {
 	return unsafe.Sizeof(*d) + d.JSON.Size()
 }
 
+// DTSVector is the TSVECTOR Datum, a document prepared for full-text search.
+type DTSVector struct {
+	tsearch.TSVector
+}
+
+// NewDTSVector is a helper routine to create a *DTSVector initialized from
+// its argument.
+func NewDTSVector(v tsearch.TSVector) *DTSVector {
+	return &DTSVector{v}
+}
+
+// ParseDTSVector parses and returns the *DTSVector Datum value represented by
+// the provided string, or an error if parsing is unsuccessful.
+func ParseDTSVector(s string) (*DTSVector, error) {
+	v, err := tsearch.ParseTSVector(s)
+	if err != nil {
+		return nil, err
+	}
+	return NewDTSVector(v), nil
+}
+
+// AsDTSVector attempts to retrieve a *DTSVector from an Expr, returning a
+// *DTSVector and a flag signifying whether the assertion was successful.
+func AsDTSVector(e Expr) (*DTSVector, bool) {
+	switch t := e.(type) {
+	case *DTSVector:
+		return t, true
+	case *DOidWrapper:
+		return AsDTSVector(t.Wrapped)
+	}
+	return nil, false
+}
+
+// MustBeDTSVector attempts to retrieve a *DTSVector from an Expr, panicking
+// if the assertion fails.
+func MustBeDTSVector(e Expr) *DTSVector {
+	v, ok := AsDTSVector(e)
+	if !ok {
+		panic(pgerror.NewAssertionErrorf("expected *DTSVector, found %T", e))
+	}
+	return v
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (*DTSVector) ResolvedType() types.T {
+	return types.TSVector
+}
+
+// Compare implements the Datum interface.
+func (d *DTSVector) Compare(ctx *EvalContext, other Datum) int {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1
+	}
+	v, ok := UnwrapDatum(ctx, other).(*DTSVector)
+	if !ok {
+		panic(makeUnsupportedComparisonMessage(d, other))
+	}
+	return d.TSVector.Compare(v.TSVector)
+}
+
+// Prev implements the Datum interface.
+func (d *DTSVector) Prev(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// Next implements the Datum interface.
+func (d *DTSVector) Next(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// IsMax implements the Datum interface.
+func (d *DTSVector) IsMax(_ *EvalContext) bool {
+	return false
+}
+
+// IsMin implements the Datum interface.
+func (d *DTSVector) IsMin(_ *EvalContext) bool {
+	return len(d.TSVector) == 0
+}
+
+// Max implements the Datum interface.
+func (d *DTSVector) Max(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// Min implements the Datum interface.
+func (d *DTSVector) Min(_ *EvalContext) (Datum, bool) {
+	return &DTSVector{}, true
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DTSVector) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DTSVector) Format(ctx *FmtCtx) {
+	s := d.TSVector.String()
+	if ctx.flags.HasFlags(fmtRawStrings) {
+		ctx.Buffer.WriteString(s)
+	} else {
+		lex.EncodeSQLStringWithFlags(ctx.Buffer, s, ctx.flags.EncodeFlags())
+	}
+}
+
+// Size implements the Datum interface.
+func (d *DTSVector) Size() uintptr {
+	return unsafe.Sizeof(*d) + d.TSVector.Size()
+}
+
+// DTSQuery is the TSQUERY Datum, a full-text search query.
+type DTSQuery struct {
+	tsearch.TSQuery
+}
+
+// NewDTSQuery is a helper routine to create a *DTSQuery initialized from its
+// argument.
+func NewDTSQuery(q tsearch.TSQuery) *DTSQuery {
+	return &DTSQuery{q}
+}
+
+// ParseDTSQuery parses and returns the *DTSQuery Datum value represented by
+// the provided string, or an error if parsing is unsuccessful.
+func ParseDTSQuery(s string) (*DTSQuery, error) {
+	q, err := tsearch.ParseTSQuery(s)
+	if err != nil {
+		return nil, err
+	}
+	return NewDTSQuery(q), nil
+}
+
+// AsDTSQuery attempts to retrieve a *DTSQuery from an Expr, returning a
+// *DTSQuery and a flag signifying whether the assertion was successful.
+func AsDTSQuery(e Expr) (*DTSQuery, bool) {
+	switch t := e.(type) {
+	case *DTSQuery:
+		return t, true
+	case *DOidWrapper:
+		return AsDTSQuery(t.Wrapped)
+	}
+	return nil, false
+}
+
+// MustBeDTSQuery attempts to retrieve a *DTSQuery from an Expr, panicking if
+// the assertion fails.
+func MustBeDTSQuery(e Expr) *DTSQuery {
+	q, ok := AsDTSQuery(e)
+	if !ok {
+		panic(pgerror.NewAssertionErrorf("expected *DTSQuery, found %T", e))
+	}
+	return q
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (*DTSQuery) ResolvedType() types.T {
+	return types.TSQuery
+}
+
+// Compare implements the Datum interface.
+func (d *DTSQuery) Compare(ctx *EvalContext, other Datum) int {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1
+	}
+	v, ok := UnwrapDatum(ctx, other).(*DTSQuery)
+	if !ok {
+		panic(makeUnsupportedComparisonMessage(d, other))
+	}
+	return d.TSQuery.Compare(v.TSQuery)
+}
+
+// Prev implements the Datum interface.
+func (d *DTSQuery) Prev(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// Next implements the Datum interface.
+func (d *DTSQuery) Next(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// IsMax implements the Datum interface.
+func (d *DTSQuery) IsMax(_ *EvalContext) bool {
+	return false
+}
+
+// IsMin implements the Datum interface.
+func (d *DTSQuery) IsMin(_ *EvalContext) bool {
+	return d.Root == nil
+}
+
+// Max implements the Datum interface.
+func (d *DTSQuery) Max(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// Min implements the Datum interface.
+func (d *DTSQuery) Min(_ *EvalContext) (Datum, bool) {
+	return &DTSQuery{}, true
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DTSQuery) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DTSQuery) Format(ctx *FmtCtx) {
+	s := d.TSQuery.String()
+	if ctx.flags.HasFlags(fmtRawStrings) {
+		ctx.Buffer.WriteString(s)
+	} else {
+		lex.EncodeSQLStringWithFlags(ctx.Buffer, s, ctx.flags.EncodeFlags())
+	}
+}
+
+// Size implements the Datum interface.
+func (d *DTSQuery) Size() uintptr {
+	return unsafe.Sizeof(*d) + d.TSQuery.Size()
+}
+
 // DTuple is the tuple Datum.
 type DTuple struct {
 	D Datums
@@ -3510,6 +3863,9 @@ func DatumTypeSize(t types.T) (uintptr, bool) {
 	case types.TCollatedString:
 		return unsafe.Sizeof(DCollatedString{"", "", nil}), variableSize
 
+	case types.TEnum:
+		return unsafe.Sizeof(DEnum{}), variableSize
+
 	case types.TTuple:
 		sz := uintptr(0)
 		variable := false
@@ -3556,6 +3912,8 @@ var baseDatumTypeSizes = map[types.T]struct {
 	types.TimestampTZ: {unsafe.Sizeof(DTimestampTZ{}), fixedSize},
 	types.Interval:    {unsafe.Sizeof(DInterval{}), fixedSize},
 	types.JSON:        {unsafe.Sizeof(DJSON{}), variableSize},
+	types.TSVector:    {unsafe.Sizeof(DTSVector{}), variableSize},
+	types.TSQuery:     {unsafe.Sizeof(DTSQuery{}), variableSize},
 	types.UUID:        {unsafe.Sizeof(DUuid{}), fixedSize},
 	types.INet:        {unsafe.Sizeof(DIPAddr{}), fixedSize},
 	// TODO(jordan,justin): This seems suspicious.
@@ -1635,6 +1635,64 @@ func init() {
 			NullableArgs: true,
 		})
 	}
+
+	// Array containment and overlap comparisons.
+	for _, t := range types.AnyNonArray {
+		CmpOps[Contains] = append(CmpOps[Contains], &CmpOp{
+			LeftType:  types.TArray{Typ: t},
+			RightType: types.TArray{Typ: t},
+			Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(arrayContains(ctx, MustBeDArray(left), MustBeDArray(right)))), nil
+			},
+		})
+
+		CmpOps[ContainedBy] = append(CmpOps[ContainedBy], &CmpOp{
+			LeftType:  types.TArray{Typ: t},
+			RightType: types.TArray{Typ: t},
+			Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(arrayContains(ctx, MustBeDArray(right), MustBeDArray(left)))), nil
+			},
+		})
+
+		CmpOps[Overlaps] = append(CmpOps[Overlaps], &CmpOp{
+			LeftType:  types.TArray{Typ: t},
+			RightType: types.TArray{Typ: t},
+			Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(arrayOverlaps(ctx, MustBeDArray(left), MustBeDArray(right)))), nil
+			},
+		})
+	}
+}
+
+// arrayContains returns whether every element of needles is equal to some
+// element of haystack. As in Postgres, the dimensions and the multiplicity of
+// the elements are ignored, and a NULL element never matches.
+func arrayContains(ctx *EvalContext, haystack, needles *DArray) bool {
+	for _, n := range needles.Array {
+		if n == DNull || !arrayHasElement(ctx, haystack, n) {
+			return false
+		}
+	}
+	return true
+}
+
+// arrayOverlaps returns whether the arrays have a non-NULL element in common.
+func arrayOverlaps(ctx *EvalContext, left, right *DArray) bool {
+	for _, n := range right.Array {
+		if n != DNull && arrayHasElement(ctx, left, n) {
+			return true
+		}
+	}
+	return false
+}
+
+func arrayHasElement(ctx *EvalContext, arr *DArray, elem Datum) bool {
+	for _, e := range arr.Array {
+		if e != DNull && e.Compare(ctx, elem) == 0 {
+			return true
+		}
+	}
+	return false
 }
 
 func init() {
@@ -1692,6 +1750,7 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 		makeEqFn(types.Bytes, types.Bytes),
 		makeEqFn(types.Date, types.Date),
 		makeEqFn(types.Decimal, types.Decimal),
+		makeEqFn(types.AnyEnum, types.AnyEnum),
 		makeEqFn(types.FamCollatedString, types.FamCollatedString),
 		makeEqFn(types.Float, types.Float),
 		makeEqFn(types.INet, types.INet),
@@ -1703,6 +1762,8 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 		makeEqFn(types.Time, types.Time),
 		makeEqFn(types.Timestamp, types.Timestamp),
 		makeEqFn(types.TimestampTZ, types.TimestampTZ),
+		makeEqFn(types.TSQuery, types.TSQuery),
+		makeEqFn(types.TSVector, types.TSVector),
 		makeEqFn(types.UUID, types.UUID),
 		makeEqFn(types.BitArray, types.BitArray),
 
@@ -1736,6 +1797,7 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 		makeLtFn(types.Bytes, types.Bytes),
 		makeLtFn(types.Date, types.Date),
 		makeLtFn(types.Decimal, types.Decimal),
+		makeLtFn(types.AnyEnum, types.AnyEnum),
 		makeLtFn(types.FamCollatedString, types.FamCollatedString),
 		makeLtFn(types.Float, types.Float),
 		makeLtFn(types.INet, types.INet),
@@ -1746,6 +1808,8 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 		makeLtFn(types.Time, types.Time),
 		makeLtFn(types.Timestamp, types.Timestamp),
 		makeLtFn(types.TimestampTZ, types.TimestampTZ),
+		makeLtFn(types.TSQuery, types.TSQuery),
+		makeLtFn(types.TSVector, types.TSVector),
 		makeLtFn(types.UUID, types.UUID),
 		makeLtFn(types.BitArray, types.BitArray),
 
@@ -1779,6 +1843,7 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 		makeLeFn(types.Bytes, types.Bytes),
 		makeLeFn(types.Date, types.Date),
 		makeLeFn(types.Decimal, types.Decimal),
+		makeLeFn(types.AnyEnum, types.AnyEnum),
 		makeLeFn(types.FamCollatedString, types.FamCollatedString),
 		makeLeFn(types.Float, types.Float),
 		makeLeFn(types.INet, types.INet),
@@ -1789,6 +1854,8 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 		makeLeFn(types.Time, types.Time),
 		makeLeFn(types.Timestamp, types.Timestamp),
 		makeLeFn(types.TimestampTZ, types.TimestampTZ),
+		makeLeFn(types.TSQuery, types.TSQuery),
+		makeLeFn(types.TSVector, types.TSVector),
 		makeLeFn(types.UUID, types.UUID),
 		makeLeFn(types.BitArray, types.BitArray),
 
@@ -1830,6 +1897,7 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 		makeIsFn(types.Bytes, types.Bytes),
 		makeIsFn(types.Date, types.Date),
 		makeIsFn(types.Decimal, types.Decimal),
+		makeIsFn(types.AnyEnum, types.AnyEnum),
 		makeIsFn(types.FamCollatedString, types.FamCollatedString),
 		makeIsFn(types.Float, types.Float),
 		makeIsFn(types.INet, types.INet),
@@ -1841,6 +1909,8 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 		makeIsFn(types.Time, types.Time),
 		makeIsFn(types.Timestamp, types.Timestamp),
 		makeIsFn(types.TimestampTZ, types.TimestampTZ),
+		makeIsFn(types.TSQuery, types.TSQuery),
+		makeIsFn(types.TSVector, types.TSVector),
 		makeIsFn(types.UUID, types.UUID),
 		makeIsFn(types.BitArray, types.BitArray),
 
@@ -1877,6 +1947,7 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 		makeEvalTupleIn(types.Bytes),
 		makeEvalTupleIn(types.Date),
 		makeEvalTupleIn(types.Decimal),
+		makeEvalTupleIn(types.AnyEnum),
 		makeEvalTupleIn(types.FamCollatedString),
 		makeEvalTupleIn(types.FamTuple),
 		makeEvalTupleIn(types.Float),
@@ -2036,6 +2107,37 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 			},
 		},
 	},
+
+	Overlaps: {
+		&CmpOp{
+			LeftType:  types.INet,
+			RightType: types.INet,
+			Fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				ipAddr := MustBeDIPAddr(left).IPAddr
+				other := MustBeDIPAddr(right).IPAddr
+				return MakeDBool(DBool(ipAddr.ContainsOrContainedBy(&other))), nil
+			},
+		},
+	},
+
+	TSMatches: {
+		&CmpOp{
+			LeftType:  types.TSVector,
+			RightType: types.TSQuery,
+			Fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				q := MustBeDTSQuery(right).TSQuery
+				return MakeDBool(DBool(q.Matches(MustBeDTSVector(left).TSVector))), nil
+			},
+		},
+		&CmpOp{
+			LeftType:  types.TSQuery,
+			RightType: types.TSVector,
+			Fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				q := MustBeDTSQuery(left).TSQuery
+				return MakeDBool(DBool(q.Matches(MustBeDTSVector(right).TSVector))), nil
+			},
+		},
+	},
 }
 
 // This map contains the inverses for operators in the CmpOps map that have
@@ -2361,6 +2463,10 @@ type EvalPlanner interface {
 
 	// EvalSubquery returns the Datum for the given subquery node.
 	EvalSubquery(expr *Subquery) (Datum, error)
+
+	// SendNotification queues a notification on the given channel, to be sent
+	// to the listening sessions when the current transaction commits.
+	SendNotification(ctx context.Context, channel, payload string) error
 }
 
 // SessionBoundInternalExecutor is a subset of sqlutil.InternalExecutor used by
@@ -2878,9 +2984,90 @@ func (expr *CastExpr) Eval(ctx *EvalContext) (Datum, error) {
 	return PerformCast(ctx, d, expr.Type)
 }
 
+// RoundTimeDatum rounds the seconds field of the given TIME, TIMESTAMP or
+// TIMESTAMPTZ datum to the given number of fractional digits. Other datums
+// are returned unchanged.
+func RoundTimeDatum(d Datum, precision int) Datum {
+	if precision >= coltypes.MaxTimePrecision {
+		return d
+	}
+	round := time.Microsecond
+	for i := precision; i < coltypes.MaxTimePrecision; i++ {
+		round *= 10
+	}
+	switch t := d.(type) {
+	case *DTimestamp:
+		return MakeDTimestamp(t.Time, round)
+	case *DTimestampTZ:
+		return MakeDTimestampTZ(t.Time, round)
+	case *DTime:
+		micros := int64(round / time.Microsecond)
+		return MakeDTime(timeofday.FromInt((int64(*t) + micros/2) / micros * micros))
+	}
+	return d
+}
+
+// TimeZoneNameToLocation returns the location with the given IANA time zone
+// name, e.g. 'America/New_York'. When there is no exact match, the name is
+// also looked up in upper case, so that e.g. 'utc' is recognized.
+func TimeZoneNameToLocation(name string) (*time.Location, error) {
+	loc, err := timeutil.LoadLocation(name)
+	if err != nil {
+		var err1 error
+		loc, err1 = timeutil.LoadLocation(strings.ToUpper(name))
+		if err1 != nil {
+			loc, err1 = timeutil.LoadLocation(strings.ToTitle(name))
+			if err1 != nil {
+				return nil, err
+			}
+		}
+	}
+	return loc, nil
+}
+
+// TimeZoneIntervalToLocation returns a location with the fixed UTC offset
+// given by an interval, e.g. INTERVAL '-08:00'.
+func TimeZoneIntervalToLocation(d *DInterval) (*time.Location, error) {
+	offset, _, _, err := d.Duration.Div(time.Second.Nanoseconds()).Encode()
+	if err != nil {
+		return nil, err
+	}
+	return timeutil.FixedOffsetTimeZoneToLocation(int(offset), d.String()), nil
+}
+
+// TimestampAtTimeZone implements TIMESTAMP AT TIME ZONE: it interprets the
+// given TIMESTAMP as a wall clock time in the given location and returns the
+// corresponding TIMESTAMPTZ.
+func TimestampAtTimeZone(ts *DTimestamp, loc *time.Location) *DTimestampTZ {
+	t := ts.Time
+	return MakeDTimestampTZ(time.Date(
+		t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc,
+	).UTC(), time.Microsecond)
+}
+
+// TimestampTZAtTimeZone implements TIMESTAMPTZ AT TIME ZONE: it returns the
+// wall clock time of the given TIMESTAMPTZ in the given location as a
+// TIMESTAMP.
+func TimestampTZAtTimeZone(ts *DTimestampTZ, loc *time.Location) *DTimestamp {
+	t := ts.Time.In(loc)
+	return MakeDTimestamp(time.Date(
+		t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC,
+	), time.Microsecond)
+}
+
 // PerformCast performs a cast from the provided Datum to the specified
 // CastTargetType.
 func PerformCast(ctx *EvalContext, d Datum, t coltypes.CastTargetType) (Datum, error) {
+	// A cast to a time type with an explicit precision is a cast to the type
+	// without precision followed by the rounding of the result.
+	if base, precision, ok := coltypes.TimePrecision(t); ok {
+		res, err := PerformCast(ctx, d, base)
+		if err != nil {
+			return nil, err
+		}
+		return RoundTimeDatum(res, precision), nil
+	}
+
 	switch typ := t.(type) {
 	case *coltypes.TBitArray:
 		switch v := d.(type) {
@@ -3112,6 +3299,8 @@ func PerformCast(ctx *EvalContext, d Datum, t coltypes.CastTargetType) (Datum, e
 			s = string(*t)
 		case *DCollatedString:
 			s = t.Contents
+		case *DEnum:
+			s = t.LogicalRep
 		case *DBytes:
 			s = lex.EncodeByteArrayToRawBytes(string(*t),
 				ctx.SessionData.DataConversion.BytesEncodeFormat, false /* skipHexPrefix */)
@@ -3119,6 +3308,10 @@ func PerformCast(ctx *EvalContext, d Datum, t coltypes.CastTargetType) (Datum, e
 			s = t.name
 		case *DJSON:
 			s = t.JSON.String()
+		case *DTSVector:
+			s = t.TSVector.String()
+		case *DTSQuery:
+			s = t.TSQuery.String()
 		}
 		switch c := t.(type) {
 		case *coltypes.TString:
@@ -3283,6 +3476,26 @@ func PerformCast(ctx *EvalContext, d Datum, t coltypes.CastTargetType) (Datum, e
 		case *DJSON:
 			return v, nil
 		}
+
+	case *coltypes.TTSVector:
+		switch v := d.(type) {
+		case *DString:
+			return ParseDTSVector(string(*v))
+		case *DCollatedString:
+			return ParseDTSVector(v.Contents)
+		case *DTSVector:
+			return v, nil
+		}
+
+	case *coltypes.TTSQuery:
+		switch v := d.(type) {
+		case *DString:
+			return ParseDTSQuery(string(*v))
+		case *DCollatedString:
+			return ParseDTSQuery(v.Contents)
+		case *DTSQuery:
+			return v, nil
+		}
 	case *coltypes.TArray:
 		switch v := d.(type) {
 		case *DString:
@@ -3306,6 +3519,22 @@ func PerformCast(ctx *EvalContext, d Datum, t coltypes.CastTargetType) (Datum, e
 			}
 			return dcast, nil
 		}
+	case *coltypes.TUserDefined:
+		enumTyp, ok := typ.Resolved.(types.TEnum)
+		if !ok {
+			break
+		}
+		switch v := d.(type) {
+		case *DString:
+			return NewDEnumFromLogicalRep(enumTyp, string(*v))
+		case *DCollatedString:
+			return NewDEnumFromLogicalRep(enumTyp, v.Contents)
+		case *DEnum:
+			if v.Typ.ID == enumTyp.ID {
+				return d, nil
+			}
+		}
+
 	case *coltypes.TOid:
 		switch v := d.(type) {
 		case *DOid:
@@ -3929,6 +4158,16 @@ func (t *DJSON) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
 }
 
+// Eval implements the TypedExpr interface.
+func (t *DTSVector) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
+// Eval implements the TypedExpr interface.
+func (t *DTSQuery) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
 // Eval implements the TypedExpr interface.
 func (t dNull) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
@@ -3944,6 +4183,11 @@ func (t *DCollatedString) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
 }
 
+// Eval implements the TypedExpr interface.
+func (t *DEnum) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
 // Eval implements the TypedExpr interface.
 func (t *DTimestamp) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
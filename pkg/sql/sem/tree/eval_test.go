@@ -275,6 +275,8 @@ func TestEvalError(t *testing.T) {
 		{`'12:00:00q'::time`, `could not parse "12:00:00q" as type time`},
 		{`'2010-09-28 12:00.1 MST'::timestamp`,
 			`unimplemented: timestamp abbreviations not supported`},
+		{`'2010-09-28 12:00'::timestamp AT TIME ZONE 'Mars/Olympus_Mons'`,
+			`time zone "Mars/Olympus_Mons" not recognized`},
 		{`'abcd'::interval`,
 			`could not parse "abcd" as type interval: interval: missing unit`},
 		{`'1- 2:3:4 9'::interval`,
@@ -316,6 +316,8 @@ const (
 	JSONExists
 	JSONSomeExists
 	JSONAllExists
+	Overlaps
+	TSMatches
 
 	// The following operators will always be used with an associated SubOperator.
 	// If Go had algebraic data types they would be defined in a self-contained
@@ -365,6 +367,8 @@ var comparisonOpName = [...]string{
 	JSONExists:        "?",
 	JSONSomeExists:    "?|",
 	JSONAllExists:     "?&",
+	Overlaps:          "&&",
+	TSMatches:         "@@",
 	Any:               "ANY",
 	Some:              "SOME",
 	All:               "ALL",
@@ -1219,6 +1223,9 @@ type FuncExpr struct {
 	Func  ResolvableFunctionReference
 	Type  funcType
 	Exprs Exprs
+	// WithinGroup is used for the ordering of the input of ordered-set
+	// aggregates: percentile_disc(0.5) WITHIN GROUP (ORDER BY k)
+	WithinGroup OrderBy
 	// Filter is used for filters on aggregates: SUM(k) FILTER (WHERE k > 0)
 	Filter    Expr
 	WindowDef *WindowDef
@@ -1268,11 +1275,21 @@ func (node *FuncExpr) GetAggregateConstructor() func(*EvalContext, Datums) Aggre
 		return nil
 	}
 	return func(evalCtx *EvalContext, arguments Datums) AggregateFunc {
-		types := typesOfExprs(node.Exprs)
+		types := typesOfExprs(node.AggregateArgs())
 		return node.fn.AggregateFunc(types, evalCtx, arguments)
 	}
 }
 
+// AggregateArgs returns the arguments passed to the implementation of an
+// aggregate function. For ordered-set aggregates, these are the expression of
+// the WITHIN GROUP ordering followed by the direct arguments of the call.
+func (node *FuncExpr) AggregateArgs() Exprs {
+	if len(node.WithinGroup) == 0 {
+		return node.Exprs
+	}
+	return append(Exprs{node.WithinGroup[0].Expr}, node.Exprs...)
+}
+
 // GetWindowConstructor returns a window function constructor if the
 // FuncExpr is a built-in window function.
 func (node *FuncExpr) GetWindowConstructor() func(*EvalContext) WindowFunc {
@@ -1358,6 +1375,11 @@ func (node *FuncExpr) Format(ctx *FmtCtx) {
 			}
 		}
 	}
+	if len(node.WithinGroup) > 0 {
+		ctx.WriteString(" WITHIN GROUP (")
+		ctx.FormatNode(&node.WithinGroup)
+		ctx.WriteByte(')')
+	}
 	if node.Filter != nil {
 		ctx.WriteString(" FILTER (WHERE ")
 		ctx.FormatNode(node.Filter)
@@ -1502,7 +1524,8 @@ var (
 	stringCastTypes = []types.T{types.Unknown, types.Bool, types.Int, types.Float, types.Decimal, types.String, types.FamCollatedString,
 		types.BitArray,
 		types.FamArray, types.FamTuple,
-		types.Bytes, types.Timestamp, types.TimestampTZ, types.Interval, types.UUID, types.Date, types.Time, types.Oid, types.INet, types.JSON}
+		types.Bytes, types.Timestamp, types.TimestampTZ, types.Interval, types.UUID, types.Date, types.Time, types.Oid, types.INet, types.JSON,
+		types.TSVector, types.TSQuery, types.AnyEnum}
 	bytesCastTypes = []types.T{types.Unknown, types.String, types.FamCollatedString, types.Bytes, types.UUID}
 	dateCastTypes  = []types.T{types.Unknown, types.String, types.FamCollatedString, types.Date, types.Timestamp, types.TimestampTZ, types.Int}
 	timeCastTypes  = []types.T{types.Unknown, types.String, types.FamCollatedString, types.Time,
@@ -1514,6 +1537,9 @@ var (
 	inetCastTypes      = []types.T{types.Unknown, types.String, types.FamCollatedString, types.INet}
 	arrayCastTypes     = []types.T{types.Unknown, types.String}
 	jsonCastTypes      = []types.T{types.Unknown, types.String, types.JSON}
+	tsvectorCastTypes  = []types.T{types.Unknown, types.String, types.FamCollatedString, types.TSVector}
+	tsqueryCastTypes   = []types.T{types.Unknown, types.String, types.FamCollatedString, types.TSQuery}
+	enumCastTypes      = []types.T{types.Unknown, types.String, types.FamCollatedString, types.AnyEnum}
 )
 
 // validCastTypes returns a set of types that can be cast into the provided type.
@@ -1547,6 +1573,10 @@ func validCastTypes(t types.T) []types.T {
 		return uuidCastTypes
 	case types.INet:
 		return inetCastTypes
+	case types.TSVector:
+		return tsvectorCastTypes
+	case types.TSQuery:
+		return tsqueryCastTypes
 	case types.Oid, types.RegClass, types.RegNamespace, types.RegProc, types.RegProcedure, types.RegType:
 		return oidCastTypes
 	default:
@@ -1558,6 +1588,8 @@ func validCastTypes(t types.T) []types.T {
 			ret := make([]types.T, len(arrayCastTypes))
 			copy(ret, arrayCastTypes)
 			return ret
+		} else if t.FamilyEqual(types.AnyEnum) {
+			return enumCastTypes
 		}
 		return nil
 	}
@@ -1715,10 +1747,13 @@ func (node *DFloat) String() string           { return AsString(node) }
 func (node *DInt) String() string             { return AsString(node) }
 func (node *DInterval) String() string        { return AsString(node) }
 func (node *DJSON) String() string            { return AsString(node) }
+func (node *DTSVector) String() string        { return AsString(node) }
+func (node *DTSQuery) String() string         { return AsString(node) }
 func (node *DUuid) String() string            { return AsString(node) }
 func (node *DIPAddr) String() string          { return AsString(node) }
 func (node *DString) String() string          { return AsString(node) }
 func (node *DCollatedString) String() string  { return AsString(node) }
+func (node *DEnum) String() string            { return AsString(node) }
 func (node *DTimestamp) String() string       { return AsString(node) }
 func (node *DTimestampTZ) String() string     { return AsString(node) }
 func (node *DTuple) String() string           { return AsString(node) }
@@ -84,6 +84,12 @@ type FunctionProperties struct {
 	// Class is the kind of built-in function (normal/aggregate/window/etc.)
 	Class FunctionClass
 
+	// OrderedSet is set to true for the aggregate functions that must be
+	// called with a WITHIN GROUP clause, e.g. percentile_disc(0.5) WITHIN
+	// GROUP (ORDER BY k). The expression of the ordering is passed to the
+	// function as its first argument, followed by the direct arguments.
+	OrderedSet bool
+
 	// Category is used to generate documentation strings.
 	Category string
 
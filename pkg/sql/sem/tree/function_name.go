@@ -29,6 +29,11 @@ import (
 // attribute.  This is populated during parsing with an
 // UnresolvedName, and gets assigned a FunctionDefinition upon the
 // first call to its Resolve() method.
+//
+// The names of the user-defined functions are resolved by a
+// FunctionReferenceResolver when no built-in function matches. Their
+// definitions are not cached in the reference, since they can change
+// between two uses of the same syntax tree.
 
 // ResolvableFunctionReference implements the editable reference cell
 // of a FuncExpr. The FunctionRerence is updated by the Normalize()
@@ -44,9 +49,11 @@ func (fn *ResolvableFunctionReference) Format(ctx *FmtCtx) {
 func (fn *ResolvableFunctionReference) String() string { return AsString(fn) }
 
 // Resolve checks if the function name is already resolved and
-// resolves it as necessary.
+// resolves it as necessary. If resolver is not nil, it is used to look
+// up the user-defined functions whose names do not match a built-in
+// function.
 func (fn *ResolvableFunctionReference) Resolve(
-	searchPath sessiondata.SearchPath,
+	searchPath sessiondata.SearchPath, resolver FunctionReferenceResolver,
 ) (*FunctionDefinition, error) {
 	switch t := fn.FunctionReference.(type) {
 	case *FunctionDefinition:
@@ -54,7 +61,20 @@ func (fn *ResolvableFunctionReference) Resolve(
 	case *UnresolvedName:
 		fd, err := t.ResolveFunction(searchPath)
 		if err != nil {
-			return nil, err
+			if resolver == nil {
+				return nil, err
+			}
+			if pgErr, ok := pgerror.GetPGCause(err); !ok || pgErr.Code != pgerror.CodeUndefinedFunctionError {
+				return nil, err
+			}
+			udf, udfErr := resolver.ResolveFunctionReference(t)
+			if udfErr != nil {
+				return nil, udfErr
+			}
+			if udf == nil {
+				return nil, err
+			}
+			return udf, nil
 		}
 		fn.FunctionReference = fd
 		return fd, nil
@@ -65,6 +85,13 @@ func (fn *ResolvableFunctionReference) Resolve(
 	}
 }
 
+// FunctionReferenceResolver resolves the names of user-defined functions.
+type FunctionReferenceResolver interface {
+	// ResolveFunctionReference returns the definition of the user-defined
+	// function with the given name, or nil if there is no such function.
+	ResolveFunctionReference(name *UnresolvedName) (*FunctionDefinition, error)
+}
+
 // WrapFunction creates a new ResolvableFunctionReference
 // holding a pre-resolved function. Helper for grammar rules.
 func WrapFunction(n string) ResolvableFunctionReference {
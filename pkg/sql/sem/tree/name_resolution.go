@@ -435,17 +435,12 @@ func (tp *TableNamePrefix) Resolve(
 
 // ResolveFunction transforms an UnresolvedName to a FunctionDefinition.
 //
-// Function resolution currently takes a "short path" using the
-// assumption that there are no stored functions in the database. That
-// is, only functions in the (virtual) global namespace and virtual
-// schemas can be used. This in turn implies that the current
-// database does not matter and no resolver is needed.
-//
-// TODO(whoever): this needs to be revisited when there can be stored functions.
-// When that is the case, function names must be first normalized to e.g.
-// TableName (or whatever an object name will be called by then)
-// and then undergo regular name resolution via ResolveExisting(). When
-// that happens, the following function can be removed.
+// Only the built-in functions are considered, that is, the functions
+// in the (virtual) global namespace and virtual schemas. This implies
+// that the current database does not matter and no resolver is
+// needed. The user-defined functions, which are stored in the
+// database, are looked up by ResolvableFunctionReference.Resolve()
+// when this method does not find a function.
 func (n *UnresolvedName) ResolveFunction(
 	searchPath sessiondata.SearchPath,
 ) (*FunctionDefinition, error) {
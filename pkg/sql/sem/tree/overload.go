@@ -51,6 +51,22 @@ type Overload struct {
 	WindowFunc    func([]types.T, *EvalContext) WindowFunc
 	Fn            func(*EvalContext, Datums) (Datum, error)
 	Generator     GeneratorFactory
+
+	// SQLFunction is set for overloads of user-defined functions written in
+	// SQL. Fn or Generator evaluates the function in this case too, but the
+	// optimizer can use the definition to inline the function.
+	SQLFunction *SQLFunction
+}
+
+// SQLFunction is the definition of an overload of a user-defined function
+// written in SQL.
+type SQLFunction struct {
+	// Body is the SELECT statement that computes the result of the function.
+	// References to the arguments of the function are placeholders cast to the
+	// type of the argument, e.g. $1::INT8.
+	Body *Select
+	// Volatility is the volatility declared by CREATE FUNCTION.
+	Volatility FunctionVolatility
 }
 
 // params implements the overloadImpl interface.
@@ -769,6 +785,11 @@ func checkReturn(
 		p := o.params()
 		for _, i := range s.constIdxs {
 			des := p.GetAt(i)
+			if des == types.AnyEnum {
+				// Constants that are compared with a value of an ENUM type are
+				// typed as that ENUM type.
+				des = resolvedEnumType(s)
+			}
 			typ, err := s.exprs[i].TypeCheck(ctx, des)
 			if err != nil {
 				return s.typedExprs, nil, true, errors.Wrap(err, "error type checking constant value")
@@ -795,6 +816,20 @@ func checkReturn(
 	}
 }
 
+// resolvedEnumType returns the type of the first resolved expression with an
+// ENUM type, or the ENUM wildcard if there is no such expression.
+func resolvedEnumType(s typeCheckOverloadState) types.T {
+	for _, i := range s.resolvableIdxs {
+		if s.typedExprs[i] == nil {
+			continue
+		}
+		if typ, ok := s.typedExprs[i].ResolvedType().(types.TEnum); ok {
+			return typ
+		}
+	}
+	return types.AnyEnum
+}
+
 func formatCandidates(prefix string, candidates []overloadImpl) string {
 	var buf bytes.Buffer
 	for _, candidate := range candidates {
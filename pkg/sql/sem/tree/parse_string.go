@@ -94,9 +94,20 @@ func parseStringAs(t types.T, s string, ctx ParseTimeContext) (Datum, error) {
 		return ParseDTimestamp(ctx, s, time.Microsecond)
 	case types.TimestampTZ:
 		return ParseDTimestampTZ(ctx, s, time.Microsecond)
+	case types.TSQuery:
+		return ParseDTSQuery(s)
+	case types.TSVector:
+		return ParseDTSVector(s)
 	case types.UUID:
 		return ParseDUuidFromString(s)
 	default:
+		if t, ok := t.(types.TEnum); ok {
+			if t.IsAmbiguous() {
+				// The members of the AnyEnum wildcard are not known.
+				return nil, makeParseError(s, t, nil)
+			}
+			return NewDEnumFromLogicalRep(t, s)
+		}
 		return nil, nil
 	}
 }
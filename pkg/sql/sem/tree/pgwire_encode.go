@@ -41,6 +41,8 @@ func (d *DTuple) pgwireFormat(ctx *FmtCtx) {
 			pgwireFormatStringInTuple(ctx.Buffer, string(*dv))
 		case *DCollatedString:
 			pgwireFormatStringInTuple(ctx.Buffer, dv.Contents)
+		case *DEnum:
+			pgwireFormatStringInTuple(ctx.Buffer, dv.LogicalRep)
 			// Bytes cannot use the default case because they will be incorrectly
 			// double escaped.
 		case *DBytes:
@@ -101,6 +103,8 @@ func (d *DArray) pgwireFormat(ctx *FmtCtx) {
 			pgwireFormatStringInArray(ctx.Buffer, string(*dv))
 		case *DCollatedString:
 			pgwireFormatStringInArray(ctx.Buffer, dv.Contents)
+		case *DEnum:
+			pgwireFormatStringInArray(ctx.Buffer, dv.LogicalRep)
 			// Bytes cannot use the default case because they will be incorrectly
 			// double escaped.
 		case *DBytes:
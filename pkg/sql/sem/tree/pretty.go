@@ -484,9 +484,26 @@ func (node *Select) docTable(p *PrettyCfg) []pretty.RLTableRow {
 	}
 	items = append(items, node.OrderBy.docRow(p))
 	items = append(items, node.Limit.docTable(p)...)
+	items = append(items, node.Locking.docTable(p)...)
 	return items
 }
 
+func (node *LockingClause) doc(p *PrettyCfg) pretty.Doc {
+	return p.rlTable(node.docTable(p)...)
+}
+
+func (node *LockingClause) docTable(p *PrettyCfg) []pretty.RLTableRow {
+	items := make([]pretty.RLTableRow, len(*node))
+	for i, n := range *node {
+		items[i] = p.row("", p.Doc(n))
+	}
+	return items
+}
+
+func (node *LockingItem) doc(p *PrettyCfg) pretty.Doc {
+	return p.docAsString(node)
+}
+
 func (node *SelectClause) doc(p *PrettyCfg) pretty.Doc {
 	return p.rlTable(node.docTable(p)...)
 }
@@ -565,7 +582,11 @@ func (node *With) docRow(p *PrettyCfg) pretty.RLTableRow {
 			pretty.Bracket("AS (", p.Doc(cte.Stmt), ")"),
 		)
 	}
-	return p.row("WITH", pretty.Join(",", d...))
+	kw := "WITH"
+	if node.Recursive {
+		kw = "WITH RECURSIVE"
+	}
+	return p.row(kw, pretty.Join(",", d...))
 }
 
 func (node *Subquery) doc(p *PrettyCfg) pretty.Doc {
@@ -623,6 +644,12 @@ func (node *FuncExpr) doc(p *PrettyCfg) pretty.Doc {
 	} else {
 		d = pretty.Concat(d, pretty.Text("()"))
 	}
+	if len(node.WithinGroup) > 0 {
+		d = pretty.Fold(pretty.ConcatSpace,
+			d,
+			pretty.Text("WITHIN GROUP"),
+			pretty.Bracket("(", p.Doc(&node.WithinGroup), ")"))
+	}
 	if node.Filter != nil {
 		d = pretty.Fold(pretty.ConcatSpace,
 			d,
@@ -796,10 +823,16 @@ func (node *Insert) doc(p *PrettyCfg) pretty.Doc {
 
 	if node.OnConflict != nil && !node.OnConflict.IsUpsertAlias() {
 		cond := pretty.Nil
+		if node.OnConflict.Constraint != "" {
+			cond = p.nestUnder(pretty.Text("ON CONSTRAINT"), p.Doc(&node.OnConflict.Constraint))
+		}
 		if len(node.OnConflict.Columns) > 0 {
 			cond = pretty.Bracket("(", p.Doc(&node.OnConflict.Columns), ")")
 		}
 		items = append(items, p.row("ON CONFLICT", cond))
+		if node.OnConflict.ArbiterPredicate != nil {
+			items = append(items, p.row("WHERE", p.Doc(node.OnConflict.ArbiterPredicate)))
+		}
 
 		if node.OnConflict.DoNothing {
 			items = append(items, p.row("DO", pretty.Text("NOTHING")))
@@ -942,7 +975,12 @@ func (node *Update) doc(p *PrettyCfg) pretty.Doc {
 	items = append(items,
 		node.With.docRow(p),
 		p.row("UPDATE", p.Doc(node.Table)),
-		p.row("SET", p.Doc(&node.Exprs)),
+		p.row("SET", p.Doc(&node.Exprs)))
+	if len(node.From) > 0 {
+		items = append(items,
+			p.row("FROM", node.From.doc(p)))
+	}
+	items = append(items,
 		node.Where.docRow(p),
 		node.OrderBy.docRow(p))
 	items = append(items, node.Limit.docTable(p)...)
@@ -1016,6 +1054,9 @@ func (node *UpdateExpr) doc(p *PrettyCfg) pretty.Doc {
 
 func (node *CreateTable) doc(p *PrettyCfg) pretty.Doc {
 	title := "CREATE TABLE "
+	if node.Temporary {
+		title = "CREATE TEMPORARY TABLE "
+	}
 	if node.IfNotExists {
 		title += "IF NOT EXISTS "
 	}
@@ -1054,8 +1095,14 @@ func (node *CreateTable) doc(p *PrettyCfg) pretty.Doc {
 }
 
 func (node *CreateView) doc(p *PrettyCfg) pretty.Doc {
+	title := "CREATE VIEW"
+	if node.Temporary {
+		title = "CREATE TEMPORARY VIEW"
+	} else if node.Materialized {
+		title = "CREATE MATERIALIZED VIEW"
+	}
 	d := pretty.ConcatSpace(
-		pretty.Text("CREATE VIEW"),
+		pretty.Text(title),
 		p.Doc(&node.Name),
 	)
 	if len(node.ColumnNames) > 0 {
@@ -1271,6 +1318,9 @@ func (node *CreateIndex) doc(p *PrettyCfg) pretty.Doc {
 	if node.PartitionBy != nil {
 		docs = append(docs, p.Doc(node.PartitionBy))
 	}
+	if node.Predicate != nil {
+		docs = append(docs, p.nestUnder(pretty.Text("WHERE"), p.Doc(node.Predicate)))
+	}
 	return pretty.Group(pretty.Stack(docs...))
 }
 
@@ -1363,6 +1413,9 @@ func (node *ColumnTableDef) doc(p *PrettyCfg) pretty.Doc {
 		if ref := p.Doc(&node.References.Actions); ref != pretty.Nil {
 			d = p.nestUnder(d, ref)
 		}
+		if node.References.Deferrability != NotDeferrable {
+			d = pretty.ConcatSpace(d, pretty.Text(node.References.Deferrability.String()))
+		}
 		docs = append(docs, d)
 	}
 	if node.IsComputed() {
@@ -45,6 +45,7 @@ type Select struct {
 	Select  SelectStatement
 	OrderBy OrderBy
 	Limit   *Limit
+	Locking LockingClause
 }
 
 // Format implements the NodeFormatter interface.
@@ -59,6 +60,10 @@ func (node *Select) Format(ctx *FmtCtx) {
 		ctx.WriteByte(' ')
 		ctx.FormatNode(node.Limit)
 	}
+	if len(node.Locking) > 0 {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(&node.Locking)
+	}
 }
 
 // ParenSelect represents a parenthesized SELECT/UNION/VALUES statement.
@@ -633,6 +638,154 @@ func (node *Limit) Format(ctx *FmtCtx) {
 	}
 }
 
+// LockingClause represents a locking clause, like FOR UPDATE.
+type LockingClause []*LockingItem
+
+// Format implements the NodeFormatter interface.
+func (node *LockingClause) Format(ctx *FmtCtx) {
+	for i, n := range *node {
+		if i > 0 {
+			ctx.WriteByte(' ')
+		}
+		ctx.FormatNode(n)
+	}
+}
+
+// Strength returns the strongest locking strength of the clause, and the
+// wait policy of the items with that strength, for the given table or alias.
+// NOWAIT takes precedence over SKIP LOCKED, which takes precedence over
+// waiting.
+func (node LockingClause) Strength(
+	tableName Name,
+) (strength LockingStrength, waitPolicy LockingWaitPolicy) {
+	for _, item := range node {
+		if !item.Targets.contains(tableName) {
+			continue
+		}
+		if item.Strength > strength {
+			strength, waitPolicy = item.Strength, item.WaitPolicy
+		} else if item.Strength == strength && item.WaitPolicy > waitPolicy {
+			waitPolicy = item.WaitPolicy
+		}
+	}
+	return strength, waitPolicy
+}
+
+// ForAlias returns the locking clause which applies to the data source with
+// the given alias, in terms of the data source itself. It is nil if the clause
+// does not apply to the alias.
+func (node LockingClause) ForAlias(alias Name) LockingClause {
+	strength, waitPolicy := node.Strength(alias)
+	if strength == ForNone {
+		return nil
+	}
+	return LockingClause{{Strength: strength, WaitPolicy: waitPolicy}}
+}
+
+// LockingItem represents a single locking item in a locking clause.
+type LockingItem struct {
+	Strength LockingStrength
+	// Targets lists the tables the item applies to. If empty, the item applies
+	// to all the tables in the FROM clause.
+	Targets    TableNames
+	WaitPolicy LockingWaitPolicy
+}
+
+// Format implements the NodeFormatter interface.
+func (node *LockingItem) Format(ctx *FmtCtx) {
+	ctx.FormatNode(node.Strength)
+	if len(node.Targets) > 0 {
+		ctx.WriteString(" OF ")
+		ctx.FormatNode(&node.Targets)
+	}
+	ctx.FormatNode(node.WaitPolicy)
+}
+
+// contains returns whether the locking targets include the given table or
+// alias. An empty list of targets includes all the tables.
+func (t TableNames) contains(tableName Name) bool {
+	if len(t) == 0 {
+		return true
+	}
+	for i := range t {
+		if t[i].TableName == tableName {
+			return true
+		}
+	}
+	return false
+}
+
+// LockingStrength represents the row-level locking strength of a locking
+// item. The strengths are ordered from weakest to strongest.
+type LockingStrength byte
+
+// The ordering of the variants is important, because the highest numerical
+// value takes precedence when row-level locking is specified multiple ways.
+const (
+	// ForNone represents the default - no row-level locking.
+	ForNone LockingStrength = iota
+	// ForKeyShare represents FOR KEY SHARE.
+	ForKeyShare
+	// ForShare represents FOR SHARE.
+	ForShare
+	// ForNoKeyUpdate represents FOR NO KEY UPDATE.
+	ForNoKeyUpdate
+	// ForUpdate represents FOR UPDATE.
+	ForUpdate
+)
+
+var lockingStrengthName = [...]string{
+	ForNone:        "",
+	ForKeyShare:    "FOR KEY SHARE",
+	ForShare:       "FOR SHARE",
+	ForNoKeyUpdate: "FOR NO KEY UPDATE",
+	ForUpdate:      "FOR UPDATE",
+}
+
+func (s LockingStrength) String() string {
+	return lockingStrengthName[s]
+}
+
+// Format implements the NodeFormatter interface.
+func (s LockingStrength) Format(ctx *FmtCtx) {
+	ctx.WriteString(s.String())
+}
+
+// LockingWaitPolicy represents the policy a locking item has towards the rows
+// that are locked by other transactions.
+type LockingWaitPolicy byte
+
+// The ordering of the variants is important, because the highest numerical
+// value takes precedence when row-level locking is specified multiple ways.
+const (
+	// LockWaitBlock represents the default - wait for the lock to become
+	// available.
+	LockWaitBlock LockingWaitPolicy = iota
+	// LockWaitSkip represents SKIP LOCKED - skip rows that can't be locked.
+	LockWaitSkip
+	// LockWaitError represents NOWAIT - raise an error if a row cannot be
+	// locked.
+	LockWaitError
+)
+
+var lockingWaitPolicyName = [...]string{
+	LockWaitBlock: "",
+	LockWaitSkip:  "SKIP LOCKED",
+	LockWaitError: "NOWAIT",
+}
+
+func (p LockingWaitPolicy) String() string {
+	return lockingWaitPolicyName[p]
+}
+
+// Format implements the NodeFormatter interface.
+func (p LockingWaitPolicy) Format(ctx *FmtCtx) {
+	if p != LockWaitBlock {
+		ctx.WriteByte(' ')
+		ctx.WriteString(p.String())
+	}
+}
+
 // RowsFromExpr represents a ROWS FROM(...) expression.
 type RowsFromExpr struct {
 	Items Exprs
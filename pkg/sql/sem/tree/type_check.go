@@ -43,6 +43,14 @@ type SemaContext struct {
 	// Location references the *Location on the current Session.
 	Location **time.Location
 
+	// TypeResolver is used to resolve the names of user-defined types. If it
+	// is nil, user-defined types cannot be referenced.
+	TypeResolver TypeReferenceResolver
+
+	// FunctionResolver is used to resolve the names of user-defined
+	// functions. If it is nil, user-defined functions cannot be called.
+	FunctionResolver FunctionReferenceResolver
+
 	// SearchPath indicates where to search for unqualified function
 	// names. The path elements must be normalized via Name.Normalize()
 	// already.
@@ -66,6 +74,32 @@ type SemaContext struct {
 	Properties SemaProperties
 }
 
+// TypeReferenceResolver resolves the names of user-defined types.
+type TypeReferenceResolver interface {
+	// ResolveTypeReference returns the type with the given name.
+	ResolveTypeReference(name string) (types.T, error)
+}
+
+// ResolveColumnType resolves the user-defined types referenced by the given
+// column or cast target type, in place.
+func ResolveColumnType(ctx *SemaContext, t coltypes.CastTargetType) error {
+	switch ct := t.(type) {
+	case *coltypes.TUserDefined:
+		if ctx == nil || ctx.TypeResolver == nil {
+			return pgerror.NewErrorf(pgerror.CodeUndefinedObjectError,
+				"type %q does not exist", ct.Name)
+		}
+		typ, err := ctx.TypeResolver.ResolveTypeReference(ct.Name)
+		if err != nil {
+			return err
+		}
+		ct.Resolved = typ
+	case *coltypes.TArray:
+		return ResolveColumnType(ctx, ct.ParamType)
+	}
+	return nil
+}
+
 // SemaProperties is a holder for required and derived properties
 // during semantic analysis. It provides scoping semantics via its
 // Restore() method, see below.
@@ -424,6 +458,9 @@ func isCastDeepValid(castFrom, castTo types.T) bool {
 
 // TypeCheck implements the Expr interface.
 func (expr *CastExpr) TypeCheck(ctx *SemaContext, _ types.T) (TypedExpr, error) {
+	if err := ResolveColumnType(ctx, expr.Type); err != nil {
+		return nil, err
+	}
 	returnType := expr.castType()
 
 	// The desired type provided to a CastExpr is ignored. Instead,
@@ -441,9 +478,12 @@ func (expr *CastExpr) TypeCheck(ctx *SemaContext, _ types.T) (TypedExpr, error)
 			// If the type doesn't have any possible parameters (like length,
 			// precision), the CastExpr becomes a no-op and can be elided.
 			switch expr.Type.(type) {
-			case *coltypes.TBool, *coltypes.TDate, *coltypes.TTime, *coltypes.TTimestamp, *coltypes.TTimestampTZ,
-				*coltypes.TInterval, *coltypes.TBytes:
+			case *coltypes.TBool, *coltypes.TDate, *coltypes.TInterval, *coltypes.TBytes:
 				return expr.Expr.TypeCheck(ctx, returnType)
+			case *coltypes.TTime, *coltypes.TTimestamp, *coltypes.TTimestampTZ:
+				if _, _, ok := coltypes.TimePrecision(expr.Type); !ok {
+					return expr.Expr.TypeCheck(ctx, returnType)
+				}
 			}
 		}
 	case ctx.isUnresolvedPlaceholder(expr.Expr):
@@ -503,6 +543,9 @@ func (expr *IndirectionExpr) TypeCheck(ctx *SemaContext, desired types.T) (Typed
 
 // TypeCheck implements the Expr interface.
 func (expr *AnnotateTypeExpr) TypeCheck(ctx *SemaContext, desired types.T) (TypedExpr, error) {
+	if err := ResolveColumnType(ctx, expr.Type); err != nil {
+		return nil, err
+	}
 	annotType := expr.annotationType()
 	subExpr, err := typeCheckAndRequire(ctx, expr.Expr, annotType,
 		fmt.Sprintf("type annotation for %v as %s, found", expr.Expr, annotType))
@@ -658,13 +701,14 @@ func (expr *ComparisonExpr) TypeCheck(ctx *SemaContext, desired types.T) (TypedE
 }
 
 var (
-	errOrderByIndexInWindow = pgerror.NewError(pgerror.CodeFeatureNotSupportedError, "ORDER BY INDEX in window definition is not supported")
-	errStarNotAllowed       = pgerror.NewError(pgerror.CodeSyntaxError, "cannot use \"*\" in this context")
-	errInvalidDefaultUsage  = pgerror.NewError(pgerror.CodeSyntaxError, "DEFAULT can only appear in a VALUES list within INSERT or on the right side of a SET")
-	errInvalidMaxUsage      = pgerror.NewError(pgerror.CodeSyntaxError, "MAXVALUE can only appear within a range partition expression")
-	errInvalidMinUsage      = pgerror.NewError(pgerror.CodeSyntaxError, "MINVALUE can only appear within a range partition expression")
-	errPrivateFunction      = pgerror.NewError(pgerror.CodeFeatureNotSupportedError, "function reserved for internal use")
-	errInsufficientPriv     = pgerror.NewError(pgerror.CodeInsufficientPrivilegeError, "insufficient privilege")
+	errOrderByIndexInWindow      = pgerror.NewError(pgerror.CodeFeatureNotSupportedError, "ORDER BY INDEX in window definition is not supported")
+	errOrderByIndexInWithinGroup = pgerror.NewError(pgerror.CodeFeatureNotSupportedError, "ORDER BY INDEX in WITHIN GROUP is not supported")
+	errStarNotAllowed            = pgerror.NewError(pgerror.CodeSyntaxError, "cannot use \"*\" in this context")
+	errInvalidDefaultUsage       = pgerror.NewError(pgerror.CodeSyntaxError, "DEFAULT can only appear in a VALUES list within INSERT or on the right side of a SET")
+	errInvalidMaxUsage           = pgerror.NewError(pgerror.CodeSyntaxError, "MAXVALUE can only appear within a range partition expression")
+	errInvalidMinUsage           = pgerror.NewError(pgerror.CodeSyntaxError, "MINVALUE can only appear within a range partition expression")
+	errPrivateFunction           = pgerror.NewError(pgerror.CodeFeatureNotSupportedError, "function reserved for internal use")
+	errInsufficientPriv          = pgerror.NewError(pgerror.CodeInsufficientPrivilegeError, "insufficient privilege")
 )
 
 // NewAggInAggError creates an error for the case when an aggregate function is
@@ -768,10 +812,12 @@ func (sc *SemaContext) checkFunctionUsage(expr *FuncExpr, def *FunctionDefinitio
 // TypeCheck implements the Expr interface.
 func (expr *FuncExpr) TypeCheck(ctx *SemaContext, desired types.T) (TypedExpr, error) {
 	var searchPath sessiondata.SearchPath
+	var resolver FunctionReferenceResolver
 	if ctx != nil {
 		searchPath = ctx.SearchPath
+		resolver = ctx.FunctionResolver
 	}
-	def, err := expr.Func.Resolve(searchPath)
+	def, err := expr.Func.Resolve(searchPath, resolver)
 	if err != nil {
 		return nil, err
 	}
@@ -779,6 +825,9 @@ func (expr *FuncExpr) TypeCheck(ctx *SemaContext, desired types.T) (TypedExpr, e
 	if err := ctx.checkFunctionUsage(expr, def); err != nil {
 		return nil, errors.Wrapf(err, "%s()", def.Name)
 	}
+	if err := expr.checkWithinGroup(def); err != nil {
+		return nil, err
+	}
 	if ctx != nil {
 		// We'll need to remember we are in a function application to
 		// generate suitable errors in checkFunctionUsage().  We cannot
@@ -792,7 +841,7 @@ func (expr *FuncExpr) TypeCheck(ctx *SemaContext, desired types.T) (TypedExpr, e
 		ctx.Properties.Derived.inFuncExpr = true
 	}
 
-	typedSubExprs, fns, err := typeCheckOverloadedExprs(ctx, desired, def.Definition, false, expr.Exprs...)
+	typedSubExprs, fns, err := typeCheckOverloadedExprs(ctx, desired, def.Definition, false, expr.AggregateArgs()...)
 	if err != nil {
 		return nil, errors.Wrapf(err, "%s()", def.Name)
 	}
@@ -887,7 +936,12 @@ func (expr *FuncExpr) TypeCheck(ctx *SemaContext, desired types.T) (TypedExpr, e
 		expr.Filter = typedFilter
 	}
 
-	for i, subExpr := range typedSubExprs {
+	typedArgs := typedSubExprs
+	if len(expr.WithinGroup) > 0 {
+		expr.WithinGroup[0].Expr = typedArgs[0]
+		typedArgs = typedArgs[1:]
+	}
+	for i, subExpr := range typedArgs {
 		expr.Exprs[i] = subExpr
 	}
 	expr.fn = overloadImpl
@@ -908,6 +962,42 @@ func (expr *FuncExpr) TypeCheck(ctx *SemaContext, desired types.T) (TypedExpr, e
 	return expr, nil
 }
 
+// checkWithinGroup checks that a WITHIN GROUP clause is used if and only if
+// the function is an ordered-set aggregate, and that it is well-formed.
+func (expr *FuncExpr) checkWithinGroup(def *FunctionDefinition) error {
+	if len(expr.WithinGroup) == 0 {
+		if def.OrderedSet {
+			return pgerror.NewErrorf(pgerror.CodeWrongObjectTypeError,
+				"WITHIN GROUP is required for ordered-set aggregate %s", &expr.Func)
+		}
+		return nil
+	}
+	if !def.OrderedSet {
+		return pgerror.NewErrorf(pgerror.CodeWrongObjectTypeError,
+			"%s is not an ordered-set aggregate, so it cannot have WITHIN GROUP", &expr.Func)
+	}
+	if expr.IsWindowFunctionApplication() {
+		return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+			"OVER is not supported for ordered-set aggregate %s", &expr.Func)
+	}
+	if expr.Type == DistinctFuncType {
+		return pgerror.NewError(pgerror.CodeSyntaxError, "cannot use DISTINCT with WITHIN GROUP")
+	}
+	if len(expr.WithinGroup) != 1 {
+		return pgerror.NewErrorf(pgerror.CodeUndefinedFunctionError,
+			"ordered-set aggregate %s takes a single ordering expression", &expr.Func)
+	}
+	order := expr.WithinGroup[0]
+	if order.OrderType != OrderByColumn {
+		return errOrderByIndexInWithinGroup
+	}
+	if order.Direction == Descending {
+		return pgerror.Unimplemented("within group desc",
+			"descending order in WITHIN GROUP is not supported")
+	}
+	return nil
+}
+
 // TypeCheck checks that offsets of the window frame (if present) are of the
 // appropriate type.
 func (f *WindowFrame) TypeCheck(ctx *SemaContext, windowDef *WindowDef) error {
@@ -1357,6 +1447,10 @@ func (d *DString) TypeCheck(_ *SemaContext, _ types.T) (TypedExpr, error) { retu
 // identity function for Datum.
 func (d *DCollatedString) TypeCheck(_ *SemaContext, _ types.T) (TypedExpr, error) { return d, nil }
 
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DEnum) TypeCheck(_ *SemaContext, _ types.T) (TypedExpr, error) { return d, nil }
+
 // TypeCheck implements the Expr interface. It is implemented as an idempotent
 // identity function for Datum.
 func (d *DBytes) TypeCheck(_ *SemaContext, _ types.T) (TypedExpr, error) { return d, nil }
@@ -1393,6 +1487,14 @@ func (d *DInterval) TypeCheck(_ *SemaContext, _ types.T) (TypedExpr, error) { re
 // identity function for Datum.
 func (d *DJSON) TypeCheck(_ *SemaContext, _ types.T) (TypedExpr, error) { return d, nil }
 
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DTSVector) TypeCheck(_ *SemaContext, _ types.T) (TypedExpr, error) { return d, nil }
+
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DTSQuery) TypeCheck(_ *SemaContext, _ types.T) (TypedExpr, error) { return d, nil }
+
 // TypeCheck implements the Expr interface. It is implemented as an idempotent
 // identity function for Datum.
 func (d *DTuple) TypeCheck(_ *SemaContext, _ types.T) (TypedExpr, error) { return d, nil }
@@ -1695,9 +1797,10 @@ func typeCheckComparisonOp(
 	// Throw a typing error if overload resolution found either no compatible candidates
 	// or if it found an ambiguity.
 	collationMismatch := leftReturn.FamilyEqual(types.FamCollatedString) && !leftReturn.Equivalent(rightReturn)
-	if len(fns) != 1 || collationMismatch {
+	enumMismatch := leftReturn.FamilyEqual(types.AnyEnum) && !leftReturn.Equivalent(rightReturn)
+	if len(fns) != 1 || collationMismatch || enumMismatch {
 		sig := fmt.Sprintf(compSignatureFmt, leftReturn, op, rightReturn)
-		if len(fns) == 0 || collationMismatch {
+		if len(fns) == 0 || collationMismatch || enumMismatch {
 			return nil, nil, nil, false,
 				pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError, unsupportedCompErrFmt, sig)
 		}
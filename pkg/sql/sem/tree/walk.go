@@ -307,6 +307,15 @@ func (expr *FuncExpr) Walk(v Visitor) Expr {
 		}
 		ret.Exprs = exprs
 	}
+	if len(expr.WithinGroup) > 0 {
+		order, changed := walkOrderBy(v, expr.WithinGroup)
+		if changed {
+			if ret == expr {
+				ret = expr.copyNode()
+			}
+			ret.WithinGroup = order
+		}
+	}
 	if expr.WindowDef != nil {
 		windowDef, changed := walkWindowDef(v, expr.WindowDef)
 		if changed {
@@ -637,6 +646,12 @@ func (expr *DInterval) Walk(_ Visitor) Expr { return expr }
 // Walk implements the Expr interface.
 func (expr *DJSON) Walk(_ Visitor) Expr { return expr }
 
+// Walk implements the Expr interface.
+func (expr *DTSVector) Walk(_ Visitor) Expr { return expr }
+
+// Walk implements the Expr interface.
+func (expr *DTSQuery) Walk(_ Visitor) Expr { return expr }
+
 // Walk implements the Expr interface.
 func (expr *DUuid) Walk(_ Visitor) Expr { return expr }
 
@@ -652,6 +667,9 @@ func (expr *DString) Walk(_ Visitor) Expr { return expr }
 // Walk implements the Expr interface.
 func (expr *DCollatedString) Walk(_ Visitor) Expr { return expr }
 
+// Walk implements the Expr interface.
+func (expr *DEnum) Walk(_ Visitor) Expr { return expr }
+
 // Walk implements the Expr interface.
 func (expr *DTimestamp) Walk(_ Visitor) Expr { return expr }
 
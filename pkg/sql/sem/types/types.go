@@ -80,11 +80,18 @@ var (
 	UUID T = tUUID{}
 	// INet is the type of a DIPAddr. Can be compared with ==.
 	INet T = tINet{}
+	// TSVector is the type of a DTSVector. Can be compared with ==.
+	TSVector T = tTSVector{}
+	// TSQuery is the type of a DTSQuery. Can be compared with ==.
+	TSQuery T = tTSQuery{}
 	// AnyArray is the type of a DArray with a wildcard parameterized type.
 	// Can be compared with ==.
 	AnyArray T = TArray{Any}
 	// Any can be any type. Can be compared with ==.
 	Any T = tAny{}
+	// AnyEnum is the type of a DEnum of any user-defined ENUM type. Can be
+	// compared with ==.
+	AnyEnum T = TEnum{}
 
 	// AnyNonArray contains all non-array types.
 	AnyNonArray = []T{
@@ -318,6 +325,30 @@ func (tINet) Oid() oid.Oid             { return oid.T_inet }
 func (tINet) SQLName() string          { return "inet" }
 func (tINet) IsAmbiguous() bool        { return false }
 
+type tTSVector struct{}
+
+func (tTSVector) String() string { return "tsvector" }
+func (tTSVector) Equivalent(other T) bool {
+	return UnwrapType(other) == TSVector || other == Any
+}
+
+func (tTSVector) FamilyEqual(other T) bool { return UnwrapType(other) == TSVector }
+func (tTSVector) Oid() oid.Oid             { return oid.T_tsvector }
+func (tTSVector) SQLName() string          { return "tsvector" }
+func (tTSVector) IsAmbiguous() bool        { return false }
+
+type tTSQuery struct{}
+
+func (tTSQuery) String() string { return "tsquery" }
+func (tTSQuery) Equivalent(other T) bool {
+	return UnwrapType(other) == TSQuery || other == Any
+}
+
+func (tTSQuery) FamilyEqual(other T) bool { return UnwrapType(other) == TSQuery }
+func (tTSQuery) Oid() oid.Oid             { return oid.T_tsquery }
+func (tTSQuery) SQLName() string          { return "tsquery" }
+func (tTSQuery) IsAmbiguous() bool        { return false }
+
 // TTuple is the type of a DTuple.
 type TTuple struct {
 	Types  []T
@@ -393,6 +424,100 @@ func (t TTuple) IsAmbiguous() bool {
 	return len(t.Types) == 0
 }
 
+// TEnum is the type of a DEnum, a value of a user-defined ENUM type.
+type TEnum struct {
+	// ID is the ID of the descriptor of the type. It is zero for the AnyEnum
+	// wildcard.
+	ID uint32
+	// Name is the name of the type.
+	Name string
+	// Members holds the members of the type. It is a pointer so that TEnum
+	// remains comparable with ==.
+	Members *EnumMembers
+}
+
+// EnumMembers holds the members of an ENUM type, in declaration order.
+type EnumMembers struct {
+	// PhysicalReps are the encodings of the members in keys and values. They
+	// sort in declaration order.
+	PhysicalReps [][]byte
+	// LogicalReps are the labels of the members.
+	LogicalReps []string
+}
+
+// enumOidOffset is added to the ID of an ENUM type to form its OID, so that
+// the OIDs of user-defined types do not collide with the OIDs of the builtin
+// types.
+const enumOidOffset = 100000
+
+// String implements the fmt.Stringer interface.
+func (t TEnum) String() string {
+	if t.ID == 0 {
+		return "anyenum"
+	}
+	return t.Name
+}
+
+// Equivalent implements the T interface.
+func (t TEnum) Equivalent(other T) bool {
+	if other == Any {
+		return true
+	}
+	u, ok := UnwrapType(other).(TEnum)
+	if ok {
+		return t.ID == 0 || u.ID == 0 || t.ID == u.ID
+	}
+	return false
+}
+
+// FamilyEqual implements the T interface.
+func (TEnum) FamilyEqual(other T) bool {
+	_, ok := UnwrapType(other).(TEnum)
+	return ok
+}
+
+// Oid implements the T interface.
+func (t TEnum) Oid() oid.Oid {
+	if t.ID == 0 {
+		return oid.T_anyenum
+	}
+	return oid.Oid(t.ID) + enumOidOffset
+}
+
+// SQLName implements the T interface.
+func (t TEnum) SQLName() string { return t.String() }
+
+// IsAmbiguous implements the T interface.
+func (t TEnum) IsAmbiguous() bool { return t.ID == 0 }
+
+// LogicalRep returns the label of the member of the type with the given
+// physical representation.
+func (t TEnum) LogicalRep(physicalRep []byte) (string, bool) {
+	if t.Members == nil {
+		return "", false
+	}
+	for i, rep := range t.Members.PhysicalReps {
+		if bytes.Equal(rep, physicalRep) {
+			return t.Members.LogicalReps[i], true
+		}
+	}
+	return "", false
+}
+
+// PhysicalRep returns the physical representation of the member of the type
+// with the given label.
+func (t TEnum) PhysicalRep(logicalRep string) ([]byte, bool) {
+	if t.Members == nil {
+		return nil, false
+	}
+	for i, rep := range t.Members.LogicalReps {
+		if rep == logicalRep {
+			return t.Members.PhysicalReps[i], true
+		}
+	}
+	return nil, false
+}
+
 // PlaceholderIdx is the 0-based index of a placeholder. Placeholder "$1"
 // has PlaceholderIdx=0.
 type PlaceholderIdx uint16
@@ -517,7 +642,7 @@ func IsStringType(t T) bool {
 // can be used in TArray.
 func IsValidArrayElementType(t T) bool {
 	switch t {
-	case JSON:
+	case JSON, TSVector, TSQuery:
 		return false
 	default:
 		return true
@@ -382,7 +382,7 @@ func getUsedSequenceNames(defaultExpr tree.TypedExpr) ([]string, error) {
 		func(expr tree.Expr) (err error, recurse bool, newExpr tree.Expr) {
 			switch t := expr.(type) {
 			case *tree.FuncExpr:
-				def, err := t.Func.Resolve(searchPath)
+				def, err := t.Func.Resolve(searchPath, nil /* resolver */)
 				if err != nil {
 					return err, false, expr
 				}
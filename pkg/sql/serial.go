@@ -96,6 +96,11 @@ func (p *planner) processSerialInColumnDef(
 	// The constraint on the name is that an object of this name must not exist already.
 	seqName := tree.NewUnqualifiedTableName(
 		tree.Name(tableName.Table() + "_" + string(d.Name) + "_seq"))
+	if tableName.Schema() != tree.PublicSchema {
+		// The sequences of temporary tables are temporary, and the sequences of
+		// the tables of user-defined schemas belong to the same schema.
+		seqName.TableNamePrefix = tableName.TableNamePrefix
+	}
 
 	// The first step in the search is to prepare the seqName to fill in
 	// the catalog/schema parent. This is what ResolveUncachedDatabase does.
@@ -122,9 +127,17 @@ func (p *planner) processSerialInColumnDef(
 		}
 	}
 
+	// The sequences of user-defined schemas are referred to by their
+	// schema-qualified name, since the schema may not be in the search path.
+	seqRef := seqName.Table()
+	if scName := seqName.Schema(); scName != tree.PublicSchema && !sessiondata.IsTemporarySchemaName(scName) {
+		qualified := tree.MakeTableNameWithSchema("", seqName.SchemaName, seqName.TableName)
+		qualified.ExplicitCatalog = false
+		seqRef = tree.AsString(&qualified)
+	}
 	defaultExpr := &tree.FuncExpr{
 		Func:  tree.WrapFunction("nextval"),
-		Exprs: tree.Exprs{tree.NewStrVal(seqName.Table())},
+		Exprs: tree.Exprs{tree.NewStrVal(seqRef)},
 	}
 
 	seqType := ""
@@ -26,29 +26,58 @@ const DefaultDatabaseName = "defaultdb"
 // PgCatalogName is the name of the pg_catalog system schema.
 const PgCatalogName = "pg_catalog"
 
+// PgTempSchemaName is the alias for temporary schemas across sessions.
+const PgTempSchemaName = "pg_temp"
+
+// IsTemporarySchemaName returns true if the given name is the name of the
+// temporary schema of some session, or the pg_temp alias.
+func IsTemporarySchemaName(name string) bool {
+	return name == PgTempSchemaName || strings.HasPrefix(name, PgTempSchemaName+"_")
+}
+
 // SearchPath represents a list of namespaces to search builtins in.
 // The names must be normalized (as per Name.Normalize) already.
 type SearchPath struct {
-	paths             []string
-	containsPgCatalog bool
+	paths                []string
+	containsPgCatalog    bool
+	containsPgTempSchema bool
+	tempSchemaName       string
 }
 
 // MakeSearchPath returns a new immutable SearchPath struct. The paths slice
 // must not be modified after hand-off to MakeSearchPath.
 func MakeSearchPath(paths []string) SearchPath {
 	containsPgCatalog := false
+	containsPgTempSchema := false
 	for _, e := range paths {
-		if e == PgCatalogName {
+		switch e {
+		case PgCatalogName:
 			containsPgCatalog = true
-			break
+		case PgTempSchemaName:
+			containsPgTempSchema = true
 		}
 	}
 	return SearchPath{
-		paths:             paths,
-		containsPgCatalog: containsPgCatalog,
+		paths:                paths,
+		containsPgCatalog:    containsPgCatalog,
+		containsPgTempSchema: containsPgTempSchema,
 	}
 }
 
+// WithTemporarySchemaName returns a copy of the SearchPath that uses the
+// given name for the temporary schema of the session. An empty name
+// indicates that the session has no temporary schema.
+func (s SearchPath) WithTemporarySchemaName(tempSchemaName string) SearchPath {
+	s.tempSchemaName = tempSchemaName
+	return s
+}
+
+// GetTemporarySchemaName returns the name of the temporary schema of the
+// session, or an empty string if the session has no temporary schema.
+func (s SearchPath) GetTemporarySchemaName() string {
+	return s.tempSchemaName
+}
+
 // Iter returns an iterator through the search path. We must include the
 // implicit pg_catalog at the beginning of the search path, unless it has been
 // explicitly set later by the user.
@@ -57,17 +86,23 @@ func MakeSearchPath(paths []string) SearchPath {
 // searched in the specified order. If pg_catalog is not in the path then it
 // will be searched before searching any of the path items."
 // - https://www.postgresql.org/docs/9.1/static/runtime-config-client.html
+//
+// Likewise, the temporary schema of the session, if it exists, is searched
+// before everything else unless it is explicitly listed in the path using
+// the pg_temp alias.
 func (s SearchPath) Iter() SearchPathIter {
-	if s.containsPgCatalog {
-		return SearchPathIter{paths: s.paths, i: 0}
+	return SearchPathIter{
+		paths:              s.paths,
+		implicitPgCatalog:  !s.containsPgCatalog,
+		implicitTempSchema: s.tempSchemaName != "" && !s.containsPgTempSchema,
+		tempSchemaName:     s.tempSchemaName,
 	}
-	return SearchPathIter{paths: s.paths, i: -1}
 }
 
 // IterWithoutImplicitPGCatalog is the same as Iter, but does not include the
-// implicit pg_catalog.
+// implicit pg_catalog nor the implicit temporary schema.
 func (s SearchPath) IterWithoutImplicitPGCatalog() SearchPathIter {
-	return SearchPathIter{paths: s.paths, i: 0}
+	return SearchPathIter{paths: s.paths, tempSchemaName: s.tempSchemaName}
 }
 
 // GetPathArray returns the underlying path array of this SearchPath. The
@@ -81,11 +116,14 @@ func (s SearchPath) Equals(other *SearchPath) bool {
 	if s.containsPgCatalog != other.containsPgCatalog {
 		return false
 	}
+	if s.tempSchemaName != other.tempSchemaName {
+		return false
+	}
 	if len(s.paths) != len(other.paths) {
 		return false
 	}
 	// Fast path: skip the check if it is the same slice.
-	if &s.paths[0] != &other.paths[0] {
+	if len(s.paths) > 0 && &s.paths[0] != &other.paths[0] {
 		for i := range s.paths {
 			if s.paths[i] != other.paths[i] {
 				return false
@@ -104,19 +142,32 @@ func (s SearchPath) String() string {
 // iterator, and then repeatedly call the Next method in order to iterate over
 // each search path.
 type SearchPathIter struct {
-	paths []string
-	i     int
+	paths              []string
+	implicitPgCatalog  bool
+	implicitTempSchema bool
+	tempSchemaName     string
+	i                  int
 }
 
 // Next returns the next search path, or false if there are no remaining paths.
+// The pg_temp alias is replaced by the name of the temporary schema of the
+// session, if it exists.
 func (iter *SearchPathIter) Next() (path string, ok bool) {
-	if iter.i == -1 {
-		iter.i++
+	if iter.implicitTempSchema {
+		iter.implicitTempSchema = false
+		return iter.tempSchemaName, true
+	}
+	if iter.implicitPgCatalog {
+		iter.implicitPgCatalog = false
 		return PgCatalogName, true
 	}
 	if iter.i < len(iter.paths) {
 		iter.i++
-		return iter.paths[iter.i-1], true
+		path = iter.paths[iter.i-1]
+		if path == PgTempSchemaName && iter.tempSchemaName != "" {
+			path = iter.tempSchemaName
+		}
+		return path, true
 	}
 	return "", false
 }
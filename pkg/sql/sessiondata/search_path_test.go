@@ -61,6 +61,46 @@ func TestImpliedSearchPath(t *testing.T) {
 	}
 }
 
+func TestImpliedSearchPathWithTemporarySchema(t *testing.T) {
+	testCases := []struct {
+		explicitSearchPath                         []string
+		expectedSearchPath                         []string
+		expectedSearchPathWithoutImplicitPgCatalog []string
+	}{
+		{[]string{}, []string{`pg_temp_1`, `pg_catalog`}, []string{}},
+		{[]string{`pg_catalog`}, []string{`pg_temp_1`, `pg_catalog`}, []string{`pg_catalog`}},
+		{[]string{`foobar`}, []string{`pg_temp_1`, `pg_catalog`, `foobar`}, []string{`foobar`}},
+		{[]string{`foobar`, `pg_temp`}, []string{`pg_catalog`, `foobar`, `pg_temp_1`}, []string{`foobar`, `pg_temp_1`}},
+		{[]string{`pg_catalog`, `pg_temp`}, []string{`pg_catalog`, `pg_temp_1`}, []string{`pg_catalog`, `pg_temp_1`}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(strings.Join(tc.explicitSearchPath, ","), func(t *testing.T) {
+			searchPath := MakeSearchPath(tc.explicitSearchPath).WithTemporarySchemaName("pg_temp_1")
+			actualSearchPath := make([]string, 0)
+			iter := searchPath.Iter()
+			for p, ok := iter.Next(); ok; p, ok = iter.Next() {
+				actualSearchPath = append(actualSearchPath, p)
+			}
+			if !reflect.DeepEqual(tc.expectedSearchPath, actualSearchPath) {
+				t.Errorf(`Expected search path to be %#v, but was %#v.`, tc.expectedSearchPath, actualSearchPath)
+			}
+		})
+
+		t.Run(strings.Join(tc.explicitSearchPath, ",")+"/no-pg-catalog", func(t *testing.T) {
+			searchPath := MakeSearchPath(tc.explicitSearchPath).WithTemporarySchemaName("pg_temp_1")
+			actualSearchPath := make([]string, 0)
+			iter := searchPath.IterWithoutImplicitPGCatalog()
+			for p, ok := iter.Next(); ok; p, ok = iter.Next() {
+				actualSearchPath = append(actualSearchPath, p)
+			}
+			if !reflect.DeepEqual(tc.expectedSearchPathWithoutImplicitPgCatalog, actualSearchPath) {
+				t.Errorf(`Expected search path to be %#v, but was %#v.`, tc.expectedSearchPathWithoutImplicitPgCatalog, actualSearchPath)
+			}
+		})
+	}
+}
+
 func TestSearchPathEquals(t *testing.T) {
 	a1 := MakeSearchPath([]string{"x", "y", "z"})
 	a2 := MakeSearchPath([]string{"x", "y", "z"})
@@ -80,4 +120,11 @@ func TestSearchPathEquals(t *testing.T) {
 
 	d := MakeSearchPath([]string{"x"})
 	assert.False(t, a1.Equals(&d))
+
+	e1 := a1.WithTemporarySchemaName("pg_temp_1")
+	e2 := a2.WithTemporarySchemaName("pg_temp_1")
+	e3 := a1.WithTemporarySchemaName("pg_temp_2")
+	assert.True(t, e1.Equals(&e2))
+	assert.False(t, a1.Equals(&e1))
+	assert.False(t, e1.Equals(&e3))
 }
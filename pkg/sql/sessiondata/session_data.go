@@ -88,6 +88,9 @@ type SessionData struct {
 	// DefaultIntSize specifies the size in bits or bytes (preferred)
 	// of how a "naked" INT type should be parsed.
 	DefaultIntSize int
+	// DefaultTextSearchConfig is the name of the text search configuration
+	// used by the text search functions when none is specified.
+	DefaultTextSearchConfig string
 }
 
 // DataConversionConfig contains the parameters that influence
@@ -0,0 +1,64 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+type setConstraintsNode struct {
+	n *tree.SetConstraints
+}
+
+// SetConstraints sets the checking mode of the deferrable constraints for
+// the rest of the transaction.
+// Privileges: None.
+//   Notes: postgres only issues a warning outside of a transaction block.
+func (p *planner) SetConstraints(n *tree.SetConstraints) (planNode, error) {
+	if p.deferredFKChecks() == nil {
+		// Every constraint is checked immediately in an implicit transaction.
+		return newZeroNode(nil /* columns */), nil
+	}
+	return &setConstraintsNode{n: n}, nil
+}
+
+func (n *setConstraintsNode) startExec(params runParams) error {
+	d := params.p.deferredFKChecks()
+	if n.n.Deferred {
+		d.SetMode(row.ConstraintsAllDeferred)
+		return nil
+	}
+	// The checks that were deferred until now are run when the constraints
+	// become immediate.
+	d.SetMode(row.ConstraintsAllImmediate)
+	return d.Run(params.ctx, params.p.txn)
+}
+
+func (*setConstraintsNode) Next(runParams) (bool, error) { return false, nil }
+func (*setConstraintsNode) Values() tree.Datums          { return tree.Datums{} }
+func (*setConstraintsNode) Close(context.Context)        {}
+
+// deferredFKChecks returns the queue of the checks of the deferred foreign
+// key constraints of the transaction, or nil if the constraints cannot be
+// deferred, which is the case in implicit transactions.
+func (p *planner) deferredFKChecks() *row.DeferredFKChecks {
+	if p.EvalContext().TxnImplicit {
+		return nil
+	}
+	return p.extendedEvalCtx.DeferredFKChecks
+}
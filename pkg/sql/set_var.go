@@ -220,17 +220,10 @@ func timeZoneVarGetStringVal(
 	switch v := tree.UnwrapDatum(&evalCtx.EvalContext, d).(type) {
 	case *tree.DString:
 		location := string(*v)
-		loc, err = timeutil.LoadLocation(location)
+		loc, err = tree.TimeZoneNameToLocation(location)
 		if err != nil {
-			var err1 error
-			loc, err1 = timeutil.LoadLocation(strings.ToUpper(location))
-			if err1 != nil {
-				loc, err1 = timeutil.LoadLocation(strings.ToTitle(location))
-				if err1 != nil {
-					return "", wrapSetVarError("timezone", values[0].String(),
-						"cannot find time zone %q: %v", location, err)
-				}
-			}
+			return "", wrapSetVarError("timezone", values[0].String(),
+				"cannot find time zone %q: %v", location, err)
 		}
 
 	case *tree.DInterval:
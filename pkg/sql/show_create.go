@@ -46,14 +46,21 @@ func ShowCreateView(
 	ctx context.Context, tn *tree.Name, desc *sqlbase.TableDescriptor,
 ) (string, error) {
 	f := tree.NewFmtCtxWithBuf(tree.FmtSimple)
-	f.WriteString("CREATE VIEW ")
+	f.WriteString("CREATE ")
+	if desc.IsTemporary() {
+		f.WriteString("TEMPORARY ")
+	}
+	if desc.MaterializedView() {
+		f.WriteString("MATERIALIZED ")
+	}
+	f.WriteString("VIEW ")
 	f.FormatNode(tn)
 	f.WriteString(" (")
-	for i := range desc.Columns {
+	for i, col := range desc.VisibleColumns() {
 		if i > 0 {
 			f.WriteString(", ")
 		}
-		f.FormatNameP(&desc.Columns[i].Name)
+		f.FormatNameP(&col.Name)
 	}
 	f.WriteString(") AS ")
 	f.WriteString(desc.ViewQuery)
@@ -117,6 +124,11 @@ func printForeignKeyConstraint(
 		buf.WriteString(" ON UPDATE ")
 		buf.WriteString(fk.OnUpdate.String())
 	}
+	if fk.InitiallyDeferred {
+		buf.WriteString(" DEFERRABLE INITIALLY DEFERRED")
+	} else if fk.Deferrable {
+		buf.WriteString(" DEFERRABLE")
+	}
 	return nil
 }
 
@@ -126,7 +138,11 @@ func ShowCreateSequence(
 	ctx context.Context, tn *tree.Name, desc *sqlbase.TableDescriptor,
 ) (string, error) {
 	f := tree.NewFmtCtxWithBuf(tree.FmtSimple)
-	f.WriteString("CREATE SEQUENCE ")
+	f.WriteString("CREATE ")
+	if desc.IsTemporary() {
+		f.WriteString("TEMPORARY ")
+	}
+	f.WriteString("SEQUENCE ")
 	f.FormatNode(tn)
 	opts := desc.SequenceOpts
 	f.Printf(" MINVALUE %d", opts.MinValue)
@@ -158,7 +174,11 @@ func ShowCreateTable(
 	a := &sqlbase.DatumAlloc{}
 
 	f := tree.NewFmtCtxWithBuf(tree.FmtSimple)
-	f.WriteString("CREATE TABLE ")
+	f.WriteString("CREATE ")
+	if desc.IsTemporary() {
+		f.WriteString("TEMPORARY ")
+	}
+	f.WriteString("TABLE ")
 	f.FormatNode(tn)
 	f.WriteString(" (")
 	primaryKeyIsOnVisibleColumn := false
@@ -205,6 +225,10 @@ func ShowCreateTable(
 			); err != nil {
 				return "", err
 			}
+			if idx.IsPartial() {
+				f.WriteString(" WHERE ")
+				f.WriteString(*idx.Predicate)
+			}
 		}
 	}
 
@@ -223,6 +247,9 @@ func ShowCreateTable(
 	}
 
 	for _, e := range desc.Checks {
+		if e.IsNonNullConstraint {
+			continue
+		}
 		f.WriteString(",\n\t")
 		if len(e.Name) > 0 {
 			f.WriteString("CONSTRAINT ")
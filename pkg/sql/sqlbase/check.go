@@ -27,6 +27,7 @@ import (
 // CheckHelper validates check constraints on rows, on INSERT and UPDATE.
 type CheckHelper struct {
 	Exprs        []tree.TypedExpr
+	checks       []*TableDescriptor_CheckConstraint
 	cols         []ColumnDescriptor
 	sourceInfo   *DataSourceInfo
 	ivarHelper   *tree.IndexedVarHelper
@@ -57,6 +58,7 @@ func (c *CheckHelper) Init(
 		return nil
 	}
 
+	c.checks = tableDesc.Checks
 	c.cols = tableDesc.Columns
 	c.sourceInfo = NewSourceInfoForSingleTable(
 		*tn, ResultColumnsFromColDescs(tableDesc.Columns),
@@ -141,12 +143,16 @@ func (c *CheckHelper) IndexedVarNodeFormatter(idx int) tree.NodeFormatter {
 func (c *CheckHelper) Check(ctx *tree.EvalContext) error {
 	ctx.PushIVarContainer(c)
 	defer func() { ctx.PopIVarContainer() }()
-	for _, expr := range c.Exprs {
+	for i, expr := range c.Exprs {
 		if d, err := expr.Eval(ctx); err != nil {
 			return err
 		} else if res, err := tree.GetBool(d); err != nil {
 			return err
 		} else if !res && d != tree.DNull {
+			if c.checks[i].IsNonNullConstraint {
+				// The column is being made NOT NULL.
+				return NewNonNullViolationError(c.columnName(c.checks[i].ColumnIDs[0]))
+			}
 			// Failed to satisfy CHECK constraint.
 			return pgerror.NewErrorf(pgerror.CodeCheckViolationError,
 				"failed to satisfy CHECK constraint (%s)", expr)
@@ -154,3 +160,12 @@ func (c *CheckHelper) Check(ctx *tree.EvalContext) error {
 	}
 	return nil
 }
+
+func (c *CheckHelper) columnName(id ColumnID) string {
+	for i := range c.cols {
+		if c.cols[i].ID == id {
+			return c.cols[i].Name
+		}
+	}
+	return ""
+}
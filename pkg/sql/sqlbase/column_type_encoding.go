@@ -27,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/pkg/errors"
 )
@@ -144,6 +145,11 @@ func EncodeTableKey(b []byte, val tree.Datum, dir encoding.Direction) ([]byte, e
 			return encoding.EncodeBytesAscending(b, t.Key), nil
 		}
 		return encoding.EncodeBytesDescending(b, t.Key), nil
+	case *tree.DEnum:
+		if dir == encoding.Ascending {
+			return encoding.EncodeBytesAscending(b, t.PhysicalRep), nil
+		}
+		return encoding.EncodeBytesDescending(b, t.PhysicalRep), nil
 	case *tree.DBitArray:
 		if dir == encoding.Ascending {
 			return encoding.EncodeBitArrayAscending(b, t.BitArray), nil
@@ -240,7 +246,7 @@ func DecodeTableKey(
 			rkey, r, err = encoding.DecodeUnsafeStringDescending(key, nil)
 		}
 		return a.NewDName(tree.DString(r)), rkey, err
-	case types.JSON:
+	case types.JSON, types.TSVector:
 		return tree.DNull, []byte{}, nil
 	case types.Bytes:
 		var r []byte
@@ -332,6 +338,18 @@ func DecodeTableKey(
 				return nil, nil, err
 			}
 			return tree.NewDCollatedString(r, t.Locale, &a.env), rkey, err
+		case types.TEnum:
+			var r []byte
+			if dir == encoding.Ascending {
+				rkey, r, err = encoding.DecodeBytesAscending(key, nil)
+			} else {
+				rkey, r, err = encoding.DecodeBytesDescending(key, nil)
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+			d, err := tree.NewDEnumFromPhysicalRep(t, r)
+			return d, rkey, err
 		}
 		return nil, nil, errors.Errorf("TODO(pmattis): decoded index key: %s", valType)
 	}
@@ -389,6 +407,10 @@ func EncodeTableValue(
 			return nil, err
 		}
 		return encoding.EncodeJSONValue(appendTo, uint32(colID), encoded), nil
+	case *tree.DTSVector:
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), t.TSVector.Encode(scratch)), nil
+	case *tree.DTSQuery:
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), t.TSQuery.Encode(scratch)), nil
 	case *tree.DArray:
 		a, err := encodeArray(t, scratch)
 		if err != nil {
@@ -399,6 +421,8 @@ func EncodeTableValue(
 		return encodeTuple(t, appendTo, uint32(colID), scratch)
 	case *tree.DCollatedString:
 		return encoding.EncodeBytesValue(appendTo, uint32(colID), []byte(t.Contents)), nil
+	case *tree.DEnum:
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), t.PhysicalRep), nil
 	case *tree.DOid:
 		return encoding.EncodeIntValue(appendTo, uint32(colID), int64(t.DInt)), nil
 	}
@@ -516,6 +540,26 @@ func decodeUntaggedDatum(a *DatumAlloc, t types.T, buf []byte) (tree.Datum, []by
 			return nil, b, err
 		}
 		return a.NewDJSON(tree.DJSON{JSON: j}), b, nil
+	case types.TSVector:
+		b, data, err := encoding.DecodeUntaggedBytesValue(buf)
+		if err != nil {
+			return nil, b, err
+		}
+		v, err := tsearch.DecodeTSVector(data)
+		if err != nil {
+			return nil, b, err
+		}
+		return tree.NewDTSVector(v), b, nil
+	case types.TSQuery:
+		b, data, err := encoding.DecodeUntaggedBytesValue(buf)
+		if err != nil {
+			return nil, b, err
+		}
+		q, err := tsearch.DecodeTSQuery(data)
+		if err != nil {
+			return nil, b, err
+		}
+		return tree.NewDTSQuery(q), b, nil
 	case types.Oid:
 		b, data, err := encoding.DecodeUntaggedIntValue(buf)
 		return a.NewDOid(tree.MakeDOid(tree.DInt(data))), b, err
@@ -534,6 +578,13 @@ func decodeUntaggedDatum(a *DatumAlloc, t types.T, buf []byte) (tree.Datum, []by
 		case types.TCollatedString:
 			b, data, err := encoding.DecodeUntaggedBytesValue(buf)
 			return tree.NewDCollatedString(string(data), typ.Locale, &a.env), b, err
+		case types.TEnum:
+			b, data, err := encoding.DecodeUntaggedBytesValue(buf)
+			if err != nil {
+				return nil, b, err
+			}
+			d, err := tree.NewDEnumFromPhysicalRep(typ, data)
+			return d, b, err
 		case types.TArray:
 			return decodeArray(a, typ.Typ, buf)
 		case types.TTuple:
@@ -665,6 +716,16 @@ func MarshalColumnValue(col ColumnDescriptor, val tree.Datum) (roachpb.Value, er
 			r.SetBytes(data)
 			return r, nil
 		}
+	case ColumnType_TSVECTOR:
+		if v, ok := val.(*tree.DTSVector); ok {
+			r.SetBytes(v.TSVector.Encode(nil))
+			return r, nil
+		}
+	case ColumnType_TSQUERY:
+		if v, ok := val.(*tree.DTSQuery); ok {
+			r.SetBytes(v.TSQuery.Encode(nil))
+			return r, nil
+		}
 	case ColumnType_ARRAY:
 		if v, ok := val.(*tree.DArray); ok {
 			if err := checkElementType(v.ParamTyp, col.Type); err != nil {
@@ -698,6 +759,11 @@ func MarshalColumnValue(col ColumnDescriptor, val tree.Datum) (roachpb.Value, er
 			r.SetInt(int64(v.DInt))
 			return r, nil
 		}
+	case ColumnType_ENUM:
+		if v, ok := val.(*tree.DEnum); ok {
+			r.SetBytes(v.PhysicalRep)
+			return r, nil
+		}
 	default:
 		return r, pgerror.NewAssertionErrorf("unsupported column type: %s", col.Type.SemanticType)
 	}
@@ -816,6 +882,26 @@ func UnmarshalColumnValue(a *DatumAlloc, typ ColumnType, value roachpb.Value) (t
 			return nil, err
 		}
 		return a.NewDIPAddr(tree.DIPAddr{IPAddr: ipAddr}), nil
+	case ColumnType_TSVECTOR:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		tv, err := tsearch.DecodeTSVector(v)
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewDTSVector(tv), nil
+	case ColumnType_TSQUERY:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		q, err := tsearch.DecodeTSQuery(v)
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewDTSQuery(q), nil
 	case ColumnType_NAME:
 		v, err := value.GetBytes()
 		if err != nil {
@@ -828,6 +914,12 @@ func UnmarshalColumnValue(a *DatumAlloc, typ ColumnType, value roachpb.Value) (t
 			return nil, err
 		}
 		return a.NewDOid(tree.MakeDOid(tree.DInt(v))), nil
+	case ColumnType_ENUM:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewDEnumFromPhysicalRep(typ.enumDatumType(), v)
 	default:
 		return nil, errors.Errorf("unsupported column type: %s", typ.SemanticType)
 	}
@@ -1075,7 +1167,7 @@ func datumTypeToArrayElementEncodingType(t types.T) (encoding.Type, error) {
 	case types.INet:
 		return encoding.IPAddr, nil
 	default:
-		if t.FamilyEqual(types.FamCollatedString) {
+		if t.FamilyEqual(types.FamCollatedString) || t.FamilyEqual(types.AnyEnum) {
 			return encoding.Bytes, nil
 		}
 		return 0, errors.Errorf("Don't know encoding type for %s", t)
@@ -1097,6 +1189,12 @@ func checkElementType(paramType types.T, columnType ColumnType) error {
 				cs.Locale, *columnType.Locale)
 		}
 	}
+	if e, ok := paramType.(types.TEnum); ok {
+		if ID(e.ID) != columnType.EnumTypeID {
+			return errors.Errorf("type of enum array being inserted (%s) doesn't match column type (%s)",
+				e.Name, columnType.EnumTypeName)
+		}
+	}
 	return nil
 }
 
@@ -1140,6 +1238,8 @@ func encodeArrayElement(b []byte, d tree.Datum) ([]byte, error) {
 		return encoding.EncodeUntaggedIntValue(b, int64(t.DInt)), nil
 	case *tree.DCollatedString:
 		return encoding.EncodeUntaggedBytesValue(b, []byte(t.Contents)), nil
+	case *tree.DEnum:
+		return encoding.EncodeUntaggedBytesValue(b, t.PhysicalRep), nil
 	}
 	return nil, errors.Errorf("don't know how to encode %s", d)
 }
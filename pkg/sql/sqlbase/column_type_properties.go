@@ -15,6 +15,7 @@
 package sqlbase
 
 import (
+	"bytes"
 	"fmt"
 	"math"
 	"strings"
@@ -71,6 +72,9 @@ func DatumTypeToColumnType(ptyp types.T) (ColumnType, error) {
 	case types.TCollatedString:
 		ctyp.SemanticType = ColumnType_COLLATEDSTRING
 		ctyp.Locale = &t.Locale
+	case types.TEnum:
+		ctyp.SemanticType = ColumnType_ENUM
+		ctyp.SetEnumType(t)
 	case types.TArray:
 		ctyp.SemanticType = ColumnType_ARRAY
 		contents, err := datumTypeToColumnSemanticType(t.Typ)
@@ -82,6 +86,9 @@ func DatumTypeToColumnType(ptyp types.T) (ColumnType, error) {
 			cs := t.Typ.(types.TCollatedString)
 			ctyp.Locale = &cs.Locale
 		}
+		if e, ok := t.Typ.(types.TEnum); ok {
+			ctyp.SetEnumType(e)
+		}
 	case types.TTuple:
 		ctyp.SemanticType = ColumnType_TUPLE
 		ctyp.TupleContents = make([]ColumnType, len(t.Types))
@@ -188,12 +195,17 @@ func PopulateTypeAttrs(base ColumnType, typ coltypes.T) (ColumnType, error) {
 	case *coltypes.TIPAddr:
 	case *coltypes.TInterval:
 	case *coltypes.TJSON:
+	case *coltypes.TTSVector, *coltypes.TTSQuery:
 	case *coltypes.TName:
 	case *coltypes.TOid:
-	case *coltypes.TTime:
-	case *coltypes.TTimestamp:
-	case *coltypes.TTimestampTZ:
+	case *coltypes.TTime, *coltypes.TTimestamp, *coltypes.TTimestampTZ:
+		if _, prec, ok := coltypes.TimePrecision(t); ok {
+			base.Precision = int32(prec)
+			base.TimePrecisionIsSet = true
+		}
+
 	case *coltypes.TUUID:
+	case *coltypes.TUserDefined:
 	default:
 		return ColumnType{}, errors.Errorf("unexpected type %T", t)
 	}
@@ -295,8 +307,14 @@ func (c *ColumnType) SQLString() string {
 			}
 			return fmt.Sprintf("%s(%d)", c.SemanticType.String(), c.Precision)
 		}
+	case ColumnType_TIME, ColumnType_TIMESTAMP, ColumnType_TIMESTAMPTZ:
+		if c.TimePrecisionIsSet {
+			return fmt.Sprintf("%s(%d)", c.SemanticType.String(), c.Precision)
+		}
 	case ColumnType_ARRAY:
 		return c.elementColumnType().SQLString() + "[]"
+	case ColumnType_ENUM:
+		return tree.NameString(c.EnumTypeName)
 	}
 	if c.VisibleType != ColumnType_NONE {
 		return c.VisibleType.String()
@@ -365,6 +383,8 @@ func (c *ColumnType) InformationSchemaVisibleType() string {
 		return "record"
 	case ColumnType_ARRAY:
 		return "ARRAY"
+	case ColumnType_ENUM:
+		return "USER-DEFINED"
 	}
 
 	// The name of the remaining semantic type constants are suitable
@@ -437,6 +457,21 @@ func (c *ColumnType) NumericPrecision() (int32, bool) {
 	return 0, false
 }
 
+// DatetimePrecision returns the declared precision of the TIME, TIMESTAMP and
+// TIMESTAMPTZ data types. Returns false if the data type is not one of these,
+// or if its precision was not specified.
+//
+// This is used to populate information_schema.columns.datetime_precision.
+func (c *ColumnType) DatetimePrecision() (int32, bool) {
+	switch c.SemanticType {
+	case ColumnType_TIME, ColumnType_TIMESTAMP, ColumnType_TIMESTAMPTZ:
+		if c.TimePrecisionIsSet {
+			return c.Precision, true
+		}
+	}
+	return 0, false
+}
+
 // NumericPrecisionRadix returns the implicit precision radix of
 // numeric data types. Returns false if the data type is not numeric.
 //
@@ -541,6 +576,10 @@ func datumTypeToColumnSemanticType(ptyp types.T) (ColumnType_SemanticType, error
 		return ColumnType_OIDVECTOR, nil
 	case types.JSON:
 		return ColumnType_JSONB, nil
+	case types.TSVector:
+		return ColumnType_TSVECTOR, nil
+	case types.TSQuery:
+		return ColumnType_TSQUERY, nil
 	default:
 		if ptyp.FamilyEqual(types.FamCollatedString) {
 			return ColumnType_COLLATEDSTRING, nil
@@ -548,6 +587,9 @@ func datumTypeToColumnSemanticType(ptyp types.T) (ColumnType_SemanticType, error
 		if ptyp.FamilyEqual(types.FamTuple) {
 			return ColumnType_TUPLE, nil
 		}
+		if ptyp.FamilyEqual(types.AnyEnum) {
+			return ColumnType_ENUM, nil
+		}
 		if wrapper, ok := ptyp.(types.TOidWrapper); ok {
 			return datumTypeToColumnSemanticType(wrapper.T)
 		}
@@ -590,6 +632,10 @@ func columnSemanticTypeToDatumType(c *ColumnType, k ColumnType_SemanticType) typ
 		return types.INet
 	case ColumnType_JSONB:
 		return types.JSON
+	case ColumnType_TSVECTOR:
+		return types.TSVector
+	case ColumnType_TSQUERY:
+		return types.TSQuery
 	case ColumnType_TUPLE:
 		return types.FamTuple
 	case ColumnType_COLLATEDSTRING:
@@ -607,10 +653,82 @@ func columnSemanticTypeToDatumType(c *ColumnType, k ColumnType_SemanticType) typ
 		return types.IntVector
 	case ColumnType_OIDVECTOR:
 		return types.OidVector
+	case ColumnType_ENUM:
+		return c.enumDatumType()
 	}
 	return nil
 }
 
+// enumDatumType returns the datum type of an ENUM column type or of an
+// array of ENUMs.
+func (c *ColumnType) enumDatumType() types.TEnum {
+	members := &types.EnumMembers{
+		PhysicalReps: make([][]byte, len(c.EnumMembers)),
+		LogicalReps:  make([]string, len(c.EnumMembers)),
+	}
+	for i := range c.EnumMembers {
+		members.PhysicalReps[i] = c.EnumMembers[i].PhysicalRepresentation
+		members.LogicalReps[i] = c.EnumMembers[i].LogicalRepresentation
+	}
+	return types.TEnum{ID: uint32(c.EnumTypeID), Name: c.EnumTypeName, Members: members}
+}
+
+// SetEnumType populates the ENUM attributes of the ColumnType from the given
+// datum type.
+func (c *ColumnType) SetEnumType(t types.TEnum) {
+	c.EnumTypeID = ID(t.ID)
+	c.EnumTypeName = t.Name
+	c.EnumMembers = nil
+	if t.Members == nil {
+		return
+	}
+	c.EnumMembers = make([]EnumMember, len(t.Members.PhysicalReps))
+	for i := range c.EnumMembers {
+		c.EnumMembers[i] = EnumMember{
+			PhysicalRepresentation: t.Members.PhysicalReps[i],
+			LogicalRepresentation:  t.Members.LogicalReps[i],
+		}
+	}
+}
+
+// AddReadOnlyEnumMember inserts a copy of a member that is being added to
+// the ENUM type among the members of the ColumnType, in the order of their
+// physical representations. The member can be read but not written until
+// MakeEnumMembersWritable is called, so that values with the member are only
+// written once every node can decode them.
+func (c *ColumnType) AddReadOnlyEnumMember(m EnumMember) {
+	m.ReadOnly = true
+	pos := len(c.EnumMembers)
+	for i := range c.EnumMembers {
+		if bytes.Compare(m.PhysicalRepresentation, c.EnumMembers[i].PhysicalRepresentation) < 0 {
+			pos = i
+			break
+		}
+	}
+	c.EnumMembers = append(c.EnumMembers, EnumMember{})
+	copy(c.EnumMembers[pos+1:], c.EnumMembers[pos:])
+	c.EnumMembers[pos] = m
+}
+
+// HasReadOnlyEnumMembers returns whether the ColumnType has members that
+// cannot be written yet.
+func (c *ColumnType) HasReadOnlyEnumMembers() bool {
+	for i := range c.EnumMembers {
+		if c.EnumMembers[i].ReadOnly {
+			return true
+		}
+	}
+	return false
+}
+
+// MakeEnumMembersWritable allows values with any of the members of the
+// ColumnType to be written.
+func (c *ColumnType) MakeEnumMembersWritable() {
+	for i := range c.EnumMembers {
+		c.EnumMembers[i].ReadOnly = false
+	}
+}
+
 // ToDatumType converts the ColumnType to a types.T (type of in-memory
 // representations). It returns nil if there is no such type.
 //
@@ -645,11 +763,13 @@ func ColumnTypesToDatumTypes(colTypes []ColumnType) []types.T {
 
 // LimitValueWidth checks that the width (for strings, byte arrays, and bit
 // strings) and scale (for decimals) of the value fits the specified column
-// type. In case of decimals, it can truncate fractional digits in the input
-// value in order to fit the target column. If the input value fits the target
+// type. In case of decimals and of times and timestamps with a precision, it
+// can truncate fractional digits in the input value in order to fit the target
+// column. If the input value fits the target
 // column, it is returned unchanged. If the input value can be truncated to fit,
-// then a truncated copy is returned. Otherwise, an error is returned. This
-// method is used by INSERT and UPDATE.
+// then a truncated copy is returned. Otherwise, an error is returned. Values
+// of ENUM types are also rejected if their member is still being added to
+// the type. This method is used by INSERT and UPDATE.
 func LimitValueWidth(
 	typ ColumnType, inVal tree.Datum, name *string,
 ) (outVal tree.Datum, err error) {
@@ -720,6 +840,20 @@ func LimitValueWidth(
 			}
 			return &outDec, nil
 		}
+	case ColumnType_TIME, ColumnType_TIMESTAMP, ColumnType_TIMESTAMPTZ:
+		if typ.TimePrecisionIsSet {
+			return tree.RoundTimeDatum(inVal, int(typ.Precision)), nil
+		}
+	case ColumnType_ENUM:
+		if v, ok := inVal.(*tree.DEnum); ok {
+			for i := range typ.EnumMembers {
+				if m := &typ.EnumMembers[i]; m.ReadOnly && bytes.Equal(m.PhysicalRepresentation, v.PhysicalRep) {
+					return nil, pgerror.NewErrorf(pgerror.CodeObjectNotInPrerequisiteStateError,
+						"enum label %q is being added to type %s (column %q); try again later",
+						v.LogicalRep, typ.SQLString(), tree.ErrNameString(name))
+				}
+			}
+		}
 	case ColumnType_ARRAY:
 		if inArr, ok := inVal.(*tree.DArray); ok {
 			var outArr *tree.DArray
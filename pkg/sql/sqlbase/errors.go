@@ -111,6 +111,12 @@ func NewUndefinedDatabaseError(name string) error {
 		pgerror.CodeInvalidCatalogNameError, "database %q does not exist", name)
 }
 
+// NewUndefinedSchemaError creates an error that represents a missing schema.
+func NewUndefinedSchemaError(name string) error {
+	return pgerror.NewErrorf(
+		pgerror.CodeInvalidSchemaNameError, "schema %q does not exist", name)
+}
+
 // NewInvalidWildcardError creates an error that represents the result of expanding
 // a table wildcard over an invalid database or schema prefix.
 func NewInvalidWildcardError(name string) error {
@@ -184,6 +190,36 @@ func NewAggInAggError() error {
 	return pgerror.NewErrorf(pgerror.CodeGroupingError, "aggregate function calls cannot be nested")
 }
 
+// NewLockingNotAllowedError creates an error for the case when a locking
+// clause is used with a query whose rows do not correspond to table rows, e.g.
+// with a GROUP BY clause.
+func NewLockingNotAllowedError(strength tree.LockingStrength, with string) error {
+	return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+		"%s is not allowed with %s", strength, with)
+}
+
+// NewLockingStrengthUnsupportedError creates an error for the locking
+// strengths which request shared locks. Only exclusive locks are supported,
+// and shared locks must not be silently upgraded to them.
+func NewLockingStrengthUnsupportedError(strength tree.LockingStrength) error {
+	return pgerror.UnimplementedWithIssueErrorf(6583, "%s is not supported", strength)
+}
+
+// NewLockingTargetNotFoundError creates an error for the case when the OF
+// list of a locking clause refers to a table which is not in the FROM clause.
+func NewLockingTargetNotFoundError(strength tree.LockingStrength, name tree.Name) error {
+	return pgerror.NewErrorf(pgerror.CodeUndefinedTableError,
+		"relation %q in %s clause not found in FROM clause", string(name), strength)
+}
+
+// NewLockingSecondaryIndexError creates an error for the case when a locking
+// read is forced to use a secondary index. Locking reads always use the
+// primary index, which contains all the keys of the rows they lock.
+func NewLockingSecondaryIndexError(indexName string) error {
+	return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+		"index %q cannot be used by a locking read; only the primary index can be used", indexName)
+}
+
 // NewStatementCompletionUnknownError creates an error with the corresponding pg
 // code. This is used to inform the client that it's unknown whether a statement
 // succeeded or not. Of particular interest to clients is when this error is
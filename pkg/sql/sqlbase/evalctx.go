@@ -107,3 +107,10 @@ func (ep *DummyEvalPlanner) ParseType(sql string) (coltypes.CastTargetType, erro
 func (ep *DummyEvalPlanner) EvalSubquery(expr *tree.Subquery) (tree.Datum, error) {
 	return nil, errEvalPlanner
 }
+
+// SendNotification is part of the tree.EvalPlanner interface.
+func (ep *DummyEvalPlanner) SendNotification(
+	ctx context.Context, channel, payload string,
+) error {
+	return errEvalPlanner
+}
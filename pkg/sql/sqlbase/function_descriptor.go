@@ -0,0 +1,106 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sqlbase
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+)
+
+// SetID implements the DescriptorProto interface.
+func (desc *FunctionDescriptor) SetID(id ID) {
+	desc.ID = id
+}
+
+// TypeName returns the plain type of this descriptor.
+func (desc *FunctionDescriptor) TypeName() string {
+	return "function"
+}
+
+// SetName implements the DescriptorProto interface.
+func (desc *FunctionDescriptor) SetName(name string) {
+	desc.Name = name
+}
+
+// GetAuditMode is part of the DescriptorProto interface.
+// Functions cannot be audited.
+func (desc *FunctionDescriptor) GetAuditMode() TableDescriptor_AuditMode {
+	return TableDescriptor_DISABLED
+}
+
+// Validate validates that the function descriptor is well formed. Checks
+// include validating the function name, verifying that there is a parent
+// database, and that the overloads have distinct argument types and either
+// all return sets or none do.
+func (desc *FunctionDescriptor) Validate() error {
+	if err := validateName(desc.Name, "function"); err != nil {
+		return err
+	}
+	if desc.ID == 0 {
+		return fmt.Errorf("invalid function ID %d", desc.ID)
+	}
+	if desc.ParentID == 0 {
+		return fmt.Errorf("invalid parent ID %d", desc.ParentID)
+	}
+	if len(desc.Overloads) == 0 {
+		return fmt.Errorf("function %q has no overloads", desc.Name)
+	}
+	for i := range desc.Overloads {
+		ov := &desc.Overloads[i]
+		if ov.Body == "" {
+			return fmt.Errorf("overload %d of function %q has no body", i, desc.Name)
+		}
+		if ov.ReturnsSet != desc.Overloads[0].ReturnsSet {
+			return fmt.Errorf("overloads of function %q differ in whether they return sets", desc.Name)
+		}
+		if j := desc.FindOverload(ov.ArgumentTypes()); j != i {
+			return fmt.Errorf("overloads %d and %d of function %q have the same argument types",
+				j, i, desc.Name)
+		}
+	}
+	return desc.Privileges.Validate(desc.GetID())
+}
+
+// FindOverload returns the index of the overload with the given argument
+// types, or -1 if there is none.
+func (desc *FunctionDescriptor) FindOverload(argTypes []types.T) int {
+	for i := range desc.Overloads {
+		ov := &desc.Overloads[i]
+		if len(ov.Arguments) != len(argTypes) {
+			continue
+		}
+		match := true
+		for j := range ov.Arguments {
+			if !ov.Arguments[j].Type.ToDatumType().Equivalent(argTypes[j]) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
+
+// ArgumentTypes returns the datum types of the arguments of the overload.
+func (ov *FunctionDescriptor_Overload) ArgumentTypes() []types.T {
+	res := make([]types.T, len(ov.Arguments))
+	for i := range ov.Arguments {
+		res[i] = ov.Arguments[i].Type.ToDatumType()
+	}
+	return res
+}
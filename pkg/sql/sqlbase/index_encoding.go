@@ -15,6 +15,7 @@
 package sqlbase
 
 import (
+	"bytes"
 	"fmt"
 	"sort"
 
@@ -693,10 +694,12 @@ func EncodeInvertedIndexKeys(
 	return EncodeInvertedIndexTableKeys(val, keyPrefix)
 }
 
-// EncodeInvertedIndexTableKeys encodes the paths in a JSON `val` and
-// concatenates it with `inKey`and returns a list of buffers per
-// path. The encoded values is guaranteed to be lexicographically
-// sortable, but not guaranteed to be round-trippable during decoding.
+// EncodeInvertedIndexTableKeys encodes the paths in a JSON `val`, the
+// elements of an array `val` or the lexemes of a tsvector `val`, and
+// concatenates it with `inKey`and returns a list of buffers per path,
+// distinct element or lexeme. The encoded values is
+// guaranteed to be lexicographically sortable, but not guaranteed to be
+// round-trippable during decoding.
 func EncodeInvertedIndexTableKeys(val tree.Datum, inKey []byte) (key [][]byte, err error) {
 	if val == tree.DNull {
 		return [][]byte{encoding.EncodeNullAscending(inKey)}, nil
@@ -704,8 +707,68 @@ func EncodeInvertedIndexTableKeys(val tree.Datum, inKey []byte) (key [][]byte, e
 	switch t := tree.UnwrapDatum(nil, val).(type) {
 	case *tree.DJSON:
 		return json.EncodeInvertedIndexKeys(inKey, (t.JSON))
+	case *tree.DArray:
+		return encodeArrayInvertedIndexTableKeys(t, inKey)
+	case *tree.DTSVector:
+		return encodeTSVectorInvertedIndexTableKeys(t, inKey), nil
 	}
-	return nil, pgerror.NewAssertionErrorf("trying to apply inverted index to non JSON type")
+	return nil, pgerror.NewAssertionErrorf(
+		"trying to apply inverted index to type %s", val.ResolvedType())
+}
+
+// encodeArrayInvertedIndexTableKeys returns one key per distinct non-NULL
+// element of the array, sorted. NULL elements are not indexed, since they
+// never satisfy a containment or overlap predicate. An array without non-NULL
+// elements is indexed under the NULL key, so that every row has at least one
+// entry in the index.
+func encodeArrayInvertedIndexTableKeys(val *tree.DArray, inKey []byte) ([][]byte, error) {
+	outKeys := make([][]byte, 0, len(val.Array))
+	for _, elem := range val.Array {
+		if elem == tree.DNull {
+			continue
+		}
+		// Each key gets its own copy of the prefix.
+		prefix := make([]byte, len(inKey), len(inKey)+16)
+		copy(prefix, inKey)
+		newKey, err := EncodeTableKey(prefix, elem, encoding.Ascending)
+		if err != nil {
+			return nil, err
+		}
+		outKeys = append(outKeys, newKey)
+	}
+	if len(outKeys) == 0 {
+		return [][]byte{encoding.EncodeNullAscending(inKey)}, nil
+	}
+
+	sort.Slice(outKeys, func(i, j int) bool {
+		return bytes.Compare(outKeys[i], outKeys[j]) < 0
+	})
+	n := 1
+	for i := 1; i < len(outKeys); i++ {
+		if !bytes.Equal(outKeys[i], outKeys[n-1]) {
+			outKeys[n] = outKeys[i]
+			n++
+		}
+	}
+	return outKeys[:n], nil
+}
+
+// encodeTSVectorInvertedIndexTableKeys returns one key per lexeme of the
+// vector, ignoring positions and weights. The keys are sorted since the
+// lexemes are. An empty vector is indexed under the NULL key, so that every
+// row has at least one entry in the index.
+func encodeTSVectorInvertedIndexTableKeys(val *tree.DTSVector, inKey []byte) [][]byte {
+	if len(val.TSVector) == 0 {
+		return [][]byte{encoding.EncodeNullAscending(inKey)}
+	}
+	outKeys := make([][]byte, len(val.TSVector))
+	for i, l := range val.TSVector {
+		// Each key gets its own copy of the prefix.
+		prefix := make([]byte, len(inKey), len(inKey)+len(l.Word)+2)
+		copy(prefix, inKey)
+		outKeys[i] = encoding.EncodeStringAscending(prefix, l.Word)
+	}
+	return outKeys
 }
 
 // EncodeSecondaryIndex encodes key/values for a secondary
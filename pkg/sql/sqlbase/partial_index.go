@@ -0,0 +1,255 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sqlbase
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+)
+
+// PredicateColumnIDs returns the IDs of the columns referenced by the
+// predicate of a partial index, in no particular order. It returns nil if the
+// index is not partial.
+func (desc *IndexDescriptor) PredicateColumnIDs(tableDesc *TableDescriptor) ([]ColumnID, error) {
+	if !desc.IsPartial() {
+		return nil, nil
+	}
+	expr, err := parser.ParseExpr(*desc.Predicate)
+	if err != nil {
+		return nil, err
+	}
+	var colIDs []ColumnID
+	seen := make(map[ColumnID]struct{})
+	_, err = tree.SimpleVisit(expr, func(expr tree.Expr) (err error, recurse bool, newExpr tree.Expr) {
+		vBase, ok := expr.(tree.VarName)
+		if !ok {
+			return nil, true, expr
+		}
+		v, err := vBase.NormalizeVarName()
+		if err != nil {
+			return err, false, nil
+		}
+		c, ok := v.(*tree.ColumnItem)
+		if !ok {
+			return nil, true, expr
+		}
+		col, _, err := tableDesc.FindColumnByName(c.ColumnName)
+		if err != nil {
+			return err, false, nil
+		}
+		if _, ok := seen[col.ID]; !ok {
+			seen[col.ID] = struct{}{}
+			colIDs = append(colIDs, col.ID)
+		}
+		return nil, false, expr
+	})
+	return colIDs, err
+}
+
+// IndexPredicateImpliedBy returns whether every row that satisfies the given
+// expression also satisfies the predicate of a partial index, so that the
+// index can serve as the arbiter of an ON CONFLICT clause with this
+// expression. The check is syntactic: each conjunct of the predicate must also
+// be a conjunct of the expression.
+func IndexPredicateImpliedBy(predicate string, expr tree.Expr) (bool, error) {
+	pred, err := parser.ParseExpr(predicate)
+	if err != nil {
+		return false, err
+	}
+	predConjuncts, err := conjunctStrings(pred)
+	if err != nil {
+		return false, err
+	}
+	exprConjuncts, err := conjunctStrings(expr)
+	if err != nil {
+		return false, err
+	}
+	for c := range predConjuncts {
+		if _, ok := exprConjuncts[c]; !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// conjunctStrings returns the set of the serialized conjuncts of a boolean
+// expression. The column references are unqualified, as they are in the
+// predicates of partial indexes.
+func conjunctStrings(expr tree.Expr) (map[string]struct{}, error) {
+	expr, err := tree.SimpleVisit(expr, func(expr tree.Expr) (err error, recurse bool, newExpr tree.Expr) {
+		vBase, ok := expr.(tree.VarName)
+		if !ok {
+			return nil, true, expr
+		}
+		v, err := vBase.NormalizeVarName()
+		if err != nil {
+			return err, false, nil
+		}
+		if c, ok := v.(*tree.ColumnItem); ok {
+			return nil, false, &tree.ColumnItem{ColumnName: c.ColumnName}
+		}
+		return nil, false, v
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]struct{})
+	var split func(expr tree.Expr)
+	split = func(expr tree.Expr) {
+		expr = tree.StripParens(expr)
+		if and, ok := expr.(*tree.AndExpr); ok {
+			split(and.Left)
+			split(and.Right)
+			return
+		}
+		res[tree.Serialize(expr)] = struct{}{}
+	}
+	split(expr)
+	return res, nil
+}
+
+// RunOverAllColumnsAndPredicate applies its argument fn to the columns
+// of the index, as RunOverAllColumns does, and then to the columns
+// referenced by the index predicate that are not part of the index.
+func (desc *IndexDescriptor) RunOverAllColumnsAndPredicate(
+	tableDesc *TableDescriptor, fn func(id ColumnID) error,
+) error {
+	if err := desc.RunOverAllColumns(fn); err != nil {
+		return err
+	}
+	predCols, err := desc.PredicateColumnIDs(tableDesc)
+	if err != nil {
+		return err
+	}
+	for _, colID := range predCols {
+		if desc.ContainsColumnID(colID) {
+			continue
+		}
+		if err := fn(colID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PartialIndexPredicates evaluates the predicates of the partial indexes in a
+// list of indexes, to determine which of them must contain an entry for a
+// given row.
+type PartialIndexPredicates struct {
+	// preds contains the predicate of each index in the list, or nil for the
+	// indexes that are not partial.
+	preds []tree.TypedExpr
+	cols  []ColumnDescriptor
+
+	// Fields used during evaluation.
+	colMap  map[ColumnID]int
+	values  tree.Datums
+	evalCtx tree.EvalContext
+}
+
+var _ tree.IndexedVarContainer = &PartialIndexPredicates{}
+
+// Init initializes the predicates of the given indexes. It is a no-op if none
+// of the indexes are partial.
+func (p *PartialIndexPredicates) Init(tableDesc *TableDescriptor, indexes []IndexDescriptor) error {
+	p.preds = nil
+	for i := range indexes {
+		if !indexes[i].IsPartial() {
+			continue
+		}
+		if p.preds == nil {
+			p.preds = make([]tree.TypedExpr, len(indexes))
+			p.cols = tableDesc.Columns
+		}
+		expr, err := parser.ParseExpr(*indexes[i].Predicate)
+		if err != nil {
+			return err
+		}
+		tn := tree.MakeUnqualifiedTableName(tree.Name(tableDesc.Name))
+		sources := MakeMultiSourceInfo(
+			NewSourceInfoForSingleTable(tn, ResultColumnsFromColDescs(p.cols)),
+		)
+		ivarHelper := tree.MakeIndexedVarHelper(p, len(p.cols))
+		expr, _, _, err = ResolveNames(expr, sources, ivarHelper, sessiondata.SearchPath{})
+		if err != nil {
+			return err
+		}
+		semaCtx := tree.MakeSemaContext(false /* privileged */)
+		semaCtx.IVarContainer = p
+		typedExpr, err := tree.TypeCheck(expr, &semaCtx, types.Bool)
+		if err != nil {
+			return err
+		}
+		p.preds[i] = typedExpr
+	}
+	if p.preds != nil {
+		// Index predicates cannot contain impure functions, so their result
+		// does not depend on the session.
+		p.evalCtx = tree.EvalContext{SessionData: &sessiondata.SessionData{}}
+	}
+	return nil
+}
+
+// HasPartialIndexes returns true if at least one of the indexes passed to Init
+// is a partial index.
+func (p *PartialIndexPredicates) HasPartialIndexes() bool {
+	return p.preds != nil
+}
+
+// RowInIndex returns true if the row with the given values must have an entry
+// in the index at the given position in the list of indexes passed to Init.
+// This is always the case for indexes that are not partial.
+func (p *PartialIndexPredicates) RowInIndex(
+	idx int, colMap map[ColumnID]int, values tree.Datums,
+) (bool, error) {
+	if p.preds == nil || p.preds[idx] == nil {
+		return true, nil
+	}
+	p.colMap = colMap
+	p.values = values
+	p.evalCtx.PushIVarContainer(p)
+	d, err := p.preds[idx].Eval(&p.evalCtx)
+	p.evalCtx.PopIVarContainer()
+	if err != nil {
+		return false, err
+	}
+	return d == tree.DBoolTrue, nil
+}
+
+// IndexedVarEval implements the tree.IndexedVarContainer interface.
+func (p *PartialIndexPredicates) IndexedVarEval(
+	idx int, ctx *tree.EvalContext,
+) (tree.Datum, error) {
+	rowIdx, ok := p.colMap[p.cols[idx].ID]
+	if !ok {
+		// Columns that are not part of the row (for example, columns omitted
+		// by an INSERT) are NULL.
+		return tree.DNull, nil
+	}
+	return p.values[rowIdx], nil
+}
+
+// IndexedVarResolvedType implements the tree.IndexedVarContainer interface.
+func (p *PartialIndexPredicates) IndexedVarResolvedType(idx int) types.T {
+	return p.cols[idx].Type.ToDatumType()
+}
+
+// IndexedVarNodeFormatter implements the tree.IndexedVarContainer interface.
+func (p *PartialIndexPredicates) IndexedVarNodeFormatter(idx int) tree.NodeFormatter {
+	n := tree.Name(p.cols[idx].Name)
+	return &n
+}
@@ -36,6 +36,10 @@ type NameResolutionVisitor struct {
 	searchPath sessiondata.SearchPath
 	resolver   ColumnResolver
 
+	// functionResolver, if not nil, is used to resolve the names of
+	// user-defined functions.
+	functionResolver tree.FunctionReferenceResolver
+
 	// foundDependentVars is set to true during the analysis if an
 	// expression was found which can change values between rows of the
 	// same data source, for example IndexedVars and calls to the
@@ -142,7 +146,7 @@ func (v *NameResolutionVisitor) VisitPre(expr tree.Expr) (recurse bool, newNode
 		return true, ivar
 
 	case *tree.FuncExpr:
-		fd, err := t.Func.Resolve(v.searchPath)
+		fd, err := t.Func.Resolve(v.searchPath, v.functionResolver)
 		if err != nil {
 			v.err = err
 			return false, expr
@@ -220,23 +224,26 @@ func ResolveNames(
 	searchPath sessiondata.SearchPath,
 ) (tree.Expr, bool, bool, error) {
 	var v NameResolutionVisitor
-	return ResolveNamesUsingVisitor(&v, expr, sources, ivarHelper, searchPath)
+	return ResolveNamesUsingVisitor(&v, expr, sources, ivarHelper, searchPath, nil /* functionResolver */)
 }
 
 // ResolveNamesUsingVisitor resolves the names in the given expression. It
 // returns the resolved expression, whether it found dependent vars, and
-// whether it found stars.
+// whether it found stars. The user-defined functions can only be called if
+// functionResolver is not nil.
 func ResolveNamesUsingVisitor(
 	v *NameResolutionVisitor,
 	expr tree.Expr,
 	sources MultiSourceInfo,
 	ivarHelper tree.IndexedVarHelper,
 	searchPath sessiondata.SearchPath,
+	functionResolver tree.FunctionReferenceResolver,
 ) (tree.Expr, bool, bool, error) {
 	*v = NameResolutionVisitor{
-		sources:    sources,
-		iVarHelper: ivarHelper,
-		searchPath: searchPath,
+		sources:          sources,
+		iVarHelper:       ivarHelper,
+		searchPath:       searchPath,
+		functionResolver: functionResolver,
 		resolver: ColumnResolver{
 			Sources: sources,
 		},
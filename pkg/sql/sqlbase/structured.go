@@ -31,6 +31,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/interval"
 	"github.com/pkg/errors"
@@ -468,6 +469,12 @@ func (desc *IndexDescriptor) IsInterleaved() bool {
 	return len(desc.Interleave.Ancestors) > 0 || len(desc.InterleavedBy) > 0
 }
 
+// IsPartial returns whether the index is a partial index, i.e. whether it
+// only contains the rows that satisfy its predicate.
+func (desc *IndexDescriptor) IsPartial() bool {
+	return desc.Predicate != nil
+}
+
 // SetID implements the DescriptorProto interface.
 func (desc *TableDescriptor) SetID(id ID) {
 	desc.ID = id
@@ -501,6 +508,12 @@ func (desc *TableDescriptor) IsView() bool {
 	return desc.ViewQuery != ""
 }
 
+// MaterializedView returns true if the TableDescriptor describes a
+// materialized view, whose results are stored like the rows of a table.
+func (desc *TableDescriptor) MaterializedView() bool {
+	return desc.IsMaterializedView
+}
+
 // IsSequence returns true if the TableDescriptor actually describes a
 // Sequence resource rather than a Table.
 func (desc *TableDescriptor) IsSequence() bool {
@@ -514,14 +527,64 @@ func (desc *TableDescriptor) IsVirtualTable() bool {
 	return desc.ID == keys.VirtualDescriptorID
 }
 
+// IsTemporary returns true if the TableDescriptor describes a temporary
+// table, view or sequence, which is only visible to the session that
+// created it.
+func (desc *TableDescriptor) IsTemporary() bool {
+	return desc.TemporarySchemaID != 0
+}
+
+// GetNamespaceParentID returns the ID under which the name of the table is
+// stored in system.namespace. This is the ID of the temporary schema for
+// temporary tables, the ID of the user-defined schema for tables in such a
+// schema, and the ID of the parent database otherwise.
+func (desc *TableDescriptor) GetNamespaceParentID() ID {
+	if desc.IsTemporary() {
+		return desc.TemporarySchemaID
+	}
+	if desc.SchemaID != 0 {
+		return desc.SchemaID
+	}
+	return desc.ParentID
+}
+
+// QualifyTemporaryTableName prepares the name of a table, view or sequence
+// created by a CREATE statement and returns whether the object is temporary.
+// Objects created by CREATE TEMPORARY statements and objects created in a
+// temporary schema are temporary.
+//
+// Unqualified names of temporary objects are qualified with the pg_temp
+// schema. Temporary objects cannot be created in other schemas.
+func QualifyTemporaryTableName(tn *tree.TableName, temporary bool) (bool, error) {
+	if tn.ExplicitSchema && sessiondata.IsTemporarySchemaName(tn.Schema()) {
+		return true, nil
+	}
+	if !temporary {
+		return false, nil
+	}
+	if tn.ExplicitSchema {
+		if tn.ExplicitCatalog || tn.Schema() == tree.PublicSchema {
+			return false, pgerror.NewErrorf(pgerror.CodeInvalidTableDefinitionError,
+				"cannot create temporary relation in non-temporary schema")
+		}
+		// The prefix of a name with two parts is the name of a database.
+		tn.CatalogName = tn.SchemaName
+		tn.ExplicitCatalog = true
+	}
+	tn.SchemaName = sessiondata.PgTempSchemaName
+	tn.ExplicitSchema = true
+	return true, nil
+}
+
 // IsPhysicalTable returns true if the TableDescriptor actually describes a
 // physical Table that needs to be stored in the kv layer, as opposed to a
 // different resource like a view or a virtual table. Physical tables have
 // primary keys, column families, and indexes (unlike virtual tables).
-// Sequences count as physical tables because their values are stored in
-// the KV layer.
+// Sequences and materialized views count as physical tables because their
+// values are stored in the KV layer.
 func (desc *TableDescriptor) IsPhysicalTable() bool {
-	return desc.IsSequence() || (desc.IsTable() && !desc.IsVirtualTable())
+	return desc.IsSequence() || desc.MaterializedView() ||
+		(desc.IsTable() && !desc.IsVirtualTable())
 }
 
 // KeysPerRow returns the maximum number of keys used to encode a row for the
@@ -783,6 +846,8 @@ func DatumTypeHasCompositeKeyEncoding(typ types.T) bool {
 func MustBeValueEncoded(semanticType ColumnType_SemanticType) bool {
 	return semanticType == ColumnType_ARRAY ||
 		semanticType == ColumnType_JSONB ||
+		semanticType == ColumnType_TSVECTOR ||
+		semanticType == ColumnType_TSQUERY ||
 		semanticType == ColumnType_TUPLE
 }
 
@@ -828,6 +893,12 @@ func (desc *MutableTableDescriptor) allocateIndexIDs(columnNames map[string]Colu
 			isCompositeColumn[col.ID] = struct{}{}
 		}
 	}
+	// Indexes being added can be defined on columns being added.
+	for _, m := range desc.Mutations {
+		if col := m.GetColumn(); col != nil && HasCompositeKeyEncoding(col.Type.SemanticType) {
+			isCompositeColumn[col.ID] = struct{}{}
+		}
+	}
 
 	// Populate IDs.
 	for _, index := range indexes {
@@ -1271,8 +1342,24 @@ func (desc *TableDescriptor) ValidateTable(st *cluster.Settings) error {
 				idx := desc.Index
 				return errors.Errorf("mutation in state %s, direction %s, index %s, id %v", m.State, m.Direction, idx.Name, idx.ID)
 			}
+		case *DescriptorMutation_Constraint:
+			if unSetEnums {
+				c := desc.Constraint
+				return errors.Errorf("mutation in state %s, direction %s, constraint %q", m.State, m.Direction, c.Name)
+			}
+		case *DescriptorMutation_ComputedColumnSwap:
+			if unSetEnums {
+				swap := desc.ComputedColumnSwap
+				return errors.Errorf("mutation in state %s, direction %s, swap of column %d with column %d",
+					m.State, m.Direction, swap.OldColumnID, swap.NewColumnID)
+			}
+		case *DescriptorMutation_MaterializedViewRefresh:
+			if unSetEnums {
+				return errors.Errorf("mutation in state %s, direction %s, refresh of materialized view",
+					m.State, m.Direction)
+			}
 		default:
-			return errors.Errorf("mutation in state %s, direction %s, and no column/index descriptor", m.State, m.Direction)
+			return errors.Errorf("mutation in state %s, direction %s, and no column/index/constraint descriptor", m.State, m.Direction)
 		}
 	}
 
@@ -1679,9 +1766,16 @@ func columnTypeIsIndexable(t ColumnType) bool {
 }
 
 // columnTypeIsInvertedIndexable returns whether the type t is valid to be indexed
-// using an inverted index.
+// using an inverted index. JSON columns are indexed by path, arrays of
+// indexable types by element and text search vectors by lexeme.
 func columnTypeIsInvertedIndexable(t ColumnType) bool {
-	return t.SemanticType == ColumnType_JSONB
+	switch t.SemanticType {
+	case ColumnType_JSONB, ColumnType_TSVECTOR:
+		return true
+	case ColumnType_ARRAY:
+		return t.ArrayContents != nil && columnTypeIsIndexable(*t.elementColumnType())
+	}
+	return false
 }
 
 func notIndexableError(cols []ColumnDescriptor, inverted bool) error {
@@ -2159,12 +2253,43 @@ func (desc *MutableTableDescriptor) MakeMutationComplete(m DescriptorMutation) e
 			if err := desc.AddIndex(*t.Index, false); err != nil {
 				return err
 			}
+
+		case *DescriptorMutation_Constraint:
+			switch t.Constraint.ConstraintType {
+			case ConstraintToUpdate_NOT_NULL:
+				// The existing rows have been validated: the column can be
+				// made NOT NULL and the hidden check constraint is no longer
+				// needed. The column may have been dropped in the meantime.
+				for i := range desc.Columns {
+					if desc.Columns[i].ID == t.Constraint.NotNullColumn {
+						desc.Columns[i].Nullable = false
+						break
+					}
+				}
+				desc.removeCheck(t.Constraint.Check.Name)
+			}
+
+		case *DescriptorMutation_ComputedColumnSwap:
+			if err := desc.performComputedColumnSwap(t.ComputedColumnSwap); err != nil {
+				return err
+			}
+
+		case *DescriptorMutation_MaterializedViewRefresh:
+			desc.performMaterializedViewRefresh(t.MaterializedViewRefresh)
 		}
 
 	case DescriptorMutation_DROP:
 		switch t := m.Descriptor_.(type) {
 		case *DescriptorMutation_Column:
 			desc.RemoveColumnFromFamily(t.Column.ID)
+
+		case *DescriptorMutation_Constraint:
+			switch t.Constraint.ConstraintType {
+			case ConstraintToUpdate_NOT_NULL:
+				// A NOT NULL constraint is only dropped when its addition is
+				// rolled back. The column remains nullable.
+				desc.removeCheck(t.Constraint.Check.Name)
+			}
 		}
 		// Nothing else to be done. The column/index was already removed from the
 		// set of column/index descriptors at mutation creation time.
@@ -2172,6 +2297,17 @@ func (desc *MutableTableDescriptor) MakeMutationComplete(m DescriptorMutation) e
 	return nil
 }
 
+// removeCheck removes the named check constraint from the descriptor, if it
+// exists.
+func (desc *MutableTableDescriptor) removeCheck(name string) {
+	for i := range desc.Checks {
+		if desc.Checks[i].Name == name {
+			desc.Checks = append(desc.Checks[:i], desc.Checks[i+1:]...)
+			return
+		}
+	}
+}
+
 // AddColumnMutation adds a column mutation to desc.Mutations.
 func (desc *MutableTableDescriptor) AddColumnMutation(
 	c ColumnDescriptor, direction DescriptorMutation_Direction,
@@ -2195,12 +2331,278 @@ func (desc *MutableTableDescriptor) AddIndexMutation(
 			return err
 		}
 	}
+	// An index on a column being swapped would be left on the old column.
+	if direction == DescriptorMutation_ADD {
+		for _, names := range [][]string{idx.ColumnNames, idx.StoreColumnNames} {
+			for _, name := range names {
+				col, _, err := desc.FindColumnByName(tree.Name(name))
+				if err != nil {
+					continue
+				}
+				if err := desc.CheckColumnNotBeingSwapped(&col); err != nil {
+					return err
+				}
+			}
+		}
+	}
 
 	m := DescriptorMutation{Descriptor_: &DescriptorMutation_Index{Index: idx}, Direction: direction}
 	desc.addMutation(m)
 	return nil
 }
 
+// AddNotNullMutation adds a mutation to desc.Mutations that makes a column
+// NOT NULL. The existing rows need to be validated before the column can be
+// made NOT NULL; in the meantime, the NOT NULL constraint is enforced on the
+// writes through the hidden check constraint ck, which is also added to
+// desc.Checks.
+func (desc *MutableTableDescriptor) AddNotNullMutation(
+	ck *TableDescriptor_CheckConstraint, direction DescriptorMutation_Direction,
+) {
+	if direction == DescriptorMutation_ADD {
+		desc.Checks = append(desc.Checks, ck)
+	}
+	m := DescriptorMutation{
+		Descriptor_: &DescriptorMutation_Constraint{
+			Constraint: &ConstraintToUpdate{
+				ConstraintType: ConstraintToUpdate_NOT_NULL,
+				Name:           ck.Name,
+				Check:          *ck,
+				NotNullColumn:  ck.ColumnIDs[0],
+			},
+		},
+		Direction: direction,
+	}
+	desc.addMutation(m)
+}
+
+// FindNotNullMutation returns the mutation that is making the column NOT
+// NULL, or nil if there is none.
+func (desc *TableDescriptor) FindNotNullMutation(colID ColumnID) *DescriptorMutation {
+	for i := range desc.Mutations {
+		if c := desc.Mutations[i].GetConstraint(); c != nil &&
+			c.ConstraintType == ConstraintToUpdate_NOT_NULL && c.NotNullColumn == colID {
+			return &desc.Mutations[i]
+		}
+	}
+	return nil
+}
+
+// AddComputedColumnSwapMutation adds a mutation to desc.Mutations that
+// replaces a column with a computed column once the latter has been added
+// and backfilled. The mutations that add the new column and the indexes
+// listed in swap must have been added to desc.Mutations before.
+func (desc *MutableTableDescriptor) AddComputedColumnSwapMutation(swap *ComputedColumnSwap) {
+	m := DescriptorMutation{
+		Descriptor_: &DescriptorMutation_ComputedColumnSwap{ComputedColumnSwap: swap},
+		Direction:   DescriptorMutation_ADD,
+	}
+	desc.addMutation(m)
+}
+
+// CheckColumnNotBeingSwapped returns an error if the column is in the middle
+// of a type change.
+func (desc *TableDescriptor) CheckColumnNotBeingSwapped(col *ColumnDescriptor) error {
+	for i := range desc.Mutations {
+		if swap := desc.Mutations[i].GetComputedColumnSwap(); swap != nil && swap.OldColumnID == col.ID {
+			return pgerror.NewErrorf(pgerror.CodeObjectNotInPrerequisiteStateError,
+				"column %q in the middle of a type change", col.Name)
+		}
+	}
+	return nil
+}
+
+// performComputedColumnSwap replaces a column with the computed column that
+// has been backfilled from it. The new column takes the name, the position
+// and the default value of the old column and stops being computed. The old
+// column and the indexes that have been rebuilt on the new column are
+// queued up to be dropped by a new mutation, and the rebuilt indexes take
+// their names. The old column and indexes take the names of the new ones, so
+// that the names remain unique.
+func (desc *MutableTableDescriptor) performComputedColumnSwap(swap *ComputedColumnSwap) error {
+	oldColIdx, newColIdx := -1, -1
+	for i := range desc.Columns {
+		switch desc.Columns[i].ID {
+		case swap.OldColumnID:
+			oldColIdx = i
+		case swap.NewColumnID:
+			newColIdx = i
+		}
+	}
+	if oldColIdx == -1 || newColIdx == -1 {
+		return errors.Errorf("column %d or %d not found", swap.OldColumnID, swap.NewColumnID)
+	}
+	oldCol, newCol := desc.Columns[oldColIdx], desc.Columns[newColIdx]
+	desc.RenameColumnDescriptor(oldCol, newCol.Name)
+	desc.RenameColumnDescriptor(newCol, oldCol.Name)
+	oldCol, newCol = desc.Columns[oldColIdx], desc.Columns[newColIdx]
+
+	newCol.ComputeExpr = nil
+	newCol.DefaultExpr = swap.NewDefaultExpr
+	newCol.Hidden = oldCol.Hidden
+	// The old column is still read and written by the nodes that have yet to
+	// see the swap, so the others compute it from the new column until it is
+	// dropped.
+	oldCol.ComputeExpr = swap.OldComputeExpr
+	oldCol.DefaultExpr = nil
+	desc.Columns[oldColIdx] = newCol
+	desc.Columns = append(desc.Columns[:newColIdx], desc.Columns[newColIdx+1:]...)
+	// The new column takes the place of the old one in its family too.
+	for i := range desc.Families {
+		family := &desc.Families[i]
+		oldPos, newPos := -1, -1
+		for j, id := range family.ColumnIDs {
+			switch id {
+			case oldCol.ID:
+				oldPos = j
+			case newCol.ID:
+				newPos = j
+			}
+		}
+		if oldPos != -1 && newPos != -1 {
+			family.ColumnIDs[oldPos], family.ColumnIDs[newPos] = family.ColumnIDs[newPos], family.ColumnIDs[oldPos]
+			family.ColumnNames[oldPos], family.ColumnNames[newPos] = family.ColumnNames[newPos], family.ColumnNames[oldPos]
+		}
+	}
+
+	var droppedIndexes []IndexDescriptor
+	for i, oldIndexID := range swap.OldIndexIDs {
+		oldIdx, newIdx := -1, -1
+		for j := range desc.Indexes {
+			switch desc.Indexes[j].ID {
+			case oldIndexID:
+				oldIdx = j
+			case swap.NewIndexIDs[i]:
+				newIdx = j
+			}
+		}
+		if oldIdx == -1 || newIdx == -1 {
+			return errors.Errorf("index %d or %d not found", oldIndexID, swap.NewIndexIDs[i])
+		}
+		oldIndex, newIndex := desc.Indexes[oldIdx], desc.Indexes[newIdx]
+		oldIndex.Name, newIndex.Name = newIndex.Name, oldIndex.Name
+		droppedIndexes = append(droppedIndexes, oldIndex)
+		desc.Indexes[oldIdx] = newIndex
+		desc.Indexes = append(desc.Indexes[:newIdx], desc.Indexes[newIdx+1:]...)
+	}
+
+	desc.AddColumnMutation(oldCol, DescriptorMutation_DROP)
+	for i := range droppedIndexes {
+		desc.addMutation(DescriptorMutation{
+			Descriptor_: &DescriptorMutation_Index{Index: &droppedIndexes[i]},
+			Direction:   DescriptorMutation_DROP,
+		})
+	}
+	return nil
+}
+
+// AddMaterializedViewRefreshMutation adds a mutation to desc.Mutations that
+// replaces the contents of a materialized view. The indexes of the view are
+// copied with new IDs, to be filled by the schema changer.
+func (desc *MutableTableDescriptor) AddMaterializedViewRefreshMutation() {
+	refresh := &MaterializedViewRefresh{NewPrimaryIndex: desc.PrimaryIndex}
+	refresh.NewPrimaryIndex.ID = desc.NextIndexID
+	desc.NextIndexID++
+	for _, idx := range desc.Indexes {
+		refresh.OldIndexIDs = append(refresh.OldIndexIDs, idx.ID)
+		idx.ID = desc.NextIndexID
+		desc.NextIndexID++
+		refresh.NewIndexes = append(refresh.NewIndexes, idx)
+	}
+	m := DescriptorMutation{
+		Descriptor_: &DescriptorMutation_MaterializedViewRefresh{MaterializedViewRefresh: refresh},
+		Direction:   DescriptorMutation_ADD,
+	}
+	desc.addMutation(m)
+}
+
+// IndexesDroppedByRefresh returns the IDs of the indexes whose data is no
+// longer used once the materialized view refresh m completes: the indexes
+// of the view that it replaces and the new indexes of the indexes dropped in
+// the meantime, or all the new indexes if the refresh was rolled back. It
+// must be called before the mutation is made complete.
+func (desc *TableDescriptor) IndexesDroppedByRefresh(m *DescriptorMutation) []IndexID {
+	refresh := m.GetMaterializedViewRefresh()
+	if m.Direction == DescriptorMutation_DROP {
+		ids := []IndexID{refresh.NewPrimaryIndex.ID}
+		for i := range refresh.NewIndexes {
+			ids = append(ids, refresh.NewIndexes[i].ID)
+		}
+		return ids
+	}
+	ids := []IndexID{desc.PrimaryIndex.ID}
+	for i, oldID := range refresh.OldIndexIDs {
+		replaced := false
+		for j := range desc.Indexes {
+			if desc.Indexes[j].ID == oldID {
+				replaced = true
+				break
+			}
+		}
+		if replaced {
+			ids = append(ids, oldID)
+		} else {
+			ids = append(ids, refresh.NewIndexes[i].ID)
+		}
+	}
+	return ids
+}
+
+// performMaterializedViewRefresh replaces the indexes of a materialized view
+// with the indexes filled by a refresh. The new indexes take the names of the
+// indexes that they replace, which may have been renamed in the meantime;
+// the new indexes of the indexes dropped in the meantime are left out.
+func (desc *MutableTableDescriptor) performMaterializedViewRefresh(refresh *MaterializedViewRefresh) {
+	newPrimary := refresh.NewPrimaryIndex
+	newPrimary.Name = desc.PrimaryIndex.Name
+	desc.PrimaryIndex = newPrimary
+	indexes := make([]IndexDescriptor, 0, len(desc.Indexes))
+	for _, idx := range desc.Indexes {
+		for i, oldID := range refresh.OldIndexIDs {
+			if oldID == idx.ID {
+				newIdx := refresh.NewIndexes[i]
+				newIdx.Name = idx.Name
+				indexes = append(indexes, newIdx)
+				break
+			}
+		}
+	}
+	desc.Indexes = indexes
+}
+
+// MakeNotNullCheckConstraint creates the hidden check constraint used to
+// validate a column being made NOT NULL. The name of the constraint is
+// chosen so as not to conflict with inuseNames, to which it is added.
+func MakeNotNullCheckConstraint(
+	colName string, colID ColumnID, inuseNames map[string]struct{}, validity ConstraintValidity,
+) *TableDescriptor_CheckConstraint {
+	name := fmt.Sprintf("%s_auto_not_null", colName)
+	if _, ok := inuseNames[name]; ok {
+		for i := 1; ; i++ {
+			candidate := fmt.Sprintf("%s%d", name, i)
+			if _, ok := inuseNames[candidate]; !ok {
+				name = candidate
+				break
+			}
+		}
+	}
+	if inuseNames != nil {
+		inuseNames[name] = struct{}{}
+	}
+	expr := &tree.ComparisonExpr{
+		Operator: tree.IsDistinctFrom,
+		Left:     &tree.ColumnItem{ColumnName: tree.Name(colName)},
+		Right:    tree.DNull,
+	}
+	return &TableDescriptor_CheckConstraint{
+		Name:                name,
+		Expr:                tree.Serialize(expr),
+		Validity:            validity,
+		ColumnIDs:           []ColumnID{colID},
+		IsNonNullConstraint: true,
+	}
+}
+
 func (desc *MutableTableDescriptor) addMutation(m DescriptorMutation) {
 	switch m.Direction {
 	case DescriptorMutation_ADD:
@@ -2263,6 +2665,35 @@ func (desc *TableDescriptor) HasDrainingNames() bool {
 	return len(desc.DrainingNames) > 0
 }
 
+// HasReadOnlyEnumMembers returns true if a column, including the columns
+// being added or dropped, has an ENUM member that is being added to its type.
+func (desc *TableDescriptor) HasReadOnlyEnumMembers() bool {
+	for i := range desc.Columns {
+		if desc.Columns[i].Type.HasReadOnlyEnumMembers() {
+			return true
+		}
+	}
+	for i := range desc.Mutations {
+		if col := desc.Mutations[i].GetColumn(); col != nil && col.Type.HasReadOnlyEnumMembers() {
+			return true
+		}
+	}
+	return false
+}
+
+// MakeEnumMembersWritable allows the ENUM members that are being added to the
+// types of the columns of the table to be written.
+func (desc *MutableTableDescriptor) MakeEnumMembersWritable() {
+	for i := range desc.Columns {
+		desc.Columns[i].Type.MakeEnumMembersWritable()
+	}
+	for i := range desc.Mutations {
+		if col := desc.Mutations[i].GetColumn(); col != nil {
+			col.Type.MakeEnumMembersWritable()
+		}
+	}
+}
+
 // VisibleColumns returns all non hidden columns.
 func (desc *TableDescriptor) VisibleColumns() []ColumnDescriptor {
 	var cols []ColumnDescriptor
@@ -2354,6 +2785,12 @@ func (desc *Descriptor) GetID() ID {
 		return t.Table.ID
 	case *Descriptor_Database:
 		return t.Database.ID
+	case *Descriptor_Type:
+		return t.Type.ID
+	case *Descriptor_Schema:
+		return t.Schema.ID
+	case *Descriptor_Function:
+		return t.Function.ID
 	default:
 		return 0
 	}
@@ -2366,6 +2803,12 @@ func (desc *Descriptor) GetName() string {
 		return t.Table.Name
 	case *Descriptor_Database:
 		return t.Database.Name
+	case *Descriptor_Type:
+		return t.Type.Name
+	case *Descriptor_Schema:
+		return t.Schema.Name
+	case *Descriptor_Function:
+		return t.Function.Name
 	default:
 		return ""
 	}
@@ -2429,7 +2872,7 @@ func (desc TableDescriptor) GetDescMetadataKey() roachpb.Key {
 
 // GetNameMetadataKey returns the namespace key for the table.
 func (desc TableDescriptor) GetNameMetadataKey() roachpb.Key {
-	return MakeNameMetadataKey(desc.ParentID, desc.Name)
+	return MakeNameMetadataKey(desc.GetNamespaceParentID(), desc.Name)
 }
 
 // SQLString returns the SQL statement describing the column.
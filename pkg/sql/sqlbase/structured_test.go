@@ -1088,7 +1088,10 @@ func TestColumnTypeSQLString(t *testing.T) {
 		{ColumnType{SemanticType: ColumnType_DECIMAL, Precision: 6}, "DECIMAL(6)"},
 		{ColumnType{SemanticType: ColumnType_DECIMAL, Precision: 7, Width: 8}, "DECIMAL(7,8)"},
 		{ColumnType{SemanticType: ColumnType_DATE}, "DATE"},
+		{ColumnType{SemanticType: ColumnType_TIME, Precision: 0, TimePrecisionIsSet: true}, "TIME(0)"},
 		{ColumnType{SemanticType: ColumnType_TIMESTAMP}, "TIMESTAMP"},
+		{ColumnType{SemanticType: ColumnType_TIMESTAMP, Precision: 3, TimePrecisionIsSet: true}, "TIMESTAMP(3)"},
+		{ColumnType{SemanticType: ColumnType_TIMESTAMPTZ, Precision: 6, TimePrecisionIsSet: true}, "TIMESTAMPTZ(6)"},
 		{ColumnType{SemanticType: ColumnType_INTERVAL}, "INTERVAL"},
 		{ColumnType{SemanticType: ColumnType_STRING}, "STRING"},
 		{ColumnType{SemanticType: ColumnType_STRING, Width: 10}, "STRING(10)"},
@@ -110,6 +110,11 @@ func MakeColumnDefDescs(
 		Nullable: d.Nullable.Nullability != tree.NotNull && !d.PrimaryKey,
 	}
 
+	// Resolve the user-defined types referenced by the column type.
+	if err := tree.ResolveColumnType(semaCtx, d.Type); err != nil {
+		return nil, nil, nil, err
+	}
+
 	// Set Type.SemanticType and Type.Locale.
 	colDatumType := coltypes.CastTargetToDatumType(d.Type)
 	colTyp, err := DatumTypeToColumnType(colDatumType)
@@ -347,6 +352,11 @@ func (desc *TableDescriptor) collectConstraintInfo(
 	}
 
 	for _, c := range desc.Checks {
+		// Hidden NOT NULL constraints are not reported: they only exist while
+		// a column is being made NOT NULL.
+		if c.IsNonNullConstraint {
+			continue
+		}
 		if _, ok := info[c.Name]; ok {
 			return nil, errors.Errorf("duplicate constraint name: %q", c.Name)
 		}
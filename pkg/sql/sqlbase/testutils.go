@@ -36,6 +36,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/randutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/pkg/errors"
 )
@@ -139,6 +140,10 @@ func RandDatumWithNullChance(rng *rand.Rand, typ ColumnType, nullChance int) tre
 			return nil
 		}
 		return &tree.DJSON{JSON: j}
+	case ColumnType_TSVECTOR:
+		return tree.NewDTSVector(tsearch.RandomTSVector(rng))
+	case ColumnType_TSQUERY:
+		return tree.NewDTSQuery(tsearch.RandomTSQuery(rng))
 	case ColumnType_TUPLE:
 		tuple := tree.DTuple{D: make(tree.Datums, len(typ.TupleContents))}
 		for i, internalType := range typ.TupleContents {
@@ -228,6 +233,11 @@ var (
 
 func init() {
 	for k := range ColumnType_SemanticType_name {
+		if ColumnType_SemanticType(k) == ColumnType_ENUM {
+			// The values of ENUM types depend on the members of a user-defined
+			// type, which random column types don't have.
+			continue
+		}
 		columnSemanticTypes = append(columnSemanticTypes, ColumnType_SemanticType(k))
 	}
 	for _, t := range types.AnyNonArray {
@@ -0,0 +1,174 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sqlbase
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+)
+
+// SetID implements the DescriptorProto interface.
+func (desc *TypeDescriptor) SetID(id ID) {
+	desc.ID = id
+}
+
+// TypeName returns the plain type of this descriptor.
+func (desc *TypeDescriptor) TypeName() string {
+	return "type"
+}
+
+// SetName implements the DescriptorProto interface.
+func (desc *TypeDescriptor) SetName(name string) {
+	desc.Name = name
+}
+
+// GetAuditMode is part of the DescriptorProto interface.
+// Types cannot be audited.
+func (desc *TypeDescriptor) GetAuditMode() TableDescriptor_AuditMode {
+	return TableDescriptor_DISABLED
+}
+
+// Validate validates that the type descriptor is well formed. Checks
+// include validating the type name, and verifying that the members have
+// distinct labels and physical representations that sort in declaration
+// order.
+func (desc *TypeDescriptor) Validate() error {
+	if err := validateName(desc.Name, "type"); err != nil {
+		return err
+	}
+	if desc.ID == 0 {
+		return fmt.Errorf("invalid type ID %d", desc.ID)
+	}
+	labels := make(map[string]struct{}, len(desc.EnumMembers))
+	for i := range desc.EnumMembers {
+		m := &desc.EnumMembers[i]
+		if _, ok := labels[m.LogicalRepresentation]; ok {
+			return fmt.Errorf("duplicate enum label %q", m.LogicalRepresentation)
+		}
+		labels[m.LogicalRepresentation] = struct{}{}
+		if i > 0 && bytes.Compare(desc.EnumMembers[i-1].PhysicalRepresentation, m.PhysicalRepresentation) >= 0 {
+			return fmt.Errorf("enum label %q is not ordered after %q",
+				m.LogicalRepresentation, desc.EnumMembers[i-1].LogicalRepresentation)
+		}
+	}
+	return desc.Privileges.Validate(desc.GetID())
+}
+
+// DatumType returns the datum type of the values of the type.
+func (desc *TypeDescriptor) DatumType() types.TEnum {
+	c := ColumnType{
+		SemanticType: ColumnType_ENUM,
+		EnumTypeID:   desc.ID,
+		EnumTypeName: desc.Name,
+		EnumMembers:  desc.EnumMembers,
+	}
+	return c.enumDatumType()
+}
+
+// HasEnumMember returns whether the type has a member with the given label.
+func (desc *TypeDescriptor) HasEnumMember(label string) bool {
+	return desc.enumMemberIdx(label) != -1
+}
+
+func (desc *TypeDescriptor) enumMemberIdx(label string) int {
+	for i := range desc.EnumMembers {
+		if desc.EnumMembers[i].LogicalRepresentation == label {
+			return i
+		}
+	}
+	return -1
+}
+
+// MakeEnumMembers returns the members of a new ENUM type with the given
+// labels. The physical representations are spread over the range of a single
+// byte when possible, which leaves room to add members between them later.
+func MakeEnumMembers(labels []string) []EnumMember {
+	members := make([]EnumMember, len(labels))
+	var prev []byte
+	for i, label := range labels {
+		var rep []byte
+		if len(labels) < 255 {
+			rep = []byte{byte((i + 1) * 256 / (len(labels) + 1))}
+		} else {
+			rep = enumBytesBetween(prev, nil)
+		}
+		members[i] = EnumMember{PhysicalRepresentation: rep, LogicalRepresentation: label}
+		prev = rep
+	}
+	return members
+}
+
+// AddEnumMember adds a member with the given label to the type and returns
+// it. If neighbor is non-empty, the new member is placed immediately before
+// or after (according to before) the existing member with that label;
+// otherwise it is placed after all the existing members.
+func (desc *TypeDescriptor) AddEnumMember(label, neighbor string, before bool) (EnumMember, error) {
+	if desc.HasEnumMember(label) {
+		return EnumMember{}, fmt.Errorf("enum label %q already exists", label)
+	}
+	// pos is the index at which the new member is inserted.
+	pos := len(desc.EnumMembers)
+	if neighbor != "" {
+		idx := desc.enumMemberIdx(neighbor)
+		if idx == -1 {
+			return EnumMember{}, fmt.Errorf("%q is not an existing enum label", neighbor)
+		}
+		pos = idx
+		if !before {
+			pos++
+		}
+	}
+	var lo, hi []byte
+	if pos > 0 {
+		lo = desc.EnumMembers[pos-1].PhysicalRepresentation
+	}
+	if pos < len(desc.EnumMembers) {
+		hi = desc.EnumMembers[pos].PhysicalRepresentation
+	}
+	m := EnumMember{PhysicalRepresentation: enumBytesBetween(lo, hi), LogicalRepresentation: label}
+	desc.EnumMembers = append(desc.EnumMembers, EnumMember{})
+	copy(desc.EnumMembers[pos+1:], desc.EnumMembers[pos:])
+	desc.EnumMembers[pos] = m
+	return m, nil
+}
+
+// enumBytesBetween returns a byte string that sorts strictly between lo and
+// hi. A nil hi stands for an upper bound larger than any byte string. The
+// result never ends in a zero byte, so that there is always room for another
+// byte string below it.
+func enumBytesBetween(lo, hi []byte) []byte {
+	var res []byte
+	for i := 0; ; i++ {
+		l := 0
+		if i < len(lo) {
+			l = int(lo[i])
+		}
+		h := 256
+		if hi != nil && i < len(hi) {
+			h = int(hi[i])
+		}
+		if h-l > 1 {
+			return append(res, byte((l+h)/2))
+		}
+		res = append(res, byte(l))
+		if l < h {
+			// res is now a prefix that sorts before hi, so hi no longer bounds
+			// the remaining bytes.
+			hi = nil
+		}
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/pkg/errors"
@@ -93,7 +94,7 @@ func GetKeysForTableDescriptor(
 	tableDesc *sqlbase.TableDescriptor,
 ) (zoneKey roachpb.Key, nameKey roachpb.Key, descKey roachpb.Key) {
 	zoneKey = config.MakeZoneKey(uint32(tableDesc.ID))
-	nameKey = sqlbase.MakeNameMetadataKey(tableDesc.ParentID, tableDesc.GetName())
+	nameKey = sqlbase.MakeNameMetadataKey(tableDesc.GetNamespaceParentID(), tableDesc.GetName())
 	descKey = sqlbase.MakeDescMetadataKey(tableDesc.ID)
 	return
 }
@@ -200,13 +201,6 @@ func (tc *TableCollection) getMutableTableDescriptor(
 		log.Infof(ctx, "reading mutable descriptor on table '%s'", tn)
 	}
 
-	if tn.SchemaName != tree.PublicSchemaName {
-		if flags.required {
-			return nil, nil, sqlbase.NewUnsupportedSchemaUsageError(tree.ErrString(tn))
-		}
-		return nil, nil, nil
-	}
-
 	refuseFurtherLookup, dbID, err := tc.getUncommittedDatabaseID(tn.Catalog(), flags.required)
 	if refuseFurtherLookup || err != nil {
 		return nil, nil, err
@@ -223,7 +217,19 @@ func (tc *TableCollection) getMutableTableDescriptor(
 		}
 	}
 
-	if refuseFurtherLookup, table, err := tc.getUncommittedTable(dbID, tn, flags.required); refuseFurtherLookup || err != nil {
+	parentID, err := getNamespaceParentID(ctx, txn, dbID, tn.Schema())
+	if err != nil {
+		return nil, nil, err
+	}
+	if parentID == 0 {
+		// The schema does not exist.
+		if flags.required {
+			return nil, nil, sqlbase.NewUndefinedRelationError(tn)
+		}
+		return nil, nil, nil
+	}
+
+	if refuseFurtherLookup, table, err := tc.getUncommittedTable(parentID, tn, flags.required); refuseFurtherLookup || err != nil {
 		return nil, nil, err
 	} else if mut := table.MutableTableDescriptor; mut != nil {
 		log.VEventf(ctx, 2, "found uncommitted table %d", mut.ID)
@@ -256,13 +262,6 @@ func (tc *TableCollection) getTableVersion(
 		log.Infof(ctx, "planner acquiring lease on table '%s'", tn)
 	}
 
-	if tn.SchemaName != tree.PublicSchemaName {
-		if flags.required {
-			return nil, nil, sqlbase.NewUnsupportedSchemaUsageError(tree.ErrString(tn))
-		}
-		return nil, nil, nil
-	}
-
 	refuseFurtherLookup, dbID, err := tc.getUncommittedDatabaseID(tn.Catalog(), flags.required)
 	if refuseFurtherLookup || err != nil {
 		return nil, nil, err
@@ -279,6 +278,18 @@ func (tc *TableCollection) getTableVersion(
 		}
 	}
 
+	parentID, err := getNamespaceParentID(ctx, txn, dbID, tn.Schema())
+	if err != nil {
+		return nil, nil, err
+	}
+	if parentID == 0 {
+		// The schema does not exist.
+		if flags.required {
+			return nil, nil, sqlbase.NewUndefinedRelationError(tn)
+		}
+		return nil, nil, nil
+	}
+
 	// TODO(vivek): Ideally we'd avoid caching for only the
 	// system.descriptor and system.lease tables, because they are
 	// used for acquiring leases, creating a chicken&egg problem.
@@ -289,7 +300,7 @@ func (tc *TableCollection) getTableVersion(
 	avoidCache := flags.avoidCached || testDisableTableLeases ||
 		(tn.Catalog() == sqlbase.SystemDB.Name && tn.TableName.String() != sqlbase.RoleMembersTable.Name)
 
-	if refuseFurtherLookup, table, err := tc.getUncommittedTable(dbID, tn, flags.required); refuseFurtherLookup || err != nil {
+	if refuseFurtherLookup, table, err := tc.getUncommittedTable(parentID, tn, flags.required); refuseFurtherLookup || err != nil {
 		return nil, nil, err
 	} else if immut := table.ImmutableTableDescriptor; immut != nil {
 		// If not forcing to resolve using KV, tables being added aren't visible.
@@ -314,7 +325,10 @@ func (tc *TableCollection) getTableVersion(
 		return obj.(*sqlbase.ImmutableTableDescriptor), db, err
 	}
 
-	if avoidCache {
+	// The objects of temporary schemas are only used by the session that owns
+	// them, which is also the only session that can modify them, so they are
+	// not leased.
+	if avoidCache || sessiondata.IsTemporarySchemaName(tn.Schema()) {
 		return readTableFromStore()
 	}
 
@@ -324,14 +338,14 @@ func (tc *TableCollection) getTableVersion(
 	// transaction.
 	for _, table := range tc.leasedTables {
 		if table.Name == string(tn.TableName) &&
-			table.ParentID == dbID {
+			table.GetNamespaceParentID() == parentID {
 			log.VEventf(ctx, 2, "found table in table collection for table '%s'", tn)
 			return table, nil, nil
 		}
 	}
 
 	origTimestamp := txn.OrigTimestamp()
-	table, expiration, err := tc.leaseMgr.AcquireByName(ctx, origTimestamp, dbID, tn.Table())
+	table, expiration, err := tc.leaseMgr.AcquireByName(ctx, origTimestamp, parentID, tn.Table())
 	if err != nil {
 		// Read the descriptor from the store in the face of some specific errors
 		// because of a known limitation of AcquireByName. See the known
@@ -576,8 +590,12 @@ func (tc *TableCollection) getUncommittedDatabaseID(
 // a known deletion of that table, so it would be invalid to miss the
 // cache and go to KV (where the descriptor prior to the DROP may
 // still exist).
+//
+// parentID is the ID under which the name of the table is stored in
+// system.namespace: the ID of the database, or the ID of the temporary
+// schema for temporary tables.
 func (tc *TableCollection) getUncommittedTable(
-	dbID sqlbase.ID, tn *tree.TableName, required bool,
+	parentID sqlbase.ID, tn *tree.TableName, required bool,
 ) (refuseFurtherLookup bool, table uncommittedTable, err error) {
 	// Walk latest to earliest so that a DROP TABLE followed by a CREATE TABLE
 	// with the same name will result in the CREATE TABLE being seen.
@@ -591,7 +609,7 @@ func (tc *TableCollection) getUncommittedTable(
 		// effect of it.
 		for _, drain := range mutTbl.DrainingNames {
 			if drain.Name == string(tn.TableName) &&
-				drain.ParentID == dbID {
+				drain.ParentID == parentID {
 				// Table name has gone away.
 				if required {
 					// If it's required here, say it doesn't exist.
@@ -605,7 +623,7 @@ func (tc *TableCollection) getUncommittedTable(
 
 		// Do we know about a table with this name?
 		if mutTbl.Name == string(tn.TableName) &&
-			mutTbl.ParentID == dbID {
+			mutTbl.GetNamespaceParentID() == parentID {
 			// Right state?
 			if err = filterTableState(mutTbl.TableDesc()); err != nil && err != errTableAdding {
 				if !required {
@@ -35,6 +35,10 @@ type tableUpserterBase struct {
 	ri    row.Inserter
 	alloc *sqlbase.DatumAlloc
 
+	// deferredFKChecks is the queue of the checks of the deferred foreign
+	// key constraints of the transaction, if any, for the updater.
+	deferredFKChecks *row.DeferredFKChecks
+
 	// Should we collect the rows for a RETURNING clause?
 	collectRows bool
 
@@ -248,6 +252,10 @@ type tableUpserter struct {
 	conflictIndex sqlbase.IndexDescriptor
 	anyComputed   bool
 
+	// conflictIndexPred evaluates the predicate of conflictIndex if it is a
+	// partial index. The rows that do not satisfy it cannot conflict.
+	conflictIndexPred sqlbase.PartialIndexPredicates
+
 	evalCtx *tree.EvalContext
 
 	// These are set for ON CONFLICT DO UPDATE, but not for DO NOTHING
@@ -287,6 +295,12 @@ func (tu *tableUpserter) init(txn *client.Txn, evalCtx *tree.EvalContext) error
 
 	tableDesc := tu.tableDesc()
 
+	if err := tu.conflictIndexPred.Init(
+		tableDesc.TableDesc(), []sqlbase.IndexDescriptor{tu.conflictIndex},
+	); err != nil {
+		return err
+	}
+
 	requestedCols := tableDesc.Columns
 
 	if len(tu.updateCols) == 0 {
@@ -306,6 +320,7 @@ func (tu *tableUpserter) init(txn *client.Txn, evalCtx *tree.EvalContext) error
 		if err != nil {
 			return err
 		}
+		tu.ru.SetDeferredFKChecks(tu.deferredFKChecks)
 
 		// t.ru.fetchCols can also contain columns undergoing mutation.
 		tu.fetchCols = tu.ru.FetchCols
@@ -818,8 +833,20 @@ func (tu *tableUpserter) upsertRowPKSpans(
 	// case, some spots in the slice will be nil (indicating no conflict) and the
 	// others will be conflicting rows.
 	b := tu.txn.NewBatch()
+	// rowIdxs contains the index in tu.insertRows of the row looked up by
+	// each request of the batch.
+	rowIdxs := make([]int, 0, tu.insertRows.Len())
 	for i := 0; i < tu.insertRows.Len(); i++ {
 		insertRow := tu.insertRows.At(i)
+		inIndex, err := tu.conflictIndexPred.RowInIndex(0, tu.ri.InsertColIDtoRowIndex, insertRow)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !inIndex {
+			// The row does not belong to the partial conflict index, so it
+			// cannot conflict with the rows in it.
+			continue
+		}
 		entries, err := sqlbase.EncodeSecondaryIndex(
 			tableDesc.TableDesc(), &tu.conflictIndex, tu.ri.InsertColIDtoRowIndex, insertRow)
 		if err != nil {
@@ -831,6 +858,7 @@ func (tu *tableUpserter) upsertRowPKSpans(
 				log.VEventf(ctx, 2, "Get %s", entry.Key)
 			}
 			b.Get(entry.Key)
+			rowIdxs = append(rowIdxs, i)
 		}
 	}
 
@@ -838,7 +866,8 @@ func (tu *tableUpserter) upsertRowPKSpans(
 		return nil, nil, err
 	}
 	conflictingPKs := make(map[int]roachpb.Key)
-	for i, result := range b.Results {
+	for j, result := range b.Results {
+		i := rowIdxs[j]
 		if len(result.Rows) == 1 {
 			if result.Rows[0].Value != nil {
 				upsertRowPK, err := sqlbase.ExtractIndexKey(tu.alloc, tableDesc.TableDesc(), result.Rows[0])
@@ -95,7 +95,11 @@ func (tu *optTableUpserter) init(txn *client.Txn, evalCtx *tree.EvalContext) err
 		evalCtx,
 		tu.alloc,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	tu.ru.SetDeferredFKChecks(tu.deferredFKChecks)
+	return nil
 }
 
 // desc is part of the tableWriter interface.
@@ -106,6 +106,10 @@ func (tu *strictTableUpserter) getUniqueIndexes() (err error) {
 	tableDesc := tu.tableDesc()
 	indexes := tableDesc.Indexes
 	for _, index := range indexes {
+		if index.Unique && index.IsPartial() {
+			return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+				"ON CONFLICT DO NOTHING is not supported on tables with partial unique indexes")
+		}
 		if index.Unique {
 			tu.conflictIndexes = append(tu.conflictIndexes, index)
 		}
@@ -0,0 +1,471 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logtags"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uint128"
+	"github.com/pkg/errors"
+)
+
+// Temporary tables, views and sequences live in a temporary schema that
+// belongs to the session that created them. The temporary schema of a session
+// has the same name in every database, pg_temp_<session ID>, and the
+// pg_temp alias always refers to the temporary schema of the current session.
+//
+// A temporary schema is not described by a descriptor. It is an entry in
+// system.namespace that maps the name of the schema, under the ID of its
+// database, to a unique ID. The names of the objects in the schema are stored
+// under that ID instead of the ID of the database, which keeps them separate
+// from the names of the objects in the public schema and from the names of the
+// objects of other sessions.
+//
+// The objects in a temporary schema are dropped when the session that owns
+// it is closed, or by the TemporaryObjectCleaner if the session disappeared
+// without cleaning up after itself, for example because its node crashed.
+
+// TemporaryObjectCleanupInterval is the interval at which each node looks for
+// the temporary schemas of sessions that are no longer alive.
+var TemporaryObjectCleanupInterval = settings.RegisterNonNegativeDurationSetting(
+	"sql.temp_object_cleaner.cleanup_interval",
+	"how often to clean up the temporary objects of sessions that are no longer alive",
+	30*time.Minute,
+)
+
+// temporarySchemaName returns the name of the temporary schema of the session
+// with the given ID.
+func temporarySchemaName(sessionID ClusterWideID) string {
+	return fmt.Sprintf("%s_%d_%d", sessiondata.PgTempSchemaName, sessionID.Hi, sessionID.Lo)
+}
+
+// temporarySchemaSessionID returns the ID of the session that owns the
+// temporary schema with the given name.
+func temporarySchemaSessionID(scName string) (ClusterWideID, error) {
+	parts := strings.Split(strings.TrimPrefix(scName, sessiondata.PgTempSchemaName+"_"), "_")
+	if len(parts) != 2 {
+		return ClusterWideID{}, errors.Errorf("malformed temporary schema name %q", scName)
+	}
+	hi, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return ClusterWideID{}, errors.Wrapf(err, "malformed temporary schema name %q", scName)
+	}
+	lo, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return ClusterWideID{}, errors.Wrapf(err, "malformed temporary schema name %q", scName)
+	}
+	return ClusterWideID{Uint128: uint128.FromInts(hi, lo)}, nil
+}
+
+// getTemporarySchemaID returns the ID of the temporary schema with the given
+// name in the given database, or 0 if the schema does not exist.
+func getTemporarySchemaID(
+	ctx context.Context, txn *client.Txn, dbID sqlbase.ID, scName string,
+) (sqlbase.ID, error) {
+	gr, err := txn.Get(ctx, tableKey{parentID: dbID, name: scName}.Key())
+	if err != nil {
+		return 0, err
+	}
+	if !gr.Exists() {
+		return 0, nil
+	}
+	return sqlbase.ID(gr.ValueInt()), nil
+}
+
+// temporarySchema is a temporary schema of a database.
+type temporarySchema struct {
+	dbID   sqlbase.ID
+	dbName string
+	name   string
+	id     sqlbase.ID
+}
+
+// nameKey returns the key of the system.namespace entry of the schema.
+func (sc temporarySchema) nameKey() roachpb.Key {
+	return tableKey{parentID: sc.dbID, name: sc.name}.Key()
+}
+
+// getTemporarySchemasInDatabase returns the temporary schemas of the given
+// database. If scName is not empty, only the schema with that name is
+// returned.
+func getTemporarySchemasInDatabase(
+	ctx context.Context, txn *client.Txn, dbID sqlbase.ID, dbName string, scName string,
+) ([]temporarySchema, error) {
+	if scName != "" {
+		id, err := getTemporarySchemaID(ctx, txn, dbID, scName)
+		if err != nil || id == 0 {
+			return nil, err
+		}
+		return []temporarySchema{{dbID: dbID, dbName: dbName, name: scName, id: id}}, nil
+	}
+
+	prefix := sqlbase.MakeNameMetadataKey(dbID, "")
+	sr, err := txn.Scan(ctx, prefix, prefix.PrefixEnd(), 0)
+	if err != nil {
+		return nil, err
+	}
+	var schemas []temporarySchema
+	for _, row := range sr {
+		_, name, err := encoding.DecodeUnsafeStringAscending(bytes.TrimPrefix(row.Key, prefix), nil)
+		if err != nil {
+			return nil, err
+		}
+		if !sessiondata.IsTemporarySchemaName(name) {
+			continue
+		}
+		schemas = append(schemas, temporarySchema{
+			dbID: dbID, dbName: dbName, name: name, id: sqlbase.ID(row.ValueInt()),
+		})
+	}
+	return schemas, nil
+}
+
+// getTemporarySchemas returns the temporary schemas of all the databases. If
+// scName is not empty, only the schemas with that name are returned.
+func getTemporarySchemas(
+	ctx context.Context, txn *client.Txn, scName string,
+) ([]temporarySchema, error) {
+	prefix := sqlbase.MakeNameMetadataKey(keys.RootNamespaceID, "")
+	sr, err := txn.Scan(ctx, prefix, prefix.PrefixEnd(), 0)
+	if err != nil {
+		return nil, err
+	}
+	var schemas []temporarySchema
+	for _, row := range sr {
+		_, dbName, err := encoding.DecodeUnsafeStringAscending(bytes.TrimPrefix(row.Key, prefix), nil)
+		if err != nil {
+			return nil, err
+		}
+		dbSchemas, err := getTemporarySchemasInDatabase(
+			ctx, txn, sqlbase.ID(row.ValueInt()), dbName, scName)
+		if err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, dbSchemas...)
+	}
+	return schemas, nil
+}
+
+// temporarySchemaName returns the name of the temporary schema of the
+// session. Session-bound internal executors inherit the temporary schema of
+// the session they are bound to.
+func (p *planner) temporarySchemaName() string {
+	if scName := p.SessionData().SearchPath.GetTemporarySchemaName(); scName != "" {
+		return scName
+	}
+	return temporarySchemaName(p.ExtendedEvalContext().SessionID)
+}
+
+// isOtherSessionTemporarySchema returns true if the given schema name is
+// the name of the temporary schema of another session. These schemas are
+// invisible to the current session.
+func (p *planner) isOtherSessionTemporarySchema(scName string) bool {
+	return scName != sessiondata.PgTempSchemaName &&
+		sessiondata.IsTemporarySchemaName(scName) &&
+		scName != p.SessionData().SearchPath.GetTemporarySchemaName()
+}
+
+// getOrCreateTemporarySchema returns the ID of the temporary schema of the
+// session in the given database, creating the schema if it does not exist.
+func (p *planner) getOrCreateTemporarySchema(
+	ctx context.Context, dbID sqlbase.ID,
+) (sqlbase.ID, error) {
+	scName := p.temporarySchemaName()
+	id, err := getTemporarySchemaID(ctx, p.txn, dbID, scName)
+	if err != nil || id != 0 {
+		return id, err
+	}
+	id, err = GenerateUniqueDescID(ctx, p.ExecCfg().DB)
+	if err != nil {
+		return 0, err
+	}
+	key := tableKey{parentID: dbID, name: scName}.Key()
+	if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+		log.VEventf(ctx, 2, "CPut %s -> %d", key, id)
+	}
+	if err := p.txn.CPut(ctx, key, id, nil); err != nil {
+		return 0, err
+	}
+	p.sessionDataMutator.SetTemporarySchemaName(scName)
+	return id, nil
+}
+
+// getCreateParentID returns the ID under which the name of a new table, view
+// or sequence with the given resolved name is stored in system.namespace,
+// along with the ID of its temporary schema if the object is temporary and the
+// descriptor of its schema if the schema is user-defined. The temporary schema
+// of the session is created if it does not exist yet.
+func (p *planner) getCreateParentID(
+	ctx context.Context, dbID sqlbase.ID, tn *tree.TableName,
+) (
+	parentID sqlbase.ID,
+	temporarySchemaID sqlbase.ID,
+	scDesc *sqlbase.SchemaDescriptor,
+	err error,
+) {
+	if sessiondata.IsTemporarySchemaName(tn.Table()) {
+		return 0, 0, nil, pgerror.NewErrorf(pgerror.CodeReservedNameError,
+			"unacceptable name %q", tn.Table()).SetDetailf(
+			"The prefix %q is reserved for temporary schemas.", sessiondata.PgTempSchemaName+"_")
+	}
+	if tn.Schema() == tree.PublicSchema {
+		return dbID, 0, nil, nil
+	}
+	if sessiondata.IsTemporarySchemaName(tn.Schema()) {
+		temporarySchemaID, err = p.getOrCreateTemporarySchema(ctx, dbID)
+		return temporarySchemaID, temporarySchemaID, nil, err
+	}
+	scDesc, err = getSchemaDesc(ctx, p.txn, dbID, tn.Schema())
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	if scDesc == nil {
+		return 0, 0, nil, sqlbase.NewUndefinedSchemaError(tn.Schema())
+	}
+	return scDesc.ID, 0, scDesc, nil
+}
+
+// getTemporaryObjects returns the tables, views and sequences stored in the
+// given temporary schema.
+func (p *planner) getTemporaryObjects(
+	ctx context.Context, sc temporarySchema,
+) ([]toDelete, error) {
+	return p.getSchemaObjects(ctx, sc.dbName, sc.name, sc.id)
+}
+
+// dropTemporarySchemas drops the given temporary schemas, along with the
+// objects they contain.
+func (p *planner) dropTemporarySchemas(
+	params runParams, schemas []temporarySchema, stmt string,
+) error {
+	ctx := params.ctx
+	var td []toDelete
+	for _, sc := range schemas {
+		objects, err := p.getTemporaryObjects(ctx, sc)
+		if err != nil {
+			return err
+		}
+		td = append(td, objects...)
+	}
+	td, err := p.filterCascadedTables(ctx, td)
+	if err != nil {
+		return err
+	}
+	if _, _, err := p.dropTablesAndViews(params, td, stmt, sqlbase.InvalidID); err != nil {
+		return err
+	}
+
+	b := &client.Batch{}
+	for _, sc := range schemas {
+		key := sc.nameKey()
+		if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+			log.VEventf(ctx, 2, "Del %s", key)
+		}
+		b.Del(key)
+	}
+	return p.txn.Run(ctx, b)
+}
+
+// discardTemporarySchemas implements DISCARD TEMPORARY: it drops the
+// temporary schema of the session in every database.
+func (p *planner) discardTemporarySchemas(ctx context.Context, stmt string) error {
+	scName := p.SessionData().SearchPath.GetTemporarySchemaName()
+	if scName == "" {
+		// The session never created temporary objects.
+		return nil
+	}
+	schemas, err := getTemporarySchemas(ctx, p.txn, scName)
+	if err != nil {
+		return err
+	}
+	params := runParams{
+		ctx:             ctx,
+		extendedEvalCtx: &p.extendedEvalCtx,
+		p:               p,
+	}
+	return p.dropTemporarySchemas(params, schemas, stmt)
+}
+
+// temporarySchemaCleanupSessionData returns the session data used to clean up
+// the temporary schema with the given name.
+func temporarySchemaCleanupSessionData(scName string) *sessiondata.SessionData {
+	return &sessiondata.SessionData{
+		User:          security.RootUser,
+		SearchPath:    sqlbase.DefaultSearchPath.WithTemporarySchemaName(scName),
+		SequenceState: sessiondata.NewSequenceState(),
+		DataConversion: sessiondata.DataConversionConfig{
+			Location: time.UTC,
+		},
+	}
+}
+
+// cleanupTemporarySchema drops the temporary objects of a session using an
+// internal executor bound to the temporary schema of the session.
+func cleanupTemporarySchema(ctx context.Context, ie sqlutil.InternalExecutor) error {
+	_, err := ie.Exec(ctx, "cleanup-temp-schema", nil /* txn */, "DISCARD TEMPORARY")
+	return err
+}
+
+// cleanupTemporarySchema drops the temporary objects created by the session,
+// if any. Failures are logged: the objects are eventually dropped by the
+// TemporaryObjectCleaner.
+func (ex *connExecutor) cleanupTemporarySchema(ctx context.Context) {
+	scName := ex.sessionData.SearchPath.GetTemporarySchemaName()
+	// Session-bound internal executors use the temporary schema of the session
+	// they are bound to, which is cleaned up when that session is closed.
+	if scName == "" || scName != temporarySchemaName(ex.sessionID) {
+		return
+	}
+	// The context of the session may already be canceled.
+	ctx = logtags.WithTags(context.Background(), logtags.FromContext(ctx))
+	ie := MakeSessionBoundInternalExecutor(
+		ctx,
+		temporarySchemaCleanupSessionData(scName),
+		ex.server,
+		ex.memMetrics,
+		ex.server.cfg.Settings,
+	)
+	if err := cleanupTemporarySchema(ctx, &ie); err != nil {
+		log.Warningf(ctx, "error cleaning up temporary schema %s: %s", scName, err)
+	}
+}
+
+// TemporaryObjectCleaner periodically drops the temporary schemas of the
+// sessions that are no longer alive.
+type TemporaryObjectCleaner struct {
+	settings     *cluster.Settings
+	db           *client.DB
+	makeExecutor sqlutil.SessionBoundInternalExecutorFactory
+	statusServer serverpb.StatusServer
+}
+
+// NewTemporaryObjectCleaner creates a TemporaryObjectCleaner.
+func NewTemporaryObjectCleaner(
+	settings *cluster.Settings,
+	db *client.DB,
+	makeExecutor sqlutil.SessionBoundInternalExecutorFactory,
+	statusServer serverpb.StatusServer,
+) *TemporaryObjectCleaner {
+	return &TemporaryObjectCleaner{
+		settings:     settings,
+		db:           db,
+		makeExecutor: makeExecutor,
+		statusServer: statusServer,
+	}
+}
+
+// Start runs the cleaner until the stopper quiesces.
+func (c *TemporaryObjectCleaner) Start(ctx context.Context, stopper *stop.Stopper) {
+	stopper.RunWorker(ctx, func(ctx context.Context) {
+		var timer timeutil.Timer
+		defer timer.Stop()
+		for {
+			timer.Reset(TemporaryObjectCleanupInterval.Get(&c.settings.SV))
+			select {
+			case <-stopper.ShouldQuiesce():
+				return
+			case <-timer.C:
+				timer.Read = true
+				if err := c.cleanupOrphanedSchemas(ctx); err != nil {
+					log.Warningf(ctx, "error cleaning up temporary schemas: %s", err)
+				}
+			}
+		}
+	})
+}
+
+// cleanupOrphanedSchemas drops the temporary schemas of the sessions that are
+// no longer alive.
+func (c *TemporaryObjectCleaner) cleanupOrphanedSchemas(ctx context.Context) error {
+	// Read the temporary schemas before listing the sessions: a schema created
+	// after the sessions were listed could otherwise be mistaken for the schema
+	// of a session that is gone.
+	var schemas []temporarySchema
+	if err := c.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		var err error
+		schemas, err = getTemporarySchemas(ctx, txn, "" /* scName */)
+		return err
+	}); err != nil {
+		return err
+	}
+	if len(schemas) == 0 {
+		return nil
+	}
+
+	response, err := c.statusServer.ListSessions(ctx, &serverpb.ListSessionsRequest{})
+	if err != nil {
+		return err
+	}
+	// The sessions of the nodes that could not be reached may still be alive.
+	unreachableNodes := make(map[roachpb.NodeID]struct{})
+	for _, e := range response.Errors {
+		if e.NodeID == 0 {
+			return errors.Errorf("error listing sessions: %s", e.Message)
+		}
+		unreachableNodes[e.NodeID] = struct{}{}
+	}
+	liveSessions := make(map[string]struct{}, len(response.Sessions))
+	for _, s := range response.Sessions {
+		liveSessions[temporarySchemaName(BytesToClusterWideID(s.ID))] = struct{}{}
+	}
+
+	cleaned := make(map[string]struct{})
+	for _, sc := range schemas {
+		if _, ok := cleaned[sc.name]; ok {
+			continue
+		}
+		if _, ok := liveSessions[sc.name]; ok {
+			continue
+		}
+		sessionID, err := temporarySchemaSessionID(sc.name)
+		if err != nil {
+			log.Warning(ctx, err)
+			continue
+		}
+		if _, ok := unreachableNodes[roachpb.NodeID(sessionID.GetNodeID())]; ok {
+			continue
+		}
+		// Dropping the schema drops it in every database.
+		cleaned[sc.name] = struct{}{}
+		log.Infof(ctx, "cleaning up temporary schema %s", sc.name)
+		ie := c.makeExecutor(ctx, temporarySchemaCleanupSessionData(sc.name))
+		if err := cleanupTemporarySchema(ctx, ie); err != nil {
+			log.Warningf(ctx, "error cleaning up temporary schema %s: %s", sc.name, err)
+		}
+	}
+	return nil
+}
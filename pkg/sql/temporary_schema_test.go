@@ -0,0 +1,104 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+	gosql "database/sql"
+	"net/url"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/uint128"
+	"github.com/pkg/errors"
+)
+
+func TestTemporarySchemaName(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	sessionID := ClusterWideID{Uint128: uint128.FromInts(1554820531, 18446744073709551615)}
+	scName := temporarySchemaName(sessionID)
+	if expected := "pg_temp_1554820531_18446744073709551615"; scName != expected {
+		t.Fatalf("expected %s, got %s", expected, scName)
+	}
+	id, err := temporarySchemaSessionID(scName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != sessionID {
+		t.Fatalf("expected %s, got %s", sessionID, id)
+	}
+
+	for _, name := range []string{"pg_temp", "pg_temp_1", "pg_temp_a_1", "pg_temp_1_2_3"} {
+		if _, err := temporarySchemaSessionID(name); !testutils.IsError(err, "malformed temporary schema name") {
+			t.Errorf("%s: expected malformed name error, got %v", name, err)
+		}
+	}
+}
+
+// TestTemporaryObjectsDroppedOnSessionClose verifies that the temporary
+// objects of a session are invisible to other sessions and are dropped when
+// the session is closed.
+func TestTemporaryObjectsDroppedOnSessionClose(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(context.TODO())
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `CREATE DATABASE d`)
+
+	pgURL, cleanup := sqlutils.PGUrl(
+		t, s.ServingAddr(), "TestTemporaryObjectsDroppedOnSessionClose", url.User(security.RootUser))
+	defer cleanup()
+	tempDB, err := gosql.Open("postgres", pgURL.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tempDB.Close()
+	tempDB.SetMaxOpenConns(1)
+	tempSQLDB := sqlutils.MakeSQLRunner(tempDB)
+	tempSQLDB.Exec(t, `CREATE TEMPORARY TABLE d.t (a INT PRIMARY KEY)`)
+	tempSQLDB.Exec(t, `INSERT INTO d.pg_temp.t VALUES (1)`)
+	tempSQLDB.CheckQueryResults(t, `SELECT a FROM d.t`, [][]string{{"1"}})
+
+	var scName string
+	tempSQLDB.QueryRow(t,
+		`SELECT nspname FROM d.pg_catalog.pg_namespace WHERE nspname LIKE 'pg_temp_%'`,
+	).Scan(&scName)
+
+	// The table is invisible to the other sessions.
+	sqlDB.ExpectErr(t, `relation "d.t" does not exist`, `SELECT a FROM d.t`)
+	sqlDB.ExpectErr(t, `relation "d.pg_temp.t" does not exist`, `SELECT a FROM d.pg_temp.t`)
+	sqlDB.ExpectErr(t, `relation ".*t" does not exist`, `SELECT a FROM d.`+scName+`.t`)
+
+	if err := tempDB.Close(); err != nil {
+		t.Fatal(err)
+	}
+	testutils.SucceedsSoon(t, func() error {
+		var count int
+		sqlDB.QueryRow(t, `SELECT count(*) FROM system.namespace WHERE name = $1`, scName).Scan(&count)
+		if count != 0 {
+			return errors.Errorf("temporary schema %s was not dropped", scName)
+		}
+		return nil
+	})
+	sqlDB.CheckQueryResults(t,
+		`SELECT count(*) FROM system.namespace WHERE name = 't'`, [][]string{{"0"}})
+}
@@ -108,6 +108,19 @@ type txnState struct {
 	// activeSavepointName stores the name of the active savepoint,
 	// or is empty if no savepoint is active.
 	activeSavepointName tree.Name
+
+	// savepoints is the stack of the regular savepoints established in the
+	// current transaction, innermost last. See savepoints.go.
+	savepoints []savepoint
+
+	// numDDL counts the DDL statements executed in the current transaction.
+	numDDL int
+
+	// kvTxnKeptOpen is set when an error moved the SQL txn to the Aborted
+	// state while regular savepoints were established. The KV txn is then not
+	// rolled back, so that ROLLBACK TO SAVEPOINT can resume it. It is rolled
+	// back when the SQL txn finishes.
+	kvTxnKeptOpen bool
 }
 
 // txnType represents the type of a SQL transaction.
@@ -147,6 +160,9 @@ func (ts *txnState) resetForNewSQLTxn(
 ) {
 	// Reset state vars to defaults.
 	ts.sqlTimestamp = sqlTimestamp
+	ts.savepoints = nil
+	ts.numDDL = 0
+	ts.kvTxnKeptOpen = false
 
 	// Create a context for this transaction. It will include a root span that
 	// will contain everything executed as part of the upcoming SQL txn, including
@@ -222,6 +238,7 @@ func (ts *txnState) resetForNewSQLTxn(
 // the current SQL txn. This needs to be called before resetForNewSQLTxn() is
 // called for starting another SQL txn.
 func (ts *txnState) finishSQLTxn() {
+	ts.rollbackKeptKVTxn()
 	ts.mon.Stop(ts.Ctx)
 	if ts.cancel != nil {
 		ts.cancel()
@@ -254,6 +271,19 @@ func (ts *txnState) finishSQLTxn() {
 	ts.recordingThreshold = 0
 }
 
+// rollbackKeptKVTxn rolls back the KV txn if it was kept open when the SQL
+// txn moved to the Aborted state (see kvTxnKeptOpen).
+func (ts *txnState) rollbackKeptKVTxn() {
+	if !ts.kvTxnKeptOpen {
+		return
+	}
+	ts.kvTxnKeptOpen = false
+	ts.savepoints = nil
+	if err := ts.mu.txn.Rollback(ts.Ctx); err != nil {
+		log.Warningf(ts.Ctx, "txn rollback failed: %s", err)
+	}
+}
+
 // finishExternalTxn is a stripped-down version of finishSQLTxn used by
 // connExecutors that run within a higher-level transaction (through the
 // InternalExecutor). These guys don't want to mess with the transaction per-se,
@@ -61,6 +61,11 @@ func (p *planner) Update(
 		return nil, pgerror.NewDangerousStatementErrorf("UPDATE without WHERE clause")
 	}
 
+	if len(n.From) > 0 {
+		return nil, pgerror.UnimplementedWithIssueErrorf(7841,
+			"UPDATE ... FROM is only supported by the cost-based optimizer")
+	}
+
 	// CTE analysis.
 	resetter, err := p.initWith(ctx, n.With)
 	if err != nil {
@@ -216,6 +221,7 @@ func (p *planner) Update(
 	if err != nil {
 		return nil, err
 	}
+	ru.SetDeferredFKChecks(p.deferredFKChecks())
 
 	tracing.AnnotateTrace()
 
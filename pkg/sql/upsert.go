@@ -214,9 +214,10 @@ func (p *planner) newUpsertNode(
 			// General/slow path.
 			un.run.tw = &tableUpserter{
 				tableUpserterBase: tableUpserterBase{
-					ri:          ri,
-					alloc:       &p.alloc,
-					collectRows: needRows,
+					ri:               ri,
+					alloc:            &p.alloc,
+					collectRows:      needRows,
+					deferredFKChecks: p.deferredFKChecks(),
 				},
 				anyComputed:   len(computeExprs) >= 0,
 				fkTables:      fkTables,
@@ -798,32 +799,56 @@ func upsertExprsAndIndex(
 		return true, updateExprs, conflictIndex, nil
 	}
 
-	if onConflict.DoNothing && len(onConflict.Columns) == 0 {
+	if onConflict.DoNothing && len(onConflict.Columns) == 0 && onConflict.Constraint == "" {
 		return false, onConflict.Exprs, nil, nil
 	}
 
 	// General case: INSERT with an ON CONFLICT clause.
 
-	indexMatch := func(index sqlbase.IndexDescriptor) bool {
+	if onConflict.Constraint != "" {
+		// The unique constraints are the unique indexes that are not partial,
+		// and have the same name.
+		index, dropped, err := tableDesc.FindIndexByName(string(onConflict.Constraint))
+		if err != nil || dropped || !index.Unique || index.IsPartial() {
+			return false, nil, nil, pgerror.NewErrorf(pgerror.CodeUndefinedObjectError,
+				"constraint %q for table %q does not exist", onConflict.Constraint, tableDesc.Name)
+		}
+		return false, onConflict.Exprs, index, nil
+	}
+
+	indexMatch := func(index sqlbase.IndexDescriptor) (bool, error) {
 		if !index.Unique {
-			return false
+			return false, nil
 		}
 		if len(index.ColumnNames) != len(onConflict.Columns) {
-			return false
+			return false, nil
 		}
 		for i, colName := range index.ColumnNames {
 			if colName != string(onConflict.Columns[i]) {
-				return false
+				return false, nil
+			}
+		}
+		if index.IsPartial() {
+			// Partial unique indexes only guarantee uniqueness among the rows
+			// that satisfy their predicate. They can only be used as arbiters if
+			// the ON CONFLICT clause restricts the conflicts to these rows.
+			if onConflict.ArbiterPredicate == nil {
+				return false, nil
 			}
+			return sqlbase.IndexPredicateImpliedBy(*index.Predicate, onConflict.ArbiterPredicate)
 		}
-		return true
+		return true, nil
 	}
 
-	if indexMatch(tableDesc.PrimaryIndex) {
+	if ok, err := indexMatch(tableDesc.PrimaryIndex); err != nil {
+		return false, nil, nil, err
+	} else if ok {
 		return false, onConflict.Exprs, &tableDesc.PrimaryIndex, nil
 	}
 	for i := range tableDesc.Indexes {
-		if indexMatch(tableDesc.Indexes[i]) {
+		if ok, err := indexMatch(tableDesc.Indexes[i]); err != nil {
+			return false, nil, nil, err
+		} else if ok {
 			return false, onConflict.Exprs, &tableDesc.Indexes[i], nil
 		}
 	}
@@ -0,0 +1,296 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// User-defined functions are stored as FunctionDescriptors. Like a type, a
+// function shares the namespace of its database with the tables: its name
+// is recorded in system.namespace under the ID of the database, and its
+// descriptor, which holds all the overloads of the function, in
+// system.descriptor.
+//
+// Function descriptors are not leased. They are read transactionally when
+// a statement calls a function that is not a builtin. The body of an
+// overload is stored with its arguments replaced by placeholders, and is
+// evaluated with the internal executor of the session, in the transaction
+// of the calling statement. The optimizer inlines the body instead when the
+// function is simple enough (see optbuilder and norm/inline.go).
+
+var _ tree.FunctionReferenceResolver = &planner{}
+
+// maxFunctionCallDepth is the maximum number of nested calls to
+// user-defined functions, which prevents unbounded recursion.
+const maxFunctionCallDepth = 32
+
+// functionCallDepthKey is the context key under which the current number of
+// nested calls to user-defined functions is stored.
+type functionCallDepthKey struct{}
+
+// functionBodyExecutor is the subset of the internal executor interface
+// used to evaluate the body of user-defined functions.
+type functionBodyExecutor interface {
+	Query(
+		ctx context.Context, opName string, txn *client.Txn, stmt string, qargs ...interface{},
+	) ([]tree.Datums, sqlbase.ResultColumns, error)
+}
+
+// ResolveFunctionReference implements the tree.FunctionReferenceResolver
+// interface. Functions are looked up in the public schema of the current
+// database, or of the database named by the function name. Nil is returned
+// if there is no such function.
+// Privileges: EXECUTE on function.
+func (p *planner) ResolveFunctionReference(
+	name *tree.UnresolvedName,
+) (*tree.FunctionDefinition, error) {
+	tn, err := tree.NormalizeTableName(name)
+	if err != nil {
+		return nil, err
+	}
+	if !tn.ExplicitSchema && p.CurrentDatabase() == "" {
+		return nil, nil
+	}
+	ctx := p.EvalContext().Context
+	found, dbDesc, err := tn.ResolveTarget(ctx, p, p.CurrentDatabase(), p.CurrentSearchPath())
+	if err != nil || !found || tn.Schema() != tree.PublicSchema {
+		return nil, err
+	}
+	desc, err := getFunctionDesc(ctx, p.txn, dbDesc.(*DatabaseDescriptor).ID, tn.Table())
+	if err != nil || desc == nil {
+		return nil, err
+	}
+	if err := p.CheckPrivilege(ctx, desc, privilege.EXECUTE); err != nil {
+		return nil, err
+	}
+	return makeFunctionDefinition(desc)
+}
+
+// resolveFunctionDesc looks up the descriptor of the named function. If the
+// function does not exist, an error is returned if required is set, and nil
+// otherwise.
+func (p *planner) resolveFunctionDesc(
+	ctx context.Context, tn *ObjectName, required bool,
+) (*sqlbase.FunctionDescriptor, error) {
+	dbDesc, err := p.resolveTypeDatabase(ctx, tn)
+	if err != nil {
+		return nil, err
+	}
+	desc, err := getFunctionDesc(ctx, p.txn, dbDesc.ID, tn.Table())
+	if err != nil {
+		return nil, err
+	}
+	if desc == nil && required {
+		return nil, pgerror.NewErrorf(pgerror.CodeUndefinedFunctionError,
+			"function %s does not exist", tree.ErrString(tn))
+	}
+	return desc, nil
+}
+
+// getFunctionDesc returns the descriptor of the function with the given
+// name in the given database, or nil if there is none.
+func getFunctionDesc(
+	ctx context.Context, txn *client.Txn, dbID sqlbase.ID, name string,
+) (*sqlbase.FunctionDescriptor, error) {
+	desc := &sqlbase.FunctionDescriptor{}
+	found, err := getDescriptor(ctx, txn, tableKey{parentID: dbID, name: name}, desc)
+	if err != nil || !found {
+		return nil, err
+	}
+	return desc, nil
+}
+
+// getFunctionsInDatabase returns the descriptors of the functions of the
+// database.
+func getFunctionsInDatabase(
+	ctx context.Context, txn *client.Txn, dbID sqlbase.ID,
+) ([]*sqlbase.FunctionDescriptor, error) {
+	descs, err := GetAllDescriptors(ctx, txn)
+	if err != nil {
+		return nil, err
+	}
+	var res []*sqlbase.FunctionDescriptor
+	for _, desc := range descs {
+		if fn, ok := desc.(*sqlbase.FunctionDescriptor); ok && fn.ParentID == dbID {
+			res = append(res, fn)
+		}
+	}
+	return res, nil
+}
+
+// makeFunctionDefinition builds the definition of a user-defined function
+// that is used for type checking and evaluation.
+func makeFunctionDefinition(desc *sqlbase.FunctionDescriptor) (*tree.FunctionDefinition, error) {
+	props := tree.FunctionProperties{
+		// User-defined functions are called on NULL input.
+		NullableArgs: true,
+		// The body is evaluated with the internal executor of the session.
+		DistsqlBlacklist: true,
+	}
+	returnsSet := desc.Overloads[0].ReturnsSet
+	if returnsSet {
+		props.Class = tree.GeneratorClass
+		props.Impure = true
+		props.ReturnLabels = []string{desc.Name}
+	}
+
+	overloads := make([]tree.Overload, len(desc.Overloads))
+	for i := range desc.Overloads {
+		ov := &desc.Overloads[i]
+		if ov.Volatility == sqlbase.FunctionDescriptor_VOLATILE {
+			props.Impure = true
+		}
+		stmt, err := parser.ParseOne(ov.Body)
+		if err != nil {
+			return nil, err
+		}
+		body, ok := stmt.AST.(*tree.Select)
+		if !ok {
+			return nil, pgerror.NewAssertionErrorf(
+				"body of function %q is not a SELECT statement", desc.Name)
+		}
+
+		argTypes := make(tree.ArgTypes, len(ov.Arguments))
+		for j := range ov.Arguments {
+			argTypes[j].Name = ov.Arguments[j].Name
+			if argTypes[j].Name == "" {
+				argTypes[j].Name = fmt.Sprintf("$%d", j+1)
+			}
+			argTypes[j].Typ = ov.Arguments[j].Type.ToDatumType()
+		}
+		retType := ov.ReturnType.ToDatumType()
+
+		overloads[i] = tree.Overload{
+			Types:      argTypes,
+			ReturnType: tree.FixedReturnType(retType),
+			SQLFunction: &tree.SQLFunction{
+				Body:       body,
+				Volatility: tree.FunctionVolatility(ov.Volatility),
+			},
+		}
+		name, bodySQL := desc.Name, ov.Body
+		if returnsSet {
+			overloads[i].Generator = func(evalCtx *tree.EvalContext, args tree.Datums) (tree.ValueGenerator, error) {
+				rows, err := evalFunctionBody(evalCtx, name, bodySQL, args, retType)
+				if err != nil {
+					return nil, err
+				}
+				return &functionValueGenerator{typ: retType, rows: rows}, nil
+			}
+			overloads[i].Fn = func(*tree.EvalContext, tree.Datums) (tree.Datum, error) {
+				return nil, pgerror.NewAssertionErrorf("generator functions cannot be evaluated as scalars")
+			}
+		} else {
+			overloads[i].Fn = func(evalCtx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				rows, err := evalFunctionBody(evalCtx, name, bodySQL, args, retType)
+				if err != nil || len(rows) == 0 {
+					return tree.DNull, err
+				}
+				return rows[0][0], nil
+			}
+		}
+	}
+	return tree.NewFunctionDefinition(desc.Name, &props, overloads), nil
+}
+
+// evalFunctionBody evaluates the body of an overload of a user-defined
+// function with the given arguments, and returns the rows of the result
+// converted to the return type of the overload.
+func evalFunctionBody(
+	evalCtx *tree.EvalContext, name, body string, args tree.Datums, retType types.T,
+) ([]tree.Datums, error) {
+	ie, ok := evalCtx.InternalExecutor.(functionBodyExecutor)
+	if !ok {
+		return nil, pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+			"function %s cannot be evaluated in this context", name)
+	}
+	ctx := evalCtx.Ctx()
+	depth, _ := ctx.Value(functionCallDepthKey{}).(int)
+	if depth >= maxFunctionCallDepth {
+		return nil, pgerror.NewErrorf(pgerror.CodeStatementTooComplexError,
+			"stack depth limit exceeded").SetHintf(
+			"more than %d nested calls to user-defined functions; check for infinite recursion",
+			maxFunctionCallDepth)
+	}
+	ctx = context.WithValue(ctx, functionCallDepthKey{}, depth+1)
+
+	qargs := make([]interface{}, len(args))
+	for i := range args {
+		qargs[i] = args[i]
+	}
+	rows, _, err := ie.Query(ctx, "udf-"+name, evalCtx.Txn, body, qargs...)
+	if err != nil {
+		return nil, err
+	}
+	var castType coltypes.T
+	for _, row := range rows {
+		if row[0] == tree.DNull || row[0].ResolvedType().Equivalent(retType) {
+			continue
+		}
+		// The body was type checked against the return type when the function
+		// was created, but without that hint constants can have another type.
+		if castType == nil {
+			if castType, err = coltypes.DatumTypeToColumnType(retType); err != nil {
+				return nil, err
+			}
+		}
+		if row[0], err = tree.PerformCast(evalCtx, row[0], castType); err != nil {
+			return nil, err
+		}
+	}
+	return rows, nil
+}
+
+// functionValueGenerator returns the rows computed by the body of a
+// user-defined function that returns a set.
+type functionValueGenerator struct {
+	typ     types.T
+	rows    []tree.Datums
+	nextIdx int
+}
+
+var _ tree.ValueGenerator = &functionValueGenerator{}
+
+// ResolvedType implements the tree.ValueGenerator interface.
+func (g *functionValueGenerator) ResolvedType() types.T { return g.typ }
+
+// Start implements the tree.ValueGenerator interface.
+func (g *functionValueGenerator) Start() error {
+	g.nextIdx = -1
+	return nil
+}
+
+// Next implements the tree.ValueGenerator interface.
+func (g *functionValueGenerator) Next() (bool, error) {
+	g.nextIdx++
+	return g.nextIdx < len(g.rows), nil
+}
+
+// Values implements the tree.ValueGenerator interface.
+func (g *functionValueGenerator) Values() tree.Datums { return g.rows[g.nextIdx] }
+
+// Close implements the tree.ValueGenerator interface.
+func (g *functionValueGenerator) Close() {}
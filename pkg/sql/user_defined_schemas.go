@@ -0,0 +1,226 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// User-defined schemas are stored as SchemaDescriptors. A schema shares the
+// namespace of its database with the tables and the types: its name is
+// recorded in system.namespace under the ID of the database, and its
+// descriptor in system.descriptor. Unlike in Postgres, a schema therefore
+// cannot have the same name as a table, view or sequence of the public
+// schema: creating either one when the other exists fails with a duplicate
+// name error.
+//
+// Like the objects of a temporary schema, the names of the tables, views and
+// sequences of a user-defined schema are stored in system.namespace under
+// the ID of the schema instead of the ID of the database, and their
+// descriptors record the ID of the schema in SchemaID.
+
+// getSchemaDesc returns the descriptor of the user-defined schema with the
+// given name in the given database, or nil if the schema does not exist.
+func getSchemaDesc(
+	ctx context.Context, txn *client.Txn, dbID sqlbase.ID, scName string,
+) (*sqlbase.SchemaDescriptor, error) {
+	desc := &sqlbase.SchemaDescriptor{}
+	found, err := getDescriptor(ctx, txn, tableKey{parentID: dbID, name: scName}, desc)
+	if err != nil || !found {
+		return nil, err
+	}
+	return desc, nil
+}
+
+// resolveSchemaDesc looks up the descriptor of the user-defined schema with
+// the given name in the current database. If the schema does not exist, an
+// error is returned if required is set, and a nil schema descriptor
+// otherwise.
+func (p *planner) resolveSchemaDesc(
+	ctx context.Context, scName string, required bool,
+) (*sqlbase.DatabaseDescriptor, *sqlbase.SchemaDescriptor, error) {
+	if p.CurrentDatabase() == "" {
+		return nil, nil, errNoDatabase
+	}
+	dbDesc, err := p.ResolveUncachedDatabaseByName(ctx, p.CurrentDatabase(), true /* required */)
+	if err != nil {
+		return nil, nil, err
+	}
+	scDesc, err := getSchemaDesc(ctx, p.txn, dbDesc.ID, scName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if scDesc == nil && required {
+		return nil, nil, sqlbase.NewUndefinedSchemaError(scName)
+	}
+	return dbDesc, scDesc, nil
+}
+
+// getSchemasInDatabase returns the descriptors of the user-defined schemas of
+// the database.
+func getSchemasInDatabase(
+	ctx context.Context, txn *client.Txn, dbID sqlbase.ID,
+) ([]*sqlbase.SchemaDescriptor, error) {
+	descs, err := GetAllDescriptors(ctx, txn)
+	if err != nil {
+		return nil, err
+	}
+	var res []*sqlbase.SchemaDescriptor
+	for _, desc := range descs {
+		if sc, ok := desc.(*sqlbase.SchemaDescriptor); ok && sc.ParentID == dbID {
+			res = append(res, sc)
+		}
+	}
+	return res, nil
+}
+
+// isVirtualSchemaName returns whether the given name is the name of one of
+// the virtual schemas.
+func isVirtualSchemaName(scName string) bool {
+	switch scName {
+	case informationSchemaName, pgCatalogName, crdbInternalName:
+		return true
+	}
+	return false
+}
+
+// pgSchemaPrefix is the prefix of the names reserved for system schemas,
+// which includes the names of the temporary schemas.
+const pgSchemaPrefix = "pg_"
+
+// isSystemSchemaName returns whether the given name is the name of a schema
+// that is not user-defined, or is reserved for such schemas.
+func isSystemSchemaName(scName string) bool {
+	return scName == tree.PublicSchema || isVirtualSchemaName(scName) ||
+		strings.HasPrefix(scName, pgSchemaPrefix)
+}
+
+// getNamespaceParentID returns the ID under which the names of the objects of
+// the given schema of the given database are stored in system.namespace. It
+// returns 0 if the schema is a temporary or user-defined schema that does not
+// exist.
+func getNamespaceParentID(
+	ctx context.Context, txn *client.Txn, dbID sqlbase.ID, scName string,
+) (sqlbase.ID, error) {
+	if scName == tree.PublicSchema {
+		return dbID, nil
+	}
+	if sessiondata.IsTemporarySchemaName(scName) {
+		return getTemporarySchemaID(ctx, txn, dbID, scName)
+	}
+	scDesc, err := getSchemaDesc(ctx, txn, dbID, scName)
+	if err != nil || scDesc == nil {
+		return 0, err
+	}
+	return scDesc.ID, nil
+}
+
+// checkCreatePrivilege checks that the user can create an object with the
+// given resolved name. The objects of a user-defined schema require the
+// CREATE privilege on the schema, and the other objects the CREATE privilege
+// on the database.
+func (p *planner) checkCreatePrivilege(
+	ctx context.Context, dbDesc *sqlbase.DatabaseDescriptor, tn *ObjectName,
+) error {
+	scName := tn.Schema()
+	if scName == tree.PublicSchema || sessiondata.IsTemporarySchemaName(scName) {
+		return p.CheckPrivilege(ctx, dbDesc, privilege.CREATE)
+	}
+	scDesc, err := getSchemaDesc(ctx, p.txn, dbDesc.ID, scName)
+	if err != nil {
+		return err
+	}
+	if scDesc == nil {
+		return sqlbase.NewUndefinedSchemaError(scName)
+	}
+	return p.CheckPrivilege(ctx, scDesc, privilege.CREATE)
+}
+
+// inheritedPrivileges returns the privileges of a new table, view or
+// sequence, which are inherited from its schema if the schema is
+// user-defined, and from its database otherwise.
+func inheritedPrivileges(
+	dbDesc *sqlbase.DatabaseDescriptor, scDesc *sqlbase.SchemaDescriptor,
+) *sqlbase.PrivilegeDescriptor {
+	if scDesc != nil {
+		return scDesc.GetPrivileges()
+	}
+	return dbDesc.GetPrivileges()
+}
+
+// getSchemaObjects returns the tables, views and sequences whose names are
+// stored in system.namespace under the given ID, which is the ID of the
+// given schema of the given database.
+func (p *planner) getSchemaObjects(
+	ctx context.Context, dbName, scName string, id sqlbase.ID,
+) ([]toDelete, error) {
+	prefix := sqlbase.MakeNameMetadataKey(id, "")
+	sr, err := p.txn.Scan(ctx, prefix, prefix.PrefixEnd(), 0)
+	if err != nil {
+		return nil, err
+	}
+	var td []toDelete
+	for _, row := range sr {
+		_, name, err := encoding.DecodeUnsafeStringAscending(bytes.TrimPrefix(row.Key, prefix), nil)
+		if err != nil {
+			return nil, err
+		}
+		desc, err := p.Tables().getMutableTableVersionByID(ctx, sqlbase.ID(row.ValueInt()), p.txn)
+		if err != nil {
+			return nil, err
+		}
+		// Skip the names that are being drained after a DROP or RENAME.
+		if desc.Dropped() || desc.Name != name || desc.GetNamespaceParentID() != id {
+			continue
+		}
+		tn := tree.MakeTableNameWithSchema(tree.Name(dbName), tree.Name(scName), tree.Name(name))
+		td = append(td, toDelete{tn: &tn, desc: desc})
+	}
+	return td, nil
+}
+
+// dropSchemaDescToBatch adds the deletion of the name and of the descriptor
+// of the user-defined schema to the batch.
+func dropSchemaDescToBatch(
+	ctx context.Context, p *planner, desc *sqlbase.SchemaDescriptor, b *client.Batch,
+) {
+	nameKey := tableKey{parentID: desc.ParentID, name: desc.Name}.Key()
+	descKey := sqlbase.MakeDescMetadataKey(desc.ID)
+	if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+		log.VEventf(ctx, 2, "Del %s", nameKey)
+		log.VEventf(ctx, 2, "Del %s", descKey)
+	}
+	b.Del(nameKey)
+	b.Del(descKey)
+}
+
+// unacceptableSchemaNameError returns the error for the creation of a schema
+// whose name has the prefix reserved for system schemas.
+func unacceptableSchemaNameError(scName string) error {
+	return pgerror.NewErrorf(pgerror.CodeReservedNameError,
+		"unacceptable schema name %q", scName).SetDetailf(
+		"The prefix %q is reserved for system schemas.", pgSchemaPrefix)
+}
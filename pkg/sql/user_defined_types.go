@@ -0,0 +1,145 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// User-defined types are stored as TypeDescriptors. A type shares the
+// namespace of its database with the tables: its name is recorded in
+// system.namespace under the ID of the database, and its descriptor in
+// system.descriptor.
+//
+// Type descriptors are not leased. They are read transactionally when a
+// statement refers to a type by name, and their members are copied into the
+// ColumnType of the columns that use them. ALTER TYPE therefore rewrites the
+// descriptors of these tables.
+
+var _ tree.TypeReferenceResolver = &planner{}
+
+// ResolveTypeReference implements the tree.TypeReferenceResolver interface.
+// The type is looked up in the current database.
+func (p *planner) ResolveTypeReference(name string) (types.T, error) {
+	tn := tree.MakeUnqualifiedTableName(tree.Name(name))
+	desc, err := p.resolveTypeDesc(p.EvalContext().Context, &tn, true /* required */)
+	if err != nil {
+		return nil, err
+	}
+	return desc.DatumType(), nil
+}
+
+// resolveTypeDesc looks up the descriptor of the named type. If the type
+// does not exist, an error is returned if required is set, and nil
+// otherwise.
+func (p *planner) resolveTypeDesc(
+	ctx context.Context, tn *ObjectName, required bool,
+) (*sqlbase.TypeDescriptor, error) {
+	dbDesc, err := p.resolveTypeDatabase(ctx, tn)
+	if err != nil {
+		return nil, err
+	}
+	desc := &sqlbase.TypeDescriptor{}
+	found, err := getDescriptor(ctx, p.txn, tableKey{parentID: dbDesc.ID, name: tn.Table()}, desc)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		if required {
+			return nil, pgerror.NewErrorf(pgerror.CodeUndefinedObjectError,
+				"type %q does not exist", tree.ErrString(tn))
+		}
+		return nil, nil
+	}
+	return desc, nil
+}
+
+// resolveTypeDatabase qualifies the name of a type and returns the
+// descriptor of the database that contains it. Types can only be created in
+// the public schema.
+func (p *planner) resolveTypeDatabase(
+	ctx context.Context, tn *ObjectName,
+) (*sqlbase.DatabaseDescriptor, error) {
+	dbDesc, err := p.ResolveUncachedDatabase(ctx, tn)
+	if err != nil {
+		return nil, err
+	}
+	if tn.Schema() != tree.PublicSchema {
+		return nil, sqlbase.NewUnsupportedSchemaUsageError(tree.ErrString(&tn.TableNamePrefix))
+	}
+	return dbDesc, nil
+}
+
+// getTypesInDatabase returns the descriptors of the types of the database.
+func getTypesInDatabase(
+	ctx context.Context, txn *client.Txn, dbID sqlbase.ID,
+) ([]*sqlbase.TypeDescriptor, error) {
+	descs, err := GetAllDescriptors(ctx, txn)
+	if err != nil {
+		return nil, err
+	}
+	var res []*sqlbase.TypeDescriptor
+	for _, desc := range descs {
+		if typ, ok := desc.(*sqlbase.TypeDescriptor); ok && typ.ParentID == dbID {
+			res = append(res, typ)
+		}
+	}
+	return res, nil
+}
+
+// getTablesUsingType returns the IDs of the tables, views and sequences that
+// have a column of the given type or of an array of the type. Dropped tables
+// are ignored.
+func getTablesUsingType(
+	ctx context.Context, txn *client.Txn, typeID sqlbase.ID,
+) ([]sqlbase.ID, error) {
+	descs, err := GetAllDescriptors(ctx, txn)
+	if err != nil {
+		return nil, err
+	}
+	var res []sqlbase.ID
+	for _, desc := range descs {
+		table, ok := desc.(*sqlbase.TableDescriptor)
+		if !ok || table.Dropped() {
+			continue
+		}
+		if tableUsesType(table, typeID) {
+			res = append(res, table.ID)
+		}
+	}
+	return res, nil
+}
+
+// tableUsesType returns whether one of the columns of the table, including
+// the columns being added or dropped, has the given type.
+func tableUsesType(table *sqlbase.TableDescriptor, typeID sqlbase.ID) bool {
+	for i := range table.Columns {
+		if table.Columns[i].Type.EnumTypeID == typeID {
+			return true
+		}
+	}
+	for i := range table.Mutations {
+		if col := table.Mutations[i].GetColumn(); col != nil && col.Type.EnumTypeID == typeID {
+			return true
+		}
+	}
+	return false
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 )
 
 const (
@@ -255,6 +256,22 @@ var varGen = map[string]sessionVar{
 			return strconv.FormatInt(defaultIntSize.Get(sv), 10)
 		},
 	},
+	// See https://www.postgresql.org/docs/10/static/runtime-config-client.html#GUC-DEFAULT-TEXT-SEARCH-CONFIG
+	`default_text_search_config`: {
+		Set: func(_ context.Context, m *sessionDataMutator, s string) error {
+			c, err := tsearch.GetConfig(s)
+			if err != nil {
+				return err
+			}
+			m.SetDefaultTextSearchConfig("pg_catalog." + c.Name())
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext) string {
+			return evalCtx.SessionData.DefaultTextSearchConfig
+		},
+		GlobalDefault: func(_ *settings.Values) string { return tsearch.DefaultConfigName },
+	},
+
 	// See https://www.postgresql.org/docs/10/static/runtime-config-client.html#GUC-DEFAULT-TRANSACTION-ISOLATION
 	`default_transaction_isolation`: {
 		Set: func(_ context.Context, m *sessionDataMutator, s string) error {
@@ -541,7 +558,10 @@ var varGen = map[string]sessionVar{
 		},
 		Set: func(_ context.Context, m *sessionDataMutator, s string) error {
 			paths := strings.Split(s, ",")
-			m.SetSearchPath(sessiondata.MakeSearchPath(paths))
+			// The temporary schema of the session is not part of the
+			// search_path variable, so preserve it.
+			tempSchemaName := m.data.SearchPath.GetTemporarySchemaName()
+			m.SetSearchPath(sessiondata.MakeSearchPath(paths).WithTemporarySchemaName(tempSchemaName))
 			return nil
 		},
 		Get: func(evalCtx *extendedEvalContext) string {
@@ -21,6 +21,7 @@ import (
 	"reflect"
 	"strconv"
 
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util"
@@ -196,6 +197,15 @@ func (v *planVisitor) visitInternal(plan planNode, name string) {
 			if n.hardLimit > 0 && isFilterTrue(n.filter) {
 				v.observer.attr(name, "limit", fmt.Sprintf("%d", n.hardLimit))
 			}
+			if n.lockingStrength != roachpb.LOCK_NONE {
+				v.observer.attr(name, "locking strength", "exclusive")
+				switch n.lockingWaitPolicy {
+				case roachpb.LOCK_WAIT_SKIP:
+					v.observer.attr(name, "locking wait policy", "skip locked")
+				case roachpb.LOCK_WAIT_ERROR:
+					v.observer.attr(name, "locking wait policy", "nowait")
+				}
+			}
 		}
 		if v.observer.expr != nil {
 			v.expr(name, "filter", -1, n.filter)
@@ -364,11 +374,16 @@ func (v *planVisitor) visitInternal(plan planNode, name string) {
 					buf.WriteString(inputCols[groupingCol].Name)
 				} else {
 					fmt.Fprintf(&buf, "%s(", agg.funcName)
-					if agg.argRenderIdx != noRenderIdx {
+					if len(agg.argRenderIdxs) > 0 {
 						if agg.isDistinct() {
 							buf.WriteString("DISTINCT ")
 						}
-						buf.WriteString(inputCols[agg.argRenderIdx].Name)
+						for j, idx := range agg.argRenderIdxs {
+							if j > 0 {
+								buf.WriteString(", ")
+							}
+							buf.WriteString(inputCols[idx].Name)
+						}
 					}
 					buf.WriteByte(')')
 					if agg.filterRenderIdx != noRenderIdx {
@@ -519,6 +534,17 @@ func (v *planVisitor) visitInternal(plan planNode, name string) {
 		if v.observer.attr != nil {
 			v.observer.attr(name, "query", tree.AsStringWithFlags(n.n.AsSource, tree.FmtParsable))
 		}
+		if n.sourcePlan != nil {
+			n.sourcePlan = v.visit(n.sourcePlan)
+		}
+
+	case *refreshViewNode:
+		if n.sourcePlan != nil {
+			n.sourcePlan = v.visit(n.sourcePlan)
+		}
+
+	case *declareCursorNode:
+		n.sourcePlan = v.visit(n.sourcePlan)
 
 	case *setVarNode:
 		if v.observer.expr != nil {
@@ -546,6 +572,17 @@ func (v *planVisitor) visitInternal(plan planNode, name string) {
 	case *ordinalityNode:
 		n.source = v.visit(n.source)
 
+	case *recursiveCTENode:
+		if v.observer.attr != nil {
+			v.observer.attr(name, "label", n.label)
+		}
+		n.initial = v.visit(n.initial)
+
+	case *workTableScanNode:
+		if v.observer.attr != nil {
+			v.observer.attr(name, "label", n.label)
+		}
+
 	case *spoolNode:
 		if n.hardLimit > 0 && v.observer.attr != nil {
 			v.observer.attr(name, "limit", fmt.Sprintf("%d", n.hardLimit))
@@ -679,6 +716,7 @@ func colListStr(cols []int) string {
 var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&alterIndexNode{}):           "alter index",
 	reflect.TypeOf(&alterSequenceNode{}):        "alter sequence",
+	reflect.TypeOf(&alterTypeAddValueNode{}):    "alter type",
 	reflect.TypeOf(&alterTableNode{}):           "alter table",
 	reflect.TypeOf(&alterUserSetPasswordNode{}): "alter user",
 	reflect.TypeOf(&commentOnColumnNode{}):      "comment on column",
@@ -689,22 +727,30 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&controlJobsNode{}):          "control jobs",
 	reflect.TypeOf(&createDatabaseNode{}):       "create database",
 	reflect.TypeOf(&createIndexNode{}):          "create index",
+	reflect.TypeOf(&createSchemaNode{}):         "create schema",
 	reflect.TypeOf(&createSequenceNode{}):       "create sequence",
+	reflect.TypeOf(&createTypeNode{}):           "create type",
+	reflect.TypeOf(&createFunctionNode{}):       "create function",
 	reflect.TypeOf(&createStatsNode{}):          "create statistics",
 	reflect.TypeOf(&createTableNode{}):          "create table",
 	reflect.TypeOf(&CreateUserNode{}):           "create user/role",
 	reflect.TypeOf(&createViewNode{}):           "create view",
+	reflect.TypeOf(&declareCursorNode{}):        "declare cursor",
 	reflect.TypeOf(&delayedNode{}):              "virtual table",
 	reflect.TypeOf(&deleteNode{}):               "delete",
 	reflect.TypeOf(&distinctNode{}):             "distinct",
 	reflect.TypeOf(&dropDatabaseNode{}):         "drop database",
 	reflect.TypeOf(&dropIndexNode{}):            "drop index",
+	reflect.TypeOf(&dropSchemaNode{}):           "drop schema",
 	reflect.TypeOf(&dropSequenceNode{}):         "drop sequence",
+	reflect.TypeOf(&dropTypeNode{}):             "drop type",
+	reflect.TypeOf(&dropFunctionNode{}):         "drop function",
 	reflect.TypeOf(&dropTableNode{}):            "drop table",
 	reflect.TypeOf(&DropUserNode{}):             "drop user/role",
 	reflect.TypeOf(&dropViewNode{}):             "drop view",
 	reflect.TypeOf(&explainDistSQLNode{}):       "explain distsql",
 	reflect.TypeOf(&explainPlanNode{}):          "explain plan",
+	reflect.TypeOf(&fetchNode{}):                "fetch",
 	reflect.TypeOf(&filterNode{}):               "filter",
 	reflect.TypeOf(&groupNode{}):                "group",
 	reflect.TypeOf(&hookFnNode{}):               "plugin",
@@ -716,6 +762,8 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&max1RowNode{}):              "max1row",
 	reflect.TypeOf(&ordinalityNode{}):           "ordinality",
 	reflect.TypeOf(&projectSetNode{}):           "project set",
+	reflect.TypeOf(&recursiveCTENode{}):         "recursive cte",
+	reflect.TypeOf(&refreshViewNode{}):          "refresh materialized view",
 	reflect.TypeOf(&relocateNode{}):             "relocate",
 	reflect.TypeOf(&renameColumnNode{}):         "rename column",
 	reflect.TypeOf(&renameDatabaseNode{}):       "rename database",
@@ -730,6 +778,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&sequenceSelectNode{}):       "sequence select",
 	reflect.TypeOf(&serializeNode{}):            "run",
 	reflect.TypeOf(&setClusterSettingNode{}):    "set cluster setting",
+	reflect.TypeOf(&setConstraintsNode{}):       "set constraints",
 	reflect.TypeOf(&setVarNode{}):               "set",
 	reflect.TypeOf(&setZoneConfigNode{}):        "configure zone",
 	reflect.TypeOf(&showFingerprintsNode{}):     "showFingerprints",
@@ -747,6 +796,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&valuesNode{}):               "values",
 	reflect.TypeOf(&virtualTableNode{}):         "virtual table values",
 	reflect.TypeOf(&windowNode{}):               "window",
+	reflect.TypeOf(&workTableScanNode{}):        "scan work table",
 	reflect.TypeOf(&zeroNode{}):                 "norows",
 	reflect.TypeOf(&zigzagJoinNode{}):           "zigzag-join",
 }
@@ -56,6 +56,14 @@ type cteSource struct {
 	// alias holds the name of the CTE and the renaming of its columns, if
 	// present.
 	alias tree.AliasClause
+	// recursive is set when the CTE is referenced from the recursive term of
+	// its own definition in a WITH RECURSIVE clause; the reference then
+	// scans the working table of this node.
+	recursive *recursiveCTENode
+	// refErr, if set, is returned by any reference to the CTE. It is used to
+	// reject references to a recursive CTE from places where they are not
+	// allowed.
+	refErr error
 }
 
 func (e cteNameEnvironment) push(frame cteNameEnvironmentFrame) cteNameEnvironment {
@@ -66,10 +74,24 @@ func (e cteNameEnvironment) pop() cteNameEnvironment {
 	return e[:len(e)-1]
 }
 
+// numUsed returns the number of CTEs in the environment that have been used
+// as a statement source.
+func (e cteNameEnvironment) numUsed() int {
+	n := 0
+	for _, frame := range e {
+		for _, src := range frame {
+			if src.used {
+				n++
+			}
+		}
+	}
+	return n
+}
+
 func popCteNameEnvironment(p *planner) error {
 	e := p.curPlan.cteNameEnvironment
 	for alias, src := range e[len(e)-1] {
-		if !src.used {
+		if !src.used && src.plan != nil {
 			seenMutation, err := containsMutations(src.plan)
 			if err != nil {
 				return err
@@ -114,7 +136,13 @@ func (p *planner) initWith(ctx context.Context, with *tree.With) (func(p *planne
 					"WITH query name %s specified more than once",
 					cte.Name.Alias)
 			}
-			ctePlan, err := p.newPlan(ctx, cte.Stmt, nil)
+			var ctePlan planNode
+			var err error
+			if with.Recursive {
+				ctePlan, err = p.newRecursiveCTEPlan(ctx, cte)
+			} else {
+				ctePlan, err = p.newPlan(ctx, cte.Stmt, nil)
+			}
 			if err != nil {
 				return nil, err
 			}
@@ -141,6 +169,9 @@ func (p *planner) getCTEDataSource(tn *tree.TableName) (planDataSource, bool, er
 	for i := len(env) - 1; i >= 0; i-- {
 		frame := p.curPlan.cteNameEnvironment[i]
 		if cteSource, ok := frame[tn.TableName]; ok {
+			if cteSource.refErr != nil {
+				return planDataSource{}, false, cteSource.refErr
+			}
 			if cteSource.used {
 				// TODO(jordan): figure out how to lift this restriction.
 				// CTE expressions that are used more than once will need to be
@@ -151,6 +182,9 @@ func (p *planner) getCTEDataSource(tn *tree.TableName) (planDataSource, bool, er
 			cteSource.used = true
 			frame[tn.TableName] = cteSource
 			plan := cteSource.plan
+			if cteSource.recursive != nil {
+				plan = &workTableScanNode{ref: cteSource.recursive, label: cteSource.recursive.label}
+			}
 			cols := planColumns(plan)
 			if len(cols) == 0 {
 				return planDataSource{}, false, pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
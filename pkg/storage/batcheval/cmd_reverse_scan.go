@@ -30,7 +30,8 @@ func init() {
 // ReverseScan scans the key range specified by start key through
 // end key in descending order up to some maximum number of results.
 // maxKeys stores the number of scan results remaining for this batch
-// (MaxInt64 for no limit).
+// (MaxInt64 for no limit). Locking scans also lock the keys they
+// return; see lockingScan.
 func ReverseScan(
 	ctx context.Context, batch engine.ReadWriter, cArgs CommandArgs, resp roachpb.Response,
 ) (result.Result, error) {
@@ -42,8 +43,17 @@ func ReverseScan(
 	var intents []roachpb.Intent
 	var resumeSpan *roachpb.Span
 
-	switch args.ScanFormat {
-	case roachpb.BATCH_RESPONSE:
+	switch {
+	case args.KeyLocking != roachpb.LOCK_NONE:
+		var rows []roachpb.KeyValue
+		rows, resumeSpan, err = lockingScan(
+			ctx, batch, cArgs, args.Span(), true /* reverse */, args.WaitPolicy)
+		if err != nil {
+			return result.Result{}, err
+		}
+		reply.NumKeys = int64(len(rows))
+		reply.Rows = rows
+	case args.ScanFormat == roachpb.BATCH_RESPONSE:
 		var kvData []byte
 		var numKvs int64
 		kvData, numKvs, resumeSpan, intents, err = engine.MVCCScanToBytes(
@@ -58,7 +68,7 @@ func ReverseScan(
 		}
 		reply.NumKeys = numKvs
 		reply.BatchResponses = [][]byte{kvData}
-	case roachpb.KEY_VALUES:
+	case args.ScanFormat == roachpb.KEY_VALUES:
 		var rows []roachpb.KeyValue
 		rows, resumeSpan, intents, err = engine.MVCCScan(
 			ctx, batch, args.Key, args.EndKey, cArgs.MaxKeys, h.Timestamp, engine.MVCCScanOptions{
@@ -0,0 +1,200 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package batcheval
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/pkg/errors"
+)
+
+// lockingScan scans the given span and locks the keys it returns by writing
+// a lock-only intent on each of them (see engine.MVCCLock). The intents carry
+// the current values of the keys and are removed instead of committed when the
+// transaction finishes, so locking a key doesn't create a new version of it.
+// They are still replicated through Raft like any other intent, which lets
+// them survive lease transfers. The keys locked by other transactions are
+// handled according to the wait policy.
+//
+// With LOCK_WAIT_BLOCK, a WriteIntentError is returned, which the store
+// handles by pushing the conflicting transactions with PUSH_ABORT and waiting
+// for them in the txnWaitQueue like any other writer. With LOCK_WAIT_ERROR, a
+// WriteIntentError is returned as well, but the store only pushes the
+// conflicting transactions with PUSH_TOUCH and returns the error to the client
+// if they are still active. With LOCK_WAIT_SKIP, the locked keys are omitted
+// from the result, along with the other keys of their SQL row. A row is only
+// omitted as a whole if all its keys are scanned by the same request.
+func lockingScan(
+	ctx context.Context,
+	batch engine.ReadWriter,
+	cArgs CommandArgs,
+	span roachpb.Span,
+	reverse bool,
+	waitPolicy roachpb.KeyLockingWaitPolicy,
+) ([]roachpb.KeyValue, *roachpb.Span, error) {
+	h := cArgs.Header
+	if h.Txn == nil {
+		return nil, nil, errors.Errorf("locking scans must be transactional")
+	}
+	if h.ReadConsistency != roachpb.CONSISTENT {
+		return nil, nil, errors.Errorf("locking scans must be consistent")
+	}
+
+	var rows []roachpb.KeyValue
+	var resumeSpan *roachpb.Span
+	var err error
+	if waitPolicy == roachpb.LOCK_WAIT_SKIP {
+		rows, resumeSpan, err = scanSkipLocked(ctx, batch, cArgs, span, reverse)
+	} else {
+		rows, resumeSpan, _, err = engine.MVCCScan(
+			ctx, batch, span.Key, span.EndKey, cArgs.MaxKeys, h.Timestamp,
+			engine.MVCCScanOptions{Txn: h.Txn, Reverse: reverse})
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The scan only reports the intents below the read timestamp. Check the
+	// scanned keys for the intents written above it before locking them.
+	var intents []roachpb.Intent
+	var meta enginepb.MVCCMetadata
+	for i := range rows {
+		key := rows[i].Key
+		ok, _, _, err := batch.GetProto(engine.MakeMVCCMetadataKey(key), &meta)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok && meta.Txn != nil && meta.Txn.ID != h.Txn.ID {
+			intents = append(intents, roachpb.Intent{
+				Span: roachpb.Span{Key: key}, Status: roachpb.PENDING, Txn: *meta.Txn,
+			})
+		}
+	}
+	if len(intents) > 0 {
+		if waitPolicy != roachpb.LOCK_WAIT_SKIP {
+			return nil, nil, &roachpb.WriteIntentError{Intents: intents}
+		}
+		rows = omitLockedRows(rows, intents)
+	}
+
+	for i := range rows {
+		value := roachpb.Value{RawBytes: rows[i].Value.RawBytes}
+		if err := engine.MVCCLock(
+			ctx, batch, cArgs.Stats, rows[i].Key, h.Timestamp, value, h.Txn,
+		); err != nil {
+			return nil, nil, err
+		}
+	}
+	return rows, resumeSpan, nil
+}
+
+// scanSkipLocked scans the given span like MVCCScan, skipping over the rows
+// that contain intents of other transactions instead of returning a
+// WriteIntentError.
+func scanSkipLocked(
+	ctx context.Context, batch engine.Reader, cArgs CommandArgs, span roachpb.Span, reverse bool,
+) ([]roachpb.KeyValue, *roachpb.Span, error) {
+	h := cArgs.Header
+	opts := engine.MVCCScanOptions{Txn: h.Txn, Reverse: reverse}
+	var rows []roachpb.KeyValue
+	for {
+		kvs, resumeSpan, _, err := engine.MVCCScan(
+			ctx, batch, span.Key, span.EndKey, cArgs.MaxKeys-int64(len(rows)), h.Timestamp, opts)
+		wiErr, ok := err.(*roachpb.WriteIntentError)
+		if !ok {
+			if err != nil {
+				return nil, nil, err
+			}
+			return append(rows, kvs...), resumeSpan, nil
+		}
+
+		// Find the row of the first intent in scan order. The part of the span
+		// before that row does not contain any intent: scan it, then continue
+		// past the row.
+		first := wiErr.Intents[0].Key
+		for _, intent := range wiErr.Intents[1:] {
+			if c := intent.Key.Compare(first); (c < 0) != reverse {
+				first = intent.Key
+			}
+		}
+		row := rowSpan(first)
+		var before, after roachpb.Span
+		if reverse {
+			before = roachpb.Span{Key: row.EndKey, EndKey: span.EndKey}
+			after = roachpb.Span{Key: span.Key, EndKey: row.Key}
+		} else {
+			before = roachpb.Span{Key: span.Key, EndKey: row.Key}
+			after = roachpb.Span{Key: row.EndKey, EndKey: span.EndKey}
+		}
+
+		if before.Key.Compare(before.EndKey) < 0 {
+			kvs, resumeSpan, _, err = engine.MVCCScan(
+				ctx, batch, before.Key, before.EndKey, cArgs.MaxKeys-int64(len(rows)), h.Timestamp, opts)
+			if err != nil {
+				return nil, nil, err
+			}
+			rows = append(rows, kvs...)
+			if resumeSpan != nil {
+				if reverse {
+					resumeSpan.Key = span.Key
+				} else {
+					resumeSpan.EndKey = span.EndKey
+				}
+				return rows, resumeSpan, nil
+			}
+		}
+		if after.Key.Compare(after.EndKey) >= 0 {
+			return rows, nil, nil
+		}
+		span = after
+	}
+}
+
+// omitLockedRows removes from rows the keys which belong to the same row as
+// one of the intents.
+func omitLockedRows(rows []roachpb.KeyValue, intents []roachpb.Intent) []roachpb.KeyValue {
+	locked := make([]roachpb.Span, len(intents))
+	for i := range intents {
+		locked[i] = rowSpan(intents[i].Key)
+	}
+	res := rows[:0]
+	for _, kv := range rows {
+		skip := false
+		for _, sp := range locked {
+			if sp.ContainsKey(kv.Key) {
+				skip = true
+				break
+			}
+		}
+		if !skip {
+			res = append(res, kv)
+		}
+	}
+	return res
+}
+
+// rowSpan returns the span of the keys which belong to the same SQL row as the
+// given key. It is the key itself if the key is not a SQL table key.
+func rowSpan(key roachpb.Key) roachpb.Span {
+	prefix, err := keys.EnsureSafeSplitKey(key)
+	if err != nil || len(prefix) == len(key) {
+		return roachpb.Span{Key: key, EndKey: key.Next()}
+	}
+	return roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()}
+}
@@ -0,0 +1,155 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package batcheval
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+// TestLockingScan verifies that locking scans lay down lock-only intents on
+// the keys they return, and that they handle the keys locked by other
+// transactions according to their wait policy.
+func TestLockingScan(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	ts1 := hlc.Timestamp{WallTime: 1}
+	ts2 := hlc.Timestamp{WallTime: 2}
+	ts3 := hlc.Timestamp{WallTime: 3}
+	ts4 := hlc.Timestamp{WallTime: 4}
+	v := roachpb.MakeValueFromString("v")
+
+	db := engine.NewInMem(roachpb.Attributes{}, 10<<20)
+	defer db.Close()
+
+	for _, k := range []string{"a", "b", "c", "d"} {
+		if err := engine.MVCCPut(ctx, db, nil, roachpb.Key(k), ts1, v, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Lock "c" below the read timestamp of the scans and "b" above it.
+	below := roachpb.MakeTransaction("below", roachpb.Key("c"), 0, ts2, 0)
+	if err := engine.MVCCPut(ctx, db, nil, roachpb.Key("c"), ts2, v, &below); err != nil {
+		t.Fatal(err)
+	}
+	above := roachpb.MakeTransaction("above", roachpb.Key("b"), 0, ts4, 0)
+	if err := engine.MVCCPut(ctx, db, nil, roachpb.Key("b"), ts4, v, &above); err != nil {
+		t.Fatal(err)
+	}
+
+	scan := func(
+		reverse bool, waitPolicy roachpb.KeyLockingWaitPolicy, maxKeys int64,
+	) (engine.Batch, *roachpb.Transaction, []roachpb.KeyValue, *roachpb.Span, error) {
+		txn := roachpb.MakeTransaction("locking", roachpb.Key("a"), 0, ts3, 0)
+		batch := db.NewBatch()
+		cArgs := CommandArgs{
+			Header:  roachpb.Header{Txn: &txn, Timestamp: ts3},
+			MaxKeys: maxKeys,
+			Stats:   &enginepb.MVCCStats{},
+		}
+		span := roachpb.Span{Key: roachpb.Key("a"), EndKey: roachpb.Key("e")}
+		rows, resumeSpan, err := lockingScan(ctx, batch, cArgs, span, reverse, waitPolicy)
+		return batch, &txn, rows, resumeSpan, err
+	}
+	keysOf := func(rows []roachpb.KeyValue) []string {
+		var res []string
+		for _, kv := range rows {
+			res = append(res, string(kv.Key))
+		}
+		return res
+	}
+
+	for _, waitPolicy := range []roachpb.KeyLockingWaitPolicy{
+		roachpb.LOCK_WAIT_BLOCK, roachpb.LOCK_WAIT_ERROR,
+	} {
+		t.Run(waitPolicy.String(), func(t *testing.T) {
+			batch, _, _, _, err := scan(false /* reverse */, waitPolicy, math.MaxInt64)
+			defer batch.Close()
+			wiErr, ok := err.(*roachpb.WriteIntentError)
+			if !ok {
+				t.Fatalf("expected WriteIntentError, got %v", err)
+			}
+			if len(wiErr.Intents) != 1 || !wiErr.Intents[0].Key.Equal(roachpb.Key("c")) {
+				t.Fatalf("unexpected intents %v", wiErr.Intents)
+			}
+		})
+	}
+
+	t.Run("skip", func(t *testing.T) {
+		batch, txn, rows, resumeSpan, err := scan(false /* reverse */, roachpb.LOCK_WAIT_SKIP, math.MaxInt64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer batch.Close()
+		if exp := []string{"a", "d"}; !reflect.DeepEqual(keysOf(rows), exp) {
+			t.Fatalf("expected %v, got %v", exp, keysOf(rows))
+		}
+		if resumeSpan != nil {
+			t.Fatalf("unexpected resume span %v", resumeSpan)
+		}
+		// The returned keys are locked by the scanning transaction.
+		for _, k := range []string{"a", "d"} {
+			var meta enginepb.MVCCMetadata
+			ok, _, _, err := batch.GetProto(engine.MakeMVCCMetadataKey(roachpb.Key(k)), &meta)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !ok || meta.Txn == nil || meta.Txn.ID != txn.ID || !meta.IsLockOnly() {
+				t.Fatalf("expected %q to be locked by the scanning transaction", k)
+			}
+		}
+		// Committing the transaction removes the locks without writing new
+		// versions of the keys.
+		for _, k := range []string{"a", "d"} {
+			intent := roachpb.Intent{
+				Span: roachpb.Span{Key: roachpb.Key(k)}, Txn: txn.TxnMeta, Status: roachpb.COMMITTED,
+			}
+			if err := engine.MVCCResolveWriteIntent(ctx, batch, nil, intent); err != nil {
+				t.Fatal(err)
+			}
+			val, _, err := engine.MVCCGet(ctx, batch, roachpb.Key(k), ts4, engine.MVCCGetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if val == nil || val.Timestamp != ts1 {
+				t.Fatalf("expected %q to only have its version at %s, got %v", k, ts1, val)
+			}
+		}
+	})
+
+	t.Run("skip-reverse-limit", func(t *testing.T) {
+		batch, _, rows, resumeSpan, err := scan(true /* reverse */, roachpb.LOCK_WAIT_SKIP, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer batch.Close()
+		if exp := []string{"d"}; !reflect.DeepEqual(keysOf(rows), exp) {
+			t.Fatalf("expected %v, got %v", exp, keysOf(rows))
+		}
+		if resumeSpan == nil || !resumeSpan.Key.Equal(roachpb.Key("a")) ||
+			!resumeSpan.EndKey.Equal(roachpb.Key("c").Next()) {
+			t.Fatalf("unexpected resume span %v", resumeSpan)
+		}
+	})
+}
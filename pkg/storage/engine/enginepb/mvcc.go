@@ -132,6 +132,12 @@ func (meta MVCCMetadata) IsInline() bool {
 	return meta.RawBytes != nil
 }
 
+// IsLockOnly returns true if the metadata is that of an intent which only
+// locks the key. See the LockOnly field.
+func (meta MVCCMetadata) IsLockOnly() bool {
+	return meta.LockOnly != nil && *meta.LockOnly
+}
+
 // AddToIntentHistory adds the sequence and value to the intent history.
 func (meta *MVCCMetadata) AddToIntentHistory(seq int32, val []byte) {
 	meta.IntentHistory = append(meta.IntentHistory,
@@ -865,6 +865,23 @@ func mvccGetInternal(
 					txn.Epoch, meta.Txn.Epoch)
 			}
 			seekKey = seekKey.Next()
+		} else if ownIntent && roachpb.TxnSeqIsIgnored(meta.Txn.Sequence, txn.IgnoredSeqNums) {
+			// The latest write of the intent has been rolled back to a
+			// savepoint. Read the latest value of the intent history that
+			// hasn't been rolled back, if any. Otherwise, skip the intent.
+			if val, ok := latestUnignoredIntentValue(meta, txn.IgnoredSeqNums); ok {
+				if len(val) == 0 {
+					// The write was a deletion.
+					return nil, nil, safeValue, nil
+				}
+				value := &buf.value
+				*value = roachpb.Value{RawBytes: val, Timestamp: metaTimestamp}
+				if err := value.Verify(metaKey.Key); err != nil {
+					return nil, nil, safeValue, err
+				}
+				return value, nil, safeValue, nil
+			}
+			seekKey = seekKey.Next()
 		}
 	} else if txn != nil && timestamp.Less(txn.MaxTimestamp) {
 		// In this branch, the latest timestamp is ahead, and so the read of an
@@ -939,6 +956,20 @@ func mvccGetInternal(
 	return value, ignoredIntent, allowedSafety, nil
 }
 
+// latestUnignoredIntentValue returns the value of the latest entry of the
+// intent history whose sequence number is not ignored. The returned boolean
+// is false if there is no such entry.
+func latestUnignoredIntentValue(
+	meta *enginepb.MVCCMetadata, ignored []roachpb.IgnoredSeqNumRange,
+) ([]byte, bool) {
+	for i := len(meta.IntentHistory) - 1; i >= 0; i-- {
+		if e := &meta.IntentHistory[i]; !roachpb.TxnSeqIsIgnored(e.Sequence, ignored) {
+			return e.Value, true
+		}
+	}
+	return nil, false
+}
+
 // putBuffer holds pointer data needed by mvccPutInternal. Bundling
 // this data into a single structure reduces memory
 // allocations. Managing this temporary buffer using a sync.Pool
@@ -1074,6 +1105,48 @@ func MVCCDelete(
 
 var noValue = roachpb.Value{}
 
+// MVCCLock locks the key for the transaction by writing an intent which
+// carries the given value, which must be the key's current value as read at
+// timestamp. The intent is marked as lock-only: when the transaction commits,
+// it is removed instead of becoming a new version of the key, so it does not
+// show up in the key's history or in changefeeds. Locks are otherwise regular
+// intents: other transactions conflict with them like with any other write.
+//
+// MVCCLock does nothing if the transaction already wrote or locked the key in
+// its current epoch. A later write of the transaction to the key clears the
+// lock-only marker.
+func MVCCLock(
+	ctx context.Context,
+	eng ReadWriter,
+	ms *enginepb.MVCCStats,
+	key roachpb.Key,
+	timestamp hlc.Timestamp,
+	value roachpb.Value,
+	txn *roachpb.Transaction,
+) error {
+	if txn == nil {
+		return errors.Errorf("%q: locks must be transactional", key)
+	}
+	if value.Timestamp != (hlc.Timestamp{}) {
+		return errors.Errorf("cannot have timestamp set in value on Lock")
+	}
+	iter := eng.NewIterator(IterOptions{Prefix: true})
+	defer iter.Close()
+
+	buf := newPutBuffer()
+	defer buf.release()
+
+	ok, _, _, err := mvccGetMetadata(iter, MakeMVCCMetadataKey(key), &buf.meta)
+	if err != nil {
+		return err
+	}
+	if ok && buf.meta.Txn != nil && buf.meta.Txn.ID == txn.ID && buf.meta.Txn.Epoch == txn.Epoch {
+		return nil
+	}
+	return mvccPutInternal(ctx, eng, iter, ms, key, timestamp, value.RawBytes,
+		txn, buf, nil /* valueFn */, true /* lockOnly */)
+}
+
 // mvccPutUsingIter sets the value for a specified key using the provided
 // Iterator. The function takes a value and a valueFn, only one of which
 // should be provided. If the valueFn is nil, value's raw bytes will be set
@@ -1100,7 +1173,7 @@ func mvccPutUsingIter(
 	buf := newPutBuffer()
 
 	err := mvccPutInternal(ctx, engine, iter, ms, key, timestamp, rawBytes,
-		txn, buf, valueFn)
+		txn, buf, valueFn, false /* lockOnly */)
 
 	// Using defer would be more convenient, but it is measurably slower.
 	buf.release()
@@ -1243,7 +1316,8 @@ func replayTransactionalWrite(
 // the existing value (or nil if none exists) and returns the value
 // to write or an error. If valueFn is supplied, value should be nil
 // and vice versa. valueFn can delete by returning nil. Returning
-// []byte{} will write an empty value, not delete.
+// []byte{} will write an empty value, not delete. If lockOnly is set,
+// the written intent is marked as only locking the key (see MVCCLock).
 //
 // Note that, when writing transactionally, the txn's timestamps
 // dictate the timestamp of the operation, and the timestamp parameter
@@ -1277,6 +1351,7 @@ func mvccPutInternal(
 	txn *roachpb.Transaction,
 	buf *putBuffer,
 	valueFn func(*roachpb.Value) ([]byte, error),
+	lockOnly bool,
 ) error {
 	if len(key) == 0 {
 		return emptyKeyError()
@@ -1443,16 +1518,21 @@ func mvccPutInternal(
 			//
 			// If the epoch of the transaction doesn't match the epoch of the
 			// intent, blow away the intent history.
+			//
+			// If the previous intent was rolled back to a savepoint, it isn't
+			// added to the history either: it must never be read again.
 			if txn.Epoch == meta.Txn.Epoch {
-				// This case shouldn't pop up, but it is worth asserting
-				// that it doesn't. We shouldn't write invalid intents
-				// to the history
-				if existingVal == nil {
-					return errors.Errorf(
-						"previous intent of the transaction with the same epoch not found for %s (%+v)",
-						metaKey, txn)
+				if !roachpb.TxnSeqIsIgnored(prevIntentSequence, txn.IgnoredSeqNums) {
+					// This case shouldn't pop up, but it is worth asserting
+					// that it doesn't. We shouldn't write invalid intents
+					// to the history
+					if existingVal == nil {
+						return errors.Errorf(
+							"previous intent of the transaction with the same epoch not found for %s (%+v)",
+							metaKey, txn)
+					}
+					buf.newMeta.AddToIntentHistory(prevIntentSequence, prevIntentValBytes)
 				}
-				buf.newMeta.AddToIntentHistory(prevIntentSequence, prevIntentValBytes)
 			} else {
 				buf.newMeta.IntentHistory = nil
 			}
@@ -1515,6 +1595,10 @@ func mvccPutInternal(
 	newMeta.KeyBytes = mvccVersionTimestampSize
 	newMeta.ValBytes = int64(len(value))
 	newMeta.Deleted = value == nil
+	if lockOnly {
+		isLockOnly := true
+		newMeta.LockOnly = &isLockOnly
+	}
 
 	var metaKeySize, metaValSize int64
 	if newMeta.Txn != nil {
@@ -1837,7 +1921,7 @@ func MVCCDeleteRange(
 
 	for i := range kvs {
 		err = mvccPutInternal(
-			ctx, engine, iter, ms, kvs[i].Key, timestamp, nil, txn, buf, nil)
+			ctx, engine, iter, ms, kvs[i].Key, timestamp, nil, txn, buf, nil, false /* lockOnly */)
 		if err != nil {
 			break
 		}
@@ -2225,6 +2309,28 @@ func mvccResolveWriteIntent(
 	timestampsValid := !intent.Txn.Timestamp.Less(hlc.Timestamp(meta.Timestamp))
 	commit := intent.Status == roachpb.COMMITTED && epochsMatch && timestampsValid
 
+	// If the transaction rolled back some of its writes to this key to a
+	// savepoint, the intent must be committed with the value of its latest
+	// write that wasn't rolled back. If all of its writes were, the intent
+	// is removed as if the transaction had aborted.
+	if commit && len(intent.IgnoredSeqNums) > 0 {
+		var removeIntent bool
+		removeIntent, origMetaKeySize, origMetaValSize, err = mvccMaybeRewriteIntentHistory(
+			engine, ms, intent.IgnoredSeqNums, metaKey, meta, origMetaKeySize, origMetaValSize, buf)
+		if err != nil {
+			return false, err
+		}
+		if removeIntent {
+			commit = false
+		}
+	}
+
+	// Lock-only intents carry the value which was already committed beneath
+	// them, so they are removed rather than committed. See MVCCLock.
+	if commit && meta.IsLockOnly() {
+		commit = false
+	}
+
 	// Note the small difference to commit epoch handling here: We allow
 	// a push from a previous epoch to move a newer intent. That's not
 	// necessary, but useful for allowing pushers to make forward
@@ -2408,6 +2514,65 @@ func mvccResolveWriteIntent(
 	return true, nil
 }
 
+// mvccMaybeRewriteIntentHistory rewrites the intent described by meta if its
+// latest write has an ignored sequence number, so that it carries the value of
+// the latest entry of the intent history that isn't ignored instead. The
+// returned boolean is true if there is no such entry, in which case the
+// intent is left untouched and must be removed by the caller. The sizes of the
+// metadata key and value after the rewrite are returned as well.
+func mvccMaybeRewriteIntentHistory(
+	engine ReadWriter,
+	ms *enginepb.MVCCStats,
+	ignored []roachpb.IgnoredSeqNumRange,
+	metaKey MVCCKey,
+	meta *enginepb.MVCCMetadata,
+	origMetaKeySize, origMetaValSize int64,
+	buf *putBuffer,
+) (remove bool, metaKeySize, metaValSize int64, err error) {
+	if !roachpb.TxnSeqIsIgnored(meta.Txn.Sequence, ignored) {
+		// The latest write stands. The intent history goes away with the
+		// metadata when the intent is resolved, so there is nothing to do.
+		return false, origMetaKeySize, origMetaValSize, nil
+	}
+	var history []enginepb.MVCCMetadata_SequencedIntent
+	for _, e := range meta.IntentHistory {
+		if !roachpb.TxnSeqIsIgnored(e.Sequence, ignored) {
+			history = append(history, e)
+		}
+	}
+	if len(history) == 0 {
+		return true, origMetaKeySize, origMetaValSize, nil
+	}
+
+	// Replace the value of the intent, at the same timestamp, with the value
+	// of the latest write that wasn't rolled back.
+	last := history[len(history)-1]
+	txnMeta := *meta.Txn
+	txnMeta.Sequence = last.Sequence
+	buf.newMeta = enginepb.MVCCMetadata{
+		Txn:           &txnMeta,
+		Timestamp:     meta.Timestamp,
+		Deleted:       len(last.Value) == 0,
+		KeyBytes:      mvccVersionTimestampSize,
+		ValBytes:      int64(len(last.Value)),
+		IntentHistory: history[:len(history)-1],
+	}
+	versionKey := MVCCKey{Key: metaKey.Key, Timestamp: hlc.Timestamp(meta.Timestamp)}
+	if err := engine.Put(versionKey, last.Value); err != nil {
+		return false, 0, 0, err
+	}
+	metaKeySize, metaValSize, err = buf.putMeta(engine, metaKey, &buf.newMeta)
+	if err != nil {
+		return false, 0, 0, err
+	}
+	if ms != nil {
+		ms.Add(updateStatsOnPut(metaKey.Key, 0 /* prevValSize */, origMetaKeySize, origMetaValSize,
+			metaKeySize, metaValSize, meta, &buf.newMeta))
+	}
+	*meta = buf.newMeta
+	return false, metaKeySize, metaValSize, nil
+}
+
 // IterAndBuf used to pass iterators and buffers between MVCC* calls, allowing
 // reuse without the callers needing to know the particulars.
 type IterAndBuf struct {
@@ -2921,6 +2921,83 @@ func TestMVCCResolveTxn(t *testing.T) {
 	}
 }
 
+// TestMVCCLock verifies that lock-only intents keep the key's value, are
+// removed when their transaction commits, and become regular intents when
+// the transaction writes the key.
+func TestMVCCLock(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	engine := createTestEngine()
+	defer engine.Close()
+
+	ts1 := hlc.Timestamp{WallTime: 1}
+	ts2 := hlc.Timestamp{WallTime: 2}
+	for _, key := range []roachpb.Key{testKey1, testKey2} {
+		if err := MVCCPut(ctx, engine, nil, key, ts1, value1, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	txn := makeTxn(*txn1, ts2)
+	for _, key := range []roachpb.Key{testKey1, testKey2} {
+		if err := MVCCLock(ctx, engine, nil, key, ts2, value1, txn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Locking a key which the transaction already locked does nothing.
+	if err := MVCCLock(ctx, engine, nil, testKey1, ts2, value2, txn); err != nil {
+		t.Fatal(err)
+	}
+	// Writing a locked key turns the lock into a regular intent.
+	txn.Sequence++
+	if err := MVCCPut(ctx, engine, nil, testKey2, ts2, value2, txn); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		key      roachpb.Key
+		lockOnly bool
+	}{
+		{testKey1, true},
+		{testKey2, false},
+	} {
+		var meta enginepb.MVCCMetadata
+		ok, _, _, err := engine.GetProto(MakeMVCCMetadataKey(tc.key), &meta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok || meta.Txn == nil || meta.IsLockOnly() != tc.lockOnly {
+			t.Fatalf("%s: expected an intent with lock-only=%t, got %+v", tc.key, tc.lockOnly, meta)
+		}
+	}
+
+	txn.Status = roachpb.COMMITTED
+	for _, tc := range []struct {
+		key   roachpb.Key
+		value roachpb.Value
+		ts    hlc.Timestamp
+	}{
+		{testKey1, value1, ts1},
+		{testKey2, value2, ts2},
+	} {
+		if err := MVCCResolveWriteIntent(ctx, engine, nil, roachpb.Intent{
+			Span:   roachpb.Span{Key: tc.key},
+			Txn:    txn.TxnMeta,
+			Status: txn.Status,
+		}); err != nil {
+			t.Fatal(err)
+		}
+		value, _, err := MVCCGet(ctx, engine, tc.key, hlc.MaxTimestamp, MVCCGetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if value == nil || !bytes.Equal(tc.value.RawBytes, value.RawBytes) || value.Timestamp != tc.ts {
+			t.Fatalf("%s: expected %q at %s, got %v", tc.key, tc.value.RawBytes, tc.ts, value)
+		}
+	}
+}
+
 // TestMVCCResolveNewerIntent verifies that resolving a newer intent
 // than the committing transaction aborts the intent.
 func TestMVCCResolveNewerIntent(t *testing.T) {
@@ -4757,6 +4834,107 @@ func TestMVCCIntentHistory(t *testing.T) {
 	}
 }
 
+// TestMVCCIgnoredSeqNums verifies that the writes of a transaction whose
+// sequence numbers are ignored are neither read by the transaction nor
+// committed when its intents are resolved.
+func TestMVCCIgnoredSeqNums(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	engine := createTestEngine()
+	defer engine.Close()
+
+	txn := txn1.Clone()
+	r := func(start, end int32) roachpb.IgnoredSeqNumRange {
+		return roachpb.IgnoredSeqNumRange{Start: start, End: end}
+	}
+
+	// Write value1, value2 and value3 to testKey1 and value1 to testKey2,
+	// with increasing sequence numbers.
+	for i, v := range []roachpb.Value{value1, value2, value3} {
+		txn.Sequence = int32(i + 1)
+		if err := MVCCPut(ctx, engine, nil, testKey1, txn.OrigTimestamp, v, &txn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	txn.Sequence = 4
+	if err := MVCCPut(ctx, engine, nil, testKey2, txn.OrigTimestamp, value1, &txn); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		ignored  []roachpb.IgnoredSeqNumRange
+		expected []byte
+	}{
+		{nil, value3.RawBytes},
+		{[]roachpb.IgnoredSeqNumRange{r(3, 4)}, value2.RawBytes},
+		{[]roachpb.IgnoredSeqNumRange{r(2, 4)}, value1.RawBytes},
+		{[]roachpb.IgnoredSeqNumRange{r(2, 2)}, value3.RawBytes},
+		{[]roachpb.IgnoredSeqNumRange{r(1, 1), r(3, 4)}, value2.RawBytes},
+		{[]roachpb.IgnoredSeqNumRange{r(1, 4)}, nil},
+	}
+	for i, tc := range testCases {
+		readTxn := txn.Clone()
+		readTxn.IgnoredSeqNums = tc.ignored
+		value, _, err := MVCCGet(ctx, engine, testKey1, txn.OrigTimestamp, MVCCGetOptions{
+			Txn: &readTxn,
+		})
+		if err != nil {
+			t.Fatalf("%d: %s", i, err)
+		}
+		if tc.expected == nil {
+			if value != nil {
+				t.Errorf("%d: expected no value, got %q", i, value.RawBytes)
+			}
+		} else if value == nil || !bytes.Equal(value.RawBytes, tc.expected) {
+			t.Errorf("%d: expected %q, got %+v", i, tc.expected, value)
+		}
+
+		// Scans read the intents the same way.
+		kvs, _, _, err := MVCCScan(ctx, engine, testKey1, testKey1.Next(), math.MaxInt64,
+			txn.OrigTimestamp, MVCCScanOptions{Txn: &readTxn})
+		if err != nil {
+			t.Fatalf("%d: %s", i, err)
+		}
+		if tc.expected == nil {
+			if len(kvs) != 0 {
+				t.Errorf("%d: expected no value, got %+v", i, kvs)
+			}
+		} else if len(kvs) != 1 || !bytes.Equal(kvs[0].Value.RawBytes, tc.expected) {
+			t.Errorf("%d: expected %q, got %+v", i, tc.expected, kvs)
+		}
+	}
+
+	// Commit the transaction after rolling back the writes with sequence
+	// numbers 3 and 4: testKey1 commits with value2 and testKey2 is removed.
+	txn.Status = roachpb.COMMITTED
+	txn.IgnoredSeqNums = []roachpb.IgnoredSeqNumRange{r(3, 4)}
+	for _, key := range []roachpb.Key{testKey1, testKey2} {
+		if err := MVCCResolveWriteIntent(ctx, engine, nil, roachpb.Intent{
+			Span:           roachpb.Span{Key: key},
+			Status:         txn.Status,
+			Txn:            txn.TxnMeta,
+			IgnoredSeqNums: txn.IgnoredSeqNums,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	value, _, err := MVCCGet(ctx, engine, testKey1, txn.OrigTimestamp, MVCCGetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value == nil || !bytes.Equal(value.RawBytes, value2.RawBytes) {
+		t.Errorf("expected %q, got %+v", value2.RawBytes, value)
+	}
+	value, _, err = MVCCGet(ctx, engine, testKey2, txn.OrigTimestamp, MVCCGetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != nil {
+		t.Errorf("expected no value, got %q", value.RawBytes)
+	}
+}
+
 // TestMVCCTimeSeriesPartialMerge ensures that "partial merges" of merged time
 // series data does not result in a different final result than a "full merge".
 func TestMVCCTimeSeriesPartialMerge(t *testing.T) {
@@ -2512,6 +2512,17 @@ func goToCTxn(txn *roachpb.Transaction) C.DBTxn {
 		r.id = goToCSlice(txn.ID.GetBytes())
 		r.epoch = C.uint32_t(txn.Epoch)
 		r.max_timestamp = goToCTimestamp(txn.MaxTimestamp)
+		if n := len(txn.IgnoredSeqNums); n > 0 {
+			ranges := make([]C.DBIgnoredSeqNumRange, n)
+			for i, ir := range txn.IgnoredSeqNums {
+				ranges[i] = C.DBIgnoredSeqNumRange{
+					start_seqnum: C.int32_t(ir.Start),
+					end_seqnum:   C.int32_t(ir.End),
+				}
+			}
+			r.ignored_seqnums.ranges = &ranges[0]
+			r.ignored_seqnums.len = C.int(n)
+		}
 	}
 	return r
 }
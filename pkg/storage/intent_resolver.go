@@ -428,9 +428,11 @@ func (ir *intentResolver) processWriteIntentError(
 	}
 
 	// Possibly queue this processing if the write intent error is for a
-	// single intent affecting a unitary key.
+	// single intent affecting a unitary key. Pushers which do not wait on the
+	// intent (pushType==PUSH_TOUCH) are never queued.
 	var cleanup func(*roachpb.WriteIntentError, *enginepb.TxnMeta)
-	if len(wiErr.Intents) == 1 && len(wiErr.Intents[0].Span.EndKey) == 0 {
+	if pushType != roachpb.PUSH_TOUCH &&
+		len(wiErr.Intents) == 1 && len(wiErr.Intents[0].Span.EndKey) == 0 {
 		var done bool
 		// Note that the write intent error may be mutated here in the event
 		// that this pusher is queued to wait for a different transaction
@@ -541,6 +543,7 @@ func (ir *intentResolver) maybePushIntents(
 		}
 		intent.Txn = pushee.TxnMeta
 		intent.Status = pushee.Status
+		intent.IgnoredSeqNums = pushee.IgnoredSeqNums
 		resolveIntents = append(resolveIntents, intent)
 	}
 	return resolveIntents, nil
@@ -954,18 +957,20 @@ func (ir *intentResolver) resolveIntents(
 		intent := intents[i] // avoids a race in `i, intent := range ...`
 		if len(intent.EndKey) == 0 {
 			resolveReqs = append(resolveReqs, &roachpb.ResolveIntentRequest{
-				RequestHeader: roachpb.RequestHeaderFromSpan(intent.Span),
-				IntentTxn:     intent.Txn,
-				Status:        intent.Status,
-				Poison:        opts.Poison,
+				RequestHeader:  roachpb.RequestHeaderFromSpan(intent.Span),
+				IntentTxn:      intent.Txn,
+				Status:         intent.Status,
+				Poison:         opts.Poison,
+				IgnoredSeqNums: intent.IgnoredSeqNums,
 			})
 		} else {
 			resolveRangeReqs = append(resolveRangeReqs, &roachpb.ResolveIntentRangeRequest{
-				RequestHeader: roachpb.RequestHeaderFromSpan(intent.Span),
-				IntentTxn:     intent.Txn,
-				Status:        intent.Status,
-				Poison:        opts.Poison,
-				MinTimestamp:  opts.MinTimestamp,
+				RequestHeader:  roachpb.RequestHeaderFromSpan(intent.Span),
+				IntentTxn:      intent.Txn,
+				Status:         intent.Status,
+				Poison:         opts.Poison,
+				MinTimestamp:   opts.MinTimestamp,
+				IgnoredSeqNums: intent.IgnoredSeqNums,
 			})
 		}
 	}
@@ -3135,15 +3135,20 @@ func (s *Store) Send(
 			// Process and resolve write intent error. We do this here because
 			// this is the code path with the requesting client waiting.
 			if pErr.Index != nil {
+				index := pErr.Index
+				args := ba.Requests[index.Index].GetInner()
+
 				var pushType roachpb.PushTxnType
-				if ba.IsWrite() {
+				if roachpb.IsLockingNoWait(args) {
+					// Locking scans which do not wait on conflicting locks only
+					// clean up after finished or abandoned transactions.
+					pushType = roachpb.PUSH_TOUCH
+				} else if ba.IsWrite() {
 					pushType = roachpb.PUSH_ABORT
 				} else {
 					pushType = roachpb.PUSH_TIMESTAMP
 				}
 
-				index := pErr.Index
-				args := ba.Requests[index.Index].GetInner()
 				// Make a copy of the header for the upcoming push; we will update
 				// the timestamp.
 				h := ba.Header
@@ -3168,8 +3173,16 @@ func (s *Store) Send(
 				if cleanupAfterWriteIntentError != nil {
 					cleanupAfterWriteIntentError(t, nil)
 				}
+				wiPErr := pErr
 				if cleanupAfterWriteIntentError, pErr =
 					s.intentResolver.processWriteIntentError(ctx, pErr, args, h, pushType); pErr != nil {
+					if _, ok := pErr.GetDetail().(*roachpb.TransactionPushError); ok &&
+						pushType == roachpb.PUSH_TOUCH {
+						// The conflicting transactions are still active. Return the
+						// conflict to the client rather than an error that would cause
+						// its transaction to retry.
+						pErr = wiPErr
+					}
 					// Do not propagate ambiguous results; assume success and retry original op.
 					if _, ok := pErr.GetDetail().(*roachpb.AmbiguousResultError); !ok {
 						// Preserve the error index.
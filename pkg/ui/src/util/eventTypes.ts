@@ -24,6 +24,10 @@ type Event = protos.cockroach.server.serverpb.EventsResponse.Event;
 export const CREATE_DATABASE = "create_database";
 // Recorded when a database is dropped.
 export const DROP_DATABASE = "drop_database";
+// Recorded when a schema is created.
+export const CREATE_SCHEMA = "create_schema";
+// Recorded when a schema is dropped.
+export const DROP_SCHEMA = "drop_schema";
 // Recorded when a table is created.
 export const CREATE_TABLE = "create_table";
 // Recorded when a table is dropped.
@@ -42,6 +46,18 @@ export const ALTER_INDEX = "alter_index";
 export const CREATE_VIEW = "create_view";
 // Recorded when a view is dropped.
 export const DROP_VIEW = "drop_view";
+// Recorded when a materialized view is refreshed.
+export const REFRESH_MATERIALIZED_VIEW = "refresh_materialized_view";
+// Recorded when a type is created.
+export const CREATE_TYPE = "create_type";
+// Recorded when a type is altered.
+export const ALTER_TYPE = "alter_type";
+// Recorded when a type is dropped.
+export const DROP_TYPE = "drop_type";
+// Recorded when a function is created or replaced.
+export const CREATE_FUNCTION = "create_function";
+// Recorded when a function is dropped.
+export const DROP_FUNCTION = "drop_function";
 // Recorded when a sequence is created.
 export const CREATE_SEQUENCE = "create_sequence";
 // Recorded when a sequence is altered.
@@ -72,11 +88,12 @@ export const REMOVE_ZONE_CONFIG = "remove_zone_config";
 
 // Node Event Types
 export const nodeEvents = [NODE_JOIN, NODE_RESTART, NODE_DECOMMISSIONED, NODE_RECOMMISSIONED];
-export const databaseEvents = [CREATE_DATABASE, DROP_DATABASE];
+export const databaseEvents = [CREATE_DATABASE, DROP_DATABASE, CREATE_SCHEMA, DROP_SCHEMA];
 export const tableEvents = [
   CREATE_TABLE, DROP_TABLE, TRUNCATE_TABLE, ALTER_TABLE, CREATE_INDEX,
-  ALTER_INDEX, DROP_INDEX, CREATE_VIEW, DROP_VIEW, REVERSE_SCHEMA_CHANGE,
-  FINISH_SCHEMA_CHANGE, FINISH_SCHEMA_CHANGE_ROLLBACK,
+  ALTER_INDEX, DROP_INDEX, CREATE_VIEW, DROP_VIEW, REFRESH_MATERIALIZED_VIEW,
+  CREATE_TYPE, ALTER_TYPE, DROP_TYPE, CREATE_FUNCTION, DROP_FUNCTION,
+  REVERSE_SCHEMA_CHANGE, FINISH_SCHEMA_CHANGE, FINISH_SCHEMA_CHANGE_ROLLBACK,
 ];
 export const settingsEvents = [SET_CLUSTER_SETTING, SET_ZONE_CONFIG, REMOVE_ZONE_CONFIG];
 export const allEvents = [...nodeEvents, ...databaseEvents, ...tableEvents, ...settingsEvents];
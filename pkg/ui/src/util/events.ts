@@ -32,6 +32,11 @@ export function getEventDescription(e: Event$Properties): string {
     case eventTypes.DROP_DATABASE:
       const tableDropText = getDroppedObjectsText(info);
       return `Database Dropped: User ${info.User} dropped database ${info.DatabaseName}. ${tableDropText}`;
+    case eventTypes.CREATE_SCHEMA:
+      return `Schema Created: User ${info.User} created schema ${info.SchemaName}`;
+    case eventTypes.DROP_SCHEMA:
+      const schemaDropText = getDroppedObjectsText(info);
+      return `Schema Dropped: User ${info.User} dropped schema ${info.SchemaName}. ${schemaDropText}`;
     case eventTypes.CREATE_TABLE:
       return `Table Created: User ${info.User} created table ${info.TableName}`;
     case eventTypes.DROP_TABLE:
@@ -50,6 +55,18 @@ export function getEventDescription(e: Event$Properties): string {
       return `View Created: User ${info.User} created view ${info.ViewName}`;
     case eventTypes.DROP_VIEW:
       return `View Dropped: User ${info.User} dropped view ${info.ViewName}`;
+    case eventTypes.REFRESH_MATERIALIZED_VIEW:
+      return `Materialized View Refreshed: User ${info.User} refreshed materialized view ${info.ViewName}`;
+    case eventTypes.CREATE_TYPE:
+      return `Type Created: User ${info.User} created type ${info.TypeName}`;
+    case eventTypes.ALTER_TYPE:
+      return `Type Altered: User ${info.User} altered type ${info.TypeName}`;
+    case eventTypes.DROP_TYPE:
+      return `Type Dropped: User ${info.User} dropped type ${info.TypeName}`;
+    case eventTypes.CREATE_FUNCTION:
+      return `Function Created: User ${info.User} created function ${info.FunctionName}`;
+    case eventTypes.DROP_FUNCTION:
+      return `Function Dropped: User ${info.User} dropped function ${info.FunctionName}`;
     case eventTypes.CREATE_SEQUENCE:
       return `Sequence Created: User ${info.User} created sequence ${info.SequenceName}`;
     case eventTypes.ALTER_SEQUENCE:
@@ -89,11 +106,14 @@ export function getEventDescription(e: Event$Properties): string {
 export interface EventInfo {
   User: string;
   DatabaseName?: string;
+  SchemaName?: string;
   TableName?: string;
   IndexName?: string;
   MutationID?: string;
   ViewName?: string;
   SequenceName?: string;
+  TypeName?: string;
+  FunctionName?: string;
   SettingName?: string;
   Value?: string;
   Target?: string;
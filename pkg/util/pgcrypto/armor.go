@@ -0,0 +1,115 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package pgcrypto
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+const (
+	armorHeader    = "-----BEGIN PGP MESSAGE-----"
+	armorFooter    = "-----END PGP MESSAGE-----"
+	armorLineWidth = 76
+)
+
+var errCorruptArmor = pgerror.NewError(pgerror.CodeExternalRoutineInvocationExceptionError,
+	"corrupt ascii-armor")
+
+// crc24 computes the checksum of the ASCII armor, see RFC 4880 section 6.1.
+func crc24(data []byte) uint32 {
+	crc := uint32(0xb704ce)
+	for _, b := range data {
+		crc ^= uint32(b) << 16
+		for i := 0; i < 8; i++ {
+			crc <<= 1
+			if crc&0x1000000 != 0 {
+				crc ^= 0x1864cfb
+			}
+		}
+	}
+	return crc & 0xffffff
+}
+
+func encodeCRC24(data []byte) string {
+	crc := crc24(data)
+	return base64.StdEncoding.EncodeToString([]byte{byte(crc >> 16), byte(crc >> 8), byte(crc)})
+}
+
+// Armor encodes data in the ASCII armor of OpenPGP, which is the base 64
+// encoding of the data with a checksum, between header and footer lines.
+func Armor(data []byte) string {
+	var buf strings.Builder
+	buf.WriteString(armorHeader)
+	buf.WriteString("\n\n")
+	for enc := base64.StdEncoding.EncodeToString(data); len(enc) > 0; {
+		n := armorLineWidth
+		if n > len(enc) {
+			n = len(enc)
+		}
+		buf.WriteString(enc[:n])
+		buf.WriteByte('\n')
+		enc = enc[n:]
+	}
+	buf.WriteByte('=')
+	buf.WriteString(encodeCRC24(data))
+	buf.WriteByte('\n')
+	buf.WriteString(armorFooter)
+	buf.WriteByte('\n')
+	return buf.String()
+}
+
+// Dearmor decodes data encoded in the ASCII armor of OpenPGP. The header
+// lines of the armor are ignored, and the checksum is verified if present.
+func Dearmor(s string) ([]byte, error) {
+	lines := strings.Split(strings.Replace(s, "\r\n", "\n", -1), "\n")
+	i := 0
+	for ; i < len(lines) && !strings.HasPrefix(lines[i], "-----BEGIN PGP "); i++ {
+	}
+	// Skip the armor headers, which end with an empty line.
+	for i++; i < len(lines) && strings.TrimSpace(lines[i]) != ""; i++ {
+	}
+	if i >= len(lines) {
+		return nil, errCorruptArmor
+	}
+
+	var enc strings.Builder
+	crc := ""
+	for i++; ; i++ {
+		if i >= len(lines) {
+			return nil, errCorruptArmor
+		}
+		line := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(line, "-----END PGP ") {
+			break
+		}
+		if strings.HasPrefix(line, "=") {
+			crc = line[1:]
+			continue
+		}
+		enc.WriteString(line)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(enc.String())
+	if err != nil {
+		return nil, errCorruptArmor
+	}
+	if crc != "" && crc != encodeCRC24(data) {
+		return nil, errCorruptArmor
+	}
+	return data, nil
+}
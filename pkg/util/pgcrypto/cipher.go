@@ -0,0 +1,225 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package pgcrypto
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"golang.org/x/crypto/blowfish"
+)
+
+// blockCipher describes a block cipher usable by the encrypt and decrypt
+// functions.
+type blockCipher struct {
+	// keyLen returns the length of the key used for a key of the given
+	// length, which is padded with zeros up to it, or 0 if the key is too
+	// long.
+	keyLen   func(n int) int
+	newBlock func(key []byte) (cipher.Block, error)
+}
+
+var aesCipher = blockCipher{
+	keyLen: func(n int) int {
+		switch {
+		case n <= 16:
+			return 16
+		case n <= 24:
+			return 24
+		case n <= 32:
+			return 32
+		}
+		return 0
+	},
+	newBlock: aes.NewCipher,
+}
+
+var bfCipher = blockCipher{
+	keyLen: func(n int) int {
+		switch {
+		case n == 0:
+			return 1
+		case n <= 56:
+			return n
+		}
+		return 0
+	},
+	newBlock: func(key []byte) (cipher.Block, error) { return blowfish.NewCipher(key) },
+}
+
+// The DES ciphers use the first bytes of their key.
+var desCipher = blockCipher{
+	keyLen:   func(int) int { return 8 },
+	newBlock: des.NewCipher,
+}
+
+var tripleDESCipher = blockCipher{
+	keyLen:   func(int) int { return 24 },
+	newBlock: des.NewTripleDESCipher,
+}
+
+// ciphers maps the algorithm names accepted by encrypt and decrypt to the
+// block ciphers.
+var ciphers = map[string]*blockCipher{
+	"aes":      &aesCipher,
+	"rijndael": &aesCipher,
+	"bf":       &bfCipher,
+	"blowfish": &bfCipher,
+	"des":      &desCipher,
+	"3des":     &tripleDESCipher,
+	"des3":     &tripleDESCipher,
+}
+
+// cipherSpec is a parsed encryption type, which has the form
+// algorithm[-mode][/pad:padding].
+type cipherSpec struct {
+	cipher *blockCipher
+	ecb    bool
+	pad    bool
+}
+
+func parseCipherSpec(typ string) (cipherSpec, error) {
+	spec := cipherSpec{pad: true}
+	errNoSuch := func(what string) error {
+		return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+			"cannot use %q: no such %s", typ, what)
+	}
+
+	s := strings.ToLower(typ)
+	if i := strings.IndexByte(s, '/'); i >= 0 {
+		switch s[i+1:] {
+		case "pad:pkcs":
+		case "pad:none":
+			spec.pad = false
+		default:
+			return spec, errNoSuch("padding")
+		}
+		s = s[:i]
+	}
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		switch s[i+1:] {
+		case "cbc":
+		case "ecb":
+			spec.ecb = true
+		default:
+			return spec, errNoSuch("cipher mode")
+		}
+		s = s[:i]
+	}
+	c, ok := ciphers[s]
+	if !ok {
+		return spec, errNoSuch("cipher algorithm")
+	}
+	spec.cipher = c
+	return spec, nil
+}
+
+func (spec cipherSpec) newBlock(key []byte) (cipher.Block, error) {
+	n := spec.cipher.keyLen(len(key))
+	if n == 0 {
+		return nil, pgerror.NewError(pgerror.CodeExternalRoutineInvocationExceptionError,
+			"key was too big")
+	}
+	paddedKey := make([]byte, n)
+	copy(paddedKey, key)
+	return spec.cipher.newBlock(paddedKey)
+}
+
+func cipherError(op, msg string) error {
+	return pgerror.NewErrorf(pgerror.CodeExternalRoutineInvocationExceptionError, "%s error: %s", op, msg)
+}
+
+// Encrypt encrypts data with key and the initialization vector iv, which
+// may be nil. The encryption type has the form algorithm[-mode][/pad:padding],
+// where the algorithm is one of aes, bf, des or 3des, the mode is cbc (the
+// default) or ecb, and the padding is pkcs (the default) or none.
+//
+// As in pgcrypto, the key and the initialization vector are padded with
+// zeros or truncated to the sizes required by the algorithm.
+func Encrypt(data, key, iv []byte, typ string) ([]byte, error) {
+	spec, err := parseCipherSpec(typ)
+	if err != nil {
+		return nil, err
+	}
+	block, err := spec.newBlock(key)
+	if err != nil {
+		return nil, err
+	}
+	bs := block.BlockSize()
+	if spec.pad {
+		n := bs - len(data)%bs
+		data = append(append([]byte(nil), data...), bytes.Repeat([]byte{byte(n)}, n)...)
+	} else if len(data)%bs != 0 {
+		return nil, cipherError("encrypt", "data is not a multiple of the block size")
+	}
+
+	res := make([]byte, len(data))
+	if spec.ecb {
+		for i := 0; i < len(data); i += bs {
+			block.Encrypt(res[i:i+bs], data[i:i+bs])
+		}
+	} else {
+		cipher.NewCBCEncrypter(block, blockIV(iv, bs)).CryptBlocks(res, data)
+	}
+	return res, nil
+}
+
+// Decrypt decrypts data encrypted by Encrypt with the same key,
+// initialization vector and encryption type.
+func Decrypt(data, key, iv []byte, typ string) ([]byte, error) {
+	spec, err := parseCipherSpec(typ)
+	if err != nil {
+		return nil, err
+	}
+	block, err := spec.newBlock(key)
+	if err != nil {
+		return nil, err
+	}
+	bs := block.BlockSize()
+	if len(data)%bs != 0 {
+		return nil, cipherError("decrypt", "data is not a multiple of the block size")
+	}
+
+	res := make([]byte, len(data))
+	if spec.ecb {
+		for i := 0; i < len(data); i += bs {
+			block.Decrypt(res[i:i+bs], data[i:i+bs])
+		}
+	} else {
+		cipher.NewCBCDecrypter(block, blockIV(iv, bs)).CryptBlocks(res, data)
+	}
+	if spec.pad {
+		if len(res) == 0 {
+			return nil, cipherError("decrypt", "decryption failed")
+		}
+		n := int(res[len(res)-1])
+		if n == 0 || n > bs || !bytes.Equal(res[len(res)-n:], bytes.Repeat([]byte{byte(n)}, n)) {
+			return nil, cipherError("decrypt", "decryption failed")
+		}
+		res = res[:len(res)-n]
+	}
+	return res, nil
+}
+
+// blockIV pads iv with zeros or truncates it to the block size.
+func blockIV(iv []byte, bs int) []byte {
+	res := make([]byte, bs)
+	copy(res, iv)
+	return res
+}
@@ -0,0 +1,156 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package pgcrypto
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestDigest(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	fox := []byte("The quick brown fox jumps over the lazy dog")
+	testCases := []struct {
+		data     []byte
+		key      []byte
+		name     string
+		expected string
+	}{
+		{[]byte("abc"), nil, "sha1", "a9993e364706816aba3e25717850c26c9cd0d89d"},
+		{[]byte("abc"), nil, "SHA224", "23097d223405d8228642a477bda255b32aadbce4bda0b3f7e36c9da7"},
+		{nil, nil, "md5", "d41d8cd98f00b204e9800998ecf8427e"},
+		{fox, []byte("key"), "sha256", "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"},
+		{fox, []byte("key"), "md5", "80070713463e7749b90c2dc24911e275"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var res []byte
+			var err error
+			if tc.key == nil {
+				res, err = Digest(tc.data, tc.name)
+			} else {
+				res, err = HMAC(tc.data, tc.key, tc.name)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := hex.EncodeToString(res); s != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, s)
+			}
+		})
+	}
+
+	if _, err := Digest(nil, "sha3"); !testutils.IsError(err, `cannot use "sha3": no such hash algorithm`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestEncrypt(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	mustDecode := func(s string) []byte {
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	// The expected values were computed with OpenSSL, which is used by
+	// pgcrypto.
+	testCases := []struct {
+		data     string
+		key      string
+		iv       string
+		typ      string
+		expected string
+	}{
+		{``, `foo`, ``, `aes`, `b48cc3338a2eb293b6007ef72c360d48`},
+		{`The quick brown fox`, "\x00\x01\x02\x03\x04\x05\x06\x07\x08\x09\x0a\x0b\x0c\x0d\x0e\x0f" +
+			"\x10\x11\x12\x13\x14\x15\x16\x17\x18\x19\x1a\x1b\x1c\x1d\x1e\x1f",
+			"\x0f\x0e\x0d\x0c\x0b\x0a\x09\x08\x07\x06\x05\x04\x03\x02\x01\x00", `aes-cbc/pad:pkcs`,
+			`925c81ee81fae7d66040fade89896324d5e56344eb8bc837ba31a773eeb145d4`},
+		{`The quick brown fox`, `0123456789abcdefghij`, ``, `AES-ECB`,
+			`4c56dca29a9f7b6e32f2f5931f335f5c7270990dd44e0ebcab97c9b62c9e0d19`},
+		{`0123456789abcdef`, `key`, `iv`, `aes/pad:none`, `734c265c5a54b676f1ce513b48d7616e`},
+		{`hello world`, `key`, ``, `des-ecb`, `642df0136d226c01ec31423e34271dc8`},
+		{`abcdefghijklmnopq`, `key`, "\x00\x01\x02\x03\x04\x05\x06\x07", `3des`,
+			`d618ebe10594339ccfe0c42756f1f4833b7ddd7ec4ad3e1f`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.typ, func(t *testing.T) {
+			res, err := Encrypt([]byte(tc.data), []byte(tc.key), []byte(tc.iv), tc.typ)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(res, mustDecode(tc.expected)) {
+				t.Fatalf("expected %s, got %x", tc.expected, res)
+			}
+			res, err = Decrypt(res, []byte(tc.key), []byte(tc.iv), tc.typ)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(res) != tc.data {
+				t.Fatalf("expected %q, got %q", tc.data, res)
+			}
+		})
+	}
+
+	// Blowfish is only checked by a round trip: OpenSSL pads the short keys
+	// on its command line.
+	for _, typ := range []string{`bf`, `bf-ecb`, `blowfish-cbc/pad:pkcs`} {
+		data := []byte("some data to encrypt")
+		enc, err := Encrypt(data, []byte("key"), nil, typ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dec, err := Decrypt(enc, []byte("key"), nil, typ); err != nil || !bytes.Equal(dec, data) {
+			t.Fatalf("%s: expected %q, got %q (%v)", typ, data, dec, err)
+		}
+	}
+
+	errorCases := []struct {
+		data    string
+		key     string
+		typ     string
+		decrypt bool
+		err     string
+	}{
+		{`abc`, `key`, `cast5`, false, `cannot use "cast5": no such cipher algorithm`},
+		{`abc`, `key`, `aes-cfb`, false, `cannot use "aes-cfb": no such cipher mode`},
+		{`abc`, `key`, `aes/pad:zero`, false, `cannot use "aes/pad:zero": no such padding`},
+		{`abc`, `key`, `aes/pad:none`, false, `encrypt error: data is not a multiple of the block size`},
+		{`abc`, `0123456789abcdef0123456789abcdef0`, `aes`, false, `key was too big`},
+		{`abc`, `key`, `aes`, true, `decrypt error: data is not a multiple of the block size`},
+		{`0123456789abcdef`, `key`, `aes`, true, `decrypt error: decryption failed`},
+	}
+	for _, tc := range errorCases {
+		t.Run(tc.err, func(t *testing.T) {
+			var err error
+			if tc.decrypt {
+				_, err = Decrypt([]byte(tc.data), []byte(tc.key), nil, tc.typ)
+			} else {
+				_, err = Encrypt([]byte(tc.data), []byte(tc.key), nil, tc.typ)
+			}
+			if !testutils.IsError(err, tc.err) {
+				t.Fatalf("expected error %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package pgcrypto
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"golang.org/x/crypto/blowfish"
+)
+
+// The crypt algorithms of pgcrypto. Only the bf and md5 algorithms are
+// supported: the des and xdes algorithms are weak and rarely used.
+const (
+	bfPrefix  = "$2a$"
+	md5Prefix = "$1$"
+
+	bfDefaultRounds = 6
+	bfMinRounds     = 4
+	bfMaxRounds     = 31
+
+	bfSaltLen     = 16
+	bfHashLen     = 23
+	md5SaltLen    = 8
+	md5Iterations = 1000
+)
+
+// The alphabets of the base 64 encodings used by crypt. The bf algorithm
+// orders the characters differently than md5.
+const (
+	md5Alphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	bfAlphabet  = "./ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+)
+
+var bfEncoding = base64.NewEncoding(bfAlphabet).WithPadding(base64.NoPadding)
+
+// bfMagic is the text encrypted by the expensive key schedule of bcrypt.
+var bfMagic = []byte("OrpheanBeholderScryDoubt")
+
+var errInvalidSalt = pgerror.NewError(pgerror.CodeInvalidParameterValueError, "invalid salt")
+
+func unsupportedAlgorithmError(name string) error {
+	return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+		"crypt algorithm %q is not supported", name)
+}
+
+// GenSalt generates a random salt for Crypt. The algorithm is either bf or
+// md5. Only bf has a number of rounds, between 4 and 31, which is the base
+// 2 logarithm of its number of iterations; zero selects the default. As in
+// pgcrypto, the number of rounds is ignored for md5.
+func GenSalt(algorithm string, rounds int) (string, error) {
+	switch strings.ToLower(algorithm) {
+	case "bf":
+		if rounds == 0 {
+			rounds = bfDefaultRounds
+		} else if rounds < bfMinRounds || rounds > bfMaxRounds {
+			return "", pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+				"incorrect number of rounds for %q: %d", algorithm, rounds)
+		}
+		salt := make([]byte, bfSaltLen)
+		if _, err := rand.Read(salt); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s%02d$%s", bfPrefix, rounds, bfEncoding.EncodeToString(salt)), nil
+
+	case "md5":
+		salt := make([]byte, md5SaltLen)
+		if _, err := rand.Read(salt); err != nil {
+			return "", err
+		}
+		for i := range salt {
+			salt[i] = md5Alphabet[salt[i]&0x3f]
+		}
+		return md5Prefix + string(salt), nil
+
+	case "des", "xdes":
+		return "", unsupportedAlgorithmError(algorithm)
+	}
+	return "", pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+		"unknown salt algorithm %q", algorithm)
+}
+
+// Crypt hashes password with salt, in the format of crypt(3). The
+// algorithm is selected by salt, which is either generated by GenSalt, or
+// a hash previously returned by Crypt: a password can then be checked by
+// comparing the stored hash with Crypt(password, hash). The bf algorithm
+// can run for a long time with many rounds: it stops early with the error
+// of ctx when ctx is canceled.
+func Crypt(ctx context.Context, password, salt string) (string, error) {
+	switch {
+	case strings.HasPrefix(salt, "$2a$"), strings.HasPrefix(salt, "$2b$"),
+		strings.HasPrefix(salt, "$2y$"):
+		return bfCrypt(ctx, password, salt)
+	case strings.HasPrefix(salt, md5Prefix):
+		return md5Crypt(password, salt), nil
+	case strings.HasPrefix(salt, "_"):
+		return "", unsupportedAlgorithmError("xdes")
+	}
+	return "", unsupportedAlgorithmError("des")
+}
+
+// bfCrypt implements the bcrypt algorithm. Its salt has the form
+// $2a$NN$SSSSSSSSSSSSSSSSSSSSSS, where NN is the number of rounds and the
+// S are the 16 bytes of salt in base 64.
+//
+// golang.org/x/crypto/bcrypt, which hashes the passwords of the SQL users,
+// cannot be used here: it always generates a random salt, while crypt must
+// reproduce the hash of the salt it is given, so that a password can be
+// checked against a stored hash with the same function.
+func bfCrypt(ctx context.Context, password, salt string) (string, error) {
+	const saltStart = len(bfPrefix) + 3
+	if len(salt) < saltStart+22 || salt[saltStart-1] != '$' {
+		return "", errInvalidSalt
+	}
+	rounds, err := strconv.Atoi(salt[len(bfPrefix) : saltStart-1])
+	if err != nil || rounds < bfMinRounds || rounds > bfMaxRounds {
+		return "", errInvalidSalt
+	}
+	// The last character of the salt only has 2 significant bits: the
+	// decoding ignores the others, and the salt is encoded again below.
+	rawSalt, err := bfEncoding.DecodeString(salt[saltStart : saltStart+22])
+	if err != nil {
+		return "", errInvalidSalt
+	}
+
+	// As in the C implementations, the key includes its terminating NUL
+	// byte. The key schedule only uses its first 72 bytes.
+	key := append([]byte(password), 0)
+	c, err := blowfish.NewSaltedCipher(key, rawSalt)
+	if err != nil {
+		return "", err
+	}
+	for i := uint64(0); i < 1<<uint(rounds); i++ {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		blowfish.ExpandKey(key, c)
+		blowfish.ExpandKey(rawSalt, c)
+	}
+	hash := make([]byte, len(bfMagic))
+	copy(hash, bfMagic)
+	for i := 0; i < len(hash); i += 8 {
+		for j := 0; j < 64; j++ {
+			c.Encrypt(hash[i:i+8], hash[i:i+8])
+		}
+	}
+
+	// Only 23 of the 24 bytes of the hash are encoded, for compatibility
+	// with the original implementation.
+	return fmt.Sprintf("%s%02d$%s%s", salt[:len(bfPrefix)], rounds,
+		bfEncoding.EncodeToString(rawSalt), bfEncoding.EncodeToString(hash[:bfHashLen])), nil
+}
+
+// md5Crypt implements the MD5-based algorithm of FreeBSD. Its salt has the
+// form $1$SSSSSSSS, with up to 8 characters of salt.
+func md5Crypt(password, salt string) string {
+	salt = salt[len(md5Prefix):]
+	if i := strings.IndexByte(salt, '$'); i >= 0 {
+		salt = salt[:i]
+	}
+	if len(salt) > md5SaltLen {
+		salt = salt[:md5SaltLen]
+	}
+	pw := []byte(password)
+
+	alt := md5.New()
+	_, _ = alt.Write(pw)
+	_, _ = alt.Write([]byte(salt))
+	_, _ = alt.Write(pw)
+	altSum := alt.Sum(nil)
+
+	h := md5.New()
+	_, _ = h.Write(pw)
+	_, _ = h.Write([]byte(md5Prefix))
+	_, _ = h.Write([]byte(salt))
+	for i := len(pw); i > 0; i -= md5.Size {
+		if i > md5.Size {
+			_, _ = h.Write(altSum)
+		} else {
+			_, _ = h.Write(altSum[:i])
+		}
+	}
+	// This writes the first byte of the password, or its terminating NUL
+	// byte, for compatibility with the original implementation.
+	first := byte(0)
+	if len(pw) > 0 {
+		first = pw[0]
+	}
+	for i := len(pw); i > 0; i >>= 1 {
+		if i&1 != 0 {
+			_, _ = h.Write([]byte{0})
+		} else {
+			_, _ = h.Write([]byte{first})
+		}
+	}
+	sum := h.Sum(nil)
+
+	for i := 0; i < md5Iterations; i++ {
+		h := md5.New()
+		if i&1 != 0 {
+			_, _ = h.Write(pw)
+		} else {
+			_, _ = h.Write(sum)
+		}
+		if i%3 != 0 {
+			_, _ = h.Write([]byte(salt))
+		}
+		if i%7 != 0 {
+			_, _ = h.Write(pw)
+		}
+		if i&1 != 0 {
+			_, _ = h.Write(sum)
+		} else {
+			_, _ = h.Write(pw)
+		}
+		sum = h.Sum(nil)
+	}
+
+	var buf strings.Builder
+	buf.WriteString(md5Prefix)
+	buf.WriteString(salt)
+	buf.WriteByte('$')
+	to64 := func(v uint32, n int) {
+		for ; n > 0; n-- {
+			buf.WriteByte(md5Alphabet[v&0x3f])
+			v >>= 6
+		}
+	}
+	for _, i := range [][3]int{{0, 6, 12}, {1, 7, 13}, {2, 8, 14}, {3, 9, 15}, {4, 10, 5}} {
+		to64(uint32(sum[i[0]])<<16|uint32(sum[i[1]])<<8|uint32(sum[i[2]]), 4)
+	}
+	to64(uint32(sum[11]), 2)
+	return buf.String()
+}
@@ -0,0 +1,150 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package pgcrypto
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestCrypt(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		password string
+		salt     string
+		expected string
+		err      string
+	}{
+		{`abc`, `$1$saltsalt`, `$1$saltsalt$7fq7ZEc75157blrz18yX./`, ``},
+		{``, `$1$saltsalt`, `$1$saltsalt$5Jhcit4zN9UlGiA0txPkO0`, ``},
+		{strings.Repeat(`a`, 40), `$1$ab`, `$1$ab$cU148AGxT4doatxfHOrbD1`, ``},
+		{`password`, `$1$12345678$o2n/JiO/h5VviOInWJ4OQ/`, `$1$12345678$o2n/JiO/h5VviOInWJ4OQ/`, ``},
+		{`password`, `$1$123456789`, `$1$12345678$o2n/JiO/h5VviOInWJ4OQ/`, ``},
+		{`allmine`, `$2a$10$XajjQvNhvvRt5GSeFk1xFe`,
+			`$2a$10$XajjQvNhvvRt5GSeFk1xFeyqRrsxkhBkUiQeg0dt.wU1qD4aFDcga`, ``},
+		{`password`, `$2a$06$RQiOJ.3ELirrXwxIZY8q0Opor.9oUAL1MojNiw3NUwpUDdI7wuxEa`,
+			`$2a$06$RQiOJ.3ELirrXwxIZY8q0Opor.9oUAL1MojNiw3NUwpUDdI7wuxEa`, ``},
+		{`password`, `$2a$06$RQiOJ.3ELirrXwxIZY8q0O`,
+			`$2a$06$RQiOJ.3ELirrXwxIZY8q0Opor.9oUAL1MojNiw3NUwpUDdI7wuxEa`, ``},
+		{`password`, `$2a$06$RQiOJ.3ELirrXwxIZY8q0`, ``, `invalid salt`},
+		{`password`, `$2a$03$RQiOJ.3ELirrXwxIZY8q0O`, ``, `invalid salt`},
+		{`password`, `ab`, ``, `crypt algorithm "des" is not supported`},
+		{`password`, `_J9..rasm`, ``, `crypt algorithm "xdes" is not supported`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.salt, func(t *testing.T) {
+			res, err := Crypt(context.Background(), tc.password, tc.salt)
+			if tc.err != "" {
+				if !testutils.IsError(err, tc.err) {
+					t.Fatalf("expected error %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestGenSalt(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		algorithm string
+		rounds    int
+		pattern   string
+		err       string
+	}{
+		{`bf`, 0, `^\$2a\$06\$[./A-Za-z0-9]{22}$`, ``},
+		{`BF`, 4, `^\$2a\$04\$[./A-Za-z0-9]{22}$`, ``},
+		{`bf`, 3, ``, `incorrect number of rounds`},
+		{`bf`, 32, ``, `incorrect number of rounds`},
+		{`md5`, 0, `^\$1\$[./A-Za-z0-9]{8}$`, ``},
+		{`md5`, 1000, `^\$1\$[./A-Za-z0-9]{8}$`, ``},
+		{`des`, 0, ``, `crypt algorithm "des" is not supported`},
+		{`foo`, 0, ``, `unknown salt algorithm "foo"`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.algorithm, func(t *testing.T) {
+			ctx := context.Background()
+			salt, err := GenSalt(tc.algorithm, tc.rounds)
+			if tc.err != "" {
+				if !testutils.IsError(err, tc.err) {
+					t.Fatalf("expected error %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !regexp.MustCompile(tc.pattern).MatchString(salt) {
+				t.Fatalf("expected a salt matching %s, got %s", tc.pattern, salt)
+			}
+			hash, err := Crypt(ctx, "password", salt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if again, err := Crypt(ctx, "password", hash); err != nil || again != hash {
+				t.Fatalf("expected %s, got %s (%v)", hash, again, err)
+			}
+			if other, err := Crypt(ctx, "other", hash); err != nil || other == hash {
+				t.Fatalf("expected a different hash than %s, got %s (%v)", hash, other, err)
+			}
+		})
+	}
+}
+
+// TestCryptCancel checks that the bf algorithm stops when its context is
+// canceled, instead of running all the iterations of its rounds.
+func TestCryptCancel(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Crypt(ctx, "password", "$2a$31$RQiOJ.3ELirrXwxIZY8q0O"); err != context.Canceled {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+}
+
+// TestBcryptCompatibility checks the hashes of the bf algorithm against the
+// bcrypt implementation used for the passwords of the SQL users.
+func TestBcryptCompatibility(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, password := range []string{"", "a", "password", strings.Repeat("long", 20), "π ≈ 3.14"} {
+		salt, err := GenSalt("bf", 4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hash, err := Crypt(context.Background(), password, salt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+			t.Errorf("%q: %s: %v", password, hash, err)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package pgcrypto implements the functions of the pgcrypto extension of
+// PostgreSQL: keyed and unkeyed hashes, password hashing with crypt and
+// gen_salt, raw encryption with encrypt and decrypt, and symmetric-key
+// OpenPGP encryption with pgp_sym_encrypt and pgp_sym_decrypt.
+//
+// The outputs are compatible with the ones of pgcrypto, so that data
+// hashed or encrypted by PostgreSQL can be checked or decrypted by
+// CockroachDB, and vice versa.
+//
+// See: https://www.postgresql.org/docs/current/static/pgcrypto.html
+package pgcrypto
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// hashes maps the names accepted by digest and hmac to the hash functions.
+var hashes = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha224": sha256.New224,
+	"sha256": sha256.New,
+	"sha384": sha512.New384,
+	"sha512": sha512.New,
+}
+
+func newHash(name string) (func() hash.Hash, error) {
+	if h, ok := hashes[strings.ToLower(name)]; ok {
+		return h, nil
+	}
+	return nil, pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+		"cannot use %q: no such hash algorithm", name)
+}
+
+// Digest computes the binary hash of data with the named hash function,
+// which is one of md5, sha1, sha224, sha256, sha384 or sha512.
+func Digest(data []byte, name string) ([]byte, error) {
+	newFn, err := newHash(name)
+	if err != nil {
+		return nil, err
+	}
+	h := newFn()
+	_, _ = h.Write(data)
+	return h.Sum(nil), nil
+}
+
+// HMAC computes the hashed MAC of data with key, using the named hash
+// function as in Digest.
+func HMAC(data, key []byte, name string) ([]byte, error) {
+	newFn, err := newHash(name)
+	if err != nil {
+		return nil, err
+	}
+	h := hmac.New(newFn, key)
+	_, _ = h.Write(data)
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,753 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package pgcrypto
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"compress/flate"
+	"compress/zlib"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/binary"
+	"hash"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"golang.org/x/crypto/blowfish"
+)
+
+// This file implements the symmetric-key encryption of OpenPGP messages, as
+// defined by RFC 4880. As in pgcrypto, the messages are made of a
+// symmetric-key encrypted session key packet, followed by a symmetrically
+// encrypted and integrity protected data packet, containing a literal data
+// packet, which may be compressed.
+
+// Packet tags, see RFC 4880 section 4.3.
+const (
+	tagSymKeyEncryptedSessionKey = 3
+	tagCompressedData            = 8
+	tagSymEncryptedData          = 9
+	tagMarker                    = 10
+	tagLiteralData               = 11
+	tagSymEncryptedMDCData       = 18
+)
+
+// The modification detection code packet is always made of these two bytes
+// followed by the SHA-1 hash of the decrypted data.
+var mdcHeader = []byte{0xd3, sha1.Size}
+
+// Compression algorithms, see RFC 4880 section 9.3.
+const (
+	compressNone = iota
+	compressZIP
+	compressZLIB
+	compressBZIP2
+)
+
+// maxDecompressedSize is the maximum size of the data of a compressed
+// message, which a small message could otherwise expand without bound.
+const maxDecompressedSize = 64 << 20
+
+// decompressChunkSize is the initial size of the buffer of decompressed
+// data.
+const decompressChunkSize = 64 << 10
+
+var (
+	errWrongKey = pgerror.NewError(pgerror.CodeExternalRoutineInvocationExceptionError,
+		"wrong key or corrupt data")
+	errNotText = pgerror.NewError(pgerror.CodeExternalRoutineInvocationExceptionError,
+		"not text data")
+	errUnsupportedCipher = pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+		"unsupported cipher algorithm")
+	errNestedCompression = pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+		"nested compressed data is not supported")
+	errDecompressedTooLarge = pgerror.NewErrorf(pgerror.CodeProgramLimitExceededError,
+		"decompressed data exceeds %d bytes", maxDecompressedSize)
+)
+
+// pgpCipher is a symmetric cipher of OpenPGP, see RFC 4880 section 9.2.
+type pgpCipher struct {
+	id       byte
+	keyLen   int
+	newBlock func(key []byte) (cipher.Block, error)
+}
+
+var pgpCiphers = map[string]pgpCipher{
+	"3des":   {id: 2, keyLen: 24, newBlock: des.NewTripleDESCipher},
+	"bf":     {id: 4, keyLen: 16, newBlock: func(key []byte) (cipher.Block, error) { return blowfish.NewCipher(key) }},
+	"aes128": {id: 7, keyLen: 16, newBlock: aes.NewCipher},
+	"aes192": {id: 8, keyLen: 24, newBlock: aes.NewCipher},
+	"aes256": {id: 9, keyLen: 32, newBlock: aes.NewCipher},
+}
+
+func pgpCipherByID(id byte) (pgpCipher, error) {
+	for _, c := range pgpCiphers {
+		if c.id == id {
+			return c, nil
+		}
+	}
+	return pgpCipher{}, errUnsupportedCipher
+}
+
+// pgpHashes maps the ids of the hash algorithms of OpenPGP, see RFC 4880
+// section 9.4, to the hash functions. Only md5 and sha1 can be selected for
+// encryption, but all are accepted for decryption.
+var pgpHashes = map[byte]func() hash.Hash{
+	1:  md5.New,
+	2:  sha1.New,
+	8:  sha256.New,
+	9:  sha512.New384,
+	10: sha512.New,
+	11: sha256.New224,
+}
+
+var pgpHashIDs = map[string]byte{
+	"md5":  1,
+	"sha1": 2,
+}
+
+// pgpOptions are the options of the pgp_sym_encrypt and pgp_sym_decrypt
+// functions.
+type pgpOptions struct {
+	cipher        pgpCipher
+	s2kCipher     pgpCipher
+	compressAlgo  int
+	compressLevel int
+	convertCRLF   bool
+	sessKey       bool
+	s2kMode       byte
+	// s2kCount is the iteration count of the s2k-mode 3, or 0 for a random
+	// one.
+	s2kCount    int
+	s2kDigest   byte
+	unicodeMode bool
+}
+
+// parsePGPOptions parses options of the form "name=value, name=value".
+func parsePGPOptions(options string) (pgpOptions, error) {
+	opts := pgpOptions{
+		cipher:        pgpCiphers["aes128"],
+		compressLevel: 6,
+		s2kMode:       3,
+		s2kDigest:     pgpHashIDs["sha1"],
+	}
+	s2kCipher := ""
+	for _, opt := range strings.Split(options, ",") {
+		opt = strings.TrimSpace(opt)
+		if opt == "" {
+			continue
+		}
+		eq := strings.IndexByte(opt, '=')
+		if eq < 0 {
+			return opts, pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+				"invalid option %q", opt)
+		}
+		name, value := strings.TrimSpace(opt[:eq]), strings.TrimSpace(opt[eq+1:])
+		invalidValue := pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+			"invalid value %q for option %q", value, name)
+		intValue := func(min, max int) (int, error) {
+			i, err := strconv.Atoi(value)
+			if err != nil || i < min || i > max {
+				return 0, invalidValue
+			}
+			return i, nil
+		}
+		var i int
+		var err error
+		switch name {
+		case "cipher-algo", "s2k-cipher-algo":
+			if _, ok := pgpCiphers[value]; !ok {
+				if value == "cast5" {
+					return opts, errUnsupportedCipher
+				}
+				return opts, invalidValue
+			}
+			if name == "cipher-algo" {
+				opts.cipher = pgpCiphers[value]
+			} else {
+				s2kCipher = value
+			}
+		case "compress-algo":
+			i, err = intValue(compressNone, compressZLIB)
+			opts.compressAlgo = i
+		case "compress-level":
+			i, err = intValue(0, 9)
+			opts.compressLevel = i
+		case "convert-crlf":
+			i, err = intValue(0, 1)
+			opts.convertCRLF = i == 1
+		case "disable-mdc":
+			i, err = intValue(0, 1)
+			if i == 1 {
+				return opts, pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+					"disabling the modification detection code is not supported")
+			}
+		case "sess-key":
+			i, err = intValue(0, 1)
+			opts.sessKey = i == 1
+		case "s2k-mode":
+			i, err = intValue(0, 3)
+			if i == 2 {
+				err = invalidValue
+			}
+			opts.s2kMode = byte(i)
+		case "s2k-count":
+			i, err = intValue(1024, 65011712)
+			opts.s2kCount = i
+		case "s2k-digest-algo":
+			id, ok := pgpHashIDs[value]
+			if !ok {
+				return opts, invalidValue
+			}
+			opts.s2kDigest = id
+		case "unicode-mode":
+			i, err = intValue(0, 1)
+			opts.unicodeMode = i == 1
+		default:
+			return opts, pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+				"invalid option %q", name)
+		}
+		if err != nil {
+			return opts, err
+		}
+	}
+	opts.s2kCipher = opts.cipher
+	if s2kCipher != "" {
+		opts.s2kCipher = pgpCiphers[s2kCipher]
+	}
+	return opts, nil
+}
+
+// s2k is a string-to-key specifier, see RFC 4880 section 3.7.
+type s2k struct {
+	mode   byte
+	hashID byte
+	salt   []byte
+	count  byte
+}
+
+func decodeS2KCount(c byte) int {
+	return (16 + int(c&15)) << (uint(c>>4) + 6)
+}
+
+// encodeS2KCount returns the smallest encoded count which is at least the
+// given one.
+func encodeS2KCount(count int) byte {
+	for c := 0; c < 255; c++ {
+		if decodeS2KCount(byte(c)) >= count {
+			return byte(c)
+		}
+	}
+	return 255
+}
+
+func (s s2k) marshal() []byte {
+	res := []byte{s.mode, s.hashID}
+	if s.mode != 0 {
+		res = append(res, s.salt...)
+	}
+	if s.mode == 3 {
+		res = append(res, s.count)
+	}
+	return res
+}
+
+func parseS2K(b []byte) (s2k, []byte, error) {
+	if len(b) < 2 {
+		return s2k{}, nil, errWrongKey
+	}
+	s := s2k{mode: b[0], hashID: b[1]}
+	b = b[2:]
+	switch s.mode {
+	case 0:
+	case 1, 3:
+		if len(b) < 8 {
+			return s, nil, errWrongKey
+		}
+		s.salt, b = b[:8], b[8:]
+		if s.mode == 3 {
+			if len(b) < 1 {
+				return s, nil, errWrongKey
+			}
+			s.count, b = b[0], b[1:]
+		}
+	default:
+		return s, nil, errWrongKey
+	}
+	return s, b, nil
+}
+
+// key derives a key of the given length from password.
+func (s s2k) key(password []byte, keyLen int) ([]byte, error) {
+	newHash, ok := pgpHashes[s.hashID]
+	if !ok {
+		return nil, pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+			"unsupported digest algorithm")
+	}
+	var key []byte
+	// When the hash is shorter than the key, the key is made of several
+	// hashes, which are preloaded with an increasing number of zeros.
+	for i := 0; len(key) < keyLen; i++ {
+		h := newHash()
+		_, _ = h.Write(make([]byte, i))
+		switch s.mode {
+		case 0:
+			_, _ = h.Write(password)
+		case 1:
+			_, _ = h.Write(s.salt)
+			_, _ = h.Write(password)
+		case 3:
+			data := append(append([]byte(nil), s.salt...), password...)
+			count := decodeS2KCount(s.count)
+			if count < len(data) {
+				count = len(data)
+			}
+			for ; count > len(data); count -= len(data) {
+				_, _ = h.Write(data)
+			}
+			_, _ = h.Write(data[:count])
+		}
+		key = h.Sum(key)
+	}
+	return key[:keyLen], nil
+}
+
+func writePacket(buf *bytes.Buffer, tag byte, body []byte) {
+	buf.WriteByte(0xc0 | tag)
+	switch n := len(body); {
+	case n < 192:
+		buf.WriteByte(byte(n))
+	case n < 8384:
+		n -= 192
+		buf.WriteByte(byte(n>>8) + 192)
+		buf.WriteByte(byte(n))
+	default:
+		var b [5]byte
+		b[0] = 0xff
+		binary.BigEndian.PutUint32(b[1:], uint32(n))
+		buf.Write(b[:])
+	}
+	buf.Write(body)
+}
+
+// readPacket reads the first packet of b, in the old or the new format, see
+// RFC 4880 section 4.2.
+func readPacket(b []byte) (tag byte, body []byte, rest []byte, err error) {
+	if len(b) == 0 || b[0]&0x80 == 0 {
+		return 0, nil, nil, errWrongKey
+	}
+	header := b[0]
+	b = b[1:]
+
+	if header&0x40 == 0 {
+		// Old format.
+		tag = (header >> 2) & 0xf
+		var n int
+		switch header & 3 {
+		case 0:
+			if len(b) < 1 {
+				return 0, nil, nil, errWrongKey
+			}
+			n, b = int(b[0]), b[1:]
+		case 1:
+			if len(b) < 2 {
+				return 0, nil, nil, errWrongKey
+			}
+			n, b = int(binary.BigEndian.Uint16(b)), b[2:]
+		case 2:
+			if len(b) < 4 {
+				return 0, nil, nil, errWrongKey
+			}
+			n, b = int(binary.BigEndian.Uint32(b)), b[4:]
+		default:
+			// The packet extends to the end of the message.
+			n = len(b)
+		}
+		if n < 0 || n > len(b) {
+			return 0, nil, nil, errWrongKey
+		}
+		return tag, b[:n], b[n:], nil
+	}
+
+	// New format, where the body may be split in parts.
+	tag = header & 0x3f
+	for {
+		if len(b) == 0 {
+			return 0, nil, nil, errWrongKey
+		}
+		var n int
+		partial := false
+		switch l := int(b[0]); {
+		case l < 192:
+			n, b = l, b[1:]
+		case l < 224:
+			if len(b) < 2 {
+				return 0, nil, nil, errWrongKey
+			}
+			n, b = (l-192)<<8+int(b[1])+192, b[2:]
+		case l == 255:
+			if len(b) < 5 {
+				return 0, nil, nil, errWrongKey
+			}
+			n, b = int(binary.BigEndian.Uint32(b[1:])), b[5:]
+		default:
+			n, b, partial = 1<<uint(l&0x1f), b[1:], true
+		}
+		if n < 0 || n > len(b) {
+			return 0, nil, nil, errWrongKey
+		}
+		body = append(body, b[:n]...)
+		b = b[n:]
+		if !partial {
+			return tag, body, b, nil
+		}
+	}
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	_, err := rand.Read(b)
+	return b, err
+}
+
+// PGPSymEncrypt encrypts data as an OpenPGP message, with a key derived
+// from password. The data is marked as text when text is true. The options
+// are the ones of pgcrypto, in the form "name=value, name=value".
+func PGPSymEncrypt(data, password []byte, text bool, options string) ([]byte, error) {
+	opts, err := parsePGPOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	if text && opts.convertCRLF {
+		data = bytes.Replace(data, []byte("\n"), []byte("\r\n"), -1)
+	}
+
+	// The literal data packet has no file name, and the current time as
+	// modification time.
+	var inner bytes.Buffer
+	literal := make([]byte, 6, 6+len(data))
+	literal[0] = 'b'
+	if text {
+		literal[0] = 't'
+		if opts.unicodeMode {
+			literal[0] = 'u'
+		}
+	}
+	binary.BigEndian.PutUint32(literal[2:], uint32(timeutil.Now().Unix()))
+	writePacket(&inner, tagLiteralData, append(literal, data...))
+
+	if opts.compressAlgo != compressNone {
+		var compressed bytes.Buffer
+		compressed.WriteByte(byte(opts.compressAlgo))
+		var w io.WriteCloser
+		if opts.compressAlgo == compressZIP {
+			w, err = flate.NewWriter(&compressed, opts.compressLevel)
+		} else {
+			w, err = zlib.NewWriterLevel(&compressed, opts.compressLevel)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(inner.Bytes()); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		inner.Reset()
+		writePacket(&inner, tagCompressedData, compressed.Bytes())
+	}
+
+	s := s2k{mode: opts.s2kMode, hashID: opts.s2kDigest}
+	if s.mode != 0 {
+		if s.salt, err = randomBytes(8); err != nil {
+			return nil, err
+		}
+	}
+	if s.mode == 3 {
+		if opts.s2kCount != 0 {
+			s.count = encodeS2KCount(opts.s2kCount)
+		} else {
+			// As in pgcrypto, the default count is random, between 65536 and
+			// 253952.
+			c, err := randomBytes(1)
+			if err != nil {
+				return nil, err
+			}
+			s.count = 0x60 + c[0]&0x1f
+		}
+	}
+
+	var res bytes.Buffer
+	var key []byte
+	if opts.sessKey {
+		// The session key is random, and encrypted with the key derived from
+		// the password.
+		if key, err = randomBytes(opts.cipher.keyLen); err != nil {
+			return nil, err
+		}
+		s2kKey, err := s.key(password, opts.s2kCipher.keyLen)
+		if err != nil {
+			return nil, err
+		}
+		block, err := opts.s2kCipher.newBlock(s2kKey)
+		if err != nil {
+			return nil, err
+		}
+		encryptedKey := append([]byte{opts.cipher.id}, key...)
+		cipher.NewCFBEncrypter(block, make([]byte, block.BlockSize())).
+			XORKeyStream(encryptedKey, encryptedKey)
+		body := append([]byte{4, opts.s2kCipher.id}, s.marshal()...)
+		writePacket(&res, tagSymKeyEncryptedSessionKey, append(body, encryptedKey...))
+	} else {
+		if key, err = s.key(password, opts.cipher.keyLen); err != nil {
+			return nil, err
+		}
+		body := append([]byte{4, opts.cipher.id}, s.marshal()...)
+		writePacket(&res, tagSymKeyEncryptedSessionKey, body)
+	}
+
+	block, err := opts.cipher.newBlock(key)
+	if err != nil {
+		return nil, err
+	}
+	bs := block.BlockSize()
+	// The encrypted data starts with random bytes, whose last two are
+	// repeated to detect a wrong key, and ends with the modification
+	// detection code.
+	plain, err := randomBytes(bs)
+	if err != nil {
+		return nil, err
+	}
+	plain = append(plain, plain[bs-2:]...)
+	plain = append(plain, inner.Bytes()...)
+	plain = append(plain, mdcHeader...)
+	mdc := sha1.Sum(plain)
+	plain = append(plain, mdc[:]...)
+	encrypted := make([]byte, 1+len(plain))
+	encrypted[0] = 1
+	cipher.NewCFBEncrypter(block, make([]byte, bs)).XORKeyStream(encrypted[1:], plain)
+	writePacket(&res, tagSymEncryptedMDCData, encrypted)
+	return res.Bytes(), nil
+}
+
+// PGPSymDecrypt decrypts an OpenPGP message encrypted with a key derived
+// from password, such as the ones produced by PGPSymEncrypt. When text is
+// true, the data must be marked as text. The memory used by the data of a
+// compressed message is accounted for in acc, unless acc is nil.
+func PGPSymDecrypt(
+	ctx context.Context, acc *mon.BoundAccount, msg, password []byte, text bool, options string,
+) ([]byte, error) {
+	opts, err := parsePGPOptions(options)
+	if err != nil {
+		return nil, err
+	}
+
+	var sessionKeyPacket, encrypted []byte
+	for len(msg) > 0 && encrypted == nil {
+		tag, body, rest, err := readPacket(msg)
+		if err != nil {
+			return nil, err
+		}
+		msg = rest
+		switch tag {
+		case tagSymKeyEncryptedSessionKey:
+			if sessionKeyPacket == nil {
+				sessionKeyPacket = body
+			}
+		case tagMarker:
+		case tagSymEncryptedMDCData:
+			encrypted = body
+		case tagSymEncryptedData:
+			return nil, pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+				"messages without modification detection code are not supported")
+		default:
+			return nil, errWrongKey
+		}
+	}
+	if sessionKeyPacket == nil || len(encrypted) == 0 || encrypted[0] != 1 {
+		return nil, errWrongKey
+	}
+
+	// Find the session key.
+	if len(sessionKeyPacket) < 2 || sessionKeyPacket[0] != 4 {
+		return nil, errWrongKey
+	}
+	c, err := pgpCipherByID(sessionKeyPacket[1])
+	if err != nil {
+		return nil, err
+	}
+	s, encryptedKey, err := parseS2K(sessionKeyPacket[2:])
+	if err != nil {
+		return nil, err
+	}
+	key, err := s.key(password, c.keyLen)
+	if err != nil {
+		return nil, err
+	}
+	if len(encryptedKey) > 0 {
+		block, err := c.newBlock(key)
+		if err != nil {
+			return nil, err
+		}
+		decryptedKey := make([]byte, len(encryptedKey))
+		cipher.NewCFBDecrypter(block, make([]byte, block.BlockSize())).
+			XORKeyStream(decryptedKey, encryptedKey)
+		if c, err = pgpCipherByID(decryptedKey[0]); err != nil || len(decryptedKey) != 1+c.keyLen {
+			return nil, errWrongKey
+		}
+		key = decryptedKey[1:]
+	}
+
+	// Decrypt the data, and check it.
+	block, err := c.newBlock(key)
+	if err != nil {
+		return nil, err
+	}
+	bs := block.BlockSize()
+	encrypted = encrypted[1:]
+	if len(encrypted) < bs+2+len(mdcHeader)+sha1.Size {
+		return nil, errWrongKey
+	}
+	plain := make([]byte, len(encrypted))
+	cipher.NewCFBDecrypter(block, make([]byte, bs)).XORKeyStream(plain, encrypted)
+	if plain[bs-2] != plain[bs] || plain[bs-1] != plain[bs+1] {
+		return nil, errWrongKey
+	}
+	mdcStart := len(plain) - sha1.Size
+	mdc := sha1.Sum(plain[:mdcStart])
+	if !bytes.Equal(plain[mdcStart-len(mdcHeader):mdcStart], mdcHeader) ||
+		subtle.ConstantTimeCompare(mdc[:], plain[mdcStart:]) != 1 {
+		return nil, errWrongKey
+	}
+
+	data, dataType, err := readLiteralData(ctx, acc, plain[bs+2:mdcStart-len(mdcHeader)])
+	if err != nil {
+		return nil, err
+	}
+	if text {
+		if dataType == 'b' {
+			return nil, errNotText
+		}
+		if opts.convertCRLF {
+			data = bytes.Replace(data, []byte("\r\n"), []byte("\n"), -1)
+		}
+	}
+	return data, nil
+}
+
+// readLiteralData returns the data of the literal data packet in b, which
+// may be compressed, and its type. As in pgcrypto, a compressed data packet
+// cannot contain another one. The decompressed data is accounted for in acc.
+func readLiteralData(ctx context.Context, acc *mon.BoundAccount, b []byte) ([]byte, byte, error) {
+	decompressed := false
+	for {
+		tag, body, _, err := readPacket(b)
+		if err != nil {
+			return nil, 0, err
+		}
+		switch tag {
+		case tagCompressedData:
+			if decompressed {
+				return nil, 0, errNestedCompression
+			}
+			decompressed = true
+			if len(body) == 0 {
+				return nil, 0, errWrongKey
+			}
+			var r io.Reader
+			switch body[0] {
+			case compressNone:
+				b = body[1:]
+				continue
+			case compressZIP:
+				r = flate.NewReader(bytes.NewReader(body[1:]))
+			case compressZLIB:
+				if r, err = zlib.NewReader(bytes.NewReader(body[1:])); err != nil {
+					return nil, 0, errWrongKey
+				}
+			case compressBZIP2:
+				r = bzip2.NewReader(bytes.NewReader(body[1:]))
+			default:
+				return nil, 0, pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+					"unsupported compression algorithm")
+			}
+			if b, err = decompress(ctx, acc, r); err != nil {
+				return nil, 0, err
+			}
+		case tagLiteralData:
+			// The packet starts with the type of the data, the length and the
+			// name of the file, and its modification time.
+			if len(body) < 2 || len(body) < 6+int(body[1]) {
+				return nil, 0, errWrongKey
+			}
+			return body[6+int(body[1]):], body[0], nil
+		default:
+			return nil, 0, errWrongKey
+		}
+	}
+}
+
+// decompress reads the data of r, up to maxDecompressedSize bytes, and
+// accounts for it in acc, if any, as it grows.
+func decompress(ctx context.Context, acc *mon.BoundAccount, r io.Reader) ([]byte, error) {
+	var res []byte
+	for {
+		if len(res) == cap(res) {
+			if len(res) >= maxDecompressedSize {
+				// Only fail if there is more data.
+				var b [1]byte
+				if n, _ := io.ReadFull(r, b[:]); n > 0 {
+					return nil, errDecompressedTooLarge
+				}
+				return res, nil
+			}
+			// The buffer doubles, like the one of ioutil.ReadAll.
+			n := 2 * cap(res)
+			if n < decompressChunkSize {
+				n = decompressChunkSize
+			} else if n > maxDecompressedSize {
+				n = maxDecompressedSize
+			}
+			if acc != nil {
+				if err := acc.Grow(ctx, int64(n-cap(res))); err != nil {
+					return nil, err
+				}
+			}
+			res = append(make([]byte, 0, n), res...)
+		}
+		n, err := r.Read(res[len(res):cap(res)])
+		res = res[:len(res)+n]
+		if err == io.EOF {
+			return res, nil
+		}
+		if err != nil {
+			return nil, errWrongKey
+		}
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package pgcrypto
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"math"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+)
+
+// startTestMonitor starts a memory monitor limited to limit bytes, or
+// unlimited when limit is zero.
+func startTestMonitor(ctx context.Context, limit int64) *mon.BytesMonitor {
+	m := mon.MakeMonitorWithLimit("test", mon.MemoryResource, limit, nil, nil, 1,
+		math.MaxInt64, cluster.MakeTestingClusterSettings())
+	m.Start(ctx, nil, mon.MakeStandaloneBudget(math.MaxInt64))
+	return &m
+}
+
+func TestPGPSymEncrypt(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	m := startTestMonitor(ctx, 0)
+	defer m.Stop(ctx)
+	acc := m.MakeBoundAccount()
+	defer acc.Close(ctx)
+
+	data := []byte("Secret message.\nOn two lines.")
+	for _, options := range []string{
+		``,
+		`cipher-algo=bf`,
+		`cipher-algo=aes192, s2k-cipher-algo=3des, sess-key=1`,
+		`cipher-algo=aes256, sess-key=1`,
+		`cipher-algo=3des, compress-algo=1`,
+		`compress-algo=2, compress-level=9`,
+		`s2k-mode=0`,
+		`s2k-mode=1, s2k-digest-algo=md5`,
+		`s2k-mode=3, s2k-count=1024`,
+		`s2k-count=65011712`,
+		`convert-crlf=1`,
+		`unicode-mode=1, disable-mdc=0`,
+	} {
+		t.Run(options, func(t *testing.T) {
+			for _, text := range []bool{false, true} {
+				msg, err := PGPSymEncrypt(data, []byte("key"), text, options)
+				if err != nil {
+					t.Fatal(err)
+				}
+				res, err := PGPSymDecrypt(ctx, &acc, msg, []byte("key"), text, options)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(res, data) {
+					t.Fatalf("expected %q, got %q", data, res)
+				}
+				if _, err := PGPSymDecrypt(ctx, &acc, msg, []byte("other key"), text, ""); err != errWrongKey {
+					t.Fatalf("expected %v, got %v", errWrongKey, err)
+				}
+				if !text {
+					if _, err := PGPSymDecrypt(ctx, &acc, msg, []byte("key"), true, ""); err != errNotText {
+						t.Fatalf("expected %v, got %v", errNotText, err)
+					}
+				}
+			}
+		})
+	}
+
+	errorCases := []struct {
+		options string
+		err     string
+	}{
+		{`cipher-algo`, `invalid option "cipher-algo"`},
+		{`foo=1`, `invalid option "foo"`},
+		{`cipher-algo=aes512`, `invalid value "aes512" for option "cipher-algo"`},
+		{`cipher-algo=cast5`, `unsupported cipher algorithm`},
+		{`compress-algo=3`, `invalid value "3" for option "compress-algo"`},
+		{`s2k-mode=2`, `invalid value "2" for option "s2k-mode"`},
+		{`s2k-count=1000`, `invalid value "1000" for option "s2k-count"`},
+		{`s2k-digest-algo=sha256`, `invalid value "sha256" for option "s2k-digest-algo"`},
+		{`disable-mdc=1`, `disabling the modification detection code is not supported`},
+	}
+	for _, tc := range errorCases {
+		t.Run(tc.options, func(t *testing.T) {
+			if _, err := PGPSymEncrypt(data, []byte("key"), false, tc.options); !testutils.IsError(err, tc.err) {
+				t.Fatalf("expected error %q, got %v", tc.err, err)
+			}
+		})
+	}
+
+	if _, err := PGPSymDecrypt(ctx, &acc, []byte("not a message"), []byte("key"), false, ""); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+// TestPGPSymDecryptGnuPG decrypts messages encrypted by GnuPG with the
+// passphrase "key".
+func TestPGPSymDecryptGnuPG(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	m := startTestMonitor(ctx, 0)
+	defer m.Stop(ctx)
+	acc := m.MakeBoundAccount()
+	defer acc.Close(ctx)
+
+	testCases := []struct {
+		name string
+		msg  string
+	}{
+		{
+			// gpg --rfc4880 --symmetric: 3DES with a SHA-1 s2k, compressed.
+			name: "3des",
+			msg: `-----BEGIN PGP MESSAGE-----
+
+jA0EAgMCpsEbAGUlAef/0ksB8XSUn5rtynC74NJiSg1iJ6SQxAVnpb3ApMpkgIcF
+EQD13lCZktnRjtZoMG6hIgHV80CotYNGRSGbvAPcW+RzN/FeboQ2pCE1b9E=
+=6qCa
+-----END PGP MESSAGE-----
+`,
+		},
+		{
+			// AES256 with a SHA-512 s2k, compressed with zlib.
+			name: "aes256",
+			msg: `-----BEGIN PGP MESSAGE-----
+
+jA0ECQMKBsslVZrS9o//0lkBuhW3tcrMkW2ITRwQAEp1Z+SSN5EZ+T5Gq2VqSDdU
+r0zLcOEhZFJnWnHx8l0twYoaaIMy/9No9lTMvPx8/KN+9bDmwWgvzTCnuWpOnIBd
+dpp5b4Qjxwe+aw==
+=n3PE
+-----END PGP MESSAGE-----
+`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			msg, err := Dearmor(tc.msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res, err := PGPSymDecrypt(ctx, &acc, msg, []byte("key"), false, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expected := "Secret message from gpg.\n"; string(res) != expected {
+				t.Fatalf("expected %q, got %q", expected, res)
+			}
+		})
+	}
+}
+
+// TestReadLiteralDataLimits checks the limits on the decompression of the
+// data of a message.
+func TestReadLiteralDataLimits(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	literal := func(data []byte) []byte {
+		var buf bytes.Buffer
+		writePacket(&buf, tagLiteralData, append([]byte{'b', 0, 0, 0, 0, 0}, data...))
+		return buf.Bytes()
+	}
+	compressed := func(b []byte) []byte {
+		var body bytes.Buffer
+		body.WriteByte(compressZIP)
+		w, err := flate.NewWriter(&body, flate.BestCompression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(b); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		writePacket(&buf, tagCompressedData, body.Bytes())
+		return buf.Bytes()
+	}
+
+	testCases := []struct {
+		name  string
+		b     []byte
+		limit int64
+		size  int
+		err   string
+	}{
+		{"compressed", compressed(literal([]byte("abc"))), 0, 3, ``},
+		{"nested", compressed(compressed(literal([]byte("abc")))), 0, 0,
+			`nested compressed data is not supported`},
+		// The literal data packet has a header of 6 bytes, and a body of 6
+		// bytes before its data.
+		{"max size", compressed(literal(make([]byte, maxDecompressedSize-12))), 0,
+			maxDecompressedSize - 12, ``},
+		{"too large", compressed(literal(make([]byte, maxDecompressedSize))), 0, 0,
+			`decompressed data exceeds`},
+		{"memory", compressed(literal(make([]byte, 1<<20))), 1 << 19, 0,
+			`memory budget exceeded`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := startTestMonitor(ctx, tc.limit)
+			defer m.Stop(ctx)
+			acc := m.MakeBoundAccount()
+			defer acc.Close(ctx)
+
+			data, _, err := readLiteralData(ctx, &acc, tc.b)
+			if tc.err != "" {
+				if !testutils.IsError(err, tc.err) {
+					t.Fatalf("expected error %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(data) != tc.size {
+				t.Fatalf("expected %d bytes, got %d", tc.size, len(data))
+			}
+		})
+	}
+
+	// Without an account, the memory is not accounted for.
+	if data, _, err := readLiteralData(ctx, nil, compressed(literal([]byte("abc")))); err != nil ||
+		string(data) != "abc" {
+		t.Fatalf("expected %q, got %q (%v)", "abc", data, err)
+	}
+}
+
+func TestArmor(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, data := range [][]byte{nil, []byte("a"), bytes.Repeat([]byte{0, 1, 2, 0xff}, 100)} {
+		s := Armor(data)
+		res, err := Dearmor(s)
+		if err != nil {
+			t.Fatalf("%s: %v", s, err)
+		}
+		if !bytes.Equal(res, data) {
+			t.Fatalf("expected %x, got %x", data, res)
+		}
+	}
+
+	for _, s := range []string{
+		``,
+		"-----BEGIN PGP MESSAGE-----\n\nYWJj\n",
+		"-----BEGIN PGP MESSAGE-----\n\nYWJj\n=AAAA\n-----END PGP MESSAGE-----\n",
+		"-----BEGIN PGP MESSAGE-----\n\nYW!j\n-----END PGP MESSAGE-----\n",
+	} {
+		if _, err := Dearmor(s); err != errCorruptArmor {
+			t.Errorf("%q: expected %v, got %v", s, errCorruptArmor, err)
+		}
+	}
+}
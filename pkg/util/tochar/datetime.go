@@ -0,0 +1,543 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tochar
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
+)
+
+// The date/time template patterns.
+const (
+	dchEra keywordID = iota
+	dchMeridiem
+	dchCC
+	dchDay
+	dchDDD
+	dchDD
+	dchDy
+	dchD
+	dchFX
+	dchHH24
+	dchHH12
+	dchIDDD
+	dchID
+	dchIW
+	dchIYYY
+	dchIYY
+	dchIY
+	dchI
+	dchJ
+	dchMI
+	dchMM
+	dchMonth
+	dchMon
+	dchMS
+	dchOF
+	dchQ
+	dchRM
+	dchSSSS
+	dchSS
+	dchTZH
+	dchTZM
+	dchTZ
+	dchUS
+	dchWW
+	dchW
+	dchYCommaYYY
+	dchYYYY
+	dchYYY
+	dchYY
+	dchY
+)
+
+// datetimeKeywords lists the date/time template patterns. The patterns
+// sharing a prefix are ordered longest first.
+var datetimeKeywords = []keyword{
+	{name: "A.D.", id: dchEra, dots: true},
+	{name: "A.M.", id: dchMeridiem, dots: true},
+	{name: "AD", id: dchEra},
+	{name: "AM", id: dchMeridiem},
+	{name: "B.C.", id: dchEra, dots: true},
+	{name: "BC", id: dchEra},
+	{name: "CC", id: dchCC, digit: true},
+	{name: "DAY", id: dchDay},
+	{name: "DDD", id: dchDDD, digit: true},
+	{name: "DD", id: dchDD, digit: true},
+	{name: "DY", id: dchDy},
+	{name: "Day", id: dchDay, casing: capitalized},
+	{name: "Dy", id: dchDy, casing: capitalized},
+	{name: "D", id: dchD, digit: true},
+	{name: "FX", id: dchFX},
+	{name: "HH24", id: dchHH24, digit: true},
+	{name: "HH12", id: dchHH12, digit: true},
+	{name: "HH", id: dchHH12, digit: true},
+	{name: "IDDD", id: dchIDDD, digit: true},
+	{name: "ID", id: dchID, digit: true},
+	{name: "IW", id: dchIW, digit: true},
+	{name: "IYYY", id: dchIYYY, digit: true},
+	{name: "IYY", id: dchIYY, digit: true},
+	{name: "IY", id: dchIY, digit: true},
+	{name: "I", id: dchI, digit: true},
+	{name: "J", id: dchJ, digit: true},
+	{name: "MI", id: dchMI, digit: true},
+	{name: "MM", id: dchMM, digit: true},
+	{name: "MONTH", id: dchMonth},
+	{name: "MON", id: dchMon},
+	{name: "MS", id: dchMS, digit: true},
+	{name: "Month", id: dchMonth, casing: capitalized},
+	{name: "Mon", id: dchMon, casing: capitalized},
+	{name: "OF", id: dchOF},
+	{name: "P.M.", id: dchMeridiem, dots: true},
+	{name: "PM", id: dchMeridiem},
+	{name: "Q", id: dchQ, digit: true},
+	{name: "RM", id: dchRM},
+	{name: "SSSSS", id: dchSSSS, digit: true},
+	{name: "SSSS", id: dchSSSS, digit: true},
+	{name: "SS", id: dchSS, digit: true},
+	{name: "TZH", id: dchTZH},
+	{name: "TZM", id: dchTZM},
+	{name: "TZ", id: dchTZ},
+	{name: "US", id: dchUS, digit: true},
+	{name: "WW", id: dchWW, digit: true},
+	{name: "W", id: dchW, digit: true},
+	{name: "Y,YYY", id: dchYCommaYYY, digit: true},
+	{name: "YYYY", id: dchYYYY, digit: true},
+	{name: "YYY", id: dchYYY, digit: true},
+	{name: "YY", id: dchYY, digit: true},
+	{name: "Y", id: dchY, digit: true},
+	{name: "a.d.", id: dchEra, casing: lowerCase, dots: true},
+	{name: "a.m.", id: dchMeridiem, casing: lowerCase, dots: true},
+	{name: "ad", id: dchEra, casing: lowerCase},
+	{name: "am", id: dchMeridiem, casing: lowerCase},
+	{name: "b.c.", id: dchEra, casing: lowerCase, dots: true},
+	{name: "bc", id: dchEra, casing: lowerCase},
+	{name: "cc", id: dchCC, digit: true},
+	{name: "day", id: dchDay, casing: lowerCase},
+	{name: "ddd", id: dchDDD, digit: true},
+	{name: "dd", id: dchDD, digit: true},
+	{name: "dy", id: dchDy, casing: lowerCase},
+	{name: "d", id: dchD, digit: true},
+	{name: "fx", id: dchFX},
+	{name: "hh24", id: dchHH24, digit: true},
+	{name: "hh12", id: dchHH12, digit: true},
+	{name: "hh", id: dchHH12, digit: true},
+	{name: "iddd", id: dchIDDD, digit: true},
+	{name: "id", id: dchID, digit: true},
+	{name: "iw", id: dchIW, digit: true},
+	{name: "iyyy", id: dchIYYY, digit: true},
+	{name: "iyy", id: dchIYY, digit: true},
+	{name: "iy", id: dchIY, digit: true},
+	{name: "i", id: dchI, digit: true},
+	{name: "j", id: dchJ, digit: true},
+	{name: "mi", id: dchMI, digit: true},
+	{name: "mm", id: dchMM, digit: true},
+	{name: "month", id: dchMonth, casing: lowerCase},
+	{name: "mon", id: dchMon, casing: lowerCase},
+	{name: "ms", id: dchMS, digit: true},
+	{name: "of", id: dchOF},
+	{name: "p.m.", id: dchMeridiem, casing: lowerCase, dots: true},
+	{name: "pm", id: dchMeridiem, casing: lowerCase},
+	{name: "q", id: dchQ, digit: true},
+	{name: "rm", id: dchRM, casing: lowerCase},
+	{name: "sssss", id: dchSSSS, digit: true},
+	{name: "ssss", id: dchSSSS, digit: true},
+	{name: "ss", id: dchSS, digit: true},
+	{name: "tzh", id: dchTZH},
+	{name: "tzm", id: dchTZM},
+	{name: "tz", id: dchTZ, casing: lowerCase},
+	{name: "us", id: dchUS, digit: true},
+	{name: "ww", id: dchWW, digit: true},
+	{name: "w", id: dchW, digit: true},
+	{name: "y,yyy", id: dchYCommaYYY, digit: true},
+	{name: "yyyy", id: dchYYYY, digit: true},
+	{name: "yyy", id: dchYYY, digit: true},
+	{name: "yy", id: dchYY, digit: true},
+	{name: "y", id: dchY, digit: true},
+}
+
+var datetimeIndex = makeKeywordIndex(datetimeKeywords)
+
+var fullMonths = []string{
+	"January", "February", "March", "April", "May", "June", "July",
+	"August", "September", "October", "November", "December",
+}
+
+var fullDays = []string{
+	"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday",
+}
+
+// romanMonths are the months in Roman numerals, from December to January.
+var romanMonths = []string{
+	"XII", "XI", "X", "IX", "VIII", "VII", "VI", "V", "IV", "III", "II", "I",
+}
+
+// fields are the broken-down fields of a timestamp or an interval.
+type fields struct {
+	// year is the astronomical year of a timestamp, where 0 is 1 BC.
+	year int
+	mon  int
+	mday int
+	hour int
+	min  int
+	sec  int
+	// usec is the microsecond of the second.
+	usec int
+	// yday is the day of the year, starting at 1.
+	yday int
+	// wday is the day of the week, starting at 0 for Sunday.
+	wday int
+	// gmtoff is the offset of the time zone in seconds east of UTC.
+	gmtoff int
+	// zone is the abbreviation of the time zone, if any.
+	zone string
+	// interval is set for the fields of intervals.
+	interval bool
+}
+
+// TimeToChar formats t according to the template pattern format, in the
+// location of t. The time zone is only written by the TZ pattern if withZone
+// is set, which is the case for the values of type TIMESTAMPTZ.
+func TimeToChar(t time.Time, withZone bool, format string) (string, error) {
+	f := fields{
+		year: t.Year(),
+		mon:  int(t.Month()),
+		mday: t.Day(),
+		hour: t.Hour(),
+		min:  t.Minute(),
+		sec:  t.Second(),
+		usec: t.Nanosecond() / 1000,
+		yday: t.YearDay(),
+		wday: int(t.Weekday()),
+	}
+	if withZone {
+		f.zone, f.gmtoff = t.Zone()
+		if f.zone == "" {
+			f.zone = formatOffset(f.gmtoff)
+		}
+	}
+	return f.toChar(parseFormat(format, datetimeIndex, true))
+}
+
+// IntervalToChar formats d according to the template pattern format. The
+// months of d are split into years and months, and its nanoseconds into
+// hours, minutes and seconds; the hours are not limited to a day.
+func IntervalToChar(d duration.Duration, format string) (string, error) {
+	f := fields{
+		year:     int(d.Months / 12),
+		mon:      int(d.Months % 12),
+		mday:     int(d.Days),
+		interval: true,
+	}
+	usecs := d.Nanos / 1000
+	f.hour = int(usecs / int64(time.Hour/time.Microsecond))
+	usecs -= int64(f.hour) * int64(time.Hour/time.Microsecond)
+	f.min = int(usecs / int64(time.Minute/time.Microsecond))
+	usecs -= int64(f.min) * int64(time.Minute/time.Microsecond)
+	f.sec = int(usecs / int64(time.Second/time.Microsecond))
+	f.usec = int(usecs - int64(f.sec)*int64(time.Second/time.Microsecond))
+	f.yday = (f.year*12+f.mon)*30 + f.mday
+	return f.toChar(parseFormat(format, datetimeIndex, true))
+}
+
+// formatOffset formats a time zone offset the way PostgreSQL names the time
+// zones without abbreviation, like +03 or -0930.
+func formatOffset(gmtoff int) string {
+	sign := '+'
+	if gmtoff < 0 {
+		sign = '-'
+		gmtoff = -gmtoff
+	}
+	if m := gmtoff % 3600 / 60; m != 0 {
+		return fmt.Sprintf("%c%02d%02d", sign, gmtoff/3600, m)
+	}
+	return fmt.Sprintf("%c%02d", sign, gmtoff/3600)
+}
+
+var errInvalidForInterval = pgerror.NewError(pgerror.CodeInvalidDatetimeFormatError,
+	"invalid format specification for an interval value").SetHintf(
+	"Intervals are not tied to specific calendar dates.")
+
+// invalidForInterval lists the patterns which only apply to timestamps.
+var invalidForInterval = map[keywordID]bool{
+	dchEra:   true,
+	dchDay:   true,
+	dchDy:    true,
+	dchD:     true,
+	dchID:    true,
+	dchMonth: true,
+	dchMon:   true,
+	dchOF:    true,
+	dchTZH:   true,
+	dchTZM:   true,
+	dchTZ:    true,
+}
+
+// width returns the zero-padding width of a numeric field, which is 0 in
+// fill mode and one more for negative values.
+func width(n node, w int, v int) int {
+	if n.fm {
+		return 0
+	}
+	if v < 0 {
+		return w + 1
+	}
+	return w
+}
+
+// adjustYear converts the astronomical year of a timestamp, where 0 is 1 BC,
+// to the year written with an era.
+func (f *fields) adjustYear(year int) int {
+	if !f.interval && year <= 0 {
+		return 1 - year
+	}
+	return year
+}
+
+// applyCasing writes s with the capitalization of the pattern of n.
+func applyCasing(s string, c casing) string {
+	switch c {
+	case upperCase:
+		return strings.ToUpper(s)
+	case lowerCase:
+		return strings.ToLower(s)
+	}
+	return s
+}
+
+// padName pads the name of a month or day to 9 characters, unless in fill
+// mode.
+func padName(n node, s string) string {
+	if n.fm {
+		return s
+	}
+	return fmt.Sprintf("%-9s", s)
+}
+
+func (f *fields) toChar(nodes []node) (string, error) {
+	var buf bytes.Buffer
+	for _, n := range nodes {
+		if n.key == nil {
+			buf.WriteString(n.char)
+			continue
+		}
+		if f.interval && invalidForInterval[n.key.id] {
+			return "", errInvalidForInterval
+		}
+		var s string
+		switch n.key.id {
+		case dchEra:
+			s = "AD"
+			if f.year <= 0 {
+				s = "BC"
+			}
+			if n.key.dots {
+				s = s[:1] + "." + s[1:] + "."
+			}
+			s = applyCasing(s, n.key.casing)
+		case dchMeridiem:
+			s = "AM"
+			if f.hour%24 >= 12 {
+				s = "PM"
+			}
+			if n.key.dots {
+				s = s[:1] + "." + s[1:] + "."
+			}
+			s = applyCasing(s, n.key.casing)
+		case dchHH12:
+			// The hour is displayed on a 12-hour clock, even for intervals.
+			h := f.hour % 12
+			if h == 0 {
+				h = 12
+			}
+			s = fmt.Sprintf("%0*d", width(n, 2, f.hour), h)
+		case dchHH24:
+			s = fmt.Sprintf("%0*d", width(n, 2, f.hour), f.hour)
+		case dchMI:
+			s = fmt.Sprintf("%0*d", width(n, 2, f.min), f.min)
+		case dchSS:
+			s = fmt.Sprintf("%0*d", width(n, 2, f.sec), f.sec)
+		case dchMS:
+			s = fmt.Sprintf("%03d", f.usec/1000)
+		case dchUS:
+			s = fmt.Sprintf("%06d", f.usec)
+		case dchSSSS:
+			s = fmt.Sprintf("%d", f.hour*3600+f.min*60+f.sec)
+		case dchTZ:
+			s = applyCasing(f.zone, n.key.casing)
+		case dchTZH:
+			sign := '+'
+			if f.gmtoff < 0 {
+				sign = '-'
+			}
+			s = fmt.Sprintf("%c%02d", sign, abs(f.gmtoff)/3600)
+		case dchTZM:
+			s = fmt.Sprintf("%02d", abs(f.gmtoff)%3600/60)
+		case dchOF:
+			sign := '+'
+			if f.gmtoff < 0 {
+				sign = '-'
+			}
+			s = fmt.Sprintf("%c%0*d", sign, width(n, 2, 0), abs(f.gmtoff)/3600)
+			if m := abs(f.gmtoff) % 3600 / 60; m != 0 {
+				s += fmt.Sprintf(":%02d", m)
+			}
+		case dchMonth:
+			if f.mon == 0 {
+				break
+			}
+			s = padName(n, applyCasing(fullMonths[f.mon-1], n.key.casing))
+		case dchMon:
+			if f.mon == 0 {
+				break
+			}
+			s = applyCasing(fullMonths[f.mon-1][:3], n.key.casing)
+		case dchMM:
+			s = fmt.Sprintf("%0*d", width(n, 2, f.mon), f.mon)
+		case dchDay:
+			s = padName(n, applyCasing(fullDays[f.wday], n.key.casing))
+		case dchDy:
+			s = applyCasing(fullDays[f.wday][:3], n.key.casing)
+		case dchDDD:
+			s = fmt.Sprintf("%0*d", width(n, 3, 0), f.yday)
+		case dchIDDD:
+			s = fmt.Sprintf("%0*d", width(n, 3, 0), isoYearDay(f.year, f.mon, f.mday))
+		case dchDD:
+			s = fmt.Sprintf("%0*d", width(n, 2, 0), f.mday)
+		case dchD:
+			s = fmt.Sprintf("%d", f.wday+1)
+		case dchID:
+			d := f.wday
+			if d == 0 {
+				d = 7
+			}
+			s = fmt.Sprintf("%d", d)
+		case dchWW:
+			s = fmt.Sprintf("%0*d", width(n, 2, 0), (f.yday-1)/7+1)
+		case dchIW:
+			_, w := date(f.year, f.mon, f.mday).ISOWeek()
+			s = fmt.Sprintf("%0*d", width(n, 2, 0), w)
+		case dchQ:
+			if f.mon == 0 {
+				break
+			}
+			s = fmt.Sprintf("%d", (f.mon-1)/3+1)
+		case dchCC:
+			var c int
+			switch {
+			case f.interval:
+				c = f.year / 100
+			case f.year > 0:
+				c = (f.year-1)/100 + 1
+			default:
+				c = f.year/100 - 1
+			}
+			if c <= 99 && c >= -99 {
+				s = fmt.Sprintf("%0*d", width(n, 2, c), c)
+			} else {
+				s = fmt.Sprintf("%d", c)
+			}
+		case dchYCommaYYY:
+			y := f.adjustYear(f.year)
+			s = fmt.Sprintf("%d,%03d", y/1000, y-y/1000*1000)
+		case dchYYYY, dchIYYY:
+			y := f.adjustYear(f.year)
+			if n.key.id == dchIYYY {
+				y = f.adjustYear(f.isoYear())
+			}
+			s = fmt.Sprintf("%0*d", width(n, 4, y), y)
+		case dchYYY, dchIYY:
+			y := f.adjustYear(f.year)
+			if n.key.id == dchIYY {
+				y = f.adjustYear(f.isoYear())
+			}
+			s = fmt.Sprintf("%0*d", width(n, 3, y), y%1000)
+		case dchYY, dchIY:
+			y := f.adjustYear(f.year)
+			if n.key.id == dchIY {
+				y = f.adjustYear(f.isoYear())
+			}
+			s = fmt.Sprintf("%0*d", width(n, 2, y), y%100)
+		case dchY, dchI:
+			y := f.adjustYear(f.year)
+			if n.key.id == dchI {
+				y = f.adjustYear(f.isoYear())
+			}
+			s = fmt.Sprintf("%d", y%10)
+		case dchRM:
+			if f.mon == 0 {
+				break
+			}
+			s = applyCasing(romanMonths[12-f.mon], n.key.casing)
+			if !n.fm {
+				s = fmt.Sprintf("%-4s", s)
+			}
+		case dchW:
+			s = fmt.Sprintf("%d", (f.mday-1)/7+1)
+		case dchJ:
+			s = fmt.Sprintf("%d", julianDay(f.year, f.mon, f.mday))
+		}
+		if n.th != thNone {
+			s += ordinalSuffix(s, n.th)
+		}
+		buf.WriteString(s)
+	}
+	return buf.String(), nil
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// date returns the midnight UTC of a date. Out of range months and days are
+// normalized, so that intervals can be formatted.
+func date(year, mon, mday int) time.Time {
+	return time.Date(year, time.Month(mon), mday, 0, 0, 0, 0, time.UTC)
+}
+
+// isoYear returns the ISO 8601 week-numbering year of the fields.
+func (f *fields) isoYear() int {
+	y, _ := date(f.year, f.mon, f.mday).ISOWeek()
+	return y
+}
+
+// isoYearDay returns the day of the ISO 8601 week-numbering year of a date.
+func isoYearDay(year, mon, mday int) int {
+	t := date(year, mon, mday)
+	_, w := t.ISOWeek()
+	return (w-1)*7 + (int(t.Weekday())+6)%7 + 1
+}
+
+// unixEpochJulianDay is the Julian day of 1970-01-01.
+const unixEpochJulianDay = 2440588
+
+// julianDay returns the number of days of a date since November 24, 4714 BC
+// in the proleptic Gregorian calendar.
+func julianDay(year, mon, mday int) int {
+	secs := date(year, mon, mday).Unix()
+	days := secs / 86400
+	if secs%86400 < 0 {
+		days--
+	}
+	return int(days) + unixEpochJulianDay
+}
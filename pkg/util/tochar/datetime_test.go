@@ -0,0 +1,242 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tochar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestTimeToChar(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ts := time.Date(2002, 4, 20, 17, 31, 12, 660000000, time.UTC)
+	est := time.FixedZone("EST", -5*3600)
+	testCases := []struct {
+		t        time.Time
+		withZone bool
+		format   string
+		expected string
+	}{
+		{ts, false, `YYYY-MM-DD HH24:MI:SS`, `2002-04-20 17:31:12`},
+		{ts, false, `HH12:MI:SS`, `05:31:12`},
+		{ts, false, `HH:MI AM`, `05:31 PM`},
+		{ts, false, `hh12 a.m.`, `05 p.m.`},
+		{ts, false, `MS US`, `660 660000`},
+		{ts, false, `SSSS`, `63072`},
+		{ts, false, `Day, DD  HH12:MI:SS`, `Saturday , 20  05:31:12`},
+		{ts, false, `FMDay, FMDD  HH12:MI:SS`, `Saturday, 20  05:31:12`},
+		{ts, false, `DAY Dy dy`, `SATURDAY  Sat sat`},
+		{ts, false, `Month Mon month MONTH|`, `April     Apr april     APRIL    |`},
+		{ts, false, `FMMonth`, `April`},
+		{ts, false, `RM|FMRM|rm`, `IV  |IV|iv  `},
+		{ts, false, `DDD Q D ID`, `110 2 7 6`},
+		{ts, false, `WW W IW`, `16 3 16`},
+		{ts, false, `IYYY-IW-ID IDDD`, `2002-16-6 111`},
+		{ts, false, `DDth DDTH Dth`, `20th 20TH 7th`},
+		{ts, false, `FMMMth`, `4th`},
+		{ts, false, `CC Y,YYY YYY YY Y`, `21 2,002 002 02 2`},
+		{ts, false, `J`, `2452385`},
+		{ts, false, `YYYY BC b.c.`, `2002 AD a.d.`},
+		{ts, false, `"Year" YYYY "\"Q\"" Q`, `Year 2002 "Q" 2`},
+		{ts, false, `FMxYYYY`, `x2002`},
+		{ts, false, `TZ|TZH:TZM|OF`, `|+00:00|+00`},
+		{ts.In(est), true, `HH24 TZ tz TZH:TZM OF`, `12 EST est -05:00 -05`},
+		{time.Date(2019, 1, 1, 0, 0, 0, 0, time.FixedZone("", 19800)), true, `TZ OF FMOF`,
+			`+0530 +05:30 +5:30`},
+		{time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), false, `HH12 AM`, `12 AM`},
+		{time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), false, `FMDDDth`, `1st`},
+		{time.Date(2019, 1, 13, 0, 0, 0, 0, time.UTC), false, `DDth`, `13th`},
+		{time.Date(-43, 3, 15, 0, 0, 0, 0, time.UTC), false, `YYYY-MM-DD BC CC`, `0044-03-15 BC -01`},
+		{time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), false, `CC`, `20`},
+		{time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC), false, `CC`, `21`},
+		{time.Date(2005, 1, 1, 0, 0, 0, 0, time.UTC), false, `IYYY IW ID`, `2004 53 6`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			s, err := TimeToChar(tc.t, tc.withZone, tc.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, s)
+			}
+		})
+	}
+}
+
+func TestIntervalToChar(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		d        duration.Duration
+		format   string
+		expected string
+		err      string
+	}{
+		{duration.Duration{Nanos: int64(15*time.Hour + 2*time.Minute + 12*time.Second)},
+			`HH24:MI:SS`, `15:02:12`, ``},
+		{duration.Duration{Nanos: int64(40*time.Hour + 500*time.Millisecond)},
+			`HH24 HH12 SSSS MS`, `40 04 144000 500`, ``},
+		{duration.Duration{Months: 14, Days: 3}, `YYYY MM DD DDD`, `0001 02 03 423`, ``},
+		{duration.Duration{Days: 3}, `RM|Q`, `|`, ``},
+		{duration.Duration{Months: -14}, `YYYY MM`, `-0001 -02`, ``},
+		{duration.Duration{}, `Day`, ``, `invalid format specification for an interval value`},
+		{duration.Duration{}, `Mon`, ``, `invalid format specification for an interval value`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			s, err := IntervalToChar(tc.d, tc.format)
+			if tc.err != "" {
+				if !testutils.IsError(err, tc.err) {
+					t.Fatalf("expected error %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, s)
+			}
+		})
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	est := time.FixedZone("EST", -5*3600)
+	testCases := []struct {
+		input    string
+		format   string
+		expected time.Time
+		err      string
+	}{
+		{`05 Dec 2000`, `DD Mon YYYY`, time.Date(2000, 12, 5, 0, 0, 0, 0, est), ``},
+		{`2000JUN`, `YYYYMON`, time.Date(2000, 6, 1, 0, 0, 0, 0, est), ``},
+		{`20000-1116`, `YYYY-MMDD`, time.Date(20000, 11, 16, 0, 0, 0, 0, est), ``},
+		{`2011-12-18 11:38 PM`, `YYYY-MM-DD HH12:MI PM`, time.Date(2011, 12, 18, 23, 38, 0, 0, est), ``},
+		{`2011-12-18 12:38 a.m.`, `YYYY-MM-DD HH12:MI A.M.`, time.Date(2011, 12, 18, 0, 38, 0, 0, est), ``},
+		{`2011-12-18 23:38:15.12`, `YYYY-MM-DD HH24:MI:SS.MS`,
+			time.Date(2011, 12, 18, 23, 38, 15, 120000000, est), ``},
+		{`15.000123`, `SS.US`, time.Date(0, 1, 1, 0, 0, 15, 123000, est), ``},
+		{`  2000  -  1-1`, `YYYY-MM-DD`, time.Date(2000, 1, 1, 0, 0, 0, 0, est), ``},
+		{`2000/03/04`, `YYYY-MM-DD`, time.Date(2000, 3, 4, 0, 0, 0, 0, est), ``},
+		{`1997 BC 11 16`, `YYYY BC MM DD`, time.Date(-1996, 11, 16, 0, 0, 0, 0, est), ``},
+		{`97`, `YY`, time.Date(1997, 1, 1, 0, 0, 0, 0, est), ``},
+		{`19 05`, `CC YY`, time.Date(1805, 1, 1, 0, 0, 0, 0, est), ``},
+		{`21`, `CC`, time.Date(2001, 1, 1, 0, 0, 0, 0, est), ``},
+		{`2,019`, `Y,YYY`, time.Date(2019, 1, 1, 0, 0, 0, 0, est), ``},
+		{`2000 60`, `YYYY DDD`, time.Date(2000, 2, 29, 0, 0, 0, 0, est), ``},
+		{`2452385`, `J`, time.Date(2002, 4, 20, 0, 0, 0, 0, est), ``},
+		{`2005-52-7`, `IYYY-IW-ID`, time.Date(2006, 1, 1, 0, 0, 0, 0, est), ``},
+		{`2019 10`, `YYYY WW`, time.Date(2019, 3, 5, 0, 0, 0, 0, est), ``},
+		{`July XII`, `Month RM`, time.Time{}, `conflicting values for "RM" field in formatting string`},
+		{`March III 15th`, `Month RM DDth`, time.Date(0, 3, 15, 0, 0, 0, 0, est), ``},
+		{`Saturday 2002 110`, `Day YYYY DDD`, time.Date(2002, 4, 20, 0, 0, 0, 0, est), ``},
+		{`2000-01-01 -05:30`, `YYYY-MM-DD TZH:TZM`,
+			time.Date(2000, 1, 1, 0, 0, 0, 0, time.FixedZone("", -19800)), ``},
+		{`2000-01-01 +08`, `YYYY-MM-DD TZH`,
+			time.Date(2000, 1, 1, 0, 0, 0, 0, time.FixedZone("", 8*3600)), ``},
+		{`2000 x 12`, `YYYY"xx"MM`, time.Date(2000, 12, 1, 0, 0, 0, 0, est), ``},
+		{`2000-12-05`, `FXYYYY MM DD`, time.Date(2000, 12, 5, 0, 0, 0, 0, est), ``},
+		{``, ``, time.Date(0, 1, 1, 0, 0, 0, 0, est), ``},
+		{`2000 EST`, `YYYY TZ`, time.Time{}, `formatting field "TZ" is only supported in to_char`},
+		{`13:00`, `HH12:MI`, time.Time{}, `hour "13" is invalid for the 12-hour clock`},
+		{`2000-02-30`, `YYYY-MM-DD`, time.Time{}, `date/time field value out of range: "2000-02-30"`},
+		{`25:00`, `HH24:MI`, time.Time{}, `date/time field value out of range: "25:00"`},
+		{`20001`, `YYYYMMDD`, time.Time{}, `source string too short for "MM" formatting field`},
+		{`2000x1`, `YYYYMMDD`, time.Time{}, `invalid value "x1" for "MM"`},
+		{`Foo`, `Mon`, time.Time{}, `invalid value "Foo" for "Mon"`},
+		{`1 2`, `MM MM`, time.Time{}, `conflicting values for "MM" field in formatting string`},
+		{`2000 1`, `YYYY ID`, time.Time{}, `invalid combination of date conventions`},
+		{`60`, `DDD`, time.Time{}, `cannot calculate day of year without year information`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input+"/"+tc.format, func(t *testing.T) {
+			res, err := ParseTimestamp(tc.input, tc.format, est)
+			if tc.err != "" {
+				if !testutils.IsError(err, tc.err) {
+					t.Fatalf("expected error %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !res.Equal(tc.expected) {
+				t.Fatalf("expected %s, got %s", tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	res, err := ParseDate(`05 Dec 2000 23:59`, `DD Mon YYYY HH24:MI`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := time.Date(2000, 12, 5, 0, 0, 0, 0, time.UTC); !res.Equal(expected) {
+		t.Fatalf("expected %s, got %s", expected, res)
+	}
+}
+
+// TestRoundTrip checks that the timestamps formatted by TimeToChar are
+// parsed back by ParseTimestamp with the same template.
+func TestRoundTrip(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	formats := []string{
+		`YYYY-MM-DD HH24:MI:SS.US`,
+		`FMDay, FMMonth FMDD, YYYY HH12:MI:SS.MS AM`,
+		`YYYYMMDDHH24MISS`,
+		`Y,YYY DDD SSSS`,
+		`IYYY-IW-ID HH24:MI`,
+		`J HH24:MI:SS`,
+		`YYYY BC MM DD`,
+	}
+	times := []time.Time{
+		time.Date(2002, 4, 20, 17, 31, 12, 660000000, time.UTC),
+		time.Date(1999, 12, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2020, 2, 29, 12, 0, 59, 1000, time.UTC),
+	}
+	for _, format := range formats {
+		for _, ts := range times {
+			s, err := TimeToChar(ts, false, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res, err := ParseTimestamp(s, format, time.UTC)
+			if err != nil {
+				t.Fatalf("%s: %v", s, err)
+			}
+			// Only compare the fields present in the template.
+			s2, err := TimeToChar(res, false, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s != s2 {
+				t.Errorf("%s: %q round-tripped to %q", format, s, s2)
+			}
+		}
+	}
+}
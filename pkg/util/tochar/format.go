@@ -0,0 +1,221 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package tochar implements the template patterns of the data type
+// formatting functions to_char, to_timestamp, to_date and to_number, which
+// convert date/time and numeric values to formatted strings and back.
+//
+// The implementation follows the one of PostgreSQL, in
+// src/backend/utils/adt/formatting.c, with the C locale.
+//
+// See: https://www.postgresql.org/docs/current/static/functions-formatting.html
+package tochar
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// keywordID identifies the action of a template pattern.
+type keywordID int
+
+// casing is the capitalization of the text written by a template pattern,
+// like MONTH, Month or month.
+type casing int
+
+const (
+	upperCase casing = iota
+	capitalized
+	lowerCase
+)
+
+// keyword is a template pattern.
+type keyword struct {
+	name string
+	id   keywordID
+	// casing is the capitalization of the text written for the pattern.
+	casing casing
+	// dots is set for the patterns with periods, like A.M.
+	dots bool
+	// digit is set for the patterns of numeric fields, which accept the TH
+	// suffix.
+	digit bool
+}
+
+// keywordIndex maps the first byte of template patterns to the patterns,
+// ordered so that longer patterns are matched first.
+type keywordIndex map[byte][]*keyword
+
+func makeKeywordIndex(keywords []keyword) keywordIndex {
+	idx := make(keywordIndex)
+	for i := range keywords {
+		k := &keywords[i]
+		idx[k.name[0]] = append(idx[k.name[0]], k)
+	}
+	return idx
+}
+
+// lookup returns the pattern at the start of s, if any.
+func (idx keywordIndex) lookup(s string) *keyword {
+	if s == "" {
+		return nil
+	}
+	for _, k := range idx[s[0]] {
+		if strings.HasPrefix(s, k.name) {
+			return k
+		}
+	}
+	return nil
+}
+
+// thMode is the mode of the ordinal number suffix of a pattern.
+type thMode int
+
+const (
+	thNone thMode = iota
+	// thUpper is the TH suffix, as in 4TH.
+	thUpper
+	// thLower is the th suffix, as in 4th.
+	thLower
+)
+
+// node is an element of a parsed template: either a pattern with its
+// modifiers, or a character copied literally.
+type node struct {
+	// key is the pattern of the node, or nil for a literal character.
+	key *keyword
+	// fm is set when the pattern has the FM (fill mode) prefix, which
+	// suppresses padding.
+	fm bool
+	// th is the ordinal number suffix of the pattern.
+	th thMode
+	// char is the literal character of the node.
+	char string
+	// quoted is set for the literal characters of double-quoted text.
+	quoted bool
+}
+
+// parseFormat splits a template into its patterns and literal characters.
+// Date/time templates accept prefix and suffix modifiers on their patterns;
+// numeric templates have FM as a pattern on its own.
+func parseFormat(format string, idx keywordIndex, modifiers bool) []node {
+	var nodes []node
+	s := format
+	for s != "" {
+		fm := false
+		if modifiers {
+			for {
+				if p := prefixModifier(s); p != "" {
+					fm = fm || strings.EqualFold(p, "FM")
+					s = s[len(p):]
+					continue
+				}
+				break
+			}
+		}
+		if k := idx.lookup(s); k != nil {
+			n := node{key: k, fm: fm}
+			s = s[len(k.name):]
+			if modifiers && k.digit {
+				switch {
+				case strings.HasPrefix(s, "TH"):
+					n.th = thUpper
+					s = s[2:]
+				case strings.HasPrefix(s, "th"):
+					n.th = thLower
+					s = s[2:]
+				case strings.HasPrefix(s, "SP"):
+					// The spell mode is not implemented, as in PostgreSQL.
+					s = s[2:]
+				}
+			}
+			nodes = append(nodes, n)
+			continue
+		}
+		if s == "" {
+			break
+		}
+		if s[0] == '"' {
+			// Text in double quotes is copied literally, and backslashes
+			// quote the next character.
+			s = s[1:]
+			for s != "" {
+				if s[0] == '"' {
+					s = s[1:]
+					break
+				}
+				if s[0] == '\\' && len(s) > 1 {
+					s = s[1:]
+				}
+				_, size := utf8.DecodeRuneInString(s)
+				nodes = append(nodes, node{char: s[:size], quoted: true})
+				s = s[size:]
+			}
+			continue
+		}
+		// Outside of double quotes, a backslash is only special before a
+		// double quote.
+		if strings.HasPrefix(s, `\"`) {
+			s = s[1:]
+		}
+		_, size := utf8.DecodeRuneInString(s)
+		nodes = append(nodes, node{char: s[:size]})
+		s = s[size:]
+	}
+	return nodes
+}
+
+// prefixModifier returns the prefix modifier at the start of s: FM for the
+// fill mode, or TM for the translation mode, which has no effect with the C
+// locale.
+func prefixModifier(s string) string {
+	if len(s) < 2 {
+		return ""
+	}
+	switch s[:2] {
+	case "FM", "fm", "TM", "tm":
+		return s[:2]
+	}
+	return ""
+}
+
+// ordinalSuffix returns the suffix of the ordinal number written as num,
+// like "nd" for "42".
+func ordinalSuffix(num string, mode thMode) string {
+	if num == "" {
+		return ""
+	}
+	last := num[len(num)-1]
+	if last < '0' || last > '9' {
+		return ""
+	}
+	var suffix string
+	switch {
+	case len(num) > 1 && num[len(num)-2] == '1':
+		// All the teens get "th".
+		suffix = "th"
+	case last == '1':
+		suffix = "st"
+	case last == '2':
+		suffix = "nd"
+	case last == '3':
+		suffix = "rd"
+	default:
+		suffix = "th"
+	}
+	if mode == thUpper {
+		return strings.ToUpper(suffix)
+	}
+	return suffix
+}
@@ -0,0 +1,964 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tochar
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/apd"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// The numeric template patterns.
+const (
+	numComma keywordID = iota
+	numDec
+	num0
+	num9
+	numB
+	numC
+	numD
+	numE
+	numFM
+	numG
+	numL
+	numMI
+	numPL
+	numPR
+	numRN
+	numSG
+	numSP
+	numS
+	numTH
+	numV
+)
+
+// numberKeywords lists the numeric template patterns. The patterns sharing a
+// prefix are ordered longest first.
+var numberKeywords = []keyword{
+	{name: ",", id: numComma},
+	{name: ".", id: numDec},
+	{name: "0", id: num0},
+	{name: "9", id: num9},
+	{name: "B", id: numB},
+	{name: "C", id: numC},
+	{name: "D", id: numD},
+	{name: "EEEE", id: numE},
+	{name: "FM", id: numFM},
+	{name: "G", id: numG},
+	{name: "L", id: numL},
+	{name: "MI", id: numMI},
+	{name: "PL", id: numPL},
+	{name: "PR", id: numPR},
+	{name: "RN", id: numRN},
+	{name: "SG", id: numSG},
+	{name: "SP", id: numSP},
+	{name: "S", id: numS},
+	{name: "TH", id: numTH},
+	{name: "V", id: numV},
+	{name: "b", id: numB},
+	{name: "c", id: numC},
+	{name: "d", id: numD},
+	{name: "eeee", id: numE},
+	{name: "fm", id: numFM},
+	{name: "g", id: numG},
+	{name: "l", id: numL},
+	{name: "mi", id: numMI},
+	{name: "pl", id: numPL},
+	{name: "pr", id: numPR},
+	{name: "rn", id: numRN, casing: lowerCase},
+	{name: "sg", id: numSG},
+	{name: "sp", id: numSP},
+	{name: "s", id: numS},
+	{name: "th", id: numTH, casing: lowerCase},
+	{name: "v", id: numV},
+}
+
+var numberIndex = makeKeywordIndex(numberKeywords)
+
+// The symbols of the C locale.
+const (
+	decimalPoint      = "."
+	thousandsSep      = ","
+	currencySymbol    = " "
+	negativeSign      = "-"
+	positiveSign      = "+"
+	maxDoubleDigits   = 15
+	maxRomanNumber    = 3999
+	romanNumeralWidth = 15
+)
+
+// numFlag is a bit set of the properties of a numeric template.
+type numFlag int
+
+const (
+	numFDecimal numFlag = 1 << iota
+	numFLDecimal
+	numFZero
+	numFBlank
+	numFFillMode
+	numFLSign
+	numFBracket
+	numFMinus
+	numFPlus
+	numFRoman
+	numFMulti
+	numFPlusPost
+	numFMinusPost
+	numFEEEE
+)
+
+// The positions of the locale sign, written by the S pattern.
+const (
+	lsignNone = iota
+	lsignPre
+	lsignPost
+)
+
+// numDesc describes a numeric template.
+type numDesc struct {
+	// pre and post are the numbers of digits before and after the decimal
+	// point.
+	pre  int
+	post int
+	// lsign is the position of the locale sign.
+	lsign int
+	flag  numFlag
+	// preLSignNum is the number of digits before the locale sign, when it
+	// is before the decimal point.
+	preLSignNum int
+	// multi is the number of digits after the V pattern, which multiplies
+	// the value by a power of 10.
+	multi int
+	// zeroStart and zeroEnd are the positions of the first and last 0
+	// patterns.
+	zeroStart int
+	zeroEnd   int
+}
+
+func (num *numDesc) is(f numFlag) bool {
+	return num.flag&f != 0
+}
+
+func syntaxError(msg string) error {
+	return pgerror.NewError(pgerror.CodeSyntaxError, msg)
+}
+
+// parseNumberFormat parses a numeric template and describes it.
+func parseNumberFormat(format string) ([]node, numDesc, error) {
+	nodes := parseFormat(format, numberIndex, false)
+	var num numDesc
+	for _, n := range nodes {
+		if n.key == nil {
+			continue
+		}
+		if num.is(numFEEEE) && n.key.id != numE {
+			return nil, num, syntaxError(`"EEEE" must be the last pattern used`)
+		}
+		switch n.key.id {
+		case num9:
+			if num.is(numFBracket) {
+				return nil, num, syntaxError(`"9" must be ahead of "PR"`)
+			}
+			switch {
+			case num.is(numFMulti):
+				num.multi++
+			case num.is(numFDecimal):
+				num.post++
+			default:
+				num.pre++
+			}
+		case num0:
+			if num.is(numFBracket) {
+				return nil, num, syntaxError(`"0" must be ahead of "PR"`)
+			}
+			if !num.is(numFZero) && !num.is(numFDecimal) {
+				num.flag |= numFZero
+				num.zeroStart = num.pre + 1
+			}
+			if !num.is(numFDecimal) {
+				num.pre++
+			} else {
+				num.post++
+			}
+			num.zeroEnd = num.pre + num.post
+		case numB:
+			if num.pre == 0 && num.post == 0 && !num.is(numFZero) {
+				num.flag |= numFBlank
+			}
+		case numD, numDec:
+			if n.key.id == numD {
+				num.flag |= numFLDecimal
+			}
+			if num.is(numFDecimal) {
+				return nil, num, syntaxError("multiple decimal points")
+			}
+			if num.is(numFMulti) {
+				return nil, num, syntaxError(`cannot use "V" and decimal point together`)
+			}
+			num.flag |= numFDecimal
+		case numFM:
+			num.flag |= numFFillMode
+		case numS:
+			if num.is(numFLSign) {
+				return nil, num, syntaxError(`cannot use "S" twice`)
+			}
+			if num.is(numFPlus) || num.is(numFMinus) || num.is(numFBracket) {
+				return nil, num, syntaxError(`cannot use "S" and "PL"/"MI"/"SG"/"PR" together`)
+			}
+			if !num.is(numFDecimal) {
+				num.lsign = lsignPre
+				num.preLSignNum = num.pre
+				num.flag |= numFLSign
+			} else if num.lsign == lsignNone {
+				num.lsign = lsignPost
+				num.flag |= numFLSign
+			}
+		case numMI:
+			if num.is(numFLSign) {
+				return nil, num, syntaxError(`cannot use "S" and "MI" together`)
+			}
+			num.flag |= numFMinus
+			if num.is(numFDecimal) {
+				num.flag |= numFMinusPost
+			}
+		case numPL:
+			if num.is(numFLSign) {
+				return nil, num, syntaxError(`cannot use "S" and "PL" together`)
+			}
+			num.flag |= numFPlus
+			if num.is(numFDecimal) {
+				num.flag |= numFPlusPost
+			}
+		case numSG:
+			if num.is(numFLSign) {
+				return nil, num, syntaxError(`cannot use "S" and "SG" together`)
+			}
+			num.flag |= numFMinus | numFPlus
+		case numPR:
+			if num.is(numFLSign) || num.is(numFPlus) || num.is(numFMinus) {
+				return nil, num, syntaxError(`cannot use "PR" and "S"/"PL"/"MI"/"SG" together`)
+			}
+			num.flag |= numFBracket
+		case numRN:
+			num.flag |= numFRoman
+		case numV:
+			if num.is(numFDecimal) {
+				return nil, num, syntaxError(`cannot use "V" and decimal point together`)
+			}
+			num.flag |= numFMulti
+		case numE:
+			if num.is(numFEEEE) {
+				return nil, num, syntaxError(`cannot use "EEEE" twice`)
+			}
+			if num.is(numFBlank) || num.is(numFFillMode) || num.is(numFLSign) ||
+				num.is(numFBracket) || num.is(numFMinus) || num.is(numFPlus) ||
+				num.is(numFRoman) || num.is(numFMulti) {
+				return nil, num, pgerror.NewError(pgerror.CodeSyntaxError,
+					`"EEEE" is incompatible with other formats`).SetDetailf(
+					`"EEEE" may only be used together with digit and decimal point patterns.`)
+			}
+			num.flag |= numFEEEE
+		}
+	}
+	return nodes, num, nil
+}
+
+// decimalCtx is the context of the decimal operations of to_char, which
+// round half away from zero, as PostgreSQL does.
+var decimalCtx = &apd.Context{
+	Precision:   2000,
+	Rounding:    apd.RoundHalfUp,
+	MaxExponent: 2000,
+	MinExponent: -2000,
+	Traps:       apd.DefaultTraps,
+}
+
+// FormatDecimal formats d according to the template pattern format.
+func FormatDecimal(d *apd.Decimal, format string) (string, error) {
+	nodes, num, err := parseNumberFormat(format)
+	if err != nil {
+		return "", err
+	}
+	var numStr string
+	outPreSpaces := 0
+	sign := byte('+')
+	switch {
+	case num.is(numFRoman):
+		var r apd.Decimal
+		if _, err := decimalCtx.Quantize(&r, d, 0); err != nil {
+			return "", err
+		}
+		i, err := r.Int64()
+		if err != nil || i < math.MinInt32 || i > math.MaxInt32 {
+			i = 0
+		}
+		numStr = intToRoman(int(i))
+	case num.is(numFEEEE):
+		numStr, err = decimalToSci(d, num.post)
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case numStr == "NaN":
+			numStr = overflowSci(num)
+		case numStr[0] != '-':
+			numStr = " " + numStr
+		}
+	default:
+		val := d
+		if num.is(numFMulti) {
+			val = new(apd.Decimal).Set(d)
+			val.Exponent += int32(num.multi)
+			num.pre += num.multi
+		}
+		var r apd.Decimal
+		if val.Form == apd.Finite {
+			if _, err := decimalCtx.Quantize(&r, val, -int32(num.post)); err != nil {
+				return "", err
+			}
+			if r.IsZero() {
+				r.Negative = false
+			}
+		} else {
+			r.Set(val)
+		}
+		numStr = r.Text('f')
+		if numStr[0] == '-' {
+			sign = '-'
+			numStr = numStr[1:]
+		}
+		numStr, outPreSpaces = padOrOverflow(numStr, num)
+	}
+	return num.toChar(nodes, numStr, outPreSpaces, sign), nil
+}
+
+// FormatFloat formats f according to the template pattern format. The
+// number of digits of the result is limited to the precision of a float.
+func FormatFloat(f float64, format string) (string, error) {
+	nodes, num, err := parseNumberFormat(format)
+	if err != nil {
+		return "", err
+	}
+	var numStr string
+	outPreSpaces := 0
+	sign := byte('+')
+	switch {
+	case num.is(numFRoman):
+		r := math.RoundToEven(f)
+		i := 0
+		if r >= math.MinInt32 && r <= math.MaxInt32 {
+			i = int(r)
+		}
+		numStr = intToRoman(i)
+	case num.is(numFEEEE):
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			numStr = overflowSci(num)
+		} else {
+			numStr = fmt.Sprintf("%+.*e", num.post, f)
+			// A positive sign is replaced by a space.
+			if numStr[0] == '+' {
+				numStr = " " + numStr[1:]
+			}
+		}
+	default:
+		val := f
+		if num.is(numFMulti) {
+			val = f * math.Pow(10, float64(num.multi))
+			num.pre += num.multi
+		}
+		preLen := len(fmt.Sprintf("%.0f", math.Abs(val)))
+		// The digits are limited to the precision of a float.
+		if preLen >= maxDoubleDigits {
+			num.post = 0
+		} else if preLen+num.post > maxDoubleDigits {
+			num.post = maxDoubleDigits - preLen
+		}
+		numStr = fmt.Sprintf("%.*f", num.post, val)
+		if numStr[0] == '-' {
+			sign = '-'
+			numStr = numStr[1:]
+		}
+		numStr, outPreSpaces = padOrOverflow(numStr, num)
+	}
+	return num.toChar(nodes, numStr, outPreSpaces, sign), nil
+}
+
+// padOrOverflow returns the number of spaces needed before the digits of
+// numStr to fill the digits of the template, or replaces numStr with #
+// characters if its digits do not fit in the template.
+func padOrOverflow(numStr string, num numDesc) (string, int) {
+	preLen := len(numStr)
+	if i := strings.IndexByte(numStr, '.'); i >= 0 {
+		preLen = i
+	}
+	if preLen < num.pre {
+		return numStr, num.pre - preLen
+	}
+	if preLen > num.pre {
+		b := bytes.Repeat([]byte{'#'}, num.pre+num.post+1)
+		b[num.pre] = '.'
+		return string(b), 0
+	}
+	return numStr, 0
+}
+
+// overflowSci returns the # characters written in scientific notation for
+// the values which are not finite.
+func overflowSci(num numDesc) string {
+	// There are 6 characters for the leading sign, the decimal point, "e",
+	// the sign of the exponent and two digits of the exponent.
+	b := bytes.Repeat([]byte{'#'}, num.pre+num.post+6)
+	b[0] = ' '
+	b[num.pre+1] = '.'
+	return string(b)
+}
+
+// decimalToSci formats d in scientific notation with post digits after the
+// decimal point. The significand is not normalized after rounding, as in
+// PostgreSQL.
+func decimalToSci(d *apd.Decimal, post int) (string, error) {
+	if d.Form != apd.Finite {
+		return "NaN", nil
+	}
+	exponent := 0
+	if !d.IsZero() {
+		exponent = int(d.NumDigits()) - 1 + int(d.Exponent)
+	}
+	var sig apd.Decimal
+	sig.Set(d)
+	sig.Exponent -= int32(exponent)
+	if _, err := decimalCtx.Quantize(&sig, &sig, -int32(post)); err != nil {
+		return "", err
+	}
+	if sig.IsZero() {
+		sig.Negative = false
+	}
+	return fmt.Sprintf("%se%+03d", sig.Text('f'), exponent), nil
+}
+
+var (
+	romanHundreds = []string{"C", "CC", "CCC", "CD", "D", "DC", "DCC", "DCCC", "CM"}
+	romanTens     = []string{"X", "XX", "XXX", "XL", "L", "LX", "LXX", "LXXX", "XC"}
+	romanUnits    = []string{"I", "II", "III", "IV", "V", "VI", "VII", "VIII", "IX"}
+)
+
+// intToRoman writes a number in Roman numerals, or as # characters if it is
+// out of the range 1 to 3999.
+func intToRoman(number int) string {
+	if number < 1 || number > maxRomanNumber {
+		return strings.Repeat("#", romanNumeralWidth)
+	}
+	var buf bytes.Buffer
+	digits := strconv.Itoa(number)
+	for i := range digits {
+		d := int(digits[i] - '1')
+		if d < 0 {
+			continue
+		}
+		switch len(digits) - i {
+		case 4:
+			buf.WriteString(strings.Repeat("M", d+1))
+		case 3:
+			buf.WriteString(romanHundreds[d])
+		case 2:
+			buf.WriteString(romanTens[d])
+		case 1:
+			buf.WriteString(romanUnits[d])
+		}
+	}
+	return buf.String()
+}
+
+// lastRelevantDigit returns the position of the last significant digit
+// after the decimal point of numStr, or of the decimal point if there are
+// none, or -1 if there is no decimal point.
+func lastRelevantDigit(numStr string) int {
+	p := strings.IndexByte(numStr, '.')
+	if p < 0 {
+		return -1
+	}
+	res := p
+	for p++; p < len(numStr); p++ {
+		if numStr[p] != '0' {
+			res = p
+		}
+	}
+	return res
+}
+
+// numProc is the state of the conversion of a number to a string.
+type numProc struct {
+	num *numDesc
+	buf bytes.Buffer
+	// sign is the sign of the number, '+' or '-'.
+	sign      byte
+	signWrote bool
+	// number holds the digits of the number, and numberP is the position of
+	// the next digit to write.
+	number  string
+	numberP int
+	// numIn is set once a digit has been written.
+	numIn bool
+	// numCurr is the position of the next digit pattern, and numCount the
+	// position of the last one.
+	numCurr      int
+	numCount     int
+	outPreSpaces int
+	// lastRelevant is the position of the last digit to write in fill
+	// mode, or -1.
+	lastRelevant int
+}
+
+// toChar writes numStr, the digits of a number with sign, according to the
+// parsed template. outPreSpaces is the number of digit patterns which
+// precede the first digit of numStr.
+func (num *numDesc) toChar(nodes []node, numStr string, outPreSpaces int, sign byte) string {
+	if num.zeroStart > 0 {
+		num.zeroStart--
+	}
+	if num.is(numFEEEE) {
+		return numStr
+	}
+	np := numProc{num: num, number: numStr, lastRelevant: -1}
+	if num.is(numFRoman) {
+		// The Roman numerals ignore the other patterns.
+		num.lsign, num.preLSignNum, num.post, num.pre, outPreSpaces, sign = 0, 0, 0, 0, 0, 0
+		num.flag &= numFFillMode
+		num.flag |= numFRoman
+	}
+
+	np.sign = sign
+	if num.is(numFPlus) || num.is(numFMinus) {
+		// MI, PL and SG write the sign themselves, but a sign is still
+		// needed with the digits for PL alone.
+		np.signWrote = !(num.is(numFPlus) && !num.is(numFMinus))
+	} else {
+		if np.sign != '-' {
+			if num.is(numFBracket) && num.is(numFFillMode) {
+				num.flag &^= numFBracket
+			}
+		}
+		np.signWrote = np.sign == '+' && num.is(numFFillMode) && !num.is(numFLSign)
+		if num.lsign == lsignPre && num.pre == num.preLSignNum {
+			num.lsign = lsignPost
+		}
+	}
+
+	np.numCount = num.post + num.pre - 1
+	np.outPreSpaces = outPreSpaces
+	if num.is(numFFillMode) && num.is(numFDecimal) {
+		np.lastRelevant = lastRelevantDigit(np.number)
+		// The digits of the 0 patterns are not stripped, within the digits
+		// of the number.
+		if np.lastRelevant >= 0 && num.zeroEnd > np.outPreSpaces {
+			lastZero := num.zeroEnd - np.outPreSpaces
+			if lastZero > len(np.number)-1 {
+				lastZero = len(np.number) - 1
+			}
+			if np.lastRelevant < lastZero {
+				np.lastRelevant = lastZero
+			}
+		}
+	}
+	if !np.signWrote && np.outPreSpaces == 0 {
+		np.numCount++
+	}
+
+	for _, n := range nodes {
+		if n.key == nil {
+			np.buf.WriteString(n.char)
+			continue
+		}
+		switch n.key.id {
+		case num9, num0, numDec, numD:
+			np.numPartToChar(n.key.id)
+		case numComma:
+			if np.numIn {
+				np.buf.WriteString(",")
+			} else if !num.is(numFFillMode) {
+				np.buf.WriteString(" ")
+			}
+		case numG:
+			if np.numIn {
+				np.buf.WriteString(thousandsSep)
+			} else if !num.is(numFFillMode) {
+				np.buf.WriteString(strings.Repeat(" ", len(thousandsSep)))
+			}
+		case numL:
+			np.buf.WriteString(currencySymbol)
+		case numRN:
+			s := np.number[np.numberP:]
+			if !num.is(numFFillMode) {
+				s = fmt.Sprintf("%*s", romanNumeralWidth, s)
+			}
+			np.buf.WriteString(applyCasing(s, n.key.casing))
+		case numTH:
+			if num.is(numFRoman) || (np.number != "" && np.number[0] == '#') ||
+				np.sign == '-' || num.is(numFDecimal) {
+				continue
+			}
+			mode := thUpper
+			if n.key.casing == lowerCase {
+				mode = thLower
+			}
+			np.buf.WriteString(ordinalSuffix(np.number, mode))
+		case numMI:
+			if np.sign == '-' {
+				np.buf.WriteByte('-')
+			} else if !num.is(numFFillMode) {
+				np.buf.WriteByte(' ')
+			}
+		case numPL:
+			if np.sign == '+' {
+				np.buf.WriteByte('+')
+			} else if !num.is(numFFillMode) {
+				np.buf.WriteByte(' ')
+			}
+		case numSG:
+			np.buf.WriteByte(np.sign)
+		}
+	}
+	return np.buf.String()
+}
+
+// isPreDecSpace returns whether the zero before the decimal point of a
+// number smaller than 1 is written as a space, as in " .1" for 9.9.
+func (np *numProc) isPreDecSpace() bool {
+	return !np.num.is(numFZero) && np.numberP == 0 && np.number != "" &&
+		np.number[0] == '0' && np.num.post != 0
+}
+
+// lastRelevantIs returns whether the last relevant digit is the character
+// c.
+func (np *numProc) lastRelevantIs(c byte) bool {
+	return np.lastRelevant >= 0 && np.lastRelevant < len(np.number) &&
+		np.number[np.lastRelevant] == c
+}
+
+// numPartToChar writes the digit or decimal point of a digit pattern, with
+// the sign before the first digit and after the last one.
+func (np *numProc) numPartToChar(id keywordID) {
+	num := np.num
+	if num.is(numFRoman) {
+		return
+	}
+	if !np.signWrote &&
+		(np.numCurr >= np.outPreSpaces || (num.is(numFZero) && num.zeroStart == np.numCurr)) &&
+		(!np.isPreDecSpace() || np.lastRelevantIs('.')) {
+		switch {
+		case num.is(numFLSign):
+			if num.lsign == lsignPre {
+				if np.sign == '-' {
+					np.buf.WriteString(negativeSign)
+				} else {
+					np.buf.WriteString(positiveSign)
+				}
+				np.signWrote = true
+			}
+		case num.is(numFBracket):
+			if np.sign == '+' {
+				np.buf.WriteByte(' ')
+			} else {
+				np.buf.WriteByte('<')
+			}
+			np.signWrote = true
+		case np.sign == '+':
+			if !num.is(numFFillMode) {
+				np.buf.WriteByte(' ')
+			}
+			np.signWrote = true
+		case np.sign == '-':
+			np.buf.WriteByte('-')
+			np.signWrote = true
+		}
+	}
+
+	switch {
+	case np.numCurr < np.outPreSpaces && (num.zeroStart > np.numCurr || !num.is(numFZero)):
+		if !num.is(numFFillMode) {
+			np.buf.WriteByte(' ')
+		}
+	case num.is(numFZero) && np.numCurr < np.outPreSpaces && num.zeroStart <= np.numCurr:
+		np.buf.WriteByte('0')
+		np.numIn = true
+	default:
+		var c byte
+		if np.numberP < len(np.number) {
+			c = np.number[np.numberP]
+		}
+		if c == '.' {
+			if !np.lastRelevantIs('.') || num.is(numFFillMode) {
+				// In fill mode, 9 is written as "9." for FM9.9.
+				np.buf.WriteString(decimalPoint)
+			}
+		} else {
+			switch {
+			case np.lastRelevant >= 0 && np.numberP > np.lastRelevant && id != num0:
+			case np.isPreDecSpace():
+				if !num.is(numFFillMode) {
+					np.buf.WriteByte(' ')
+				} else if np.lastRelevantIs('.') {
+					// 0 is written as "0." for FM9.9.
+					np.buf.WriteByte('0')
+				}
+			default:
+				if c != 0 {
+					np.buf.WriteByte(c)
+				}
+				np.numIn = true
+			}
+		}
+		if np.numberP < len(np.number) {
+			np.numberP++
+		}
+	}
+
+	end := np.numCount
+	if np.outPreSpaces > 0 {
+		end++
+	}
+	if num.is(numFDecimal) {
+		end++
+	}
+	if np.lastRelevant >= 0 && np.lastRelevant == np.numberP {
+		end = np.numCurr
+	}
+	if np.numCurr+1 == end {
+		if np.signWrote && num.is(numFBracket) {
+			if np.sign == '+' {
+				np.buf.WriteByte(' ')
+			} else {
+				np.buf.WriteByte('>')
+			}
+		} else if num.is(numFLSign) && num.lsign == lsignPost {
+			if np.sign == '-' {
+				np.buf.WriteString(negativeSign)
+			} else {
+				np.buf.WriteString(positiveSign)
+			}
+		}
+	}
+	np.numCurr++
+}
+
+// numParser is the state of the conversion of a string to a number.
+type numParser struct {
+	num *numDesc
+	in  string
+	pos int
+	// number holds the sign of the number followed by its digits.
+	number   []byte
+	readDec  bool
+	readPre  int
+	readPost int
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// ParseNumber parses s according to the template pattern format. The
+// characters of s which do not match the patterns of the template are
+// skipped.
+func ParseNumber(s, format string) (*apd.Decimal, error) {
+	nodes, num, err := parseNumberFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	if num.is(numFEEEE) {
+		return nil, pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+			`"EEEE" not supported for input`)
+	}
+	if num.is(numFRoman) {
+		return nil, pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+			`"RN" not supported for input`)
+	}
+	p := numParser{num: &num, in: s, number: []byte{' '}}
+	for _, n := range nodes {
+		if p.pos >= len(s) {
+			break
+		}
+		if n.key == nil {
+			// Each literal character of the template skips a character of
+			// the input, whether it matches or not.
+			_, size := utf8.DecodeRuneInString(s[p.pos:])
+			p.pos += size
+			continue
+		}
+		switch n.key.id {
+		case num9, num0, numDec, numD:
+			p.numPartFromChar(n.key.id)
+		case numComma:
+			if num.is(numFFillMode) || s[p.pos] != ',' {
+				continue
+			}
+		case numG:
+			if num.is(numFFillMode) || !strings.HasPrefix(s[p.pos:], thousandsSep) {
+				continue
+			}
+			p.pos += len(thousandsSep) - 1
+		case numL:
+			p.eatNonDataChars(len(currencySymbol))
+			continue
+		case numTH:
+			if !num.is(numFDecimal) {
+				p.eatNonDataChars(2)
+			}
+			continue
+		case numMI, numPL, numSG:
+			c := s[p.pos]
+			if (c == '-' && n.key.id != numPL) || (c == '+' && n.key.id != numMI) {
+				p.number[0] = c
+			} else {
+				p.eatNonDataChars(1)
+				continue
+			}
+		default:
+			continue
+		}
+		p.pos++
+	}
+
+	if p.number[len(p.number)-1] == '.' {
+		p.number = p.number[:len(p.number)-1]
+	}
+	numStr := string(p.number)
+	d, _, err := apd.NewFromString(strings.TrimSpace(numStr))
+	if err != nil {
+		return nil, pgerror.NewErrorf(pgerror.CodeInvalidTextRepresentationError,
+			"invalid input syntax for type numeric: %q", numStr)
+	}
+	precision := num.pre + num.multi + p.readPost
+	if maxDigits := precision - p.readPost; !d.IsZero() &&
+		int(d.NumDigits())+int(d.Exponent) > maxDigits {
+		limit := "1"
+		if maxDigits > 0 {
+			limit = fmt.Sprintf("10^%d", maxDigits)
+		}
+		return nil, pgerror.NewError(pgerror.CodeNumericValueOutOfRangeError,
+			"numeric field overflow").SetDetailf(
+			"A field with precision %d, scale %d must round to an absolute value less than %s.",
+			precision, p.readPost, limit)
+	}
+	if num.is(numFMulti) {
+		d.Exponent -= int32(num.multi)
+	}
+	return d, nil
+}
+
+// eatNonDataChars skips up to n characters of the input, stopping at the
+// first digit, sign, decimal point or comma.
+func (p *numParser) eatNonDataChars(n int) {
+	for ; n > 0 && p.pos < len(p.in); n-- {
+		if strings.IndexByte("0123456789.,+-", p.in[p.pos]) >= 0 {
+			break
+		}
+		_, size := utf8.DecodeRuneInString(p.in[p.pos:])
+		p.pos += size
+	}
+}
+
+// numPartFromChar reads the digit or decimal point of a digit pattern, with
+// the sign before the first digit or after the last one.
+func (p *numParser) numPartFromChar(id keywordID) {
+	num := p.num
+	s := p.in
+	if p.pos >= len(s) {
+		return
+	}
+	if s[p.pos] == ' ' {
+		p.pos++
+	}
+	if p.pos >= len(s) {
+		return
+	}
+
+	// Read the sign before the number.
+	if p.number[0] == ' ' && (id == num0 || id == num9) && p.readPre+p.readPost == 0 {
+		if num.is(numFLSign) && num.lsign == lsignPre {
+			if strings.HasPrefix(s[p.pos:], negativeSign) {
+				p.pos += len(negativeSign)
+				p.number[0] = '-'
+			} else if strings.HasPrefix(s[p.pos:], positiveSign) {
+				p.pos += len(positiveSign)
+				p.number[0] = '+'
+			}
+		} else {
+			if s[p.pos] == '-' || (num.is(numFBracket) && s[p.pos] == '<') {
+				p.number[0] = '-'
+				p.pos++
+			} else if s[p.pos] == '+' {
+				p.number[0] = '+'
+				p.pos++
+			}
+		}
+	}
+	if p.pos >= len(s) {
+		return
+	}
+
+	// Read a digit or the decimal point.
+	isRead := false
+	if isDigit(s[p.pos]) {
+		if p.readDec && p.readPost == num.post {
+			return
+		}
+		p.number = append(p.number, s[p.pos])
+		if p.readDec {
+			p.readPost++
+		} else {
+			p.readPre++
+		}
+		isRead = true
+	} else if num.is(numFDecimal) && !p.readDec && strings.HasPrefix(s[p.pos:], decimalPoint) {
+		p.pos += len(decimalPoint) - 1
+		p.number = append(p.number, '.')
+		p.readDec = true
+		isRead = true
+	}
+	if p.pos >= len(s) {
+		return
+	}
+
+	// Read the sign after the last digit, since its position is not
+	// exactly known, as in 5.01- for FM9.999999MI.
+	if p.number[0] == ' ' && p.readPre+p.readPost > 0 {
+		if num.is(numFLSign) && isRead && p.pos+1 < len(s) && !isDigit(s[p.pos+1]) {
+			// The locale sign is anchored to the last digit.
+			tmp := p.pos
+			p.pos++
+			if strings.HasPrefix(s[p.pos:], negativeSign) {
+				p.pos += len(negativeSign) - 1
+				p.number[0] = '-'
+			} else if strings.HasPrefix(s[p.pos:], positiveSign) {
+				p.pos += len(positiveSign) - 1
+				p.number[0] = '+'
+			}
+			if p.number[0] == ' ' {
+				p.pos = tmp
+			}
+		} else if !isRead && !num.is(numFLSign) && (num.is(numFPlus) || num.is(numFMinus)) {
+			if s[p.pos] == '-' || s[p.pos] == '+' {
+				p.number[0] = s[p.pos]
+			}
+		}
+	}
+}
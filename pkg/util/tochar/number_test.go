@@ -0,0 +1,187 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tochar
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cockroachdb/apd"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestFormatNumber(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		value    string
+		format   string
+		expected string
+	}{
+		{`-0.1`, `99.99`, `  -.10`},
+		{`-0.1`, `FM9.99`, `-.1`},
+		{`-0.1`, `FM90.99`, `-0.1`},
+		{`0.1`, `0.9`, ` 0.1`},
+		{`12`, `9990999.9`, `    0012.0`},
+		{`12`, `FM9990999.9`, `0012.`},
+		{`485`, `999`, ` 485`},
+		{`-485`, `999`, `-485`},
+		{`485`, `9 9 9`, ` 4 8 5`},
+		{`1485`, `9,999`, ` 1,485`},
+		{`1485`, `9G999`, ` 1,485`},
+		{`148.5`, `999.999`, ` 148.500`},
+		{`148.5`, `FM999.999`, `148.5`},
+		{`148.5`, `FM999.990`, `148.500`},
+		{`3148.5`, `9G999D999`, ` 3,148.500`},
+		{`-485`, `999S`, `485-`},
+		{`-485`, `999MI`, `485-`},
+		{`485`, `999MI`, `485 `},
+		{`485`, `FM999MI`, `485`},
+		{`485`, `SG999`, `+485`},
+		{`-485`, `SG999`, `-485`},
+		{`-485`, `9SG99`, `4-85`},
+		{`-485`, `999PR`, `<485>`},
+		{`485`, `999PR`, ` 485 `},
+		{`485`, `RN`, `        CDLXXXV`},
+		{`485`, `FMRN`, `CDLXXXV`},
+		{`5.2`, `FMRN`, `V`},
+		{`1999`, `FMrn`, `mcmxcix`},
+		{`4000`, `RN`, `###############`},
+		{`482`, `999th`, ` 482nd`},
+		{`11`, `99TH`, ` 11TH`},
+		{`485`, `"Good number:"999`, `Good number: 485`},
+		{`485.8`, `"Pre:"999" Post:" .999`, `Pre: 485 Post: .800`},
+		{`12`, `99V999`, ` 12000`},
+		{`12.4`, `99V999`, ` 12400`},
+		{`12.45`, `99V9`, ` 125`},
+		{`0.0004859`, `9.99EEEE`, ` 4.86e-04`},
+		{`-1234.5`, `9.9EEEE`, `-1.2e+03`},
+		{`-125.8`, `999D99S`, `125.80-`},
+		{`12345`, `999`, ` ###`},
+		{`12345.6`, `99.9`, ` ##.#`},
+		{`0`, `999`, `   0`},
+		{`0.5`, `9`, ` 1`},
+		{`-0.001`, `9.99`, `  .00`},
+		{`1`, `S9`, `+1`},
+		{`123456789`, `999G999G999`, ` 123,456,789`},
+		{`1`, `999G999G999`, `           1`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.value+"/"+tc.format, func(t *testing.T) {
+			d, _, err := apd.NewFromString(tc.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s, err := FormatDecimal(d, tc.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, s)
+			}
+		})
+	}
+}
+
+func TestFormatFloat(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		value    float64
+		format   string
+		expected string
+	}{
+		{-0.1, `99.99`, `  -.10`},
+		{148.5, `FM999.990`, `148.500`},
+		{-485, `999PR`, `<485>`},
+		{485, `FMRN`, `CDLXXXV`},
+		{0.0004859, `9.99EEEE`, ` 4.86e-04`},
+		// Floats are rounded half to even, and they are multiplied by V
+		// inexactly.
+		{0.5, `9`, ` 0`},
+		{12.45, `99V9`, ` 124`},
+		{-0.001, `9.99`, ` -.00`},
+		// The digits are limited to the precision of a float.
+		{1.0 / 3, `9.99999999999999999999`, `  .33333333333333`},
+		{1234567.0 / 7, `999999.9999999999`, ` 176366.714285714`},
+		{math.Inf(1), `9.99EEEE`, ` #.######`},
+		{math.NaN(), `9.99EEEE`, ` #.######`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			s, err := FormatFloat(tc.value, tc.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, s)
+			}
+		})
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		input    string
+		format   string
+		expected string
+		err      string
+	}{
+		{`12,454.8-`, `99G999D9S`, `-12454.8`, ``},
+		{`-34,338,492`, `99G999G999`, `-34338492`, ``},
+		{`-34,338,492.654,878`, `99G999G999D999G999`, `-34338492.654878`, ``},
+		{`34,50`, `999,99`, `3450`, ``},
+		{`123456`, `999G999`, `123456`, ``},
+		{`42nd`, `99th`, `42`, ``},
+		{`5.01-`, `FM9.999999MI`, `-5.01`, ``},
+		{`5 4 4 4 4 8 . 7 8`, `9 9 9 9 9 9 . 9 9`, `544448.78`, ``},
+		{`.01`, `FM9.99`, `0.01`, ``},
+		{`<123.45>`, `999.99PR`, `-123.45`, ``},
+		{`+123`, `SG999`, `123`, ``},
+		{`12.`, `99.9`, `12`, ``},
+		{`12.345`, `99.99`, `12.34`, ``},
+		{`12000`, `99V999`, `12.000`, ``},
+		{`0000001`, `9999999`, `1`, ``},
+		{`abc`, `999`, ``, `invalid input syntax for type numeric: " "`},
+		{`1234`, `99.9`, ``, `numeric field overflow`},
+		{`1e3`, `9.9EEEE`, ``, `"EEEE" not supported for input`},
+		{`X`, `RN`, ``, `"RN" not supported for input`},
+		{`1`, `9.9.9`, ``, `multiple decimal points`},
+		{`1`, `99PR9`, ``, `"9" must be ahead of "PR"`},
+		{`1`, `S9S`, ``, `cannot use "S" twice`},
+		{`1`, `9V9.9`, ``, `cannot use "V" and decimal point together`},
+		{`1`, `FM9EEEE`, ``, `"EEEE" is incompatible with other formats`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input+"/"+tc.format, func(t *testing.T) {
+			d, err := ParseNumber(tc.input, tc.format)
+			if tc.err != "" {
+				if !testutils.IsError(err, tc.err) {
+					t.Fatalf("expected error %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := d.String(); s != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, s)
+			}
+		})
+	}
+}
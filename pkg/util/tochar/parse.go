@@ -0,0 +1,744 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tochar
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// dateMode is the convention of the date fields of a template: either the
+// Gregorian calendar or the ISO 8601 week-numbering year.
+type dateMode int
+
+const (
+	dateModeNone dateMode = iota
+	dateModeGregorian
+	dateModeISOWeek
+)
+
+// fromChar collects the fields read from a string. A zero field was not
+// read.
+type fromChar struct {
+	mode dateMode
+	// hh12 is set when the hour uses a 12-hour clock.
+	hh12 bool
+	pm   bool
+	bc   bool
+	hh   int
+	mi   int
+	ss   int
+	ssss int
+	ms   int
+	us   int
+	d    int
+	dd   int
+	ddd  int
+	mm   int
+	cc   int
+	j    int
+	w    int
+	ww   int
+	year int
+	// yearDigits is the number of digits of the year pattern, like 2 for YY.
+	yearDigits int
+	// tzSign is 1 or -1 when the time zone offset was read, and 0 otherwise.
+	tzSign int
+	tzh    int
+	tzm    int
+}
+
+// dateParser reads a string according to a parsed template.
+type dateParser struct {
+	nodes []node
+	// in is the whole input, and s its remainder.
+	in string
+	s  string
+	fc fromChar
+}
+
+// ParseTimestamp parses s according to the template pattern format. The
+// result is in the location given by the TZH and TZM patterns if present,
+// and in loc otherwise. The fields which are not in the template take their
+// lowest value, January 1, 1 BC at midnight.
+func ParseTimestamp(s, format string, loc *time.Location) (time.Time, error) {
+	f, usec, err := parseFields(s, format)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if f.zone != "" {
+		loc = time.FixedZone(f.zone, f.gmtoff)
+	}
+	return time.Date(f.year, time.Month(f.mon), f.mday, f.hour, f.min, f.sec,
+		usec*1000, loc), nil
+}
+
+// ParseDate parses s according to the template pattern format, as
+// ParseTimestamp does, and returns the midnight UTC of the date.
+func ParseDate(s, format string) (time.Time, error) {
+	f, _, err := parseFields(s, format)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return date(f.year, f.mon, f.mday), nil
+}
+
+// parseFields reads s according to format, validates the fields and returns
+// them with the microseconds.
+func parseFields(s, format string) (fields, int, error) {
+	p := dateParser{nodes: parseFormat(format, datetimeIndex, true), in: s, s: s}
+	if err := p.parse(); err != nil {
+		return fields{}, 0, err
+	}
+	return p.fc.toFields(s)
+}
+
+func isSpace(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\r', '\v', '\f':
+		return true
+	}
+	return false
+}
+
+// isSeparator returns whether c is a printable ASCII character other than a
+// space, a letter or a digit.
+func isSeparator(c byte) bool {
+	return c > 0x20 && c < 0x7f &&
+		!(c >= 'A' && c <= 'Z') && !(c >= 'a' && c <= 'z') && !(c >= '0' && c <= '9')
+}
+
+// skipChar consumes a character of the input.
+func (p *dateParser) skipChar() {
+	_, size := utf8.DecodeRuneInString(p.s)
+	p.s = p.s[size:]
+}
+
+// skipSpaces consumes the spaces at the start of the input and returns their
+// number.
+func (p *dateParser) skipSpaces() int {
+	n := 0
+	for p.s != "" && isSpace(p.s[0]) {
+		p.s = p.s[1:]
+		n++
+	}
+	return n
+}
+
+func (p *dateParser) parse() error {
+	fx := false
+	// extraSkip is the number of characters skipped beyond the ones of the
+	// template.
+	extraSkip := 0
+	for i := 0; i < len(p.nodes) && p.s != ""; i++ {
+		n := p.nodes[i]
+		// Spaces are ignored at the start of the input and before fields,
+		// unless in fixed format mode.
+		if !fx && (n.key == nil || n.key.id != dchFX) && (n.key != nil || i == 0) {
+			extraSkip += p.skipSpaces()
+		}
+		if n.key == nil {
+			isSep := !n.quoted && len(n.char) == 1 &&
+				(isSpace(n.char[0]) || isSeparator(n.char[0]))
+			switch {
+			case fx:
+				// In fixed format mode, each character of the template
+				// consumes a character of the input, whatever it is.
+				p.skipChar()
+			case isSep:
+				// A space or separator of the template matches a space or
+				// separator of the input, if any.
+				extraSkip--
+				if p.s != "" && (isSpace(p.s[0]) || isSeparator(p.s[0])) {
+					p.s = p.s[1:]
+					extraSkip++
+				}
+			case extraSkip > 0:
+				// The character may have been skipped already.
+				extraSkip--
+			default:
+				p.skipChar()
+			}
+			continue
+		}
+
+		if err := p.setMode(n); err != nil {
+			return err
+		}
+		var err error
+		switch n.key.id {
+		case dchFX:
+			fx = true
+		case dchMeridiem:
+			var v int
+			if v, err = p.searchSeq(n, applyDots([]string{"AM", "PM"}, n.key.dots)); err == nil {
+				p.fc.pm = v == 1
+				p.fc.hh12 = true
+			}
+		case dchEra:
+			var v int
+			if v, err = p.searchSeq(n, applyDots([]string{"AD", "BC"}, n.key.dots)); err == nil {
+				p.fc.bc = v == 1
+			}
+		case dchHH12:
+			_, err = p.parseIntLen(&p.fc.hh, 2, i)
+			p.fc.hh12 = true
+		case dchHH24:
+			_, err = p.parseIntLen(&p.fc.hh, 2, i)
+		case dchMI:
+			_, err = p.parseInt(&p.fc.mi, i)
+		case dchSS:
+			_, err = p.parseInt(&p.fc.ss, i)
+		case dchMS:
+			var l int
+			if l, err = p.parseIntLen(&p.fc.ms, 3, i); err == nil {
+				// 25 is 0.25, as is 250; 025 is 0.025.
+				for ; l < 3; l++ {
+					p.fc.ms *= 10
+				}
+			}
+		case dchUS:
+			var l int
+			if l, err = p.parseIntLen(&p.fc.us, 6, i); err == nil {
+				for ; l < 6; l++ {
+					p.fc.us *= 10
+				}
+			}
+		case dchSSSS:
+			_, err = p.parseInt(&p.fc.ssss, i)
+		case dchTZ, dchOF:
+			return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+				"formatting field %q is only supported in to_char", n.key.name)
+		case dchTZH:
+			// The sign of the offset may have been skipped as a separator.
+			switch {
+			case p.s != "" && (p.s[0] == '+' || p.s[0] == '-' || p.s[0] == ' '):
+				p.fc.tzSign = 1
+				if p.s[0] == '-' {
+					p.fc.tzSign = -1
+				}
+				p.s = p.s[1:]
+			case extraSkip > 0 && p.in[len(p.in)-len(p.s)-1] == '-':
+				p.fc.tzSign = -1
+			default:
+				p.fc.tzSign = 1
+			}
+			_, err = p.parseIntLen(&p.fc.tzh, 2, i)
+		case dchTZM:
+			if p.fc.tzSign == 0 {
+				p.fc.tzSign = 1
+			}
+			_, err = p.parseIntLen(&p.fc.tzm, 2, i)
+		case dchMonth:
+			var v int
+			if v, err = p.searchSeq(n, fullMonths); err == nil {
+				err = p.setInt(&p.fc.mm, v+1, n)
+			}
+		case dchMon:
+			var v int
+			if v, err = p.searchSeq(n, shortNames(fullMonths)); err == nil {
+				err = p.setInt(&p.fc.mm, v+1, n)
+			}
+		case dchMM:
+			_, err = p.parseInt(&p.fc.mm, i)
+		case dchDay:
+			var v int
+			if v, err = p.searchSeq(n, fullDays); err == nil {
+				err = p.setInt(&p.fc.d, v+1, n)
+			}
+		case dchDy:
+			var v int
+			if v, err = p.searchSeq(n, shortNames(fullDays)); err == nil {
+				err = p.setInt(&p.fc.d, v+1, n)
+			}
+		case dchDDD:
+			_, err = p.parseInt(&p.fc.ddd, i)
+		case dchIDDD:
+			_, err = p.parseIntLen(&p.fc.ddd, 3, i)
+		case dchDD:
+			_, err = p.parseInt(&p.fc.dd, i)
+		case dchD:
+			_, err = p.parseInt(&p.fc.d, i)
+		case dchID:
+			if _, err = p.parseIntLen(&p.fc.d, 1, i); err == nil {
+				// The days are numbered from Sunday, as in the Gregorian
+				// convention.
+				if p.fc.d++; p.fc.d > 7 {
+					p.fc.d = 1
+				}
+			}
+		case dchWW, dchIW:
+			_, err = p.parseInt(&p.fc.ww, i)
+		case dchQ:
+			// The quarter is read but ignored, since it is unclear which
+			// date of the quarter it stands for.
+			var q int
+			_, err = p.parseInt(&q, i)
+		case dchCC:
+			_, err = p.parseInt(&p.fc.cc, i)
+		case dchYCommaYYY:
+			err = p.parseYCommaYYY(n)
+		case dchYYYY, dchIYYY:
+			_, err = p.parseInt(&p.fc.year, i)
+			p.fc.yearDigits = 4
+		case dchYYY, dchIYY:
+			err = p.parsePartialYear(i, 3)
+		case dchYY, dchIY:
+			err = p.parsePartialYear(i, 2)
+		case dchY, dchI:
+			err = p.parsePartialYear(i, 1)
+		case dchRM:
+			var v int
+			if v, err = p.searchSeq(n, romanMonths); err == nil {
+				err = p.setInt(&p.fc.mm, 12-v, n)
+			}
+		case dchW:
+			_, err = p.parseInt(&p.fc.w, i)
+		case dchJ:
+			_, err = p.parseInt(&p.fc.j, i)
+		}
+		if err != nil {
+			return err
+		}
+		if n.th != thNone {
+			// Skip the ordinal suffix.
+			for k := 0; k < 2 && p.s != ""; k++ {
+				p.skipChar()
+			}
+		}
+		// Spaces are ignored after fields, unless in fixed format mode.
+		if !fx {
+			extraSkip = p.skipSpaces()
+		}
+	}
+	return nil
+}
+
+// applyDots adds periods to the letters of the given strings, like A.M.
+func applyDots(strs []string, dots bool) []string {
+	if !dots {
+		return strs
+	}
+	res := make([]string, len(strs))
+	for i, s := range strs {
+		res[i] = s[:1] + "." + s[1:] + "."
+	}
+	return res
+}
+
+// shortNames returns the three-letter abbreviations of month or day names.
+func shortNames(names []string) []string {
+	res := make([]string, len(names))
+	for i, s := range names {
+		res[i] = s[:3]
+	}
+	return res
+}
+
+// setMode checks that the Gregorian and ISO week date conventions are not
+// mixed in the template.
+func (p *dateParser) setMode(n node) error {
+	var mode dateMode
+	switch n.key.id {
+	case dchIDDD, dchID, dchIW, dchIYYY, dchIYY, dchIY, dchI:
+		mode = dateModeISOWeek
+	case dchFX, dchMeridiem, dchHH12, dchHH24, dchMI, dchSS, dchMS, dchUS, dchSSSS,
+		dchTZ, dchTZH, dchTZM, dchOF:
+		return nil
+	default:
+		mode = dateModeGregorian
+	}
+	if p.fc.mode == dateModeNone {
+		p.fc.mode = mode
+	} else if p.fc.mode != mode {
+		return pgerror.NewError(pgerror.CodeInvalidDatetimeFormatError,
+			"invalid combination of date conventions").SetHintf(
+			"Do not mix Gregorian and ISO week date conventions in a formatting template.")
+	}
+	return nil
+}
+
+// setInt sets a field, unless it has already been set to a different value.
+func (p *dateParser) setInt(dest *int, v int, n node) error {
+	if *dest != 0 && *dest != v {
+		return pgerror.NewErrorf(pgerror.CodeInvalidDatetimeFormatError,
+			"conflicting values for %q field in formatting string", n.key.name).SetDetailf(
+			"This value contradicts a previous setting for the same field type.")
+	}
+	*dest = v
+	return nil
+}
+
+// searchSeq consumes the first of the given strings found at the start of
+// the input, ignoring case, and returns its index.
+func (p *dateParser) searchSeq(n node, strs []string) (int, error) {
+	for i, s := range strs {
+		if len(p.s) >= len(s) && strings.EqualFold(p.s[:len(s)], s) {
+			p.s = p.s[len(s):]
+			return i, nil
+		}
+	}
+	maxLen := 0
+	for _, s := range strs {
+		if len(s) > maxLen {
+			maxLen = len(s)
+		}
+	}
+	return 0, pgerror.NewErrorf(pgerror.CodeInvalidDatetimeFormatError,
+		"invalid value %q for %q", truncate(p.s, maxLen), n.key.name).SetDetailf(
+		"The given value did not match any of the allowed values for this field.")
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
+
+// isNextSeparator returns whether the field of the i-th node is followed by
+// something other than a digit, in which case it is read greedily.
+func (p *dateParser) isNextSeparator(i int) bool {
+	if p.nodes[i].th != thNone {
+		return true
+	}
+	if i+1 == len(p.nodes) {
+		return true
+	}
+	next := p.nodes[i+1]
+	if next.key != nil {
+		return !next.key.digit
+	}
+	return !(len(next.char) == 1 && next.char[0] >= '0' && next.char[0] <= '9')
+}
+
+// parseInt reads a field with as many digits as letters in its pattern, or
+// greedily in fill mode or before a separator. It returns the number of
+// characters read.
+func (p *dateParser) parseInt(dest *int, i int) (int, error) {
+	return p.parseIntLen(dest, len(p.nodes[i].key.name), i)
+}
+
+// parseIntLen reads a field of the given width, or greedily in fill mode or
+// before a separator. It returns the number of characters read.
+func (p *dateParser) parseIntLen(dest *int, width int, i int) (int, error) {
+	n := p.nodes[i]
+	init := len(p.s)
+	p.skipSpaces()
+	copied := truncate(p.s, width)
+	var v int64
+	var used int
+	if n.fm || p.isNextSeparator(i) {
+		v, used = strtol(p.s)
+	} else {
+		if len(copied) < width {
+			return 0, pgerror.NewErrorf(pgerror.CodeInvalidDatetimeFormatError,
+				"source string too short for %q formatting field", n.key.name).SetDetailf(
+				"Field requires %d characters, but only %d remain.", width, len(copied)).SetHintf(
+				`If your source string is not fixed-width, try using the "FM" modifier.`)
+		}
+		v, used = strtol(copied)
+		if used > 0 && used < width {
+			return 0, pgerror.NewErrorf(pgerror.CodeInvalidDatetimeFormatError,
+				"invalid value %q for %q", copied, n.key.name).SetDetailf(
+				"Field requires %d characters, but only %d could be parsed.", width, used).SetHintf(
+				`If your source string is not fixed-width, try using the "FM" modifier.`)
+		}
+	}
+	if used == 0 {
+		return 0, pgerror.NewErrorf(pgerror.CodeInvalidDatetimeFormatError,
+			"invalid value %q for %q", copied, n.key.name).SetDetailf(
+			"Value must be an integer.")
+	}
+	p.s = p.s[used:]
+	if v < math.MinInt32 || v > math.MaxInt32 {
+		return 0, pgerror.NewErrorf(pgerror.CodeDatetimeFieldOverflowError,
+			"value for %q in source string is out of range", n.key.name).SetDetailf(
+			"Value must be in the range %d to %d.", math.MinInt32, math.MaxInt32)
+	}
+	if err := p.setInt(dest, int(v), n); err != nil {
+		return 0, err
+	}
+	return init - len(p.s), nil
+}
+
+// strtol reads a decimal integer with an optional sign at the start of s,
+// after spaces. It returns the number of characters read, which is 0 if
+// there are no digits. Values out of the range of an int64 are clamped.
+func strtol(s string) (int64, int) {
+	i := 0
+	for i < len(s) && isSpace(s[i]) {
+		i++
+	}
+	neg := false
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		neg = s[i] == '-'
+		i++
+	}
+	start := i
+	var v int64
+	for ; i < len(s) && s[i] >= '0' && s[i] <= '9'; i++ {
+		if v < math.MaxInt64/10 {
+			v = v*10 + int64(s[i]-'0')
+		}
+	}
+	if i == start {
+		return 0, 0
+	}
+	if neg {
+		v = -v
+	}
+	return v, i
+}
+
+// parsePartialYear reads a year with less than 4 digits, which is taken as
+// the year closest to 2020 with these last digits.
+func (p *dateParser) parsePartialYear(i int, digits int) error {
+	l, err := p.parseInt(&p.fc.year, i)
+	if err != nil {
+		return err
+	}
+	if l < 4 {
+		p.fc.year = adjustPartialYearTo2020(p.fc.year)
+	}
+	p.fc.yearDigits = digits
+	return nil
+}
+
+func adjustPartialYearTo2020(year int) int {
+	switch {
+	case year < 70:
+		// 0-69 are in the 2000s.
+		return year + 2000
+	case year < 100:
+		// 70-99 are in the 1900s.
+		return year + 1900
+	case year < 520:
+		// 100-519 are in the 2000s.
+		return year + 2000
+	case year < 1000:
+		// 520-999 are in the 1000s.
+		return year + 1000
+	}
+	return year
+}
+
+// parseYCommaYYY reads a year with a comma separating the thousands.
+func (p *dateParser) parseYCommaYYY(n node) error {
+	millennia, used := strtol(p.s)
+	var years int64
+	var used2 int
+	if used > 0 && used < len(p.s) && p.s[used] == ',' {
+		rest := p.s[used+1:]
+		years, used2 = strtol(truncate(rest, 3))
+	}
+	if used2 == 0 {
+		return pgerror.NewError(pgerror.CodeInvalidDatetimeFormatError,
+			`invalid input string for "Y,YYY"`)
+	}
+	p.s = p.s[used+1+used2:]
+	p.fc.yearDigits = 4
+	return p.setInt(&p.fc.year, int(millennia*1000+years), n)
+}
+
+// yearDays are the cumulative number of days at the end of each month, for
+// common and leap years.
+var yearDays = [2][13]int{
+	{0, 31, 59, 90, 120, 151, 181, 212, 243, 273, 304, 334, 365},
+	{0, 31, 60, 91, 121, 152, 182, 213, 244, 274, 305, 335, 366},
+}
+
+func isLeap(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+// fromJulianDay returns the date of a Julian day.
+func fromJulianDay(j int) (year, mon, mday int) {
+	t := time.Unix(int64(j-unixEpochJulianDay)*86400, 0).UTC()
+	return t.Year(), int(t.Month()), t.Day()
+}
+
+// isoWeekJulianDay returns the Julian day of the Monday of an ISO week.
+func isoWeekJulianDay(year, week int) int {
+	// The first week of the year contains January 4.
+	jan4 := date(year, 1, 4)
+	return julianDay(year, 1, 4) - (int(jan4.Weekday())+6)%7 + (week-1)*7
+}
+
+// toFields computes the date and time from the fields read from s.
+func (fc *fromChar) toFields(s string) (fields, int, error) {
+	f := fields{mon: 1, mday: 1}
+	usec := 0
+
+	if fc.ssss != 0 {
+		f.hour = fc.ssss / 3600
+		f.min = fc.ssss % 3600 / 60
+		f.sec = fc.ssss % 60
+	}
+	if fc.ss != 0 {
+		f.sec = fc.ss
+	}
+	if fc.mi != 0 {
+		f.min = fc.mi
+	}
+	if fc.hh != 0 {
+		f.hour = fc.hh
+	}
+	if fc.hh12 {
+		if f.hour < 1 || f.hour > 12 {
+			return f, 0, pgerror.NewErrorf(pgerror.CodeInvalidDatetimeFormatError,
+				"hour \"%d\" is invalid for the 12-hour clock", f.hour).SetHintf(
+				"Use the 24-hour clock, or give an hour between 1 and 12.")
+		}
+		if fc.pm && f.hour < 12 {
+			f.hour += 12
+		} else if !fc.pm && f.hour == 12 {
+			f.hour = 0
+		}
+	}
+
+	if fc.year != 0 {
+		if fc.cc != 0 && fc.yearDigits <= 2 {
+			// The last two digits of the year are in the given century,
+			// knowing that the 21st century runs from 2001 to 2100, and the
+			// 6th century BC from 600 BC to 501 BC.
+			cc := fc.cc
+			if fc.bc {
+				cc = -cc
+			}
+			f.year = fc.year % 100
+			switch {
+			case f.year == 0:
+				f.year = cc * 100
+				if cc < 0 {
+					f.year++
+				}
+			case cc >= 0:
+				f.year += (cc - 1) * 100
+			default:
+				f.year = (cc+1)*100 - f.year + 1
+			}
+		} else {
+			f.year = fc.year
+			if fc.bc && f.year > 0 {
+				f.year = 1 - f.year
+			}
+		}
+	} else if fc.cc != 0 {
+		// The year is the first one of the century.
+		cc := fc.cc
+		if fc.bc {
+			cc = -cc
+		}
+		if cc >= 0 {
+			f.year = (cc-1)*100 + 1
+		} else {
+			f.year = cc*100 + 1
+		}
+	}
+
+	if fc.j != 0 {
+		f.year, f.mon, f.mday = fromJulianDay(fc.j)
+	}
+
+	if fc.ww != 0 {
+		if fc.mode == dateModeISOWeek {
+			// Without a day, the date is the Monday of the week.
+			j := isoWeekJulianDay(f.year, fc.ww)
+			if fc.d > 1 {
+				j += fc.d - 2
+			} else if fc.d == 1 {
+				j += 6
+			}
+			f.year, f.mon, f.mday = fromJulianDay(j)
+		} else {
+			fc.ddd = (fc.ww-1)*7 + 1
+		}
+	}
+
+	if fc.w != 0 {
+		fc.dd = (fc.w-1)*7 + 1
+	}
+	if fc.dd != 0 {
+		f.mday = fc.dd
+	}
+	if fc.mm != 0 {
+		f.mon = fc.mm
+	}
+
+	if fc.ddd != 0 && (f.mon <= 1 || f.mday <= 1) {
+		// The month and day are computed from the day of the year, which is
+		// Gregorian or ISO depending on the template.
+		if f.year == 0 && !fc.bc {
+			return f, 0, pgerror.NewError(pgerror.CodeInvalidDatetimeFormatError,
+				"cannot calculate day of year without year information")
+		}
+		if fc.mode == dateModeISOWeek {
+			j0 := isoWeekJulianDay(f.year, 1) - 1
+			f.year, f.mon, f.mday = fromJulianDay(j0 + fc.ddd)
+		} else {
+			leap := 0
+			if isLeap(f.year) {
+				leap = 1
+			}
+			y := yearDays[leap]
+			m := 1
+			for ; m <= 12; m++ {
+				if fc.ddd <= y[m] {
+					break
+				}
+			}
+			if f.mon <= 1 {
+				f.mon = m
+			}
+			if f.mday <= 1 {
+				if m > 12 {
+					m = 12
+				}
+				f.mday = fc.ddd - y[m-1]
+			}
+		}
+	}
+
+	usec = fc.ms*1000 + fc.us
+
+	outOfRange := pgerror.NewErrorf(pgerror.CodeDatetimeFieldOverflowError,
+		"date/time field value out of range: %q", s)
+	if f.mon < 1 || f.mon > 12 || f.mday < 1 || f.mday > 31 {
+		return f, 0, outOfRange
+	}
+	leap := 0
+	if isLeap(f.year) {
+		leap = 1
+	}
+	if f.mday > yearDays[leap][f.mon]-yearDays[leap][f.mon-1] {
+		return f, 0, outOfRange
+	}
+	if f.hour < 0 || f.hour >= 24 || f.min < 0 || f.min >= 60 || f.sec < 0 || f.sec >= 60 ||
+		usec < 0 || usec >= 1000000 {
+		return f, 0, outOfRange
+	}
+
+	if fc.tzSign != 0 {
+		f.gmtoff = fc.tzSign * (fc.tzh*3600 + fc.tzm*60)
+		f.zone = fmt.Sprintf("%c%02d:%02d", "+-"[(1-fc.tzSign)/2], fc.tzh, fc.tzm)
+	}
+	return f, usec, nil
+}
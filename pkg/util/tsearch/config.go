@@ -0,0 +1,155 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tsearch
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// Config is a text search configuration, which determines how documents and
+// queries are split into words and how the words are normalized into
+// lexemes.
+type Config struct {
+	name string
+	// stopWords are the words too common to be useful in searches. They are
+	// not indexed, but still count as positions in a document.
+	stopWords map[string]struct{}
+	// stem reduces a lowercase word to its root form.
+	stem func(string) string
+}
+
+// Name returns the name of the configuration.
+func (c *Config) Name() string {
+	return c.name
+}
+
+var configs = map[string]*Config{
+	"simple": {
+		name: "simple",
+	},
+	"english": {
+		name:      "english",
+		stopWords: englishStopWords,
+		stem:      stemEnglish,
+	},
+}
+
+// DefaultConfigName is the name of the text search configuration used by
+// default.
+const DefaultConfigName = "pg_catalog.english"
+
+// ConfigNames lists the available text search configurations.
+var ConfigNames = []string{"english", "simple"}
+
+// GetConfig returns the text search configuration with the given name. The
+// name may be qualified with the pg_catalog schema.
+func GetConfig(name string) (*Config, error) {
+	name = strings.TrimPrefix(strings.ToLower(name), "pg_catalog.")
+	if c, ok := configs[name]; ok {
+		return c, nil
+	}
+	return nil, pgerror.NewErrorf(pgerror.CodeUndefinedObjectError,
+		"text search configuration %q does not exist", name)
+}
+
+// words splits text into words: maximal sequences of letters and digits.
+func words(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// normalize returns the lexeme for a word, or false if the word is a stop
+// word.
+func (c *Config) normalize(word string) (string, bool) {
+	word = strings.ToLower(word)
+	if _, ok := c.stopWords[word]; ok {
+		return "", false
+	}
+	if c.stem != nil {
+		word = c.stem(word)
+	}
+	return word, true
+}
+
+// lexemes normalizes the words of text, skipping stop words.
+func (c *Config) lexemes(text string) []string {
+	var res []string
+	for _, w := range words(text) {
+		if l, ok := c.normalize(w); ok {
+			res = append(res, l)
+		}
+	}
+	return res
+}
+
+// ToTSVector normalizes a document into a TSVector. Each word gets the
+// position it has in the document, stop words included.
+func (c *Config) ToTSVector(document string) TSVector {
+	var lexemes []Lexeme
+	for i, w := range words(document) {
+		l, ok := c.normalize(w)
+		if !ok {
+			continue
+		}
+		pos := i + 1
+		if pos > MaxPosition {
+			pos = MaxPosition
+		}
+		lexemes = append(lexemes, Lexeme{Word: l, Positions: []Position{{Pos: uint16(pos)}}})
+	}
+	return MakeTSVector(lexemes)
+}
+
+// ToTSQuery parses a query in the text representation of a tsquery and
+// normalizes its operands. Operands consisting of several words are replaced
+// by the AND of their lexemes, and stop words are removed.
+func (c *Config) ToTSQuery(query string) (TSQuery, error) {
+	return parseTSQuery(query, c.lexemes)
+}
+
+// PlainToTSQuery builds the TSQuery matching the documents containing all
+// the words of text, ignoring punctuation and stop words.
+func (c *Config) PlainToTSQuery(text string) TSQuery {
+	var root *Node
+	for _, l := range c.lexemes(text) {
+		root = makeBinary(OpAnd, root, &Node{Op: OpOperand, Word: l})
+	}
+	return TSQuery{Root: root}
+}
+
+// englishStopWords is the list of English stop words from the Snowball
+// project, also used by PostgreSQL.
+var englishStopWords = makeStopWords(`
+	i me my myself we our ours ourselves you your yours yourself yourselves
+	he him his himself she her hers herself it its itself they them their
+	theirs themselves what which who whom this that these those am is are was
+	were be been being have has had having do does did doing a an the and but
+	if or because as until while of at by for with about against between into
+	through during before after above below to from up down in out on off
+	over under again further then once here there when where why how all any
+	both each few more most other some such no nor not only own same so than
+	too very s t can will just don should now`)
+
+func makeStopWords(list string) map[string]struct{} {
+	res := make(map[string]struct{})
+	for _, w := range strings.Fields(list) {
+		res[w] = struct{}{}
+	}
+	return res
+}
@@ -0,0 +1,70 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tsearch
+
+import "math/rand"
+
+// randomWord generates a short word over a small alphabet, so that random
+// vectors and queries have a good chance of sharing lexemes.
+func randomWord(rng *rand.Rand) string {
+	p := make([]byte, 1+rng.Intn(3))
+	for i := range p {
+		p[i] = byte('a' + rng.Intn(4))
+	}
+	return string(p)
+}
+
+// RandomTSVector generates a random TSVector.
+func RandomTSVector(rng *rand.Rand) TSVector {
+	lexemes := make([]Lexeme, rng.Intn(8))
+	for i := range lexemes {
+		lexemes[i].Word = randomWord(rng)
+		lexemes[i].Positions = make([]Position, rng.Intn(3))
+		for j := range lexemes[i].Positions {
+			lexemes[i].Positions[j] = Position{
+				Pos:    uint16(1 + rng.Intn(MaxPosition)),
+				Weight: Weight(rng.Intn(4)),
+			}
+		}
+	}
+	return MakeTSVector(lexemes)
+}
+
+// RandomTSQuery generates a random TSQuery.
+func RandomTSQuery(rng *rand.Rand) TSQuery {
+	if rng.Intn(10) == 0 {
+		return TSQuery{}
+	}
+	return TSQuery{Root: randomNode(rng, 3)}
+}
+
+func randomNode(rng *rand.Rand, depth int) *Node {
+	if depth == 0 || rng.Intn(2) == 0 {
+		return &Node{
+			Op:      OpOperand,
+			Word:    randomWord(rng),
+			Prefix:  rng.Intn(4) == 0,
+			Weights: byte(rng.Intn(16)),
+		}
+	}
+	switch rng.Intn(3) {
+	case 0:
+		return &Node{Op: OpNot, Left: randomNode(rng, depth-1)}
+	case 1:
+		return &Node{Op: OpAnd, Left: randomNode(rng, depth-1), Right: randomNode(rng, depth-1)}
+	default:
+		return &Node{Op: OpOr, Left: randomNode(rng, depth-1), Right: randomNode(rng, depth-1)}
+	}
+}
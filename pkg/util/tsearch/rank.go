@@ -0,0 +1,179 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tsearch
+
+import "math"
+
+// DefaultWeights are the default values of the weights D, C, B and A used
+// by Rank.
+var DefaultWeights = [4]float32{0.1, 0.2, 0.4, 1.0}
+
+// Normalization options of Rank, which can be combined.
+const (
+	// NormLogLength divides the rank by 1 + the logarithm of the document
+	// length.
+	NormLogLength = 1
+	// NormLength divides the rank by the document length.
+	NormLength = 2
+	// NormUniq divides the rank by the number of unique words in the
+	// document.
+	NormUniq = 8
+	// NormLogUniq divides the rank by 1 + the logarithm of the number of
+	// unique words in the document.
+	NormLogUniq = 16
+	// NormRank divides the rank by itself + 1.
+	NormRank = 32
+)
+
+// maxEntryPos is the distance used for pairs of lexemes without positions.
+const maxEntryPos = 1 << 14
+
+// nullPositions are the positions used for lexemes without positions.
+var nullPositions = []Position{{}}
+
+// Rank computes how relevant the document v is to the query q, based on the
+// frequency of the query lexemes in the document, and on their proximity for
+// queries whose top-level operator is AND. Occurrences are weighted by
+// weights, indexed by the weight of the occurrence. The rank is normalized
+// according to the normalization options set in the norm bit mask.
+//
+// This follows the ts_rank function of PostgreSQL.
+func Rank(weights [4]float32, v TSVector, q TSQuery, norm int) float32 {
+	if q.Root == nil {
+		return 0
+	}
+	operands := q.Operands()
+	var res float32
+	if q.Root.Op == OpAnd && len(operands) > 1 {
+		res = rankAnd(weights, v, operands)
+	} else {
+		res = rankOr(weights, v, operands)
+	}
+	if res < 0 {
+		res = 1e-20
+	}
+	if norm&NormLogLength != 0 && len(v) > 0 {
+		res = float32(float64(res) / (math.Log(float64(v.length()+1)) / math.Log(2.0)))
+	}
+	if norm&NormLength != 0 {
+		if l := v.length(); l > 0 {
+			res /= float32(l)
+		}
+	}
+	if norm&NormUniq != 0 && len(v) > 0 {
+		res /= float32(len(v))
+	}
+	if norm&NormLogUniq != 0 && len(v) > 0 {
+		res = float32(float64(res) / (math.Log(float64(len(v)+1)) / math.Log(2.0)))
+	}
+	if norm&NormRank != 0 {
+		res /= res + 1
+	}
+	return res
+}
+
+// length returns the number of words of the document, counting the lexemes
+// without positions once.
+func (v TSVector) length() int {
+	n := 0
+	for _, l := range v {
+		if len(l.Positions) == 0 {
+			n++
+		}
+		n += len(l.Positions)
+	}
+	return n
+}
+
+func positionsOrNull(l Lexeme) []Position {
+	if len(l.Positions) == 0 {
+		return nullPositions
+	}
+	return l.Positions
+}
+
+// rankOr sums the contributions of each query lexeme. The contribution of a
+// lexeme is dominated by its occurrence of highest weight; the following
+// occurrences contribute less and less.
+func rankOr(weights [4]float32, v TSVector, operands []*Node) float32 {
+	var res float32
+	for _, op := range operands {
+		for _, l := range v.matching(op.Word, op.Prefix) {
+			pos := positionsOrNull(l)
+			var resj, wjm float32 = 0, -1
+			jm := 0
+			for j, p := range pos {
+				w := weights[p.Weight]
+				resj += w / float32((j+1)*(j+1))
+				if w > wjm {
+					wjm, jm = w, j
+				}
+			}
+			// The limit of sum(1/i^2) for i=1..inf is pi^2/6.
+			res = float32(float64(res) + float64(wjm+resj-wjm/float32((jm+1)*(jm+1)))/1.64493406685)
+		}
+	}
+	if len(operands) > 0 {
+		res /= float32(len(operands))
+	}
+	return res
+}
+
+// rankAnd combines the proximity of all the pairs of occurrences of distinct
+// query lexemes.
+func rankAnd(weights [4]float32, v TSVector, operands []*Node) float32 {
+	var res float32 = -1
+	pos := make([][]Position, len(operands))
+	for i, op := range operands {
+		for _, l := range v.matching(op.Word, op.Prefix) {
+			pos[i] = positionsOrNull(l)
+			for k := 0; k < i; k++ {
+				if pos[k] == nil {
+					continue
+				}
+				for _, p1 := range pos[i] {
+					for _, p2 := range pos[k] {
+						dist := int(p1.Pos) - int(p2.Pos)
+						if dist < 0 {
+							dist = -dist
+						}
+						if dist == 0 {
+							if len(l.Positions) > 0 && &pos[k][0] != &nullPositions[0] {
+								continue
+							}
+							dist = maxEntryPos
+						}
+						curw := float32(math.Sqrt(float64(
+							weights[p1.Weight] * weights[p2.Weight] * wordDistance(dist))))
+						if res < 0 {
+							res = curw
+						} else {
+							res = float32(1 - (1-float64(res))*(1-float64(curw)))
+						}
+					}
+				}
+			}
+		}
+	}
+	return res
+}
+
+// wordDistance decreases with the distance between two occurrences.
+func wordDistance(dist int) float32 {
+	if dist > 100 {
+		return 1e-30
+	}
+	return float32(1.0 / (1.005 + 0.05*math.Exp(float64(dist)/1.5-2)))
+}
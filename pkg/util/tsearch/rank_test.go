@@ -0,0 +1,65 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tsearch
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestRank(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		vector   string
+		query    string
+		norm     int
+		expected string
+	}{
+		{`cat:1`, `cat`, 0, `0.0607927`},
+		{`cat:1`, `dog`, 0, `0`},
+		{`cat:1`, ``, 0, `0`},
+		{`cat`, `cat`, 0, `0.0607927`},
+		{`cat:1A`, `cat`, 0, `0.607927`},
+		{`cat:1,2,3`, `cat`, 0, `0.0827456`},
+		{`cat:1 dog:2`, `cat | dog`, 0, `0.0607927`},
+		{`cat:1 dog:2`, `cat & dog`, 0, `0.0991032`},
+		{`cat:1 dog:20`, `cat & dog`, 0, `0.00215862`},
+		{`cat:1 dog:2`, `cat & mouse`, 0, `1e-20`},
+		{`cat:1 dog:2 mouse:3`, `cat`, NormLength, `0.0202642`},
+		{`cat:1 dog:2 mouse:3`, `cat`, NormUniq | NormRank, `0.0198618`},
+		{`cat:1 dog:2 mouse:3`, `cat`, NormLogLength, `0.0303964`},
+		{`caterpillar:1 cat:2`, `cat:*`, 0, `0.121585`},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s/%s", tc.vector, tc.query), func(t *testing.T) {
+			v, err := ParseTSVector(tc.vector)
+			if err != nil {
+				t.Fatal(err)
+			}
+			q, err := ParseTSQuery(tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rank := Rank(DefaultWeights, v, q, tc.norm)
+			if res := strconv.FormatFloat(float64(rank), 'g', 6, 32); res != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, res)
+			}
+		})
+	}
+}
@@ -0,0 +1,289 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tsearch
+
+// stemEnglish reduces a lowercase English word to its stem with the Porter
+// stemming algorithm, so that for example "connected", "connecting" and
+// "connections" all become "connect". Words that are not made of ASCII
+// letters are returned unchanged.
+//
+// See: https://tartarus.org/martin/PorterStemmer/def.txt
+func stemEnglish(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			return word
+		}
+	}
+	s := porterStemmer{b: []byte(word), k: len(word) - 1}
+	s.step1ab()
+	if s.k > 0 {
+		s.step1c()
+		s.step2()
+		s.step3()
+		s.step4()
+		s.step5()
+	}
+	return string(s.b[:s.k+1])
+}
+
+// porterStemmer holds the state of the stemming of a word. The word being
+// stemmed is b[:k+1]; j is the end of the stem preceding the suffix matched
+// by the last successful call to ends.
+type porterStemmer struct {
+	b    []byte
+	k, j int
+}
+
+// cons returns whether b[i] is a consonant. A y is a consonant at the start
+// of a word or after a vowel.
+func (s *porterStemmer) cons(i int) bool {
+	switch s.b[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		return i == 0 || !s.cons(i-1)
+	}
+	return true
+}
+
+// m measures the number of vowel-consonant sequences in b[:j+1]. With [C]
+// and [V] optional sequences of consonants and vowels, the stem is of the
+// form [C](VC)^m[V].
+func (s *porterStemmer) m() int {
+	n, i := 0, 0
+	for ; i <= s.j && s.cons(i); i++ {
+	}
+	for {
+		for ; i <= s.j && !s.cons(i); i++ {
+		}
+		if i > s.j {
+			return n
+		}
+		for ; i <= s.j && s.cons(i); i++ {
+		}
+		n++
+	}
+}
+
+// vowelInStem returns whether b[:j+1] contains a vowel.
+func (s *porterStemmer) vowelInStem() bool {
+	for i := 0; i <= s.j; i++ {
+		if !s.cons(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// doubleCons returns whether b[i-1:i+1] is a double consonant.
+func (s *porterStemmer) doubleCons(i int) bool {
+	return i >= 1 && s.b[i] == s.b[i-1] && s.cons(i)
+}
+
+// cvc returns whether b[i-2:i+1] is consonant-vowel-consonant, with the last
+// consonant not being w, x or y. This is used to restore an e at the end of
+// short words, as in cav(e), lov(e) or hop(e).
+func (s *porterStemmer) cvc(i int) bool {
+	if i < 2 || !s.cons(i) || s.cons(i-1) || !s.cons(i-2) {
+		return false
+	}
+	switch s.b[i] {
+	case 'w', 'x', 'y':
+		return false
+	}
+	return true
+}
+
+// ends returns whether b[:k+1] ends with suffix, and sets j accordingly.
+func (s *porterStemmer) ends(suffix string) bool {
+	l := len(suffix)
+	if l > s.k+1 || string(s.b[s.k-l+1:s.k+1]) != suffix {
+		return false
+	}
+	s.j = s.k - l
+	return true
+}
+
+// setTo replaces b[j+1:k+1] with repl.
+func (s *porterStemmer) setTo(repl string) {
+	s.b = append(s.b[:s.j+1], repl...)
+	s.k = s.j + len(repl)
+}
+
+// replace replaces the suffix matched by ends with repl if the stem is long
+// enough.
+func (s *porterStemmer) replace(repl string) {
+	if s.m() > 0 {
+		s.setTo(repl)
+	}
+}
+
+// replaceSuffix replaces the first matching suffix of a list of pairs of
+// suffixes and replacements.
+func (s *porterStemmer) replaceSuffix(pairs ...string) {
+	for i := 0; i < len(pairs); i += 2 {
+		if s.ends(pairs[i]) {
+			s.replace(pairs[i+1])
+			return
+		}
+	}
+}
+
+// step1ab removes plurals, -ed and -ing.
+func (s *porterStemmer) step1ab() {
+	if s.b[s.k] == 's' {
+		if s.ends("sses") {
+			s.k -= 2
+		} else if s.ends("ies") {
+			s.setTo("i")
+		} else if s.b[s.k-1] != 's' {
+			s.k--
+		}
+	}
+	if s.ends("eed") {
+		if s.m() > 0 {
+			s.k--
+		}
+	} else if (s.ends("ed") || s.ends("ing")) && s.vowelInStem() {
+		s.k = s.j
+		if s.ends("at") {
+			s.setTo("ate")
+		} else if s.ends("bl") {
+			s.setTo("ble")
+		} else if s.ends("iz") {
+			s.setTo("ize")
+		} else if s.doubleCons(s.k) {
+			switch s.b[s.k] {
+			case 'l', 's', 'z':
+			default:
+				s.k--
+			}
+		} else if s.j = s.k; s.m() == 1 && s.cvc(s.k) {
+			s.setTo("e")
+		}
+	}
+}
+
+// step1c turns a terminal y into an i when there is another vowel in the
+// stem.
+func (s *porterStemmer) step1c() {
+	if s.ends("y") && s.vowelInStem() {
+		s.b[s.k] = 'i'
+	}
+}
+
+// step2 maps double suffixes to single ones, like -ization to -ize.
+func (s *porterStemmer) step2() {
+	switch s.b[s.k-1] {
+	case 'a':
+		s.replaceSuffix("ational", "ate", "tional", "tion")
+	case 'c':
+		s.replaceSuffix("enci", "ence", "anci", "ance")
+	case 'e':
+		s.replaceSuffix("izer", "ize")
+	case 'l':
+		s.replaceSuffix("bli", "ble", "alli", "al", "entli", "ent", "eli", "e", "ousli", "ous")
+	case 'o':
+		s.replaceSuffix("ization", "ize", "ation", "ate", "ator", "ate")
+	case 's':
+		s.replaceSuffix("alism", "al", "iveness", "ive", "fulness", "ful", "ousness", "ous")
+	case 't':
+		s.replaceSuffix("aliti", "al", "iviti", "ive", "biliti", "ble")
+	case 'g':
+		s.replaceSuffix("logi", "log")
+	}
+}
+
+// step3 handles -ic-, -full, -ness and the like.
+func (s *porterStemmer) step3() {
+	switch s.b[s.k] {
+	case 'e':
+		s.replaceSuffix("icate", "ic", "ative", "", "alize", "al")
+	case 'i':
+		s.replaceSuffix("iciti", "ic")
+	case 'l':
+		s.replaceSuffix("ical", "ic", "ful", "")
+	case 's':
+		s.replaceSuffix("ness", "")
+	}
+}
+
+// step4 removes -ant, -ence and the like from long enough stems.
+func (s *porterStemmer) step4() {
+	var suffixes []string
+	switch s.b[s.k-1] {
+	case 'a':
+		suffixes = []string{"al"}
+	case 'c':
+		suffixes = []string{"ance", "ence"}
+	case 'e':
+		suffixes = []string{"er"}
+	case 'i':
+		suffixes = []string{"ic"}
+	case 'l':
+		suffixes = []string{"able", "ible"}
+	case 'n':
+		suffixes = []string{"ant", "ement", "ment", "ent"}
+	case 'o':
+		if s.ends("ion") && s.j >= 0 && (s.b[s.j] == 's' || s.b[s.j] == 't') {
+			break
+		}
+		suffixes = []string{"ou"}
+	case 's':
+		suffixes = []string{"ism"}
+	case 't':
+		suffixes = []string{"ate", "iti"}
+	case 'u':
+		suffixes = []string{"ous"}
+	case 'v':
+		suffixes = []string{"ive"}
+	case 'z':
+		suffixes = []string{"ize"}
+	default:
+		return
+	}
+	if suffixes != nil {
+		found := false
+		for _, suffix := range suffixes {
+			if s.ends(suffix) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return
+		}
+	}
+	if s.m() > 1 {
+		s.k = s.j
+	}
+}
+
+// step5 removes a final -e and reduces a final -ll to -l in long enough
+// stems.
+func (s *porterStemmer) step5() {
+	s.j = s.k
+	if s.b[s.k] == 'e' {
+		if a := s.m(); a > 1 || (a == 1 && !s.cvc(s.k-1)) {
+			s.k--
+		}
+	}
+	if s.b[s.k] == 'l' && s.doubleCons(s.k) && s.m() > 1 {
+		s.k--
+	}
+}
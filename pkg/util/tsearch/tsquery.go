@@ -0,0 +1,472 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tsearch
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/pkg/errors"
+)
+
+// Op is the kind of a node of a TSQuery.
+type Op byte
+
+// Op values.
+const (
+	// OpOperand is a lexeme to look for in a document.
+	OpOperand Op = iota
+	// OpNot matches the documents not matching its operand.
+	OpNot
+	// OpAnd matches the documents matching both its operands.
+	OpAnd
+	// OpOr matches the documents matching either of its operands.
+	OpOr
+)
+
+// priority returns the binding strength of an operator, which determines
+// where parentheses are needed when formatting a query.
+func (op Op) priority() int {
+	switch op {
+	case OpOr:
+		return 1
+	case OpAnd:
+		return 2
+	case OpNot:
+		return 3
+	}
+	return 4
+}
+
+// Node is a node of a TSQuery.
+type Node struct {
+	Op Op
+	// Word, Prefix and Weights are set for OpOperand nodes. A prefix operand
+	// matches all the lexemes starting with Word. Weights is a bit mask, with
+	// the bit 1<<w set for each weight w the operand is restricted to, or 0 if
+	// it matches occurrences of any weight.
+	Word    string
+	Prefix  bool
+	Weights byte
+	// Left is the operand of OpNot nodes. Left and Right are the operands of
+	// OpAnd and OpOr nodes.
+	Left, Right *Node
+}
+
+// TSQuery is a boolean combination of lexemes to match against a TSVector.
+// The query with a nil root is empty, and matches no documents.
+type TSQuery struct {
+	Root *Node
+}
+
+// makeNot returns a NOT node. Operands removed during normalization are nil,
+// and the operators referencing them are simplified away.
+func makeNot(n *Node) *Node {
+	if n == nil {
+		return nil
+	}
+	return &Node{Op: OpNot, Left: n}
+}
+
+// makeBinary returns an AND or OR node, or one of its operands if the other
+// one was removed during normalization.
+func makeBinary(op Op, left, right *Node) *Node {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+	return &Node{Op: op, Left: left, Right: right}
+}
+
+// Matches returns whether the document v matches the query.
+func (q TSQuery) Matches(v TSVector) bool {
+	if q.Root == nil {
+		return false
+	}
+	return q.Root.matches(v)
+}
+
+func (n *Node) matches(v TSVector) bool {
+	switch n.Op {
+	case OpOperand:
+		for _, l := range v.matching(n.Word, n.Prefix) {
+			// Lexemes without positions carry no weight information, and
+			// satisfy any weight restriction.
+			if n.Weights == 0 || len(l.Positions) == 0 {
+				return true
+			}
+			for _, p := range l.Positions {
+				if n.Weights&(1<<p.Weight) != 0 {
+					return true
+				}
+			}
+		}
+		return false
+	case OpNot:
+		return !n.Left.matches(v)
+	case OpAnd:
+		return n.Left.matches(v) && n.Right.matches(v)
+	case OpOr:
+		return n.Left.matches(v) || n.Right.matches(v)
+	}
+	panic(errors.Errorf("unknown tsquery operator %d", n.Op))
+}
+
+// Operands returns the distinct operands of the query, ignoring their
+// weight restrictions, sorted by word.
+func (q TSQuery) Operands() []*Node {
+	var res []*Node
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		switch n.Op {
+		case OpOperand:
+			res = append(res, n)
+		case OpNot:
+			walk(n.Left)
+		default:
+			walk(n.Left)
+			walk(n.Right)
+		}
+	}
+	if q.Root != nil {
+		walk(q.Root)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Word != res[j].Word {
+			return res[i].Word < res[j].Word
+		}
+		return !res[i].Prefix && res[j].Prefix
+	})
+	if len(res) == 0 {
+		return nil
+	}
+	uniq := res[:1]
+	for _, n := range res[1:] {
+		if last := uniq[len(uniq)-1]; n.Word != last.Word || n.Prefix != last.Prefix {
+			uniq = append(uniq, n)
+		}
+	}
+	return uniq
+}
+
+// Compare returns -1, 0 or 1 if q is respectively smaller than, equal to or
+// larger than other. Queries are ordered by their text representation.
+func (q TSQuery) Compare(other TSQuery) int {
+	return strings.Compare(q.String(), other.String())
+}
+
+// Size returns the approximate size of the query in bytes.
+func (q TSQuery) Size() uintptr {
+	var sz uintptr
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		if n == nil {
+			return
+		}
+		sz += uintptr(len(n.Word)) + 48
+		walk(n.Left)
+		walk(n.Right)
+	}
+	walk(q.Root)
+	return sz
+}
+
+// String implements the fmt.Stringer interface.
+func (q TSQuery) String() string {
+	var buf bytes.Buffer
+	q.Format(&buf)
+	return buf.String()
+}
+
+// Format writes the text representation of the query to buf, for example
+// 'fat' & ( 'rat' | 'cat':*A ).
+func (q TSQuery) Format(buf *bytes.Buffer) {
+	if q.Root != nil {
+		q.Root.format(buf)
+	}
+}
+
+func (n *Node) format(buf *bytes.Buffer) {
+	formatChild := func(child *Node) {
+		if child.Op.priority() < n.Op.priority() {
+			buf.WriteString("( ")
+			child.format(buf)
+			buf.WriteString(" )")
+		} else {
+			child.format(buf)
+		}
+	}
+	switch n.Op {
+	case OpOperand:
+		formatLexeme(buf, n.Word)
+		if n.Prefix || n.Weights != 0 {
+			buf.WriteByte(':')
+			if n.Prefix {
+				buf.WriteByte('*')
+			}
+			for w := WeightA; ; w-- {
+				if n.Weights&(1<<w) != 0 {
+					buf.WriteString(w.String())
+				}
+				if w == WeightD {
+					break
+				}
+			}
+		}
+	case OpNot:
+		buf.WriteByte('!')
+		formatChild(n.Left)
+	case OpAnd, OpOr:
+		formatChild(n.Left)
+		if n.Op == OpAnd {
+			buf.WriteString(" & ")
+		} else {
+			buf.WriteString(" | ")
+		}
+		formatChild(n.Right)
+	}
+}
+
+// ParseTSQuery parses the text representation of a tsquery. The lexemes are
+// taken as is, without normalization. Operands may be combined with the
+// operators ! (NOT), & (AND) and | (OR), in decreasing order of precedence,
+// and grouped with parentheses. Each operand may be followed by a colon and
+// a * to match it as a prefix, and by weight letters to restrict the
+// occurrences it matches, as in 'supernova':*AB.
+func ParseTSQuery(s string) (TSQuery, error) {
+	return parseTSQuery(s, nil /* normalize */)
+}
+
+// parseTSQuery parses the text representation of a tsquery, normalizing its
+// operands with normalize unless it is nil. An operand may normalize to
+// several lexemes, which are combined with AND, or to none if it is a stop
+// word, in which case it is removed from the query.
+func parseTSQuery(s string, normalize func(string) []string) (TSQuery, error) {
+	p := queryParser{tsParser: tsParser{s: s}, normalize: normalize}
+	p.skipSpace()
+	if p.done() {
+		return TSQuery{}, nil
+	}
+	root, err := p.parseOr()
+	if err == nil && !p.done() {
+		err = p.unexpected()
+	}
+	if pgErr, ok := err.(*pgerror.Error); ok {
+		return TSQuery{}, pgErr
+	}
+	if err != nil {
+		return TSQuery{}, makeParseError(s, "tsquery", err)
+	}
+	return TSQuery{Root: root}, nil
+}
+
+type queryParser struct {
+	tsParser
+	normalize func(string) []string
+}
+
+func (p *queryParser) unexpected() error {
+	if p.peek() == '<' {
+		return pgerror.Unimplemented("tsquery phrase", "phrase search operators are not supported")
+	}
+	return errors.Errorf("syntax error at position %d", p.pos+1)
+}
+
+// next skips whitespace and returns whether the next character is c, in
+// which case it is consumed.
+func (p *queryParser) next(c byte) bool {
+	p.skipSpace()
+	if !p.done() && p.peek() == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *queryParser) parseOr() (*Node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.next('|') {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = makeBinary(OpOr, left, right)
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (*Node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.next('&') {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = makeBinary(OpAnd, left, right)
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseNot() (*Node, error) {
+	if p.next('!') {
+		n, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return makeNot(n), nil
+	}
+	if p.next('(') {
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.next(')') {
+			if p.done() {
+				return nil, errors.New("unbalanced parentheses")
+			}
+			return nil, p.unexpected()
+		}
+		return n, nil
+	}
+	if p.done() {
+		return nil, errors.New("unexpected end of input")
+	}
+	if strings.IndexByte("&|)<", p.peek()) >= 0 {
+		return nil, p.unexpected()
+	}
+	return p.parseOperand()
+}
+
+func (p *queryParser) parseOperand() (*Node, error) {
+	word, err := p.lexeme(true /* query */)
+	if err != nil {
+		return nil, err
+	}
+	operand := Node{Op: OpOperand}
+	if !p.done() && p.peek() == ':' {
+		p.pos++
+		for !p.done() {
+			if p.peek() == '*' {
+				operand.Prefix = true
+			} else if w, ok := parseWeight(p.peek()); ok {
+				operand.Weights |= 1 << w
+			} else {
+				break
+			}
+			p.pos++
+		}
+	}
+	words := []string{word}
+	if p.normalize != nil {
+		words = p.normalize(word)
+	}
+	var res *Node
+	for _, w := range words {
+		n := operand
+		n.Word = w
+		res = makeBinary(OpAnd, res, &n)
+	}
+	return res, nil
+}
+
+// Encode appends the binary encoding of the query to appendTo. The nodes are
+// encoded in prefix order.
+func (q TSQuery) Encode(appendTo []byte) []byte {
+	if q.Root == nil {
+		return appendTo
+	}
+	return q.Root.encode(appendTo)
+}
+
+// prefixFlag is set in the encoding of the weights of prefix operands.
+const prefixFlag = 1 << 4
+
+func (n *Node) encode(appendTo []byte) []byte {
+	appendTo = append(appendTo, byte(n.Op))
+	switch n.Op {
+	case OpOperand:
+		appendTo = encoding.EncodeUvarintAscending(appendTo, uint64(len(n.Word)))
+		appendTo = append(appendTo, n.Word...)
+		flags := n.Weights
+		if n.Prefix {
+			flags |= prefixFlag
+		}
+		return append(appendTo, flags)
+	case OpNot:
+		return n.Left.encode(appendTo)
+	default:
+		appendTo = n.Left.encode(appendTo)
+		return n.Right.encode(appendTo)
+	}
+}
+
+// DecodeTSQuery decodes a query encoded with Encode.
+func DecodeTSQuery(b []byte) (TSQuery, error) {
+	if len(b) == 0 {
+		return TSQuery{}, nil
+	}
+	b, root, err := decodeNode(b)
+	if err != nil {
+		return TSQuery{}, err
+	}
+	if len(b) > 0 {
+		return TSQuery{}, errors.Errorf("invalid tsquery encoding: %d trailing bytes", len(b))
+	}
+	return TSQuery{Root: root}, nil
+}
+
+func decodeNode(b []byte) ([]byte, *Node, error) {
+	if len(b) == 0 {
+		return nil, nil, errors.New("invalid tsquery encoding: unexpected end of input")
+	}
+	n := &Node{Op: Op(b[0])}
+	b = b[1:]
+	var err error
+	switch n.Op {
+	case OpOperand:
+		var word []byte
+		if b, word, err = decodeString(b); err != nil {
+			return nil, nil, err
+		}
+		if len(b) == 0 {
+			return nil, nil, errors.New("invalid tsquery encoding: missing operand flags")
+		}
+		n.Word = string(word)
+		n.Prefix = b[0]&prefixFlag != 0
+		n.Weights = b[0] &^ prefixFlag
+		return b[1:], n, nil
+	case OpNot:
+		b, n.Left, err = decodeNode(b)
+		return b, n, err
+	case OpAnd, OpOr:
+		if b, n.Left, err = decodeNode(b); err != nil {
+			return nil, nil, err
+		}
+		b, n.Right, err = decodeNode(b)
+		return b, n, err
+	}
+	return nil, nil, errors.Errorf("invalid tsquery encoding: unknown operator %d", n.Op)
+}
@@ -0,0 +1,185 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tsearch
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+)
+
+func TestParseTSQuery(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		input    string
+		expected string
+		err      string
+	}{
+		{``, ``, ``},
+		{`fat`, `'fat'`, ``},
+		{`fat & rat`, `'fat' & 'rat'`, ``},
+		{`fat&rat|cat`, `'fat' & 'rat' | 'cat'`, ``},
+		{`fat & (rat | cat)`, `'fat' & ( 'rat' | 'cat' )`, ``},
+		{`!fat & !(rat | cat)`, `!'fat' & !( 'rat' | 'cat' )`, ``},
+		{`!!fat`, `!!'fat'`, ``},
+		{`(((fat)))`, `'fat'`, ``},
+		{`super:*`, `'super':*`, ``},
+		{`super:*ab | cat:c`, `'super':*AB | 'cat':C`, ``},
+		{`'fat rat' & 'it''s'`, `'fat rat' & 'it''s'`, ``},
+		{`fat rat`, ``, `could not parse "fat rat" as type tsquery: syntax error at position 5`},
+		{`fat &`, ``, `could not parse "fat &" as type tsquery: unexpected end of input`},
+		{`(fat`, ``, `could not parse "(fat" as type tsquery: unbalanced parentheses`},
+		{`fat)`, ``, `could not parse "fat)" as type tsquery: syntax error at position 4`},
+		{`& fat`, ``, `could not parse "& fat" as type tsquery: syntax error at position 1`},
+		{`fat <-> rat`, ``, `phrase search operators are not supported`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			q, err := ParseTSQuery(tc.input)
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("expected error %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := q.String(); s != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, s)
+			}
+			if q2, err := ParseTSQuery(q.String()); err != nil {
+				t.Fatal(err)
+			} else if q.Compare(q2) != 0 {
+				t.Fatalf("%s round-tripped to %s", q, q2)
+			}
+			if q2, err := DecodeTSQuery(q.Encode(nil)); err != nil {
+				t.Fatal(err)
+			} else if !reflect.DeepEqual(q, q2) {
+				t.Fatalf("%s decoded to %s", q, q2)
+			}
+		})
+	}
+}
+
+func TestToTSQuery(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	c, err := GetConfig("english")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		input    string
+		expected string
+		plain    string
+	}{
+		{`Fat & Rats`, `'fat' & 'rat'`, `'fat' & 'rat'`},
+		{`the & rats`, `'rat'`, `'rat'`},
+		{`!(the | an)`, ``, ``},
+		{`'fat rats' | cats:*A`, `'fat' & 'rat' | 'cat':*A`, `'fat' & 'rat' & 'cat'`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			q, err := c.ToTSQuery(tc.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := q.String(); s != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, s)
+			}
+			if s := c.PlainToTSQuery(tc.input).String(); s != tc.plain {
+				t.Fatalf("expected %s, got %s", tc.plain, s)
+			}
+		})
+	}
+}
+
+func TestTSQueryMatches(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const doc = `'cat':3 'fat':2,11A 'mat':7 'rat':12 'sat':4 'supernova'`
+	testCases := []struct {
+		query    string
+		expected bool
+	}{
+		{``, false},
+		{`cat`, true},
+		{`dog`, false},
+		{`cat & rat`, true},
+		{`cat & dog`, false},
+		{`cat | dog`, true},
+		{`!dog`, true},
+		{`!cat`, false},
+		{`cat & !(dog | mouse)`, true},
+		{`ma:*`, true},
+		{`mo:*`, false},
+		{`fat:A`, true},
+		{`fat:B`, false},
+		{`cat:AB`, false},
+		{`cat:D`, true},
+		{`sa:*B`, false},
+		{`supernova:A`, true},
+	}
+	v, err := ParseTSVector(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			q, err := ParseTSQuery(tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res := q.Matches(v); res != tc.expected {
+				t.Fatalf("expected %t, got %t", tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestRandomRoundTrip(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	rng, _ := randutil.NewPseudoRand()
+	for i := 0; i < 1000; i++ {
+		v := RandomTSVector(rng)
+		if v2, err := ParseTSVector(v.String()); err != nil {
+			t.Fatal(err)
+		} else if v.Compare(v2) != 0 {
+			t.Fatalf("%s round-tripped to %s", v, v2)
+		}
+		if v2, err := DecodeTSVector(v.Encode(nil)); err != nil {
+			t.Fatal(err)
+		} else if v.Compare(v2) != 0 {
+			t.Fatalf("%s decoded to %s", v, v2)
+		}
+
+		q := RandomTSQuery(rng)
+		if q2, err := ParseTSQuery(q.String()); err != nil {
+			t.Fatal(err)
+		} else if q.Compare(q2) != 0 {
+			t.Fatalf("%s round-tripped to %s", q, q2)
+		}
+		if q2, err := DecodeTSQuery(q.Encode(nil)); err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(q, q2) {
+			t.Fatalf("%s decoded to %s", q, q2)
+		}
+	}
+}
@@ -0,0 +1,456 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package tsearch implements the tsvector and tsquery types used by full-text
+// search, the text search configurations that produce them from documents
+// and queries, and the ranking of matches.
+//
+// See: https://www.postgresql.org/docs/current/static/textsearch.html
+package tsearch
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/pkg/errors"
+)
+
+// Weight is the weight of a lexeme occurrence. Weights are used to mark
+// occurrences coming from different parts of a document, like its title or
+// its body, and to rank them differently.
+type Weight byte
+
+// Weight values, from the lowest to the highest. Occurrences without an
+// explicit weight have weight D.
+const (
+	WeightD Weight = iota
+	WeightC
+	WeightB
+	WeightA
+)
+
+// String implements the fmt.Stringer interface.
+func (w Weight) String() string {
+	return string("DCBA"[w])
+}
+
+func parseWeight(c byte) (Weight, bool) {
+	switch c {
+	case 'A', 'a':
+		return WeightA, true
+	case 'B', 'b':
+		return WeightB, true
+	case 'C', 'c':
+		return WeightC, true
+	case 'D', 'd':
+		return WeightD, true
+	}
+	return 0, false
+}
+
+// MaxPosition is the largest position of a lexeme occurrence. Larger
+// positions are silently reduced to MaxPosition.
+const MaxPosition = 1<<14 - 1
+
+// maxPositionsPerLexeme is the maximum number of occurrences recorded for a
+// lexeme. Further occurrences are silently dropped.
+const maxPositionsPerLexeme = 256
+
+// Position is an occurrence of a lexeme in a document.
+type Position struct {
+	Pos    uint16
+	Weight Weight
+}
+
+// Lexeme is a normalized word of a document, with the positions at which it
+// occurs. Positions are sorted and unique; they may be absent, for example
+// when the vector was produced from text without positional information.
+type Lexeme struct {
+	Word      string
+	Positions []Position
+}
+
+// TSVector is a sorted list of distinct lexemes, the representation of a
+// document used by full-text search.
+type TSVector []Lexeme
+
+// MakeTSVector builds a TSVector from a list of lexemes that may be unsorted
+// and contain duplicates. The positions of duplicated lexemes are merged.
+func MakeTSVector(lexemes []Lexeme) TSVector {
+	if len(lexemes) == 0 {
+		return nil
+	}
+	sort.SliceStable(lexemes, func(i, j int) bool { return lexemes[i].Word < lexemes[j].Word })
+	res := lexemes[:1]
+	for _, l := range lexemes[1:] {
+		last := &res[len(res)-1]
+		if l.Word == last.Word {
+			last.Positions = append(last.Positions, l.Positions...)
+			continue
+		}
+		res = append(res, l)
+	}
+	for i := range res {
+		res[i].Positions = normalizePositions(res[i].Positions)
+	}
+	return TSVector(res)
+}
+
+// normalizePositions sorts positions and removes duplicates, keeping the
+// highest weight of a duplicated position.
+func normalizePositions(pos []Position) []Position {
+	if len(pos) == 0 {
+		return nil
+	}
+	sort.Slice(pos, func(i, j int) bool {
+		if pos[i].Pos != pos[j].Pos {
+			return pos[i].Pos < pos[j].Pos
+		}
+		return pos[i].Weight > pos[j].Weight
+	})
+	res := pos[:1]
+	for _, p := range pos[1:] {
+		if p.Pos != res[len(res)-1].Pos {
+			res = append(res, p)
+		}
+	}
+	if len(res) > maxPositionsPerLexeme {
+		res = res[:maxPositionsPerLexeme]
+	}
+	return res
+}
+
+// matching returns the lexemes matching a query operand: the lexeme equal to
+// the operand's word, or all the lexemes starting with it for a prefix
+// operand.
+func (v TSVector) matching(word string, prefix bool) TSVector {
+	i := sort.Search(len(v), func(i int) bool { return v[i].Word >= word })
+	if !prefix {
+		if i < len(v) && v[i].Word == word {
+			return v[i : i+1]
+		}
+		return nil
+	}
+	j := i
+	for j < len(v) && strings.HasPrefix(v[j].Word, word) {
+		j++
+	}
+	return v[i:j]
+}
+
+// Compare returns -1, 0 or 1 if v is respectively smaller than, equal to or
+// larger than other. Vectors are ordered lexeme by lexeme; a lexeme orders
+// first by its word and then by its positions.
+func (v TSVector) Compare(other TSVector) int {
+	for i := 0; i < len(v) && i < len(other); i++ {
+		if c := strings.Compare(v[i].Word, other[i].Word); c != 0 {
+			return c
+		}
+		a, b := v[i].Positions, other[i].Positions
+		for j := 0; j < len(a) && j < len(b); j++ {
+			if a[j].Pos != b[j].Pos {
+				if a[j].Pos < b[j].Pos {
+					return -1
+				}
+				return 1
+			}
+			if a[j].Weight != b[j].Weight {
+				if a[j].Weight < b[j].Weight {
+					return -1
+				}
+				return 1
+			}
+		}
+		if len(a) != len(b) {
+			if len(a) < len(b) {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(v) < len(other):
+		return -1
+	case len(v) > len(other):
+		return 1
+	}
+	return 0
+}
+
+// Size returns the approximate size of the vector in bytes.
+func (v TSVector) Size() uintptr {
+	var sz uintptr
+	for _, l := range v {
+		sz += uintptr(len(l.Word)) + uintptr(len(l.Positions))*4 + 40
+	}
+	return sz
+}
+
+// String implements the fmt.Stringer interface.
+func (v TSVector) String() string {
+	var buf bytes.Buffer
+	v.Format(&buf)
+	return buf.String()
+}
+
+// Format writes the text representation of the vector to buf, for example
+// 'cat':3 'fat':2,11A.
+func (v TSVector) Format(buf *bytes.Buffer) {
+	for i, l := range v {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		formatLexeme(buf, l.Word)
+		for j, p := range l.Positions {
+			if j == 0 {
+				buf.WriteByte(':')
+			} else {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(strconv.Itoa(int(p.Pos)))
+			if p.Weight != WeightD {
+				buf.WriteString(p.Weight.String())
+			}
+		}
+	}
+}
+
+// formatLexeme writes a quoted lexeme, doubling the quotes and backslashes
+// it contains.
+func formatLexeme(buf *bytes.Buffer, word string) {
+	buf.WriteByte('\'')
+	for i := 0; i < len(word); i++ {
+		if c := word[i]; c == '\'' || c == '\\' {
+			buf.WriteByte(c)
+		}
+		buf.WriteByte(word[i])
+	}
+	buf.WriteByte('\'')
+}
+
+// ParseTSVector parses the text representation of a tsvector. The lexemes are
+// taken as is, without normalization: lexemes are separated by whitespace
+// and may be quoted, and each one may be followed by a colon and a list of
+// positions with optional weights, as in 'fat':2,4A cat:3.
+func ParseTSVector(s string) (TSVector, error) {
+	p := tsParser{s: s}
+	var lexemes []Lexeme
+	for {
+		p.skipSpace()
+		if p.done() {
+			break
+		}
+		word, err := p.lexeme(false /* query */)
+		if err != nil {
+			return nil, makeParseError(s, "tsvector", err)
+		}
+		l := Lexeme{Word: word}
+		if !p.done() && p.peek() == ':' {
+			p.pos++
+			if l.Positions, err = p.positions(); err != nil {
+				return nil, makeParseError(s, "tsvector", err)
+			}
+		}
+		lexemes = append(lexemes, l)
+	}
+	return MakeTSVector(lexemes), nil
+}
+
+func makeParseError(s, typ string, err error) error {
+	return pgerror.NewErrorf(pgerror.CodeSyntaxError,
+		"could not parse %q as type %s: %v", s, typ, err)
+}
+
+// tsParser is a scanner for the text representations of tsvector and
+// tsquery values.
+type tsParser struct {
+	s   string
+	pos int
+}
+
+func (p *tsParser) done() bool {
+	return p.pos >= len(p.s)
+}
+
+func (p *tsParser) peek() byte {
+	return p.s[p.pos]
+}
+
+func (p *tsParser) skipSpace() {
+	for !p.done() {
+		r, size := utf8.DecodeRuneInString(p.s[p.pos:])
+		if !unicode.IsSpace(r) {
+			return
+		}
+		p.pos += size
+	}
+}
+
+// lexeme scans a quoted or unquoted lexeme. Backslashes escape the following
+// character, and a quote is escaped in a quoted lexeme by doubling it. In a
+// query, unquoted lexemes also end at operators and parentheses.
+func (p *tsParser) lexeme(query bool) (string, error) {
+	var buf strings.Builder
+	quoted := p.peek() == '\''
+	if quoted {
+		p.pos++
+	}
+	for !p.done() {
+		c := p.peek()
+		switch {
+		case c == '\\':
+			p.pos++
+			if p.done() {
+				return "", errors.New("unexpected end of input after backslash")
+			}
+			c = p.peek()
+		case quoted && c == '\'':
+			if p.pos+1 < len(p.s) && p.s[p.pos+1] == '\'' {
+				p.pos++
+				break
+			}
+			p.pos++
+			if buf.Len() == 0 {
+				return "", errors.New("empty lexeme")
+			}
+			return buf.String(), nil
+		case !quoted && (c == ':' || (query && strings.IndexByte("&|!()<", c) >= 0)):
+			return unquotedLexeme(&buf)
+		case !quoted:
+			if r, _ := utf8.DecodeRuneInString(p.s[p.pos:]); unicode.IsSpace(r) {
+				return unquotedLexeme(&buf)
+			}
+		}
+		buf.WriteByte(c)
+		p.pos++
+	}
+	if quoted {
+		return "", errors.New("unterminated quoted lexeme")
+	}
+	return unquotedLexeme(&buf)
+}
+
+func unquotedLexeme(buf *strings.Builder) (string, error) {
+	if buf.Len() == 0 {
+		return "", errors.New("syntax error")
+	}
+	return buf.String(), nil
+}
+
+// positions scans a comma-separated list of positions with optional weights.
+func (p *tsParser) positions() ([]Position, error) {
+	var res []Position
+	for {
+		start := p.pos
+		for !p.done() && p.peek() >= '0' && p.peek() <= '9' {
+			p.pos++
+		}
+		if start == p.pos {
+			return nil, errors.New("expected a position")
+		}
+		n, err := strconv.Atoi(p.s[start:p.pos])
+		if err != nil || n > MaxPosition {
+			n = MaxPosition
+		}
+		if n == 0 {
+			return nil, errors.New("positions must be positive")
+		}
+		pos := Position{Pos: uint16(n)}
+		if !p.done() {
+			if w, ok := parseWeight(p.peek()); ok {
+				pos.Weight = w
+				p.pos++
+			}
+		}
+		res = append(res, pos)
+		if p.done() || p.peek() != ',' {
+			return res, nil
+		}
+		p.pos++
+	}
+}
+
+// Encode appends the binary encoding of the vector to appendTo.
+func (v TSVector) Encode(appendTo []byte) []byte {
+	appendTo = encoding.EncodeUvarintAscending(appendTo, uint64(len(v)))
+	for _, l := range v {
+		appendTo = encoding.EncodeUvarintAscending(appendTo, uint64(len(l.Word)))
+		appendTo = append(appendTo, l.Word...)
+		appendTo = encoding.EncodeUvarintAscending(appendTo, uint64(len(l.Positions)))
+		for _, p := range l.Positions {
+			appendTo = encoding.EncodeUvarintAscending(appendTo, uint64(p.Pos)<<2|uint64(p.Weight))
+		}
+	}
+	return appendTo
+}
+
+// DecodeTSVector decodes a vector encoded with Encode.
+func DecodeTSVector(b []byte) (TSVector, error) {
+	b, n, err := encoding.DecodeUvarintAscending(b)
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(b)) {
+		return nil, errors.Errorf("invalid tsvector encoding: %d lexemes in %d bytes", n, len(b))
+	}
+	var v TSVector
+	if n > 0 {
+		v = make(TSVector, n)
+	}
+	for i := range v {
+		var word []byte
+		if b, word, err = decodeString(b); err != nil {
+			return nil, err
+		}
+		v[i].Word = string(word)
+		var npos uint64
+		if b, npos, err = encoding.DecodeUvarintAscending(b); err != nil {
+			return nil, err
+		}
+		if npos > uint64(len(b)) {
+			return nil, errors.Errorf("invalid tsvector encoding: %d positions in %d bytes", npos, len(b))
+		}
+		if npos > 0 {
+			v[i].Positions = make([]Position, npos)
+		}
+		for j := range v[i].Positions {
+			var p uint64
+			if b, p, err = encoding.DecodeUvarintAscending(b); err != nil {
+				return nil, err
+			}
+			v[i].Positions[j] = Position{Pos: uint16(p >> 2), Weight: Weight(p & 3)}
+		}
+	}
+	if len(b) > 0 {
+		return nil, errors.Errorf("invalid tsvector encoding: %d trailing bytes", len(b))
+	}
+	return v, nil
+}
+
+func decodeString(b []byte) ([]byte, []byte, error) {
+	b, n, err := encoding.DecodeUvarintAscending(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	if n > uint64(len(b)) {
+		return nil, nil, errors.Errorf("invalid string length %d in %d bytes", n, len(b))
+	}
+	return b[n:], b[:n], nil
+}
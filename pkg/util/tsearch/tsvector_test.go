@@ -0,0 +1,140 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tsearch
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestParseTSVector(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		input    string
+		expected string
+		err      string
+	}{
+		{``, ``, ``},
+		{`a fat cat sat on a mat`, `'a' 'cat' 'fat' 'mat' 'on' 'sat'`, ``},
+		{`  spaces	and
+newlines `, `'and' 'newlines' 'spaces'`, ``},
+		{`fat:2,4 cat:3 rat:5A`, `'cat':3 'fat':2,4 'rat':5A`, ``},
+		{`a:3,1,2 a:2B`, `'a':1,2B,3`, ``},
+		{`a:1b,2C,3d`, `'a':1B,2C,3`, ``},
+		{`a:99999`, `'a':16383`, ``},
+		{`'quoted lexeme' 'it''s' back\ slash`, `'back slash' 'it''s' 'quoted lexeme'`, ``},
+		{`'a\\b'`, `'a\\b'`, ``},
+		{`'unterminated`, ``, `could not parse "'unterminated" as type tsvector: unterminated quoted lexeme`},
+		{`a:`, ``, `could not parse "a:" as type tsvector: expected a position`},
+		{`a:0`, ``, `could not parse "a:0" as type tsvector: positions must be positive`},
+		{`:1`, ``, `could not parse ":1" as type tsvector: syntax error`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			v, err := ParseTSVector(tc.input)
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("expected error %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := v.String(); s != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, s)
+			}
+			// The text representation is parsed back to the same vector.
+			if v2, err := ParseTSVector(v.String()); err != nil {
+				t.Fatal(err)
+			} else if v.Compare(v2) != 0 {
+				t.Fatalf("%s round-tripped to %s", v, v2)
+			}
+			// So is the binary representation.
+			if v2, err := DecodeTSVector(v.Encode(nil)); err != nil {
+				t.Fatal(err)
+			} else if !reflect.DeepEqual(v, v2) {
+				t.Fatalf("%s decoded to %s", v, v2)
+			}
+		})
+	}
+}
+
+func TestTSVectorCompare(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ordered := []string{``, `a`, `a b`, `a:1`, `a:1,2`, `a:1A`, `a:2`, `ab`, `b`}
+	for i := range ordered {
+		for j := range ordered {
+			v1, err := ParseTSVector(ordered[i])
+			if err != nil {
+				t.Fatal(err)
+			}
+			v2, err := ParseTSVector(ordered[j])
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := 0
+			if i < j {
+				expected = -1
+			} else if i > j {
+				expected = 1
+			}
+			if c := v1.Compare(v2); c != expected {
+				t.Errorf("expected %s compared to %s to be %d, got %d", v1, v2, expected, c)
+			}
+		}
+	}
+}
+
+func TestToTSVector(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		config   string
+		document string
+		expected string
+	}{
+		{`simple`, `The Fat Rats`, `'fat':2 'rats':3 'the':1`},
+		{`english`, `The Fat Rats`, `'fat':2 'rat':3`},
+		{`english`, `a fat  cat sat on a mat - it ate a fat rats`,
+			`'at':9 'cat':3 'fat':2,11 'mat':7 'rat':12 'sat':4`},
+		{`english`, `Connected, connecting: connections!`, `'connect':1,2,3`},
+		{`english`, `Ünïcode wörds and 42 numbers`, `'42':4 'number':5 'wörds':2 'ünïcode':1`},
+		{`english`, `the and of`, ``},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.document, func(t *testing.T) {
+			c, err := GetConfig(tc.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := c.ToTSVector(tc.document).String(); s != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, s)
+			}
+		})
+	}
+
+	if _, err := GetConfig("pg_catalog.English"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetConfig("french"); !testutils.IsError(err, `text search configuration "french" does not exist`) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}